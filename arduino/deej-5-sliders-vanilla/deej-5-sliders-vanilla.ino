@@ -12,6 +12,10 @@ int analogSliderValues[NUM_SLIDERS];
 int lastSentValues[NUM_SLIDERS];
 const int CHANGE_THRESHOLD = 10; // Only send if value changes by more than this amount
 
+// Minimum time between slider reports, set by deej's "rate:<per second>" command (0 = as often as they change)
+unsigned long reportInterval = 0;
+unsigned long lastReportTime = 0;
+
 void setup() { 
   for (int i = 0; i < NUM_SLIDERS; i++) {
     pinMode(analogInputs[i], INPUT);
@@ -20,7 +24,20 @@ void setup() {
   Serial.begin(9600);
   delay(1000);
 
-  // Send startup signal with version and capabilities
+  // Send startup signal with version and capabilities (comma-separated, e.g. "5sliders,2buttons,display")
+  // Boards with motorized faders should add "faders" and handle "deej:<version>:setfader:<slider>:<0-1023>"
+  // Boards that take settings from deej should add "config" and handle
+  // "deej:<version>:config:report_rate=50,led_brightness=128,smoothing.0=4,color.0=ff8800" (any subset of those,
+  // colors are hex RGB for a slider's LEDs)
+  // Boards with a "display" get "deej:<version>:command:display:nowplaying:<slider>:<title - artist>" whenever
+  // what's playing on a slider changes (empty when nothing is), and "deej:<version>:command:display:name:<slider>:<name>"
+  // with each slider's name (empty for sliders without one)
+  // Boards with a "display" or "leds" get "deej:<version>:command:display:idle:<dim|off|wake>" (or "led:idle:...")
+  // when nobody has touched them for a while, and again once someone does
+  // Boards that keep settings in EEPROM should add "eeprom", answer "deej:<version>:command:eeprom" with
+  // "deej:<version>:response:eeprom:name=Desk%20Mixer,sliders=5,slider_name.0=Music,led_brightness=128" and store
+  // the pairs sent with "deej:<version>:command:eeprom_write:<pairs>", answering with "eeprom_write_ack".
+  // values are percent-escaped (%, commas, colons, equals signs and anything outside printable ascii)
   Serial.print("deej:");
   Serial.print(FIRMWARE_VERSION);
   Serial.println(":startup:5sliders");
@@ -43,10 +60,11 @@ void loop() {
   
   updateSliderValues();
   
-  // Prioritize slider data - send immediately if there are changes
-  if (hasSignificantChanges()) {
+  // Prioritize slider data - send immediately if there are changes (but no more often than deej asked for)
+  if (hasSignificantChanges() && millis() - lastReportTime >= reportInterval) {
     sendSliderValues();
     updateLastSentValues();
+    lastReportTime = millis();
   }
   
   // Reduced delay for better responsiveness
@@ -136,6 +154,16 @@ void processCommand(String command) {
   else if (command == "sliders") {
     sendSliderValues();
   }
+  else if (command.startsWith("rate:")) {
+    long rate = command.substring(5).toInt();
+    reportInterval = rate > 0 ? 1000 / rate : 0;
+
+    String response = "deej:";
+    response += FIRMWARE_VERSION;
+    response += ":response:rate_ack:";
+    response += rate;
+    Serial.println(response);
+  }
   else {
     // Unknown command
     String response = "deej:";
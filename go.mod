@@ -22,8 +22,8 @@ require (
 	github.com/stretchr/testify v1.10.0 // indirect
 	github.com/thoas/go-funk v0.7.0
 	go.uber.org/zap v1.15.0
-	golang.org/x/sys v0.30.0 // indirect
-	golang.org/x/text v0.22.0 // indirect
+	golang.org/x/sys v0.30.0
+	golang.org/x/text v0.22.0
 	gopkg.in/check.v1 v1.0.0-20200227125254-8fa46927fb4f // indirect
-	gopkg.in/yaml.v2 v2.2.8 // indirect
+	gopkg.in/yaml.v2 v2.2.8
 )
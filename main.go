@@ -1,8 +1,12 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"flag"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/omriharel/deej/pkg/deej"
 )
@@ -12,17 +16,49 @@ var (
 	versionTag string
 	buildType  string
 
-	verbose bool
+	// base64 ed25519 public key that release assets are signed with, release builds only
+	updatePublicKey string
+
+	verbose     bool
+	doctor      bool
+	dumpSerial  bool
+	recordTrace string
+	replayTrace string
+
+	measureLatency bool
+	encryptSecret  bool
+
+	installService   bool
+	uninstallService bool
+	runService       bool
+	autostarted      bool
 )
 
 func init() {
 	flag.BoolVar(&verbose, "verbose", false, "show verbose logs (useful for debugging serial)")
 	flag.BoolVar(&verbose, "v", false, "shorthand for --verbose")
+	flag.BoolVar(&doctor, "doctor", false, "check serial ports, the audio backend, the config and notifications, then print a report and exit")
+	flag.BoolVar(&dumpSerial, "dump-serial", false, "log all serial traffic, along with how deej interpreted it")
+	flag.StringVar(&recordTrace, "record-trace", "", "record slider data and the resulting volume changes to this file")
+	flag.StringVar(&replayTrace, "replay-trace", "", "replay a recorded trace file instead of connecting to a device")
+	flag.BoolVar(&measureLatency, "measure-latency", false, "measure how long slider moves take to apply, logged and served on the web UI's /metrics")
+	flag.BoolVar(&encryptSecret, "encrypt-secret", false, "read a password or token from standard input and print it encrypted, for use in config.yaml")
+	flag.BoolVar(&installService, "install-service", false, "install deej as a windows service, which starts it with the machine")
+	flag.BoolVar(&uninstallService, "uninstall-service", false, "remove the windows service installed with --install-service")
+	flag.BoolVar(&runService, "service", false, "run as the windows service (the service manager passes this)")
+	flag.BoolVar(&autostarted, "autostart", false, "started by the login autostart entry (the entry passes this)")
 	flag.Parse()
 }
 
 func main() {
 
+	// services and login items don't start in deej's directory, but config and logs live next to the executable
+	if runService || autostarted {
+		if err := deej.ChdirToExecutable(); err != nil {
+			panic(fmt.Sprintf("Failed to change working directory: %v", err))
+		}
+	}
+
 	// first we need a logger
 	logger, err := deej.NewLogger()
 	if err != nil {
@@ -42,12 +78,80 @@ func main() {
 		named.Debug("Verbose flag provided, all log messages will be shown")
 	}
 
+	// these only manage the service (the deej that ends up running is a separate process) or check the setup
+	switch {
+	case installService:
+		if err := deej.InstallService(); err != nil {
+			fmt.Printf("Failed to install the deej service: %v\n", err)
+			named.Fatalw("Failed to install service", "error", err)
+		}
+
+		fmt.Println("Installed the deej service, deej will now start along with the machine")
+		named.Info("Installed service")
+		return
+
+	case uninstallService:
+		if err := deej.UninstallService(); err != nil {
+			fmt.Printf("Failed to uninstall the deej service: %v\n", err)
+			named.Fatalw("Failed to uninstall service", "error", err)
+		}
+
+		fmt.Println("Uninstalled the deej service")
+		named.Info("Uninstalled service")
+		return
+
+	case runService:
+		if err := deej.RunService(logger); err != nil {
+			named.Fatalw("Failed to run as a service", "error", err)
+		}
+
+		return
+
+	case encryptSecret:
+		// read from stdin rather than the command line, so it doesn't end up in shell history
+		fmt.Fprint(os.Stderr, "Value to encrypt: ")
+
+		value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && value == "" {
+			named.Fatalw("Failed to read value to encrypt", "error", err)
+		}
+
+		encrypted, err := deej.EncryptSecret(strings.TrimRight(value, "\r\n"))
+		if err != nil {
+			fmt.Printf("Failed to encrypt: %v\n", err)
+			named.Fatalw("Failed to encrypt secret", "error", err)
+		}
+
+		fmt.Println(encrypted)
+		return
+
+	case doctor:
+		if !deej.RunDoctor(logger, os.Stdout) {
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	// create the deej instance
 	d, err := deej.NewDeej(logger, verbose)
 	if err != nil {
 		named.Fatalw("Failed to create deej object", "error", err)
 	}
 
+	d.SetDumpSerial(dumpSerial)
+	d.SetMeasureLatency(measureLatency)
+
+	if recordTrace != "" {
+		if err := d.SetRecordTrace(recordTrace); err != nil {
+			named.Fatalw("Failed to start recording slider trace", "error", err)
+		}
+	}
+
+	if replayTrace != "" {
+		d.SetReplayTrace(replayTrace)
+	}
+
 	// if injected by build process, set version info to show up in the tray
 	if buildType != "" && (versionTag != "" || gitCommit != "") {
 		identifier := gitCommit
@@ -59,8 +163,15 @@ func main() {
 		d.SetVersion(versionString)
 	}
 
+	// only release builds know what to update to, and how to verify it
+	if buildType == "release" {
+		d.SetRelease(versionTag, updatePublicKey)
+	}
+
 	// onwards, to glory
-	if err = d.Initialize(); err != nil {
+	if err = d.Initialize(); errors.Is(err, deej.ErrAlreadyRunning) {
+		named.Info("deej is already running, exiting")
+	} else if err != nil {
 		named.Fatalw("Failed to initialize deej", "error", err)
 	}
 }
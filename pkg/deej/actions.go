@@ -0,0 +1,189 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// actions are things deej can do on request from the hardware (e.g. slider gestures), as opposed to
+// simply setting volumes. actions that act on "the slider" apply to the targets of the slider that triggered them
+const (
+
+	// toggle mute for the slider's targets
+	actionMute = "mute"
+
+	// toggle mute for a specific slider's targets, e.g. "mute:2", for buttons and hotkeys that don't belong to one
+	actionMutePrefix = "mute:"
+
+	// re-acquire all audio sessions
+	actionRefreshSessions = "refresh_sessions"
+
+	// cycle to the next profile in the config, then back to the default mapping
+	actionNextProfile = "next_profile"
+
+	// switch to a specific profile, e.g. "profile:gaming" ("profile:" alone switches back to the default mapping)
+	actionProfilePrefix = "profile:"
+
+	// duck every app except the given target's, e.g. "duck:discord.exe". buttons duck while held,
+	// gestures toggle it
+	actionDuckPrefix = "duck:"
+
+	// keep a session's volume at or below a percentage wherever its slider is, e.g. "cap:master:30"
+	// (see volume_caps.go). a cap of 100 lifts it
+	actionCapPrefix = "cap:"
+
+	// lift every cap
+	actionUncap = "uncap"
+)
+
+// passed as the slider ID for actions that weren't triggered by a slider
+const noSliderID = -1
+
+var errUnknownAction = errors.New("unknown action")
+
+// validateAction returns an error if the given action isn't one deej knows how to perform
+func validateAction(action string) error {
+	switch {
+	case action == actionMute, action == actionRefreshSessions, action == actionNextProfile, action == actionUncap:
+		return nil
+	case strings.HasPrefix(action, actionProfilePrefix):
+		return nil
+	case strings.HasPrefix(action, actionMutePrefix):
+		if sliderID, err := strconv.Atoi(strings.TrimPrefix(action, actionMutePrefix)); err == nil && sliderID >= 0 {
+			return nil
+		}
+	case strings.HasPrefix(action, actionDuckPrefix) && strings.TrimPrefix(action, actionDuckPrefix) != "":
+		return nil
+	case strings.HasPrefix(action, actionCapPrefix):
+		if _, _, err := parseCapAction(action); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%q: %w", action, errUnknownAction)
+}
+
+// performAction carries out an action on behalf of the given slider
+func (d *Deej) performAction(action string, sliderID int) error {
+	d.logger.Infow("Performing action", "action", action, "sliderID", sliderID)
+
+	switch {
+	case action == actionMute:
+		return d.toggleSliderMute(sliderID)
+
+	case strings.HasPrefix(action, actionMutePrefix):
+		targetSliderID, _ := strconv.Atoi(strings.TrimPrefix(action, actionMutePrefix))
+		return d.toggleSliderMute(targetSliderID)
+
+	case action == actionRefreshSessions:
+
+		// performance: the user asked for this explicitly, so skip the usual refresh cooldown
+		d.sessions.refreshSessions(true)
+
+	case action == actionNextProfile:
+		return d.switchProfile(d.config.NextProfile())
+
+	case strings.HasPrefix(action, actionProfilePrefix):
+		return d.switchProfile(strings.TrimPrefix(action, actionProfilePrefix))
+
+	case strings.HasPrefix(action, actionDuckPrefix):
+		if d.sessions.ducking() {
+			d.sessions.restoreDucked()
+			return nil
+		}
+
+		return d.duckOthers(strings.TrimPrefix(action, actionDuckPrefix))
+
+	case strings.HasPrefix(action, actionCapPrefix):
+		key, limit, _ := parseCapAction(action)
+		d.sessions.setVolumeCap(key, limit)
+
+		// put volumes that are over the cap now (or were held back by it) where they belong
+		d.serial.resyncSliders()
+
+	case action == actionUncap:
+		d.sessions.clearVolumeCaps()
+		d.serial.resyncSliders()
+
+	default:
+		return fmt.Errorf("perform %q: %w", action, errUnknownAction)
+	}
+
+	return nil
+}
+
+// performButtonAction carries out an action bound to a hardware button. duck actions last for as long as
+// the button is held, everything else happens once on press
+func (d *Deej) performButtonAction(action string, buttonID int, pressed bool) error {
+	d.logger.Infow("Performing button action", "action", action, "buttonID", buttonID, "pressed", pressed)
+
+	if strings.HasPrefix(action, actionDuckPrefix) {
+		if !pressed {
+			d.sessions.restoreDucked()
+			return nil
+		}
+
+		return d.duckOthers(strings.TrimPrefix(action, actionDuckPrefix))
+	}
+
+	if !pressed {
+		return nil
+	}
+
+	// buttons don't belong to a slider, so slider actions like mute have nothing to act on
+	return d.performAction(action, noSliderID)
+}
+
+// parseCapAction splits a cap action into the session key it caps and the cap, from 0 to 1. the percentage goes
+// last, since keys like "master:rear" have colons of their own
+func parseCapAction(action string) (string, float32, error) {
+	spec := strings.TrimPrefix(action, actionCapPrefix)
+
+	separator := strings.LastIndex(spec, ":")
+	if separator <= 0 {
+		return "", 0, fmt.Errorf("%q: expected cap:<target>:<percent>: %w", action, errUnknownAction)
+	}
+
+	percent, err := strconv.Atoi(spec[separator+1:])
+	if err != nil || percent < 0 || percent > 100 {
+		return "", 0, fmt.Errorf("%q: cap must be a percentage from 0 to 100: %w", action, errUnknownAction)
+	}
+
+	return strings.ToLower(spec[:separator]), float32(percent) / 100, nil
+}
+
+func (d *Deej) toggleSliderMute(sliderID int) error {
+	muted, err := d.sessions.toggleSliderMute(sliderID)
+	if err != nil {
+		return fmt.Errorf("toggle mute for slider %d: %w", sliderID, err)
+	}
+
+	d.logger.Infow("Toggled slider mute", "sliderID", sliderID, "muted", muted)
+
+	return nil
+}
+
+func (d *Deej) duckOthers(target string) error {
+	if err := d.sessions.duckOthers(target, d.config.DuckLevel); err != nil {
+		return fmt.Errorf("duck sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Deej) switchProfile(name string) error {
+	if err := d.config.SetActiveProfile(name); err != nil {
+		return fmt.Errorf("switch profile: %w", err)
+	}
+
+	displayName := name
+	if displayName == "" {
+		displayName = "default"
+	}
+
+	d.notifier.Notify(tr("notify.profile_switched.title"), tr("notify.profile_switched.message", displayName))
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package deej
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// deej "freezing" usually turns out to be the audio server not answering, with deej stuck waiting on it. to tell
+// the two apart, every call into the audio backend (pulseaudio requests on linux, WASAPI on windows) is timed:
+// slow calls are logged, calls that still haven't returned after a while are logged while they hang, and counts
+// and durations are served on /metrics
+
+// audioCallRecorder times calls into the audio backend. there's a single one, since sessions and session finders
+// are created well away from the Deej that would otherwise hold it
+type audioCallRecorder struct {
+	logger *zap.SugaredLogger
+
+	calls    map[string]*audioCallStats
+	inFlight int
+	lock     sync.Mutex
+}
+
+// audioCallStats are the running totals of one kind of call since deej started
+type audioCallStats struct {
+	count uint64
+	slow  uint64
+	sum   time.Duration
+	max   time.Duration
+}
+
+const (
+	// calls that take this long are logged once they return
+	slowAudioCallThreshold = 250 * time.Millisecond
+
+	// calls that take this long are logged while they're still waiting
+	stuckAudioCallThreshold = 2 * time.Second
+)
+
+var audioCalls = newAudioCallRecorder()
+
+func newAudioCallRecorder() *audioCallRecorder {
+	return &audioCallRecorder{
+		logger: zap.NewNop().Sugar(),
+		calls:  map[string]*audioCallStats{},
+	}
+}
+
+// setLogger has slow calls logged under the given logger, instead of nowhere
+func (r *audioCallRecorder) setLogger(logger *zap.SugaredLogger) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.logger = logger.Named("audio_calls")
+}
+
+// time starts timing a call into the audio backend, and returns the function that ends it:
+//
+//	defer audioCalls.time("GetSinkInfo")()
+func (r *audioCallRecorder) time(call string) func() {
+	started := time.Now()
+
+	r.lock.Lock()
+	r.inFlight++
+	logger := r.logger
+	r.lock.Unlock()
+
+	stuck := time.AfterFunc(stuckAudioCallThreshold, func() {
+		logger.Warnw("Audio backend call hasn't returned yet, the audio server may be hung",
+			"call", call, "waiting", stuckAudioCallThreshold.String())
+	})
+
+	return func() {
+		stuck.Stop()
+		r.record(call, time.Since(started))
+	}
+}
+
+// record adds a call that took the given time
+func (r *audioCallRecorder) record(call string, elapsed time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.inFlight--
+
+	stats, ok := r.calls[call]
+	if !ok {
+		stats = &audioCallStats{}
+		r.calls[call] = stats
+	}
+
+	stats.count++
+	stats.sum += elapsed
+
+	if elapsed > stats.max {
+		stats.max = elapsed
+	}
+
+	if elapsed >= slowAudioCallThreshold {
+		stats.slow++
+		r.logger.Warnw("Slow audio backend call", "call", call, "took", elapsed.String())
+	}
+}
+
+// writeMetrics writes every kind of call's totals in prometheus' text format
+func (r *audioCallRecorder) writeMetrics(w io.Writer) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	calls := make([]string, 0, len(r.calls))
+	for call := range r.calls {
+		calls = append(calls, call)
+	}
+
+	sort.Strings(calls)
+
+	fmt.Fprintln(w, "# HELP deej_audio_calls_in_flight Calls into the audio backend that haven't returned yet.")
+	fmt.Fprintln(w, "# TYPE deej_audio_calls_in_flight gauge")
+	fmt.Fprintf(w, "deej_audio_calls_in_flight %d\n", r.inFlight)
+
+	fmt.Fprintln(w, "# HELP deej_audio_call_seconds Time taken by calls into the audio backend.")
+	fmt.Fprintln(w, "# TYPE deej_audio_call_seconds summary")
+
+	for _, call := range calls {
+		fmt.Fprintf(w, "deej_audio_call_seconds_sum{call=%q} %g\n", call, r.calls[call].sum.Seconds())
+		fmt.Fprintf(w, "deej_audio_call_seconds_count{call=%q} %d\n", call, r.calls[call].count)
+	}
+
+	fmt.Fprintln(w, "# HELP deej_audio_call_max_seconds Longest call into the audio backend.")
+	fmt.Fprintln(w, "# TYPE deej_audio_call_max_seconds gauge")
+
+	for _, call := range calls {
+		fmt.Fprintf(w, "deej_audio_call_max_seconds{call=%q} %g\n", call, r.calls[call].max.Seconds())
+	}
+
+	fmt.Fprintf(w, "# HELP deej_audio_calls_slow_total Calls into the audio backend that took %s or more.\n",
+		slowAudioCallThreshold)
+	fmt.Fprintln(w, "# TYPE deej_audio_calls_slow_total counter")
+
+	for _, call := range calls {
+		fmt.Fprintf(w, "deej_audio_calls_slow_total{call=%q} %d\n", call, r.calls[call].slow)
+	}
+}
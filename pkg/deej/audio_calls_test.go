@@ -0,0 +1,43 @@
+package deej
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAudioCallRecorder(t *testing.T) {
+	r := newAudioCallRecorder()
+
+	r.time("GetSinkInfo")()
+
+	// pretend these were started through time, which counts them as in flight
+	r.inFlight += 3
+	r.record("SetSinkInputVolume", time.Millisecond)
+	r.record("SetSinkInputVolume", slowAudioCallThreshold)
+	r.record("SetSinkInputVolume", time.Second)
+
+	stats := r.calls["SetSinkInputVolume"]
+	if stats.count != 3 || stats.slow != 2 || stats.max != time.Second {
+		t.Errorf("expected 3 calls, 2 slow and a max of 1s, got %d, %d and %v", stats.count, stats.slow, stats.max)
+	}
+
+	r.inFlight++
+
+	var metrics bytes.Buffer
+	r.writeMetrics(&metrics)
+
+	for _, expected := range []string{
+		"deej_audio_calls_in_flight 1\n",
+		"deej_audio_call_seconds_count{call=\"GetSinkInfo\"} 1\n",
+		"deej_audio_call_seconds_count{call=\"SetSinkInputVolume\"} 3\n",
+		"deej_audio_call_max_seconds{call=\"SetSinkInputVolume\"} 1\n",
+		"deej_audio_calls_slow_total{call=\"GetSinkInfo\"} 0\n",
+		"deej_audio_calls_slow_total{call=\"SetSinkInputVolume\"} 2\n",
+	} {
+		if !strings.Contains(metrics.String(), expected) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", expected, metrics.String())
+		}
+	}
+}
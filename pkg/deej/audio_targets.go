@@ -1,19 +1,14 @@
 package deej
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
-	"time"
 
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
-
-	"github.com/godbus/dbus/v5"
 	"github.com/omriharel/deej/pkg/deej/util"
 )
 
@@ -138,14 +133,7 @@ func (d *Deej) getProcessAudioTargets() ([]AudioTarget, error) {
 		return nil, fmt.Errorf("get sessions: %w", err)
 	}
 
-	// Build MPRIS process name map and bus map
-	mprisMap := getAllMprisPlayers()
-	mprisBusMap := make(map[string]*MprisInfo)
-	for k, v := range mprisMap {
-		if strings.HasPrefix(k, "org.mpris.MediaPlayer2.") {
-			mprisBusMap[k] = v
-		}
-	}
+	mprisPlayers := getAllMprisPlayers(d.mpris.Players())
 
 	matchedBusNames := make(map[string]bool)
 
@@ -169,13 +157,11 @@ func (d *Deej) getProcessAudioTargets() ([]AudioTarget, error) {
 
 		// Try to match any process name to any MPRIS DesktopEntry
 		var mprisInfo *MprisInfo
-		var displayName string
 		for _, name := range processNames {
-			if info, ok := mprisMap[name]; ok {
+			if info, ok := mprisPlayers.byProcess[name]; ok {
 				mprisInfo = info
-				displayName = info.PlayerName
 				// Mark all bus names for this info as matched
-				for bus, i := range mprisBusMap {
+				for bus, i := range mprisPlayers.byBusName {
 					if i == info {
 						matchedBusNames[bus] = true
 					}
@@ -184,11 +170,10 @@ func (d *Deej) getProcessAudioTargets() ([]AudioTarget, error) {
 			}
 		}
 
-		if len(processNames) > 0 {
-			displayName = processNames[0]
-			displayName = strings.TrimSuffix(displayName, ".exe")
-			displayName = cases.Title(language.English).String(strings.ToLower(displayName))
-			displayName = strings.ReplaceAll(displayName, ".", " ")
+		// the session's own name is the friendliest, then its player's, and its process name only as a last resort
+		displayName := sessionDisplayName(session)
+		if dns, ok := session.(displayNamedSession); (!ok || dns.DisplayName() == "") && mprisInfo != nil && mprisInfo.PlayerName != "" {
+			displayName = mprisInfo.PlayerName
 		}
 
 		targets = append(targets, AudioTarget{
@@ -201,10 +186,17 @@ func (d *Deej) getProcessAudioTargets() ([]AudioTarget, error) {
 	}
 
 	// List unmatched MPRIS players (by bus name)
-	for bus, info := range mprisBusMap {
+	busNames := make([]string, 0, len(mprisPlayers.byBusName))
+	for bus := range mprisPlayers.byBusName {
+		busNames = append(busNames, bus)
+	}
+	sort.Strings(busNames)
+
+	for _, bus := range busNames {
 		if matchedBusNames[bus] {
 			continue
 		}
+		info := mprisPlayers.byBusName[bus]
 		targets = append(targets, AudioTarget{
 			Name:        bus,
 			DisplayName: info.PlayerName,
@@ -217,231 +209,54 @@ func (d *Deej) getProcessAudioTargets() ([]AudioTarget, error) {
 	return targets, nil
 }
 
-// getMprisInfo attempts to get MPRIS metadata for a given process name
-func getMprisInfo(processName string) *MprisInfo {
-	if !util.Linux() {
-		return nil
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
-	defer cancel()
-
-	conn, err := dbus.ConnectSessionBus()
-	if err != nil {
-		return nil
-	}
-	defer conn.Close()
+// mprisPlayerIndex looks up MPRIS players' info by the process behind them, or by their bus name. a player found
+// both ways has the same *MprisInfo in both maps
+type mprisPlayerIndex struct {
+	byProcess map[string]*MprisInfo // only players whose process could be told
+	byBusName map[string]*MprisInfo // every player
+}
 
-	var names []string
-	call := conn.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.ListNames", 0)
-	if call.Err != nil {
-		return nil
-	}
-	if err := call.Store(&names); err != nil {
-		return nil
+// getAllMprisPlayers indexes the given MPRIS players
+func getAllMprisPlayers(players []mprisPlayer) mprisPlayerIndex {
+	index := mprisPlayerIndex{
+		byProcess: make(map[string]*MprisInfo),
+		byBusName: make(map[string]*MprisInfo),
 	}
 
-	// Look for MPRIS players that might match this process
-	for _, name := range names {
-		if !strings.HasPrefix(name, "org.mpris.MediaPlayer2.") {
-			continue
-		}
-
-		// Try to match the player name with the process name
-		playerName := strings.TrimPrefix(name, "org.mpris.MediaPlayer2.")
-		if !strings.Contains(strings.ToLower(playerName), strings.ToLower(processName)) &&
-			!strings.Contains(strings.ToLower(processName), strings.ToLower(playerName)) {
-			continue
-		}
-
-		// Get player identity
-		obj := conn.Object(name, "/org/mpris/MediaPlayer2")
-		var identity dbus.Variant
-		err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.mpris.MediaPlayer2", "Identity").Store(&identity)
-		if err != nil {
-			continue
-		}
-
-		playerIdentity := playerName
-		if s, ok := identity.Value().(string); ok && s != "" {
-			playerIdentity = s
-		}
-
-		// Get playback status
-		var playbackStatus dbus.Variant
-		err = obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.mpris.MediaPlayer2.Player", "PlaybackStatus").Store(&playbackStatus)
-		if err != nil {
-			continue
-		}
-
-		status, ok := playbackStatus.Value().(string)
-		if !ok {
-			continue
-		}
-
-		isPlaying := status == "Playing"
-
-		// Get metadata
-		var metadata dbus.Variant
-		err = obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.mpris.MediaPlayer2.Player", "Metadata").Store(&metadata)
-		if err != nil {
-			continue
-		}
-
-		metadataMap, ok := metadata.Value().(map[string]dbus.Variant)
-		if !ok {
-			continue
-		}
-
-		// Extract title, artist, album
-		title := ""
-		artist := ""
-		album := ""
-
-		if titleVar, exists := metadataMap["xesam:title"]; exists {
-			if t, ok := titleVar.Value().(string); ok {
-				title = t
-			}
-		}
-
-		if artistVar, exists := metadataMap["xesam:artist"]; exists {
-			if artists, ok := artistVar.Value().([]string); ok && len(artists) > 0 {
-				artist = artists[0]
-			}
-		}
-
-		if albumVar, exists := metadataMap["xesam:album"]; exists {
-			if a, ok := albumVar.Value().(string); ok {
-				album = a
-			}
-		}
-
-		return &MprisInfo{
-			IsPlaying:  isPlaying,
-			Title:      title,
-			Artist:     artist,
-			Album:      album,
-			PlayerName: playerIdentity,
+	for _, player := range players {
+		info := player.info
+		if player.processName != "" {
+			index.byProcess[player.processName] = &info
 		}
+		index.byBusName[player.busName] = &info
 	}
 
-	return nil
+	return index
 }
 
-// getAllMprisPlayers returns a map of processName to MprisInfo for all active MPRIS players
-func getAllMprisPlayers() map[string]*MprisInfo {
-	mprisMap := make(map[string]*MprisInfo)
-	mprisBusMap := make(map[string]*MprisInfo) // bus name -> MprisInfo
-	if !util.Linux() {
-		return mprisMap
-	}
+// nowPlaying returns what's playing on the players behind the given mapping targets, preferring one that's actually
+// playing. players is what getAllMprisPlayers returned
+func nowPlaying(players mprisPlayerIndex, targets []string) *MprisInfo {
+	var paused *MprisInfo
 
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-	defer cancel()
+	for _, target := range targets {
+		name, _, _ := splitTargetTrim(strings.ToLower(target))
 
-	conn, err := dbus.ConnectSessionBus()
-	if err != nil {
-		return mprisMap
-	}
-	defer conn.Close()
-
-	var names []string
-	call := conn.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.ListNames", 0)
-	if call.Err != nil {
-		return mprisMap
-	}
-	if err := call.Store(&names); err != nil {
-		return mprisMap
-	}
-
-	for _, name := range names {
-		if !strings.HasPrefix(name, "org.mpris.MediaPlayer2.") {
+		info := players.byProcess[strings.TrimSuffix(name, ".exe")]
+		if info == nil {
 			continue
 		}
-		obj := conn.Object(name, "/org/mpris/MediaPlayer2")
-
-		var processName string
-		// Try DesktopEntry, but don't require it
-		var desktopEntry dbus.Variant
-		err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.mpris.MediaPlayer2", "DesktopEntry").Store(&desktopEntry)
-		if err == nil {
-			if pn, ok := desktopEntry.Value().(string); ok && pn != "" {
-				processName = strings.ToLower(pn)
-			}
-		}
-
-		// Try to get process name from PID if DesktopEntry is not available
-		if processName == "" {
-			var uniqueName string
-			call := conn.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.GetNameOwner", 0, name)
-			if call.Err == nil {
-				if err := call.Store(&uniqueName); err == nil && uniqueName != "" {
-					var pid uint32
-					pidCall := conn.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.GetConnectionUnixProcessID", 0, uniqueName)
-					if pidCall.Err == nil {
-						if err := pidCall.Store(&pid); err == nil && pid > 0 {
-							procName := getProcessNameFromPID(pid)
-							if procName != "" && procName != "xdg-dbus-proxy" && procName != "bwrap" {
-								processName = strings.ToLower(procName)
-							}
-						}
-					}
-				}
-			}
-		}
 
-		// Get identity
-		var identity dbus.Variant
-		_ = obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.mpris.MediaPlayer2", "Identity").Store(&identity)
-		playerIdentity := strings.TrimPrefix(name, "org.mpris.MediaPlayer2.")
-		if s, ok := identity.Value().(string); ok && s != "" {
-			playerIdentity = s
+		if info.IsPlaying {
+			return info
 		}
 
-		// Get playback status
-		var playbackStatus dbus.Variant
-		_ = obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.mpris.MediaPlayer2.Player", "PlaybackStatus").Store(&playbackStatus)
-		status, _ := playbackStatus.Value().(string)
-		isPlaying := status == "Playing"
-
-		// Get metadata
-		var metadata dbus.Variant
-		_ = obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.mpris.MediaPlayer2.Player", "Metadata").Store(&metadata)
-		metadataMap, _ := metadata.Value().(map[string]dbus.Variant)
-		title := ""
-		artist := ""
-		album := ""
-		if titleVar, exists := metadataMap["xesam:title"]; exists {
-			if t, ok := titleVar.Value().(string); ok {
-				title = t
-			}
-		}
-		if artistVar, exists := metadataMap["xesam:artist"]; exists {
-			if artists, ok := artistVar.Value().([]string); ok && len(artists) > 0 {
-				artist = artists[0]
-			}
-		}
-		if albumVar, exists := metadataMap["xesam:album"]; exists {
-			if a, ok := albumVar.Value().(string); ok {
-				album = a
-			}
+		if paused == nil {
+			paused = info
 		}
-
-		info := &MprisInfo{
-			IsPlaying:  isPlaying,
-			Title:      title,
-			Artist:     artist,
-			Album:      album,
-			PlayerName: playerIdentity,
-		}
-		if processName != "" {
-			mprisMap[processName] = info
-		}
-		mprisBusMap[name] = info
 	}
-	// Attach bus name map for unmatched listing
-	mprisMap["__bus_map__"] = (*MprisInfo)(nil) // marker for getProcessAudioTargets
-	return mprisMap
+
+	return paused
 }
 
 // getDeviceAudioTargets returns audio targets for audio devices (Windows only)
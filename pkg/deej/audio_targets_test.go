@@ -0,0 +1,142 @@
+package deej
+
+import (
+	"testing"
+)
+
+// fakeMPRISWatcher always has the same players
+type fakeMPRISWatcher struct {
+	players []mprisPlayer
+}
+
+func (fmw *fakeMPRISWatcher) Players() []mprisPlayer {
+	return fmw.players
+}
+
+func (fmw *fakeMPRISWatcher) Changes() <-chan struct{} {
+	return nil
+}
+
+func (fmw *fakeMPRISWatcher) Close() error {
+	return nil
+}
+
+var testMPRISPlayers = []mprisPlayer{
+	{
+		busName:     "org.mpris.MediaPlayer2.spotify",
+		processName: "spotify",
+		info:        MprisInfo{IsPlaying: true, Title: "Song", Artist: "Band", PlayerName: "Spotify"},
+	},
+	{
+		busName:     "org.mpris.MediaPlayer2.vlc",
+		processName: "vlc",
+		info:        MprisInfo{Title: "Movie", PlayerName: "VLC media player"},
+	},
+	{
+		// a sandboxed player, whose process couldn't be told
+		busName: "org.mpris.MediaPlayer2.chromium.instance42",
+		info:    MprisInfo{IsPlaying: true, Title: "Video", PlayerName: "Chromium"},
+	},
+}
+
+func TestGetAllMprisPlayers(t *testing.T) {
+	index := getAllMprisPlayers(testMPRISPlayers)
+
+	if len(index.byBusName) != len(testMPRISPlayers) {
+		t.Errorf("expected every player by bus name, got %v", index.byBusName)
+	}
+
+	if len(index.byProcess) != 2 {
+		t.Errorf("expected only players with a process by process name, got %v", index.byProcess)
+	}
+
+	spotify, ok := index.byProcess["spotify"]
+	if !ok || spotify.Title != "Song" {
+		t.Fatalf("expected spotify by process name, got %v", spotify)
+	}
+
+	if index.byBusName["org.mpris.MediaPlayer2.spotify"] != spotify {
+		t.Errorf("expected the same info by process and by bus name")
+	}
+
+	if _, ok := index.byProcess[""]; ok {
+		t.Errorf("expected no player under an empty process name")
+	}
+}
+
+func TestGetProcessAudioTargetsMatchesPlayers(t *testing.T) {
+	td := newTestDeej(t, "", "master", "spotify", "firefox")
+	td.mpris = &fakeMPRISWatcher{players: testMPRISPlayers}
+
+	targets, err := td.getProcessAudioTargets()
+	if err != nil {
+		t.Fatalf("get process audio targets: %v", err)
+	}
+
+	processes := map[string]AudioTarget{}
+	unmatched := []string{}
+
+	for _, target := range targets {
+		switch target.Type {
+		case "process":
+			processes[target.Name] = target
+		case "mpris-unmatched":
+			unmatched = append(unmatched, target.Name)
+		default:
+			t.Errorf("unexpected target type %q for %q", target.Type, target.Name)
+		}
+	}
+
+	if _, ok := processes["master"]; ok {
+		t.Errorf("expected master to be left out of process targets")
+	}
+
+	if info := processes["spotify"].MprisInfo; info == nil || info.Title != "Song" {
+		t.Errorf("expected spotify to carry its player's info, got %v", info)
+	}
+
+	if info := processes["firefox"].MprisInfo; info != nil {
+		t.Errorf("expected firefox to have no player, got %v", info)
+	}
+
+	expectedUnmatched := []string{"org.mpris.MediaPlayer2.chromium.instance42", "org.mpris.MediaPlayer2.vlc"}
+	if len(unmatched) != len(expectedUnmatched) {
+		t.Fatalf("expected unmatched players %v, got %v", expectedUnmatched, unmatched)
+	}
+
+	for idx, name := range expectedUnmatched {
+		if unmatched[idx] != name {
+			t.Errorf("expected unmatched players %v, got %v", expectedUnmatched, unmatched)
+			break
+		}
+	}
+}
+
+func TestNowPlaying(t *testing.T) {
+	index := getAllMprisPlayers(testMPRISPlayers)
+
+	tests := []struct {
+		name     string
+		targets  []string
+		expected string
+	}{
+		{"nothing mapped", nil, ""},
+		{"no player", []string{"firefox.exe"}, ""},
+		{"exe and trim", []string{"Spotify.exe (-10%)"}, "Song"},
+		{"paused player", []string{"vlc"}, "Movie"},
+		{"playing preferred", []string{"vlc", "spotify"}, "Song"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			title := ""
+			if info := nowPlaying(index, test.targets); info != nil {
+				title = info.Title
+			}
+
+			if title != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, title)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package deej
+
+import "fmt"
+
+const (
+
+	// what the autostart entry is called, on every platform
+	autostartEntryName = "deej"
+
+	// what the autostart entry starts deej with, see main.go. login items don't start in deej's directory,
+	// which is where it keeps its config and logs
+	autostartArgument = "--autostart"
+)
+
+// setAutostart adds or removes deej's autostart entry, and keeps the tray's checkbox in sync. it's called from the
+// tray and from web UI requests, one at a time
+func (d *Deej) setAutostart(enabled bool) error {
+	d.autostartLock.Lock()
+	defer d.autostartLock.Unlock()
+
+	logger := d.logger.Named("autostart")
+
+	var err error
+	if enabled {
+		err = enableAutostart()
+	} else {
+		err = disableAutostart()
+	}
+
+	if err != nil {
+		logger.Warnw("Failed to change autostart entry", "enabled", enabled, "error", err)
+		return fmt.Errorf("change autostart entry: %w", err)
+	}
+
+	logger.Infow("Changed autostart entry", "enabled", enabled)
+
+	if d.autostartMenuItem != nil {
+		if enabled {
+			d.autostartMenuItem.Check()
+		} else {
+			d.autostartMenuItem.Uncheck()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package deej
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/omriharel/deej/pkg/deej/util"
+)
+
+// see the XDG autostart spec, which every desktop worth mentioning follows
+const autostartDesktopEntry = `[Desktop Entry]
+Type=Application
+Name=deej
+Comment=Control your audio sessions with physical sliders
+Exec="%s" %s
+Terminal=false
+X-GNOME-Autostart-enabled=true
+`
+
+func autostartEntryPath() (string, error) {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("get home directory: %w", err)
+		}
+
+		configDir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configDir, "autostart", autostartEntryName+".desktop"), nil
+}
+
+func autostartEnabled() (bool, error) {
+	entryPath, err := autostartEntryPath()
+	if err != nil {
+		return false, err
+	}
+
+	return util.FileExists(entryPath), nil
+}
+
+func enableAutostart() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("get executable path: %w", err)
+	}
+
+	entryPath, err := autostartEntryPath()
+	if err != nil {
+		return err
+	}
+
+	if err := util.EnsureDirExists(filepath.Dir(entryPath)); err != nil {
+		return fmt.Errorf("ensure autostart directory exists: %w", err)
+	}
+
+	entry := fmt.Sprintf(autostartDesktopEntry, executable, autostartArgument)
+
+	if err := ioutil.WriteFile(entryPath, []byte(entry), 0644); err != nil {
+		return fmt.Errorf("write autostart entry: %w", err)
+	}
+
+	return nil
+}
+
+func disableAutostart() error {
+	entryPath, err := autostartEntryPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(entryPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove autostart entry: %w", err)
+	}
+
+	return nil
+}
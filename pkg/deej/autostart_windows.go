@@ -0,0 +1,60 @@
+package deej
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// the current user's Run key starts everything in it at login, no shortcut needed
+const autostartRegistryPath = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+func autostartEnabled() (bool, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, autostartRegistryPath, registry.QUERY_VALUE)
+	if err != nil {
+		return false, fmt.Errorf("open Run key: %w", err)
+	}
+	defer key.Close()
+
+	if _, _, err := key.GetStringValue(autostartEntryName); err == registry.ErrNotExist {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("read Run key value: %w", err)
+	}
+
+	return true, nil
+}
+
+func enableAutostart() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("get executable path: %w", err)
+	}
+
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, autostartRegistryPath, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("open Run key: %w", err)
+	}
+	defer key.Close()
+
+	if err := key.SetStringValue(autostartEntryName, fmt.Sprintf(`"%s" %s`, executable, autostartArgument)); err != nil {
+		return fmt.Errorf("write Run key value: %w", err)
+	}
+
+	return nil
+}
+
+func disableAutostart() error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, autostartRegistryPath, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("open Run key: %w", err)
+	}
+	defer key.Close()
+
+	if err := key.DeleteValue(autostartEntryName); err != nil && err != registry.ErrNotExist {
+		return fmt.Errorf("delete Run key value: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package deej
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// DeviceCapabilities describes the hardware features advertised by the firmware in its startup
+// message, e.g. "deej:v2.0:startup:5sliders,2buttons,display"
+type DeviceCapabilities struct {
+	Sliders  int  `json:"sliders"`
+	Buttons  int  `json:"buttons"`
+	Encoders int  `json:"encoders"`
+	LEDs     int  `json:"leds"`
+	Display  bool `json:"display"`
+
+	// anything we don't recognize is kept as-is, so newer firmware features can still be checked for
+	Other []string `json:"other,omitempty"`
+}
+
+const (
+	capabilitySliders  = "sliders"
+	capabilityButtons  = "buttons"
+	capabilityEncoders = "encoders"
+	capabilityLEDs     = "leds"
+	capabilityDisplay  = "display"
+
+	// the device checksums its frames and expects checksummed commands
+	capabilityCRC = "crc"
+
+	// the device's sliders are motorized, and can be moved to match volume changes made elsewhere
+	capabilityFaders = "faders"
+
+	// the device takes hardware settings from deej, see HardwareSettings
+	capabilityConfig = "config"
+)
+
+// commands that only make sense on hardware advertising the matching capability
+var commandCapabilities = map[string]string{
+	"display":      capabilityDisplay,
+	"led":          capabilityLEDs,
+	"eeprom":       capabilityEEPROM,
+	"eeprom_write": capabilityEEPROM,
+}
+
+// message types other than commands that need a capability of their own
+var messageCapabilities = map[string]string{
+	messageTypeSetFader: capabilityFaders,
+	messageTypeConfig:   capabilityConfig,
+}
+
+var errCapabilityUnsupported = errors.New("connected device doesn't support this feature")
+
+// parseCapabilities turns the startup message's capability field into a DeviceCapabilities struct.
+// capabilities are separated by commas, and can be prefixed with a count ("5sliders", "leds")
+func parseCapabilities(raw string) *DeviceCapabilities {
+	caps := &DeviceCapabilities{}
+
+	for _, token := range strings.Split(raw, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if token == "" {
+			continue
+		}
+
+		// split off the leading count, if there is one
+		digits := 0
+		for digits < len(token) && token[digits] >= '0' && token[digits] <= '9' {
+			digits++
+		}
+
+		count := 1
+		if digits > 0 {
+			count, _ = strconv.Atoi(token[:digits])
+		}
+
+		switch name := token[digits:]; name {
+		case capabilitySliders:
+			caps.Sliders = count
+		case capabilityButtons:
+			caps.Buttons = count
+		case capabilityEncoders:
+			caps.Encoders = count
+		case capabilityLEDs:
+			caps.LEDs = count
+		case capabilityDisplay:
+			caps.Display = count > 0
+		default:
+			caps.Other = append(caps.Other, token)
+		}
+	}
+
+	return caps
+}
+
+// Supports returns true if the device advertised the given capability
+func (caps *DeviceCapabilities) Supports(capability string) bool {
+	if caps == nil {
+		return false
+	}
+
+	switch capability {
+	case capabilitySliders:
+		return caps.Sliders > 0
+	case capabilityButtons:
+		return caps.Buttons > 0
+	case capabilityEncoders:
+		return caps.Encoders > 0
+	case capabilityLEDs:
+		return caps.LEDs > 0
+	case capabilityDisplay:
+		return caps.Display
+	}
+
+	for _, other := range caps.Other {
+		if other == capability {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,216 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// a composite slider is one slider made out of several physical ones, e.g. the first slider on a desk unit and a
+// knob mounted on the monitor both controlling master. it's written in composite_sliders as the slider's index and
+// which sliders feed it: "latest(0, 5)" follows whichever of them moved last, "max(0, 5)" follows the highest one.
+// the composite slider's own slider_mapping decides what it controls, the mappings of its inputs are ignored.
+// composites are put together before the session map sees any moves, everything else sees the physical sliders
+const (
+	compositePolicyLatest = "latest"
+	compositePolicyMax    = "max"
+)
+
+var compositeSliderPattern = regexp.MustCompile(`(?i)^(latest|max)\(([^()]*)\)$`)
+
+var errInvalidCompositeSlider = errors.New("invalid composite slider")
+
+type compositeSlider struct {
+	policy string
+	inputs []int
+}
+
+// parseCompositeSlider parses a composite slider as written in the config
+func parseCompositeSlider(spec string) (compositeSlider, error) {
+	match := compositeSliderPattern.FindStringSubmatch(strings.TrimSpace(spec))
+	if match == nil {
+		return compositeSlider{}, fmt.Errorf("%q: expected latest(...) or max(...): %w", spec, errInvalidCompositeSlider)
+	}
+
+	composite := compositeSlider{policy: strings.ToLower(match[1])}
+	seen := map[int]bool{}
+
+	for _, inputString := range strings.Split(match[2], ",") {
+		input, err := strconv.Atoi(strings.TrimSpace(inputString))
+		if err != nil || input < 0 {
+			return compositeSlider{}, fmt.Errorf("%q: %q isn't a slider index: %w", spec, inputString, errInvalidCompositeSlider)
+		}
+
+		if !seen[input] {
+			seen[input] = true
+			composite.inputs = append(composite.inputs, input)
+		}
+	}
+
+	if len(composite.inputs) < 2 {
+		return compositeSlider{}, fmt.Errorf("%q: needs at least two sliders: %w", spec, errInvalidCompositeSlider)
+	}
+
+	return composite, nil
+}
+
+// populateCompositeSliders reads the composite sliders from the config. a slider can only feed one of them
+func (cc *CanonicalConfig) populateCompositeSliders() {
+	cc.CompositeSliders = map[int]compositeSlider{}
+	cc.compositeInputs = map[int]int{}
+
+	for sliderIdxString, spec := range cc.userConfig.GetStringMapString(configKeyCompositeSliders) {
+		sliderIdx, err := strconv.Atoi(sliderIdxString)
+		if err != nil || sliderIdx < 0 {
+			cc.logger.Warnw("Invalid slider index in composite sliders, ignoring", "key", configKeyCompositeSliders, "slider", sliderIdxString)
+			continue
+		}
+
+		composite, err := parseCompositeSlider(spec)
+		if err != nil {
+			cc.logger.Warnw("Invalid composite slider in config, ignoring", "slider", sliderIdx, "error", err)
+			continue
+		}
+
+		cc.CompositeSliders[sliderIdx] = composite
+	}
+
+	// go through them in order, so the same config always keeps the same composite when two want the same slider
+	sliderIdxs := []int{}
+	for sliderIdx := range cc.CompositeSliders {
+		sliderIdxs = append(sliderIdxs, sliderIdx)
+	}
+
+	sort.Ints(sliderIdxs)
+
+	for _, sliderIdx := range sliderIdxs {
+		composite := cc.CompositeSliders[sliderIdx]
+
+		if input, other, taken := cc.takenCompositeInput(composite); taken {
+			cc.logger.Warnw("Slider already feeds another composite slider, ignoring", "slider", sliderIdx, "input", input,
+				"other", other)
+			delete(cc.CompositeSliders, sliderIdx)
+			continue
+		}
+
+		for _, input := range composite.inputs {
+			cc.compositeInputs[input] = sliderIdx
+		}
+	}
+}
+
+// takenCompositeInput returns an input of the given composite that already feeds another one, and which one
+func (cc *CanonicalConfig) takenCompositeInput(composite compositeSlider) (int, int, bool) {
+	for _, input := range composite.inputs {
+		if other, taken := cc.compositeInputs[input]; taken {
+			return input, other, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// physicalSliders returns the physical sliders behind a slider: a composite slider's inputs, or just the slider
+func (cc *CanonicalConfig) physicalSliders(sliderID int) []int {
+	if composite, ok := cc.CompositeSliders[sliderID]; ok {
+		return composite.inputs
+	}
+
+	return []int{sliderID}
+}
+
+// sliderAggregator turns the moves of composite sliders' inputs into moves of the composite sliders, for the
+// session map. other sliders' moves pass through as they are
+type sliderAggregator struct {
+	deej *Deej
+
+	// physical slider index -> where it last was
+	inputValues map[int]float32
+
+	// composite slider index -> where it is
+	values map[int]float32
+
+	lock sync.Mutex
+}
+
+func newSliderAggregator(deej *Deej) *sliderAggregator {
+	return &sliderAggregator{
+		deej:        deej,
+		inputValues: map[int]float32{},
+		values:      map[int]float32{},
+	}
+}
+
+// aggregate returns the move the session map should see for a physical slider's move, or false if it shouldn't
+// see one
+func (a *sliderAggregator) aggregate(event SliderMoveEvent) (SliderMoveEvent, bool) {
+	config := a.deej.config
+
+	sliderIdx, ok := config.compositeInputs[event.SliderID]
+	if !ok {
+
+		// a physical slider with a composite's index that doesn't feed it would fight it
+		if _, composite := config.CompositeSliders[event.SliderID]; composite {
+			return event, false
+		}
+
+		return event, true
+	}
+
+	composite := config.CompositeSliders[sliderIdx]
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	previous, known := a.inputValues[event.SliderID]
+	a.inputValues[event.SliderID] = event.PercentValue
+
+	value := event.PercentValue
+
+	switch composite.policy {
+	case compositePolicyLatest:
+
+		// the same value again (i.e. the sliders being re-synced) isn't a move, the input that actually moved last
+		// still decides
+		if current, ok := a.values[sliderIdx]; ok && known && previous == event.PercentValue {
+			value = current
+		}
+
+	case compositePolicyMax:
+		for _, input := range composite.inputs {
+			if inputValue, ok := a.inputValues[input]; ok && inputValue > value {
+				value = inputValue
+			}
+		}
+	}
+
+	a.values[sliderIdx] = value
+
+	event.SliderID = sliderIdx
+	event.PercentValue = value
+
+	return event, true
+}
+
+// sliderValue returns where a slider is the way the session map sees it, for showing it to the user
+func (d *Deej) sliderValue(sliderID int) (float32, bool) {
+	return d.sessions.sliders.value(sliderID)
+}
+
+// value returns where a slider is as far as the session map is concerned, composite or not
+func (a *sliderAggregator) value(sliderID int) (float32, bool) {
+	if _, composite := a.deej.config.CompositeSliders[sliderID]; !composite {
+		return a.deej.serial.SliderValue(sliderID)
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	value, ok := a.values[sliderID]
+
+	return value, ok
+}
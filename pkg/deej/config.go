@@ -1,13 +1,21 @@
 package deej
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
+	"github.com/thoas/go-funk"
 	"go.uber.org/zap"
 
 	"github.com/omriharel/deej/pkg/deej/util"
@@ -18,49 +26,255 @@ import (
 type CanonicalConfig struct {
 	SliderMapping *sliderMap
 
-	ConnectionInfo struct {
-		COMPort  string
-		BaudRate int
-	}
+	// the slider mapping from the config files alone, without any runtime overrides
+	configuredSliderMapping *sliderMap
+
+	// transient slider mapping changes made at runtime, never written to disk
+	mappingOverrides map[int][]string
+	overridesLock    sync.Mutex
+
+	ConnectionInfo ConnectionInfo
+	ProbeSafety    ProbeSafety
+
+	// which session finder backend to use, or audioBackendAuto for the platform's default. see session_finder.go
+	AudioBackend string
+
+	// wait quietly for a board that's only plugged in now and then, see Deej.waitingPassively
+	PassiveMode bool
 
 	InvertSliders bool
 
 	NoiseReductionLevel string
 
+	PermissionDialog string
+
+	ExternalVolumeChange string
+
+	VolumeChangeMute string
+
+	// how long a big jump in volume takes to fade in, 0 for no fading. see volume_ramp.go
+	VolumeRamp time.Duration
+
+	// linux only - whether the volumes deej sets are also remembered by the audio server, see persist_volumes.go
+	PersistVolumes bool
+
+	// whether deej looks for new audio sessions by itself (sessionRefreshAuto), and if so, at most how often.
+	// see sessionMap.refreshSessions
+	SessionRefresh         string
+	SessionRefreshInterval time.Duration
+
+	// how deej.current picks the current window, see util.FocusModeForeground and util.FocusModeCursor
+	FocusMode string
+
+	// gesture name -> action name, see gestures.go
+	Gestures map[string]string
+
+	// slider index -> the targets it crossfades between, see slider_modes.go
+	Crossfades map[int]crossfade
+
+	// slider index -> the physical sliders it's made of, see composite_sliders.go
+	CompositeSliders map[int]compositeSlider
+	compositeInputs  map[int]int // physical slider index -> the composite slider it feeds
+
+	// button index -> action name, for boards with buttons
+	Buttons map[int]string
+
+	// normalized hotkey (i.e. "alt+ctrl+m") -> action name, see hotkeys.go
+	Hotkeys map[string]string
+
+	// what to do while the screen is locked or the system sleeps, see system_events.go
+	LockPolicies []string
+
+	// actions to perform at set times, ordered by time of day. see schedule.go
+	Schedule []scheduleEntry
+
+	// what ducked apps' volume is multiplied by, see ducking.go
+	DuckLevel float32
+
+	// lowercase target name -> volume trim, see trim.go. inline trims in the slider mapping take precedence
+	TargetTrim map[string]volumeTrim
+
+	// lowercase target name -> the most its volume can be, from 0 to 1. see volume_caps.go
+	VolumeLimits map[string]float32
+
+	// lowercase target name -> how many dB it's turned down by to even out its loudness, see loudness.go
+	LoudnessOffsets map[string]float32
+
+	// rules that put sessions in roles by name, for role targets. see roles.go
+	SessionRoles sessionRoleRules
+
+	// slider index -> the volume the top of the slider stands for, only for sliders allowed to boost past 100%
+	SliderMaxVolume map[int]float32
+
+	// slider index -> the positions it snaps to, only for sliders that have detents. see detents.go
+	SliderDetents map[int]sliderDetents
+
+	// whether to look for new releases every now and then, see updates.go
+	CheckForUpdates bool
+
+	// how many sliders the config window shows before the board was ever connected, see slider_count.go
+	DefaultSliderCount int
+
+	// slider index -> what the user calls it, only for named sliders. see slider_names.go
+	SliderNames map[int]string
+
+	// how long the board goes untouched before its display and LEDs are dimmed and turned off, 0 for never.
+	// see idle.go
+	IdleDimAfter time.Duration
+	IdleOffAfter time.Duration
+
+	// how the web UI looks, one of webThemeAuto, webThemeLight and webThemeDark
+	WebTheme string
+
+	// the address and port the web UI is served on. the port is only used if it's free, see WebConfigServer.listen
+	WebHost string
+	WebPort int
+
+	// what other devices on the network need to present to use the web UI, see web_auth.go. the web UI is only
+	// served to the network with one
+	WebToken string
+
+	// the language of the tray menu, notifications and web UI, or localeAuto to follow the system. see i18n.go
+	Locale string
+
+	// settings pushed to the board's firmware, see hardware.go
+	Hardware HardwareSettings
+
+	// names of the profiles defined in the config, and the one whose slider mapping is in use ("" for the default one)
+	Profiles      []string
+	ActiveProfile string
+
 	logger             *zap.SugaredLogger
 	notifier           Notifier
 	stopWatcherChannel chan bool
 
+	// the last reload failure the user was notified about
+	lastReloadError string
+
+	// each receives whether a reload changed anything that decides what volume a slider sets (see
+	// volumeConfigKeys). when it didn't, reload consumers leave volumes alone
 	reloadConsumers []chan bool
 
 	userConfig     *viper.Viper
 	internalConfig *viper.Viper
+
+	// held while a (re)load populates the fields, or something else (a profile switch, a web UI request) changes
+	// them. only the accessors that take it (ScheduleEntries, webTheme, webToken, NextProfile) are safe to use while
+	// that happens. most fields are still read directly, and may be seen halfway through a reload
+	lock sync.RWMutex
+
+	// held while the user config file is read back and written, so two writes don't drop each other's values
+	writeLock sync.Mutex
+
+	// deej writes to the internal config while running, i.e. to remember the slider count
+	internalLock sync.Mutex
 }
 
-const (
-	userConfigFilepath     = "config.yaml"
-	internalConfigFilepath = "preferences.yaml"
+// ConnectionInfo describes how to reach the board
+type ConnectionInfo struct {
+	Type string // "serial" or "hid"
 
-	userConfigName     = "config"
-	internalConfigName = "preferences"
+	COMPort  string
+	BaudRate int
 
-	userConfigPath = "."
+	// only used for HID boards
+	HIDVendorID  uint16
+	HIDProductID uint16
+}
+
+// ProbeSafety describes which serial ports auto-detection leaves alone, see serial_probe_safety.go
+type ProbeSafety struct {
+	SkipPorts []string
+
+	// only probe ports whose USB metadata says they belong to a known board
+	SafeProbing bool
+}
+
+const (
+	// the user config's location is resolved at startup (see config_paths.go), the internal one stays with the logs
+	internalConfigFilepath = "preferences.yaml"
+	internalConfigName     = "preferences"
 
 	configType = "yaml"
 
 	configKeySliderMapping       = "slider_mapping"
 	configKeyInvertSliders       = "invert_sliders"
+	configKeyPassiveMode         = "passive_mode"
+	configKeyConnectionType      = "connection_type"
 	configKeyCOMPort             = "com_port"
+	configKeyHIDVendorID         = "hid_vendor_id"
+	configKeyHIDProductID        = "hid_product_id"
+	configKeySkipPorts           = "skip_ports"
+	configKeySafeProbing         = "safe_probing"
 	configKeyBaudRate            = "baud_rate"
 	configKeyNoiseReductionLevel = "noise_reduction"
+	configKeyPermissionDialog    = "permission_dialog"
+	configKeyExternalVolume      = "external_volume_change"
+	configKeyVolumeChangeMute    = "volume_change_mute"
+	configKeyVolumeRamp          = "volume_ramp"
+	configKeyPersistVolumes      = "persist_volumes"
+	configKeyFocusMode           = "current_window_focus"
+	configKeyGestures            = "gestures"
+	configKeyProfiles            = "profiles"
+	configKeyTargetTrim          = "target_trim"
+	configKeyVolumeLimits        = "volume_limits"
+	configKeyLoudnessOffsets     = "loudness_offsets"
+	configKeySliderOptions       = "slider_options"
+	configKeyAllowBoost          = "allow_boost"
+	configKeyMaxBoost            = "max_boost"
+	configKeyDetents             = "detents"
+	configKeySnapRange           = "snap_range"
+	configKeyHardware            = "hardware"
+	configKeySessionRoles        = "session_roles"
+	configKeyButtons             = "buttons"
+	configKeyDuckLevel           = "duck_level"
+	configKeySliderModes         = "slider_modes"
+	configKeyCompositeSliders    = "composite_sliders"
+	configKeyHotkeys             = "hotkeys"
+	configKeyOnLock              = "on_lock"
+	configKeySchedule            = "schedule"
+	configKeyCheckForUpdates     = "check_for_updates"
+	configKeyDefaultSliderCount  = "default_slider_count"
+	configKeySliderNames         = "slider_names"
+	configKeyLocale              = "locale"
+	configKeyWebTheme            = "web_theme"
+	configKeyWebHost             = "web_host"
+	configKeyWebPort             = "web_port"
+	configKeyWebToken            = "web_token"
+	configKeyAudioBackend        = "audio_backend"
+	configKeySessionRefresh      = "session_refresh"
+	configKeyRefreshFrequency    = "process_refresh_frequency"
 
 	defaultCOMPort  = "COM4"
+	comPortAuto     = "auto"
 	defaultBaudRate = 9600
+
+	// in seconds
+	defaultRefreshFrequency = 5
+
+	// max_boost values, in percent
+	defaultMaxBoost = 150
+	maxMaxBoost     = 300
+
+	// how loud ducked apps stay, in percent of their volume
+	defaultDuckLevel = 20
+
+	// volume_ramp, in seconds
+	defaultVolumeRamp = 0
+	maxVolumeRamp     = 5
+
+	// how long the config file has to stay untouched before we reload it
+	configReloadDebounce = 300 * time.Millisecond
 )
 
 // has to be defined as a non-constant because we're using path.Join
 var internalConfigPath = path.Join(".", logDirectory)
 
+var (
+	errConfigNotFound = errors.New("config file not found")
+	errInvalidConfig  = errors.New("invalid config")
+)
+
 // NewConfig creates a config instance for the deej object and sets up viper instances for deej's config files
 func NewConfig(logger *zap.SugaredLogger, notifier Notifier) (*CanonicalConfig, error) {
 	logger = logger.Named("config")
@@ -70,18 +284,18 @@ func NewConfig(logger *zap.SugaredLogger, notifier Notifier) (*CanonicalConfig,
 		notifier:           notifier,
 		reloadConsumers:    []chan bool{},
 		stopWatcherChannel: make(chan bool),
+		mappingOverrides:   map[int][]string{},
+
+		// these are only ever refilled, never replaced, so they're safe to hold on to (see sliderMap.replace)
+		SliderMapping:           newSliderMap(),
+		configuredSliderMapping: newSliderMap(),
 	}
 
-	// distinguish between the user-provided config (config.yaml) and the internal config (logs/preferences.yaml)
-	userConfig := viper.New()
-	userConfig.SetConfigName(userConfigName)
-	userConfig.SetConfigType(configType)
-	userConfig.AddConfigPath(userConfigPath)
+	userConfigFilepath = resolveUserConfigFilepath(logger)
+	logger.Debugw("Resolved user config location", "path", userConfigFilepath)
 
-	userConfig.SetDefault(configKeySliderMapping, map[string][]string{})
-	userConfig.SetDefault(configKeyInvertSliders, false)
-	userConfig.SetDefault(configKeyCOMPort, defaultCOMPort)
-	userConfig.SetDefault(configKeyBaudRate, defaultBaudRate)
+	// distinguish between the user-provided config (config.yaml) and the internal config (logs/preferences.yaml)
+	userConfig := newUserConfigViper()
 
 	internalConfig := viper.New()
 	internalConfig.SetConfigName(internalConfigName)
@@ -96,55 +310,162 @@ func NewConfig(logger *zap.SugaredLogger, notifier Notifier) (*CanonicalConfig,
 	return cc, nil
 }
 
+// newUserConfigViper creates an empty viper instance for the user config, with all defaults set
+func newUserConfigViper() *viper.Viper {
+	userConfig := viper.New()
+	userConfig.SetConfigFile(userConfigFilepath)
+	userConfig.SetConfigType(configType)
+
+	userConfig.SetDefault(configKeySliderMapping, map[string][]string{})
+	userConfig.SetDefault(configKeyInvertSliders, false)
+	userConfig.SetDefault(configKeyPassiveMode, false)
+	userConfig.SetDefault(configKeyConnectionType, connectionTypeSerial)
+	userConfig.SetDefault(configKeyCOMPort, defaultCOMPort)
+	userConfig.SetDefault(configKeyBaudRate, defaultBaudRate)
+	userConfig.SetDefault(configKeyPermissionDialog, dialogBackendAuto)
+	userConfig.SetDefault(configKeyExternalVolume, externalVolumeChangeIgnore)
+	userConfig.SetDefault(configKeyVolumeChangeMute, volumeChangeMuteLeave)
+	userConfig.SetDefault(configKeyVolumeRamp, defaultVolumeRamp)
+	userConfig.SetDefault(configKeyPersistVolumes, false)
+	userConfig.SetDefault(configKeyFocusMode, util.FocusModeForeground)
+	userConfig.SetDefault(configKeyGestures, map[string]string{})
+	userConfig.SetDefault(configKeyDuckLevel, defaultDuckLevel)
+	userConfig.SetDefault(configKeyCheckForUpdates, true)
+	userConfig.SetDefault(configKeyDefaultSliderCount, defaultSliderCount)
+	userConfig.SetDefault(configKeyLocale, localeAuto)
+	userConfig.SetDefault(configKeyWebTheme, webThemeAuto)
+	userConfig.SetDefault(configKeyWebHost, defaultWebHost)
+	userConfig.SetDefault(configKeyWebPort, defaultWebPort)
+	userConfig.SetDefault(configKeyAudioBackend, audioBackendAuto)
+	userConfig.SetDefault(configKeySessionRefresh, sessionRefreshAuto)
+	userConfig.SetDefault(configKeyRefreshFrequency, defaultRefreshFrequency)
+
+	return userConfig
+}
+
 // Load reads deej's config files from disk and tries to parse them
 func (cc *CanonicalConfig) Load() error {
 	cc.logger.Debugw("Loading config", "path", userConfigFilepath)
 
-	// make sure it exists
-	if !util.FileExists(userConfigFilepath) {
-		cc.logger.Warnw("Config file not found", "path", userConfigFilepath)
-		cc.notifier.Notify("Can't find configuration!",
-			fmt.Sprintf("%s must be in the same directory as deej. Please re-launch", userConfigFilepath))
+	userConfig, err := cc.readUserConfig()
+	if err != nil {
+		title, message := describeError(err)
+		cc.notifier.Notify(title, message)
 
-		return fmt.Errorf("config file doesn't exist: %s", userConfigFilepath)
+		return err
 	}
 
-	// load the user config
-	if err := cc.userConfig.ReadInConfig(); err != nil {
-		cc.logger.Warnw("Viper failed to read user config", "error", err)
+	if err := cc.apply(userConfig); err != nil {
+		return err
+	}
 
-		// if the error is yaml-format-related, show a sensible error. otherwise, show 'em to the logs
-		if strings.Contains(err.Error(), "yaml:") {
-			cc.notifier.Notify("Invalid configuration!",
-				fmt.Sprintf("Please make sure %s is in a valid YAML format.", userConfigFilepath))
-		} else {
-			cc.notifier.Notify("Error loading configuration!", "Please check deej's logs for more details.")
+	cc.logger.Info("Loaded config successfully")
+	cc.logger.Infow("Config values",
+		"sliderMapping", cc.SliderMapping,
+		"connectionInfo", cc.ConnectionInfo,
+		"invertSliders", cc.InvertSliders)
+
+	return nil
+}
+
+// readUserConfig reads and validates the user config into a new viper instance, leaving the current one untouched
+func (cc *CanonicalConfig) readUserConfig() (*viper.Viper, error) {
+
+	systemConfig := systemConfigFilepath()
+	hasSystemConfig := systemConfig != "" && util.FileExists(systemConfig)
+
+	// make sure there's something to read. a system-wide config is enough to run with
+	if !util.FileExists(userConfigFilepath) && !hasSystemConfig {
+		cc.logger.Warnw("Config file not found", "path", userConfigFilepath)
+		return nil, fmt.Errorf("config file doesn't exist: %s: %w", userConfigFilepath, errConfigNotFound)
+	}
+
+	userConfig := newUserConfigViper()
+
+	// the system-wide config goes first, so the user config overrides it key by key
+	if hasSystemConfig {
+		if err := mergeConfigFile(userConfig, systemConfig); err != nil {
+			cc.logger.Warnw("Viper failed to read system-wide config", "path", systemConfig, "error", err)
+			return nil, withConfigReadErrorCode(systemConfig, fmt.Errorf("read system-wide config: %w", err))
+		}
+
+		cc.logger.Debugw("Read system-wide config", "path", systemConfig)
+	}
+
+	if util.FileExists(userConfigFilepath) {
+		if err := mergeConfigFile(userConfig, userConfigFilepath); err != nil {
+			cc.logger.Warnw("Viper failed to read user config", "error", err)
+			return nil, withConfigReadErrorCode(userConfigFilepath, fmt.Errorf("read user config: %w", err))
 		}
+	}
 
-		return fmt.Errorf("read user config: %w", err)
+	if err := validateUserConfig(userConfig); err != nil {
+		cc.logger.Warnw("User config failed validation", "error", err)
+		return nil, fmt.Errorf("validate user config: %w", err)
 	}
 
+	return userConfig, nil
+}
+
+// apply makes a successfully read user config the current one
+func (cc *CanonicalConfig) apply(userConfig *viper.Viper) error {
 	// load the internal config - this doesn't have to exist, so it can error
+	cc.internalLock.Lock()
 	if err := cc.internalConfig.ReadInConfig(); err != nil {
 		cc.logger.Debugw("Viper failed to read internal config", "error", err, "reminder", "this is fine")
 	}
+	cc.internalLock.Unlock()
+
+	// canonize the configuration with viper's helpers. anything that can't fall back to a sensible value was already
+	// caught by validateUserConfig, before this config got here
+	cc.lock.Lock()
+	cc.userConfig = userConfig
+	err := cc.populateFromVipers()
+	cc.lock.Unlock()
 
-	// canonize the configuration with viper's helpers
-	if err := cc.populateFromVipers(); err != nil {
+	if err != nil {
 		cc.logger.Warnw("Failed to populate config fields", "error", err)
 		return fmt.Errorf("populate config fields: %w", err)
 	}
 
-	cc.logger.Info("Loaded config successfully")
-	cc.logger.Infow("Config values",
-		"sliderMapping", cc.SliderMapping,
-		"connectionInfo", cc.ConnectionInfo,
-		"invertSliders", cc.InvertSliders)
+	return nil
+}
+
+// validateUserConfig catches mistakes that would otherwise silently break the config, like mistyped slider indexes.
+// anything with a sensible fallback (e.g. an unknown noise reduction level) is left to populateFromVipers
+func validateUserConfig(userConfig *viper.Viper) error {
+	if err := validateSliderIndexes(userConfig, configKeySliderMapping); err != nil {
+		return err
+	}
+
+	// profiles replace the slider mapping when they're switched to, so they're held to the same rules
+	for profileName := range userConfig.GetStringMap(configKeyProfiles) {
+		key := fmt.Sprintf("%s.%s.%s", configKeyProfiles, profileName, configKeySliderMapping)
+		if err := validateSliderIndexes(userConfig, key); err != nil {
+			return err
+		}
+	}
+
+	if userConfig.IsSet(configKeyBaudRate) && userConfig.GetInt(configKeyBaudRate) <= 0 {
+		return fmt.Errorf("%s: must be a positive number: %w", configKeyBaudRate, errInvalidConfig)
+	}
+
+	return nil
+}
+
+// validateSliderIndexes checks that every slider in the slider mapping under key is a slider index
+func validateSliderIndexes(userConfig *viper.Viper, key string) error {
+	for sliderIdxString := range userConfig.GetStringMapStringSlice(key) {
+		if sliderIdx, err := strconv.Atoi(sliderIdxString); err != nil || sliderIdx < 0 {
+			return fmt.Errorf("%s: %q isn't a slider index: %w", key, sliderIdxString, errInvalidConfig)
+		}
+	}
 
 	return nil
 }
 
-// SubscribeToChanges allows external components to receive updates when the config is reloaded
+// SubscribeToChanges allows external components to receive updates when the config is reloaded, along with whether
+// the reload changed anything that decides what volume a slider sets
 func (cc *CanonicalConfig) SubscribeToChanges() chan bool {
 	c := make(chan bool)
 	cc.reloadConsumers = append(cc.reloadConsumers, c)
@@ -157,50 +478,92 @@ func (cc *CanonicalConfig) SubscribeToChanges() chan bool {
 func (cc *CanonicalConfig) WatchConfigFileChanges() {
 	cc.logger.Debugw("Starting to watch user config file for changes", "path", userConfigFilepath)
 
-	const (
-		minTimeBetweenReloadAttempts = time.Millisecond * 500
-		delayBetweenEventAndReload   = time.Millisecond * 50
-	)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		cc.logger.Warnw("Failed to create config file watcher", "error", err)
+		return
+	}
+	defer watcher.Close()
 
-	lastAttemptedReload := time.Now()
+	// watch the directory rather than the file itself: many editors save by replacing the file,
+	// which would silently end a watch on the original
+	if err := watcher.Add(filepath.Dir(userConfigFilepath)); err != nil {
+		cc.logger.Warnw("Failed to watch config file directory", "error", err)
+		return
+	}
 
-	// establish watch using viper as opposed to doing it ourselves, though our internal cooldown is still required
-	cc.userConfig.WatchConfig()
-	cc.userConfig.OnConfigChange(func(event fsnotify.Event) {
+	// both are named config.yaml, so the check below lets either through
+	if system := systemConfigFilepath(); system != "" && util.FileExists(system) {
+		if err := watcher.Add(filepath.Dir(system)); err != nil {
+			cc.logger.Warnw("Failed to watch system-wide config directory", "error", err)
+		}
+	}
 
-		// when we get a write event...
-		if event.Op&fsnotify.Write == fsnotify.Write {
+	// editors tend to write a file several times per save, so wait for things to settle before reloading
+	debounceTimer := time.NewTimer(configReloadDebounce)
+	debounceTimer.Stop()
 
-			now := time.Now()
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
 
-			// ... check if it's not a duplicate (many editors will write to a file twice)
-			if lastAttemptedReload.Add(minTimeBetweenReloadAttempts).Before(now) {
+			if filepath.Base(event.Name) != filepath.Base(userConfigFilepath) || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
 
-				// and attempt reload if appropriate
-				cc.logger.Debugw("Config file modified, attempting reload", "event", event)
+			cc.logger.Debugw("Config file modified, scheduling reload", "event", event)
+			debounceTimer.Reset(configReloadDebounce)
 
-				// wait a bit to let the editor actually flush the new file contents to disk
-				<-time.After(delayBetweenEventAndReload)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
 
-				if err := cc.Load(); err != nil {
-					cc.logger.Warnw("Failed to reload config file", "error", err)
-				} else {
-					cc.logger.Info("Reloaded config successfully")
-					cc.notifier.Notify("Configuration reloaded!", "Your changes have been applied.")
+			cc.logger.Warnw("Config file watcher error", "error", err)
 
-					cc.onConfigReloaded()
-				}
+		case <-debounceTimer.C:
+			cc.reload()
 
-				// don't forget to update the time
-				lastAttemptedReload = now
-			}
+		case <-cc.stopWatcherChannel:
+			cc.logger.Debug("Stopping user config file watcher")
+			return
 		}
-	})
+	}
+}
+
+// reload re-reads the config after it changed on disk. if the new config can't be used, deej keeps running
+// with the last one that worked, and lets the user know once (rather than on every save until it's fixed)
+func (cc *CanonicalConfig) reload() {
+	userConfig, err := cc.readUserConfig()
+	if err != nil {
+		cc.logger.Warnw("Failed to reload config file, keeping last working config", "error", err)
+
+		if err.Error() != cc.lastReloadError {
+			title, message := describeError(err)
+			cc.notifier.Notify(title, tr("notify.config_previous_kept", message))
+		}
+
+		cc.lastReloadError = err.Error()
+		return
+	}
+
+	cc.lastReloadError = ""
+	previous := cc.userConfig
+
+	if err := cc.apply(userConfig); err != nil {
+		cc.logger.Warnw("Failed to apply reloaded config", "error", err)
+		return
+	}
+
+	volumesChanged := volumeSettingsChanged(previous, userConfig)
 
-	// wait till they stop us
-	<-cc.stopWatcherChannel
-	cc.logger.Debug("Stopping user config file watcher")
-	cc.userConfig.OnConfigChange(nil)
+	cc.logger.Infow("Reloaded config successfully", "volumesChanged", volumesChanged)
+	cc.notifier.Notify(tr("notify.config_reloaded.title"), tr("notify.config_reloaded.message"))
+
+	cc.onConfigReloaded(volumesChanged)
 }
 
 // StopWatchingConfigFile signals our filesystem watcher to stop
@@ -210,16 +573,473 @@ func (cc *CanonicalConfig) StopWatchingConfigFile() {
 
 func (cc *CanonicalConfig) populateFromVipers() error {
 
-	// merge the slider mappings from the user and internal configs
-	cc.SliderMapping = sliderMapFromConfigs(
-		cc.userConfig.GetStringMapStringSlice(configKeySliderMapping),
-		cc.internalConfig.GetStringMapStringSlice(configKeySliderMapping),
-	)
+	cc.Profiles = nil
+	for profileName := range cc.userConfig.GetStringMap(configKeyProfiles) {
+		cc.Profiles = append(cc.Profiles, profileName)
+	}
+
+	sort.Strings(cc.Profiles)
+
+	// the active profile might have been removed from the config since it was activated
+	if cc.ActiveProfile != "" && !funk.ContainsString(cc.Profiles, cc.ActiveProfile) {
+		cc.logger.Warnw("Active profile no longer exists, switching back to the default mapping", "profile", cc.ActiveProfile)
+		cc.ActiveProfile = ""
+	}
+
+	cc.populateSliderModes()
+	cc.populateCompositeSliders()
+	cc.populateSliderMapping()
+
+	cc.populateConnectionInfo()
+
+	cc.AudioBackend = strings.ToLower(cc.userConfig.GetString(configKeyAudioBackend))
+	if _, ok := sessionFinderBackends[cc.AudioBackend]; !ok && cc.AudioBackend != audioBackendAuto {
+		cc.logger.Warnw("Unknown audio backend specified, using default value",
+			"key", configKeyAudioBackend,
+			"invalidValue", cc.AudioBackend,
+			"validValues", sessionFinderBackendNames(),
+			"defaultValue", audioBackendAuto)
+
+		cc.AudioBackend = audioBackendAuto
+	}
 
 	// get the rest of the config fields - viper saves us a lot of effort here
-	cc.ConnectionInfo.COMPort = cc.userConfig.GetString(configKeyCOMPort)
+	cc.InvertSliders = cc.userConfig.GetBool(configKeyInvertSliders)
+	cc.PassiveMode = cc.userConfig.GetBool(configKeyPassiveMode)
+	cc.CheckForUpdates = cc.userConfig.GetBool(configKeyCheckForUpdates)
+
+	cc.populateDefaultSliderCount()
+	cc.populateSliderNames()
+
+	cc.Locale = strings.ToLower(cc.userConfig.GetString(configKeyLocale))
+	if cc.Locale != localeAuto && !supportedLocale(cc.Locale) {
+		cc.logger.Warnw("Unsupported locale specified, using default value",
+			"key", configKeyLocale,
+			"invalidValue", cc.Locale,
+			"defaultValue", localeAuto)
+
+		cc.Locale = localeAuto
+	}
+
+	cc.logger.Debugw("Using locale", "locale", setLocale(cc.Locale))
+
+	cc.WebTheme = strings.ToLower(cc.userConfig.GetString(configKeyWebTheme))
+	switch cc.WebTheme {
+	case webThemeAuto, webThemeLight, webThemeDark:
+	default:
+		cc.logger.Warnw("Invalid web theme specified, using default value",
+			"key", configKeyWebTheme,
+			"invalidValue", cc.WebTheme,
+			"defaultValue", webThemeAuto)
+
+		cc.WebTheme = webThemeAuto
+	}
+
+	cc.WebHost = strings.ToLower(strings.TrimSpace(cc.userConfig.GetString(configKeyWebHost)))
+	if cc.WebHost != defaultWebHost && net.ParseIP(cc.WebHost) == nil {
+		cc.logger.Warnw("Invalid web host specified, using default value",
+			"key", configKeyWebHost,
+			"invalidValue", cc.WebHost,
+			"defaultValue", defaultWebHost)
+
+		cc.WebHost = defaultWebHost
+	}
+
+	cc.WebPort = cc.userConfig.GetInt(configKeyWebPort)
+	if cc.WebPort <= 0 || cc.WebPort > 65535 {
+		cc.logger.Warnw("Invalid web port specified, using default value",
+			"key", configKeyWebPort,
+			"invalidValue", cc.WebPort,
+			"defaultValue", defaultWebPort)
 
-	cc.ConnectionInfo.BaudRate = cc.userConfig.GetInt(configKeyBaudRate)
+		cc.WebPort = defaultWebPort
+	}
+
+	// secret already warned about a token it couldn't decrypt, which leaves it unset
+	cc.WebToken, _ = cc.secret(configKeyWebToken)
+
+	// anyone on the network could change the config and volumes otherwise
+	if ip := net.ParseIP(cc.WebHost); ip != nil && !ip.IsLoopback() && cc.WebToken == "" {
+		cc.logger.Warnw("Web host is reachable from the network but no web token is set, using default value",
+			"key", configKeyWebHost,
+			"invalidValue", cc.WebHost,
+			"defaultValue", defaultWebHost)
+
+		cc.WebHost = defaultWebHost
+	}
+
+	cc.NoiseReductionLevel = cc.userConfig.GetString(configKeyNoiseReductionLevel)
+	cc.PermissionDialog = cc.userConfig.GetString(configKeyPermissionDialog)
+
+	cc.ExternalVolumeChange = strings.ToLower(cc.userConfig.GetString(configKeyExternalVolume))
+	switch cc.ExternalVolumeChange {
+	case externalVolumeChangeIgnore, externalVolumeChangeReassert, externalVolumeChangeAdopt:
+	default:
+		cc.logger.Warnw("Invalid external volume change policy specified, using default value",
+			"key", configKeyExternalVolume,
+			"invalidValue", cc.ExternalVolumeChange,
+			"defaultValue", externalVolumeChangeIgnore)
+
+		cc.ExternalVolumeChange = externalVolumeChangeIgnore
+	}
+
+	cc.VolumeChangeMute = strings.ToLower(cc.userConfig.GetString(configKeyVolumeChangeMute))
+	switch cc.VolumeChangeMute {
+	case volumeChangeMuteLeave, volumeChangeMutePreserve, volumeChangeMuteUnmute:
+	default:
+		cc.logger.Warnw("Invalid volume change mute behavior specified, using default value",
+			"key", configKeyVolumeChangeMute,
+			"invalidValue", cc.VolumeChangeMute,
+			"defaultValue", volumeChangeMuteLeave)
+
+		cc.VolumeChangeMute = volumeChangeMuteLeave
+	}
+
+	volumeRamp := cc.userConfig.GetFloat64(configKeyVolumeRamp)
+	if volumeRamp < 0 || volumeRamp > maxVolumeRamp {
+		cc.logger.Warnw("Invalid volume ramp specified, using default value",
+			"key", configKeyVolumeRamp,
+			"invalidValue", volumeRamp,
+			"defaultValue", defaultVolumeRamp)
+
+		volumeRamp = defaultVolumeRamp
+	}
+
+	cc.VolumeRamp = time.Duration(volumeRamp * float64(time.Second))
+
+	cc.PersistVolumes = cc.userConfig.GetBool(configKeyPersistVolumes)
+
+	cc.SessionRefresh = strings.ToLower(cc.userConfig.GetString(configKeySessionRefresh))
+	if cc.SessionRefresh != sessionRefreshAuto && cc.SessionRefresh != sessionRefreshManual {
+		cc.logger.Warnw("Invalid session refresh mode specified, using default value",
+			"key", configKeySessionRefresh,
+			"invalidValue", cc.SessionRefresh,
+			"defaultValue", sessionRefreshAuto)
+
+		cc.SessionRefresh = sessionRefreshAuto
+	}
+
+	refreshFrequency := cc.userConfig.GetFloat64(configKeyRefreshFrequency)
+	if refreshFrequency < 0 {
+		cc.logger.Warnw("Invalid process refresh frequency specified, using default value",
+			"key", configKeyRefreshFrequency,
+			"invalidValue", refreshFrequency,
+			"defaultValue", defaultRefreshFrequency)
+
+		refreshFrequency = defaultRefreshFrequency
+	}
+
+	cc.SessionRefreshInterval = time.Duration(refreshFrequency * float64(time.Second))
+
+	cc.FocusMode = strings.ToLower(cc.userConfig.GetString(configKeyFocusMode))
+	if cc.FocusMode != util.FocusModeForeground && cc.FocusMode != util.FocusModeCursor {
+		cc.logger.Warnw("Invalid current window focus mode specified, using default value",
+			"key", configKeyFocusMode,
+			"invalidValue", cc.FocusMode,
+			"defaultValue", util.FocusModeForeground)
+
+		cc.FocusMode = util.FocusModeForeground
+	}
+
+	cc.Gestures = map[string]string{}
+	for gesture, action := range cc.userConfig.GetStringMapString(configKeyGestures) {
+		if !funk.ContainsString(supportedGestures, gesture) {
+			cc.logger.Warnw("Unknown gesture in config, ignoring", "gesture", gesture)
+			continue
+		}
+
+		if err := validateAction(action); err != nil {
+			cc.logger.Warnw("Invalid gesture action in config, ignoring", "gesture", gesture, "action", action, "error", err)
+			continue
+		}
+
+		cc.Gestures[gesture] = action
+	}
+
+	cc.Buttons = map[int]string{}
+	for buttonIdxString, action := range cc.userConfig.GetStringMapString(configKeyButtons) {
+		buttonIdx, err := strconv.Atoi(buttonIdxString)
+		if err != nil || buttonIdx < 0 {
+			cc.logger.Warnw("Invalid button index in config, ignoring", "key", configKeyButtons, "button", buttonIdxString)
+			continue
+		}
+
+		if err := validateAction(action); err != nil {
+			cc.logger.Warnw("Invalid button action in config, ignoring", "button", buttonIdx, "action", action, "error", err)
+			continue
+		}
+
+		cc.Buttons[buttonIdx] = action
+	}
+
+	cc.Hotkeys = map[string]string{}
+	for spec, action := range cc.userConfig.GetStringMapString(configKeyHotkeys) {
+		parsed, err := parseHotkey(spec)
+		if err != nil {
+			cc.logger.Warnw("Invalid hotkey in config, ignoring", "hotkey", spec, "error", err)
+			continue
+		}
+
+		if err := validateAction(action); err != nil {
+			cc.logger.Warnw("Invalid hotkey action in config, ignoring", "hotkey", spec, "action", action, "error", err)
+			continue
+		}
+
+		cc.Hotkeys[parsed.String()] = action
+	}
+
+	cc.LockPolicies = []string{}
+	for _, policy := range cc.userConfig.GetStringSlice(configKeyOnLock) {
+		policy = strings.ToLower(policy)
+		if !funk.ContainsString(supportedLockPolicies, policy) {
+			cc.logger.Warnw("Unknown lock policy in config, ignoring", "key", configKeyOnLock, "policy", policy)
+			continue
+		}
+
+		cc.LockPolicies = append(cc.LockPolicies, policy)
+	}
+
+	cc.populateSchedule()
+
+	duckLevel := cc.userConfig.GetInt(configKeyDuckLevel)
+	if duckLevel < 0 || duckLevel > 100 {
+		cc.logger.Warnw("Invalid duck level specified, using default value",
+			"key", configKeyDuckLevel,
+			"invalidValue", duckLevel,
+			"defaultValue", defaultDuckLevel)
+
+		duckLevel = defaultDuckLevel
+	}
+
+	cc.DuckLevel = float32(duckLevel) / 100
+
+	cc.TargetTrim = map[string]volumeTrim{}
+	for target, spec := range cc.userConfig.GetStringMapString(configKeyTargetTrim) {
+		trim, err := parseVolumeTrim(spec)
+		if err != nil {
+			cc.logger.Warnw("Invalid target trim in config, ignoring", "target", target, "trim", spec, "error", err)
+			continue
+		}
+
+		cc.TargetTrim[strings.ToLower(target)] = trim
+	}
+
+	cc.VolumeLimits = map[string]float32{}
+	for target, spec := range cc.userConfig.GetStringMapString(configKeyVolumeLimits) {
+		percent, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(spec), "%"))
+		if err != nil || percent < 0 || percent > 100 {
+			cc.logger.Warnw("Invalid volume limit in config, ignoring", "key", configKeyVolumeLimits, "target", target, "limit", spec)
+			continue
+		}
+
+		cc.VolumeLimits[strings.ToLower(target)] = float32(percent) / 100
+	}
+
+	cc.LoudnessOffsets = map[string]float32{}
+	for target, spec := range cc.userConfig.GetStringMapString(configKeyLoudnessOffsets) {
+		offset, err := parseLoudnessOffset(spec)
+		if err != nil {
+			cc.logger.Warnw("Invalid loudness offset in config, ignoring", "key", configKeyLoudnessOffsets, "target", target, "error", err)
+			continue
+		}
+
+		cc.LoudnessOffsets[strings.ToLower(target)] = offset
+	}
+
+	var roleRuleErrs []error
+	cc.SessionRoles, roleRuleErrs = parseSessionRoleRules(cc.userConfig.GetStringMapStringSlice(configKeySessionRoles))
+	for _, err := range roleRuleErrs {
+		cc.logger.Warnw("Invalid session role rule in config, ignoring", "key", configKeySessionRoles, "error", err)
+	}
+
+	cc.populateSliderMaxVolumes()
+	cc.populateSliderDetents()
+	cc.populateHardwareSettings()
+	cc.populateIdleTimeouts()
+
+	cc.logger.Debug("Populated config fields from vipers")
+
+	return nil
+}
+
+// populateSliderMaxVolumes reads which sliders are allowed to boost their targets past 100%, and how far
+func (cc *CanonicalConfig) populateSliderMaxVolumes() {
+	cc.SliderMaxVolume = map[int]float32{}
+
+	for sliderIdxString := range cc.userConfig.GetStringMap(configKeySliderOptions) {
+		sliderIdx, err := strconv.Atoi(sliderIdxString)
+		if err != nil || sliderIdx < 0 {
+			cc.logger.Warnw("Invalid slider index in slider options, ignoring", "key", configKeySliderOptions, "slider", sliderIdxString)
+			continue
+		}
+
+		optionsKey := fmt.Sprintf("%s.%s", configKeySliderOptions, sliderIdxString)
+		if !cc.userConfig.GetBool(optionsKey + "." + configKeyAllowBoost) {
+			continue
+		}
+
+		if !sessionVolumeBoostSupported {
+			cc.logger.Warnw("Volume boost isn't supported on this platform, ignoring", "slider", sliderIdx)
+			continue
+		}
+
+		maxBoost := defaultMaxBoost
+		if cc.userConfig.IsSet(optionsKey + "." + configKeyMaxBoost) {
+			maxBoost = cc.userConfig.GetInt(optionsKey + "." + configKeyMaxBoost)
+		}
+
+		if maxBoost <= 100 || maxBoost > maxMaxBoost {
+			cc.logger.Warnw("Invalid max boost specified, using default value",
+				"slider", sliderIdx,
+				"invalidValue", maxBoost,
+				"defaultValue", defaultMaxBoost)
+
+			maxBoost = defaultMaxBoost
+		}
+
+		cc.SliderMaxVolume[sliderIdx] = float32(maxBoost) / 100
+	}
+}
+
+// populateSliderDetents reads which sliders snap to set positions
+func (cc *CanonicalConfig) populateSliderDetents() {
+	cc.SliderDetents = map[int]sliderDetents{}
+
+	for sliderIdxString := range cc.userConfig.GetStringMap(configKeySliderOptions) {
+		sliderIdx, err := strconv.Atoi(sliderIdxString)
+		optionsKey := fmt.Sprintf("%s.%s", configKeySliderOptions, sliderIdxString)
+
+		// populateSliderMaxVolumes already warned about bad indexes
+		if err != nil || sliderIdx < 0 || !cc.userConfig.IsSet(optionsKey+"."+configKeyDetents) {
+			continue
+		}
+
+		detents, err := parseSliderDetents(cc.userConfig.GetStringSlice(optionsKey+"."+configKeyDetents),
+			cc.userConfig.GetInt(optionsKey+"."+configKeySnapRange))
+		if err != nil {
+			cc.logger.Warnw("Invalid slider detents in config, ignoring", "slider", sliderIdx, "error", err)
+			continue
+		}
+
+		cc.SliderDetents[sliderIdx] = detents
+	}
+}
+
+// populateHardwareSettings reads the settings to push to the board. if any of them is invalid, none are pushed
+func (cc *CanonicalConfig) populateHardwareSettings() {
+	settings := newHardwareSettings()
+	hardwareKey := func(name string) string { return fmt.Sprintf("%s.%s", configKeyHardware, name) }
+
+	if cc.userConfig.IsSet(hardwareKey(hardwareKeyReportRate)) {
+		settings.ReportRate = cc.userConfig.GetInt(hardwareKey(hardwareKeyReportRate))
+	}
+
+	if cc.userConfig.IsSet(hardwareKey(hardwareKeyLEDBrightness)) {
+		settings.LEDBrightness = cc.userConfig.GetInt(hardwareKey(hardwareKeyLEDBrightness))
+	}
+
+	for sliderIdxString := range cc.userConfig.GetStringMap(hardwareKey(hardwareKeySmoothing)) {
+		sliderIdx, err := strconv.Atoi(sliderIdxString)
+		if err != nil {
+			cc.logger.Warnw("Invalid slider index in hardware smoothing settings, ignoring", "slider", sliderIdxString)
+			continue
+		}
+
+		settings.Smoothing[sliderIdx] = cc.userConfig.GetInt(hardwareKey(hardwareKeySmoothing) + "." + sliderIdxString)
+	}
+
+	for sliderIdxString, color := range cc.userConfig.GetStringMapString(hardwareKey(hardwareKeyColors)) {
+		sliderIdx, err := strconv.Atoi(sliderIdxString)
+		if err != nil {
+			cc.logger.Warnw("Invalid slider index in hardware color settings, ignoring", "slider", sliderIdxString)
+			continue
+		}
+
+		settings.Colors[sliderIdx] = color
+	}
+
+	if err := settings.validate(); err != nil {
+		cc.logger.Warnw("Invalid hardware settings in config, not pushing any to the board", "key", configKeyHardware, "error", err)
+		settings = newHardwareSettings()
+	}
+
+	cc.Hardware = settings
+}
+
+// populateSchedule reads the schedule, leaving out times that don't parse and actions deej doesn't know
+func (cc *CanonicalConfig) populateSchedule() {
+	cc.Schedule = []scheduleEntry{}
+
+	for spec, actions := range cc.userConfig.GetStringMapStringSlice(configKeySchedule) {
+		entry, err := parseScheduleTime(spec)
+		if err != nil {
+			cc.logger.Warnw("Invalid schedule time in config, ignoring", "key", configKeySchedule, "error", err)
+			continue
+		}
+
+		for _, action := range actions {
+			if err := validateAction(action); err != nil {
+				cc.logger.Warnw("Invalid scheduled action in config, ignoring", "time", spec, "action", action, "error", err)
+				continue
+			}
+
+			entry.actions = append(entry.actions, action)
+		}
+
+		if len(entry.actions) > 0 {
+			cc.Schedule = append(cc.Schedule, entry)
+		}
+	}
+
+	sort.Slice(cc.Schedule, func(i, j int) bool {
+		if cc.Schedule[i].minute != cc.Schedule[j].minute {
+			return cc.Schedule[i].minute < cc.Schedule[j].minute
+		}
+
+		return cc.Schedule[i].spec < cc.Schedule[j].spec
+	})
+}
+
+// ScheduleEntries returns a copy of the schedule, safe to use while the config reloads
+func (cc *CanonicalConfig) ScheduleEntries() []scheduleEntry {
+	cc.lock.RLock()
+	defer cc.lock.RUnlock()
+
+	return append([]scheduleEntry{}, cc.Schedule...)
+}
+
+// webTheme returns the web UI's theme, safe to use while the config reloads
+func (cc *CanonicalConfig) webTheme() string {
+	cc.lock.RLock()
+	defer cc.lock.RUnlock()
+
+	return cc.WebTheme
+}
+
+// webToken returns the web token, safe to use while the config reloads
+func (cc *CanonicalConfig) webToken() string {
+	cc.lock.RLock()
+	defer cc.lock.RUnlock()
+
+	return cc.WebToken
+}
+
+// populateConnectionInfo reads how to reach the board, letting the active profile override any of it
+// (e.g. a profile for a second mixer on another port)
+func (cc *CanonicalConfig) populateConnectionInfo() {
+	cc.ConnectionInfo.Type = strings.ToLower(cc.userConfig.GetString(cc.profileKey(configKeyConnectionType)))
+	if cc.ConnectionInfo.Type != connectionTypeSerial && cc.ConnectionInfo.Type != connectionTypeHID {
+		cc.logger.Warnw("Invalid connection type specified, using default value",
+			"key", configKeyConnectionType,
+			"invalidValue", cc.ConnectionInfo.Type,
+			"defaultValue", connectionTypeSerial)
+
+		cc.ConnectionInfo.Type = connectionTypeSerial
+	}
+
+	cc.ConnectionInfo.COMPort = cc.userConfig.GetString(cc.profileKey(configKeyCOMPort))
+
+	cc.ConnectionInfo.BaudRate = cc.userConfig.GetInt(cc.profileKey(configKeyBaudRate))
 	if cc.ConnectionInfo.BaudRate <= 0 {
 		cc.logger.Warnw("Invalid baud rate specified, using default value",
 			"key", configKeyBaudRate,
@@ -229,18 +1049,260 @@ func (cc *CanonicalConfig) populateFromVipers() error {
 		cc.ConnectionInfo.BaudRate = defaultBaudRate
 	}
 
-	cc.InvertSliders = cc.userConfig.GetBool(configKeyInvertSliders)
-	cc.NoiseReductionLevel = cc.userConfig.GetString(configKeyNoiseReductionLevel)
+	// usually written in hex (0x2e8a), which yaml already turns into a number for us
+	cc.ConnectionInfo.HIDVendorID = uint16(cc.userConfig.GetUint(cc.profileKey(configKeyHIDVendorID)))
+	cc.ConnectionInfo.HIDProductID = uint16(cc.userConfig.GetUint(cc.profileKey(configKeyHIDProductID)))
 
-	cc.logger.Debug("Populated config fields from vipers")
+	cc.ProbeSafety.SkipPorts = []string{}
+	for _, port := range cc.userConfig.GetStringSlice(cc.profileKey(configKeySkipPorts)) {
+		if port = strings.TrimSpace(port); port != "" {
+			cc.ProbeSafety.SkipPorts = append(cc.ProbeSafety.SkipPorts, port)
+		}
+	}
+
+	cc.ProbeSafety.SafeProbing = cc.userConfig.GetBool(cc.profileKey(configKeySafeProbing))
+
+	if cc.ConnectionInfo.Type == connectionTypeHID && cc.ConnectionInfo.HIDVendorID == 0 {
+		cc.logger.Warnw("HID connection selected without a vendor ID, the board won't be found",
+			"key", configKeyHIDVendorID)
+	}
+}
+
+// profileKey returns the active profile's version of a top-level key if the profile sets it, or the key itself
+func (cc *CanonicalConfig) profileKey(key string) string {
+	if cc.ActiveProfile == "" {
+		return key
+	}
+
+	if profileKey := fmt.Sprintf("%s.%s.%s", configKeyProfiles, cc.ActiveProfile, key); cc.userConfig.IsSet(profileKey) {
+		return profileKey
+	}
+
+	return key
+}
+
+// populateSliderMapping merges the active profile's slider mapping (or the default one) with the internal config's
+func (cc *CanonicalConfig) populateSliderMapping() {
+	userMappingKey := configKeySliderMapping
+	if cc.ActiveProfile != "" {
+		userMappingKey = fmt.Sprintf("%s.%s.%s", configKeyProfiles, cc.ActiveProfile, configKeySliderMapping)
+	}
+
+	configured := sliderMapFromConfigs(
+		cc.userConfig.GetStringMapStringSlice(userMappingKey),
+		cc.internalConfig.GetStringMapStringSlice(configKeySliderMapping),
+	)
+
+	// crossfading sliders control exactly the two targets they fade between
+	for sliderIdx, mode := range cc.Crossfades {
+		configured.set(sliderIdx, []string{mode.from, mode.to})
+	}
+
+	cc.configuredSliderMapping.replace(configured)
+	cc.SliderMapping.replace(configured.withOverrides(cc.MappingOverrides()))
+}
+
+// SetMappingOverride binds a slider to the given targets until the override is cleared or deej exits,
+// regardless of what the config says. an empty target list unbinds the slider
+func (cc *CanonicalConfig) SetMappingOverride(sliderIdx int, targets []string) error {
+	if sliderIdx < 0 {
+		return fmt.Errorf("set mapping override: slider index %d: %w", sliderIdx, errInvalidConfig)
+	}
+
+	cleanTargets := []string{}
+	for _, target := range targets {
+		if target = strings.TrimSpace(target); target != "" {
+			cleanTargets = append(cleanTargets, target)
+		}
+	}
+
+	cc.overridesLock.Lock()
+	cc.mappingOverrides[sliderIdx] = cleanTargets
+	cc.overridesLock.Unlock()
+
+	cc.logger.Infow("Set slider mapping override", "slider", sliderIdx, "targets", cleanTargets)
+	cc.onMappingOverridesChanged()
+
+	return nil
+}
+
+// ClearMappingOverride returns a slider to its configured targets
+func (cc *CanonicalConfig) ClearMappingOverride(sliderIdx int) {
+	cc.overridesLock.Lock()
+	delete(cc.mappingOverrides, sliderIdx)
+	cc.overridesLock.Unlock()
+
+	cc.logger.Infow("Cleared slider mapping override", "slider", sliderIdx)
+	cc.onMappingOverridesChanged()
+}
+
+// ClearMappingOverrides returns all sliders to their configured targets
+func (cc *CanonicalConfig) ClearMappingOverrides() {
+	cc.overridesLock.Lock()
+	cc.mappingOverrides = map[int][]string{}
+	cc.overridesLock.Unlock()
+
+	cc.logger.Info("Cleared all slider mapping overrides")
+	cc.onMappingOverridesChanged()
+}
+
+// MappingOverrides returns a copy of the currently active mapping overrides
+func (cc *CanonicalConfig) MappingOverrides() map[int][]string {
+	cc.overridesLock.Lock()
+	defer cc.overridesLock.Unlock()
+
+	result := make(map[int][]string, len(cc.mappingOverrides))
+	for sliderIdx, targets := range cc.mappingOverrides {
+		result[sliderIdx] = append([]string{}, targets...)
+	}
+
+	return result
+}
+
+func (cc *CanonicalConfig) onMappingOverridesChanged() {
+	cc.SliderMapping.replace(cc.configuredSliderMapping.withOverrides(cc.MappingOverrides()))
+	cc.onConfigReloaded(true)
+}
+
+// SetBaudRate saves the given baud rate to the config file
+func (cc *CanonicalConfig) SetBaudRate(baudRate int) error {
+	return cc.saveUserConfigValue(configKeyBaudRate, baudRate)
+}
+
+// SetCOMPort saves the given COM port to the config file
+func (cc *CanonicalConfig) SetCOMPort(comPort string) error {
+	return cc.saveUserConfigValue(configKeyCOMPort, comPort)
+}
+
+// SetWebTheme saves the given web UI theme to the config file
+func (cc *CanonicalConfig) SetWebTheme(theme string) error {
+	switch theme {
+	case webThemeAuto, webThemeLight, webThemeDark:
+	default:
+		return fmt.Errorf("%s: unknown theme %q: %w", configKeyWebTheme, theme, errInvalidConfig)
+	}
+
+	if err := cc.saveUserConfigValue(configKeyWebTheme, theme); err != nil {
+		return err
+	}
+
+	// don't wait for the file watcher, the page asks right away
+	cc.lock.Lock()
+	cc.WebTheme = theme
+	cc.lock.Unlock()
+
+	return nil
+}
+
+// SetLoudnessOffsets saves the given loudness offsets to the config file, replacing the ones it had. an empty map
+// clears them
+func (cc *CanonicalConfig) SetLoudnessOffsets(offsets map[string]float32) error {
+	formatted := make(map[string]string, len(offsets))
+	for target, offset := range offsets {
+		formatted[target] = formatLoudnessOffset(offset)
+	}
+
+	return cc.saveUserConfigValue(configKeyLoudnessOffsets, formatted)
+}
+
+// saveUserConfigValue writes a single value to the config file. the file watcher takes care of reloading it
+func (cc *CanonicalConfig) saveUserConfigValue(key string, value interface{}) error {
+	if err := cc.writeUserConfigValues(map[string]interface{}{key: value}); err != nil {
+		cc.logger.Warnw("Failed to write config file", "key", key, "error", err)
+		return fmt.Errorf("write config file: %w", err)
+	}
+
+	return nil
+}
+
+// WriteStarterConfig creates a config file for first-time users, with the master volume on the first slider
+// and the board's port left to auto-detect. it doesn't overwrite an existing config file
+func (cc *CanonicalConfig) WriteStarterConfig() error {
+	starterConfig := newUserConfigViper()
+	starterConfig.Set(configKeySliderMapping, map[string][]string{"0": {masterSessionName}})
+	starterConfig.Set(configKeyCOMPort, comPortAuto)
+
+	if err := starterConfig.SafeWriteConfigAs(userConfigFilepath); err != nil {
+		cc.logger.Warnw("Failed to write starter config file", "path", userConfigFilepath, "error", err)
+		return fmt.Errorf("write starter config file: %w", err)
+	}
+
+	cc.logger.Infow("Wrote starter config file", "path", userConfigFilepath)
 
 	return nil
 }
 
-func (cc *CanonicalConfig) onConfigReloaded() {
+// SetActiveProfile switches to the named profile's slider mapping and connection, or back to the default ones
+// if name is empty
+func (cc *CanonicalConfig) SetActiveProfile(name string) error {
+	name = strings.ToLower(name)
+
+	cc.lock.Lock()
+
+	if name != "" && !funk.ContainsString(cc.Profiles, name) {
+		cc.lock.Unlock()
+		return fmt.Errorf("no such profile: %s", name)
+	}
+
+	cc.ActiveProfile = name
+	cc.populateSliderMapping()
+
+	// serial picks up a changed connection the same way it does on a config reload
+	cc.populateConnectionInfo()
+
+	cc.logger.Infow("Switched profile", "profile", name, "sliderMapping", cc.SliderMapping, "connectionInfo", cc.ConnectionInfo)
+	cc.lock.Unlock()
+
+	cc.onConfigReloaded(true)
+
+	return nil
+}
+
+// NextProfile returns the name of the profile after the active one, wrapping around through the default mapping
+func (cc *CanonicalConfig) NextProfile() string {
+	cc.lock.RLock()
+	defer cc.lock.RUnlock()
+
+	profiles := append([]string{""}, cc.Profiles...)
+
+	for idx, profile := range profiles {
+		if profile == cc.ActiveProfile {
+			return profiles[(idx+1)%len(profiles)]
+		}
+	}
+
+	return ""
+}
+
+// volumeConfigKeys are the settings that decide what volume each slider position stands for
+var volumeConfigKeys = []string{
+	configKeySliderMapping,
+	configKeyProfiles,
+	configKeyInvertSliders,
+	configKeyTargetTrim,
+	configKeyVolumeLimits,
+	configKeyLoudnessOffsets,
+	configKeySliderOptions,
+	configKeySliderModes,
+	configKeyCompositeSliders,
+	configKeySessionRoles,
+}
+
+// volumeSettingsChanged returns true if any of volumeConfigKeys differs between the two configs
+func volumeSettingsChanged(previous *viper.Viper, current *viper.Viper) bool {
+	for _, key := range volumeConfigKeys {
+		if !reflect.DeepEqual(previous.Get(key), current.Get(key)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cc *CanonicalConfig) onConfigReloaded(volumesChanged bool) {
 	cc.logger.Debug("Notifying consumers about configuration reload")
 
 	for _, consumer := range cc.reloadConsumers {
-		consumer <- true
+		consumer <- volumesChanged
 	}
 }
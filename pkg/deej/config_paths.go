@@ -0,0 +1,271 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/omriharel/deej/pkg/deej/util"
+)
+
+// deej used to only look for its config in the working directory, so it had to be started from there. it also looks
+// in the per-user config directory ($XDG_CONFIG_HOME/deej or ~/.config/deej on linux, %APPDATA%\deej on windows), so
+// an installed deej can be started from anywhere. a config in the working directory wins, which keeps portable setups
+// (deej.exe and config.yaml in one folder) working as they always have.
+//
+// below the user config, a system-wide one (/etc/deej/config.yaml, or %ProgramData%\deej\config.yaml on windows) can
+// provide defaults, e.g. from a distro package or a fleet deployment. the user config overrides it key by key, and
+// with a system-wide config around, deej runs without a user config at all
+const (
+	userConfigFilename = "config.yaml"
+	userConfigDirName  = "deej"
+
+	systemConfigDirLinux = "/etc"
+
+	// what a config moved to the per-user directory leaves behind, so it's not lost if the move wasn't wanted
+	migratedConfigSuffix = ".migrated"
+
+	// remembered in the internal config once the user was asked about moving their config, so they're asked once
+	internalKeyConfigMigration = "config_migration"
+
+	// the user agreed to move the config, which happens the next time deej starts
+	configMigrationAccepted = "accepted"
+)
+
+// userConfigFilepath is where the user config is read from and written to, see resolveUserConfigFilepath
+var userConfigFilepath = userConfigFilename
+
+var errNoUserConfigDir = errors.New("no per-user config directory")
+
+// perUserConfigFilepath returns where the user config goes in the per-user config directory
+func perUserConfigFilepath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("%v: %w", err, errNoUserConfigDir)
+	}
+
+	return filepath.Join(dir, userConfigDirName, userConfigFilename), nil
+}
+
+// resolveUserConfigFilepath picks the user config to use: the working directory's, then the per-user one. with
+// neither around, new configs go in the working directory like they always did - unless there's a system-wide
+// config, which means deej is installed rather than unpacked somewhere, so they go in the per-user directory
+func resolveUserConfigFilepath(logger *zap.SugaredLogger) string {
+	if util.FileExists(userConfigFilename) {
+		return userConfigFilename
+	}
+
+	perUser, err := perUserConfigFilepath()
+	if err != nil {
+		logger.Debugw("Can't look for a per-user config", "error", err)
+		return userConfigFilename
+	}
+
+	if util.FileExists(perUser) {
+		return perUser
+	}
+
+	if system := systemConfigFilepath(); system != "" && util.FileExists(system) {
+		return perUser
+	}
+
+	return userConfigFilename
+}
+
+// offerConfigMigration asks users whose config is in the working directory whether to move it to the per-user config
+// directory, where deej finds it no matter where it's started from. it only ever asks once, and backends that can't
+// ask get a notification saying where the config can go instead. it runs alongside everything else, so the config
+// only moves the next time deej starts (see completeConfigMigration), before anything uses it
+func (cc *CanonicalConfig) offerConfigMigration(dialog dialogBackend) {
+	if userConfigFilepath != userConfigFilename || cc.configMigration() != "" {
+		return
+	}
+
+	perUser, err := perUserConfigFilepath()
+	if err != nil || util.FileExists(perUser) {
+		return
+	}
+
+	title := tr("notify.config_migration.title")
+
+	confirmed, err := dialog.Confirm(title, tr("notify.config_migration.message", userConfigFilename, filepath.Dir(perUser)))
+	if errors.Is(err, errDialogNotInteractive) {
+		dialog.Inform(title, tr("notify.config_migration.inform", filepath.Dir(perUser)))
+		cc.rememberConfigMigration("informed")
+		return
+	}
+
+	if err != nil {
+		cc.logger.Warnw("Failed to ask about moving the config file", "error", err)
+		return
+	}
+
+	if !confirmed {
+		cc.logger.Info("User declined to move the config file to the per-user config directory")
+		cc.rememberConfigMigration("declined")
+		return
+	}
+
+	cc.logger.Info("User agreed to move the config file to the per-user config directory on the next start")
+	cc.rememberConfigMigration(configMigrationAccepted)
+}
+
+// completeConfigMigration moves the config the user agreed to move last time deej ran. it's called while loading,
+// before anything watches or writes the config file
+func (cc *CanonicalConfig) completeConfigMigration() {
+	if userConfigFilepath != userConfigFilename || cc.configMigration() != configMigrationAccepted {
+		return
+	}
+
+	perUser, err := perUserConfigFilepath()
+	if err == nil && util.FileExists(perUser) {
+		err = fmt.Errorf("%s already exists", perUser)
+	}
+
+	if err == nil {
+		err = cc.migrateUserConfig(perUser)
+	}
+
+	if err != nil {
+		cc.logger.Warnw("Failed to move config file to the per-user config directory", "error", err)
+		cc.notifier.Notify(tr("notify.config_migration.title"), tr("notify.config_migration.failed", err.Error()))
+		cc.rememberConfigMigration("failed")
+
+		return
+	}
+
+	cc.rememberConfigMigration("migrated")
+}
+
+// configMigration returns how offerConfigMigration went, empty if it never asked
+func (cc *CanonicalConfig) configMigration() string {
+	cc.internalLock.Lock()
+	defer cc.internalLock.Unlock()
+
+	return cc.internalConfig.GetString(internalKeyConfigMigration)
+}
+
+// migrateUserConfig copies the user config to the given path and starts using it there, renaming the old one out of
+// the way (otherwise it'd keep winning)
+func (cc *CanonicalConfig) migrateUserConfig(path string) error {
+	contents, err := ioutil.ReadFile(userConfigFilepath)
+	if err != nil {
+		cc.logger.Warnw("Failed to read config file for moving", "error", err)
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	if err := util.EnsureDirExists(filepath.Dir(path)); err != nil {
+		cc.logger.Warnw("Failed to create per-user config directory", "path", filepath.Dir(path), "error", err)
+		return fmt.Errorf("create config directory: %w", err)
+	}
+
+	if err := ioutil.WriteFile(path, contents, 0644); err != nil {
+		cc.logger.Warnw("Failed to write moved config file", "path", path, "error", err)
+		return fmt.Errorf("write config file: %w", err)
+	}
+
+	if err := os.Rename(userConfigFilepath, userConfigFilepath+migratedConfigSuffix); err != nil {
+		cc.logger.Warnw("Failed to rename old config file, removing the moved copy", "error", err)
+		os.Remove(path)
+
+		return fmt.Errorf("rename old config file: %w", err)
+	}
+
+	cc.logger.Infow("Moved config file to the per-user config directory", "from", userConfigFilepath, "to", path)
+
+	userConfigFilepath = path
+	cc.userConfig.SetConfigFile(path)
+
+	return nil
+}
+
+// rememberConfigMigration saves how offerConfigMigration went to the internal config, so it doesn't ask again
+func (cc *CanonicalConfig) rememberConfigMigration(outcome string) {
+	cc.internalLock.Lock()
+	defer cc.internalLock.Unlock()
+
+	cc.internalConfig.Set(internalKeyConfigMigration, outcome)
+	cc.saveInternalConfig()
+}
+
+// saveInternalConfig writes the internal config out. callers must hold internalLock
+func (cc *CanonicalConfig) saveInternalConfig() {
+	if err := util.EnsureDirExists(internalConfigPath); err != nil {
+		cc.logger.Warnw("Failed to create internal config directory", "error", err)
+		return
+	}
+
+	if err := cc.internalConfig.WriteConfigAs(filepath.Join(internalConfigPath, internalConfigFilepath)); err != nil {
+		cc.logger.Warnw("Failed to write internal config", "error", err)
+	}
+}
+
+// systemConfigFilepath returns where a system-wide config would be, or nothing if there's nowhere for it
+func systemConfigFilepath() string {
+	if util.Linux() {
+		return filepath.Join(systemConfigDirLinux, userConfigDirName, userConfigFilename)
+	}
+
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		return ""
+	}
+
+	return filepath.Join(programData, userConfigDirName, userConfigFilename)
+}
+
+// mergeConfigFile reads a config file into the given viper instance, over whatever it already holds
+func mergeConfigFile(config *viper.Viper, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return config.MergeConfig(file)
+}
+
+// withConfigReadErrorCode tells broken yaml apart from a file that can't be read at all, naming the file either way
+func withConfigReadErrorCode(path string, err error) error {
+	var parseErr viper.ConfigParseError
+	if errors.As(err, &parseErr) {
+		return withErrorCode(errorCodeConfigSyntax, path, err)
+	}
+
+	return withErrorCode(errorCodeConfigUnreadable, path, err)
+}
+
+// writeUserConfigValues sets the given keys in the user config file, and writes it. only the file's own keys go back
+// into it, never defaults or whatever the system-wide config says, so those keep applying. the running config isn't
+// touched, the file watcher picks the change up like any other edit. with only a system-wide config around, that's
+// the first time anything goes in the per-user config directory, so it's created as needed
+func (cc *CanonicalConfig) writeUserConfigValues(values map[string]interface{}) error {
+	cc.writeLock.Lock()
+	defer cc.writeLock.Unlock()
+
+	ownConfig := viper.New()
+	ownConfig.SetConfigType(configType)
+
+	if util.FileExists(userConfigFilepath) {
+		if err := mergeConfigFile(ownConfig, userConfigFilepath); err != nil {
+			cc.logger.Warnw("Failed to read config file before writing it", "path", userConfigFilepath, "error", err)
+			return fmt.Errorf("read config file: %w", err)
+		}
+	}
+
+	for key, value := range values {
+		ownConfig.Set(key, value)
+	}
+
+	if err := util.EnsureDirExists(filepath.Dir(userConfigFilepath)); err != nil {
+		cc.logger.Warnw("Failed to create config file directory", "path", userConfigFilepath, "error", err)
+		return fmt.Errorf("create config directory: %w", err)
+	}
+
+	return ownConfig.WriteConfigAs(userConfigFilepath)
+}
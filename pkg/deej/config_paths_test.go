@@ -0,0 +1,141 @@
+package deej
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/omriharel/deej/pkg/deej/util"
+)
+
+func TestResolveUserConfigFilepath(t *testing.T) {
+	if !util.Linux() {
+		t.Skip("the per-user config directory only follows XDG_CONFIG_HOME on linux")
+	}
+
+	workDir, err := ioutil.TempDir("", "deej-work")
+	if err != nil {
+		t.Fatalf("create working directory: %v", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	configHome, err := ioutil.TempDir("", "deej-xdg")
+	if err != nil {
+		t.Fatalf("create config home: %v", err)
+	}
+	defer os.RemoveAll(configHome)
+
+	previousDir, _ := os.Getwd()
+	defer os.Chdir(previousDir)
+
+	previousConfigHome, hadConfigHome := os.LookupEnv("XDG_CONFIG_HOME")
+	defer func() {
+		if hadConfigHome {
+			os.Setenv("XDG_CONFIG_HOME", previousConfigHome)
+		} else {
+			os.Unsetenv("XDG_CONFIG_HOME")
+		}
+	}()
+
+	os.Chdir(workDir)
+	os.Setenv("XDG_CONFIG_HOME", configHome)
+
+	logger := zap.NewNop().Sugar()
+	perUser := filepath.Join(configHome, userConfigDirName, userConfigFilename)
+
+	if path := resolveUserConfigFilepath(logger); path != userConfigFilename {
+		t.Errorf("expected the working directory without any config around, got %q", path)
+	}
+
+	os.MkdirAll(filepath.Dir(perUser), 0755)
+	ioutil.WriteFile(perUser, []byte("invert_sliders: true\n"), 0644)
+
+	if path := resolveUserConfigFilepath(logger); path != perUser {
+		t.Errorf("expected the per-user config %q, got %q", perUser, path)
+	}
+
+	ioutil.WriteFile(userConfigFilename, []byte("invert_sliders: false\n"), 0644)
+
+	if path := resolveUserConfigFilepath(logger); path != userConfigFilename {
+		t.Errorf("expected the working directory's config to win, got %q", path)
+	}
+}
+
+func TestUserConfigOverridesSystemConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deej-layers")
+	if err != nil {
+		t.Fatalf("create config directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	system := filepath.Join(dir, "system.yaml")
+	user := filepath.Join(dir, "user.yaml")
+
+	ioutil.WriteFile(system, []byte("com_port: /dev/ttyACM0\nnoise_reduction: high\ninvert_sliders: true\n"), 0644)
+	ioutil.WriteFile(user, []byte("noise_reduction: low\n"), 0644)
+
+	config := newUserConfigViper()
+	for _, path := range []string{system, user} {
+		if err := mergeConfigFile(config, path); err != nil {
+			t.Fatalf("merge %s: %v", path, err)
+		}
+	}
+
+	if got := config.GetString(configKeyNoiseReductionLevel); got != "low" {
+		t.Errorf("expected the user config's noise_reduction, got %q", got)
+	}
+
+	if got := config.GetString(configKeyCOMPort); got != "/dev/ttyACM0" {
+		t.Errorf("expected the system config's com_port, got %q", got)
+	}
+
+	if !config.GetBool(configKeyInvertSliders) {
+		t.Error("expected the system config's invert_sliders")
+	}
+}
+
+func TestWritingUserConfigKeepsOtherLayersOut(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deej-layers")
+	if err != nil {
+		t.Fatalf("create config directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	previousPath := userConfigFilepath
+	defer func() { userConfigFilepath = previousPath }()
+
+	userConfigFilepath = filepath.Join(dir, "user", "config.yaml")
+
+	cc := &CanonicalConfig{logger: zap.NewNop().Sugar()}
+
+	// the user config doesn't exist yet, as with only a system-wide one around
+	if err := cc.writeUserConfigValues(map[string]interface{}{configKeyBaudRate: 115200}); err != nil {
+		t.Fatalf("write user config: %v", err)
+	}
+
+	if err := cc.writeUserConfigValues(map[string]interface{}{configKeyNoiseReductionLevel: "low"}); err != nil {
+		t.Fatalf("write user config: %v", err)
+	}
+
+	written := newUserConfigViper()
+	if err := mergeConfigFile(written, userConfigFilepath); err != nil {
+		t.Fatalf("read user config: %v", err)
+	}
+
+	if got := written.GetInt(configKeyBaudRate); got != 115200 {
+		t.Errorf("expected the earlier write's baud_rate to stay, got %d", got)
+	}
+
+	if got := written.GetString(configKeyNoiseReductionLevel); got != "low" {
+		t.Errorf("expected noise_reduction to be written, got %q", got)
+	}
+
+	data, _ := ioutil.ReadFile(userConfigFilepath)
+	if strings.Contains(string(data), configKeyWebPort) {
+		t.Errorf("expected defaults to stay out of the user config, got:\n%s", data)
+	}
+}
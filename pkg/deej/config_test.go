@@ -0,0 +1,269 @@
+package deej
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/omriharel/deej/pkg/deej/util"
+)
+
+func TestValidateUserConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		invalid bool
+	}{
+		{"empty", "", false},
+		{"slider indexes", "slider_mapping:\n  0: master\n  4: spotify.exe\n", false},
+		{"named slider", "slider_mapping:\n  volume: master\n", true},
+		{"negative slider", "slider_mapping:\n  -1: master\n", true},
+		{"profile slider indexes", "profiles:\n  gaming:\n    slider_mapping:\n      1: game.exe\n", false},
+		{"named profile slider", "profiles:\n  gaming:\n    slider_mapping:\n      volume: game.exe\n", true},
+		{"zero baud rate", "baud_rate: 0\n", true},
+		{"negative baud rate", "baud_rate: -9600\n", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			userConfig := newUserConfigViper()
+			if err := userConfig.ReadConfig(strings.NewReader(test.config)); err != nil {
+				t.Fatalf("read config: %v", err)
+			}
+
+			err := validateUserConfig(userConfig)
+			if test.invalid != errors.Is(err, errInvalidConfig) {
+				t.Errorf("expected invalid: %v, got error: %v", test.invalid, err)
+			}
+		})
+	}
+}
+
+func TestConfigOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		field  func(cc *CanonicalConfig) interface{}
+		want   interface{}
+	}{
+		{"default connection type", "", func(cc *CanonicalConfig) interface{} { return cc.ConnectionInfo.Type }, connectionTypeSerial},
+		{"hid connection type", "connection_type: HID\n", func(cc *CanonicalConfig) interface{} { return cc.ConnectionInfo.Type }, connectionTypeHID},
+		{"unknown connection type", "connection_type: bluetooth\n", func(cc *CanonicalConfig) interface{} { return cc.ConnectionInfo.Type }, connectionTypeSerial},
+		{"default baud rate", "", func(cc *CanonicalConfig) interface{} { return cc.ConnectionInfo.BaudRate }, defaultBaudRate},
+		{"hid vendor id in hex", "hid_vendor_id: 0x2e8a\n", func(cc *CanonicalConfig) interface{} { return cc.ConnectionInfo.HIDVendorID }, uint16(0x2e8a)},
+		{"skip ports", "skip_ports:\n  - COM3\n  - \" \"\n  - /dev/ttyACM*\n", func(cc *CanonicalConfig) interface{} { return cc.ProbeSafety.SkipPorts }, []string{"COM3", "/dev/ttyACM*"}},
+		{"safe probing", "safe_probing: true\n", func(cc *CanonicalConfig) interface{} { return cc.ProbeSafety.SafeProbing }, true},
+		{"default external volume change", "", func(cc *CanonicalConfig) interface{} { return cc.ExternalVolumeChange }, externalVolumeChangeIgnore},
+		{"external volume change", "external_volume_change: Adopt\n", func(cc *CanonicalConfig) interface{} { return cc.ExternalVolumeChange }, externalVolumeChangeAdopt},
+		{"unknown external volume change", "external_volume_change: fight\n", func(cc *CanonicalConfig) interface{} { return cc.ExternalVolumeChange }, externalVolumeChangeIgnore},
+		{"default slider count", "", func(cc *CanonicalConfig) interface{} { return cc.DefaultSliderCount }, defaultSliderCount},
+		{"configured default slider count", "default_slider_count: 7\n", func(cc *CanonicalConfig) interface{} { return cc.DefaultSliderCount }, 7},
+		{"invalid default slider count", "default_slider_count: 0\n", func(cc *CanonicalConfig) interface{} { return cc.DefaultSliderCount }, defaultSliderCount},
+		{"slider names", "slider_names:\n  0: \" Music  Room \"\n  2: \"\"\n  x: Game\n", func(cc *CanonicalConfig) interface{} { return cc.SliderNames }, map[int]string{0: "Music Room"}},
+		{"default persist volumes", "", func(cc *CanonicalConfig) interface{} { return cc.PersistVolumes }, false},
+		{"persist volumes", "persist_volumes: true\n", func(cc *CanonicalConfig) interface{} { return cc.PersistVolumes }, true},
+		{"volume change mute", "volume_change_mute: preserve\n", func(cc *CanonicalConfig) interface{} { return cc.VolumeChangeMute }, volumeChangeMutePreserve},
+		{"default audio backend", "", func(cc *CanonicalConfig) interface{} { return cc.AudioBackend }, audioBackendAuto},
+		{"audio backend", "audio_backend: Mock\n", func(cc *CanonicalConfig) interface{} { return cc.AudioBackend }, "mock"},
+		{"unknown audio backend", "audio_backend: oss\n", func(cc *CanonicalConfig) interface{} { return cc.AudioBackend }, audioBackendAuto},
+		{"default session refresh", "", func(cc *CanonicalConfig) interface{} { return cc.SessionRefresh }, sessionRefreshAuto},
+		{"manual session refresh", "session_refresh: Manual\n", func(cc *CanonicalConfig) interface{} { return cc.SessionRefresh }, sessionRefreshManual},
+		{"unknown session refresh", "session_refresh: never\n", func(cc *CanonicalConfig) interface{} { return cc.SessionRefresh }, sessionRefreshAuto},
+		{"refresh frequency", "process_refresh_frequency: 2.5\n", func(cc *CanonicalConfig) interface{} { return cc.SessionRefreshInterval }, 2500 * time.Millisecond},
+		{"unknown volume change mute", "volume_change_mute: always\n", func(cc *CanonicalConfig) interface{} { return cc.VolumeChangeMute }, volumeChangeMuteLeave},
+		{"volume ramp", "volume_ramp: 0.3\n", func(cc *CanonicalConfig) interface{} { return cc.VolumeRamp }, 300 * time.Millisecond},
+		{"invalid volume ramp", "volume_ramp: 30\n", func(cc *CanonicalConfig) interface{} { return cc.VolumeRamp }, time.Duration(0)},
+		{"focus mode", "current_window_focus: cursor\n", func(cc *CanonicalConfig) interface{} { return cc.FocusMode }, util.FocusModeCursor},
+		{"unknown focus mode", "current_window_focus: keyboard\n", func(cc *CanonicalConfig) interface{} { return cc.FocusMode }, util.FocusModeForeground},
+		{
+			"target trims",
+			"target_trim:\n  Spotify.exe: +10%\n  discord.exe: x0.8\n  chrome.exe: loud\n",
+			func(cc *CanonicalConfig) interface{} { return cc.TargetTrim },
+			map[string]volumeTrim{"spotify.exe": {offset: 0.1, gain: 1}, "discord.exe": {gain: 0.8}},
+		},
+		{
+			"volume limits",
+			"volume_limits:\n  Discord.exe: 70\n  master: 80%\n  chrome.exe: loud\n  spotify.exe: 150\n",
+			func(cc *CanonicalConfig) interface{} { return cc.VolumeLimits },
+			map[string]float32{"discord.exe": 0.7, "master": 0.8},
+		},
+		{
+			"loudness offsets",
+			"loudness_offsets:\n  Game.exe: -8dB\n  chrome.exe: -3.5\n  spotify.exe: +2dB\n  discord.exe: quiet\n",
+			func(cc *CanonicalConfig) interface{} { return cc.LoudnessOffsets },
+			map[string]float32{"game.exe": -8, "chrome.exe": -3.5},
+		},
+		{
+			"composite sliders",
+			"composite_sliders:\n  0: latest(0, 5)\n  1: MAX(1,6,1)\n  2: max(2, 5)\n  3: loudest(3, 4)\n  4: latest(4)\n",
+			func(cc *CanonicalConfig) interface{} { return cc.CompositeSliders },
+			map[int]compositeSlider{
+				0: {policy: compositePolicyLatest, inputs: []int{0, 5}},
+				1: {policy: compositePolicyMax, inputs: []int{1, 6}},
+			},
+		},
+		{
+			"unknown gestures",
+			"gestures:\n  teleport: mute\n",
+			func(cc *CanonicalConfig) interface{} { return cc.Gestures },
+			map[string]string{},
+		},
+		{
+			"profiles",
+			"profiles:\n  gaming:\n    slider_mapping:\n      0: game.exe\n  work:\n    slider_mapping:\n      0: zoom.exe\n",
+			func(cc *CanonicalConfig) interface{} { return cc.Profiles },
+			[]string{"gaming", "work"},
+		},
+		{
+			"hardware settings",
+			"hardware:\n  report_rate: 50\n  smoothing:\n    1: 4\n    0: 2\n",
+			func(cc *CanonicalConfig) interface{} { return cc.Hardware.payload() },
+			"report_rate=50,smoothing.0=2,smoothing.1=4",
+		},
+		{
+			"hardware colors",
+			"hardware:\n  colors:\n    1: \"#00FF88\"\n    0: ff0000\n",
+			func(cc *CanonicalConfig) interface{} { return cc.Hardware.payload() },
+			"color.0=ff0000,color.1=00ff88",
+		},
+		{
+			"idle timeouts",
+			"hardware:\n  dim_after: 60\n  off_after: 1.5\n",
+			func(cc *CanonicalConfig) interface{} { return []time.Duration{cc.IdleDimAfter, cc.IdleOffAfter} },
+			[]time.Duration{time.Minute, 1500 * time.Millisecond},
+		},
+		{
+			"invalid hardware color",
+			"hardware:\n  report_rate: 50\n  colors:\n    0: red\n",
+			func(cc *CanonicalConfig) interface{} { return cc.Hardware.payload() },
+			"",
+		},
+		{
+			"invalid hardware settings",
+			"hardware:\n  report_rate: 50\n  led_brightness: 300\n",
+			func(cc *CanonicalConfig) interface{} { return cc.Hardware.payload() },
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cc := newTestConfig(t, test.config)
+
+			if got := test.field(cc); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %#v, got %#v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestSliderMaxVolumes(t *testing.T) {
+	cc := newTestConfig(t, `
+slider_options:
+  0:
+    allow_boost: true
+  1:
+    allow_boost: true
+    max_boost: 200
+  2:
+    allow_boost: true
+    max_boost: 1000
+  3:
+    max_boost: 200
+`)
+
+	want := map[int]float32{0: 1.5, 1: 2, 2: 1.5}
+	if !sessionVolumeBoostSupported {
+		want = map[int]float32{}
+	}
+
+	if !reflect.DeepEqual(cc.SliderMaxVolume, want) {
+		t.Errorf("expected %v, got %v", want, cc.SliderMaxVolume)
+	}
+}
+
+func TestSplitTargetTrim(t *testing.T) {
+	tests := []struct {
+		target  string
+		name    string
+		trim    volumeTrim
+		trimmed bool
+	}{
+		{"spotify.exe", "spotify.exe", noTrim, false},
+		{"spotify.exe(+10%)", "spotify.exe", volumeTrim{offset: 0.1, gain: 1}, true},
+		{"discord.exe (x0.5)", "discord.exe", volumeTrim{gain: 0.5}, true},
+		{"chrome.exe(*2)", "chrome.exe", volumeTrim{gain: 2}, true},
+		{"Speakers (Realtek Audio)", "Speakers (Realtek Audio)", noTrim, false},
+		{"spotify.exe(+10)", "spotify.exe(+10)", noTrim, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.target, func(t *testing.T) {
+			name, trim, trimmed := splitTargetTrim(test.target)
+
+			if name != test.name || trimmed != test.trimmed || !volumesEqual(trim.offset, test.trim.offset) || !volumesEqual(trim.gain, test.trim.gain) {
+				t.Errorf("expected (%q, %+v, %v), got (%q, %+v, %v)", test.name, test.trim, test.trimmed, name, trim, trimmed)
+			}
+		})
+	}
+}
+
+func TestProfileSliderMapping(t *testing.T) {
+	cc := newTestConfig(t, `
+slider_mapping:
+  0: master
+profiles:
+  gaming:
+    slider_mapping:
+      0: game.exe
+`)
+
+	if err := cc.SetActiveProfile("Gaming"); err != nil {
+		t.Fatalf("set active profile: %v", err)
+	}
+
+	if targets, _ := cc.SliderMapping.get(0); !reflect.DeepEqual(targets, []string{"game.exe"}) {
+		t.Errorf("expected the profile's mapping, got %v", targets)
+	}
+
+	if err := cc.SetActiveProfile("streaming"); err == nil {
+		t.Error("expected an error switching to a profile that doesn't exist")
+	}
+
+	if err := cc.SetActiveProfile(""); err != nil {
+		t.Fatalf("set default profile: %v", err)
+	}
+
+	if targets, _ := cc.SliderMapping.get(0); !reflect.DeepEqual(targets, []string{"master"}) {
+		t.Errorf("expected the default mapping, got %v", targets)
+	}
+}
+
+func TestVolumeSettingsChanged(t *testing.T) {
+	read := func(configYAML string) *viper.Viper {
+		config := newUserConfigViper()
+		if err := config.ReadConfig(strings.NewReader(configYAML)); err != nil {
+			t.Fatalf("read config: %v", err)
+		}
+
+		return config
+	}
+
+	previous := read("slider_mapping:\n  0: master\nweb_theme: light\n")
+
+	if volumeSettingsChanged(previous, read("slider_mapping:\n  0: master\nweb_theme: dark\n")) {
+		t.Error("expected a theme change to leave volumes alone")
+	}
+
+	if !volumeSettingsChanged(previous, read("slider_mapping:\n  0: master\n  1: discord.exe\nweb_theme: light\n")) {
+		t.Error("expected a mapping change to change volumes")
+	}
+
+	if !volumeSettingsChanged(previous, read("slider_mapping:\n  0: master\ninvert_sliders: true\nweb_theme: light\n")) {
+		t.Error("expected inverting the sliders to change volumes")
+	}
+}
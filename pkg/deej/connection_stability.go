@@ -0,0 +1,140 @@
+package deej
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// a bad cable or a board that keeps resetting makes the connection drop and come back over and over. each time
+// around is a disconnect, a reconnect and possibly a notification about the board, so connectionStability keeps
+// track of how often it happens, lets the user know once, and holds back the notifications that would otherwise
+// pop up on every cycle. the status page shows the details
+type connectionStability struct {
+	logger   *zap.SugaredLogger
+	notifier Notifier
+
+	// unexpected disconnects within connectionUnstableWindow, oldest first
+	recent []time.Time
+	last   time.Time
+	total  uint64
+
+	// set once recent reaches connectionUnstableThreshold, until a window passes without any
+	unstable bool
+
+	// when each connection-related notification was last shown, and how many were held back since deej started
+	notified   map[string]time.Time
+	suppressed uint64
+
+	lock sync.Mutex
+}
+
+// ConnectionStability sums up how often the device connection dropped, for the status page
+type ConnectionStability struct {
+	Unstable          bool       `json:"unstable"`
+	RecentDisconnects int        `json:"recentDisconnects"`
+	WindowMinutes     int        `json:"windowMinutes"`
+	TotalDisconnects  uint64     `json:"totalDisconnects"`
+	LastDisconnect    *time.Time `json:"lastDisconnect,omitempty"`
+
+	// notifications held back because they were already shown recently, or the connection is unstable
+	SuppressedNotifications uint64 `json:"suppressedNotifications"`
+}
+
+const (
+	// this many disconnects within the window make a connection unstable
+	connectionUnstableWindow    = 5 * time.Minute
+	connectionUnstableThreshold = 3
+
+	// the same connection-related notification isn't shown again for this long
+	connectionNotifyCooldown = 10 * time.Minute
+)
+
+func newConnectionStability(logger *zap.SugaredLogger, notifier Notifier) *connectionStability {
+	return &connectionStability{
+		logger:   logger.Named("stability"),
+		notifier: notifier,
+		notified: map[string]time.Time{},
+	}
+}
+
+// recordDisconnect counts a disconnect nobody asked for, and lets the user know the first time they pile up
+func (cs *connectionStability) recordDisconnect(portName string) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	now := time.Now()
+	cs.prune(now)
+
+	cs.recent = append(cs.recent, now)
+	cs.last = now
+	cs.total++
+
+	if cs.unstable || len(cs.recent) < connectionUnstableThreshold {
+		return
+	}
+
+	cs.unstable = true
+	cs.logger.Warnw("Device connection is unstable", "port", portName, "disconnects", len(cs.recent), "within", connectionUnstableWindow)
+
+	go cs.notifier.Notify(tr("notify.connection_unstable.title", portName),
+		tr("notify.connection_unstable.message", strconv.Itoa(len(cs.recent)), strconv.Itoa(int(connectionUnstableWindow.Minutes()))))
+}
+
+// notify shows a notification that comes with (re)connecting, unless the same one was shown recently or the
+// connection is unstable, in which case the user already knows why they keep seeing it
+func (cs *connectionStability) notify(key string, title string, message string) {
+	cs.lock.Lock()
+
+	now := time.Now()
+	cs.prune(now)
+
+	if last, ok := cs.notified[key]; cs.unstable || (ok && now.Sub(last) < connectionNotifyCooldown) {
+		cs.suppressed++
+		cs.logger.Debugw("Holding back repeated connection notification", "key", key, "unstable", cs.unstable)
+		cs.lock.Unlock()
+		return
+	}
+
+	cs.notified[key] = now
+	cs.lock.Unlock()
+
+	cs.notifier.Notify(title, message)
+}
+
+// prune forgets disconnects that fell out of the window, and calms down once there are none left. callers must
+// hold the lock
+func (cs *connectionStability) prune(now time.Time) {
+	for len(cs.recent) > 0 && now.Sub(cs.recent[0]) >= connectionUnstableWindow {
+		cs.recent = cs.recent[1:]
+	}
+
+	if cs.unstable && len(cs.recent) == 0 {
+		cs.unstable = false
+		cs.logger.Info("Device connection is stable again")
+	}
+}
+
+func (cs *connectionStability) snapshot() ConnectionStability {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	cs.prune(time.Now())
+
+	snapshot := ConnectionStability{
+		Unstable:                cs.unstable,
+		RecentDisconnects:       len(cs.recent),
+		WindowMinutes:           int(connectionUnstableWindow.Minutes()),
+		TotalDisconnects:        cs.total,
+		SuppressedNotifications: cs.suppressed,
+	}
+
+	if !cs.last.IsZero() {
+		last := cs.last
+		snapshot.LastDisconnect = &last
+	}
+
+	return snapshot
+}
@@ -0,0 +1,60 @@
+package deej
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// titleNotifier passes on the titles of the notifications it's asked to show
+type titleNotifier chan string
+
+func (tn titleNotifier) Notify(title string, message string) {
+	tn <- title
+}
+
+func TestConnectionStabilityCoalescesNotifications(t *testing.T) {
+	notifications := make(titleNotifier, 10)
+	cs := newConnectionStability(zap.NewNop().Sugar(), notifications)
+
+	cs.notify("incompatible_firmware", "firmware", "")
+	cs.notify("incompatible_firmware", "firmware", "")
+
+	for idx := 0; idx < connectionUnstableThreshold*3; idx++ {
+		cs.recordDisconnect("COM4")
+		cs.notify("other", "other", "")
+	}
+
+	// each one once - the first "other" came before the connection turned unstable, and the unstable
+	// notification is shown in the background
+	expected := map[string]bool{"firmware": true, "other": true, tr("notify.connection_unstable.title", "COM4"): true}
+
+	deadline := time.After(time.Second)
+	for len(expected) > 0 {
+		select {
+		case got := <-notifications:
+			if !expected[got] {
+				t.Errorf("unexpected notification %q", got)
+			}
+			delete(expected, got)
+		case <-deadline:
+			t.Fatalf("timed out waiting for notifications %v", expected)
+		}
+	}
+
+	select {
+	case got := <-notifications:
+		t.Errorf("expected no more notifications, got %q", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	snapshot := cs.snapshot()
+	if !snapshot.Unstable || snapshot.RecentDisconnects != connectionUnstableThreshold*3 || snapshot.LastDisconnect == nil {
+		t.Errorf("expected an unstable connection with %d recent disconnects, got %+v", connectionUnstableThreshold*3, snapshot)
+	}
+
+	if snapshot.SuppressedNotifications != connectionUnstableThreshold*3 {
+		t.Errorf("expected %d suppressed notifications, got %d", connectionUnstableThreshold*3, snapshot.SuppressedNotifications)
+	}
+}
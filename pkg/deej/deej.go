@@ -3,11 +3,14 @@
 package deej
 
 import (
-	"errors"
 	"fmt"
+	"net"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"fyne.io/systray"
 	"go.uber.org/zap"
 
 	"github.com/omriharel/deej/pkg/deej/util"
@@ -17,24 +20,73 @@ const (
 
 	// when this is set to anything, deej won't use a tray icon
 	envNoTray = "DEEJ_NO_TRAY_ICON"
+
+	// how long to wait after the system resumes before renewing connections
+	resumeSettleDelay = 2 * time.Second
 )
 
 // Deej is the main entity managing access to all sub-components
 type Deej struct {
-	logger   *zap.SugaredLogger
-	notifier Notifier
-	config   *CanonicalConfig
-	serial   *SerialIO
-	sessions *sessionMap
+	logger      *zap.SugaredLogger
+	notifier    Notifier
+	config      *CanonicalConfig
+	serial      *SerialIO
+	sessions    *sessionMap
+	permissions *SerialPermissionsHelper
+	faders      *faderSync
+	gestures    *gestureRecognizer
+	hotkeys     *hotkeyManager
+	scheduler   *scheduler
+
+	lockPolicies *lockPolicies
+	updates      *updateChecker
+	diagnostics  *targetDiagnostics
+	timeline     *eventTimeline
+	nowPlaying   *nowPlayingTracker
+	idle         *idleDimmer
+	mpris        mprisWatcher
+
+	// the tray's device info, next scheduled actions, autostart and verbose checkboxes and update entry,
+	// nil until the tray is ready
+	deviceMenuItem    *systray.MenuItem
+	scheduleMenuItem  *systray.MenuItem
+	autostartMenuItem *systray.MenuItem
+	verboseMenuItem   *systray.MenuItem
+	updateMenuItem    *systray.MenuItem
+
+	// the tray and the web UI can both change the autostart entry, see setAutostart
+	autostartLock sync.Mutex
+
+	// set (atomically) while the audio server can't be reached, see setAudioServerAvailable
+	audioServerUnavailable int32
+
+	// held for as long as deej runs, see acquireInstanceLock
+	instanceListener net.Listener
+
+	webConfig     *WebConfigServer
+	webConfigLock sync.Mutex
+
+	// set when deej had to create its config file, to walk the user through setup
+	firstRun bool
+
+	// set when recording a slider trace, or replaying one instead of connecting to a device
+	trace           *sliderTrace
+	replayTracePath string
+
+	// set when measuring how long slider moves take to apply
+	latency *latencyRecorder
 
 	stopChannel chan bool
 	version     string
-	verbose     bool
+
+	// set (atomically) in verbose mode, see SetVerbose
+	verbose int32
 }
 
 // NewDeej creates a Deej instance
 func NewDeej(logger *zap.SugaredLogger, verbose bool) (*Deej, error) {
 	logger = logger.Named("deej")
+	audioCalls.setLogger(logger)
 
 	notifier, err := NewToastNotifier(logger)
 	if err != nil {
@@ -53,9 +105,15 @@ func NewDeej(logger *zap.SugaredLogger, verbose bool) (*Deej, error) {
 		notifier:    notifier,
 		config:      config,
 		stopChannel: make(chan bool),
-		verbose:     verbose,
+		timeline:    newEventTimeline(),
+	}
+
+	if verbose {
+		d.SetVerbose(true)
 	}
 
+	d.permissions = newSerialPermissionsHelper(d, logger)
+
 	serial, err := NewSerialIO(d, logger)
 	if err != nil {
 		logger.Errorw("Failed to create SerialIO", "error", err)
@@ -64,19 +122,24 @@ func NewDeej(logger *zap.SugaredLogger, verbose bool) (*Deej, error) {
 
 	d.serial = serial
 
-	sessionFinder, err := newSessionFinder(logger)
-	if err != nil {
-		logger.Errorw("Failed to create SessionFinder", "error", err)
-		return nil, fmt.Errorf("create new SessionFinder: %w", err)
-	}
-
-	sessions, err := newSessionMap(d, logger, sessionFinder)
+	// the session finder depends on the configured audio backend, so the session map creates it once the config is loaded
+	sessions, err := newSessionMap(d, logger, nil)
 	if err != nil {
 		logger.Errorw("Failed to create sessionMap", "error", err)
 		return nil, fmt.Errorf("create new sessionMap: %w", err)
 	}
 
 	d.sessions = sessions
+	d.faders = newFaderSync(d, logger)
+	d.gestures = newGestureRecognizer(d, logger)
+	d.hotkeys = newHotkeyManager(d, logger)
+	d.scheduler = newScheduler(d, logger)
+	d.lockPolicies = newLockPolicies(d, logger)
+	d.updates = newUpdateChecker(d, logger)
+	d.diagnostics = newTargetDiagnostics(d, logger)
+	d.mpris = newMPRISWatcher(logger)
+	d.nowPlaying = newNowPlayingTracker(d, logger)
+	d.idle = newIdleDimmer(d, logger)
 
 	logger.Debug("Created deej instance")
 
@@ -87,18 +150,64 @@ func NewDeej(logger *zap.SugaredLogger, verbose bool) (*Deej, error) {
 func (d *Deej) Initialize() error {
 	d.logger.Debug("Initializing")
 
+	// a second instance would only fight this one over the serial port, so hand over to the running one instead
+	if err := d.acquireInstanceLock(); err != nil {
+		return err
+	}
+
+	// windows users get a starter config and the setup page, instead of having to write yaml by hand
+	if !util.Linux() && !util.FileExists(userConfigFilepath) && !util.FileExists(systemConfigFilepath()) {
+		if err := d.config.WriteStarterConfig(); err == nil {
+			d.firstRun = true
+		}
+	}
+
 	// load the config for the first time
 	if err := d.config.Load(); err != nil {
 		d.logger.Errorw("Failed to load config during initialization", "error", err)
 		return fmt.Errorf("load config during init: %w", err)
 	}
 
+	// before anything watches the config file, since this can move it. a config deej just wrote is fine where it is
+	if !d.firstRun {
+		d.config.completeConfigMigration()
+	}
+
 	// initialize the session map
 	if err := d.sessions.initialize(); err != nil {
 		d.logger.Errorw("Failed to initialize session map", "error", err)
+		d.notifyError(err)
+
 		return fmt.Errorf("init session map: %w", err)
 	}
 
+	// keep motorized faders in sync with volume changes made elsewhere
+	d.faders.initialize()
+
+	// turn slider gestures into actions
+	d.gestures.initialize()
+
+	// the same actions, from the keyboard
+	d.hotkeys.initialize()
+
+	// and at set times
+	d.scheduler.initialize()
+
+	// follow the screen locking and the system sleeping
+	d.lockPolicies.initialize()
+
+	// look for new releases every now and then
+	d.updates.initialize()
+
+	// point out mapped targets that never match anything, they're usually typos
+	d.diagnostics.initialize()
+
+	// tell the web UI and the board's display what's playing on each slider
+	d.nowPlaying.initialize()
+
+	// dim the board's display and LEDs while nobody's touching it
+	d.idle.initialize()
+
 	d.logger.Debug("About to check for tray mode")
 
 	// decide whether to run with/without tray
@@ -125,14 +234,80 @@ func (d *Deej) SetVersion(version string) {
 	d.version = version
 }
 
+// SetRelease tells deej which release it is (its tag, i.e. "v0.9.10") and the base64 ed25519 public key that
+// release assets are signed with, so it can look for and install updates. dev builds have neither
+func (d *Deej) SetRelease(tag string, updatePublicKey string) {
+	d.updates.setRelease(tag, updatePublicKey)
+}
+
+// SetDumpSerial causes deej to log every line sent to or received from the device, along with what it made of it
+func (d *Deej) SetDumpSerial(dump bool) {
+	d.serial.inspector.dump = dump
+}
+
+// SetRecordTrace causes deej to record incoming slider data and the volumes it applies to the given file,
+// so the session can be replayed later with SetReplayTrace
+func (d *Deej) SetRecordTrace(path string) error {
+	trace, err := newSliderTrace(d.logger, path)
+	if err != nil {
+		return fmt.Errorf("start recording trace: %w", err)
+	}
+
+	d.trace = trace
+	return nil
+}
+
+// SetMeasureLatency causes deej to measure the time from reading a line from the device to the audio backend
+// applying the volume change, logging percentiles as it goes and serving them on the web UI's /metrics
+func (d *Deej) SetMeasureLatency(measure bool) {
+	if measure {
+		d.latency = newLatencyRecorder(d.logger)
+	}
+}
+
+// SetReplayTrace causes deej to replay a recorded slider trace instead of connecting to a device
+func (d *Deej) SetReplayTrace(path string) {
+	d.replayTracePath = path
+}
+
 // Verbose returns a boolean indicating whether deej is running in verbose mode
 func (d *Deej) Verbose() bool {
-	return d.verbose
+	return atomic.LoadInt32(&d.verbose) == 1
+}
+
+// SetVerbose turns verbose mode on or off while deej runs, so detailed logs of a problem can be captured without
+// restarting (and losing the problem). it also keeps the tray's checkbox in sync
+func (d *Deej) SetVerbose(verbose bool) {
+	if verbose {
+		atomic.StoreInt32(&d.verbose, 1)
+		logLevel.SetLevel(zap.DebugLevel)
+	} else {
+		atomic.StoreInt32(&d.verbose, 0)
+		logLevel.SetLevel(baseLogLevel)
+	}
+
+	d.logger.Infow("Changed verbose mode", "verbose", verbose)
+
+	if d.verboseMenuItem != nil {
+		if verbose {
+			d.verboseMenuItem.Check()
+		} else {
+			d.verboseMenuItem.Uncheck()
+		}
+	}
 }
 
 func (d *Deej) setupInterruptHandler() {
 	interruptChannel := util.SetupCloseHandler()
 
+	// lets verbose mode be toggled from a terminal, where there's no tray to click
+	verboseChannel := util.SetupVerboseToggleHandler()
+	go func() {
+		for range verboseChannel {
+			d.SetVerbose(!d.Verbose())
+		}
+	}()
+
 	go func() {
 		signal := <-interruptChannel
 		d.logger.Debugw("Interrupted", "signal", signal)
@@ -146,68 +321,30 @@ func (d *Deej) run() {
 	// watch the config file for changes
 	go d.config.WatchConfigFileChanges()
 
-	// connect to the arduino for the first time with retry logic
-	go func() {
-		// Try initial connection with retries
-		maxRetries := 5
-		retryDelay := 2 * time.Second
-
-		for attempt := 1; attempt <= maxRetries; attempt++ {
-			d.logger.Infow("Attempting initial Arduino connection", "attempt", attempt, "maxRetries", maxRetries)
-
-			if err := d.serial.Start(); err == nil {
-				d.logger.Info("Initial Arduino connection successful")
-				return
-			} else {
-				d.logger.Warnw("Failed to start first-time serial connection", "attempt", attempt, "error", err)
-
-				// If the port is busy, that's because something else is connected - notify and quit
-				if errors.Is(err, os.ErrPermission) {
-					d.logger.Warnw("Serial port seems busy, notifying user and closing",
-						"comPort", d.config.ConnectionInfo.COMPort)
-
-					d.notifier.Notify(fmt.Sprintf("Can't connect to %s!", d.config.ConnectionInfo.COMPort),
-						"This serial port is busy, make sure to close any serial monitor or other deej instance.")
-
-					d.signalStop()
-					return
-
-					// also notify if the COM port they gave isn't found, maybe their config is wrong
-				} else if errors.Is(err, os.ErrNotExist) {
-					d.logger.Warnw("Provided COM port seems wrong, notifying user and closing",
-						"comPort", d.config.ConnectionInfo.COMPort)
+	// replaying a trace stands in for the device entirely
+	if d.replayTracePath != "" {
+		go d.replayTrace()
+	} else {
 
-					d.notifier.Notify(fmt.Sprintf("Can't connect to %s!", d.config.ConnectionInfo.COMPort),
-						"This serial port doesn't exist, check your configuration and make sure it's set correctly.")
+		// connect to the arduino whenever it gets plugged in (and disconnect when it's removed)
+		go d.serial.WatchForDevices()
 
-					d.signalStop()
-					return
-				}
+		// connect to the arduino for the first time with retry logic
+		go d.connectInitially()
+	}
 
-				// For other errors, retry after delay
-				if attempt < maxRetries {
-					d.logger.Infow("Retrying initial connection", "attempt", attempt+1, "delay", retryDelay)
-					time.Sleep(retryDelay)
-				}
-			}
+	// a web UI that's served to the network is there for other devices, so it runs whether it's open here or not
+	if d.webConfigExposed() {
+		if _, err := d.startWebConfig(); err != nil {
+			d.logger.Warnw("Failed to start web config server for the network", "error", err)
 		}
+	}
 
-		// If we get here, all retries failed
-		d.logger.Error("All initial connection attempts failed, starting reconnection loop")
-
-		// Start the reconnection loop for ongoing attempts
-		go func() {
-			for {
-				time.Sleep(5 * time.Second)
-				if err := d.serial.Start(); err == nil {
-					d.logger.Info("Successfully connected to Arduino after initial failures")
-					return
-				} else {
-					d.logger.Debugw("Reconnection attempt failed", "error", err)
-				}
-			}
-		}()
-	}()
+	if d.firstRun {
+		go d.openSetup(tr("notify.welcome.title"), tr("notify.welcome.message"))
+	} else {
+		go d.config.offerConfigMigration(newDialogBackend(d.config.PermissionDialog, d.notifier, d.logger))
+	}
 
 	// wait until stopped (gracefully)
 	<-d.stopChannel
@@ -222,6 +359,116 @@ func (d *Deej) run() {
 	}
 }
 
+// recoverFromSleep re-establishes the serial and audio connections, either of which can go stale across sleep
+// without an error until the next time they're used, and puts every session back where its slider is
+func (d *Deej) recoverFromSleep() {
+
+	// usb devices and audio servers take a moment to come back
+	<-time.After(resumeSettleDelay)
+
+	d.logger.Info("Recovering from sleep")
+
+	d.serial.reconnect()
+
+	// the old connection might still be fine, in which case at least re-acquire everything
+	if err := d.sessions.renewSessionFinder(); err != nil {
+		d.sessions.refreshSessions(true)
+	}
+
+	// audio stacks often reset volumes across sleep, and the board might have missed moves while asleep
+	d.serial.resyncSliders()
+}
+
+// waitingPassively returns true while passive mode keeps quiet about the board not being there,
+// i.e. until one has connected for the first time
+func (d *Deej) waitingPassively() bool {
+	return d.config.PassiveMode && atomic.LoadInt32(&d.serial.deviceSeen) == 0
+}
+
+// connectInitially tries to connect to the arduino a few times, unless the hotplug watcher beats it to it
+func (d *Deej) connectInitially() {
+	// Try initial connection with retries
+	maxRetries := 5
+	retryDelay := 2 * time.Second
+
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+
+		// the hotplug watcher might have beaten us to it
+		if d.serial.isConnected() {
+			return
+		}
+
+		d.logger.Infow("Attempting initial Arduino connection", "attempt", attempt, "maxRetries", maxRetries)
+
+		if err := d.serial.Start(); err == nil {
+			d.logger.Info("Initial Arduino connection successful")
+			return
+		} else {
+			d.logger.Warnw("Failed to start first-time serial connection", "attempt", attempt, "error", err)
+
+			// the hotplug watcher connects once the board gets plugged in, there's nothing to retry or tell anyone
+			if d.waitingPassively() {
+				d.logger.Info("Passive mode enabled, waiting for the Arduino to be plugged in")
+				return
+			}
+
+			code, _ := errorCodeOf(err)
+
+			// the permissions helper already let the user know how to fix this one, wait for them to do it
+			if d.permissions.hasProblem(d.config.ConnectionInfo.COMPort) {
+				d.logger.Infow("Serial port permission problem reported, waiting for it to be resolved",
+					"comPort", d.config.ConnectionInfo.COMPort)
+
+				return
+
+				// If the port is busy, that's because something else is connected - notify and quit
+			} else if code == errorCodePortBusy {
+				d.logger.Warnw("Serial port seems busy, notifying user and closing",
+					"comPort", d.config.ConnectionInfo.COMPort)
+
+				d.notifyError(err)
+				d.signalStop()
+				return
+
+				// on windows, help them pick the right one - there's no other way to find out which it is
+			} else if code == errorCodePortNotFound && !util.Linux() {
+				d.logger.Warnw("Provided COM port seems wrong, opening setup page",
+					"comPort", d.config.ConnectionInfo.COMPort)
+
+				d.openSetup(tr("notify.port_missing_setup.title", d.config.ConnectionInfo.COMPort),
+					tr("notify.port_missing_setup.message"))
+
+				return
+
+				// also notify if the COM port they gave isn't found, maybe their config is wrong
+			} else if code == errorCodePortNotFound {
+				d.logger.Warnw("Provided COM port seems wrong, notifying user and closing",
+					"comPort", d.config.ConnectionInfo.COMPort)
+
+				d.notifyError(err)
+				d.signalStop()
+				return
+			}
+
+			// For other errors, retry after delay
+			if attempt < maxRetries {
+				d.logger.Infow("Retrying initial connection", "attempt", attempt+1, "delay", retryDelay)
+				time.Sleep(retryDelay)
+			}
+		}
+	}
+
+	// If we get here, all retries failed - the hotplug watcher will connect once the device shows up
+	d.logger.Warn("All initial connection attempts failed, waiting for the Arduino to be plugged in")
+}
+
+func (d *Deej) replayTrace() {
+	if err := d.serial.replayTrace(d.replayTracePath); err != nil {
+		d.logger.Warnw("Failed to replay slider trace", "path", d.replayTracePath, "error", err)
+		d.notifier.Notify(tr("notify.trace_replay_failed.title"), fmt.Sprintf("%s: %v", d.replayTracePath, err))
+	}
+}
+
 func (d *Deej) signalStop() {
 	d.logger.Debug("Signalling stop channel")
 	d.stopChannel <- true
@@ -231,7 +478,16 @@ func (d *Deej) stop() error {
 	d.logger.Info("Stopping")
 
 	d.config.StopWatchingConfigFile()
+	d.serial.StopWatchingForDevices()
 	d.serial.Stop()
+	d.hotkeys.release()
+	d.scheduler.release()
+	d.lockPolicies.release()
+	d.mpris.Close()
+	d.trace.close()
+	d.latency.logSummary()
+	d.stopWebConfig()
+	d.releaseInstanceLock()
 
 	// release the session map
 	if err := d.sessions.release(); err != nil {
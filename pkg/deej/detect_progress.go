@@ -0,0 +1,126 @@
+package deej
+
+import (
+	"sync"
+)
+
+// auto-detection tries every serial port at every common baud rate, and each try takes a second or more. so that
+// isn't mistaken for deej hanging, how far it got is shown in the tray's tooltip and the web UI while it runs, and
+// what each port came to once it gives up
+
+// DetectionProgress is how far the last auto-detection got looking for the board
+type DetectionProgress struct {
+
+	// true while ports are still being probed
+	Active bool `json:"active"`
+
+	// the port and baud rate being probed right now, and how many probes came before
+	Port     string `json:"port,omitempty"`
+	BaudRate uint   `json:"baudRate,omitempty"`
+	Probes   int    `json:"probes"`
+
+	// what each probe so far came to
+	Results []PortProbeResult `json:"results"`
+}
+
+// PortProbeResult is what probing a single port at a single baud rate came to
+type PortProbeResult struct {
+	Port     string `json:"port"`
+	BaudRate uint   `json:"baudRate"`
+	Outcome  string `json:"outcome"`
+}
+
+const (
+	probeOutcomeFound       = "found"
+	probeOutcomeNoAnswer    = "no_answer"
+	probeOutcomeDenied      = "denied"
+	probeOutcomeUnavailable = "unavailable"
+	probeOutcomeSkipped     = "skipped"
+)
+
+// detectionTracker keeps the progress of the running (or last failed) auto-detection
+type detectionTracker struct {
+	progress *DetectionProgress
+	lock     sync.Mutex
+
+	// called whenever the progress changes
+	onChange func()
+}
+
+func newDetectionTracker(onChange func()) *detectionTracker {
+	return &detectionTracker{onChange: onChange}
+}
+
+// start begins tracking a new auto-detection, forgetting the last one
+func (dt *detectionTracker) start() {
+	dt.update(func() {
+		dt.progress = &DetectionProgress{Active: true, Results: []PortProbeResult{}}
+	})
+}
+
+// probing records that the given port is being tried at the given baud rate
+func (dt *detectionTracker) probing(port string, baudRate uint) {
+	dt.update(func() {
+		dt.progress.Port = port
+		dt.progress.BaudRate = baudRate
+	})
+}
+
+// result records what probing a port came to
+func (dt *detectionTracker) result(port string, baudRate uint, outcome string) {
+	dt.update(func() {
+		dt.progress.Probes++
+		dt.progress.Results = append(dt.progress.Results, PortProbeResult{Port: port, BaudRate: baudRate, Outcome: outcome})
+	})
+}
+
+// finish ends the auto-detection. a board that was found needs no explaining, so only a failed one is kept
+func (dt *detectionTracker) finish(found bool) {
+	dt.update(func() {
+		if found {
+			dt.progress = nil
+			return
+		}
+
+		dt.progress.Active = false
+		dt.progress.Port = ""
+		dt.progress.BaudRate = 0
+	})
+}
+
+func (dt *detectionTracker) update(change func()) {
+	dt.lock.Lock()
+	change()
+	dt.lock.Unlock()
+
+	if dt.onChange != nil {
+		dt.onChange()
+	}
+}
+
+// snapshot returns a copy of the auto-detection's progress, or nil if there's nothing to tell
+func (dt *detectionTracker) snapshot() *DetectionProgress {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+
+	if dt.progress == nil {
+		return nil
+	}
+
+	progress := *dt.progress
+	progress.Results = append([]PortProbeResult{}, dt.progress.Results...)
+
+	return &progress
+}
+
+// probedPorts returns how many different ports the auto-detection tried, leaving out the ones it skipped
+func (progress *DetectionProgress) probedPorts() int {
+	ports := map[string]bool{}
+	for _, result := range progress.Results {
+		if result.Outcome != probeOutcomeSkipped {
+			ports[result.Port] = true
+		}
+	}
+
+	return len(ports)
+}
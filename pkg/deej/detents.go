@@ -0,0 +1,114 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// detents make a slider snap to set positions when it's close to them, like the notches some faders have: with
+// detents at 0, 25, 50, 75 and 100, a slider anywhere from 48% to 52% counts as being at 50%. "center" puts a single,
+// wider detent in the middle, for balance sliders (i.e. crossfades, see slider_modes.go) to land on even
+const (
+	detentsCenter = "center"
+
+	// how close to a detent, in percent, a slider has to be to snap to it
+	defaultDetentSnapRange = 2
+	centerDetentSnapRange  = 5
+	maxDetentSnapRange     = 20
+
+	// a snapped slider stays on its detent until it's moved this many times the snap range away, so that a slider
+	// resting at the edge of the range doesn't keep snapping on and off with the noise
+	detentReleaseFactor = 2
+
+	// no detent holds the slider
+	noDetent = -1
+)
+
+var errInvalidDetents = errors.New("invalid detents")
+
+// sliderDetents are the positions a slider snaps to, in whole percents
+type sliderDetents struct {
+	positions []int
+	snapRange int
+}
+
+// parseSliderDetents parses a slider's detents as written in the config: "center", or percents like
+// [0, 25, 50, 75, 100] (or the same, comma separated). a snapRange of 0 picks the default for the detents
+func parseSliderDetents(values []string, snapRange int) (sliderDetents, error) {
+	fields := strings.FieldsFunc(strings.Join(values, ","), func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+
+	detents := sliderDetents{snapRange: snapRange}
+
+	if len(fields) == 1 && strings.ToLower(fields[0]) == detentsCenter {
+		detents.positions = []int{50}
+
+		if detents.snapRange == 0 {
+			detents.snapRange = centerDetentSnapRange
+		}
+	} else {
+		for _, field := range fields {
+			position, err := strconv.Atoi(strings.TrimSuffix(field, "%"))
+			if err != nil || position < 0 || position > 100 {
+				return sliderDetents{}, fmt.Errorf("%q: expected percents from 0 to 100, or %q: %w", field, detentsCenter,
+					errInvalidDetents)
+			}
+
+			detents.positions = append(detents.positions, position)
+		}
+
+		if detents.snapRange == 0 {
+			detents.snapRange = defaultDetentSnapRange
+		}
+	}
+
+	if len(detents.positions) == 0 {
+		return sliderDetents{}, fmt.Errorf("no positions: %w", errInvalidDetents)
+	}
+
+	if detents.snapRange < 0 || detents.snapRange > maxDetentSnapRange {
+		return sliderDetents{}, fmt.Errorf("snap range %d: expected 1 to %d percent: %w", detents.snapRange,
+			maxDetentSnapRange, errInvalidDetents)
+	}
+
+	return detents, nil
+}
+
+// snap returns where a slider at the given value counts as being, along with the detent now holding it (or
+// noDetent). held is the detent that held the slider until now
+func (sd sliderDetents) snap(value float32, held int) (float32, int) {
+	percent := int(math.Round(float64(value) * 100))
+
+	if held != noDetent && sd.has(held) && percentDistance(percent, held) <= sd.snapRange*detentReleaseFactor {
+		return float32(held) / 100, held
+	}
+
+	for _, position := range sd.positions {
+		if percentDistance(percent, position) <= sd.snapRange {
+			return float32(position) / 100, position
+		}
+	}
+
+	return value, noDetent
+}
+
+func (sd sliderDetents) has(position int) bool {
+	for _, candidate := range sd.positions {
+		if candidate == position {
+			return true
+		}
+	}
+
+	return false
+}
+
+func percentDistance(a int, b int) int {
+	if a < b {
+		return b - a
+	}
+
+	return a - b
+}
@@ -0,0 +1,36 @@
+package deej
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseSliderDetents(t *testing.T) {
+	tests := []struct {
+		values    []string
+		snapRange int
+		want      sliderDetents
+	}{
+		{[]string{"0", "25", "50", "75", "100"}, 0, sliderDetents{positions: []int{0, 25, 50, 75, 100}, snapRange: 2}},
+		{[]string{"0,", "50%,", "100"}, 3, sliderDetents{positions: []int{0, 50, 100}, snapRange: 3}},
+		{[]string{"Center"}, 0, sliderDetents{positions: []int{50}, snapRange: 5}},
+	}
+
+	for _, test := range tests {
+		detents, err := parseSliderDetents(test.values, test.snapRange)
+		if err != nil || !reflect.DeepEqual(detents, test.want) {
+			t.Errorf("%v: expected %+v, got %+v (%v)", test.values, test.want, detents, err)
+		}
+	}
+
+	for _, values := range [][]string{{}, {"middle"}, {"50", "150"}} {
+		if _, err := parseSliderDetents(values, 0); !errors.Is(err, errInvalidDetents) {
+			t.Errorf("%v: expected invalid detents, got %v", values, err)
+		}
+	}
+
+	if _, err := parseSliderDetents([]string{"50"}, 50); !errors.Is(err, errInvalidDetents) {
+		t.Errorf("expected a snap range of 50 to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// dialogBackend shows blocking questions and messages to the user outside of the tray and web UI
+type dialogBackend interface {
+	Name() string
+
+	// Interactive returns false for backends that can't ask the user anything
+	Interactive() bool
+
+	Confirm(title string, message string) (bool, error)
+	Inform(title string, message string) error
+}
+
+const (
+	dialogBackendAuto    = "auto"
+	dialogBackendPortal  = "portal"
+	dialogBackendZenity  = "zenity"
+	dialogBackendKDialog = "kdialog"
+	dialogBackendNotify  = "notify"
+	dialogBackendNone    = "none"
+)
+
+var errDialogNotInteractive = errors.New("dialog backend can't ask questions")
+
+// newDialogBackend picks a dialog backend by name. "auto" prefers the desktop portal, which works under any
+// wayland compositor, then whichever dialog tool is installed, falling back to plain notifications when there are none
+func newDialogBackend(name string, notifier Notifier, logger *zap.SugaredLogger) dialogBackend {
+	logger = logger.Named("dialog")
+
+	switch strings.ToLower(name) {
+	case dialogBackendPortal:
+		portal, err := newPortalDialog(logger)
+		if err == nil {
+			return portal
+		}
+
+		logger.Warnw("Desktop portal unavailable, falling back to dialog tools", "error", err)
+		return autoDialogBackend(notifier, logger)
+	case dialogBackendZenity:
+		return &commandDialog{tool: dialogBackendZenity, logger: logger}
+	case dialogBackendKDialog:
+		return &commandDialog{tool: dialogBackendKDialog, logger: logger}
+	case dialogBackendNotify:
+		return &notifierDialog{notifier: notifier}
+	case dialogBackendNone:
+		return &logDialog{logger: logger}
+	case dialogBackendAuto, "":
+		if portal, err := newPortalDialog(logger); err == nil {
+			return portal
+		}
+
+		return autoDialogBackend(notifier, logger)
+	}
+
+	logger.Warnw("Unknown dialog backend, falling back to notifications", "backend", name)
+
+	return &notifierDialog{notifier: notifier}
+}
+
+// autoDialogBackend returns whichever dialog tool is installed, or plain notifications when there are none
+func autoDialogBackend(notifier Notifier, logger *zap.SugaredLogger) dialogBackend {
+	for _, tool := range []string{dialogBackendZenity, dialogBackendKDialog} {
+		if _, err := exec.LookPath(tool); err == nil {
+			return &commandDialog{tool: tool, logger: logger}
+		}
+	}
+
+	return &notifierDialog{notifier: notifier}
+}
+
+// commandDialog shells out to zenity or kdialog
+type commandDialog struct {
+	tool   string
+	logger *zap.SugaredLogger
+}
+
+func (cd *commandDialog) Name() string {
+	return cd.tool
+}
+
+func (cd *commandDialog) Interactive() bool {
+	return true
+}
+
+func (cd *commandDialog) Confirm(title string, message string) (bool, error) {
+	args := []string{"--question", "--title", title, "--text", message}
+	if cd.tool == dialogBackendKDialog {
+		args = []string{"--title", title, "--yesno", message}
+	}
+
+	err := exec.Command(cd.tool, args...).Run()
+	if err == nil {
+		return true, nil
+	}
+
+	// both tools exit with 1 when the user says no or closes the dialog
+	exitErr := &exec.ExitError{}
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+
+	cd.logger.Warnw("Failed to show question dialog", "tool", cd.tool, "error", err)
+	return false, fmt.Errorf("show %s question: %w", cd.tool, err)
+}
+
+func (cd *commandDialog) Inform(title string, message string) error {
+	args := []string{"--info", "--title", title, "--text", message}
+	if cd.tool == dialogBackendKDialog {
+		args = []string{"--title", title, "--msgbox", message}
+	}
+
+	if err := exec.Command(cd.tool, args...).Run(); err != nil {
+		cd.logger.Warnw("Failed to show message dialog", "tool", cd.tool, "error", err)
+		return fmt.Errorf("show %s message: %w", cd.tool, err)
+	}
+
+	return nil
+}
+
+// notifierDialog can only tell the user things, through regular notifications
+type notifierDialog struct {
+	notifier Notifier
+}
+
+func (nd *notifierDialog) Name() string {
+	return dialogBackendNotify
+}
+
+func (nd *notifierDialog) Interactive() bool {
+	return false
+}
+
+func (nd *notifierDialog) Confirm(title string, message string) (bool, error) {
+	return false, errDialogNotInteractive
+}
+
+func (nd *notifierDialog) Inform(title string, message string) error {
+	nd.notifier.Notify(title, message)
+	return nil
+}
+
+// logDialog is the fully non-interactive backend, for headless setups: everything only goes to the logs
+type logDialog struct {
+	logger *zap.SugaredLogger
+}
+
+func (ld *logDialog) Name() string {
+	return dialogBackendNone
+}
+
+func (ld *logDialog) Interactive() bool {
+	return false
+}
+
+func (ld *logDialog) Confirm(title string, message string) (bool, error) {
+	return false, errDialogNotInteractive
+}
+
+func (ld *logDialog) Inform(title string, message string) error {
+	ld.logger.Infow(title, "message", message)
+	return nil
+}
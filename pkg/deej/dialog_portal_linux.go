@@ -0,0 +1,142 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"go.uber.org/zap"
+)
+
+// zenity and kdialog need an x11 (or xwayland) display, which wlroots compositors don't always have. the desktop
+// portal works on any of them: it has no dialogs of its own, but its notifications can carry buttons, which is
+// enough to ask a yes or no question
+const (
+	portalNotificationInterface = "org.freedesktop.portal.Notification"
+
+	portalDialogActionYes = "yes"
+	portalDialogActionNo  = "no"
+
+	// a question nobody answered in this long counts as a no
+	portalDialogTimeout = 2 * time.Minute
+)
+
+var errPortalUnavailable = errors.New("desktop portal has no notification interface")
+
+// portalDialog shows questions and messages as desktop portal notifications
+type portalDialog struct {
+	logger *zap.SugaredLogger
+}
+
+// newPortalDialog returns a portal dialog backend, or an error if there's no portal to show its notifications
+func newPortalDialog(logger *zap.SugaredLogger) (dialogBackend, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("connect to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	portal := conn.Object(portalBusName, portalObjectPath)
+	if _, err := portal.GetProperty(portalNotificationInterface + ".version"); err != nil {
+		return nil, fmt.Errorf("%w: %v", errPortalUnavailable, err)
+	}
+
+	return &portalDialog{logger: logger}, nil
+}
+
+func (pd *portalDialog) Name() string {
+	return dialogBackendPortal
+}
+
+func (pd *portalDialog) Interactive() bool {
+	return true
+}
+
+func (pd *portalDialog) Confirm(title string, message string) (bool, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return false, fmt.Errorf("connect to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	// subscribe before asking, so a quick answer isn't missed
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchInterface(portalNotificationInterface),
+		dbus.WithMatchMember("ActionInvoked"),
+	); err != nil {
+		return false, fmt.Errorf("subscribe to portal notification actions: %w", err)
+	}
+
+	signals := make(chan *dbus.Signal, 4)
+	conn.Signal(signals)
+
+	id := fmt.Sprintf("deej-question-%d", time.Now().UnixNano())
+	buttons := []map[string]dbus.Variant{
+		{"label": dbus.MakeVariant(tr("dialog.yes")), "action": dbus.MakeVariant(portalDialogActionYes)},
+		{"label": dbus.MakeVariant(tr("dialog.no")), "action": dbus.MakeVariant(portalDialogActionNo)},
+	}
+
+	if err := pd.notify(conn, id, title, message, buttons); err != nil {
+		return false, err
+	}
+
+	timeout := time.After(portalDialogTimeout)
+
+	for {
+		select {
+		case signal, ok := <-signals:
+			if !ok {
+				return false, errors.New("session bus connection closed while waiting for an answer")
+			}
+
+			if signal.Name != portalNotificationInterface+".ActionInvoked" || len(signal.Body) < 2 {
+				continue
+			}
+
+			if answeredID, _ := signal.Body[0].(string); answeredID != id {
+				continue
+			}
+
+			action, _ := signal.Body[1].(string)
+			return action == portalDialogActionYes, nil
+
+		case <-timeout:
+			pd.logger.Infow("Nobody answered question in time, taking it as a no", "title", title)
+			conn.Object(portalBusName, portalObjectPath).Call(portalNotificationInterface+".RemoveNotification", 0, id)
+
+			return false, nil
+		}
+	}
+}
+
+func (pd *portalDialog) Inform(title string, message string) error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("connect to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	return pd.notify(conn, fmt.Sprintf("deej-message-%d", time.Now().UnixNano()), title, message, nil)
+}
+
+// notify shows a portal notification, with buttons if there are any
+func (pd *portalDialog) notify(conn *dbus.Conn, id string, title string, message string, buttons []map[string]dbus.Variant) error {
+	notification := map[string]dbus.Variant{
+		"title":    dbus.MakeVariant(title),
+		"body":     dbus.MakeVariant(message),
+		"priority": dbus.MakeVariant("high"),
+	}
+
+	if len(buttons) > 0 {
+		notification["buttons"] = dbus.MakeVariant(buttons)
+	}
+
+	portal := conn.Object(portalBusName, portalObjectPath)
+	if err := portal.Call(portalNotificationInterface+".AddNotification", 0, id, notification).Err; err != nil {
+		pd.logger.Warnw("Failed to show portal notification", "title", title, "error", err)
+		return fmt.Errorf("show portal notification: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package deej
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+var errPortalUnavailable = errors.New("desktop portals are linux only")
+
+// newPortalDialog always fails, windows has no desktop portal
+func newPortalDialog(logger *zap.SugaredLogger) (dialogBackend, error) {
+	return nil, errPortalUnavailable
+}
@@ -0,0 +1,55 @@
+package deej
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// sessions are matched by their process name, which makes for a stable key but not much of a label ("chrome.exe").
+// where the audio backend or the app itself has a friendlier name ("Google Chrome"), it's shown instead
+
+// displayNamedSession is implemented by sessions that can tell what they'd like to be called
+type displayNamedSession interface {
+
+	// DisplayName returns the session's friendly name, or an empty string if there isn't one
+	DisplayName() string
+}
+
+func (s *baseSession) DisplayName() string {
+	return s.displayName
+}
+
+// sessionDisplayName returns what to call a session: its friendly name if it has one, or its key made presentable
+func sessionDisplayName(session Session) string {
+	if dns, ok := session.(displayNamedSession); ok {
+		if name := dns.DisplayName(); name != "" {
+			return name
+		}
+	}
+
+	return prettyProcessName(session.Key())
+}
+
+// prettyProcessName turns a process name into something that looks like a name, e.g. "Chrome" from "chrome.exe"
+func prettyProcessName(processName string) string {
+	name := strings.TrimSuffix(strings.ToLower(processName), ".exe")
+	name = cases.Title(language.English).String(name)
+
+	return strings.ReplaceAll(name, ".", " ")
+}
+
+// displayName returns the friendly name of a session with the given key, or an empty string if none of them
+// has one
+func (m *sessionMap) displayName(key string) string {
+	sessions, _ := m.get(key)
+
+	for _, session := range sessions {
+		if dns, ok := session.(displayNamedSession); ok && dns.DisplayName() != "" {
+			return dns.DisplayName()
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,481 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/thoas/go-funk"
+	"go.uber.org/zap"
+
+	"github.com/omriharel/deej/pkg/deej/util"
+)
+
+// deej --doctor goes through everything deej needs in order to work, and prints what it found along with what to do
+// about anything that's wrong. it's meant to be the first thing asked for in a support thread, so the report leans
+// towards including details rather than leaving them out
+
+// doctorStatus is the outcome of a single check
+type doctorStatus string
+
+const (
+	doctorPass doctorStatus = "PASS"
+	doctorWarn doctorStatus = "WARN"
+	doctorFail doctorStatus = "FAIL"
+	doctorSkip doctorStatus = "SKIP"
+)
+
+// the most sessions listed by name, the rest are only counted
+const doctorMaxListedSessions = 10
+
+// doctorCheck is what a single check found
+type doctorCheck struct {
+	name    string
+	status  doctorStatus
+	details []string
+
+	// what to do about it, for checks that didn't pass
+	fix string
+}
+
+type doctor struct {
+	logger   *zap.SugaredLogger
+	notifier *ToastNotifier
+
+	// nil if the config couldn't be loaded
+	config *CanonicalConfig
+
+	checks []doctorCheck
+}
+
+// RunDoctor checks deej's setup, prints a report to out and returns whether every check passed (or only warned)
+func RunDoctor(logger *zap.SugaredLogger, out io.Writer) bool {
+	logger = logger.Named("doctor")
+
+	notifier, err := NewToastNotifier(logger)
+	if err != nil {
+		logger.Warnw("Failed to create notifier", "error", err)
+	}
+
+	dr := &doctor{
+		logger:   logger,
+		notifier: notifier,
+	}
+
+	logger.Info("Running self-test")
+
+	dr.checkInstance()
+	dr.checkConfig()
+	dr.checkDevice()
+	dr.checkAudio()
+	dr.checkNotifications()
+
+	dr.report(out)
+
+	return !dr.failed()
+}
+
+func (dr *doctor) add(check doctorCheck) {
+	dr.logger.Infow("Checked", "check", check.name, "status", check.status, "details", check.details)
+	dr.checks = append(dr.checks, check)
+}
+
+// checkInstance looks for a deej that's already running, since whatever port it's connected to will look busy
+func (dr *doctor) checkInstance() {
+	check := doctorCheck{name: "Running instance", status: doctorPass}
+
+	listener, err := net.Listen("tcp", instanceLockAddress)
+	if err != nil {
+		check.status = doctorWarn
+		check.details = append(check.details, "deej (or something else) is already running and holding "+instanceLockAddress)
+		check.fix = "Quit deej from its tray menu and run this again, or the port it's connected to will show up as busy."
+	} else {
+		listener.Close()
+		check.details = append(check.details, "no other deej is running")
+	}
+
+	dr.add(check)
+}
+
+func (dr *doctor) checkConfig() {
+	check := doctorCheck{name: "Config"}
+
+	config, err := NewConfig(dr.logger, dr.notifier)
+	if err == nil {
+		err = dr.loadConfig(config)
+	}
+
+	if err != nil {
+		check.status = doctorFail
+		check.details = append(check.details, err.Error())
+		check.fix = errorMessage(err)
+		dr.add(check)
+
+		return
+	}
+
+	dr.config = config
+
+	check.status = doctorPass
+	if util.FileExists(userConfigFilepath) {
+		check.details = append(check.details, "loaded "+userConfigFilepath)
+	}
+
+	if system := systemConfigFilepath(); system != "" && util.FileExists(system) {
+		check.details = append(check.details, "with defaults from "+system)
+	}
+
+	sliders := 0
+	config.SliderMapping.iterate(func(int, []string) { sliders++ })
+
+	if sliders == 0 {
+		check.status = doctorWarn
+		check.fix = "Map your sliders to something under " + configKeySliderMapping + " in " + userConfigFilepath + "."
+	}
+
+	check.details = append(check.details, fmt.Sprintf("%d mapped slider(s)", sliders))
+
+	dr.add(check)
+}
+
+// loadConfig is CanonicalConfig.Load, without notifying about what's wrong
+func (dr *doctor) loadConfig(config *CanonicalConfig) error {
+	userConfig, err := config.readUserConfig()
+	if err != nil {
+		return err
+	}
+
+	return config.apply(userConfig)
+}
+
+// checkDevice looks for the board the way deej would connect to it
+func (dr *doctor) checkDevice() {
+	if dr.config == nil {
+		dr.add(doctorCheck{name: "Device", status: doctorSkip, details: []string{"needs a working config"}})
+		return
+	}
+
+	if dr.config.ConnectionInfo.Type == connectionTypeHID {
+		dr.checkHIDDevice()
+		return
+	}
+
+	dr.checkSerialPorts()
+}
+
+func (dr *doctor) checkHIDDevice() {
+	info := dr.config.ConnectionInfo
+	check := doctorCheck{name: "HID device"}
+
+	device := fmt.Sprintf("%04x:%04x", info.HIDVendorID, info.HIDProductID)
+
+	conn, path, err := openHIDDevice(info.HIDVendorID, info.HIDProductID)
+	if err != nil {
+		check.status = doctorFail
+		check.details = append(check.details, fmt.Sprintf("couldn't open %s: %v", device, err))
+
+		if errors.Is(err, os.ErrPermission) {
+			check.fix = "deej isn't allowed to open the device. " + dr.permissionFix(path)
+		} else {
+			check.fix = "Make sure the board is plugged in, and that the vendor and product IDs in " + userConfigFilepath + " match it."
+		}
+
+		dr.add(check)
+		return
+	}
+	conn.Close()
+
+	check.status = doctorPass
+	check.details = append(check.details, fmt.Sprintf("found %s at %s", device, path))
+
+	dr.add(check)
+}
+
+// checkSerialPorts lists the serial ports around and probes each for a deej. ports that open but don't answer at the
+// configured baud rate are tried at the other common ones, since a mismatched rate looks just like no deej at all
+func (dr *doctor) checkSerialPorts() {
+	info := dr.config.ConnectionInfo
+	check := doctorCheck{name: "Serial ports"}
+
+	ports, err := listSerialPorts()
+	if err != nil {
+		check.status = doctorFail
+		check.details = append(check.details, fmt.Sprintf("couldn't list serial ports: %v", err))
+		dr.add(check)
+
+		return
+	}
+
+	autoDetect := info.COMPort == "" || strings.ToLower(info.COMPort) == comPortAuto
+	if autoDetect {
+		check.details = append(check.details, fmt.Sprintf("configured: auto-detect at %d baud", info.BaudRate))
+	} else {
+		check.details = append(check.details, fmt.Sprintf("configured: %s at %d baud", info.COMPort, info.BaudRate))
+	}
+
+	if len(ports) == 0 {
+		check.status = doctorFail
+		check.details = append(check.details, "no serial ports found")
+		check.fix = "Make sure the board is plugged in with a data (not charge-only) cable, and that its USB driver is installed."
+		dr.add(check)
+
+		return
+	}
+
+	baudRates := []uint{uint(info.BaudRate)}
+	for _, rate := range commonBaudRates {
+		if rate != uint(info.BaudRate) {
+			baudRates = append(baudRates, rate)
+		}
+	}
+
+	found := map[string]uint{}
+	denied := []string{}
+	fixes := []string{}
+
+	_, skipped := dr.config.ProbeSafety.probeablePorts(ports, dr.logger)
+
+	for _, port := range ports {
+		if funk.ContainsString(skipped, port) {
+			check.details = append(check.details, fmt.Sprintf("%s: not probed, because of %s or %s", port, configKeySkipPorts, configKeySafeProbing))
+			continue
+		}
+
+		for idx, rate := range baudRates {
+			ok, err := probeArduinoPort(port, rate, dr.logger)
+			if err != nil {
+				if errors.Is(err, os.ErrPermission) {
+					denied = append(denied, port)
+				}
+
+				check.details = append(check.details, fmt.Sprintf("%s: couldn't open: %v", port, err))
+				break
+			}
+
+			if ok {
+				found[port] = rate
+				check.details = append(check.details, fmt.Sprintf("%s: deej found at %d baud", port, rate))
+				break
+			}
+
+			if idx == len(baudRates)-1 {
+				check.details = append(check.details, fmt.Sprintf("%s: no deej answered", port))
+			}
+		}
+	}
+
+	for _, port := range denied {
+		fixes = append(fixes, fmt.Sprintf("deej isn't allowed to open %s. %s", port, dr.permissionFix(port)))
+	}
+
+	switch {
+	case len(found) == 0:
+		check.status = doctorFail
+		fixes = append(fixes, "No port answered like a deej. Make sure the board runs deej's firmware, and that nothing else (like the Arduino IDE's serial monitor) has it open.")
+
+	case !autoDetect:
+		rate, ok := found[dr.configuredPort(ports)]
+		switch {
+		case !ok:
+			check.status = doctorFail
+			fixes = append(fixes, fmt.Sprintf("deej is configured to use %s, but found a deej on %s. Set %s to one of those (or to %q) in %s.",
+				info.COMPort, strings.Join(sortedPorts(found), ", "), configKeyCOMPort, comPortAuto, userConfigFilepath))
+		case rate != uint(info.BaudRate):
+			check.status = doctorWarn
+			fixes = append(fixes, fmt.Sprintf("The board answers at %d baud rather than %d. Set %s to %d in %s.",
+				rate, info.BaudRate, configKeyBaudRate, rate, userConfigFilepath))
+		default:
+			check.status = doctorPass
+		}
+
+	default:
+		check.status = doctorPass
+		for _, rate := range found {
+			if rate != uint(info.BaudRate) {
+				check.status = doctorWarn
+				fixes = append(fixes, fmt.Sprintf("The board answers at %d baud rather than %d. deej copes, but setting %s to %d in %s saves it some probing.",
+					rate, info.BaudRate, configKeyBaudRate, rate, userConfigFilepath))
+				break
+			}
+		}
+	}
+
+	if check.status == doctorPass && len(denied) > 0 {
+		check.status = doctorWarn
+	}
+
+	check.fix = strings.Join(fixes, "\n")
+
+	dr.add(check)
+}
+
+// configuredPort returns the configured port as it's listed, since port names aren't always compared case-sensitively
+func (dr *doctor) configuredPort(ports []string) string {
+	for _, port := range ports {
+		if samePort(port, dr.config.ConnectionInfo.COMPort) {
+			return port
+		}
+	}
+
+	return dr.config.ConnectionInfo.COMPort
+}
+
+// permissionFix says how to get access to a device deej wasn't allowed to open
+func (dr *doctor) permissionFix(portName string) string {
+	if !util.Linux() {
+		return "Another program (a serial monitor, or another deej) probably has it open."
+	}
+
+	groups, err := serialDeviceGroups(portName)
+	if err != nil || len(groups) == 0 {
+		return "Check the device's permissions, deej couldn't tell which group owns it."
+	}
+
+	return fmt.Sprintf("Run \"sudo usermod -aG %s %s\", then log out and back in.", groups[0], os.Getenv("USER"))
+}
+
+func (dr *doctor) checkAudio() {
+	check := doctorCheck{name: "Audio backend"}
+
+	backend := audioBackendAuto
+	if dr.config != nil {
+		backend = dr.config.AudioBackend
+	}
+
+	sessionFinder, err := newSessionFinderWithFallback(dr.logger, backend)
+	if err != nil {
+		check.status = doctorFail
+		check.details = append(check.details, err.Error())
+		check.fix = errorMessage(withErrorCode(errorCodeAudioUnavailable, "", err))
+		dr.add(check)
+
+		return
+	}
+	defer sessionFinder.Release()
+
+	sessions, err := sessionFinder.GetAllSessions()
+	if err != nil {
+		check.status = doctorFail
+		check.details = append(check.details, fmt.Sprintf("connected, but couldn't list audio sessions: %v", err))
+		check.fix = errorMessage(withErrorCode(errorCodeAudioUnavailable, "", err))
+		dr.add(check)
+
+		return
+	}
+
+	keys := map[string]bool{}
+	for _, session := range sessions {
+		keys[session.Key()] = true
+		session.Release()
+	}
+
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	if len(names) > doctorMaxListedSessions {
+		names = append(names[:doctorMaxListedSessions], fmt.Sprintf("and %d more", len(names)-doctorMaxListedSessions))
+	}
+
+	check.status = doctorPass
+	check.details = append(check.details, fmt.Sprintf("connected, %d session(s): %s", len(sessions), strings.Join(names, ", ")))
+
+	if !keys[masterSessionName] {
+		check.status = doctorWarn
+		check.fix = "The audio backend has no default output device, so \"master\" won't control anything."
+	}
+
+	dr.add(check)
+}
+
+// checkNotifications sends a test notification. whether it was actually shown is up to the system, so all that can
+// be told here is whether it was handed over
+func (dr *doctor) checkNotifications() {
+	check := doctorCheck{name: "Notifications"}
+
+	if dr.notifier == nil {
+		check.status = doctorSkip
+		dr.add(check)
+
+		return
+	}
+
+	for _, missing := range dr.notifier.missing {
+		check.details = append(check.details, "missing "+missing)
+	}
+
+	if err := dr.notifier.send(tr("notify.doctor.title"), tr("notify.doctor.message")); err != nil {
+		check.status = doctorFail
+		check.details = append(check.details, err.Error())
+		check.fix = "deej can't show notifications, so it'll only be able to tell you about problems in its tray menu or logs."
+
+		if util.Linux() {
+			check.fix += " Make sure a notification daemon is running."
+		}
+
+		dr.add(check)
+		return
+	}
+
+	check.status = doctorPass
+	check.details = append(check.details, "sent a test notification, if it didn't show up check your system's notification settings")
+
+	dr.add(check)
+}
+
+func (dr *doctor) failed() bool {
+	for _, check := range dr.checks {
+		if check.status == doctorFail {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (dr *doctor) report(out io.Writer) {
+	counts := map[doctorStatus]int{}
+
+	fmt.Fprintln(out, "deej self-test")
+	fmt.Fprintln(out)
+
+	for _, check := range dr.checks {
+		counts[check.status]++
+
+		fmt.Fprintf(out, "[%s] %s\n", check.status, check.name)
+		for _, detail := range check.details {
+			fmt.Fprintf(out, "       %s\n", detail)
+		}
+
+		if check.fix != "" {
+			for idx, line := range strings.Split(check.fix, "\n") {
+				prefix := "  fix: "
+				if idx > 0 {
+					prefix = "       "
+				}
+
+				fmt.Fprintf(out, "%s%s\n", prefix, line)
+			}
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%d passed, %d warning(s), %d failed, %d skipped\n",
+		counts[doctorPass], counts[doctorWarn], counts[doctorFail], counts[doctorSkip])
+}
+
+func sortedPorts(found map[string]uint) []string {
+	ports := make([]string, 0, len(found))
+	for port := range found {
+		ports = append(ports, port)
+	}
+	sort.Strings(ports)
+
+	return ports
+}
@@ -0,0 +1,106 @@
+package deej
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/thoas/go-funk"
+)
+
+// duckedVolume is what a session's volume was before it got ducked, and what it was ducked to
+type duckedVolume struct {
+	original float32
+	ducked   float32
+}
+
+// duckOthers lowers every app session except the given target's to level times its current volume,
+// remembering their volumes for restoreDucked. devices (master, mic, system) are left alone, since
+// ducking them would duck the target too. it's a no-op while sessions are already ducked
+func (m *sessionMap) duckOthers(target string, level float32) error {
+	m.duckLock.Lock()
+	defer m.duckLock.Unlock()
+
+	if m.duckedVolumes != nil {
+		return nil
+	}
+
+	spared := m.resolveTarget(target)
+	if len(spared) == 0 {
+		return fmt.Errorf("duck others than %q: no such sessions", target)
+	}
+
+	m.lock.Lock()
+	sessions := []Session{}
+	for key, keySessions := range m.m {
+		if funk.ContainsString(spared, key) {
+			continue
+		}
+
+		sessions = append(sessions, keySessions...)
+	}
+	m.lock.Unlock()
+
+	ducked := map[string]duckedVolume{}
+
+	for _, session := range sessions {
+		if m.isDeviceSession(session) {
+			continue
+		}
+
+		original := session.GetVolume()
+		volume := original * level
+
+		m.recordVolumeSet(session.Key(), volume)
+
+		if err := session.SetVolume(volume); err != nil {
+			m.logger.Warnw("Failed to duck session", "session", session.Key(), "error", err)
+			continue
+		}
+
+		// sessions sharing a key were set to the same volume by their slider anyway
+		ducked[session.Key()] = duckedVolume{original: original, ducked: volume}
+	}
+
+	m.logger.Debugw("Ducked sessions", "spared", spared, "level", level, "count", len(ducked))
+	m.duckedVolumes = ducked
+
+	return nil
+}
+
+// restoreDucked brings ducked sessions back to their volume from before they were ducked. sessions whose
+// volume changed in the meantime (i.e. their slider moved) keep their new one
+func (m *sessionMap) restoreDucked() {
+	m.duckLock.Lock()
+	defer m.duckLock.Unlock()
+
+	// sessions may have been refreshed since, so they're looked up again by key
+	for key, volumes := range m.duckedVolumes {
+		sessions, _ := m.get(key)
+
+		for _, session := range sessions {
+			if math.Abs(float64(session.GetVolume()-volumes.ducked)) >= externalVolumeChangeTolerance {
+				continue
+			}
+
+			m.recordVolumeSet(key, volumes.original)
+
+			if err := session.SetVolume(volumes.original); err != nil {
+				m.logger.Warnw("Failed to restore ducked session", "session", key, "error", err)
+				continue
+			}
+
+			m.persistVolume(session, volumes.original)
+		}
+	}
+
+	m.logger.Debugw("Restored ducked sessions", "count", len(m.duckedVolumes))
+	m.duckedVolumes = nil
+}
+
+// ducking returns true while sessions are ducked
+func (m *sessionMap) ducking() bool {
+	m.duckLock.Lock()
+	defer m.duckLock.Unlock()
+
+	return m.duckedVolumes != nil
+}
@@ -0,0 +1,218 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BoardSettings are settings boards keep in their own EEPROM, so they carry them from one computer to the next:
+// what the board is called, how many sliders it reads, what those are called and how bright its LEDs are by
+// default. boards advertising "eeprom" answer the "eeprom" command with them, and store the ones sent along with
+// "eeprom_write" (answering "eeprom_write_ack"). both are comma-separated pairs, like hardware settings, with values
+// percent-escaped since names can hold commas and colons:
+// "deej:v2.0:response:eeprom:name=Desk%20Mixer,sliders=5,slider_name.0=Music,led_brightness=128"
+type BoardSettings struct {
+	Name string `json:"name"`
+
+	// 0 if the board doesn't say
+	Sliders int `json:"sliders"`
+
+	// slider index -> its name
+	SliderNames map[int]string `json:"sliderNames"`
+
+	// hardwareSettingUnset if the board doesn't say
+	LEDBrightness int `json:"ledBrightness"`
+}
+
+const (
+	capabilityEEPROM = "eeprom"
+
+	boardKeyName          = "name"
+	boardKeySliders       = "sliders"
+	boardKeySliderName    = "slider_name"
+	boardKeyLEDBrightness = "led_brightness"
+
+	// EEPROMs are small, and names have to fit on the board's display
+	maxBoardNameLength = 32
+)
+
+var errInvalidBoardSettings = errors.New("invalid board settings")
+
+func newBoardSettings() BoardSettings {
+	return BoardSettings{
+		SliderNames:   map[int]string{},
+		LEDBrightness: hardwareSettingUnset,
+	}
+}
+
+// parseBoardSettings parses the board's answer to the "eeprom" command. keys it doesn't know are skipped, so newer
+// firmware can keep more
+func parseBoardSettings(raw string) (BoardSettings, error) {
+	settings := newBoardSettings()
+
+	for _, pair := range strings.Split(raw, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return BoardSettings{}, fmt.Errorf("%q: expected key=value: %w", pair, errInvalidBoardSettings)
+		}
+
+		key := strings.TrimSpace(parts[0])
+
+		value, err := url.PathUnescape(parts[1])
+		if err != nil {
+			return BoardSettings{}, fmt.Errorf("%q: %v: %w", pair, err, errInvalidBoardSettings)
+		}
+
+		switch {
+		case key == boardKeyName:
+			settings.Name = value
+
+		case key == boardKeySliders:
+			if settings.Sliders, err = strconv.Atoi(value); err != nil {
+				return BoardSettings{}, fmt.Errorf("%q: not a number: %w", pair, errInvalidBoardSettings)
+			}
+
+		case key == boardKeyLEDBrightness:
+			if settings.LEDBrightness, err = strconv.Atoi(value); err != nil {
+				return BoardSettings{}, fmt.Errorf("%q: not a number: %w", pair, errInvalidBoardSettings)
+			}
+
+		case strings.HasPrefix(key, boardKeySliderName+"."):
+			sliderIdx, err := strconv.Atoi(strings.TrimPrefix(key, boardKeySliderName+"."))
+			if err != nil || sliderIdx < 0 {
+				return BoardSettings{}, fmt.Errorf("%q: not a slider index: %w", pair, errInvalidBoardSettings)
+			}
+
+			settings.SliderNames[sliderIdx] = value
+		}
+	}
+
+	return settings, nil
+}
+
+// validate returns an error describing the first setting the board couldn't store
+func (bs BoardSettings) validate() error {
+	if len(bs.Name) > maxBoardNameLength {
+		return fmt.Errorf("%s can't be longer than %d characters: %w", boardKeyName, maxBoardNameLength, errInvalidBoardSettings)
+	}
+
+	if bs.Sliders < 0 || bs.Sliders > maxSliderCount {
+		return fmt.Errorf("%s must be between 1 and %d: %w", boardKeySliders, maxSliderCount, errInvalidBoardSettings)
+	}
+
+	for sliderIdx, name := range bs.SliderNames {
+		if sliderIdx < 0 || len(name) > maxBoardNameLength {
+			return fmt.Errorf("%s for slider %d can't be longer than %d characters: %w", boardKeySliderName, sliderIdx,
+				maxBoardNameLength, errInvalidBoardSettings)
+		}
+	}
+
+	if bs.LEDBrightness != hardwareSettingUnset && (bs.LEDBrightness < 0 || bs.LEDBrightness > maxLEDBrightness) {
+		return fmt.Errorf("%s must be between 0 and %d: %w", boardKeyLEDBrightness, maxLEDBrightness, errInvalidBoardSettings)
+	}
+
+	return nil
+}
+
+// payload formats the settings for the "eeprom_write" command. unset settings are left out, so the board keeps
+// what it has
+func (bs BoardSettings) payload() string {
+	pairs := []string{}
+
+	if bs.Name != "" {
+		pairs = append(pairs, boardKeyName+"="+escapeBoardValue(bs.Name))
+	}
+
+	if bs.Sliders > 0 {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", boardKeySliders, bs.Sliders))
+	}
+
+	sliderIdxs := []int{}
+	for sliderIdx := range bs.SliderNames {
+		sliderIdxs = append(sliderIdxs, sliderIdx)
+	}
+
+	sort.Ints(sliderIdxs)
+
+	for _, sliderIdx := range sliderIdxs {
+		pairs = append(pairs, fmt.Sprintf("%s.%d=%s", boardKeySliderName, sliderIdx, escapeBoardValue(bs.SliderNames[sliderIdx])))
+	}
+
+	if bs.LEDBrightness != hardwareSettingUnset {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", boardKeyLEDBrightness, bs.LEDBrightness))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// escapeBoardValue percent-escapes whatever would get in the way of parsing a frame or a pair, and anything that
+// isn't printable ascii. it's the least firmware has to decode
+func escapeBoardValue(value string) string {
+	var escaped strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		if c < 0x20 || c >= 0x7f || strings.IndexByte("%,=:", c) >= 0 {
+			fmt.Fprintf(&escaped, "%%%02X", c)
+			continue
+		}
+
+		escaped.WriteByte(c)
+	}
+
+	return escaped.String()
+}
+
+// ReadBoardSettings asks the board for the settings in its EEPROM
+func (sio *SerialIO) ReadBoardSettings() (BoardSettings, error) {
+	if !sio.Capabilities().Supports(capabilityEEPROM) {
+		return BoardSettings{}, fmt.Errorf("read board settings: %w", errCapabilityUnsupported)
+	}
+
+	args, err := sio.SendCommandAndWait("eeprom", commandResponseTimeout)
+	if err != nil {
+		return BoardSettings{}, err
+	}
+
+	settings, err := parseBoardSettings(strings.Join(args, ":"))
+	if err != nil {
+		sio.logger.Warnw("Board sent settings we can't read", "error", err)
+		return BoardSettings{}, fmt.Errorf("read board settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// WriteBoardSettings has the board store the given settings in its EEPROM
+func (sio *SerialIO) WriteBoardSettings(settings BoardSettings) error {
+	if !sio.Capabilities().Supports(capabilityEEPROM) {
+		return fmt.Errorf("write board settings: %w", errCapabilityUnsupported)
+	}
+
+	if err := settings.validate(); err != nil {
+		return fmt.Errorf("write board settings: %w", err)
+	}
+
+	if _, err := sio.SendCommandAndWait("eeprom_write:"+settings.payload(), commandResponseTimeout); err != nil {
+		return err
+	}
+
+	sio.logger.Infow("Stored settings on the board", "settings", settings)
+
+	sio.deviceLock.Lock()
+	sio.boardSliderNames = settings.SliderNames
+	sio.deviceLock.Unlock()
+
+	sio.displaySliderNames()
+
+	return nil
+}
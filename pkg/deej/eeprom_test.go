@@ -0,0 +1,39 @@
+package deej
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBoardSettingsRoundTrip(t *testing.T) {
+	settings := newBoardSettings()
+	settings.Name = "Desk: 100%, left"
+	settings.Sliders = 5
+	settings.SliderNames[0] = "Music"
+	settings.SliderNames[3] = "Chat=Discord"
+	settings.LEDBrightness = 128
+
+	payload := settings.payload()
+	if expected := "name=Desk%3A 100%25%2C left,sliders=5,slider_name.0=Music,slider_name.3=Chat%3DDiscord,led_brightness=128"; payload != expected {
+		t.Errorf("expected payload %q, got %q", expected, payload)
+	}
+
+	parsed, err := parseBoardSettings(payload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+
+	if !reflect.DeepEqual(parsed, settings) {
+		t.Errorf("expected %+v back, got %+v", settings, parsed)
+	}
+
+	// whatever newer firmware keeps besides is skipped
+	parsed, err = parseBoardSettings("sliders=3,theme=dark")
+	if err != nil || parsed.Sliders != 3 || parsed.LEDBrightness != hardwareSettingUnset {
+		t.Errorf("expected 3 sliders and nothing else, got %+v (%v)", parsed, err)
+	}
+
+	if _, err := parseBoardSettings("sliders"); err == nil {
+		t.Error("expected a pair without a value to fail")
+	}
+}
@@ -0,0 +1,210 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// eq targets ("eq:band2.gain") control a band of a pipewire filter-chain equalizer, so a deej box can act as a
+// physical tone control. the filter-chain's filters need to be named eq_band_1, eq_band_2 and so on, like in
+// pipewire's own parametric eq examples. parameters are read with pw-dump and set with pw-cli, see pipewire_linux.go
+const (
+	eqParamGain = "gain"
+	eqParamFreq = "freq"
+	eqParamQ    = "q"
+
+	// the gain slider goes from -eqGainRange to +eqGainRange dB, with 0 dB in the middle
+	eqGainRange = 12.0
+
+	// frequency and q sliders are logarithmic between these
+	eqMinFreq = 20.0
+	eqMaxFreq = 20000.0
+	eqMinQ    = 0.1
+	eqMaxQ    = 10.0
+)
+
+// filter-chain control names, i.e. "eq_band_2:Gain"
+var eqControlPattern = regexp.MustCompile(`^eq_band_(\d+):(Freq|Q|Gain)$`)
+
+var errEQMuteUnsupported = errors.New("eq bands can't be muted")
+
+// eqSession controls a single parameter of one equalizer band
+type eqSession struct {
+	baseSession
+
+	nodeID  int
+	control string
+	param   string
+
+	// pw-dump is too slow to run on every GetVolume, so this is the last value deej read or set
+	value float64
+	lock  sync.Mutex
+
+	writer *pwPropsWriter
+}
+
+func newEQSession(logger *zap.SugaredLogger, nodeID int, control string, band string, param string, value float64) *eqSession {
+	s := &eqSession{
+		nodeID:  nodeID,
+		control: control,
+		param:   param,
+		value:   value,
+	}
+
+	key := fmt.Sprintf("%sband%s.%s", eqTargetPrefix, band, param)
+
+	s.logger = logger.Named(key)
+	s.writer = newPWPropsWriter(s.logger, func(props string) error { return pwSetProps(nodeID, props) })
+	s.name = key
+	s.humanReadableDesc = fmt.Sprintf("%s (%s on node %d)", key, control, nodeID)
+
+	s.logger.Debugw(sessionCreationLogMessage, "session", s)
+
+	return s
+}
+
+// findEQSessions returns a session for every equalizer band parameter pipewire knows about.
+// without pipewire's tools around, there just aren't any
+func findEQSessions(logger *zap.SugaredLogger) ([]Session, error) {
+	if _, err := exec.LookPath("pw-dump"); err != nil {
+		return nil, nil
+	}
+
+	objects, err := pwDump()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []*eqSession{}
+	seen := map[string]bool{}
+
+	for _, object := range objects {
+		if object.Type != pwNodeType {
+			continue
+		}
+
+		for _, props := range object.Info.Params.Props {
+
+			// params alternate between a control name and its value
+			for idx := 0; idx+1 < len(props.Params); idx += 2 {
+				control, ok := props.Params[idx].(string)
+				if !ok {
+					continue
+				}
+
+				value, ok := props.Params[idx+1].(float64)
+				if !ok {
+					continue
+				}
+
+				match := eqControlPattern.FindStringSubmatch(control)
+				if match == nil {
+					continue
+				}
+
+				// a filter-chain's capture and playback nodes can both list its controls
+				session := newEQSession(logger, object.ID, control, match[1], strings.ToLower(match[2]), value)
+				if seen[session.Key()] {
+					continue
+				}
+
+				seen[session.Key()] = true
+				sessions = append(sessions, session)
+			}
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Key() < sessions[j].Key() })
+
+	result := make([]Session, len(sessions))
+	for idx, session := range sessions {
+		result[idx] = session
+	}
+
+	return result, nil
+}
+
+func (s *eqSession) GetVolume() float32 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return float32(eqParamToVolume(s.param, s.value))
+}
+
+// the parameter is set in the background, see pwPropsWriter. failing to set it is only logged
+func (s *eqSession) SetVolume(v float32) error {
+	value := eqVolumeToParam(s.param, float64(v))
+	props := fmt.Sprintf(`{ params = [ "%s" %s ] }`, s.control, strconv.FormatFloat(value, 'f', 2, 64))
+
+	s.lock.Lock()
+	s.value = value
+	s.lock.Unlock()
+
+	s.writer.set(s.control, props)
+	s.logger.Debugw("Adjusting eq parameter", "to", fmt.Sprintf("%.2f", value))
+
+	return nil
+}
+
+func (s *eqSession) GetMute() bool {
+	return false
+}
+
+func (s *eqSession) SetMute(m bool) error {
+	return errEQMuteUnsupported
+}
+
+func (s *eqSession) IsAlive() bool {
+	return true
+}
+
+func (s *eqSession) Release() {
+	s.logger.Debug("Releasing audio session")
+}
+
+func (s *eqSession) String() string {
+	return fmt.Sprintf(sessionStringFormat, s.humanReadableDesc, s.GetVolume())
+}
+
+// eqVolumeToParam maps a slider's volume (0-1) to a value for the given parameter
+func eqVolumeToParam(param string, volume float64) float64 {
+	volume = math.Max(0, math.Min(1, volume))
+
+	switch param {
+	case eqParamFreq:
+		return eqMinFreq * math.Pow(eqMaxFreq/eqMinFreq, volume)
+	case eqParamQ:
+		return eqMinQ * math.Pow(eqMaxQ/eqMinQ, volume)
+	default:
+		return (volume*2 - 1) * eqGainRange
+	}
+}
+
+// eqParamToVolume is the inverse of eqVolumeToParam
+func eqParamToVolume(param string, value float64) float64 {
+	var volume float64
+
+	switch param {
+	case eqParamFreq:
+		volume = math.Log(value/eqMinFreq) / math.Log(eqMaxFreq/eqMinFreq)
+	case eqParamQ:
+		volume = math.Log(value/eqMinQ) / math.Log(eqMaxQ/eqMinQ)
+	default:
+		volume = (value/eqGainRange + 1) / 2
+	}
+
+	if math.IsNaN(volume) {
+		return 0
+	}
+
+	return math.Max(0, math.Min(1, volume))
+}
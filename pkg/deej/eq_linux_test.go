@@ -0,0 +1,46 @@
+package deej
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestPWPropsWriterSetsOnlyTheLatest(t *testing.T) {
+	applied := make(chan string)
+	release := make(chan struct{})
+
+	w := newPWPropsWriter(zap.NewNop().Sugar(), func(props string) error {
+		applied <- props
+		<-release
+		return nil
+	})
+
+	expectApplied := func(expected string) {
+		select {
+		case props := <-applied:
+			if props != expected {
+				t.Errorf("expected %q set, got %q", expected, props)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %q set, got nothing", expected)
+		}
+	}
+
+	// the first one keeps the writer busy while the rest come in
+	w.set("gain", "first")
+	expectApplied("first")
+
+	w.set("gain", "second")
+	w.set("gain", "third")
+	close(release)
+
+	expectApplied("third")
+
+	select {
+	case props := <-applied:
+		t.Errorf("expected nothing else set, got %q", props)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
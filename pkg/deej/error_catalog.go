@@ -0,0 +1,231 @@
+package deej
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// problems the user can do something about have a code, and the error catalog turns the code into what the user
+// is told: a title and message, a hint on how to fix it, and where in the docs to read more. errors get their code
+// from what they wrap (e.g. errCommandTimeout), or from withErrorCode when their cause doesn't tell it apart, like
+// os.ErrPermission meaning something else for a serial port than for a config file
+
+// errorCode identifies an entry in the error catalog
+type errorCode string
+
+const (
+	errorCodeConfigNotFound       errorCode = "config_not_found"
+	errorCodeConfigSyntax         errorCode = "config_syntax"
+	errorCodeConfigInvalid        errorCode = "config_invalid"
+	errorCodeConfigUnreadable     errorCode = "config_unreadable"
+	errorCodePortBusy             errorCode = "port_busy"
+	errorCodePortNotFound         errorCode = "port_not_found"
+	errorCodeDeviceNotFound       errorCode = "device_not_found"
+	errorCodeNotConnected         errorCode = "not_connected"
+	errorCodeDeviceUnresponsive   errorCode = "device_unresponsive"
+	errorCodeCommandRejected      errorCode = "command_rejected"
+	errorCodeFirmwareIncompatible errorCode = "firmware_incompatible"
+	errorCodeUnsupported          errorCode = "unsupported"
+	errorCodeAudioUnavailable     errorCode = "audio_unavailable"
+)
+
+const docsFAQURL = "https://github.com/omriharel/deej/blob/master/docs/faq/faq.md"
+
+// errorCatalogEntry holds the string keys (see i18n.go) of what the user is told about a problem. titles and
+// messages can mention the problem's subject (the port, or the config file) where they say so
+type errorCatalogEntry struct {
+	title          string
+	titleSubject   bool
+	message        string
+	messageSubject bool
+
+	// optional
+	hint string
+	docs string
+}
+
+var errorCatalog = map[errorCode]errorCatalogEntry{
+	errorCodeConfigNotFound: {
+		title:   "notify.config_not_found.title",
+		message: "notify.config_not_found.message", messageSubject: true,
+		hint: "error.config_not_found.hint",
+		docs: docsFAQURL + "#how-do-i-open-and-edit-the-configyaml-file",
+	},
+	errorCodeConfigSyntax: {
+		title:   "notify.config_invalid.title",
+		message: "notify.config_invalid_yaml.message", messageSubject: true,
+		hint: "error.config_syntax.hint",
+		docs: docsFAQURL + "#how-can-i-check-my-configyaml-for-errors",
+	},
+
+	// the error itself says which setting is wrong, so it's the message
+	errorCodeConfigInvalid: {
+		title: "notify.config_invalid.title",
+		hint:  "error.config_invalid.hint",
+	},
+	errorCodeConfigUnreadable: {
+		title:   "notify.config_error.title",
+		message: "notify.config_error.message",
+	},
+	errorCodePortBusy: {
+		title: "notify.cant_connect.title", titleSubject: true,
+		message: "notify.port_busy.message",
+		docs:    docsFAQURL + "#im-unable-to-connect-to-my-board-in-the-arduino-ide",
+	},
+	errorCodePortNotFound: {
+		title: "notify.cant_connect.title", titleSubject: true,
+		message: "notify.port_missing.message",
+		docs:    docsFAQURL + "#how-can-i-find-my-comserial-port",
+	},
+	errorCodeDeviceNotFound: {
+		title:   "error.device_not_found.title",
+		message: "error.device_not_found.message",
+		hint:    "error.device_not_found.hint",
+		docs:    docsFAQURL + "#how-do-i-know-that-i-uploaded-the-deej-sketch-correctly",
+	},
+	errorCodeNotConnected: {
+		title:   "error.device_not_found.title",
+		message: "error.not_connected.message",
+		hint:    "error.not_connected.hint",
+	},
+	errorCodeDeviceUnresponsive: {
+		title:   "error.device_unresponsive.title",
+		message: "error.device_unresponsive.message",
+		hint:    "error.device_unresponsive.hint",
+		docs:    docsFAQURL + "#how-do-i-know-that-i-uploaded-the-deej-sketch-correctly",
+	},
+	errorCodeCommandRejected: {
+		title:   "error.device_unresponsive.title",
+		message: "error.command_rejected.message",
+		hint:    "error.update_firmware.hint",
+	},
+	errorCodeFirmwareIncompatible: {
+		title:   "notify.incompatible_firmware.title",
+		message: "error.firmware_incompatible.message",
+		hint:    "error.update_firmware.hint",
+	},
+	errorCodeUnsupported: {
+		title:   "error.device_unresponsive.title",
+		message: "error.unsupported.message",
+	},
+	errorCodeAudioUnavailable: {
+		title:   "error.audio_unavailable.title",
+		message: "error.audio_unavailable.message",
+		hint:    "error.audio_unavailable.hint",
+	},
+}
+
+// codedError gives an error its code in the error catalog, and names what it's about
+type codedError struct {
+	code    errorCode
+	subject string
+	err     error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// withErrorCode gives err a code in the error catalog. subject is what the error is about, e.g. a port name
+func withErrorCode(code errorCode, subject string, err error) error {
+	return &codedError{code: code, subject: subject, err: err}
+}
+
+// errorCodeOf returns the catalog code for err, or an empty one if it isn't something the catalog knows about
+func errorCodeOf(err error) (errorCode, string) {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code, coded.subject
+	}
+
+	var parseErr viper.ConfigParseError
+
+	switch {
+	case errors.Is(err, errConfigNotFound):
+		return errorCodeConfigNotFound, userConfigFilepath
+	case errors.As(err, &parseErr):
+		return errorCodeConfigSyntax, userConfigFilepath
+	case errors.Is(err, errInvalidConfig):
+		return errorCodeConfigInvalid, userConfigFilepath
+	case errors.Is(err, errHIDDeviceNotFound):
+		return errorCodeDeviceNotFound, ""
+	case errors.Is(err, errNotConnected), errors.Is(err, errCommandAborted):
+		return errorCodeNotConnected, ""
+	case errors.Is(err, errCommandTimeout):
+		return errorCodeDeviceUnresponsive, ""
+	case errors.Is(err, errCommandRejected):
+		return errorCodeCommandRejected, ""
+	case errors.Is(err, errLegacyProtocol), errors.Is(err, errIncompatibleProtocol):
+		return errorCodeFirmwareIncompatible, ""
+	case errors.Is(err, errCapabilityUnsupported):
+		return errorCodeUnsupported, ""
+	}
+
+	return "", ""
+}
+
+// describeError turns an error into a notification title and message the user can act on. errors the catalog
+// doesn't know about point the user to the logs
+func describeError(err error) (string, string) {
+	code, subject := errorCodeOf(err)
+
+	entry, ok := errorCatalog[code]
+	if !ok {
+		return tr("error.unknown.title"), tr("error.unknown.message")
+	}
+
+	title := tr(entry.title)
+	if entry.titleSubject {
+		title = tr(entry.title, subject)
+	}
+
+	return title, describeErrorMessage(err, entry, subject)
+}
+
+// describeErrorMessage is describeError's message alone, for when the caller has a title of its own
+func describeErrorMessage(err error, entry errorCatalogEntry, subject string) string {
+	var message string
+
+	switch {
+	case entry.message == "":
+		message = strings.TrimSuffix(err.Error(), ": "+errInvalidConfig.Error())
+	case entry.messageSubject:
+		message = tr(entry.message, subject)
+	default:
+		message = tr(entry.message)
+	}
+
+	if entry.hint != "" {
+		message += " " + tr(entry.hint)
+	}
+
+	if entry.docs != "" {
+		message += "\n" + tr("error.docs", entry.docs)
+	}
+
+	return message
+}
+
+// errorMessage returns the message describeError would, or the error's own text if the catalog doesn't know it
+func errorMessage(err error) string {
+	code, subject := errorCodeOf(err)
+
+	entry, ok := errorCatalog[code]
+	if !ok {
+		return err.Error()
+	}
+
+	return describeErrorMessage(err, entry, subject)
+}
+
+// notifyError lets the user know about a problem, as described by the error catalog
+func (d *Deej) notifyError(err error) {
+	title, message := describeError(err)
+	d.notifier.Notify(title, message)
+}
@@ -0,0 +1,148 @@
+package deej
+
+import (
+	"strings"
+)
+
+// an explanation walks through how a slider's mapping resolves right now: the targets it's configured with, what
+// each of them stands for, the sessions they matched and those sessions' volumes. it's there to answer "why does
+// this slider control that", so it only looks and never changes anything
+
+// the kinds of targets, by how they're resolved
+const (
+	explainKindName      = "name"      // a process, device or special session name
+	explainKindTransform = "transform" // a deej.* special target
+	explainKindRole      = "role"      // every session with a role
+)
+
+// sliderExplanation describes how a slider's mapping resolves
+type sliderExplanation struct {
+	SliderID int `json:"sliderId"`
+
+	// a runtime override replaced the configured targets
+	Overridden bool `json:"overridden"`
+
+	// the targets aren't the slider's, but ones it could be mapped to, see explainSlider
+	Preview bool `json:"preview"`
+
+	// slider input is ignored while the screen is locked (see lockPolicyFreeze)
+	Frozen bool `json:"frozen"`
+
+	// the slider's targets keep an externally set volume until it moves
+	Adopted bool `json:"adopted"`
+
+	// where the slider is, nil until the device reported it
+	SliderValue *float32 `json:"sliderValue,omitempty"`
+
+	Targets []targetExplanation `json:"targets"`
+}
+
+// targetExplanation describes a single mapping target and what it resolved to
+type targetExplanation struct {
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+
+	// the target hasn't matched any session since it was mapped, see targetDiagnostics
+	NeverMatched bool `json:"neverMatched,omitempty"`
+
+	Resolved []resolvedTargetExplanation `json:"resolved"`
+}
+
+// resolvedTargetExplanation describes a session key a target resolved to
+type resolvedTargetExplanation struct {
+	Key string `json:"key"`
+
+	// the friendly name of the sessions behind the key, if they have one
+	DisplayName string `json:"displayName,omitempty"`
+
+	// the volume the slider's position sets the key to, after trims, crossfades and the slider's ceiling.
+	// nil while the slider's position isn't known
+	Volume *float32 `json:"volume,omitempty"`
+
+	Sessions []sessionExplanation `json:"sessions"`
+}
+
+// sessionExplanation describes a session's current state
+type sessionExplanation struct {
+	Volume float32 `json:"volume"`
+	Muted  bool    `json:"muted"`
+
+	// the keys that address just this session, see session_instances.go
+	Instances []string `json:"instances,omitempty"`
+}
+
+// explainSlider describes how the given slider's mapping resolves right now. with preview targets, it describes how
+// those would resolve if the slider was mapped to them instead, so mapping changes can be checked before saving
+func (m *sessionMap) explainSlider(sliderID int, preview []string) sliderExplanation {
+	explanation := sliderExplanation{
+		SliderID: sliderID,
+		Frozen:   m.deej.lockPolicies.frozen(),
+		Preview:  preview != nil,
+		Targets:  []targetExplanation{},
+	}
+
+	if !explanation.Preview {
+		_, explanation.Overridden = m.deej.config.MappingOverrides()[sliderID]
+	}
+
+	m.volumeLock.Lock()
+	_, explanation.Adopted = m.adoptedVolumes[sliderID]
+	m.volumeLock.Unlock()
+
+	sliderValue, sliderValueKnown := m.sliders.value(sliderID)
+	if sliderValueKnown {
+		explanation.SliderValue = &sliderValue
+	}
+
+	targets, _ := m.deej.config.SliderMapping.get(sliderID)
+	if explanation.Preview {
+		targets = preview
+	}
+
+	for _, target := range targets {
+		name, _, _ := splitTargetTrim(strings.ToLower(target))
+
+		targetExplanation := targetExplanation{
+			Target:   target,
+			Kind:     explainKindName,
+			Resolved: []resolvedTargetExplanation{},
+		}
+
+		if m.targetHasSpecialTransform(name) {
+			targetExplanation.Kind = explainKindTransform
+		} else if _, ok := roleTarget(name); ok {
+			targetExplanation.Kind = explainKindRole
+		} else {
+			targetExplanation.NeverMatched = m.deej.diagnostics.isUnmatched(target)
+		}
+
+		for _, resolvedTarget := range m.resolveTarget(target) {
+			resolved := resolvedTargetExplanation{
+				Key:         resolvedTarget,
+				DisplayName: m.displayName(resolvedTarget),
+				Sessions:    []sessionExplanation{},
+			}
+
+			if sliderValueKnown {
+				volume := m.capVolume(resolvedTarget, m.targetTrim(target, resolvedTarget).apply(
+					m.crossfadeValue(sliderID, target, sliderValue), m.sliderCeiling(sliderID)))
+				resolved.Volume = &volume
+			}
+
+			sessions, _ := m.get(resolvedTarget)
+			for _, session := range sessions {
+				resolved.Sessions = append(resolved.Sessions, sessionExplanation{
+					Volume:    session.GetVolume(),
+					Muted:     session.GetMute(),
+					Instances: m.instanceKeys(session),
+				})
+			}
+
+			targetExplanation.Resolved = append(targetExplanation.Resolved, resolved)
+		}
+
+		explanation.Targets = append(explanation.Targets, targetExplanation)
+	}
+
+	return explanation
+}
@@ -0,0 +1,90 @@
+package deej
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// faderSync moves motorized faders to follow volume changes made outside of deej (media keys,
+// the OS mixer, other apps). fader updates are throttled per slider, and only the latest
+// position is sent, so dragging a volume slider on screen doesn't flood the serial line
+type faderSync struct {
+	deej   *Deej
+	logger *zap.SugaredLogger
+
+	pending       map[int]float32
+	flushTimerSet bool
+	lock          sync.Mutex
+}
+
+// how often each fader can be told to move
+const faderUpdateInterval = 50 * time.Millisecond
+
+func newFaderSync(deej *Deej, logger *zap.SugaredLogger) *faderSync {
+	logger = logger.Named("faders")
+
+	fs := &faderSync{
+		deej:    deej,
+		logger:  logger,
+		pending: map[int]float32{},
+	}
+
+	logger.Debug("Created fader sync instance")
+
+	return fs
+}
+
+func (fs *faderSync) initialize() {
+	volumeChanges := fs.deej.sessions.SubscribeToExternalVolumeChanges()
+
+	go func() {
+		for event := range volumeChanges {
+			fs.handleExternalVolumeChange(event)
+		}
+	}()
+}
+
+func (fs *faderSync) handleExternalVolumeChange(event ExternalVolumeChangeEvent) {
+
+	// nothing to move if the device doesn't have motorized faders (or isn't connected)
+	if !fs.deej.serial.Capabilities().Supports(capabilityFaders) {
+		return
+	}
+
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	fs.pending[event.SliderID] = event.PercentValue
+
+	if !fs.flushTimerSet {
+		fs.flushTimerSet = true
+		time.AfterFunc(faderUpdateInterval, fs.flush)
+	}
+}
+
+func (fs *faderSync) flush() {
+	fs.lock.Lock()
+	pending := fs.pending
+	fs.pending = map[int]float32{}
+	fs.flushTimerSet = false
+	fs.lock.Unlock()
+
+	for logicalID, percentValue := range pending {
+
+		// all of a composite slider's faders follow it
+		for _, sliderID := range fs.deej.config.physicalSliders(logicalID) {
+			if err := fs.deej.serial.SetFader(sliderID, percentValue); err != nil {
+				if !errors.Is(err, errCapabilityUnsupported) {
+					fs.logger.Warnw("Failed to move fader", "sliderID", sliderID, "error", err)
+				}
+
+				continue
+			}
+
+			fs.logger.Debugw("Moved fader to match volume", "sliderID", sliderID, "percentValue", percentValue)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package deej
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// gestureRecognizer watches slider movement for deliberate patterns and turns them into actions,
+// giving plain potentiometers a few extra tricks without needing any buttons
+type gestureRecognizer struct {
+	deej   *Deej
+	logger *zap.SugaredLogger
+
+	sliders map[int]*sliderGestureState
+	lock    sync.Mutex
+}
+
+// gestures that can be bound to actions in the config
+const (
+	gestureDoubleTapTop    = "double_tap_top"    // hit 100% twice in quick succession
+	gestureDoubleTapBottom = "double_tap_bottom" // hit 0% twice in quick succession
+	gestureHoldTop         = "hold_top"          // stay at 100% for a while
+	gestureHoldBottom      = "hold_bottom"       // stay at 0% for a while
+	gestureWiggle          = "wiggle"            // move back and forth rapidly
+)
+
+var supportedGestures = []string{
+	gestureDoubleTapTop,
+	gestureDoubleTapBottom,
+	gestureHoldTop,
+	gestureHoldBottom,
+	gestureWiggle,
+}
+
+const (
+
+	// how close to either end a slider must be to count as being at that end stop
+	gestureEndStopMargin = 0.02
+
+	// the longest time between two arrivals at the same end stop that still counts as a double tap
+	gestureDoubleTapWindow = 600 * time.Millisecond
+
+	// how long a slider must stay at an end stop to count as held there
+	gestureHoldDuration = 1500 * time.Millisecond
+
+	// a wiggle is this many direction changes, each at least gestureWiggleMinTravel long, within gestureWiggleWindow
+	gestureWiggleReversals = 4
+	gestureWiggleMinTravel = 0.05
+	gestureWiggleWindow    = time.Second
+)
+
+type endStop int
+
+const (
+	endStopNone endStop = iota
+	endStopBottom
+	endStopTop
+)
+
+type sliderGestureState struct {
+	lastValue float32
+	endStop   endStop
+
+	// when the slider last arrived at each end stop
+	lastArrival map[endStop]time.Time
+	holdTimer   *time.Timer
+
+	// wiggle tracking: the direction of travel, where it last turned around, and when it did
+	direction     int
+	turningValue  float32
+	reversalTimes []time.Time
+}
+
+func newGestureRecognizer(deej *Deej, logger *zap.SugaredLogger) *gestureRecognizer {
+	logger = logger.Named("gestures")
+
+	gr := &gestureRecognizer{
+		deej:    deej,
+		logger:  logger,
+		sliders: map[int]*sliderGestureState{},
+	}
+
+	logger.Debug("Created gesture recognizer instance")
+
+	return gr
+}
+
+func (gr *gestureRecognizer) initialize() {
+	sliderEventsChannel := gr.deej.serial.SubscribeToSliderMoveEvents("gestures")
+
+	go func() {
+		for event := range sliderEventsChannel {
+			gr.handleSliderMoveEvent(event)
+		}
+	}()
+}
+
+func (gr *gestureRecognizer) handleSliderMoveEvent(event SliderMoveEvent) {
+
+	// nothing to do if no gestures are configured, or slider input is frozen
+	if len(gr.deej.config.Gestures) == 0 || gr.deej.lockPolicies.frozen() {
+		return
+	}
+
+	gr.lock.Lock()
+	defer gr.lock.Unlock()
+
+	state, ok := gr.sliders[event.SliderID]
+
+	// the first values after connecting say where the slider is, not how it moved
+	if !ok || event.Initial {
+		gr.sliders[event.SliderID] = &sliderGestureState{
+			lastValue:    event.PercentValue,
+			endStop:      endStopAt(event.PercentValue),
+			lastArrival:  map[endStop]time.Time{},
+			turningValue: event.PercentValue,
+		}
+
+		if ok && state.holdTimer != nil {
+			state.holdTimer.Stop()
+		}
+
+		return
+	}
+
+	now := time.Now()
+
+	gr.trackEndStops(event.SliderID, state, event.PercentValue, now)
+	gr.trackWiggle(event.SliderID, state, event.PercentValue, now)
+
+	state.lastValue = event.PercentValue
+}
+
+func (gr *gestureRecognizer) trackEndStops(sliderID int, state *sliderGestureState, value float32, now time.Time) {
+	current := endStopAt(value)
+	if current == state.endStop {
+		return
+	}
+
+	// the slider left wherever it was, so it's not being held there anymore
+	if state.holdTimer != nil {
+		state.holdTimer.Stop()
+		state.holdTimer = nil
+	}
+
+	state.endStop = current
+	if current == endStopNone {
+		return
+	}
+
+	// arriving at the same end stop twice in a row, quickly enough, is a double tap
+	if lastArrival, ok := state.lastArrival[current]; ok && now.Sub(lastArrival) <= gestureDoubleTapWindow {
+		delete(state.lastArrival, current)
+		gr.trigger(sliderID, pickGesture(current, gestureDoubleTapTop, gestureDoubleTapBottom))
+
+		return
+	}
+
+	state.lastArrival[current] = now
+
+	// and staying there is a hold
+	holdGesture := pickGesture(current, gestureHoldTop, gestureHoldBottom)
+	state.holdTimer = time.AfterFunc(gestureHoldDuration, func() {
+		gr.lock.Lock()
+		stillHeld := state.endStop == current
+		state.holdTimer = nil
+		gr.lock.Unlock()
+
+		if stillHeld {
+			gr.trigger(sliderID, holdGesture)
+		}
+	})
+}
+
+func (gr *gestureRecognizer) trackWiggle(sliderID int, state *sliderGestureState, value float32, now time.Time) {
+	direction := 1
+	if value < state.lastValue {
+		direction = -1
+	}
+
+	// a reversal only counts once the slider travelled far enough since the last one
+	if direction != state.direction {
+		travel := state.lastValue - state.turningValue
+		if travel < 0 {
+			travel = -travel
+		}
+
+		if state.direction != 0 && travel >= gestureWiggleMinTravel {
+			state.reversalTimes = append(state.reversalTimes, now)
+		}
+
+		state.direction = direction
+		state.turningValue = state.lastValue
+	}
+
+	// forget reversals that are too old to be part of the same wiggle
+	for len(state.reversalTimes) > 0 && now.Sub(state.reversalTimes[0]) > gestureWiggleWindow {
+		state.reversalTimes = state.reversalTimes[1:]
+	}
+
+	if len(state.reversalTimes) >= gestureWiggleReversals {
+		state.reversalTimes = nil
+		gr.trigger(sliderID, gestureWiggle)
+	}
+}
+
+// trigger runs the action bound to a gesture, if there is one. it never blocks the caller
+func (gr *gestureRecognizer) trigger(sliderID int, gesture string) {
+	action, ok := gr.deej.config.Gestures[gesture]
+	if !ok {
+		gr.logger.Debugw("Recognized unbound gesture", "sliderID", sliderID, "gesture", gesture)
+		return
+	}
+
+	gr.logger.Infow("Recognized gesture", "sliderID", sliderID, "gesture", gesture, "action", action)
+
+	go func() {
+		if err := gr.deej.performAction(action, sliderID); err != nil {
+			gr.logger.Warnw("Failed to perform gesture action", "gesture", gesture, "action", action, "error", err)
+		}
+	}()
+}
+
+func endStopAt(value float32) endStop {
+	switch {
+	case value <= gestureEndStopMargin:
+		return endStopBottom
+	case value >= 1-gestureEndStopMargin:
+		return endStopTop
+	}
+
+	return endStopNone
+}
+
+func pickGesture(stop endStop, top string, bottom string) string {
+	if stop == endStopTop {
+		return top
+	}
+
+	return bottom
+}
@@ -0,0 +1,130 @@
+package deej
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// HardwareSettings are settings that live on the board itself rather than in deej. they're pushed to firmware
+// advertising the "config" capability whenever it connects and whenever they change, as comma-separated pairs:
+// "deej:v2.0:config:report_rate=50,led_brightness=128,smoothing.0=4,color.0=ff8800"
+type HardwareSettings struct {
+
+	// how many times per second the board reports slider positions
+	ReportRate int `json:"reportRate"`
+
+	// brightness of the board's LEDs or display, 0-255
+	LEDBrightness int `json:"ledBrightness"`
+
+	// slider index -> how many readings the firmware averages for that slider
+	Smoothing map[int]int `json:"smoothing"`
+
+	// slider index -> the color of that slider's RGB LEDs, as "#rrggbb"
+	Colors map[int]string `json:"colors"`
+}
+
+// settings left at this value aren't sent, so the firmware keeps its own defaults
+const hardwareSettingUnset = -1
+
+var ledColorPattern = regexp.MustCompile(`^#?[0-9a-fA-F]{6}$`)
+
+const (
+	hardwareKeyReportRate    = "report_rate"
+	hardwareKeyLEDBrightness = "led_brightness"
+	hardwareKeySmoothing     = "smoothing"
+
+	// colors are a map in the config, but go to the board one slider at a time like smoothing: "color.0=ff8800"
+	hardwareKeyColors = "colors"
+	hardwareKeyColor  = "color"
+
+	minReportRate    = 1
+	maxReportRate    = 1000
+	maxLEDBrightness = 255
+	maxSmoothing     = 32
+)
+
+func newHardwareSettings() HardwareSettings {
+	return HardwareSettings{
+		ReportRate:    hardwareSettingUnset,
+		LEDBrightness: hardwareSettingUnset,
+		Smoothing:     map[int]int{},
+		Colors:        map[int]string{},
+	}
+}
+
+// normalizeLEDColor turns a color written as "#RRGGBB" or "RRGGBB" into "#rrggbb", or returns false if it's neither
+func normalizeLEDColor(color string) (string, bool) {
+	color = strings.TrimSpace(color)
+	if !ledColorPattern.MatchString(color) {
+		return "", false
+	}
+
+	return "#" + strings.ToLower(strings.TrimPrefix(color, "#")), true
+}
+
+// validate returns an error describing the first setting that's out of range
+func (hs HardwareSettings) validate() error {
+	if hs.ReportRate != hardwareSettingUnset && (hs.ReportRate < minReportRate || hs.ReportRate > maxReportRate) {
+		return fmt.Errorf("%s must be between %d and %d: %w", hardwareKeyReportRate, minReportRate, maxReportRate, errInvalidConfig)
+	}
+
+	if hs.LEDBrightness != hardwareSettingUnset && (hs.LEDBrightness < 0 || hs.LEDBrightness > maxLEDBrightness) {
+		return fmt.Errorf("%s must be between 0 and %d: %w", hardwareKeyLEDBrightness, maxLEDBrightness, errInvalidConfig)
+	}
+
+	for sliderIdx, smoothing := range hs.Smoothing {
+		if sliderIdx < 0 || smoothing < 0 || smoothing > maxSmoothing {
+			return fmt.Errorf("%s for slider %d must be between 0 and %d: %w", hardwareKeySmoothing, sliderIdx, maxSmoothing, errInvalidConfig)
+		}
+	}
+
+	for sliderIdx, color := range hs.Colors {
+		if _, ok := normalizeLEDColor(color); sliderIdx < 0 || !ok {
+			return fmt.Errorf("%s for slider %d must look like \"#ff8800\": %w", hardwareKeyColor, sliderIdx, errInvalidConfig)
+		}
+	}
+
+	return nil
+}
+
+// payload formats the settings for a config message, or returns an empty string if none are set
+func (hs HardwareSettings) payload() string {
+	pairs := []string{}
+
+	if hs.ReportRate != hardwareSettingUnset {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", hardwareKeyReportRate, hs.ReportRate))
+	}
+
+	if hs.LEDBrightness != hardwareSettingUnset {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", hardwareKeyLEDBrightness, hs.LEDBrightness))
+	}
+
+	// keep the order stable, so that unchanged settings always produce the same payload
+	sliderIdxs := []int{}
+	for sliderIdx := range hs.Smoothing {
+		sliderIdxs = append(sliderIdxs, sliderIdx)
+	}
+
+	sort.Ints(sliderIdxs)
+
+	for _, sliderIdx := range sliderIdxs {
+		pairs = append(pairs, fmt.Sprintf("%s.%d=%d", hardwareKeySmoothing, sliderIdx, hs.Smoothing[sliderIdx]))
+	}
+
+	sliderIdxs = []int{}
+	for sliderIdx := range hs.Colors {
+		sliderIdxs = append(sliderIdxs, sliderIdx)
+	}
+
+	sort.Ints(sliderIdxs)
+
+	// the board gets bare hex digits, there's no need to make it skip the '#'
+	for _, sliderIdx := range sliderIdxs {
+		color, _ := normalizeLEDColor(hs.Colors[sliderIdx])
+		pairs = append(pairs, fmt.Sprintf("%s.%d=%s", hardwareKeyColor, sliderIdx, strings.TrimPrefix(color, "#")))
+	}
+
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,218 @@
+package deej
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+
+	// how long to wait for volumes to settle, since sessions are set from their own goroutines
+	testSettleTimeout = time.Second
+
+	// fake sessions start out at this volume, so fixtures can tell sessions deej left alone apart
+	fakeSessionInitialVolume = 0.42
+)
+
+type fakeNotifier struct{}
+
+func (fn *fakeNotifier) Notify(title string, message string) {}
+
+// fakeSession is an in-memory audio session that remembers whatever deej sets on it
+type fakeSession struct {
+	key string
+
+	volume float32
+	muted  bool
+	lock   sync.Mutex
+}
+
+func newFakeSession(key string) *fakeSession {
+	return &fakeSession{key: strings.ToLower(key), volume: fakeSessionInitialVolume}
+}
+
+func (fs *fakeSession) GetVolume() float32 {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	return fs.volume
+}
+
+func (fs *fakeSession) SetVolume(v float32) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	fs.volume = v
+	return nil
+}
+
+func (fs *fakeSession) GetMute() bool {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	return fs.muted
+}
+
+func (fs *fakeSession) SetMute(m bool) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	fs.muted = m
+	return nil
+}
+
+func (fs *fakeSession) IsAlive() bool {
+	return true
+}
+
+func (fs *fakeSession) Key() string {
+	return fs.key
+}
+
+func (fs *fakeSession) Release() {}
+
+// fakeSessionFinder always finds the same sessions
+type fakeSessionFinder struct {
+	sessions []Session
+}
+
+func (fsf *fakeSessionFinder) GetAllSessions() ([]Session, error) {
+	return fsf.sessions, nil
+}
+
+func (fsf *fakeSessionFinder) Release() error {
+	return nil
+}
+
+// testDeej is a deej instance without a device, tray or config file. lines fed to it go through
+// the same parsing as a real device's output, and end up on its fake sessions
+type testDeej struct {
+	*Deej
+
+	// session key -> every fake session with that key
+	sessions map[string][]*fakeSession
+
+	// a second subscriber to slider moves, to see which ones the serial side emitted
+	moves chan SliderMoveEvent
+}
+
+func newTestConfig(t testing.TB, configYAML string) *CanonicalConfig {
+	t.Helper()
+
+	logger := zap.NewNop().Sugar()
+
+	config, err := NewConfig(logger, &fakeNotifier{})
+	if err != nil {
+		t.Fatalf("create config: %v", err)
+	}
+
+	userConfig := newUserConfigViper()
+	if err := userConfig.ReadConfig(strings.NewReader(configYAML)); err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+
+	if err := validateUserConfig(userConfig); err != nil {
+		t.Fatalf("validate config: %v", err)
+	}
+
+	if err := config.apply(userConfig); err != nil {
+		t.Fatalf("apply config: %v", err)
+	}
+
+	return config
+}
+
+func newTestDeej(t testing.TB, configYAML string, sessionKeys ...string) *testDeej {
+	t.Helper()
+
+	logger := zap.NewNop().Sugar()
+
+	d := &Deej{
+		logger:      logger,
+		notifier:    &fakeNotifier{},
+		config:      newTestConfig(t, configYAML),
+		stopChannel: make(chan bool),
+	}
+
+	serial, err := NewSerialIO(d, logger)
+	if err != nil {
+		t.Fatalf("create serial i/o: %v", err)
+	}
+
+	d.serial = serial
+
+	td := &testDeej{
+		Deej:     d,
+		sessions: map[string][]*fakeSession{},
+		moves:    serial.SubscribeToSliderMoveEvents("test"),
+	}
+
+	finder := &fakeSessionFinder{}
+	for _, key := range sessionKeys {
+		session := newFakeSession(key)
+		td.sessions[session.Key()] = append(td.sessions[session.Key()], session)
+		finder.sessions = append(finder.sessions, session)
+	}
+
+	sessions, err := newSessionMap(d, logger, finder)
+	if err != nil {
+		t.Fatalf("create session map: %v", err)
+	}
+
+	d.sessions = sessions
+
+	if err := sessions.initialize(); err != nil {
+		t.Fatalf("initialize session map: %v", err)
+	}
+
+	return td
+}
+
+// feed passes a line through serial handling as if the device sent it, and returns the slider moves it caused
+func (td *testDeej) feed(line string) []SliderMoveEvent {
+	td.serial.handleLine(td.serial.logger, line)
+
+	moves := []SliderMoveEvent{}
+	for {
+		select {
+		case move := <-td.moves:
+			moves = append(moves, move)
+		default:
+			return moves
+		}
+	}
+}
+
+// expectVolumes waits for every session with each of the given keys to reach its expected volume
+func (td *testDeej) expectVolumes(t *testing.T, expected map[string]float32) {
+	t.Helper()
+
+	deadline := time.Now().Add(testSettleTimeout)
+
+	for key, volume := range expected {
+		sessions, ok := td.sessions[strings.ToLower(key)]
+		if !ok {
+			t.Fatalf("no fake session with key %q", key)
+		}
+
+		for _, session := range sessions {
+			for !volumesEqual(session.GetVolume(), volume) && time.Now().Before(deadline) {
+				time.Sleep(5 * time.Millisecond)
+			}
+
+			if actual := session.GetVolume(); !volumesEqual(actual, volume) {
+				t.Errorf("%s: expected volume %.3f, got %.3f", key, volume, actual)
+			}
+		}
+	}
+}
+
+func volumesEqual(a float32, b float32) bool {
+	const tolerance = 0.001
+
+	return a-b < tolerance && b-a < tolerance
+}
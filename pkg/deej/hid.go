@@ -0,0 +1,87 @@
+package deej
+
+import (
+	"bytes"
+	"errors"
+)
+
+// boards that present themselves as a USB HID device (instead of a CDC serial port) speak the same line-based
+// protocol, just chopped up into reports. every report carries the next chunk of text, padded with NUL bytes,
+// without a report ID. commands sent to the device are chunked the same way
+const (
+	connectionTypeSerial = "serial"
+	connectionTypeHID    = "hid"
+
+	// the report size used by boards that don't tell us otherwise (full-speed USB's maximum)
+	defaultHIDReportSize = 64
+)
+
+var errHIDDeviceNotFound = errors.New("no matching HID device found")
+
+// hidReportDevice is the platform-specific part of a HID connection
+type hidReportDevice interface {
+
+	// readReport blocks until the device sends an input report, and returns its payload
+	readReport() ([]byte, error)
+
+	// writeReport sends a single output report. payload is at most outputReportSize() bytes long
+	writeReport(payload []byte) error
+
+	outputReportSize() int
+	close() error
+}
+
+// hidConn turns a HID device's reports back into the byte stream the serial protocol expects
+type hidConn struct {
+	device  hidReportDevice
+	pending []byte
+}
+
+func newHIDConn(device hidReportDevice) *hidConn {
+	return &hidConn{device: device}
+}
+
+func (c *hidConn) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		report, err := c.device.readReport()
+		if err != nil {
+			return 0, err
+		}
+
+		// drop the padding
+		if end := bytes.IndexByte(report, 0); end >= 0 {
+			report = report[:end]
+		}
+
+		c.pending = report
+	}
+
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+
+	return n, nil
+}
+
+func (c *hidConn) Write(p []byte) (int, error) {
+	reportSize := c.device.outputReportSize()
+	written := 0
+
+	for written < len(p) {
+		end := written + reportSize
+		if end > len(p) {
+			end = len(p)
+		}
+
+		if err := c.device.writeReport(p[written:end]); err != nil {
+			return written, err
+		}
+
+		written = end
+	}
+
+	return written, nil
+}
+
+func (c *hidConn) Close() error {
+	return c.device.close()
+}
@@ -0,0 +1,91 @@
+package deej
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const hidrawClassPath = "/sys/class/hidraw"
+
+type hidrawDevice struct {
+	file *os.File
+}
+
+// openHIDDevice finds the first hidraw node belonging to a device with the given vendor and product IDs
+func openHIDDevice(vendorID uint16, productID uint16) (*hidConn, string, error) {
+	entries, err := ioutil.ReadDir(hidrawClassPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("list hidraw devices: %w", err)
+	}
+
+	// the kernel describes each node's device as "HID_ID=<bus>:<vendor>:<product>", all in hex
+	wantedID := fmt.Sprintf(":%08X:%08X", vendorID, productID)
+
+	for _, entry := range entries {
+		uevent, err := ioutil.ReadFile(filepath.Join(hidrawClassPath, entry.Name(), "device", "uevent"))
+		if err != nil {
+			continue
+		}
+
+		if !hidUeventMatches(string(uevent), wantedID) {
+			continue
+		}
+
+		devicePath := "/dev/" + entry.Name()
+
+		file, err := os.OpenFile(devicePath, os.O_RDWR, 0)
+		if err != nil {
+			return nil, devicePath, fmt.Errorf("open %s: %w", devicePath, err)
+		}
+
+		return newHIDConn(&hidrawDevice{file: file}), devicePath, nil
+	}
+
+	return nil, "", fmt.Errorf("find %04x:%04x: %w", vendorID, productID, errHIDDeviceNotFound)
+}
+
+func hidUeventMatches(uevent string, wantedID string) bool {
+	for _, line := range strings.Split(uevent, "\n") {
+		if strings.HasPrefix(line, "HID_ID=") && strings.HasSuffix(strings.ToUpper(line), wantedID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isHIDPort returns true for port names that refer to HID devices rather than serial ports
+func isHIDPort(portName string) bool {
+	return strings.HasPrefix(portName, "/dev/hidraw")
+}
+
+func (d *hidrawDevice) readReport() ([]byte, error) {
+	buf := make([]byte, defaultHIDReportSize)
+
+	n, err := d.file.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf[:n], nil
+}
+
+// hidraw expects the report ID as the first byte, even for devices that don't number their reports
+func (d *hidrawDevice) writeReport(payload []byte) error {
+	report := make([]byte, defaultHIDReportSize+1)
+	copy(report[1:], payload)
+
+	_, err := d.file.Write(report)
+	return err
+}
+
+func (d *hidrawDevice) outputReportSize() int {
+	return defaultHIDReportSize
+}
+
+func (d *hidrawDevice) close() error {
+	return d.file.Close()
+}
@@ -0,0 +1,156 @@
+package deej
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// GUID_DEVINTERFACE_HID
+var hidInterfaceClassGUID = windows.GUID{
+	Data1: 0x4D1E55B2,
+	Data2: 0xF16F,
+	Data3: 0x11CF,
+	Data4: [8]byte{0x88, 0xCB, 0x00, 0x11, 0x11, 0x00, 0x00, 0x30},
+}
+
+var (
+	hidDLL                    = windows.NewLazySystemDLL("hid.dll")
+	procHidDGetPreparsedData  = hidDLL.NewProc("HidD_GetPreparsedData")
+	procHidDFreePreparsedData = hidDLL.NewProc("HidD_FreePreparsedData")
+	procHidPGetCaps           = hidDLL.NewProc("HidP_GetCaps")
+)
+
+const (
+	hidpStatusSuccess = 0x00110000
+
+	// device interface paths look like \\?\hid#vid_2e8a&pid_000a#...
+	hidDeviceInterfaceIDFormat = "vid_%04x&pid_%04x"
+)
+
+// HIDP_CAPS
+type hidpCaps struct {
+	Usage                     uint16
+	UsagePage                 uint16
+	InputReportByteLength     uint16
+	OutputReportByteLength    uint16
+	FeatureReportByteLength   uint16
+	Reserved                  [17]uint16
+	NumberLinkCollectionNodes [10]uint16
+}
+
+type windowsHIDDevice struct {
+	handle windows.Handle
+
+	// both include the leading report ID byte
+	inputReportLength  int
+	outputReportLength int
+}
+
+// openHIDDevice finds the first HID interface belonging to a device with the given vendor and product IDs
+func openHIDDevice(vendorID uint16, productID uint16) (*hidConn, string, error) {
+	interfaces, err := windows.CM_Get_Device_Interface_List("", &hidInterfaceClassGUID, windows.CM_GET_DEVICE_INTERFACE_LIST_PRESENT)
+	if err != nil {
+		return nil, "", fmt.Errorf("list HID devices: %w", err)
+	}
+
+	wantedID := fmt.Sprintf(hidDeviceInterfaceIDFormat, vendorID, productID)
+
+	for _, devicePath := range interfaces {
+		if !strings.Contains(strings.ToLower(devicePath), wantedID) {
+			continue
+		}
+
+		pathPtr, err := windows.UTF16PtrFromString(devicePath)
+		if err != nil {
+			continue
+		}
+
+		handle, err := windows.CreateFile(pathPtr,
+			windows.GENERIC_READ|windows.GENERIC_WRITE,
+			windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE,
+			nil,
+			windows.OPEN_EXISTING,
+			0,
+			0)
+
+		// composite devices expose several interfaces, some of which (keyboards, mice) windows keeps to itself
+		if err != nil {
+			continue
+		}
+
+		device := &windowsHIDDevice{handle: handle}
+		if err := device.readCaps(); err != nil {
+			windows.CloseHandle(handle)
+			continue
+		}
+
+		return newHIDConn(device), devicePath, nil
+	}
+
+	return nil, "", fmt.Errorf("find %04x:%04x: %w", vendorID, productID, errHIDDeviceNotFound)
+}
+
+// isHIDPort returns true for port names that refer to HID devices rather than serial ports
+func isHIDPort(portName string) bool {
+	return strings.HasPrefix(strings.ToLower(portName), `\\?\hid#`)
+}
+
+func (d *windowsHIDDevice) readCaps() error {
+	var preparsedData uintptr
+
+	if ok, _, err := procHidDGetPreparsedData.Call(uintptr(d.handle), uintptr(unsafe.Pointer(&preparsedData))); ok == 0 {
+		return fmt.Errorf("get preparsed data: %w", err)
+	}
+	defer procHidDFreePreparsedData.Call(preparsedData)
+
+	caps := hidpCaps{}
+	if status, _, _ := procHidPGetCaps.Call(preparsedData, uintptr(unsafe.Pointer(&caps))); status != hidpStatusSuccess {
+		return fmt.Errorf("get caps: status %x", status)
+	}
+
+	if caps.InputReportByteLength == 0 || caps.OutputReportByteLength < 2 {
+		return fmt.Errorf("device has no usable input/output reports")
+	}
+
+	d.inputReportLength = int(caps.InputReportByteLength)
+	d.outputReportLength = int(caps.OutputReportByteLength)
+
+	return nil
+}
+
+func (d *windowsHIDDevice) readReport() ([]byte, error) {
+	buf := make([]byte, d.inputReportLength)
+
+	var read uint32
+	if err := windows.ReadFile(d.handle, buf, &read, nil); err != nil {
+		return nil, err
+	}
+
+	// skip the report ID
+	if read == 0 {
+		return nil, nil
+	}
+
+	return buf[1:read], nil
+}
+
+func (d *windowsHIDDevice) writeReport(payload []byte) error {
+
+	// windows wants every report at its full length, starting with the report ID
+	report := make([]byte, d.outputReportLength)
+	copy(report[1:], payload)
+
+	var written uint32
+	return windows.WriteFile(d.handle, report, &written, nil)
+}
+
+func (d *windowsHIDDevice) outputReportSize() int {
+	return d.outputReportLength - 1
+}
+
+func (d *windowsHIDDevice) close() error {
+	return windows.CloseHandle(d.handle)
+}
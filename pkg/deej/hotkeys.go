@@ -0,0 +1,247 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/thoas/go-funk"
+	"go.uber.org/zap"
+)
+
+// hotkeys trigger the same actions as buttons and gestures from the keyboard, for boards without buttons.
+// they're written like "ctrl+alt+m", with any number of modifiers (in any order) and a single key
+const (
+	hotkeyModifierAlt   = "alt"
+	hotkeyModifierCtrl  = "ctrl"
+	hotkeyModifierShift = "shift"
+	hotkeyModifierSuper = "super"
+
+	// events are delivered from the platform's listener, which must never block
+	hotkeyEventBufferSize = 16
+)
+
+var hotkeyModifierAliases = map[string]string{
+	"alt":     hotkeyModifierAlt,
+	"ctrl":    hotkeyModifierCtrl,
+	"control": hotkeyModifierCtrl,
+	"shift":   hotkeyModifierShift,
+	"super":   hotkeyModifierSuper,
+	"win":     hotkeyModifierSuper,
+	"meta":    hotkeyModifierSuper,
+}
+
+// keys other than letters, digits and function keys
+var namedHotkeyKeys = []string{
+	"space", "enter", "tab", "escape", "backspace",
+	"insert", "delete", "home", "end", "pageup", "pagedown",
+	"up", "down", "left", "right",
+}
+
+var functionHotkeyKeyPattern = regexp.MustCompile(`^f([1-9]|1[0-9]|2[0-4])$`)
+
+var errInvalidHotkey = errors.New("invalid hotkey")
+
+// hotkey is a parsed key combination
+type hotkey struct {
+	modifiers []string // sorted, without duplicates
+	key       string
+}
+
+// parseHotkey parses hotkeys like "ctrl+alt+m" or "Super+Shift+F5"
+func parseHotkey(spec string) (hotkey, error) {
+	parts := strings.Split(strings.ToLower(strings.Join(strings.Fields(spec), "")), "+")
+	result := hotkey{}
+
+	for _, part := range parts[:len(parts)-1] {
+		modifier, ok := hotkeyModifierAliases[part]
+		if !ok {
+			return hotkey{}, fmt.Errorf("%q: unknown modifier %q: %w", spec, part, errInvalidHotkey)
+		}
+
+		if !funk.ContainsString(result.modifiers, modifier) {
+			result.modifiers = append(result.modifiers, modifier)
+		}
+	}
+
+	if len(result.modifiers) == 0 {
+		return hotkey{}, fmt.Errorf("%q: needs at least one modifier: %w", spec, errInvalidHotkey)
+	}
+
+	sort.Strings(result.modifiers)
+
+	key := parts[len(parts)-1]
+	if !validHotkeyKey(key) {
+		return hotkey{}, fmt.Errorf("%q: unknown key %q: %w", spec, key, errInvalidHotkey)
+	}
+
+	result.key = key
+
+	return result, nil
+}
+
+func validHotkeyKey(key string) bool {
+	if len(key) == 1 && (key[0] >= 'a' && key[0] <= 'z' || key[0] >= '0' && key[0] <= '9') {
+		return true
+	}
+
+	return functionHotkeyKeyPattern.MatchString(key) || funk.ContainsString(namedHotkeyKeys, key)
+}
+
+func (h hotkey) String() string {
+	return strings.Join(append(append([]string{}, h.modifiers...), h.key), "+")
+}
+
+func (h hotkey) hasModifier(modifier string) bool {
+	return funk.ContainsString(h.modifiers, modifier)
+}
+
+// hotkeyListener represents a platform-specific source of global hotkey presses
+type hotkeyListener interface {
+
+	// Events receives the index of each pressed hotkey, in the order they were given to newHotkeyListener
+	Events() <-chan int
+	Close() error
+}
+
+// hotkeyManager keeps the configured hotkeys registered, and performs their actions
+type hotkeyManager struct {
+	deej   *Deej
+	logger *zap.SugaredLogger
+
+	// the hotkeys currently registered, and the listener they're registered with
+	registered map[string]string
+	listener   hotkeyListener
+	lock       sync.Mutex
+
+	// bumped whenever the registered hotkeys change or they're released, so a listener that took a while to
+	// create (see register) can tell whether it's still wanted
+	generation int
+}
+
+func newHotkeyManager(deej *Deej, logger *zap.SugaredLogger) *hotkeyManager {
+	logger = logger.Named("hotkeys")
+
+	hm := &hotkeyManager{
+		deej:       deej,
+		logger:     logger,
+		registered: map[string]string{},
+	}
+
+	logger.Debug("Created hotkey manager instance")
+
+	return hm
+}
+
+func (hm *hotkeyManager) initialize() {
+
+	// registering can wait on the desktop asking the user to confirm, which startup shouldn't
+	go hm.register(hm.deej.config.Hotkeys)
+
+	configReloadedChannel := hm.deej.config.SubscribeToChanges()
+	go func() {
+		for range configReloadedChannel {
+			hm.register(hm.deej.config.Hotkeys)
+		}
+	}()
+}
+
+// register replaces the registered hotkeys with the given ones (hotkey -> action), unless they're the same.
+// creating the listener happens outside the lock, since it can take a while on some platforms
+func (hm *hotkeyManager) register(hotkeys map[string]string) {
+	hm.lock.Lock()
+
+	if reflect.DeepEqual(hotkeys, hm.registered) {
+		hm.lock.Unlock()
+		return
+	}
+
+	previous := hm.takeListener()
+	hm.registered = hotkeys
+	generation := hm.generation
+
+	hm.lock.Unlock()
+
+	hm.closeListener(previous)
+
+	if len(hotkeys) == 0 {
+		return
+	}
+
+	specs := make([]string, 0, len(hotkeys))
+	for spec := range hotkeys {
+		specs = append(specs, spec)
+	}
+
+	sort.Strings(specs)
+
+	parsed := make([]hotkey, len(specs))
+	for idx, spec := range specs {
+
+		// the config only keeps hotkeys that parse
+		parsed[idx], _ = parseHotkey(spec)
+	}
+
+	listener, err := newHotkeyListener(hm.logger, parsed)
+	if err != nil {
+		hm.logger.Warnw("Failed to register hotkeys", "error", err)
+		return
+	}
+
+	hm.lock.Lock()
+	current := hm.generation == generation
+	if current {
+		hm.listener = listener
+	}
+	hm.lock.Unlock()
+
+	// the hotkeys changed (or were released) while this listener was being created
+	if !current {
+		hm.closeListener(listener)
+		return
+	}
+
+	hm.logger.Infow("Registered hotkeys", "hotkeys", specs)
+
+	go func() {
+		for idx := range listener.Events() {
+			action := hotkeys[specs[idx]]
+
+			if err := hm.deej.performAction(action, noSliderID); err != nil {
+				hm.logger.Warnw("Failed to perform hotkey action", "hotkey", specs[idx], "action", action, "error", err)
+			}
+		}
+	}()
+}
+
+func (hm *hotkeyManager) release() {
+	hm.lock.Lock()
+	listener := hm.takeListener()
+	hm.lock.Unlock()
+
+	hm.closeListener(listener)
+}
+
+// takeListener hands over the current listener (if any) to be closed. the lock must be held
+func (hm *hotkeyManager) takeListener() hotkeyListener {
+	listener := hm.listener
+
+	hm.listener = nil
+	hm.generation++
+
+	return listener
+}
+
+func (hm *hotkeyManager) closeListener(listener hotkeyListener) {
+	if listener == nil {
+		return
+	}
+
+	if err := listener.Close(); err != nil {
+		hm.logger.Warnw("Failed to unregister hotkeys", "error", err)
+	}
+}
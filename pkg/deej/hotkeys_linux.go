@@ -0,0 +1,241 @@
+package deej
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"go.uber.org/zap"
+)
+
+// x11 has no way to grab keys without a display connection, and wayland none at all, so hotkeys go through
+// the desktop portal's GlobalShortcuts interface. the desktop may ask the user to confirm them the first time
+const (
+	portalBusName    = "org.freedesktop.portal.Desktop"
+	portalObjectPath = "/org/freedesktop/portal/desktop"
+
+	portalRequestInterface   = "org.freedesktop.portal.Request"
+	portalSessionInterface   = "org.freedesktop.portal.Session"
+	portalShortcutsInterface = "org.freedesktop.portal.GlobalShortcuts"
+
+	portalHandleToken = "deej_hotkeys"
+
+	hotkeyShortcutIDPrefix = "deej-hotkey-"
+
+	// how long to wait for the portal to answer a request, including the user confirming the shortcuts
+	portalRequestTimeout = time.Minute
+)
+
+// xdg shortcut names for keys whose name differs from deej's, see xkbcommon's keysym names
+var portalKeyNames = map[string]string{
+	"enter":     "Return",
+	"tab":       "Tab",
+	"escape":    "Escape",
+	"backspace": "BackSpace",
+	"insert":    "Insert",
+	"delete":    "Delete",
+	"home":      "Home",
+	"end":       "End",
+	"pageup":    "Page_Up",
+	"pagedown":  "Page_Down",
+	"up":        "Up",
+	"down":      "Down",
+	"left":      "Left",
+	"right":     "Right",
+}
+
+var portalModifierNames = map[string]string{
+	hotkeyModifierAlt:   "ALT",
+	hotkeyModifierCtrl:  "CTRL",
+	hotkeyModifierShift: "SHIFT",
+	hotkeyModifierSuper: "LOGO",
+}
+
+// portalShortcut mirrors the (sa{sv}) shortcuts BindShortcuts takes
+type portalShortcut struct {
+	ID         string
+	Properties map[string]dbus.Variant
+}
+
+type portalHotkeyListener struct {
+	logger *zap.SugaredLogger
+
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+	signals chan *dbus.Signal
+	events  chan int
+}
+
+func newHotkeyListener(logger *zap.SugaredLogger, hotkeys []hotkey) (hotkeyListener, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("connect to session bus: %w", err)
+	}
+
+	l := &portalHotkeyListener{
+		logger:  logger,
+		conn:    conn,
+		signals: make(chan *dbus.Signal, hotkeyEventBufferSize),
+		events:  make(chan int, hotkeyEventBufferSize),
+	}
+
+	if err := l.bind(hotkeys); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go l.forwardActivations()
+
+	return l, nil
+}
+
+func (l *portalHotkeyListener) Events() <-chan int {
+	return l.events
+}
+
+func (l *portalHotkeyListener) Close() error {
+	l.conn.Object(portalBusName, l.session).Call(portalSessionInterface+".Close", 0)
+
+	// closing the connection closes the signal channel, which ends forwardActivations
+	if err := l.conn.Close(); err != nil {
+		return fmt.Errorf("close session bus connection: %w", err)
+	}
+
+	return nil
+}
+
+// bind creates a portal session and binds the hotkeys to it
+func (l *portalHotkeyListener) bind(hotkeys []hotkey) error {
+	for _, option := range [][]dbus.MatchOption{
+		{dbus.WithMatchInterface(portalRequestInterface), dbus.WithMatchMember("Response")},
+		{dbus.WithMatchInterface(portalShortcutsInterface), dbus.WithMatchMember("Activated")},
+	} {
+		if err := l.conn.AddMatchSignal(option...); err != nil {
+			return fmt.Errorf("subscribe to portal signals: %w", err)
+		}
+	}
+
+	l.conn.Signal(l.signals)
+
+	portal := l.conn.Object(portalBusName, portalObjectPath)
+
+	results, err := l.request(portal, "CreateSession", map[string]dbus.Variant{
+		"handle_token":         dbus.MakeVariant(portalHandleToken),
+		"session_handle_token": dbus.MakeVariant(portalHandleToken),
+	})
+	if err != nil {
+		return fmt.Errorf("create global shortcuts session: %w", err)
+	}
+
+	sessionHandle, ok := results["session_handle"].Value().(string)
+	if !ok {
+		return fmt.Errorf("create global shortcuts session: no session handle in response")
+	}
+
+	l.session = dbus.ObjectPath(sessionHandle)
+
+	shortcuts := make([]portalShortcut, len(hotkeys))
+	for idx, h := range hotkeys {
+		shortcuts[idx] = portalShortcut{
+			ID: hotkeyShortcutIDPrefix + strconv.Itoa(idx),
+			Properties: map[string]dbus.Variant{
+				"description":       dbus.MakeVariant(fmt.Sprintf("deej hotkey %s", h)),
+				"preferred_trigger": dbus.MakeVariant(portalTrigger(h)),
+			},
+		}
+	}
+
+	if _, err := l.request(portal, "BindShortcuts", l.session, shortcuts, "", map[string]dbus.Variant{
+		"handle_token": dbus.MakeVariant(portalHandleToken),
+	}); err != nil {
+		return fmt.Errorf("bind global shortcuts: %w", err)
+	}
+
+	return nil
+}
+
+// request calls a portal method that answers through a Request object, and waits for its response
+func (l *portalHotkeyListener) request(portal dbus.BusObject, method string, args ...interface{}) (map[string]dbus.Variant, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), portalRequestTimeout)
+	defer cancel()
+
+	var requestPath dbus.ObjectPath
+	call := portal.CallWithContext(ctx, portalShortcutsInterface+"."+method, 0, args...)
+	if err := call.Store(&requestPath); err != nil {
+		return nil, fmt.Errorf("call %s: %w", method, err)
+	}
+
+	for {
+		var signal *dbus.Signal
+
+		select {
+		case signal = <-l.signals:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%s: no response from the desktop portal: %w", method, ctx.Err())
+		}
+
+		if signal == nil {
+			return nil, fmt.Errorf("%s: connection closed while waiting for response", method)
+		}
+
+		if signal.Path != requestPath || signal.Name != portalRequestInterface+".Response" || len(signal.Body) < 2 {
+			continue
+		}
+
+		if response, _ := signal.Body[0].(uint32); response != 0 {
+			return nil, fmt.Errorf("%s was denied or cancelled (response %d)", method, response)
+		}
+
+		results, _ := signal.Body[1].(map[string]dbus.Variant)
+		return results, nil
+	}
+}
+
+func (l *portalHotkeyListener) forwardActivations() {
+	defer close(l.events)
+
+	for signal := range l.signals {
+		if signal.Name != portalShortcutsInterface+".Activated" || len(signal.Body) < 2 {
+			continue
+		}
+
+		if session, _ := signal.Body[0].(dbus.ObjectPath); session != l.session {
+			continue
+		}
+
+		shortcutID, _ := signal.Body[1].(string)
+
+		idx, err := strconv.Atoi(strings.TrimPrefix(shortcutID, hotkeyShortcutIDPrefix))
+		if err != nil {
+			continue
+		}
+
+		select {
+		case l.events <- idx:
+		default:
+			l.logger.Warnw("Hotkey event channel full, dropping event", "shortcut", shortcutID)
+		}
+	}
+
+	l.logger.Debug("Stopped listening for global shortcuts")
+}
+
+// portalTrigger formats a hotkey the way the shortcuts spec wants it, e.g. "CTRL+ALT+m"
+func portalTrigger(h hotkey) string {
+	parts := []string{}
+	for _, modifier := range h.modifiers {
+		parts = append(parts, portalModifierNames[modifier])
+	}
+
+	key := h.key
+	if name, ok := portalKeyNames[key]; ok {
+		key = name
+	} else if functionHotkeyKeyPattern.MatchString(key) {
+		key = strings.ToUpper(key)
+	}
+
+	return strings.Join(append(parts, key), "+")
+}
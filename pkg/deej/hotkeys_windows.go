@@ -0,0 +1,171 @@
+package deej
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/lxn/win"
+	"go.uber.org/zap"
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32DLL             = windows.NewLazySystemDLL("user32.dll")
+	procRegisterHotKey    = user32DLL.NewProc("RegisterHotKey")
+	procUnregisterHotKey  = user32DLL.NewProc("UnregisterHotKey")
+	procPostThreadMessage = user32DLL.NewProc("PostThreadMessageW")
+)
+
+// from winuser.h
+const (
+	modAlt      = 0x0001
+	modControl  = 0x0002
+	modShift    = 0x0004
+	modWin      = 0x0008
+	modNoRepeat = 0x4000
+)
+
+var hotkeyVirtualKeys = map[string]uint32{
+	"space":     0x20,
+	"enter":     0x0D,
+	"tab":       0x09,
+	"escape":    0x1B,
+	"backspace": 0x08,
+	"insert":    0x2D,
+	"delete":    0x2E,
+	"home":      0x24,
+	"end":       0x23,
+	"pageup":    0x21,
+	"pagedown":  0x22,
+	"left":      0x25,
+	"up":        0x26,
+	"right":     0x27,
+	"down":      0x28,
+}
+
+// registeredHotkeyListener registers hotkeys with RegisterHotKey, which posts WM_HOTKEY to the registering thread
+type registeredHotkeyListener struct {
+	logger *zap.SugaredLogger
+
+	threadID uint32
+	events   chan int
+}
+
+func newHotkeyListener(logger *zap.SugaredLogger, hotkeys []hotkey) (hotkeyListener, error) {
+	l := &registeredHotkeyListener{
+		logger: logger,
+		events: make(chan int, hotkeyEventBufferSize),
+	}
+
+	ready := make(chan error)
+	go l.runMessageLoop(hotkeys, ready)
+
+	if err := <-ready; err != nil {
+		return nil, fmt.Errorf("register hotkeys: %w", err)
+	}
+
+	return l, nil
+}
+
+func (l *registeredHotkeyListener) Events() <-chan int {
+	return l.events
+}
+
+func (l *registeredHotkeyListener) Close() error {
+	if ret, _, err := procPostThreadMessage.Call(uintptr(l.threadID), win.WM_QUIT, 0, 0); ret == 0 {
+		return fmt.Errorf("stop hotkey message loop: %w", err)
+	}
+
+	return nil
+}
+
+func (l *registeredHotkeyListener) runMessageLoop(hotkeys []hotkey, ready chan error) {
+
+	// hotkey messages go to the thread that registered them, so we can't move around
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(l.events)
+
+	l.threadID = win.GetCurrentThreadId()
+
+	registered := 0
+
+	for idx, h := range hotkeys {
+
+		// ids start at 1, and map back to the hotkey's index
+		id := uintptr(idx + 1)
+
+		if ret, _, err := procRegisterHotKey.Call(0, id, uintptr(hotkeyModifiers(h)), uintptr(hotkeyVirtualKey(h.key))); ret == 0 {
+			l.logger.Warnw("Failed to register hotkey, another app might be using it", "hotkey", h, "error", err)
+			continue
+		}
+
+		defer procUnregisterHotKey.Call(0, id)
+		registered++
+	}
+
+	if registered == 0 && len(hotkeys) > 0 {
+		ready <- fmt.Errorf("none of the %d hotkeys could be registered", len(hotkeys))
+		return
+	}
+
+	ready <- nil
+
+	var msg win.MSG
+	for win.GetMessage(&msg, 0, 0, 0) > 0 {
+		if msg.Message != win.WM_HOTKEY {
+			continue
+		}
+
+		// don't block the message loop on a slow consumer
+		select {
+		case l.events <- int(msg.WParam) - 1:
+		default:
+			l.logger.Warnw("Hotkey event channel full, dropping event", "id", msg.WParam)
+		}
+	}
+
+	l.logger.Debug("Hotkey message loop ended")
+}
+
+func hotkeyModifiers(h hotkey) uint32 {
+
+	// holding a hotkey down shouldn't repeat its action
+	modifiers := uint32(modNoRepeat)
+
+	if h.hasModifier(hotkeyModifierAlt) {
+		modifiers |= modAlt
+	}
+
+	if h.hasModifier(hotkeyModifierCtrl) {
+		modifiers |= modControl
+	}
+
+	if h.hasModifier(hotkeyModifierShift) {
+		modifiers |= modShift
+	}
+
+	if h.hasModifier(hotkeyModifierSuper) {
+		modifiers |= modWin
+	}
+
+	return modifiers
+}
+
+func hotkeyVirtualKey(key string) uint32 {
+	if vk, ok := hotkeyVirtualKeys[key]; ok {
+		return vk
+	}
+
+	// function keys start at VK_F1
+	if functionHotkeyKeyPattern.MatchString(key) {
+		var number uint32
+		fmt.Sscanf(key, "f%d", &number)
+
+		return 0x70 + number - 1
+	}
+
+	// letters and digits are their own (uppercase) ASCII codes
+	return uint32(strings.ToUpper(key)[0])
+}
@@ -0,0 +1,40 @@
+package deej
+
+import (
+	"strings"
+)
+
+// hotplugAction describes what happened to a serial device
+type hotplugAction int
+
+const (
+	hotplugAdded hotplugAction = iota
+	hotplugRemoved
+)
+
+// hotplugEvent is emitted by a hotplugWatcher whenever a serial device appears or disappears
+type hotplugEvent struct {
+	Action hotplugAction
+
+	// the device's port name as it would appear in the config, e.g. "/dev/ttyACM0" or "COM4"
+	PortName string
+}
+
+// hotplugWatcher represents a platform-specific source of serial device arrival/removal notifications
+type hotplugWatcher interface {
+	Events() <-chan hotplugEvent
+	Close() error
+}
+
+func (a hotplugAction) String() string {
+	if a == hotplugAdded {
+		return "added"
+	}
+
+	return "removed"
+}
+
+// samePort compares two port names the way the current platform would (COM ports are case-insensitive)
+func samePort(a string, b string) bool {
+	return strings.EqualFold(a, b)
+}
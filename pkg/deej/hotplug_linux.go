@@ -0,0 +1,167 @@
+package deej
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+const (
+
+	// the kernel broadcasts uevents on multicast group 1 of NETLINK_KOBJECT_UEVENT
+	kernelUeventGroup = 1
+
+	// large enough for any single uevent datagram
+	ueventBufferSize = 8192
+)
+
+type udevWatcher struct {
+	logger *zap.SugaredLogger
+
+	socket *os.File
+	events chan hotplugEvent
+
+	// closed by Close, so readEvents doesn't wait forever on a send nobody's receiving anymore
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newHotplugWatcher(logger *zap.SugaredLogger) (hotplugWatcher, error) {
+	logger = logger.Named("hotplug")
+
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, syscall.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		logger.Warnw("Failed to open netlink socket", "error", err)
+		return nil, fmt.Errorf("open netlink socket: %w", err)
+	}
+
+	address := &syscall.SockaddrNetlink{
+		Family: syscall.AF_NETLINK,
+		Groups: kernelUeventGroup,
+	}
+
+	if err := syscall.Bind(fd, address); err != nil {
+		syscall.Close(fd)
+
+		logger.Warnw("Failed to bind netlink socket", "error", err)
+		return nil, fmt.Errorf("bind netlink socket: %w", err)
+	}
+
+	// a non-blocking descriptor lets the runtime poller handle it, which in turn allows Close to interrupt reads
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		syscall.Close(fd)
+
+		logger.Warnw("Failed to set netlink socket to non-blocking mode", "error", err)
+		return nil, fmt.Errorf("set netlink socket non-blocking: %w", err)
+	}
+
+	w := &udevWatcher{
+		logger: logger,
+		socket: os.NewFile(uintptr(fd), "uevent"),
+		events: make(chan hotplugEvent),
+		done:   make(chan struct{}),
+	}
+
+	go w.readEvents()
+
+	logger.Debug("Created udev hotplug watcher")
+
+	return w, nil
+}
+
+func (w *udevWatcher) Events() <-chan hotplugEvent {
+	return w.events
+}
+
+func (w *udevWatcher) Close() error {
+	var err error
+
+	w.closeOnce.Do(func() {
+		close(w.done)
+		err = w.socket.Close()
+	})
+
+	if err != nil {
+		w.logger.Warnw("Failed to close netlink socket", "error", err)
+		return fmt.Errorf("close netlink socket: %w", err)
+	}
+
+	return nil
+}
+
+func (w *udevWatcher) readEvents() {
+	defer close(w.events)
+
+	buf := make([]byte, ueventBufferSize)
+
+	for {
+		n, err := w.socket.Read(buf)
+		if err != nil {
+			w.logger.Debugw("Stopped reading uevents", "error", err)
+			return
+		}
+
+		event, ok := parseUevent(buf[:n])
+		if !ok {
+			continue
+		}
+
+		w.logger.Debugw("Serial device hotplug event", "action", event.Action, "port", event.PortName)
+
+		select {
+		case w.events <- event:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// parseUevent extracts a hotplugEvent from a raw kernel uevent, which looks like
+// "add@/devices/...\0ACTION=add\0SUBSYSTEM=tty\0DEVNAME=ttyACM0\0..."
+// only tty devices that look like USB serial adapters are of interest to us
+func parseUevent(raw []byte) (hotplugEvent, bool) {
+	properties := map[string]string{}
+
+	for _, field := range bytes.Split(raw, []byte{0}) {
+		kv := strings.SplitN(string(field), "=", 2)
+		if len(kv) == 2 {
+			properties[kv[0]] = kv[1]
+		}
+	}
+
+	devName := properties["DEVNAME"]
+
+	switch properties["SUBSYSTEM"] {
+	case "tty":
+		if !strings.HasPrefix(devName, "ttyUSB") && !strings.HasPrefix(devName, "ttyACM") {
+			return hotplugEvent{}, false
+		}
+
+	// boards using the HID transport
+	case "hidraw":
+		if !strings.HasPrefix(devName, "hidraw") {
+			return hotplugEvent{}, false
+		}
+
+	default:
+		return hotplugEvent{}, false
+	}
+
+	event := hotplugEvent{PortName: "/dev/" + devName}
+
+	switch properties["ACTION"] {
+	case "add":
+		event.Action = hotplugAdded
+	case "remove":
+		event.Action = hotplugRemoved
+	default:
+		return hotplugEvent{}, false
+	}
+
+	return event, true
+}
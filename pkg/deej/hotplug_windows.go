@@ -0,0 +1,154 @@
+package deej
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+	"go.uber.org/zap"
+)
+
+const (
+	hotplugWindowClassName = "deejHotplugWindow"
+
+	// from dbt.h
+	dbtDeviceArrival        = 0x8000
+	dbtDeviceRemoveComplete = 0x8004
+	dbtDevTypPort           = 0x00000003
+
+	// events are delivered from the window procedure, which must never block
+	hotplugEventBufferSize = 16
+)
+
+// mirrors DEV_BROADCAST_HDR from dbt.h
+type devBroadcastHeader struct {
+	size       uint32
+	deviceType uint32
+	reserved   uint32
+}
+
+type deviceChangeWatcher struct {
+	logger *zap.SugaredLogger
+
+	hwnd   win.HWND
+	events chan hotplugEvent
+}
+
+func newHotplugWatcher(logger *zap.SugaredLogger) (hotplugWatcher, error) {
+	logger = logger.Named("hotplug")
+
+	w := &deviceChangeWatcher{
+		logger: logger,
+		events: make(chan hotplugEvent, hotplugEventBufferSize),
+	}
+
+	ready := make(chan error)
+	go w.runMessageLoop(ready)
+
+	if err := <-ready; err != nil {
+		logger.Warnw("Failed to create device notification window", "error", err)
+		return nil, fmt.Errorf("create device notification window: %w", err)
+	}
+
+	logger.Debug("Created WM_DEVICECHANGE hotplug watcher")
+
+	return w, nil
+}
+
+func (w *deviceChangeWatcher) Events() <-chan hotplugEvent {
+	return w.events
+}
+
+func (w *deviceChangeWatcher) Close() error {
+	win.PostMessage(w.hwnd, win.WM_CLOSE, 0, 0)
+	return nil
+}
+
+func (w *deviceChangeWatcher) runMessageLoop(ready chan error) {
+
+	// windows delivers messages to the thread that created the window, so we can't move around
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(w.events)
+
+	className, _ := syscall.UTF16PtrFromString(hotplugWindowClassName)
+	instance := win.GetModuleHandle(nil)
+
+	windowClass := win.WNDCLASSEX{
+		LpfnWndProc:   syscall.NewCallback(w.windowProc),
+		HInstance:     instance,
+		LpszClassName: className,
+	}
+	windowClass.CbSize = uint32(unsafe.Sizeof(windowClass))
+
+	if win.RegisterClassEx(&windowClass) == 0 {
+		ready <- fmt.Errorf("register window class: %w", syscall.GetLastError())
+		return
+	}
+
+	// port arrival broadcasts only reach top-level windows, so this can't be a message-only window.
+	// it's never shown, though
+	w.hwnd = win.CreateWindowEx(0, className, className, 0, 0, 0, 0, 0, 0, 0, instance, nil)
+	if w.hwnd == 0 {
+		ready <- fmt.Errorf("create window: %w", syscall.GetLastError())
+		return
+	}
+
+	ready <- nil
+
+	var msg win.MSG
+	for win.GetMessage(&msg, 0, 0, 0) > 0 {
+		win.TranslateMessage(&msg)
+		win.DispatchMessage(&msg)
+	}
+
+	w.logger.Debug("Device notification message loop ended")
+}
+
+func (w *deviceChangeWatcher) windowProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_DEVICECHANGE:
+		if wParam != dbtDeviceArrival && wParam != dbtDeviceRemoveComplete || lParam == 0 {
+			break
+		}
+
+		header := (*devBroadcastHeader)(unsafe.Pointer(lParam))
+		if header.deviceType != dbtDevTypPort {
+			break
+		}
+
+		// DEV_BROADCAST_PORT's name immediately follows the header as a null-terminated UTF-16 string
+		namePtr := (*[64]uint16)(unsafe.Pointer(lParam + unsafe.Sizeof(*header)))
+		event := hotplugEvent{
+			Action:   hotplugAdded,
+			PortName: syscall.UTF16ToString(namePtr[:]),
+		}
+
+		if wParam == dbtDeviceRemoveComplete {
+			event.Action = hotplugRemoved
+		}
+
+		w.logger.Debugw("Serial device hotplug event", "action", event.Action, "port", event.PortName)
+
+		// don't block the message loop on a slow consumer
+		select {
+		case w.events <- event:
+		default:
+			w.logger.Warnw("Hotplug event channel full, dropping event", "event", event)
+		}
+
+		return 1
+
+	case win.WM_CLOSE:
+		win.DestroyWindow(hwnd)
+		return 0
+
+	case win.WM_DESTROY:
+		win.PostQuitMessage(0)
+		return 0
+	}
+
+	return win.DefWindowProc(hwnd, msg, wParam, lParam)
+}
@@ -0,0 +1,101 @@
+package deej
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// user-facing strings (the tray menu, notifications and the web UI) come from a catalog per language, see i18n_*.go.
+// they're looked up by key, and anything a catalog doesn't have (yet) falls back to english
+const (
+	localeAuto    = "auto"
+	defaultLocale = "en"
+)
+
+var localeCatalogs = map[string]map[string]string{
+	"en": catalogEN,
+	"de": catalogDE,
+	"es": catalogES,
+	"fr": catalogFR,
+}
+
+// the locale strings are looked up in, always one of localeCatalogs' keys
+var activeLocale atomic.Value
+
+func init() {
+
+	// anything said before the config is loaded goes by the system's locale
+	activeLocale.Store(resolveLocale(localeAuto))
+}
+
+// setLocale switches to the given locale (or the system's, for localeAuto) and returns the one it ended up with
+func setLocale(locale string) string {
+	resolved := resolveLocale(locale)
+	activeLocale.Store(resolved)
+
+	return resolved
+}
+
+func currentLocale() string {
+	return activeLocale.Load().(string)
+}
+
+// resolveLocale turns a locale like "de_DE.UTF-8" or "de-DE" into the catalog for its language,
+// or the default one if there's no such catalog
+func resolveLocale(locale string) string {
+	if locale == "" || locale == localeAuto {
+		locale = systemLocale()
+	}
+
+	locale = strings.ToLower(locale)
+	if idx := strings.IndexAny(locale, "_-.@"); idx >= 0 {
+		locale = locale[:idx]
+	}
+
+	if _, ok := localeCatalogs[locale]; !ok {
+		return defaultLocale
+	}
+
+	return locale
+}
+
+// supportedLocale returns true if the given locale has a catalog of its own
+func supportedLocale(locale string) bool {
+	_, ok := localeCatalogs[locale]
+	return ok
+}
+
+// tr returns the string with the given key in the current locale, formatted with args like fmt.Sprintf
+func tr(key string, args ...interface{}) string {
+	text, ok := localeCatalogs[currentLocale()][key]
+	if !ok {
+		text, ok = catalogEN[key]
+	}
+
+	// a missing string is a bug, but the key says more than nothing
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+
+	return fmt.Sprintf(text, args...)
+}
+
+// localeStrings returns every string whose key starts with prefix in the current locale, english filling the gaps
+func localeStrings(prefix string) map[string]string {
+	result := map[string]string{}
+
+	for _, catalog := range []map[string]string{catalogEN, localeCatalogs[currentLocale()]} {
+		for key, text := range catalog {
+			if strings.HasPrefix(key, prefix) {
+				result[key] = text
+			}
+		}
+	}
+
+	return result
+}
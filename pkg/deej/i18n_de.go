@@ -0,0 +1,347 @@
+package deej
+
+var catalogDE = map[string]string{
+
+	// tray menu
+	"tray.edit_config":                     "Konfiguration bearbeiten",
+	"tray.edit_config.tooltip":             "Konfigurationsdatei im Editor öffnen",
+	"tray.config_window":                   "Konfigurationsfenster",
+	"tray.config_window.tooltip":           "Die Weboberfläche zur Konfiguration öffnen",
+	"tray.refresh_sessions":                "Audiositzungen neu einlesen",
+	"tray.refresh_sessions.tooltip":        "Audiositzungen manuell aktualisieren, falls etwas hängt",
+	"tray.refresh_sessions.tooltip_manual": "deej findet neu gestartete Apps nur, wenn du das auswählst (session_refresh ist manual)",
+	"tray.reconnect_audio":                 "Audio-Backend neu verbinden",
+	"tray.reconnect_audio.tooltip":         "Neu mit dem Audiosystem verbinden, z. B. nachdem es neu gestartet oder ersetzt wurde, ohne deej neu zu starten",
+	"tray.fix_permissions":                 "Berechtigungen für serielle Ports reparieren",
+	"tray.fix_permissions.tooltip":         "Zugriff auf serielle Ports erhalten, die deej nicht öffnen durfte",
+	"tray.setup_board":                     "deej-Board einrichten",
+	"tray.setup_board.tooltip":             "Den Port auswählen, an dem dein deej angeschlossen ist",
+	"tray.autostart":                       "deej bei der Anmeldung starten",
+	"tray.autostart.tooltip":               "deej bei jeder Anmeldung starten",
+	"tray.verbose":                         "Ausführliche Logs",
+	"tray.verbose.tooltip":                 "Alles protokollieren, was deej tut, um ein Problem genau festzuhalten",
+	"tray.update":                          "deej aktualisieren",
+	"tray.update.tooltip":                  "Die neueste Version von deej installieren",
+	"tray.update_to":                       "Auf deej %s aktualisieren",
+	"tray.arduino":                         "Arduino-Befehle",
+	"tray.arduino.tooltip":                 "Befehle an den Arduino senden",
+	"tray.reboot_arduino":                  "Arduino neu starten",
+	"tray.reboot_arduino.tooltip":          "Den Arduino per Software neu starten",
+	"tray.request_version":                 "Version abfragen",
+	"tray.request_version.tooltip":         "Die Firmware-Version des Arduino abfragen",
+	"tray.device_disconnected":             "Kein deej verbunden",
+	"tray.device_detecting":                "Suche deej: versuche %s mit %s Baud",
+	"tray.device_not_found":                "Kein deej an %s Ports gefunden",
+	"tray.schedule_next":                   "Als Nächstes: %s um %s",
+	"tray.notification":                    "%s: %s",
+	"tray.notification.tooltip":            "Desktop-Benachrichtigungen sind nicht verfügbar (es fehlt: %s), daher wird die letzte hier angezeigt",
+	"tray.device_port":                     "deej an %s",
+	"tray.device_firmware":                 "Firmware %s",
+	"tray.device_legacy_firmware":          "alte Firmware",
+	"tray.device_sliders":                  "%s Regler",
+	"tray.slider":                          "Regler %s",
+	"tray.slider_readout":                  "%s %s %%",
+	"tray.quit":                            "Beenden",
+	"tray.quit.tooltip":                    "deej anhalten und beenden",
+
+	// notifications
+	"notify.profile_switched.title":        "Profil gewechselt",
+	"notify.profile_switched.message":      "Jetzt wird die Reglerzuordnung %s verwendet.",
+	"notify.config_not_found.title":        "Konfiguration nicht gefunden!",
+	"notify.config_not_found.message":      "%s muss im selben Ordner wie deej liegen, oder in einem deej-Ordner in deinem Benutzer-Konfigurationsordner (~/.config/deej unter Linux, AppData\\Roaming\\deej unter Windows). Bitte starte deej neu",
+	"notify.config_invalid.title":          "Ungültige Konfiguration!",
+	"notify.config_invalid_yaml.message":   "Bitte stelle sicher, dass %s gültiges YAML ist.",
+	"notify.config_error.title":            "Fehler beim Laden der Konfiguration!",
+	"notify.config_error.message":          "Weitere Details findest du in den Logs von deej.",
+	"notify.config_previous_kept":          "%s Bis das behoben ist, gilt weiterhin deine vorherige Konfiguration.",
+	"notify.config_reloaded.title":         "Konfiguration neu geladen!",
+	"notify.config_reloaded.message":       "Deine Änderungen wurden übernommen.",
+	"notify.config_migration.title":        "deej-Konfiguration verschieben?",
+	"notify.config_migration.message":      "deej hat %s im Ordner gefunden, aus dem es gestartet wurde. Soll die Datei beim nächsten Start von deej nach %s verschoben werden, damit deej sie unabhängig vom Startordner findet? Die alte Datei bleibt als Sicherung erhalten.",
+	"notify.config_migration.inform":       "deej sucht config.yaml auch in %s. Verschiebe sie dorthin, um deej von überall aus starten zu können.",
+	"notify.config_migration.failed":       "deej konnte deine Konfiguration nicht verschieben und nutzt sie weiter am alten Ort: %s",
+	"notify.welcome.title":                 "Willkommen bei deej!",
+	"notify.welcome.message":               "Schließe dein deej an und wähle auf der Einrichtungsseite seinen Port aus, um loszulegen.",
+	"notify.cant_connect.title":            "Keine Verbindung zu %s!",
+	"notify.port_busy.message":             "Dieser serielle Port ist belegt. Schließe alle seriellen Monitore und andere deej-Instanzen.",
+	"notify.port_missing.message":          "Dieser serielle Port existiert nicht. Prüfe, ob er in deiner Konfiguration richtig eingestellt ist.",
+	"notify.port_missing_setup.title":      "%s nicht gefunden!",
+	"notify.port_missing_setup.message":    "Dieser serielle Port existiert nicht. Wähle auf der Einrichtungsseite den Port aus, an dem dein deej angeschlossen ist.",
+	"notify.port_permission.title":         "Kein Zugriff auf %s!",
+	"notify.port_permission.message":       "deej hat keine Berechtigung für diesen seriellen Port. Wähle im Tray-Menü \"Berechtigungen für serielle Ports reparieren\", um das zu beheben.",
+	"notify.doctor.title":                  "deej-Selbsttest",
+	"notify.doctor.message":                "Wenn du das siehst, funktionieren Benachrichtigungen.",
+	"notify.trace_replay_failed.title":     "Regler-Aufzeichnung kann nicht abgespielt werden!",
+	"notify.incompatible_firmware.title":   "Inkompatible deej-Firmware!",
+	"notify.incompatible_firmware.message": "Dein Gerät hat Firmware %s, diese Version von deej erwartet aber %s. Bitte aktualisiere deej oder flashe dein Board neu.",
+	"notify.arduino_reboot.title":          "Arduino-Neustart",
+	"notify.arduino_reboot.message":        "Neustart-Befehl wird an den Arduino gesendet...",
+	"notify.arduino_reboot_failed.title":   "Arduino kann nicht neu gestartet werden!",
+	"notify.firmware_version.title":        "Arduino-Firmware",
+	"notify.firmware_version.message":      "Dein Board läuft mit der deej-Firmware %s.",
+	"notify.firmware_version_failed.title": "Firmware-Version kann nicht abgefragt werden!",
+	"notify.audio_lost.title":              "Verbindung zum Audioserver verloren!",
+	"notify.audio_lost.message":            "deej versucht weiter, sich neu zu verbinden. Bis dahin bewirken deine Regler nichts.",
+	"notify.audio_back.title":              "Wieder mit dem Audioserver verbunden",
+	"notify.audio_back.message":            "Deine Regler haben wieder die Kontrolle.",
+	"notify.autostart_failed.title":        "Autostart kann nicht geändert werden!",
+	"notify.update_failed.title":           "deej kann nicht aktualisiert werden!",
+	"notify.update_available.title":        "deej %s ist verfügbar!",
+	"notify.update_available.notes":        "Was neu ist, steht unter %s.",
+	"notify.update_available.install":      "Installiere es über das Tray-Menü oder das Konfigurationsfenster.",
+	"notify.updated.title":                 "Auf deej %s aktualisiert!",
+	"notify.updated.message":               "Starte deej neu, um die neue Version zu verwenden.",
+	"notify.unmatched_targets.title":       "Manche Schieberegler steuern nichts",
+	"notify.unmatched_targets.message":     "Bisher passt keine Audiositzung zu %s. Prüfe die Schreibweise in deiner Konfiguration, oder starte die App und spiele etwas ab.",
+	"notify.web_config_failed.title":       "Das Konfigurationsfenster lässt sich nicht öffnen!",
+	"notify.web_config_failed.message":     "deej konnte seinen Webserver nicht starten: %s",
+	"notify.connection_unstable.title":     "Verbindung zu %s bricht immer wieder ab",
+	"notify.connection_unstable.message":   "deej hat die Verbindung %s-mal in %s Minuten verloren, meist wegen eines lockeren oder defekten USB-Kabels. deej verbindet sich ohne weitere Benachrichtigungen neu, Details zeigt die Statusseite.",
+	"notify.baud_rate_mismatch.title":      "Baudrate stimmt nicht überein",
+	"notify.baud_rate_mismatch.message":    "Dein deej-Board läuft mit %d Baud, aber %s ist in deiner Konfiguration auf %d gesetzt. %d in deiner Konfiguration speichern?",
+	"notify.baud_rate_mismatch.inform":     "Dein deej-Board läuft mit %d Baud. Setze %s in deiner Konfiguration auf %d, damit es passt.",
+	"notify.degraded.unknown":              "Benachrichtigungen konnten nicht gesendet werden",
+	"dialog.yes":                           "Ja",
+	"dialog.no":                            "Nein",
+
+	// serial port permission dialogs, see permissions_linux.go
+	"permissions.title":                  "Berechtigungen für serielle Ports",
+	"permissions.no_problems":            "deej ist auf keine Berechtigungsprobleme mit seriellen Ports gestoßen.",
+	"permissions.unknown_group.title":    "Berechtigungen für serielle Ports können nicht repariert werden",
+	"permissions.unknown_group.message":  "Zugriff auf %s verweigert, aber deej konnte nicht erkennen, welcher Gruppe das Gerät gehört.\n\nBitte prüfe die Berechtigungen des Geräts manuell.",
+	"permissions.already_member.title":   "Bereits Mitglied",
+	"permissions.already_member.message": "Du bist bereits Mitglied der Gruppe '%s'.\n\nBitte melde dich ab und wieder an, falls weiterhin Probleme auftreten.",
+	"permissions.manual.message":         "Zugriff auf %s verweigert. Um das zu beheben, führe aus:\n%s\nund melde dich danach ab und wieder an.",
+	"permissions.confirm.message":        "Zugriff auf %s verweigert.\n\nMöchtest du dich zur Gruppe '%s' hinzufügen?\n\nDu wirst nach deinem Passwort gefragt.",
+	"permissions.cancelled.title":        "Aktion abgebrochen",
+	"permissions.cancelled.message":      "Es wurden keine Änderungen vorgenommen.",
+	"permissions.failed.title":           "Fehler",
+	"permissions.failed.message":         "Du konntest nicht zur Gruppe hinzugefügt werden.\n\nBitte führe diesen Befehl manuell aus:\n%s",
+	"permissions.added.title":            "Aktion erforderlich",
+	"permissions.added.message":          "Du wurdest zur Gruppe hinzugefügt.\n\nBitte melde dich ab und wieder an und starte dieses Programm dann erneut.",
+
+	// errors, see error_catalog.go
+	"error.unknown.title":                 "Etwas ist schiefgelaufen!",
+	"error.unknown.message":               "Weitere Details stehen in den Logs von deej.",
+	"error.docs":                          "Weitere Hilfe: %s",
+	"error.config_not_found.hint":         "Zum Start kannst du die Beispielkonfiguration von der Release-Seite von deej kopieren.",
+	"error.config_syntax.hint":            "Rücke mit Leerzeichen statt Tabs ein und setze nach jedem Doppelpunkt ein Leerzeichen.",
+	"error.config_invalid.hint":           "Korrigiere es und speichere die Datei, deej übernimmt die Änderung sofort.",
+	"error.device_not_found.title":        "Dein deej wurde nicht gefunden!",
+	"error.device_not_found.message":      "Keines der verbundenen Geräte hat wie ein deej-Board geantwortet.",
+	"error.device_not_found.hint":         "Stelle sicher, dass dein Board angeschlossen ist und den deej-Sketch ausführt, oder wähle seinen Port auf der Einrichtungsseite.",
+	"error.not_connected.message":         "Dein deej-Board ist nicht verbunden.",
+	"error.not_connected.hint":            "Schließe es an, deej verbindet sich von selbst.",
+	"error.device_unresponsive.title":     "Dein deej-Board hat nicht reagiert!",
+	"error.device_unresponsive.message":   "Dein Board hat nicht rechtzeitig geantwortet.",
+	"error.device_unresponsive.hint":      "Versuche, es aus- und wieder einzustecken.",
+	"error.command_rejected.message":      "Die Firmware deines Boards kann das nicht.",
+	"error.update_firmware.hint":          "Spiele den Sketch, der zu dieser Version von deej gehört, neu auf dein Board.",
+	"error.firmware_incompatible.message": "Die Firmware deines Boards spricht eine andere Protokollversion als diese Version von deej.",
+	"error.unsupported.message":           "Deinem Board fehlt die Hardware dafür.",
+	"error.audio_unavailable.title":       "Dein Audiosystem ist nicht erreichbar!",
+	"error.audio_unavailable.message":     "deej konnte deine Audiositzungen nicht abrufen, die Schieberegler können also nichts steuern.",
+	"error.audio_unavailable.hint":        "Stelle sicher, dass dein Audiodienst läuft (Windows-Audio, oder PulseAudio bzw. PipeWire unter Linux).",
+
+	// web UI
+	"web.title":                    "deej-Konfiguration",
+	"web.slider_mappings":          "Reglerzuordnung",
+	"web.refresh_slider_count":     "Anzahl der Regler aktualisieren",
+	"web.sliders_detected":         "%s Regler vom Arduino erkannt",
+	"web.sliders_default":          "Arduino nicht verbunden - es werden %s Regler angezeigt, so viele wie beim letzten Mal (oder default_slider_count)",
+	"web.sliders_default_hint":     "Schließe deinen Arduino an und klicke auf \"Anzahl der Regler aktualisieren\", um die tatsächliche Anzahl zu erkennen",
+	"web.targets_hint":             "Gib Prozessnamen (z. B. chrome.exe) oder besondere Ziele (master, mic, deej.unmapped usw.) ein",
+	"web.targets_separator_hint":   "Mehrere Ziele können durch Kommas getrennt werden",
+	"web.slider":                   "Regler %s:",
+	"web.slider.placeholder":       "z. B. chrome.exe, firefox.exe",
+	"web.slider_name.placeholder":  "Name (optional)",
+	"web.slider_name_for":          "Name von Regler %s",
+	"web.pick_target":              "Ziel auswählen",
+	"web.pick_target_for":          "Ziel für Regler %s auswählen",
+	"web.unmatched_targets":        "Bisher passt keine Audiositzung zu %s. Prüfe die Schreibweise, oder starte die App und spiele etwas ab.",
+	"web.advanced":                 "Erweitert",
+	"web.connection_settings":      "Verbindungseinstellungen",
+	"web.permissions_notice":       "deej durfte %s nicht öffnen.",
+	"web.fix_permissions":          "Berechtigungen reparieren",
+	"web.com_port":                 "COM-Port:",
+	"web.com_port.placeholder":     "z. B. COM4 oder auto",
+	"web.baud_rate":                "Baudrate:",
+	"web.inspector_hint":           "Probleme mit deinem Board? Der <a href=\"/inspector\" target=\"_blank\">Inspektor für seriellen Datenverkehr</a> zeigt live alles, was es sendet.",
+	"web.request_version":          "Firmware-Version vom Board abfragen",
+	"web.explain":                  "Schieberegler erklären",
+	"web.explain_hint":             "Sieh dir an, was ein Schieberegler gerade steuert: seine Ziele, die gefundenen Audiositzungen und deren Lautstärke.",
+	"web.explain_slider":           "Schieberegler:",
+	"web.explain_button":           "Erklären",
+	"web.explain_preview_button":   "Vorschau der ungespeicherten Zuordnung",
+	"web.test_tone":                "Testton abspielen",
+	"web.test_tone_played":         "Testton abgespielt auf %s",
+	"web.test_tone_failed":         "Testton konnte nicht abgespielt werden: %s",
+	"web.explain_failed":           "Schieberegler konnte nicht erklärt werden: %s",
+	"web.explain_position":         "Der Schieberegler steht bei %s.",
+	"web.explain_position_unknown": "Das Board hat noch nicht gemeldet, wo der Schieberegler steht.",
+	"web.explain_overridden":       "Eine vorübergehende Überschreibung ersetzt die Ziele aus der Konfiguration.",
+	"web.explain_preview":          "Vorschau der oben für diesen Regler eingegebenen Ziele, die noch nicht gespeichert sind.",
+	"web.explain_frozen":           "Eingaben der Schieberegler werden ignoriert, solange der Bildschirm gesperrt ist.",
+	"web.explain_adopted":          "Seine Ziele behalten eine anderswo eingestellte Lautstärke, bis der Schieberegler bewegt wird.",
+	"web.explain_unmapped":         "Diesem Schieberegler ist nichts zugeordnet.",
+	"web.explain_kind_name":        "nach Name",
+	"web.explain_kind_transform":   "Spezialziel",
+	"web.explain_kind_role":        "nach Rolle",
+	"web.explain_never_matched":    "Seit dieses Ziel zugeordnet wurde, hat keine Audiositzung dazu gepasst.",
+	"web.explain_resolved_nothing": "Steht gerade für nichts",
+	"web.explain_sets":             "setzt %s",
+	"web.explain_no_sessions":      "keine Audiositzung gefunden",
+	"web.explain_sessions":         "%s Audiositzungen, gerade bei %s",
+	"web.explain_muted":            "(stumm)",
+	"web.explain_instance":         "%s: %s",
+	"web.loudness":                 "Lautstärke angleichen",
+	"web.loudness_hint":            "Manche Apps spielen viel lauter als andere. Spiele in jeder App, die du angleichen willst, etwas Typisches ab (oder rosa Rauschen, wenn sie das kann) und kalibriere dann: deej hört sich jede App ein paar Sekunden lang bei voller Lautstärke an und regelt die lauteren herunter, damit dieselbe Reglerstellung überall etwa gleich laut klingt.",
+	"web.loudness_calibrate":       "Kalibrieren",
+	"web.loudness_clear":           "Ausgleich entfernen",
+	"web.loudness_calibrating":     "Alle Apps, die gerade etwas abspielen, werden angehört, das dauert ein paar Sekunden...",
+	"web.loudness_calibrated":      "Kalibriert, der Ausgleich gilt sofort.",
+	"web.loudness_nothing_playing": "Keine deiner Apps spielt gerade etwas ab. Starte etwas Audio und versuche es erneut.",
+	"web.loudness_failed":          "Lautstärke konnte nicht kalibriert werden: %s",
+	"web.loudness_none":            "Kein Lautstärkeausgleich, jede App spielt so laut, wie sie ist.",
+	"web.loudness_offset":          "%s: %s dB",
+	"web.loudness_silent":          "%s: still, nicht kalibriert",
+	"web.timeline":                 "Ereignisverlauf",
+	"web.timeline_hint":            "Was seit dem Start von deej mit deinen Audiositzungen, deren Lautstärke und der Verbindung zum Board passiert ist, das Neueste zuerst.",
+	"web.timeline_refresh":         "Aktualisieren",
+	"web.timeline_failed":          "Verlauf konnte nicht geladen werden: %s",
+	"web.timeline_empty":           "Bisher ist nichts passiert.",
+	"web.timeline_repeated":        "(%s-mal)",
+	"web.timeline_session_added":   "%s ist aufgetaucht",
+	"web.timeline_session_removed": "%s ist verschwunden",
+	"web.timeline_volume":          "%s auf %s gesetzt (Schieberegler %s)",
+	"web.timeline_external_volume": "%s wurde außerhalb von deej auf %s geändert (Schieberegler %s)",
+	"web.timeline_connected":       "Mit dem Board an %s verbunden",
+	"web.timeline_disconnected":    "Verbindung zum Board an %s getrennt",
+	"web.timeline_connect_failed":  "Keine Verbindung zum Board an %s: %s",
+	"web.timeline_audio_lost":      "Verbindung zum Audioserver verloren",
+	"web.timeline_audio_back":      "Wieder mit dem Audioserver verbunden",
+	"web.remote_hint":              "Die <a href=\"/remote\" target=\"_blank\">Fernbedienung</a> hat Schieberegler auf dem Bildschirm, die genau wie die deines Boards funktionieren, auch wenn es nicht angeschlossen ist. Um sie vom Handy aus zu nutzen, setze web_host und web_token in der config.yaml.",
+	"web.remote.title":             "deej-Fernbedienung",
+	"web.remote.board_connected":   "Dein Board ist verbunden und übernimmt wieder, sobald einer seiner Schieberegler bewegt wird.",
+	"web.remote.board_missing":     "Dein Board ist nicht verbunden, es gibt nur die Schieberegler hier.",
+	"web.remote.slider":            "%s: %s",
+	"web.remote.slider_unmapped":   "%s: nicht zugeordnet",
+	"web.remote.failed":            "Verbindung zu deej verloren: %s",
+	"web.status.title":             "deej-Status",
+	"web.status.connected":         "Verbunden (%s)",
+	"web.status.disconnected":      "Nicht verbunden",
+	"web.detect.probing":           "Suche dein deej-Board: versuche %s mit %s Baud…",
+	"web.detect.failed":            "Dein deej-Board wurde nicht gefunden. Es wird erkannt, sobald es angeschlossen ist.",
+	"web.detect.found":             "%s mit %s Baud: deej gefunden",
+	"web.detect.no_answer":         "%s mit %s Baud: keine Antwort",
+	"web.detect.denied":            "%s: Zugriff verweigert",
+	"web.detect.unavailable":       "%s: konnte nicht geöffnet werden",
+	"web.detect.skipped":           "%s: nicht geprüft (skip_ports oder safe_probing)",
+	"web.status.unmapped":          "Schieberegler %s",
+	"web.status.named":             "%s: %s",
+	"web.status.failed":            "Verbindung zu deej verloren: %s",
+	"web.status.unstable":          "Verbindung instabil!",
+	"web.status.disconnects":       "%s-mal getrennt in den letzten %s Minuten, zuletzt um %s.",
+	"web.status.suppressed":        "%s Benachrichtigungen zurückgehalten.",
+	"web.firmware_version":         "Dein Board läuft mit der deej-Firmware %s",
+	"web.firmware_version_failed":  "Firmware-Version konnte nicht abgefragt werden: %s",
+	"web.hardware":                 "Hardware",
+	"web.board_settings":           "Board-Einstellungen",
+	"web.hardware_notice":          "Diese Einstellungen werden bei jeder Verbindung an das Board gesendet. Lass ein Feld leer, um den Standardwert der Firmware zu behalten.",
+	"web.hardware_unsupported":     "Das verbundene Board nimmt keine Einstellungen von deej an (oder ist nicht verbunden). Sie werden gesendet, sobald sich ein Board verbindet, das es kann. Die Meldungsrate gilt trotzdem.",
+	"web.report_rate":              "Meldungsrate (pro Sekunde):",
+	"web.led_brightness":           "LED-Helligkeit (0-255):",
+	"web.dim_after":                "Display und LEDs dimmen nach (Sekunden ohne Berührung):",
+	"web.off_after":                "Ausschalten nach (Sekunden ohne Berührung):",
+	"web.idle_hint":                "Leer lassen, um sie eingeschaltet zu lassen. Ein Regler oder eine Taste weckt sie wieder auf.",
+	"web.smoothing":                "Glättung %s:",
+	"web.smoothing.placeholder":    "Anzahl gemittelter Messwerte",
+	"web.led_colors_hint":          "Für Boards mit RGB-LEDs: Gib jedem Regler eine Farbe, passend zu seinem Namen auf dem Bildschirm.",
+	"web.led_color":                "Farbe %s:",
+	"web.led_color_clear":          "Entfernen",
+	"web.led_color_clear_for":      "Farbe von Regler %s entfernen",
+	"web.save_hardware":            "Speichern und an das Board senden",
+	"web.board_storage":            "Auf dem Board gespeichert",
+	"web.board_storage_hint":       "Boards, die Einstellungen in ihrem EEPROM speichern, nehmen sie zu anderen Computern mit. Lies sie vom Board, um sie zu bearbeiten, und schreibe sie dann zurück.",
+	"web.board_name":               "Name des Boards:",
+	"web.board_sliders":            "Anzahl der Regler:",
+	"web.board_slider_name":        "Name von Regler %s:",
+	"web.board_read":               "Vom Board lesen",
+	"web.board_write":              "Auf das Board schreiben",
+	"web.board_read_done":          "Die auf dem Board gespeicherten Einstellungen wurden gelesen",
+	"web.board_read_failed":        "Die Einstellungen des Boards konnten nicht gelesen werden: %s",
+	"web.board_written":            "Die Einstellungen wurden auf dem Board gespeichert",
+	"web.board_write_failed":       "Die Einstellungen konnten nicht auf dem Board gespeichert werden: %s",
+	"web.other_settings":           "Weitere Einstellungen",
+	"web.autostart":                "deej bei der Anmeldung starten",
+	"web.verbose":                  "Ausführliche Logs (um ein Problem in den Logs von deej festzuhalten)",
+	"web.invert_sliders":           "Regler umkehren",
+	"web.theme":                    "Design:",
+	"web.theme_auto":               "Wie das System",
+	"web.theme_light":              "Hell",
+	"web.theme_dark":               "Dunkel",
+	"web.theme_failed":             "Design konnte nicht geändert werden: %s",
+	"web.noise_reduction":          "Rauschunterdrückung:",
+	"web.noise_low":                "Niedrig (hervorragende Hardware)",
+	"web.noise_default":            "Standard (normale Hardware)",
+	"web.noise_high":               "Hoch (schlechte, verrauschte Hardware)",
+	"web.session_refresh":          "Neue Apps finden:",
+	"web.session_refresh_auto":     "Automatisch",
+	"web.session_refresh_manual":   "Nur auf Anfrage",
+	"web.refresh_frequency":        "Höchstens alle (Sekunden):",
+	"web.session_refresh_hint":     "Die Suche nach Apps dauert einen Moment, was auf langsamen Rechnern auffallen kann. Steht das auf „Nur auf Anfrage“, findet deej später gestartete Apps erst, wenn du im Tray „Audiositzungen neu einlesen“ wählst oder hier laufende Anwendungen neu einliest.",
+	"web.cancel":                   "Abbrechen",
+	"web.save":                     "Konfiguration speichern",
+	"web.select_target":            "Audioziel auswählen",
+	"web.rescan_running":           "Laufende Anwendungen neu einlesen",
+	"web.loading_targets":          "Verfügbare Ziele werden geladen...",
+	"web.rescanning":               "Laufende Anwendungen werden neu eingelesen...",
+	"web.search_installed":         "Installierte Anwendungen durchsuchen...",
+	"web.targets_failed":           "Ziele konnten nicht geladen werden: %s",
+	"web.system_controls":          "Systemsteuerung",
+	"web.running_apps":             "Laufende Anwendungen",
+	"web.mpris_players":            "Nicht zugeordnete MPRIS-Player",
+	"web.audio_devices":            "Audiogeräte",
+	"web.installed_apps":           "Installierte Anwendungen (%s)",
+	"web.other_category":           "Sonstige",
+	"web.no_targets":               "Keine Audioziele gefunden",
+	"web.playing":                  "%s - Spielt: %s",
+	"web.by_artist":                "%s von %s",
+	"web.now_playing":              "Läuft gerade: %s",
+	"web.saved":                    "Konfiguration erfolgreich gespeichert!",
+	"web.save_failed":              "Konfiguration konnte nicht gespeichert werden: %s",
+	"web.load_failed":              "Konfiguration konnte nicht geladen werden: %s",
+	"web.slider_count_refreshed":   "Anzahl der Regler aktualisiert: %s Regler erkannt",
+	"web.slider_count_failed":      "Anzahl der Regler konnte nicht aktualisiert werden: %s",
+	"web.hardware_saved":           "Hardware-Einstellungen gespeichert",
+	"web.hardware_failed":          "Hardware-Einstellungen konnten nicht gespeichert werden: %s",
+	"web.permissions_started":      "Folge den Anweisungen, um die Berechtigungen für serielle Ports zu reparieren",
+	"web.permissions_failed":       "Der Berechtigungs-Assistent konnte nicht gestartet werden: %s",
+	"web.autostart_on":             "deej startet ab jetzt bei der Anmeldung",
+	"web.autostart_off":            "deej startet nicht mehr bei der Anmeldung",
+	"web.autostart_failed":         "Autostart konnte nicht geändert werden: %s",
+	"web.verbose_on":               "Ausführliche Logs aktiviert",
+	"web.verbose_off":              "Ausführliche Logs deaktiviert",
+	"web.verbose_failed":           "Ausführliche Logs konnten nicht geändert werden: %s",
+	"web.update_available":         "deej %s ist verfügbar.",
+	"web.whats_new":                "Was ist neu",
+	"web.update":                   "Aktualisieren",
+	"web.updating":                 "Wird aktualisiert...",
+	"web.restart_to_finish":        "deej zum Abschließen neu starten",
+	"web.updated":                  "deej wurde aktualisiert, starte es neu, um die neue Version zu verwenden",
+	"web.update_failed":            "deej konnte nicht aktualisiert werden: %s",
+	"web.setup.title":              "deej einrichten",
+	"web.setup.intro":              "Schließe dein deej an und wähle den Port, an dem es hängt. deej markiert die Ports, an denen es ein Board gefunden hat.",
+	"web.setup.looking":            "Suche nach deinem deej, das kann ein paar Sekunden dauern...",
+	"web.setup.look_again":         "Erneut suchen",
+	"web.setup.save":               "Speichern",
+	"web.setup.full_config":        "Zur vollständigen Konfiguration",
+	"web.setup.auto":               "Automatisch finden",
+	"web.setup.connected":          "%s (verbunden)",
+	"web.setup.deej_found":         "%s (deej gefunden)",
+	"web.setup.skipped":            "%s (nicht geprüft, siehe skip_ports und safe_probing)",
+	"web.setup.ports_failed":       "Serielle Ports konnten nicht aufgelistet werden: %s",
+	"web.setup.saved":              "Gespeichert! deej verbindet sich gleich mit deinem Board.",
+	"web.setup.save_failed":        "Speichern fehlgeschlagen: %s",
+	"web.setup.all_set":            "Mit deinem deej an %s verbunden. Alles bereit!",
+}
@@ -0,0 +1,349 @@
+package deej
+
+// catalogEN is the complete catalog every other one falls back to. keys starting with "web." are sent to the web UI,
+// whose strings use %s for their arguments too
+var catalogEN = map[string]string{
+
+	// tray menu
+	"tray.edit_config":                     "Edit configuration",
+	"tray.edit_config.tooltip":             "Open config file with notepad",
+	"tray.config_window":                   "Configuration Window",
+	"tray.config_window.tooltip":           "Open web-based configuration interface",
+	"tray.refresh_sessions":                "Re-scan audio sessions",
+	"tray.refresh_sessions.tooltip":        "Manually refresh audio sessions if something's stuck",
+	"tray.refresh_sessions.tooltip_manual": "deej only finds newly started apps when you choose this (session_refresh is manual)",
+	"tray.reconnect_audio":                 "Reconnect audio backend",
+	"tray.reconnect_audio.tooltip":         "Reconnect to the audio system, i.e. after it was restarted or replaced, without restarting deej",
+	"tray.fix_permissions":                 "Fix serial port permissions",
+	"tray.fix_permissions.tooltip":         "Get access to serial ports deej wasn't allowed to open",
+	"tray.setup_board":                     "Set up deej board",
+	"tray.setup_board.tooltip":             "Pick the port your deej is plugged into",
+	"tray.autostart":                       "Start deej at login",
+	"tray.autostart.tooltip":               "Start deej whenever you log in",
+	"tray.verbose":                         "Verbose logging",
+	"tray.verbose.tooltip":                 "Log everything deej does, to capture a problem in detail",
+	"tray.update":                          "Update deej",
+	"tray.update.tooltip":                  "Install the latest release of deej",
+	"tray.update_to":                       "Update to deej %s",
+	"tray.arduino":                         "Arduino Commands",
+	"tray.arduino.tooltip":                 "Send commands to the Arduino",
+	"tray.reboot_arduino":                  "Reboot Arduino",
+	"tray.reboot_arduino.tooltip":          "Soft reboot the Arduino device",
+	"tray.request_version":                 "Request Version",
+	"tray.request_version.tooltip":         "Get Arduino firmware version",
+	"tray.device_disconnected":             "No deej connected",
+	"tray.device_detecting":                "Looking for deej: trying %s at %s baud",
+	"tray.device_not_found":                "No deej found on %s ports",
+	"tray.schedule_next":                   "Next: %s at %s",
+	"tray.notification":                    "%s: %s",
+	"tray.notification.tooltip":            "Desktop notifications aren't available (missing: %s), so the latest one is shown here",
+	"tray.device_port":                     "deej on %s",
+	"tray.device_firmware":                 "firmware %s",
+	"tray.device_legacy_firmware":          "legacy firmware",
+	"tray.device_sliders":                  "%s sliders",
+	"tray.slider":                          "Slider %s",
+	"tray.slider_readout":                  "%s %s%%",
+	"tray.quit":                            "Quit",
+	"tray.quit.tooltip":                    "Stop deej and quit",
+
+	// notifications
+	"notify.profile_switched.title":        "Profile switched",
+	"notify.profile_switched.message":      "Now using the %s slider mapping.",
+	"notify.config_not_found.title":        "Can't find configuration!",
+	"notify.config_not_found.message":      "%s must be in the same directory as deej, or in a deej folder in your user config directory (~/.config/deej on Linux, AppData\\Roaming\\deej on Windows). Please re-launch",
+	"notify.config_invalid.title":          "Invalid configuration!",
+	"notify.config_invalid_yaml.message":   "Please make sure %s is in a valid YAML format.",
+	"notify.config_error.title":            "Error loading configuration!",
+	"notify.config_error.message":          "Please check deej's logs for more details.",
+	"notify.config_previous_kept":          "%s Your previous configuration stays in effect until this is fixed.",
+	"notify.config_reloaded.title":         "Configuration reloaded!",
+	"notify.config_reloaded.message":       "Your changes have been applied.",
+	"notify.config_migration.title":        "Move your deej config?",
+	"notify.config_migration.message":      "deej found %s in the folder it was started from. Move it to %s the next time deej starts, so deej finds it no matter where it's started from? The old file is kept as a backup.",
+	"notify.config_migration.inform":       "deej also looks for config.yaml in %s. Move it there to start deej from anywhere.",
+	"notify.config_migration.failed":       "deej couldn't move your config and keeps using it where it is: %s",
+	"notify.welcome.title":                 "Welcome to deej!",
+	"notify.welcome.message":               "Plug in your deej and pick its port on the setup page to get started.",
+	"notify.cant_connect.title":            "Can't connect to %s!",
+	"notify.port_busy.message":             "This serial port is busy, make sure to close any serial monitor or other deej instance.",
+	"notify.port_missing.message":          "This serial port doesn't exist, check your configuration and make sure it's set correctly.",
+	"notify.port_missing_setup.title":      "Can't find %s!",
+	"notify.port_missing_setup.message":    "This serial port doesn't exist. Pick the port your deej is plugged into on the setup page.",
+	"notify.port_permission.title":         "Can't access %s!",
+	"notify.port_permission.message":       "deej doesn't have permission to use this serial port. Choose \"Fix serial port permissions\" from the tray menu to resolve this.",
+	"notify.doctor.title":                  "deej self-test",
+	"notify.doctor.message":                "If you can see this, notifications work.",
+	"notify.trace_replay_failed.title":     "Can't replay slider trace!",
+	"notify.incompatible_firmware.title":   "Incompatible deej firmware!",
+	"notify.incompatible_firmware.message": "Your device runs firmware %s, but this version of deej expects %s. Please update deej or re-flash your board.",
+	"notify.arduino_reboot.title":          "Arduino Reboot",
+	"notify.arduino_reboot.message":        "Sending reboot command to Arduino...",
+	"notify.arduino_reboot_failed.title":   "Can't reboot the Arduino!",
+	"notify.firmware_version.title":        "Arduino firmware",
+	"notify.firmware_version.message":      "Your board runs deej firmware %s.",
+	"notify.firmware_version_failed.title": "Can't get the firmware version!",
+	"notify.audio_lost.title":              "Lost connection to the audio server!",
+	"notify.audio_lost.message":            "deej will keep trying to reconnect, your sliders won't do anything until then.",
+	"notify.audio_back.title":              "Reconnected to the audio server",
+	"notify.audio_back.message":            "Your sliders are back in control.",
+	"notify.autostart_failed.title":        "Can't change autostart!",
+	"notify.update_failed.title":           "Can't update deej!",
+	"notify.update_available.title":        "deej %s is available!",
+	"notify.update_available.notes":        "See what's new at %s.",
+	"notify.update_available.install":      "Install it from the tray menu or the configuration window.",
+	"notify.updated.title":                 "Updated to deej %s!",
+	"notify.updated.message":               "Restart deej to start using it.",
+	"notify.unmatched_targets.title":       "Some sliders aren't controlling anything",
+	"notify.unmatched_targets.message":     "No audio session has matched %s yet. Check the spelling in your config, or start the app and play something.",
+	"notify.web_config_failed.title":       "Can't open the configuration window!",
+	"notify.web_config_failed.message":     "deej couldn't start its web server: %s",
+	"notify.connection_unstable.title":     "Connection to %s keeps dropping",
+	"notify.connection_unstable.message":   "deej lost its connection %s times in %s minutes, usually because of a loose or faulty USB cable. It keeps reconnecting without further notifications, the status page has the details.",
+	"notify.baud_rate_mismatch.title":      "Baud rate mismatch",
+	"notify.baud_rate_mismatch.message":    "Your deej board runs at %d baud, but %s is set to %d in your config. Save %d to your config?",
+	"notify.baud_rate_mismatch.inform":     "Your deej board runs at %d baud. Set %s to %d in your config to match it.",
+	"notify.degraded.unknown":              "notifications failed to send",
+	"dialog.yes":                           "Yes",
+	"dialog.no":                            "No",
+
+	// serial port permission dialogs, see permissions_linux.go
+	"permissions.title":                  "Serial port permissions",
+	"permissions.no_problems":            "deej hasn't run into any serial port permission problems.",
+	"permissions.unknown_group.title":    "Can't fix serial port permissions",
+	"permissions.unknown_group.message":  "Permission denied opening %s, but deej couldn't tell which group owns it.\n\nPlease check the device's permissions manually.",
+	"permissions.already_member.title":   "Already a member",
+	"permissions.already_member.message": "You are already a member of the '%s' group.\n\nPlease log out and log back in if you still have issues.",
+	"permissions.manual.message":         "Permission denied opening %s. To fix this, run:\n%s\nthen log out and log back in.",
+	"permissions.confirm.message":        "Permission denied opening %s.\n\nWould you like to add yourself to the '%s' group?\n\nYou will be prompted for your password.",
+	"permissions.cancelled.title":        "Action Cancelled",
+	"permissions.cancelled.message":      "No changes were made.",
+	"permissions.failed.title":           "Error",
+	"permissions.failed.message":         "Failed to add you to the group.\n\nPlease run this command manually:\n%s",
+	"permissions.added.title":            "Action Required",
+	"permissions.added.message":          "You have been added to the group.\n\nPlease log out and log back in, then rerun this program to continue.",
+
+	// errors, see error_catalog.go
+	"error.unknown.title":                 "Something went wrong!",
+	"error.unknown.message":               "Please check deej's logs for more details.",
+	"error.docs":                          "More help: %s",
+	"error.config_not_found.hint":         "You can copy the example config from deej's release page to get started.",
+	"error.config_syntax.hint":            "Indent with spaces rather than tabs, and put a space after every colon.",
+	"error.config_invalid.hint":           "Fix it and save the file, deej picks up the change right away.",
+	"error.device_not_found.title":        "Can't find your deej!",
+	"error.device_not_found.message":      "None of the connected devices answered like a deej board.",
+	"error.device_not_found.hint":         "Make sure your board is plugged in and runs the deej sketch, or pick its port on the setup page.",
+	"error.not_connected.message":         "Your deej board isn't connected.",
+	"error.not_connected.hint":            "Plug it in, deej connects to it on its own.",
+	"error.device_unresponsive.title":     "Your deej board didn't respond!",
+	"error.device_unresponsive.message":   "Your board didn't answer in time.",
+	"error.device_unresponsive.hint":      "Try unplugging it and plugging it back in.",
+	"error.command_rejected.message":      "Your board's firmware doesn't know how to do this.",
+	"error.update_firmware.hint":          "Re-flash your board with the sketch that comes with this version of deej.",
+	"error.firmware_incompatible.message": "Your board's firmware speaks a different protocol version than this version of deej.",
+	"error.unsupported.message":           "Your board doesn't have the hardware for this.",
+	"error.audio_unavailable.title":       "Can't reach your audio system!",
+	"error.audio_unavailable.message":     "deej couldn't list your audio sessions, so the sliders can't control anything.",
+	"error.audio_unavailable.hint":        "Make sure your audio service is running (Windows Audio, or PulseAudio or PipeWire on Linux).",
+
+	// web UI
+	"web.title":                    "deej Configuration",
+	"web.slider_mappings":          "Slider Mappings",
+	"web.refresh_slider_count":     "Refresh Slider Count",
+	"web.sliders_detected":         "Detected %s slider(s) from Arduino",
+	"web.sliders_default":          "Arduino not connected - showing %s sliders, as many as it had last time (or default_slider_count)",
+	"web.sliders_default_hint":     "Connect your Arduino and click \"Refresh Slider Count\" to detect the actual number of sliders",
+	"web.targets_hint":             "Enter process names (e.g., chrome.exe) or special targets (master, mic, deej.unmapped, etc.)",
+	"web.targets_separator_hint":   "Multiple targets can be separated by commas",
+	"web.slider":                   "Slider %s:",
+	"web.slider.placeholder":       "e.g., chrome.exe, firefox.exe",
+	"web.slider_name.placeholder":  "Name (optional)",
+	"web.slider_name_for":          "Name of slider %s",
+	"web.pick_target":              "Pick Target",
+	"web.pick_target_for":          "Pick a target for slider %s",
+	"web.unmatched_targets":        "No audio session has matched %s yet. Check the spelling, or start the app and play something.",
+	"web.advanced":                 "Advanced",
+	"web.connection_settings":      "Connection Settings",
+	"web.permissions_notice":       "deej wasn't allowed to open %s.",
+	"web.fix_permissions":          "Fix permissions",
+	"web.com_port":                 "COM Port:",
+	"web.com_port.placeholder":     "e.g., COM4 or auto",
+	"web.baud_rate":                "Baud Rate:",
+	"web.inspector_hint":           "Having trouble with your board? The <a href=\"/inspector\" target=\"_blank\">serial traffic inspector</a> shows everything it sends, live.",
+	"web.request_version":          "Ask the board for its firmware version",
+	"web.explain":                  "Explain a slider",
+	"web.explain_hint":             "See what a slider controls right now: its targets, the audio sessions they matched and their volumes.",
+	"web.explain_slider":           "Slider:",
+	"web.explain_button":           "Explain",
+	"web.explain_preview_button":   "Preview unsaved mapping",
+	"web.test_tone":                "Play a test tone",
+	"web.test_tone_played":         "Played a test tone on %s",
+	"web.test_tone_failed":         "Failed to play a test tone: %s",
+	"web.explain_failed":           "Failed to explain the slider: %s",
+	"web.explain_position":         "The slider is at %s.",
+	"web.explain_position_unknown": "The board hasn't reported where the slider is yet.",
+	"web.explain_overridden":       "A temporary override replaced the targets from the config.",
+	"web.explain_preview":          "Preview of the targets typed for this slider above, which aren't saved yet.",
+	"web.explain_frozen":           "Slider input is ignored while the screen is locked.",
+	"web.explain_adopted":          "Its targets keep a volume set elsewhere until the slider moves.",
+	"web.explain_unmapped":         "Nothing is mapped to this slider.",
+	"web.explain_kind_name":        "by name",
+	"web.explain_kind_transform":   "special target",
+	"web.explain_kind_role":        "by role",
+	"web.explain_never_matched":    "No audio session has matched this target since it was mapped.",
+	"web.explain_resolved_nothing": "Stands for nothing right now",
+	"web.explain_sets":             "sets %s",
+	"web.explain_no_sessions":      "no audio session found",
+	"web.explain_sessions":         "%s audio sessions, now at %s",
+	"web.explain_muted":            "(muted)",
+	"web.explain_instance":         "%s: %s",
+	"web.loudness":                 "Even out loudness",
+	"web.loudness_hint":            "Some apps play much louder than others. Play something typical in each app you want to even out (or pink noise, if it can play it), then calibrate: deej listens to each app at full volume for a few seconds and turns the louder ones down, so the same slider position sounds about as loud everywhere.",
+	"web.loudness_calibrate":       "Calibrate",
+	"web.loudness_clear":           "Clear offsets",
+	"web.loudness_calibrating":     "Listening to every app that's playing, this takes a few seconds...",
+	"web.loudness_calibrated":      "Calibrated, the offsets apply right away.",
+	"web.loudness_nothing_playing": "None of your apps are playing anything, start some audio and try again.",
+	"web.loudness_failed":          "Failed to calibrate loudness: %s",
+	"web.loudness_none":            "No loudness offsets, every app plays as loud as it is.",
+	"web.loudness_offset":          "%s: %s dB",
+	"web.loudness_silent":          "%s: silent, not calibrated",
+	"web.timeline":                 "Event timeline",
+	"web.timeline_hint":            "What happened to your audio sessions, their volumes and the board's connection since deej started, newest first.",
+	"web.timeline_refresh":         "Refresh",
+	"web.timeline_failed":          "Failed to load the timeline: %s",
+	"web.timeline_empty":           "Nothing happened yet.",
+	"web.timeline_repeated":        "(%s times)",
+	"web.timeline_session_added":   "%s appeared",
+	"web.timeline_session_removed": "%s went away",
+	"web.timeline_volume":          "%s set to %s by slider %s",
+	"web.timeline_external_volume": "%s changed to %s by something other than deej (slider %s)",
+	"web.timeline_connected":       "Connected to the board on %s",
+	"web.timeline_disconnected":    "Disconnected from the board on %s",
+	"web.timeline_connect_failed":  "Couldn't connect to the board on %s: %s",
+	"web.timeline_audio_lost":      "Lost the connection to the audio server",
+	"web.timeline_audio_back":      "Reconnected to the audio server",
+	"web.remote_hint":              "The <a href=\"/remote\" target=\"_blank\">remote</a> has on-screen sliders that work just like your board's, even when it isn't plugged in. To use it from your phone, set web_host and web_token in config.yaml.",
+	"web.remote.title":             "deej Remote",
+	"web.remote.board_connected":   "Your board is connected, so it takes over again whenever one of its sliders moves.",
+	"web.remote.board_missing":     "Your board isn't connected, the sliders here are all there is.",
+	"web.remote.slider":            "%s: %s",
+	"web.remote.slider_unmapped":   "%s: not mapped",
+	"web.remote.failed":            "Lost touch with deej: %s",
+	"web.status.title":             "deej Status",
+	"web.status.connected":         "Connected (%s)",
+	"web.status.disconnected":      "Not connected",
+	"web.detect.probing":           "Looking for your deej board: trying %s at %s baud…",
+	"web.detect.failed":            "Couldn't find your deej board. It'll be picked up as soon as it's plugged in.",
+	"web.detect.found":             "%s at %s baud: deej found",
+	"web.detect.no_answer":         "%s at %s baud: no answer",
+	"web.detect.denied":            "%s: permission denied",
+	"web.detect.unavailable":       "%s: couldn't be opened",
+	"web.detect.skipped":           "%s: not probed (skip_ports or safe_probing)",
+	"web.status.unmapped":          "Slider %s",
+	"web.status.named":             "%s: %s",
+	"web.status.failed":            "Lost touch with deej: %s",
+	"web.status.unstable":          "Connection unstable!",
+	"web.status.disconnects":       "Disconnected %s times in the last %s minutes, last at %s.",
+	"web.status.suppressed":        "%s notifications held back.",
+	"web.firmware_version":         "Your board runs deej firmware %s",
+	"web.firmware_version_failed":  "Failed to get the firmware version: %s",
+	"web.hardware":                 "Hardware",
+	"web.board_settings":           "Board Settings",
+	"web.hardware_notice":          "These settings are sent to the board whenever it connects. Leave a field empty to keep the firmware's default.",
+	"web.hardware_unsupported":     "The connected board doesn't take settings from deej (or isn't connected). They'll be sent once a board that does connects. The report rate applies either way.",
+	"web.report_rate":              "Report rate (times per second):",
+	"web.led_brightness":           "LED brightness (0-255):",
+	"web.dim_after":                "Dim the display and LEDs after (seconds untouched):",
+	"web.off_after":                "Turn them off after (seconds untouched):",
+	"web.idle_hint":                "Leave empty to keep them on. Moving a slider or pressing a button wakes them up.",
+	"web.smoothing":                "Smoothing %s:",
+	"web.smoothing.placeholder":    "readings to average",
+	"web.led_colors_hint":          "For boards with RGB LEDs: give each slider a color, so it matches its name on screen.",
+	"web.led_color":                "Color %s:",
+	"web.led_color_clear":          "Clear",
+	"web.led_color_clear_for":      "Clear the color of slider %s",
+	"web.save_hardware":            "Save and Send to Board",
+	"web.board_storage":            "Stored on the Board",
+	"web.board_storage_hint":       "Boards that keep settings in their EEPROM take them along to other computers. Read them from the board to edit them, then write them back.",
+	"web.board_name":               "Board name:",
+	"web.board_sliders":            "Number of sliders:",
+	"web.board_slider_name":        "Slider %s name:",
+	"web.board_read":               "Read from Board",
+	"web.board_write":              "Write to Board",
+	"web.board_read_done":          "Read the settings stored on the board",
+	"web.board_read_failed":        "Failed to read the board's settings: %s",
+	"web.board_written":            "Stored the settings on the board",
+	"web.board_write_failed":       "Failed to store the settings on the board: %s",
+	"web.other_settings":           "Other Settings",
+	"web.autostart":                "Start deej at login",
+	"web.verbose":                  "Verbose logging (to capture a problem in deej's logs)",
+	"web.invert_sliders":           "Invert sliders",
+	"web.theme":                    "Theme:",
+	"web.theme_auto":               "Match system",
+	"web.theme_light":              "Light",
+	"web.theme_dark":               "Dark",
+	"web.theme_failed":             "Failed to change theme: %s",
+	"web.noise_reduction":          "Noise Reduction:",
+	"web.noise_low":                "Low (excellent hardware)",
+	"web.noise_default":            "Default (regular hardware)",
+	"web.noise_high":               "High (bad, noisy hardware)",
+	"web.session_refresh":          "Finding new apps:",
+	"web.session_refresh_auto":     "Automatically",
+	"web.session_refresh_manual":   "Only when asked to",
+	"web.refresh_frequency":        "At most every (seconds):",
+	"web.session_refresh_hint":     "Scanning for apps takes a moment, which slow machines can notice. Set this to only when asked to, and deej finds apps started after it only when you choose \"Re-scan audio sessions\" from the tray or rescan running applications here.",
+	"web.cancel":                   "Cancel",
+	"web.save":                     "Save Configuration",
+	"web.select_target":            "Select Audio Target",
+	"web.rescan_running":           "Rescan Running Applications",
+	"web.loading_targets":          "Loading available targets...",
+	"web.rescanning":               "Rescanning running applications...",
+	"web.search_installed":         "Search installed applications...",
+	"web.targets_failed":           "Failed to load targets: %s",
+	"web.system_controls":          "System Controls",
+	"web.running_apps":             "Running Applications",
+	"web.mpris_players":            "Unmatched MPRIS Players",
+	"web.audio_devices":            "Audio Devices",
+	"web.installed_apps":           "Installed Applications (%s)",
+	"web.other_category":           "Other",
+	"web.no_targets":               "No audio targets found",
+	"web.playing":                  "%s - Playing: %s",
+	"web.by_artist":                "%s by %s",
+	"web.now_playing":              "Now playing: %s",
+	"web.saved":                    "Configuration saved successfully!",
+	"web.save_failed":              "Failed to save configuration: %s",
+	"web.load_failed":              "Failed to load configuration: %s",
+	"web.slider_count_refreshed":   "Slider count refreshed: %s slider(s) detected",
+	"web.slider_count_failed":      "Failed to refresh slider count: %s",
+	"web.hardware_saved":           "Hardware settings saved",
+	"web.hardware_failed":          "Failed to save hardware settings: %s",
+	"web.permissions_started":      "Follow the prompts to fix serial port permissions",
+	"web.permissions_failed":       "Failed to start permissions helper: %s",
+	"web.autostart_on":             "deej will start at login",
+	"web.autostart_off":            "deej won't start at login anymore",
+	"web.autostart_failed":         "Failed to change autostart: %s",
+	"web.verbose_on":               "Verbose logging enabled",
+	"web.verbose_off":              "Verbose logging disabled",
+	"web.verbose_failed":           "Failed to change verbose logging: %s",
+	"web.update_available":         "deej %s is available.",
+	"web.whats_new":                "What's new",
+	"web.update":                   "Update",
+	"web.updating":                 "Updating...",
+	"web.restart_to_finish":        "Restart deej to finish",
+	"web.updated":                  "deej was updated, restart it to start using the new version",
+	"web.update_failed":            "Failed to update deej: %s",
+	"web.setup.title":              "Set up deej",
+	"web.setup.intro":              "Plug in your deej, then pick the port it's on. deej marks the ports it found a board on.",
+	"web.setup.looking":            "Looking for your deej, this can take a few seconds...",
+	"web.setup.look_again":         "Look again",
+	"web.setup.save":               "Save",
+	"web.setup.full_config":        "Go to the full configuration",
+	"web.setup.auto":               "Find it automatically",
+	"web.setup.connected":          "%s (connected)",
+	"web.setup.deej_found":         "%s (deej found)",
+	"web.setup.skipped":            "%s (not probed, see skip_ports and safe_probing)",
+	"web.setup.ports_failed":       "Couldn't list serial ports: %s",
+	"web.setup.saved":              "Saved! deej will connect to your board in a moment.",
+	"web.setup.save_failed":        "Failed to save: %s",
+	"web.setup.all_set":            "Connected to your deej on %s. You're all set!",
+}
@@ -0,0 +1,347 @@
+package deej
+
+var catalogES = map[string]string{
+
+	// tray menu
+	"tray.edit_config":                     "Editar configuración",
+	"tray.edit_config.tooltip":             "Abrir el archivo de configuración en el editor",
+	"tray.config_window":                   "Ventana de configuración",
+	"tray.config_window.tooltip":           "Abrir la interfaz web de configuración",
+	"tray.refresh_sessions":                "Volver a buscar sesiones de audio",
+	"tray.refresh_sessions.tooltip":        "Actualizar las sesiones de audio a mano si algo se atasca",
+	"tray.refresh_sessions.tooltip_manual": "deej solo encuentra las apps recién abiertas cuando eliges esto (session_refresh es manual)",
+	"tray.reconnect_audio":                 "Reconectar el backend de audio",
+	"tray.reconnect_audio.tooltip":         "Volver a conectar con el sistema de audio, p. ej. tras reiniciarlo o reemplazarlo, sin reiniciar deej",
+	"tray.fix_permissions":                 "Reparar permisos de puertos serie",
+	"tray.fix_permissions.tooltip":         "Obtener acceso a los puertos serie que deej no pudo abrir",
+	"tray.setup_board":                     "Configurar la placa deej",
+	"tray.setup_board.tooltip":             "Elegir el puerto al que está conectado tu deej",
+	"tray.autostart":                       "Iniciar deej al iniciar sesión",
+	"tray.autostart.tooltip":               "Iniciar deej cada vez que inicies sesión",
+	"tray.verbose":                         "Registro detallado",
+	"tray.verbose.tooltip":                 "Registrar todo lo que hace deej, para capturar un problema en detalle",
+	"tray.update":                          "Actualizar deej",
+	"tray.update.tooltip":                  "Instalar la última versión de deej",
+	"tray.update_to":                       "Actualizar a deej %s",
+	"tray.arduino":                         "Comandos del Arduino",
+	"tray.arduino.tooltip":                 "Enviar comandos al Arduino",
+	"tray.reboot_arduino":                  "Reiniciar Arduino",
+	"tray.reboot_arduino.tooltip":          "Reiniciar el Arduino por software",
+	"tray.request_version":                 "Consultar versión",
+	"tray.request_version.tooltip":         "Obtener la versión del firmware del Arduino",
+	"tray.device_disconnected":             "Ningún deej conectado",
+	"tray.device_detecting":                "Buscando deej: probando %s a %s baudios",
+	"tray.device_not_found":                "No se encontró deej en %s puertos",
+	"tray.schedule_next":                   "Siguiente: %s a las %s",
+	"tray.notification":                    "%s: %s",
+	"tray.notification.tooltip":            "Las notificaciones de escritorio no están disponibles (falta: %s), así que la última se muestra aquí",
+	"tray.device_port":                     "deej en %s",
+	"tray.device_firmware":                 "firmware %s",
+	"tray.device_legacy_firmware":          "firmware antiguo",
+	"tray.device_sliders":                  "%s deslizadores",
+	"tray.slider":                          "Deslizador %s",
+	"tray.slider_readout":                  "%s %s%%",
+	"tray.quit":                            "Salir",
+	"tray.quit.tooltip":                    "Detener deej y salir",
+
+	// notifications
+	"notify.profile_switched.title":        "Perfil cambiado",
+	"notify.profile_switched.message":      "Ahora se usa la asignación de deslizadores %s.",
+	"notify.config_not_found.title":        "¡No se encuentra la configuración!",
+	"notify.config_not_found.message":      "%s debe estar en la misma carpeta que deej, o en una carpeta deej dentro de tu carpeta de configuración de usuario (~/.config/deej en Linux, AppData\\Roaming\\deej en Windows). Vuelve a iniciarlo",
+	"notify.config_invalid.title":          "¡Configuración no válida!",
+	"notify.config_invalid_yaml.message":   "Asegúrate de que %s tenga un formato YAML válido.",
+	"notify.config_error.title":            "¡Error al cargar la configuración!",
+	"notify.config_error.message":          "Consulta los registros de deej para más detalles.",
+	"notify.config_previous_kept":          "%s Tu configuración anterior sigue en uso hasta que se corrija.",
+	"notify.config_reloaded.title":         "¡Configuración recargada!",
+	"notify.config_reloaded.message":       "Se han aplicado tus cambios.",
+	"notify.config_migration.title":        "¿Mover tu configuración de deej?",
+	"notify.config_migration.message":      "deej encontró %s en la carpeta desde la que se inició. ¿Moverlo a %s la próxima vez que se inicie deej, para que deej lo encuentre sin importar desde dónde se inicie? El archivo antiguo se conserva como copia de seguridad.",
+	"notify.config_migration.inform":       "deej también busca config.yaml en %s. Muévelo allí para poder iniciar deej desde cualquier lugar.",
+	"notify.config_migration.failed":       "deej no pudo mover tu configuración y la sigue usando donde está: %s",
+	"notify.welcome.title":                 "¡Bienvenido a deej!",
+	"notify.welcome.message":               "Conecta tu deej y elige su puerto en la página de configuración inicial para empezar.",
+	"notify.cant_connect.title":            "¡No se puede conectar a %s!",
+	"notify.port_busy.message":             "Este puerto serie está ocupado. Cierra cualquier monitor serie u otra instancia de deej.",
+	"notify.port_missing.message":          "Este puerto serie no existe. Revisa que esté bien configurado.",
+	"notify.port_missing_setup.title":      "¡No se encuentra %s!",
+	"notify.port_missing_setup.message":    "Este puerto serie no existe. Elige el puerto al que está conectado tu deej en la página de configuración inicial.",
+	"notify.port_permission.title":         "¡No se puede acceder a %s!",
+	"notify.port_permission.message":       "deej no tiene permiso para usar este puerto serie. Elige \"Reparar permisos de puertos serie\" en el menú de la bandeja para solucionarlo.",
+	"notify.doctor.title":                  "Autoprueba de deej",
+	"notify.doctor.message":                "Si puedes ver esto, las notificaciones funcionan.",
+	"notify.trace_replay_failed.title":     "¡No se puede reproducir la grabación de deslizadores!",
+	"notify.incompatible_firmware.title":   "¡Firmware de deej incompatible!",
+	"notify.incompatible_firmware.message": "Tu dispositivo tiene el firmware %s, pero esta versión de deej espera %s. Actualiza deej o vuelve a programar tu placa.",
+	"notify.arduino_reboot.title":          "Reinicio del Arduino",
+	"notify.arduino_reboot.message":        "Enviando el comando de reinicio al Arduino...",
+	"notify.arduino_reboot_failed.title":   "¡No se puede reiniciar el Arduino!",
+	"notify.firmware_version.title":        "Firmware del Arduino",
+	"notify.firmware_version.message":      "Tu placa usa el firmware de deej %s.",
+	"notify.firmware_version_failed.title": "¡No se puede obtener la versión del firmware!",
+	"notify.audio_lost.title":              "¡Se perdió la conexión con el servidor de audio!",
+	"notify.audio_lost.message":            "deej seguirá intentando reconectarse. Hasta entonces, tus deslizadores no harán nada.",
+	"notify.audio_back.title":              "Reconectado al servidor de audio",
+	"notify.audio_back.message":            "Tus deslizadores vuelven a tener el control.",
+	"notify.autostart_failed.title":        "¡No se puede cambiar el inicio automático!",
+	"notify.update_failed.title":           "¡No se puede actualizar deej!",
+	"notify.update_available.title":        "¡deej %s está disponible!",
+	"notify.update_available.notes":        "Mira las novedades en %s.",
+	"notify.update_available.install":      "Instálalo desde el menú de la bandeja o la ventana de configuración.",
+	"notify.updated.title":                 "¡Actualizado a deej %s!",
+	"notify.updated.message":               "Reinicia deej para empezar a usarlo.",
+	"notify.unmatched_targets.title":       "Algunos deslizadores no controlan nada",
+	"notify.unmatched_targets.message":     "Ninguna sesión de audio ha coincidido aún con %s. Revisa cómo está escrito en tu configuración, o abre la aplicación y reproduce algo.",
+	"notify.web_config_failed.title":       "¡No se puede abrir la ventana de configuración!",
+	"notify.web_config_failed.message":     "deej no pudo iniciar su servidor web: %s",
+	"notify.connection_unstable.title":     "La conexión con %s se corta una y otra vez",
+	"notify.connection_unstable.message":   "deej perdió la conexión %s veces en %s minutos, normalmente por un cable USB suelto o defectuoso. Seguirá reconectando sin más notificaciones, la página de estado tiene los detalles.",
+	"notify.baud_rate_mismatch.title":      "La velocidad en baudios no coincide",
+	"notify.baud_rate_mismatch.message":    "Tu placa deej funciona a %d baudios, pero %s está configurado a %d en tu configuración. ¿Guardar %d en tu configuración?",
+	"notify.baud_rate_mismatch.inform":     "Tu placa deej funciona a %d baudios. Configura %s a %d en tu configuración para que coincida.",
+	"notify.degraded.unknown":              "no se pudieron enviar las notificaciones",
+	"dialog.yes":                           "Sí",
+	"dialog.no":                            "No",
+
+	// serial port permission dialogs, see permissions_linux.go
+	"permissions.title":                  "Permisos de puertos serie",
+	"permissions.no_problems":            "deej no ha encontrado ningún problema de permisos con los puertos serie.",
+	"permissions.unknown_group.title":    "No se pueden arreglar los permisos de puertos serie",
+	"permissions.unknown_group.message":  "Permiso denegado al abrir %s, pero deej no pudo saber qué grupo es su propietario.\n\nRevisa los permisos del dispositivo manualmente.",
+	"permissions.already_member.title":   "Ya eres miembro",
+	"permissions.already_member.message": "Ya eres miembro del grupo '%s'.\n\nCierra la sesión y vuelve a iniciarla si sigues teniendo problemas.",
+	"permissions.manual.message":         "Permiso denegado al abrir %s. Para arreglarlo, ejecuta:\n%s\ny después cierra la sesión y vuelve a iniciarla.",
+	"permissions.confirm.message":        "Permiso denegado al abrir %s.\n\n¿Quieres añadirte al grupo '%s'?\n\nSe te pedirá tu contraseña.",
+	"permissions.cancelled.title":        "Acción cancelada",
+	"permissions.cancelled.message":      "No se ha realizado ningún cambio.",
+	"permissions.failed.title":           "Error",
+	"permissions.failed.message":         "No se te pudo añadir al grupo.\n\nEjecuta este comando manualmente:\n%s",
+	"permissions.added.title":            "Acción necesaria",
+	"permissions.added.message":          "Se te ha añadido al grupo.\n\nCierra la sesión y vuelve a iniciarla, y luego vuelve a ejecutar este programa para continuar.",
+
+	// errors, see error_catalog.go
+	"error.unknown.title":                 "¡Algo salió mal!",
+	"error.unknown.message":               "Consulta los registros de deej para más detalles.",
+	"error.docs":                          "Más ayuda: %s",
+	"error.config_not_found.hint":         "Para empezar, puedes copiar la configuración de ejemplo de la página de versiones de deej.",
+	"error.config_syntax.hint":            "Usa espacios en lugar de tabuladores para sangrar y pon un espacio después de cada dos puntos.",
+	"error.config_invalid.hint":           "Corrígelo y guarda el archivo, deej aplica el cambio al instante.",
+	"error.device_not_found.title":        "¡No se encuentra tu deej!",
+	"error.device_not_found.message":      "Ninguno de los dispositivos conectados respondió como una placa deej.",
+	"error.device_not_found.hint":         "Asegúrate de que tu placa está conectada y ejecuta el sketch de deej, o elige su puerto en la página de configuración inicial.",
+	"error.not_connected.message":         "Tu placa deej no está conectada.",
+	"error.not_connected.hint":            "Conéctala y deej se conectará a ella por sí solo.",
+	"error.device_unresponsive.title":     "¡Tu placa deej no respondió!",
+	"error.device_unresponsive.message":   "Tu placa no respondió a tiempo.",
+	"error.device_unresponsive.hint":      "Prueba a desconectarla y volver a conectarla.",
+	"error.command_rejected.message":      "El firmware de tu placa no sabe hacer esto.",
+	"error.update_firmware.hint":          "Vuelve a cargar en tu placa el sketch que viene con esta versión de deej.",
+	"error.firmware_incompatible.message": "El firmware de tu placa usa una versión del protocolo distinta a la de esta versión de deej.",
+	"error.unsupported.message":           "Tu placa no tiene el hardware necesario para esto.",
+	"error.audio_unavailable.title":       "¡No se puede acceder a tu sistema de audio!",
+	"error.audio_unavailable.message":     "deej no pudo obtener tus sesiones de audio, así que los deslizadores no pueden controlar nada.",
+	"error.audio_unavailable.hint":        "Asegúrate de que tu servicio de audio está en marcha (Audio de Windows, o PulseAudio o PipeWire en Linux).",
+
+	// web UI
+	"web.title":                    "Configuración de deej",
+	"web.slider_mappings":          "Asignación de deslizadores",
+	"web.refresh_slider_count":     "Actualizar número de deslizadores",
+	"web.sliders_detected":         "Se detectaron %s deslizador(es) en el Arduino",
+	"web.sliders_default":          "Arduino no conectado - se muestran %s deslizadores, los que tenía la última vez (o default_slider_count)",
+	"web.sliders_default_hint":     "Conecta tu Arduino y pulsa \"Actualizar número de deslizadores\" para detectar cuántos tiene",
+	"web.targets_hint":             "Escribe nombres de procesos (p. ej., chrome.exe) u objetivos especiales (master, mic, deej.unmapped, etc.)",
+	"web.targets_separator_hint":   "Puedes separar varios objetivos con comas",
+	"web.slider":                   "Deslizador %s:",
+	"web.slider.placeholder":       "p. ej., chrome.exe, firefox.exe",
+	"web.slider_name.placeholder":  "Nombre (opcional)",
+	"web.slider_name_for":          "Nombre del deslizador %s",
+	"web.pick_target":              "Elegir objetivo",
+	"web.pick_target_for":          "Elegir un objetivo para el deslizador %s",
+	"web.unmatched_targets":        "Ninguna sesión de audio ha coincidido aún con %s. Revisa cómo está escrito, o abre la aplicación y reproduce algo.",
+	"web.advanced":                 "Avanzado",
+	"web.connection_settings":      "Ajustes de conexión",
+	"web.permissions_notice":       "deej no pudo abrir %s.",
+	"web.fix_permissions":          "Reparar permisos",
+	"web.com_port":                 "Puerto COM:",
+	"web.com_port.placeholder":     "p. ej., COM4 o auto",
+	"web.baud_rate":                "Velocidad en baudios:",
+	"web.inspector_hint":           "¿Problemas con tu placa? El <a href=\"/inspector\" target=\"_blank\">inspector de tráfico serie</a> muestra en directo todo lo que envía.",
+	"web.request_version":          "Preguntar a la placa su versión de firmware",
+	"web.explain":                  "Explicar un deslizador",
+	"web.explain_hint":             "Mira qué controla un deslizador ahora mismo: sus destinos, las sesiones de audio que encontraron y su volumen.",
+	"web.explain_slider":           "Deslizador:",
+	"web.explain_button":           "Explicar",
+	"web.explain_preview_button":   "Previsualizar asignación sin guardar",
+	"web.test_tone":                "Reproducir un tono de prueba",
+	"web.test_tone_played":         "Tono de prueba reproducido en %s",
+	"web.test_tone_failed":         "No se pudo reproducir el tono de prueba: %s",
+	"web.explain_failed":           "No se pudo explicar el deslizador: %s",
+	"web.explain_position":         "El deslizador está en %s.",
+	"web.explain_position_unknown": "La placa aún no ha informado de dónde está el deslizador.",
+	"web.explain_overridden":       "Una anulación temporal reemplazó los destinos de la configuración.",
+	"web.explain_preview":          "Vista previa de los destinos escritos arriba para este deslizador, que aún no se han guardado.",
+	"web.explain_frozen":           "Los deslizadores se ignoran mientras la pantalla está bloqueada.",
+	"web.explain_adopted":          "Sus destinos mantienen un volumen fijado en otro lugar hasta que el deslizador se mueva.",
+	"web.explain_unmapped":         "No hay nada asignado a este deslizador.",
+	"web.explain_kind_name":        "por nombre",
+	"web.explain_kind_transform":   "destino especial",
+	"web.explain_kind_role":        "por rol",
+	"web.explain_never_matched":    "Ninguna sesión de audio ha coincidido con este destino desde que se asignó.",
+	"web.explain_resolved_nothing": "Ahora mismo no representa nada",
+	"web.explain_sets":             "fija %s",
+	"web.explain_no_sessions":      "no se encontró ninguna sesión de audio",
+	"web.explain_sessions":         "%s sesiones de audio, ahora en %s",
+	"web.explain_muted":            "(silenciada)",
+	"web.explain_instance":         "%s: %s",
+	"web.loudness":                 "Igualar el volumen percibido",
+	"web.loudness_hint":            "Algunas aplicaciones suenan mucho más fuerte que otras. Reproduce algo típico en cada aplicación que quieras igualar (o ruido rosa, si puede reproducirlo) y luego calibra: deej escucha cada aplicación a todo volumen durante unos segundos y baja las más fuertes, para que la misma posición del deslizador suene más o menos igual de fuerte en todas.",
+	"web.loudness_calibrate":       "Calibrar",
+	"web.loudness_clear":           "Borrar ajustes",
+	"web.loudness_calibrating":     "Escuchando cada aplicación que está sonando, esto tarda unos segundos...",
+	"web.loudness_calibrated":      "Calibrado, los ajustes se aplican de inmediato.",
+	"web.loudness_nothing_playing": "Ninguna de tus aplicaciones está reproduciendo nada. Pon algo de audio e inténtalo de nuevo.",
+	"web.loudness_failed":          "No se pudo calibrar el volumen: %s",
+	"web.loudness_none":            "Sin ajustes de volumen, cada aplicación suena tan fuerte como es.",
+	"web.loudness_offset":          "%s: %s dB",
+	"web.loudness_silent":          "%s: en silencio, sin calibrar",
+	"web.timeline":                 "Historial de eventos",
+	"web.timeline_hint":            "Lo que ha pasado con tus sesiones de audio, su volumen y la conexión con la placa desde que se inició deej, lo más reciente primero.",
+	"web.timeline_refresh":         "Actualizar",
+	"web.timeline_failed":          "No se pudo cargar el historial: %s",
+	"web.timeline_empty":           "Todavía no ha pasado nada.",
+	"web.timeline_repeated":        "(%s veces)",
+	"web.timeline_session_added":   "%s apareció",
+	"web.timeline_session_removed": "%s desapareció",
+	"web.timeline_volume":          "%s fijado en %s por el deslizador %s",
+	"web.timeline_external_volume": "%s cambió a %s por algo distinto de deej (deslizador %s)",
+	"web.timeline_connected":       "Conectado a la placa en %s",
+	"web.timeline_disconnected":    "Desconectado de la placa en %s",
+	"web.timeline_connect_failed":  "No se pudo conectar a la placa en %s: %s",
+	"web.timeline_audio_lost":      "Se perdió la conexión con el servidor de audio",
+	"web.timeline_audio_back":      "Reconectado al servidor de audio",
+	"web.remote_hint":              "El <a href=\"/remote\" target=\"_blank\">control remoto</a> tiene deslizadores en pantalla que funcionan igual que los de tu placa, incluso cuando no está conectada. Para usarlo desde el móvil, configura web_host y web_token en config.yaml.",
+	"web.remote.title":             "Control remoto de deej",
+	"web.remote.board_connected":   "Tu placa está conectada, así que vuelve a tomar el control cuando se mueve uno de sus deslizadores.",
+	"web.remote.board_missing":     "Tu placa no está conectada, solo están los deslizadores de aquí.",
+	"web.remote.slider":            "%s: %s",
+	"web.remote.slider_unmapped":   "%s: sin asignar",
+	"web.remote.failed":            "Se perdió el contacto con deej: %s",
+	"web.status.title":             "Estado de deej",
+	"web.status.connected":         "Conectado (%s)",
+	"web.status.disconnected":      "No conectado",
+	"web.detect.probing":           "Buscando tu placa deej: probando %s a %s baudios…",
+	"web.detect.failed":            "No se encontró tu placa deej. Se detectará en cuanto la conectes.",
+	"web.detect.found":             "%s a %s baudios: deej encontrado",
+	"web.detect.no_answer":         "%s a %s baudios: sin respuesta",
+	"web.detect.denied":            "%s: permiso denegado",
+	"web.detect.unavailable":       "%s: no se pudo abrir",
+	"web.detect.skipped":           "%s: no sondeado (skip_ports o safe_probing)",
+	"web.status.unmapped":          "Deslizador %s",
+	"web.status.named":             "%s: %s",
+	"web.status.failed":            "Se perdió el contacto con deej: %s",
+	"web.status.unstable":          "¡Conexión inestable!",
+	"web.status.disconnects":       "Desconectado %s veces en los últimos %s minutos, la última a las %s.",
+	"web.status.suppressed":        "%s notificaciones retenidas.",
+	"web.firmware_version":         "Tu placa usa el firmware de deej %s",
+	"web.firmware_version_failed":  "No se pudo obtener la versión del firmware: %s",
+	"web.hardware":                 "Hardware",
+	"web.board_settings":           "Ajustes de la placa",
+	"web.hardware_notice":          "Estos ajustes se envían a la placa cada vez que se conecta. Deja un campo vacío para mantener el valor por defecto del firmware.",
+	"web.hardware_unsupported":     "La placa conectada no acepta ajustes de deej (o no está conectada). Se enviarán cuando se conecte una placa que sí los acepte. La frecuencia de envío se aplica de todos modos.",
+	"web.report_rate":              "Frecuencia de envío (veces por segundo):",
+	"web.led_brightness":           "Brillo de los LED (0-255):",
+	"web.dim_after":                "Atenuar la pantalla y los LED tras (segundos sin tocar):",
+	"web.off_after":                "Apagarlos tras (segundos sin tocar):",
+	"web.idle_hint":                "Déjalo vacío para mantenerlos encendidos. Mover un deslizador o pulsar un botón los despierta.",
+	"web.smoothing":                "Suavizado %s:",
+	"web.smoothing.placeholder":    "lecturas a promediar",
+	"web.led_colors_hint":          "Para placas con LED RGB: asigna un color a cada deslizador, para que coincida con su nombre en pantalla.",
+	"web.led_color":                "Color %s:",
+	"web.led_color_clear":          "Quitar",
+	"web.led_color_clear_for":      "Quitar el color del deslizador %s",
+	"web.save_hardware":            "Guardar y enviar a la placa",
+	"web.board_storage":            "Guardado en la placa",
+	"web.board_storage_hint":       "Las placas que guardan ajustes en su EEPROM los llevan consigo a otros ordenadores. Léelos de la placa para editarlos y luego vuelve a escribirlos.",
+	"web.board_name":               "Nombre de la placa:",
+	"web.board_sliders":            "Número de deslizadores:",
+	"web.board_slider_name":        "Nombre del deslizador %s:",
+	"web.board_read":               "Leer de la placa",
+	"web.board_write":              "Escribir en la placa",
+	"web.board_read_done":          "Se leyeron los ajustes guardados en la placa",
+	"web.board_read_failed":        "No se pudieron leer los ajustes de la placa: %s",
+	"web.board_written":            "Se guardaron los ajustes en la placa",
+	"web.board_write_failed":       "No se pudieron guardar los ajustes en la placa: %s",
+	"web.other_settings":           "Otros ajustes",
+	"web.autostart":                "Iniciar deej al iniciar sesión",
+	"web.verbose":                  "Registro detallado (para capturar un problema en los registros de deej)",
+	"web.invert_sliders":           "Invertir deslizadores",
+	"web.theme":                    "Tema:",
+	"web.theme_auto":               "Igual que el sistema",
+	"web.theme_light":              "Claro",
+	"web.theme_dark":               "Oscuro",
+	"web.theme_failed":             "No se pudo cambiar el tema: %s",
+	"web.noise_reduction":          "Reducción de ruido:",
+	"web.noise_low":                "Baja (hardware excelente)",
+	"web.noise_default":            "Normal (hardware corriente)",
+	"web.noise_high":               "Alta (hardware malo o ruidoso)",
+	"web.session_refresh":          "Encontrar apps nuevas:",
+	"web.session_refresh_auto":     "Automáticamente",
+	"web.session_refresh_manual":   "Solo cuando se pida",
+	"web.refresh_frequency":        "Como mucho cada (segundos):",
+	"web.session_refresh_hint":     "Buscar apps lleva un momento, lo que se nota en equipos lentos. Con \"Solo cuando se pida\", deej solo encuentra las apps abiertas después de él cuando eliges \"Volver a buscar sesiones de audio\" en la bandeja o vuelves a buscar aplicaciones aquí.",
+	"web.cancel":                   "Cancelar",
+	"web.save":                     "Guardar configuración",
+	"web.select_target":            "Elegir objetivo de audio",
+	"web.rescan_running":           "Volver a buscar aplicaciones en ejecución",
+	"web.loading_targets":          "Cargando objetivos disponibles...",
+	"web.rescanning":               "Buscando aplicaciones en ejecución...",
+	"web.search_installed":         "Buscar aplicaciones instaladas...",
+	"web.targets_failed":           "No se pudieron cargar los objetivos: %s",
+	"web.system_controls":          "Controles del sistema",
+	"web.running_apps":             "Aplicaciones en ejecución",
+	"web.mpris_players":            "Reproductores MPRIS sin asociar",
+	"web.audio_devices":            "Dispositivos de audio",
+	"web.installed_apps":           "Aplicaciones instaladas (%s)",
+	"web.other_category":           "Otras",
+	"web.no_targets":               "No se encontraron objetivos de audio",
+	"web.playing":                  "%s - Reproduciendo: %s",
+	"web.by_artist":                "%s de %s",
+	"web.now_playing":              "Sonando: %s",
+	"web.saved":                    "¡Configuración guardada!",
+	"web.save_failed":              "No se pudo guardar la configuración: %s",
+	"web.load_failed":              "No se pudo cargar la configuración: %s",
+	"web.slider_count_refreshed":   "Número de deslizadores actualizado: %s detectado(s)",
+	"web.slider_count_failed":      "No se pudo actualizar el número de deslizadores: %s",
+	"web.hardware_saved":           "Ajustes de hardware guardados",
+	"web.hardware_failed":          "No se pudieron guardar los ajustes de hardware: %s",
+	"web.permissions_started":      "Sigue las instrucciones para reparar los permisos de los puertos serie",
+	"web.permissions_failed":       "No se pudo iniciar el asistente de permisos: %s",
+	"web.autostart_on":             "deej se iniciará al iniciar sesión",
+	"web.autostart_off":            "deej ya no se iniciará al iniciar sesión",
+	"web.autostart_failed":         "No se pudo cambiar el inicio automático: %s",
+	"web.verbose_on":               "Registro detallado activado",
+	"web.verbose_off":              "Registro detallado desactivado",
+	"web.verbose_failed":           "No se pudo cambiar el registro detallado: %s",
+	"web.update_available":         "deej %s está disponible.",
+	"web.whats_new":                "Novedades",
+	"web.update":                   "Actualizar",
+	"web.updating":                 "Actualizando...",
+	"web.restart_to_finish":        "Reinicia deej para terminar",
+	"web.updated":                  "deej se actualizó, reinícialo para usar la nueva versión",
+	"web.update_failed":            "No se pudo actualizar deej: %s",
+	"web.setup.title":              "Configurar deej",
+	"web.setup.intro":              "Conecta tu deej y elige el puerto en el que está. deej marca los puertos en los que encontró una placa.",
+	"web.setup.looking":            "Buscando tu deej, esto puede tardar unos segundos...",
+	"web.setup.look_again":         "Buscar de nuevo",
+	"web.setup.save":               "Guardar",
+	"web.setup.full_config":        "Ir a la configuración completa",
+	"web.setup.auto":               "Encontrarlo automáticamente",
+	"web.setup.connected":          "%s (conectado)",
+	"web.setup.deej_found":         "%s (deej encontrado)",
+	"web.setup.skipped":            "%s (no sondeado, ver skip_ports y safe_probing)",
+	"web.setup.ports_failed":       "No se pudieron listar los puertos serie: %s",
+	"web.setup.saved":              "¡Guardado! deej se conectará a tu placa en un momento.",
+	"web.setup.save_failed":        "Error al guardar: %s",
+	"web.setup.all_set":            "Conectado a tu deej en %s. ¡Todo listo!",
+}
@@ -0,0 +1,347 @@
+package deej
+
+var catalogFR = map[string]string{
+
+	// tray menu
+	"tray.edit_config":                     "Modifier la configuration",
+	"tray.edit_config.tooltip":             "Ouvrir le fichier de configuration dans l'éditeur",
+	"tray.config_window":                   "Fenêtre de configuration",
+	"tray.config_window.tooltip":           "Ouvrir l'interface web de configuration",
+	"tray.refresh_sessions":                "Rechercher à nouveau les sessions audio",
+	"tray.refresh_sessions.tooltip":        "Actualiser les sessions audio manuellement si quelque chose bloque",
+	"tray.refresh_sessions.tooltip_manual": "deej ne trouve les applications lancées depuis que lorsque vous choisissez ceci (session_refresh est sur manual)",
+	"tray.reconnect_audio":                 "Reconnecter le backend audio",
+	"tray.reconnect_audio.tooltip":         "Se reconnecter au système audio, par exemple après son redémarrage ou son remplacement, sans redémarrer deej",
+	"tray.fix_permissions":                 "Réparer les permissions des ports série",
+	"tray.fix_permissions.tooltip":         "Obtenir l'accès aux ports série que deej n'a pas pu ouvrir",
+	"tray.setup_board":                     "Configurer la carte deej",
+	"tray.setup_board.tooltip":             "Choisir le port auquel votre deej est branché",
+	"tray.autostart":                       "Lancer deej à l'ouverture de session",
+	"tray.autostart.tooltip":               "Lancer deej à chaque ouverture de session",
+	"tray.verbose":                         "Journalisation détaillée",
+	"tray.verbose.tooltip":                 "Journaliser tout ce que fait deej, pour capturer un problème en détail",
+	"tray.update":                          "Mettre à jour deej",
+	"tray.update.tooltip":                  "Installer la dernière version de deej",
+	"tray.update_to":                       "Mettre à jour vers deej %s",
+	"tray.arduino":                         "Commandes Arduino",
+	"tray.arduino.tooltip":                 "Envoyer des commandes à l'Arduino",
+	"tray.reboot_arduino":                  "Redémarrer l'Arduino",
+	"tray.reboot_arduino.tooltip":          "Redémarrer l'Arduino de façon logicielle",
+	"tray.request_version":                 "Demander la version",
+	"tray.request_version.tooltip":         "Obtenir la version du firmware de l'Arduino",
+	"tray.device_disconnected":             "Aucun deej connecté",
+	"tray.device_detecting":                "Recherche de deej : essai de %s à %s bauds",
+	"tray.device_not_found":                "Aucun deej trouvé sur %s ports",
+	"tray.schedule_next":                   "Ensuite : %s à %s",
+	"tray.notification":                    "%s : %s",
+	"tray.notification.tooltip":            "Les notifications de bureau ne sont pas disponibles (il manque : %s), la dernière s'affiche donc ici",
+	"tray.device_port":                     "deej sur %s",
+	"tray.device_firmware":                 "firmware %s",
+	"tray.device_legacy_firmware":          "ancien firmware",
+	"tray.device_sliders":                  "%s curseurs",
+	"tray.slider":                          "Curseur %s",
+	"tray.slider_readout":                  "%s %s %%",
+	"tray.quit":                            "Quitter",
+	"tray.quit.tooltip":                    "Arrêter deej et quitter",
+
+	// notifications
+	"notify.profile_switched.title":        "Profil changé",
+	"notify.profile_switched.message":      "L'affectation des curseurs %s est maintenant utilisée.",
+	"notify.config_not_found.title":        "Configuration introuvable !",
+	"notify.config_not_found.message":      "%s doit se trouver dans le même dossier que deej, ou dans un dossier deej de votre dossier de configuration utilisateur (~/.config/deej sous Linux, AppData\\Roaming\\deej sous Windows). Veuillez le relancer",
+	"notify.config_invalid.title":          "Configuration invalide !",
+	"notify.config_invalid_yaml.message":   "Vérifiez que %s est au format YAML valide.",
+	"notify.config_error.title":            "Erreur au chargement de la configuration !",
+	"notify.config_error.message":          "Consultez les journaux de deej pour plus de détails.",
+	"notify.config_previous_kept":          "%s Votre configuration précédente reste en vigueur jusqu'à ce que ce soit corrigé.",
+	"notify.config_reloaded.title":         "Configuration rechargée !",
+	"notify.config_reloaded.message":       "Vos modifications ont été appliquées.",
+	"notify.config_migration.title":        "Déplacer votre configuration deej ?",
+	"notify.config_migration.message":      "deej a trouvé %s dans le dossier depuis lequel il a été lancé. Le déplacer dans %s au prochain lancement de deej, pour que deej le trouve quel que soit l'endroit d'où il est lancé ? L'ancien fichier est conservé comme sauvegarde.",
+	"notify.config_migration.inform":       "deej cherche aussi config.yaml dans %s. Déplacez-le là-bas pour lancer deej depuis n'importe où.",
+	"notify.config_migration.failed":       "deej n'a pas pu déplacer votre configuration et continue de l'utiliser là où elle est : %s",
+	"notify.welcome.title":                 "Bienvenue dans deej !",
+	"notify.welcome.message":               "Branchez votre deej et choisissez son port sur la page de configuration pour commencer.",
+	"notify.cant_connect.title":            "Impossible de se connecter à %s !",
+	"notify.port_busy.message":             "Ce port série est occupé. Fermez tout moniteur série ou autre instance de deej.",
+	"notify.port_missing.message":          "Ce port série n'existe pas. Vérifiez qu'il est correctement indiqué dans votre configuration.",
+	"notify.port_missing_setup.title":      "%s introuvable !",
+	"notify.port_missing_setup.message":    "Ce port série n'existe pas. Choisissez le port auquel votre deej est branché sur la page de configuration.",
+	"notify.port_permission.title":         "Accès à %s impossible !",
+	"notify.port_permission.message":       "deej n'a pas la permission d'utiliser ce port série. Choisissez \"Réparer les permissions des ports série\" dans le menu de la zone de notification pour y remédier.",
+	"notify.doctor.title":                  "Autotest de deej",
+	"notify.doctor.message":                "Si vous voyez ceci, les notifications fonctionnent.",
+	"notify.trace_replay_failed.title":     "Impossible de rejouer l'enregistrement des curseurs !",
+	"notify.incompatible_firmware.title":   "Firmware deej incompatible !",
+	"notify.incompatible_firmware.message": "Votre appareil utilise le firmware %s, mais cette version de deej attend %s. Mettez deej à jour ou reflashez votre carte.",
+	"notify.arduino_reboot.title":          "Redémarrage de l'Arduino",
+	"notify.arduino_reboot.message":        "Envoi de la commande de redémarrage à l'Arduino...",
+	"notify.arduino_reboot_failed.title":   "Impossible de redémarrer l'Arduino !",
+	"notify.firmware_version.title":        "Firmware de l'Arduino",
+	"notify.firmware_version.message":      "Votre carte utilise le firmware deej %s.",
+	"notify.firmware_version_failed.title": "Impossible d'obtenir la version du firmware !",
+	"notify.audio_lost.title":              "Connexion au serveur audio perdue !",
+	"notify.audio_lost.message":            "deej va continuer à essayer de se reconnecter. D'ici là, vos curseurs n'auront aucun effet.",
+	"notify.audio_back.title":              "Reconnecté au serveur audio",
+	"notify.audio_back.message":            "Vos curseurs reprennent le contrôle.",
+	"notify.autostart_failed.title":        "Impossible de modifier le lancement automatique !",
+	"notify.update_failed.title":           "Impossible de mettre à jour deej !",
+	"notify.update_available.title":        "deej %s est disponible !",
+	"notify.update_available.notes":        "Découvrez les nouveautés sur %s.",
+	"notify.update_available.install":      "Installez-le depuis le menu de la zone de notification ou la fenêtre de configuration.",
+	"notify.updated.title":                 "Mis à jour vers deej %s !",
+	"notify.updated.message":               "Redémarrez deej pour utiliser la nouvelle version.",
+	"notify.unmatched_targets.title":       "Certains curseurs ne contrôlent rien",
+	"notify.unmatched_targets.message":     "Aucune session audio ne correspond encore à %s. Vérifiez l'orthographe dans votre configuration, ou lancez l'application et jouez quelque chose.",
+	"notify.web_config_failed.title":       "Impossible d'ouvrir la fenêtre de configuration !",
+	"notify.web_config_failed.message":     "deej n'a pas pu démarrer son serveur web : %s",
+	"notify.connection_unstable.title":     "La connexion à %s coupe sans arrêt",
+	"notify.connection_unstable.message":   "deej a perdu la connexion %s fois en %s minutes, généralement à cause d'un câble USB mal branché ou défectueux. Il continue à se reconnecter sans autre notification, la page d'état donne les détails.",
+	"notify.baud_rate_mismatch.title":      "Débit en bauds différent",
+	"notify.baud_rate_mismatch.message":    "Votre carte deej fonctionne à %d bauds, mais %s vaut %d dans votre configuration. Enregistrer %d dans votre configuration ?",
+	"notify.baud_rate_mismatch.inform":     "Votre carte deej fonctionne à %d bauds. Réglez %s sur %d dans votre configuration pour qu'ils correspondent.",
+	"notify.degraded.unknown":              "les notifications n'ont pas pu être envoyées",
+	"dialog.yes":                           "Oui",
+	"dialog.no":                            "Non",
+
+	// serial port permission dialogs, see permissions_linux.go
+	"permissions.title":                  "Autorisations des ports série",
+	"permissions.no_problems":            "deej n'a rencontré aucun problème d'autorisation avec les ports série.",
+	"permissions.unknown_group.title":    "Impossible de corriger les autorisations des ports série",
+	"permissions.unknown_group.message":  "Accès refusé à %s, mais deej n'a pas pu déterminer quel groupe en est propriétaire.\n\nVeuillez vérifier les autorisations du périphérique manuellement.",
+	"permissions.already_member.title":   "Déjà membre",
+	"permissions.already_member.message": "Vous êtes déjà membre du groupe '%s'.\n\nDéconnectez-vous puis reconnectez-vous si le problème persiste.",
+	"permissions.manual.message":         "Accès refusé à %s. Pour corriger cela, exécutez :\n%s\npuis déconnectez-vous et reconnectez-vous.",
+	"permissions.confirm.message":        "Accès refusé à %s.\n\nVoulez-vous vous ajouter au groupe '%s' ?\n\nVotre mot de passe vous sera demandé.",
+	"permissions.cancelled.title":        "Action annulée",
+	"permissions.cancelled.message":      "Aucune modification n'a été effectuée.",
+	"permissions.failed.title":           "Erreur",
+	"permissions.failed.message":         "Impossible de vous ajouter au groupe.\n\nVeuillez exécuter cette commande manuellement :\n%s",
+	"permissions.added.title":            "Action requise",
+	"permissions.added.message":          "Vous avez été ajouté au groupe.\n\nDéconnectez-vous puis reconnectez-vous, puis relancez ce programme pour continuer.",
+
+	// errors, see error_catalog.go
+	"error.unknown.title":                 "Une erreur s'est produite !",
+	"error.unknown.message":               "Consultez les journaux de deej pour plus de détails.",
+	"error.docs":                          "Plus d'aide : %s",
+	"error.config_not_found.hint":         "Pour commencer, vous pouvez copier la configuration d'exemple depuis la page des versions de deej.",
+	"error.config_syntax.hint":            "Indentez avec des espaces plutôt que des tabulations, et mettez un espace après chaque deux-points.",
+	"error.config_invalid.hint":           "Corrigez-le et enregistrez le fichier, deej prend la modification en compte aussitôt.",
+	"error.device_not_found.title":        "Impossible de trouver votre deej !",
+	"error.device_not_found.message":      "Aucun des appareils connectés n'a répondu comme une carte deej.",
+	"error.device_not_found.hint":         "Vérifiez que votre carte est branchée et exécute le sketch deej, ou choisissez son port sur la page de configuration initiale.",
+	"error.not_connected.message":         "Votre carte deej n'est pas connectée.",
+	"error.not_connected.hint":            "Branchez-la, deej s'y connecte tout seul.",
+	"error.device_unresponsive.title":     "Votre carte deej n'a pas répondu !",
+	"error.device_unresponsive.message":   "Votre carte n'a pas répondu à temps.",
+	"error.device_unresponsive.hint":      "Essayez de la débrancher puis de la rebrancher.",
+	"error.command_rejected.message":      "Le firmware de votre carte ne sait pas faire cela.",
+	"error.update_firmware.hint":          "Reflashez votre carte avec le sketch fourni avec cette version de deej.",
+	"error.firmware_incompatible.message": "Le firmware de votre carte utilise une autre version du protocole que cette version de deej.",
+	"error.unsupported.message":           "Votre carte n'a pas le matériel nécessaire.",
+	"error.audio_unavailable.title":       "Impossible d'accéder à votre système audio !",
+	"error.audio_unavailable.message":     "deej n'a pas pu lister vos sessions audio, les curseurs ne peuvent donc rien contrôler.",
+	"error.audio_unavailable.hint":        "Vérifiez que votre service audio fonctionne (Audio Windows, ou PulseAudio ou PipeWire sous Linux).",
+
+	// web UI
+	"web.title":                    "Configuration de deej",
+	"web.slider_mappings":          "Affectation des curseurs",
+	"web.refresh_slider_count":     "Actualiser le nombre de curseurs",
+	"web.sliders_detected":         "%s curseur(s) détecté(s) sur l'Arduino",
+	"web.sliders_default":          "Arduino non connecté - %s curseurs affichés, autant que la dernière fois (ou default_slider_count)",
+	"web.sliders_default_hint":     "Branchez votre Arduino et cliquez sur \"Actualiser le nombre de curseurs\" pour détecter leur nombre réel",
+	"web.targets_hint":             "Saisissez des noms de processus (par ex. chrome.exe) ou des cibles spéciales (master, mic, deej.unmapped, etc.)",
+	"web.targets_separator_hint":   "Séparez plusieurs cibles par des virgules",
+	"web.slider":                   "Curseur %s :",
+	"web.slider.placeholder":       "par ex. chrome.exe, firefox.exe",
+	"web.slider_name.placeholder":  "Nom (facultatif)",
+	"web.slider_name_for":          "Nom du curseur %s",
+	"web.pick_target":              "Choisir une cible",
+	"web.pick_target_for":          "Choisir une cible pour le curseur %s",
+	"web.unmatched_targets":        "Aucune session audio ne correspond encore à %s. Vérifiez l'orthographe, ou lancez l'application et jouez quelque chose.",
+	"web.advanced":                 "Avancé",
+	"web.connection_settings":      "Paramètres de connexion",
+	"web.permissions_notice":       "deej n'a pas pu ouvrir %s.",
+	"web.fix_permissions":          "Réparer les permissions",
+	"web.com_port":                 "Port COM :",
+	"web.com_port.placeholder":     "par ex. COM4 ou auto",
+	"web.baud_rate":                "Débit en bauds :",
+	"web.inspector_hint":           "Un problème avec votre carte ? L'<a href=\"/inspector\" target=\"_blank\">inspecteur du trafic série</a> affiche en direct tout ce qu'elle envoie.",
+	"web.request_version":          "Demander sa version de firmware à la carte",
+	"web.explain":                  "Expliquer un curseur",
+	"web.explain_hint":             "Voyez ce qu'un curseur contrôle en ce moment : ses cibles, les sessions audio trouvées et leur volume.",
+	"web.explain_slider":           "Curseur :",
+	"web.explain_button":           "Expliquer",
+	"web.explain_preview_button":   "Aperçu de l'affectation non enregistrée",
+	"web.test_tone":                "Jouer un son de test",
+	"web.test_tone_played":         "Son de test joué sur %s",
+	"web.test_tone_failed":         "Impossible de jouer le son de test : %s",
+	"web.explain_failed":           "Impossible d'expliquer le curseur : %s",
+	"web.explain_position":         "Le curseur est à %s.",
+	"web.explain_position_unknown": "La carte n'a pas encore indiqué où se trouve le curseur.",
+	"web.explain_overridden":       "Un remplacement temporaire a remplacé les cibles de la configuration.",
+	"web.explain_preview":          "Aperçu des cibles saisies ci-dessus pour ce curseur, pas encore enregistrées.",
+	"web.explain_frozen":           "Les curseurs sont ignorés tant que l'écran est verrouillé.",
+	"web.explain_adopted":          "Ses cibles gardent un volume réglé ailleurs jusqu'à ce que le curseur bouge.",
+	"web.explain_unmapped":         "Rien n'est associé à ce curseur.",
+	"web.explain_kind_name":        "par nom",
+	"web.explain_kind_transform":   "cible spéciale",
+	"web.explain_kind_role":        "par rôle",
+	"web.explain_never_matched":    "Aucune session audio ne correspond à cette cible depuis qu'elle a été associée.",
+	"web.explain_resolved_nothing": "Ne représente rien pour l'instant",
+	"web.explain_sets":             "règle %s",
+	"web.explain_no_sessions":      "aucune session audio trouvée",
+	"web.explain_sessions":         "%s sessions audio, actuellement à %s",
+	"web.explain_muted":            "(muette)",
+	"web.explain_instance":         "%s : %s",
+	"web.loudness":                 "Égaliser le volume perçu",
+	"web.loudness_hint":            "Certaines applications jouent bien plus fort que d'autres. Lancez quelque chose de typique dans chaque application à égaliser (ou du bruit rose, si elle peut en jouer), puis calibrez : deej écoute chaque application à plein volume pendant quelques secondes et baisse les plus fortes, pour qu'une même position de curseur sonne à peu près aussi fort partout.",
+	"web.loudness_calibrate":       "Calibrer",
+	"web.loudness_clear":           "Effacer les corrections",
+	"web.loudness_calibrating":     "Écoute de chaque application en cours de lecture, cela prend quelques secondes...",
+	"web.loudness_calibrated":      "Calibré, les corrections s'appliquent tout de suite.",
+	"web.loudness_nothing_playing": "Aucune de vos applications ne joue quoi que ce soit. Lancez un peu d'audio et réessayez.",
+	"web.loudness_failed":          "Impossible de calibrer le volume : %s",
+	"web.loudness_none":            "Aucune correction de volume, chaque application joue aussi fort qu'elle le fait.",
+	"web.loudness_offset":          "%s : %s dB",
+	"web.loudness_silent":          "%s : silencieuse, non calibrée",
+	"web.timeline":                 "Historique des événements",
+	"web.timeline_hint":            "Ce qui est arrivé à vos sessions audio, à leur volume et à la connexion avec la carte depuis le démarrage de deej, le plus récent d'abord.",
+	"web.timeline_refresh":         "Actualiser",
+	"web.timeline_failed":          "Impossible de charger l'historique : %s",
+	"web.timeline_empty":           "Rien ne s'est encore passé.",
+	"web.timeline_repeated":        "(%s fois)",
+	"web.timeline_session_added":   "%s est apparu",
+	"web.timeline_session_removed": "%s a disparu",
+	"web.timeline_volume":          "%s réglé à %s par le curseur %s",
+	"web.timeline_external_volume": "%s changé à %s par autre chose que deej (curseur %s)",
+	"web.timeline_connected":       "Connecté à la carte sur %s",
+	"web.timeline_disconnected":    "Déconnecté de la carte sur %s",
+	"web.timeline_connect_failed":  "Impossible de se connecter à la carte sur %s : %s",
+	"web.timeline_audio_lost":      "Connexion au serveur audio perdue",
+	"web.timeline_audio_back":      "Reconnecté au serveur audio",
+	"web.remote_hint":              "La <a href=\"/remote\" target=\"_blank\">télécommande</a> a des curseurs à l'écran qui fonctionnent comme ceux de votre carte, même quand elle n'est pas branchée. Pour l'utiliser depuis votre téléphone, réglez web_host et web_token dans config.yaml.",
+	"web.remote.title":             "Télécommande deej",
+	"web.remote.board_connected":   "Votre carte est connectée, elle reprend la main dès qu'un de ses curseurs bouge.",
+	"web.remote.board_missing":     "Votre carte n'est pas connectée, il n'y a que les curseurs d'ici.",
+	"web.remote.slider":            "%s : %s",
+	"web.remote.slider_unmapped":   "%s : non associé",
+	"web.remote.failed":            "Contact perdu avec deej : %s",
+	"web.status.title":             "État de deej",
+	"web.status.connected":         "Connecté (%s)",
+	"web.status.disconnected":      "Non connecté",
+	"web.detect.probing":           "Recherche de votre carte deej : essai de %s à %s bauds…",
+	"web.detect.failed":            "Votre carte deej est introuvable. Elle sera détectée dès qu'elle sera branchée.",
+	"web.detect.found":             "%s à %s bauds : deej trouvé",
+	"web.detect.no_answer":         "%s à %s bauds : pas de réponse",
+	"web.detect.denied":            "%s : permission refusée",
+	"web.detect.unavailable":       "%s : impossible de l'ouvrir",
+	"web.detect.skipped":           "%s : non sondé (skip_ports ou safe_probing)",
+	"web.status.unmapped":          "Curseur %s",
+	"web.status.named":             "%s : %s",
+	"web.status.failed":            "Contact perdu avec deej : %s",
+	"web.status.unstable":          "Connexion instable !",
+	"web.status.disconnects":       "Déconnecté %s fois ces %s dernières minutes, la dernière à %s.",
+	"web.status.suppressed":        "%s notifications retenues.",
+	"web.firmware_version":         "Votre carte utilise le firmware deej %s",
+	"web.firmware_version_failed":  "Impossible d'obtenir la version du firmware : %s",
+	"web.hardware":                 "Matériel",
+	"web.board_settings":           "Paramètres de la carte",
+	"web.hardware_notice":          "Ces paramètres sont envoyés à la carte à chaque connexion. Laissez un champ vide pour garder la valeur par défaut du firmware.",
+	"web.hardware_unsupported":     "La carte connectée n'accepte pas de paramètres de deej (ou n'est pas connectée). Ils seront envoyés dès qu'une carte qui les accepte sera connectée. La fréquence d'envoi s'applique dans tous les cas.",
+	"web.report_rate":              "Fréquence d'envoi (fois par seconde) :",
+	"web.led_brightness":           "Luminosité des LED (0-255) :",
+	"web.dim_after":                "Atténuer l'écran et les LED après (secondes sans contact) :",
+	"web.off_after":                "Les éteindre après (secondes sans contact) :",
+	"web.idle_hint":                "Laissez vide pour les garder allumés. Bouger un curseur ou appuyer sur un bouton les réveille.",
+	"web.smoothing":                "Lissage %s :",
+	"web.smoothing.placeholder":    "mesures à moyenner",
+	"web.led_colors_hint":          "Pour les cartes avec des LED RVB : donnez une couleur à chaque curseur, pour qu'il corresponde à son nom à l'écran.",
+	"web.led_color":                "Couleur %s :",
+	"web.led_color_clear":          "Effacer",
+	"web.led_color_clear_for":      "Effacer la couleur du curseur %s",
+	"web.save_hardware":            "Enregistrer et envoyer à la carte",
+	"web.board_storage":            "Enregistré sur la carte",
+	"web.board_storage_hint":       "Les cartes qui gardent des réglages dans leur EEPROM les emportent sur d'autres ordinateurs. Lisez-les depuis la carte pour les modifier, puis réécrivez-les.",
+	"web.board_name":               "Nom de la carte :",
+	"web.board_sliders":            "Nombre de curseurs :",
+	"web.board_slider_name":        "Nom du curseur %s :",
+	"web.board_read":               "Lire depuis la carte",
+	"web.board_write":              "Écrire sur la carte",
+	"web.board_read_done":          "Réglages enregistrés sur la carte lus",
+	"web.board_read_failed":        "Impossible de lire les réglages de la carte : %s",
+	"web.board_written":            "Réglages enregistrés sur la carte",
+	"web.board_write_failed":       "Impossible d'enregistrer les réglages sur la carte : %s",
+	"web.other_settings":           "Autres paramètres",
+	"web.autostart":                "Lancer deej à l'ouverture de session",
+	"web.verbose":                  "Journalisation détaillée (pour capturer un problème dans les journaux de deej)",
+	"web.invert_sliders":           "Inverser les curseurs",
+	"web.theme":                    "Thème :",
+	"web.theme_auto":               "Comme le système",
+	"web.theme_light":              "Clair",
+	"web.theme_dark":               "Sombre",
+	"web.theme_failed":             "Impossible de changer de thème : %s",
+	"web.noise_reduction":          "Réduction du bruit :",
+	"web.noise_low":                "Faible (excellent matériel)",
+	"web.noise_default":            "Par défaut (matériel courant)",
+	"web.noise_high":               "Forte (matériel médiocre ou bruyant)",
+	"web.session_refresh":          "Trouver les nouvelles applications :",
+	"web.session_refresh_auto":     "Automatiquement",
+	"web.session_refresh_manual":   "Uniquement sur demande",
+	"web.refresh_frequency":        "Au plus toutes les (secondes) :",
+	"web.session_refresh_hint":     "Rechercher les applications prend un moment, ce qui peut se remarquer sur les machines lentes. Sur « Uniquement sur demande », deej ne trouve les applications lancées après lui que lorsque vous choisissez « Rechercher à nouveau les sessions audio » dans la zone de notification ou relancez la recherche ici.",
+	"web.cancel":                   "Annuler",
+	"web.save":                     "Enregistrer la configuration",
+	"web.select_target":            "Choisir une cible audio",
+	"web.rescan_running":           "Rechercher à nouveau les applications en cours",
+	"web.loading_targets":          "Chargement des cibles disponibles...",
+	"web.rescanning":               "Recherche des applications en cours...",
+	"web.search_installed":         "Rechercher parmi les applications installées...",
+	"web.targets_failed":           "Impossible de charger les cibles : %s",
+	"web.system_controls":          "Contrôles système",
+	"web.running_apps":             "Applications en cours",
+	"web.mpris_players":            "Lecteurs MPRIS non associés",
+	"web.audio_devices":            "Périphériques audio",
+	"web.installed_apps":           "Applications installées (%s)",
+	"web.other_category":           "Autres",
+	"web.no_targets":               "Aucune cible audio trouvée",
+	"web.playing":                  "%s - En lecture : %s",
+	"web.by_artist":                "%s par %s",
+	"web.now_playing":              "En cours de lecture : %s",
+	"web.saved":                    "Configuration enregistrée !",
+	"web.save_failed":              "Impossible d'enregistrer la configuration : %s",
+	"web.load_failed":              "Impossible de charger la configuration : %s",
+	"web.slider_count_refreshed":   "Nombre de curseurs actualisé : %s détecté(s)",
+	"web.slider_count_failed":      "Impossible d'actualiser le nombre de curseurs : %s",
+	"web.hardware_saved":           "Paramètres matériels enregistrés",
+	"web.hardware_failed":          "Impossible d'enregistrer les paramètres matériels : %s",
+	"web.permissions_started":      "Suivez les instructions pour réparer les permissions des ports série",
+	"web.permissions_failed":       "Impossible de lancer l'assistant de permissions : %s",
+	"web.autostart_on":             "deej se lancera à l'ouverture de session",
+	"web.autostart_off":            "deej ne se lancera plus à l'ouverture de session",
+	"web.autostart_failed":         "Impossible de modifier le lancement automatique : %s",
+	"web.verbose_on":               "Journalisation détaillée activée",
+	"web.verbose_off":              "Journalisation détaillée désactivée",
+	"web.verbose_failed":           "Impossible de modifier la journalisation détaillée : %s",
+	"web.update_available":         "deej %s est disponible.",
+	"web.whats_new":                "Nouveautés",
+	"web.update":                   "Mettre à jour",
+	"web.updating":                 "Mise à jour...",
+	"web.restart_to_finish":        "Redémarrez deej pour terminer",
+	"web.updated":                  "deej a été mis à jour, redémarrez-le pour utiliser la nouvelle version",
+	"web.update_failed":            "Impossible de mettre à jour deej : %s",
+	"web.setup.title":              "Configurer deej",
+	"web.setup.intro":              "Branchez votre deej, puis choisissez le port sur lequel il se trouve. deej signale les ports sur lesquels il a trouvé une carte.",
+	"web.setup.looking":            "Recherche de votre deej, cela peut prendre quelques secondes...",
+	"web.setup.look_again":         "Chercher à nouveau",
+	"web.setup.save":               "Enregistrer",
+	"web.setup.full_config":        "Aller à la configuration complète",
+	"web.setup.auto":               "Le trouver automatiquement",
+	"web.setup.connected":          "%s (connecté)",
+	"web.setup.deej_found":         "%s (deej trouvé)",
+	"web.setup.skipped":            "%s (non sondé, voir skip_ports et safe_probing)",
+	"web.setup.ports_failed":       "Impossible de lister les ports série : %s",
+	"web.setup.saved":              "Enregistré ! deej va se connecter à votre carte dans un instant.",
+	"web.setup.save_failed":        "Impossible d'enregistrer : %s",
+	"web.setup.all_set":            "Connecté à votre deej sur %s. Tout est prêt !",
+}
@@ -0,0 +1,14 @@
+package deej
+
+import "os"
+
+// systemLocale returns the locale messages are shown in, going by the usual environment variables in order
+func systemLocale() string {
+	for _, variable := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(variable); locale != "" && locale != "C" && locale != "POSIX" {
+			return locale
+		}
+	}
+
+	return defaultLocale
+}
@@ -0,0 +1,26 @@
+package deej
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32DLL                  = windows.NewLazySystemDLL("kernel32.dll")
+	procGetUserDefaultLocaleName = kernel32DLL.NewProc("GetUserDefaultLocaleName")
+)
+
+// from winnls.h
+const localeNameMaxLength = 85
+
+// systemLocale returns the user's locale, i.e. "de-DE"
+func systemLocale() string {
+	buffer := make([]uint16, localeNameMaxLength)
+
+	if ret, _, _ := procGetUserDefaultLocaleName.Call(uintptr(unsafe.Pointer(&buffer[0])), uintptr(len(buffer))); ret == 0 {
+		return defaultLocale
+	}
+
+	return windows.UTF16ToString(buffer)
+}
@@ -0,0 +1,163 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// after a while without anyone touching the board's sliders or buttons, deej dims its display and LEDs, and after a
+// while longer turns them off. the next touch wakes them up. boards with a display are sent "display:idle:<state>",
+// boards with LEDs "led:idle:<state>", where the state is one of the idleState constants
+
+const (
+	idleStateAwake  = "wake"
+	idleStateDimmed = "dim"
+	idleStateOff    = "off"
+
+	hardwareKeyDimAfter = "dim_after"
+	hardwareKeyOffAfter = "off_after"
+
+	// how often the board's idle time is checked against the timeouts
+	idleCheckInterval = time.Second
+)
+
+// idleDimmer keeps track of how long the board has gone untouched, and dims or wakes it accordingly
+type idleDimmer struct {
+	deej   *Deej
+	logger *zap.SugaredLogger
+
+	// one of the idleState constants, as last sent to the board
+	state        string
+	lastActivity time.Time
+
+	lock sync.Mutex
+}
+
+func newIdleDimmer(deej *Deej, logger *zap.SugaredLogger) *idleDimmer {
+	logger = logger.Named("idle")
+
+	id := &idleDimmer{
+		deej:         deej,
+		logger:       logger,
+		state:        idleStateAwake,
+		lastActivity: time.Now(),
+	}
+
+	logger.Debug("Created idle dimmer instance")
+
+	return id
+}
+
+func (id *idleDimmer) initialize() {
+	sliderEventsChannel := id.deej.serial.SubscribeToSliderMoveEvents("idle")
+
+	go func() {
+		ticker := time.NewTicker(idleCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case event := <-sliderEventsChannel:
+
+				// values sent right after connecting or re-sent by deej itself aren't anyone touching the board
+				if !event.Initial && !event.Received.IsZero() {
+					id.activity()
+				}
+
+			case now := <-ticker.C:
+				id.check(now)
+			}
+		}
+	}()
+}
+
+// activity starts the wait over, waking the board if it's dimmed or off
+func (id *idleDimmer) activity() {
+	if id == nil {
+		return
+	}
+
+	id.lock.Lock()
+	id.lastActivity = time.Now()
+	asleep := id.state != idleStateAwake
+	id.lock.Unlock()
+
+	if asleep {
+		id.setState(idleStateAwake)
+	}
+}
+
+// connected starts the wait over for a board that just connected, which is awake whatever it was told before
+func (id *idleDimmer) connected() {
+	if id == nil {
+		return
+	}
+
+	id.lock.Lock()
+	id.lastActivity = time.Now()
+	id.state = idleStateAwake
+	id.lock.Unlock()
+}
+
+// check dims or turns off the board once it's gone untouched for long enough
+func (id *idleDimmer) check(now time.Time) {
+	if !id.deej.serial.connected {
+		return
+	}
+
+	id.lock.Lock()
+	idle := now.Sub(id.lastActivity)
+	current := id.state
+	id.lock.Unlock()
+
+	// timeouts turned off while the board is dimmed wake it right back up
+	state := idleStateAwake
+	switch {
+	case id.deej.config.IdleOffAfter > 0 && idle >= id.deej.config.IdleOffAfter:
+		state = idleStateOff
+	case id.deej.config.IdleDimAfter > 0 && idle >= id.deej.config.IdleDimAfter:
+		state = idleStateDimmed
+	}
+
+	if state != current {
+		id.setState(state)
+	}
+}
+
+// setState tells the board's display and LEDs to dim, turn off or wake up
+func (id *idleDimmer) setState(state string) {
+	id.lock.Lock()
+	id.state = state
+	id.lock.Unlock()
+
+	id.logger.Debugw("Board idle state changed", "state", state)
+
+	for _, command := range []string{"display", "led"} {
+		err := id.deej.serial.SendCommand(fmt.Sprintf("%s:idle:%s", command, state))
+		if err != nil && !errors.Is(err, errCapabilityUnsupported) && !errors.Is(err, errNotConnected) {
+			id.logger.Warnw("Failed to send idle state to board", "command", command, "state", state, "error", err)
+		}
+	}
+}
+
+// populateIdleTimeouts reads how many seconds the board can go untouched before it's dimmed and turned off, 0 for never
+func (cc *CanonicalConfig) populateIdleTimeouts() {
+	cc.IdleDimAfter = cc.idleTimeout(hardwareKeyDimAfter)
+	cc.IdleOffAfter = cc.idleTimeout(hardwareKeyOffAfter)
+}
+
+func (cc *CanonicalConfig) idleTimeout(name string) time.Duration {
+	key := fmt.Sprintf("%s.%s", configKeyHardware, name)
+
+	seconds := cc.userConfig.GetFloat64(key)
+	if seconds < 0 {
+		cc.logger.Warnw("Invalid idle timeout, never going idle instead", "key", key, "invalidValue", seconds)
+		return 0
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
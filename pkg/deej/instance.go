@@ -0,0 +1,124 @@
+package deej
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// deej holds on to a fixed local port while it runs, which doubles as a lock against a second instance fighting
+// over the serial port and web UI, and as the way a second instance asks the running one to show its web UI
+const (
+	instanceLockAddress = "localhost:53172"
+
+	instanceCommandShowWebConfig = "show-web-config"
+	instanceCommandQuit          = "quit" // sent by the windows service when it stops
+	instanceReplyOK              = "deej:ok"
+
+	instanceRequestTimeout = 2 * time.Second
+)
+
+// ErrAlreadyRunning is returned from Initialize when another deej instance is already running.
+// by then, the running instance has been asked to show its web UI instead
+var ErrAlreadyRunning = errors.New("deej is already running")
+
+// acquireInstanceLock makes this the running deej instance, or returns ErrAlreadyRunning after handing
+// over to the one that already is. if something other than deej has taken the port, deej runs anyway
+func (d *Deej) acquireInstanceLock() error {
+	logger := d.logger.Named("instance")
+
+	listener, err := net.Listen("tcp", instanceLockAddress)
+	if err == nil {
+		d.instanceListener = listener
+		go d.serveInstanceRequests(listener)
+
+		return nil
+	}
+
+	logger.Debugw("Instance lock is taken, checking for another deej instance", "address", instanceLockAddress, "error", err)
+
+	reply, err := sendInstanceCommand(instanceCommandShowWebConfig)
+	if err != nil || reply != instanceReplyOK {
+		logger.Warnw("Instance lock is held by something other than deej, running without it",
+			"address", instanceLockAddress,
+			"reply", reply,
+			"error", err)
+
+		return nil
+	}
+
+	logger.Info("Another deej instance is already running, asked it to show its web UI")
+
+	return ErrAlreadyRunning
+}
+
+func (d *Deej) releaseInstanceLock() {
+	if d.instanceListener != nil {
+		d.instanceListener.Close()
+	}
+}
+
+func (d *Deej) serveInstanceRequests(listener net.Listener) {
+	logger := d.logger.Named("instance")
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			logger.Debugw("Stopped accepting instance requests", "error", err)
+			return
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(instanceRequestTimeout))
+
+			command, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				logger.Debugw("Failed to read instance request", "error", err)
+				return
+			}
+
+			switch strings.TrimSpace(command) {
+			case instanceCommandShowWebConfig:
+				logger.Info("Another deej instance was started, showing the web UI instead")
+				fmt.Fprintln(conn, instanceReplyOK)
+
+				d.openWebConfig(webConfigIndexPage)
+
+			case instanceCommandQuit:
+				logger.Info("Asked to quit by the deej service")
+				fmt.Fprintln(conn, instanceReplyOK)
+
+				d.signalStop()
+
+			default:
+				logger.Debugw("Ignoring unknown instance request", "command", command)
+			}
+		}(conn)
+	}
+}
+
+// sendInstanceCommand sends a command to the running instance and returns its reply
+func sendInstanceCommand(command string) (string, error) {
+	conn, err := net.DialTimeout("tcp", instanceLockAddress, instanceRequestTimeout)
+	if err != nil {
+		return "", fmt.Errorf("connect to running instance: %w", err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(instanceRequestTimeout))
+
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return "", fmt.Errorf("send instance command: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("read instance reply: %w", err)
+	}
+
+	return strings.TrimSpace(reply), nil
+}
@@ -0,0 +1,192 @@
+package deej
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// latencyRecorder measures how long slider moves take to turn into volume changes, starting from when deej read the
+// line they came in (see SliderMoveEvent.Received). it's only created with --measure-latency, so the hot path pays
+// nothing for it otherwise. percentiles are logged once a minute while sliders move, and served on /metrics
+type latencyRecorder struct {
+	logger *zap.SugaredLogger
+
+	stages  map[string]*latencyStage
+	lastLog time.Time
+	lock    sync.Mutex
+}
+
+// latencyStage holds the most recent samples of one stage, plus running totals since deej started
+type latencyStage struct {
+	samples []time.Duration
+	next    int
+
+	sum   time.Duration
+	count uint64
+
+	// count as of the last time this stage was logged
+	logged uint64
+}
+
+const (
+	latencyStageDispatch = "dispatch" // line read to the session map picking up the move
+	latencyStageApply    = "apply"    // line read to the audio backend returning from setting the volume
+
+	// how many recent samples percentiles are taken over
+	latencySampleCapacity = 1024
+
+	latencyLogInterval = time.Minute
+)
+
+// stages in the order they happen, for logs and /metrics
+var latencyStages = []string{latencyStageDispatch, latencyStageApply}
+
+// the percentiles logged and served, as fractions
+var latencyQuantiles = []float64{0.5, 0.9, 0.99}
+
+func newLatencyRecorder(logger *zap.SugaredLogger) *latencyRecorder {
+	lr := &latencyRecorder{
+		logger:  logger.Named("latency"),
+		stages:  map[string]*latencyStage{},
+		lastLog: time.Now(),
+	}
+
+	for _, stage := range latencyStages {
+		lr.stages[stage] = &latencyStage{}
+	}
+
+	lr.logger.Info("Measuring slider latency")
+
+	return lr
+}
+
+// record adds the time since the given line was read to a stage. like the trace, it does nothing on a nil
+// recorder, nor for moves that didn't come from a line (the web UI's remote sliders)
+func (lr *latencyRecorder) record(stage string, received time.Time) {
+	if lr == nil || received.IsZero() {
+		return
+	}
+
+	elapsed := time.Since(received)
+
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+
+	s := lr.stages[stage]
+
+	if len(s.samples) < latencySampleCapacity {
+		s.samples = append(s.samples, elapsed)
+	} else {
+		s.samples[s.next] = elapsed
+		s.next = (s.next + 1) % latencySampleCapacity
+	}
+
+	s.sum += elapsed
+	s.count++
+
+	if time.Since(lr.lastLog) >= latencyLogInterval {
+		lr.log()
+	}
+}
+
+// logSummary logs whatever was measured since the last time, i.e. when deej stops
+func (lr *latencyRecorder) logSummary() {
+	if lr == nil {
+		return
+	}
+
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+
+	lr.log()
+}
+
+// log logs the percentiles of every stage that has new samples. callers must hold the lock
+func (lr *latencyRecorder) log() {
+	lr.lastLog = time.Now()
+
+	for _, stage := range latencyStages {
+		s := lr.stages[stage]
+		if s.count == s.logged {
+			continue
+		}
+
+		percentiles := s.percentiles()
+		fields := []interface{}{"stage", stage, "samples", len(s.samples), "new", s.count - s.logged}
+
+		for idx, quantile := range latencyQuantiles {
+			fields = append(fields, fmt.Sprintf("p%g", quantile*100), percentiles[idx].String())
+		}
+
+		fields = append(fields, "max", s.max().String())
+
+		lr.logger.Infow("Slider latency", fields...)
+		s.logged = s.count
+	}
+}
+
+// writeMetrics writes every stage as a summary in prometheus' text format
+func (lr *latencyRecorder) writeMetrics(w io.Writer) {
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+
+	fmt.Fprintln(w, "# HELP deej_slider_latency_seconds Time from reading a line from the device to each stage of applying it.")
+	fmt.Fprintln(w, "# TYPE deej_slider_latency_seconds summary")
+
+	for _, stage := range latencyStages {
+		s := lr.stages[stage]
+		percentiles := s.percentiles()
+
+		for idx, quantile := range latencyQuantiles {
+			value := math.NaN()
+			if len(s.samples) > 0 {
+				value = percentiles[idx].Seconds()
+			}
+
+			fmt.Fprintf(w, "deej_slider_latency_seconds{stage=%q,quantile=\"%g\"} %g\n", stage, quantile, value)
+		}
+
+		fmt.Fprintf(w, "deej_slider_latency_seconds_sum{stage=%q} %g\n", stage, s.sum.Seconds())
+		fmt.Fprintf(w, "deej_slider_latency_seconds_count{stage=%q} %d\n", stage, s.count)
+	}
+}
+
+// percentiles returns the stage's latencyQuantiles over its recent samples, by nearest rank
+func (s *latencyStage) percentiles() []time.Duration {
+	percentiles := make([]time.Duration, len(latencyQuantiles))
+	if len(s.samples) == 0 {
+		return percentiles
+	}
+
+	sorted := make([]time.Duration, len(s.samples))
+	copy(sorted, s.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for idx, quantile := range latencyQuantiles {
+		rank := int(math.Ceil(quantile*float64(len(sorted)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+
+		percentiles[idx] = sorted[rank]
+	}
+
+	return percentiles
+}
+
+func (s *latencyStage) max() time.Duration {
+	max := time.Duration(0)
+	for _, sample := range s.samples {
+		if sample > max {
+			max = sample
+		}
+	}
+
+	return max
+}
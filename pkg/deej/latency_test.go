@@ -0,0 +1,60 @@
+package deej
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestLatencyStagePercentiles(t *testing.T) {
+	s := &latencyStage{}
+	for ms := 1; ms <= 100; ms++ {
+		s.samples = append(s.samples, time.Duration(ms)*time.Millisecond)
+	}
+
+	expected := []time.Duration{50 * time.Millisecond, 90 * time.Millisecond, 99 * time.Millisecond}
+	for idx, percentile := range s.percentiles() {
+		if percentile != expected[idx] {
+			t.Errorf("p%g: expected %v, got %v", latencyQuantiles[idx]*100, expected[idx], percentile)
+		}
+	}
+
+	if s.max() != 100*time.Millisecond {
+		t.Errorf("expected a max of 100ms, got %v", s.max())
+	}
+}
+
+func TestLatencyRecorder(t *testing.T) {
+	var disabled *latencyRecorder
+	disabled.record(latencyStageApply, time.Now())
+
+	lr := newLatencyRecorder(zap.NewNop().Sugar())
+
+	// moves that didn't come from the device have nothing to measure from
+	lr.record(latencyStageApply, time.Time{})
+
+	for idx := 0; idx < latencySampleCapacity+10; idx++ {
+		lr.record(latencyStageApply, time.Now().Add(-time.Millisecond))
+	}
+
+	apply := lr.stages[latencyStageApply]
+	if len(apply.samples) != latencySampleCapacity || apply.count != latencySampleCapacity+10 {
+		t.Errorf("expected %d samples out of %d, got %d out of %d",
+			latencySampleCapacity, latencySampleCapacity+10, len(apply.samples), apply.count)
+	}
+
+	metrics := &bytes.Buffer{}
+	lr.writeMetrics(metrics)
+
+	for _, line := range []string{
+		`deej_slider_latency_seconds{stage="dispatch",quantile="0.5"} NaN`,
+		`deej_slider_latency_seconds_count{stage="apply"} 1034`,
+	} {
+		if !strings.Contains(metrics.String(), line) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", line, metrics.String())
+		}
+	}
+}
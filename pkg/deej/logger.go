@@ -16,6 +16,13 @@ const (
 	logFilename  = "deej-latest-run.log"
 )
 
+// the level the logger logs at, lowered to debug in verbose mode (see Deej.SetVerbose). baseLogLevel is what
+// it goes back to afterwards
+var (
+	logLevel     = zap.NewAtomicLevelAt(zap.InfoLevel)
+	baseLogLevel = zap.InfoLevel
+)
+
 // isDebugMode returns true if DEEJ_DEBUG=1 is set in the environment
 func isDebugMode() bool {
 	return os.Getenv("DEEJ_DEBUG") == "1"
@@ -27,18 +34,20 @@ func NewLogger() (*zap.SugaredLogger, error) {
 
 	if isDebugMode() {
 		loggerConfig = zap.NewDevelopmentConfig()
-		loggerConfig.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+		baseLogLevel = zap.DebugLevel
 		loggerConfig.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	} else {
 		if err := util.EnsureDirExists(logDirectory); err != nil {
 			return nil, fmt.Errorf("ensure log directory exists: %w", err)
 		}
 		loggerConfig = zap.NewProductionConfig()
-		loggerConfig.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
 		loggerConfig.OutputPaths = []string{filepath.Join(logDirectory, logFilename)}
 		loggerConfig.Encoding = "console"
 	}
 
+	logLevel.SetLevel(baseLogLevel)
+	loggerConfig.Level = logLevel
+
 	// all build types: make it readable
 	loggerConfig.EncoderConfig.EncodeCaller = nil
 	loggerConfig.EncoderConfig.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
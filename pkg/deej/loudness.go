@@ -0,0 +1,237 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// apps don't all play equally loud: a game at 50% can drown out a call at 50%. loudness offsets turn the louder
+// targets down by a few dB, so that equal slider positions sound about equally loud. offsets are learned by
+// calibrating, which listens to every app that's playing (typical content, or pink noise where an app can play it)
+// with its volume at 100% and compares how loud each one is. the quietest app is the reference, so offsets are
+// never positive and sliders can still reach each app's full volume. offsets can also be written by hand, in the
+// loudness_offsets config section
+const (
+
+	// how long each app is listened to while calibrating
+	loudnessCalibrationDuration = 5 * time.Second
+
+	// how often the level of an app is sampled while calibrating
+	loudnessSampleInterval = 40 * time.Millisecond
+
+	// peaks below this are silence (pauses between songs, say) and don't count towards an app's loudness
+	loudnessSilenceDB = -60
+
+	// the lowest offset that makes sense, any lower and the app is as good as muted
+	minLoudnessOffsetDB = -60
+)
+
+var errInvalidLoudnessOffset = errors.New("invalid loudness offset")
+var errCalibrationRunning = errors.New("loudness calibration already running")
+var errNothingPlaying = errors.New("no apps are playing anything")
+
+// loudnessMeter is implemented by sessions whose playback level deej can measure
+type loudnessMeter interface {
+
+	// measurePeaks samples the session's peak level (from 0 to 1) every interval, for the given duration
+	measurePeaks(duration time.Duration, interval time.Duration) ([]float32, error)
+}
+
+// parseLoudnessOffset parses an offset like "-6dB" or "-4.5"
+func parseLoudnessOffset(spec string) (float32, error) {
+	spec = strings.TrimSpace(spec)
+	trimmed := strings.TrimSpace(strings.TrimSuffix(strings.ToLower(spec), "db"))
+
+	offset, err := strconv.ParseFloat(trimmed, 32)
+	if err != nil || offset > 0 || offset < minLoudnessOffsetDB {
+		return 0, fmt.Errorf("%q: expected between %d and 0 dB: %w", spec, minLoudnessOffsetDB, errInvalidLoudnessOffset)
+	}
+
+	return float32(offset), nil
+}
+
+// formatLoudnessOffset puts an offset the way it's written in the config
+func formatLoudnessOffset(offset float32) string {
+	return strconv.FormatFloat(float64(offset), 'f', -1, 32) + "dB"
+}
+
+// loudnessOffsetTrim returns the trim that turns a target down by the given number of dB
+func loudnessOffsetTrim(offset float32) volumeTrim {
+	if offset == 0 {
+		return noTrim
+	}
+
+	return volumeTrim{gain: volumeForLevel(dbToLevel(offset))}
+}
+
+// volumeForLevel returns the session volume that plays a stream at the given fraction of its full level
+func volumeForLevel(level float32) float32 {
+	return float32(math.Pow(float64(level), 1/float64(sessionVolumeExponent)))
+}
+
+// levelForVolume returns the fraction of its full level a stream plays at with the given session volume
+func levelForVolume(volume float32) float32 {
+	return float32(math.Pow(float64(volume), float64(sessionVolumeExponent)))
+}
+
+func dbToLevel(db float32) float32 {
+	return float32(math.Pow(10, float64(db)/20))
+}
+
+func levelToDB(level float32) float32 {
+	return float32(20 * math.Log10(float64(level)))
+}
+
+// typicalLoudness returns how loud a series of peaks is in dB, ignoring silence. it returns false if it was
+// silent throughout
+func typicalLoudness(peaks []float32) (float32, bool) {
+	var total float32
+	var count int
+
+	for _, peak := range peaks {
+		if peak <= 0 {
+			continue
+		}
+
+		if db := levelToDB(peak); db > loudnessSilenceDB {
+			total += db
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, false
+	}
+
+	return total / float32(count), true
+}
+
+// loudnessOffsets returns the offset of each target from the quietest one, given how loud each of them is
+func loudnessOffsets(loudness map[string]float32) map[string]float32 {
+	reference := float32(math.Inf(1))
+	for _, db := range loudness {
+		if db < reference {
+			reference = db
+		}
+	}
+
+	offsets := make(map[string]float32, len(loudness))
+	for key, db := range loudness {
+
+		// half a dB is about the smallest difference anyone can hear
+		offset := float32(math.Round(float64(reference-db)*2) / 2)
+		if offset < minLoudnessOffsetDB {
+			offset = minLoudnessOffsetDB
+		}
+
+		offsets[key] = offset
+	}
+
+	return offsets
+}
+
+// calibrateLoudness listens to every app that's playing, and returns the loudness offsets that even them out.
+// it also returns the apps that were silent, and so couldn't be calibrated. each app is briefly played at
+// 100% (or its cap, see volume_caps.go) and then brought back to its volume from before
+func (m *sessionMap) calibrateLoudness(duration time.Duration) (map[string]float32, []string, error) {
+	if !atomic.CompareAndSwapInt32(&m.calibrating, 0, 1) {
+		return nil, nil, errCalibrationRunning
+	}
+	defer atomic.StoreInt32(&m.calibrating, 0)
+
+	m.lock.Lock()
+	meters := map[string][]loudnessMeter{}
+	for key, sessions := range m.m {
+		for _, session := range sessions {
+			if meter, ok := session.(loudnessMeter); ok && !m.isDeviceSession(session) {
+				meters[key] = append(meters[key], meter)
+			}
+		}
+	}
+	m.lock.Unlock()
+
+	if len(meters) == 0 {
+		return nil, nil, fmt.Errorf("calibrate loudness: %w", errNothingPlaying)
+	}
+
+	m.logger.Infow("Calibrating loudness", "sessions", len(meters), "duration", duration)
+
+	var resultLock sync.Mutex
+	var wg sync.WaitGroup
+	loudness := map[string]float32{}
+	silent := []string{}
+
+	for key, keyMeters := range meters {
+		referenceVolume := m.capVolume(key, 1)
+		if referenceVolume <= 0 {
+			continue
+		}
+
+		for _, meter := range keyMeters {
+			wg.Add(1)
+
+			go func(key string, session Session, meter loudnessMeter) {
+				defer wg.Done()
+
+				original := session.GetVolume()
+				m.setCalibrationVolume(session, referenceVolume)
+				defer m.setCalibrationVolume(session, original)
+
+				peaks, err := meter.measurePeaks(duration, loudnessSampleInterval)
+				if err != nil {
+					m.logger.Warnw("Failed to measure session loudness", "session", key, "error", err)
+					return
+				}
+
+				db, ok := typicalLoudness(peaks)
+				if !ok {
+					return
+				}
+
+				// as loud as it would have been at 100%
+				db -= levelToDB(levelForVolume(referenceVolume))
+
+				// sessions sharing a key share their slider, the loudest of them decides how loud it sounds
+				resultLock.Lock()
+				if previous, ok := loudness[key]; !ok || db > previous {
+					loudness[key] = db
+				}
+				resultLock.Unlock()
+			}(key, meter.(Session), meter)
+		}
+	}
+
+	wg.Wait()
+
+	for key := range meters {
+		if _, ok := loudness[key]; !ok {
+			silent = append(silent, key)
+		}
+	}
+
+	sort.Strings(silent)
+
+	if len(loudness) == 0 {
+		return nil, silent, fmt.Errorf("calibrate loudness: %w", errNothingPlaying)
+	}
+
+	offsets := loudnessOffsets(loudness)
+	m.logger.Infow("Calibrated loudness", "loudness", loudness, "offsets", offsets, "silent", silent)
+
+	return offsets, silent, nil
+}
+
+func (m *sessionMap) setCalibrationVolume(session Session, volume float32) {
+	m.recordVolumeSet(session.Key(), volume)
+
+	if err := session.SetVolume(volume); err != nil {
+		m.logger.Warnw("Failed to set session volume for calibration", "session", session.Key(), "error", err)
+	}
+}
@@ -0,0 +1,106 @@
+package deej
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jfreymuth/pulse/proto"
+)
+
+// paPeakStreams hands the peaks pulse records for measurePeaks to whoever asked for them. recorded audio arrives
+// through the client's callback, which belongs to the session finder, and only for as long as the callback runs
+type paPeakStreams struct {
+	lock    sync.Mutex
+	streams map[uint32]chan float32
+}
+
+func newPAPeakStreams() *paPeakStreams {
+	return &paPeakStreams{streams: map[uint32]chan float32{}}
+}
+
+// deliver passes on the peaks in a packet of recorded audio. it runs on the client's read loop, so it never blocks
+func (ps *paPeakStreams) deliver(packet *proto.DataPacket) {
+	ps.lock.Lock()
+	peaks, ok := ps.streams[packet.StreamIndex]
+	ps.lock.Unlock()
+
+	if !ok {
+		return
+	}
+
+	for offset := 0; offset+4 <= len(packet.Data); offset += 4 {
+		select {
+		case peaks <- math.Float32frombits(binary.LittleEndian.Uint32(packet.Data[offset:])):
+		default:
+		}
+	}
+}
+
+func (ps *paPeakStreams) add(streamIndex uint32, peaks chan float32) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	ps.streams[streamIndex] = peaks
+}
+
+func (ps *paPeakStreams) remove(streamIndex uint32) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	delete(ps.streams, streamIndex)
+}
+
+// measurePeaks records the sink input's level off its sink's monitor, the way pavucontrol's level meters do:
+// pulse detects the peaks itself and sends one per interval
+func (s *paSession) measurePeaks(duration time.Duration, interval time.Duration) ([]float32, error) {
+	sinkInput := proto.GetSinkInputInfoReply{}
+	if err := s.client.Request(&proto.GetSinkInputInfo{SinkInputIndex: s.sinkInputIndex}, &sinkInput); err != nil {
+		return nil, fmt.Errorf("get sink input info: %w", err)
+	}
+
+	sink := proto.GetSinkInfoReply{}
+	if err := s.client.Request(&proto.GetSinkInfo{SinkIndex: sinkInput.SinkIndex}, &sink); err != nil {
+		return nil, fmt.Errorf("get sink info: %w", err)
+	}
+
+	request := proto.CreateRecordStream{
+		SampleSpec:         proto.SampleSpec{Format: proto.FormatFloat32LE, Channels: 1, Rate: uint32(time.Second / interval)},
+		ChannelMap:         proto.ChannelMap{proto.ChannelMono},
+		SourceIndex:        sink.MonitorSourceIndex,
+		BufferMaxLength:    proto.Undefined,
+		BufferFragSize:     4,
+		PeakDetect:         true,
+		AdjustLatency:      true,
+		DirectOnInputIndex: s.sinkInputIndex,
+		Properties: proto.PropList{
+			"media.name": proto.PropListString("deej loudness calibration"),
+		},
+	}
+	reply := proto.CreateRecordStreamReply{}
+
+	if err := s.client.Request(&request, &reply); err != nil {
+		return nil, fmt.Errorf("create record stream: %w", err)
+	}
+
+	// leave room for a few more than expected, in case pulse sends them faster
+	peaks := make(chan float32, 2*duration/interval)
+	s.peakStreams.add(reply.StreamIndex, peaks)
+
+	time.Sleep(duration)
+
+	s.peakStreams.remove(reply.StreamIndex)
+
+	if err := s.client.Request(&proto.DeleteRecordStream{StreamIndex: reply.StreamIndex}, nil); err != nil {
+		s.logger.Warnw("Failed to delete record stream", "error", err)
+	}
+
+	result := make([]float32, 0, len(peaks))
+	for len(peaks) > 0 {
+		result = append(result, <-peaks)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package deej
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypicalLoudness(t *testing.T) {
+
+	// the silent gaps don't drag the average down
+	if db, ok := typicalLoudness([]float32{0, 0.1, 0.0001, 0.1, 0}); !ok || !volumesEqual(db, -20) {
+		t.Errorf("expected -20 dB, got %.2f (%v)", db, ok)
+	}
+
+	if _, ok := typicalLoudness([]float32{0, 0.0001, 0}); ok {
+		t.Error("expected silence")
+	}
+}
+
+func TestLoudnessOffsets(t *testing.T) {
+	offsets := loudnessOffsets(map[string]float32{
+		"game.exe":    -6,
+		"discord.exe": -18.2,
+		"chrome.exe":  -14.1,
+	})
+
+	want := map[string]float32{"game.exe": -12, "discord.exe": 0, "chrome.exe": -4}
+	if !reflect.DeepEqual(offsets, want) {
+		t.Errorf("expected %v, got %v", want, offsets)
+	}
+}
+
+func TestLoudnessOffsetTrim(t *testing.T) {
+	if trim := loudnessOffsetTrim(0); trim != noTrim {
+		t.Errorf("expected no trim, got %+v", trim)
+	}
+
+	// a slider at full volume plays the target 6 dB quieter, whatever the platform's volume curve
+	trim := loudnessOffsetTrim(-6)
+	if level := levelToDB(levelForVolume(trim.apply(1, 1))); !volumesEqual(level, -6) {
+		t.Errorf("expected -6 dB, got %.2f", level)
+	}
+}
@@ -0,0 +1,62 @@
+package deej
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+
+	ole "github.com/go-ole/go-ole"
+)
+
+// IAudioMeterInformation, which go-wca doesn't have. audio sessions hand it out for their own stream
+var iidAudioMeterInformation = ole.NewGUID("{C02216F6-8C67-4B5B-9D00-D008E73E0064}")
+
+type audioMeterInformation struct {
+	ole.IUnknown
+}
+
+type audioMeterInformationVtbl struct {
+	ole.IUnknownVtbl
+	GetPeakValue uintptr
+}
+
+func (v *audioMeterInformation) getPeakValue() (float32, error) {
+	var peak float32
+
+	vtable := (*audioMeterInformationVtbl)(unsafe.Pointer(v.RawVTable))
+	hr, _, _ := syscall.Syscall(vtable.GetPeakValue, 2, uintptr(unsafe.Pointer(v)), uintptr(unsafe.Pointer(&peak)), 0)
+	if hr != 0 {
+		return 0, ole.NewError(hr)
+	}
+
+	return peak, nil
+}
+
+// measurePeaks polls the session's meter, which holds the highest peak since it was last asked
+func (s *wcaSession) measurePeaks(duration time.Duration, interval time.Duration) ([]float32, error) {
+	dispatch, err := s.control.QueryInterface(iidAudioMeterInformation)
+	if err != nil {
+		return nil, fmt.Errorf("get session meter: %w", err)
+	}
+
+	meter := (*audioMeterInformation)(unsafe.Pointer(dispatch))
+	defer meter.Release()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	peaks := []float32{}
+	for deadline := time.Now().Add(duration); time.Now().Before(deadline); {
+		<-ticker.C
+
+		peak, err := meter.getPeakValue()
+		if err != nil {
+			return nil, fmt.Errorf("get session peak: %w", err)
+		}
+
+		peaks = append(peaks, peak)
+	}
+
+	return peaks, nil
+}
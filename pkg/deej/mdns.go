@@ -0,0 +1,420 @@
+package deej
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// when the web UI is reachable from the network, deej advertises it over mDNS as a "_deej._tcp" service, so phones
+// and other deej instances on the LAN can find it without anyone typing in IP addresses. there's no mDNS library
+// among deej's dependencies, and a responder for a single service is small enough to write out: it announces the
+// service when the web server starts, answers queries for it while it runs, and says goodbye when it stops
+
+const (
+	mdnsServiceType   = "_deej._tcp.local."
+	mdnsServiceLister = "_services._dns-sd._udp.local."
+	mdnsDomain        = "local."
+
+	// how long others may cache our records
+	mdnsTTL = 120
+
+	mdnsPort = 5353
+
+	// record types and classes, see RFC 1035 and RFC 6762
+	dnsTypeA       = 1
+	dnsTypePTR     = 12
+	dnsTypeTXT     = 16
+	dnsTypeSRV     = 33
+	dnsTypeANY     = 255
+	dnsClassIN     = 1
+	dnsCacheFlush  = 0x8000 // set on records only we answer for
+	dnsUnicastFlag = 0x8000 // set on questions that want a unicast response
+
+	dnsFlagsResponse = 0x8400 // a response, and an authoritative one
+
+	// mDNS messages can be bigger, but nothing deej sends or cares about is
+	mdnsMaxMessageSize = 9000
+
+	// announcements are sent twice, a second apart, in case the first one gets lost
+	mdnsAnnounceCount    = 2
+	mdnsAnnounceInterval = time.Second
+)
+
+var mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: mdnsPort}
+
+var errMalformedDNSMessage = errors.New("malformed DNS message")
+
+// dnsRecord is a resource record, with its data already encoded
+type dnsRecord struct {
+	name       string
+	recordType uint16
+	flush      bool
+	ttl        uint32
+	data       []byte
+}
+
+// mdnsAdvertiser answers mDNS queries for the web UI's service
+type mdnsAdvertiser struct {
+	logger *zap.SugaredLogger
+	conn   *net.UDPConn
+
+	// e.g. "deej on desktop._deej._tcp.local." and "desktop.local."
+	instance string
+	host     string
+
+	port int
+	ips  []net.IP
+
+	stop chan struct{}
+}
+
+// newMDNSAdvertiser starts advertising the web UI on the given port. ip is the address the web server listens on,
+// and if it's unspecified (0.0.0.0) every address of the machine's network interfaces is advertised instead
+func newMDNSAdvertiser(logger *zap.SugaredLogger, ip net.IP, port int) (*mdnsAdvertiser, error) {
+	logger = logger.Named("mdns")
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "deej"
+	}
+
+	// the hostname might be a FQDN already, but mDNS names live under .local
+	hostname = strings.SplitN(hostname, ".", 2)[0]
+
+	ips := []net.IP{ip.To4()}
+	if ip.IsUnspecified() {
+		if ips, err = localIPv4Addresses(); err != nil {
+			logger.Warnw("Failed to list network addresses", "error", err)
+			return nil, fmt.Errorf("list network addresses: %w", err)
+		}
+	}
+
+	if len(ips) == 0 || ips[0] == nil {
+		return nil, fmt.Errorf("no IPv4 address to advertise for %s", ip)
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsGroup)
+	if err != nil {
+		logger.Warnw("Failed to join mDNS multicast group", "error", err)
+		return nil, fmt.Errorf("join mDNS multicast group: %w", err)
+	}
+
+	ma := &mdnsAdvertiser{
+		logger:   logger,
+		conn:     conn,
+		instance: fmt.Sprintf("deej on %s.%s", hostname, mdnsServiceType),
+		host:     hostname + "." + mdnsDomain,
+		port:     port,
+		ips:      ips,
+		stop:     make(chan struct{}),
+	}
+
+	go ma.announce()
+	go ma.serve()
+
+	logger.Infow("Advertising web UI over mDNS", "instance", ma.instance, "addresses", ips, "port", port)
+
+	return ma, nil
+}
+
+// close says goodbye, so others drop our records right away rather than once they expire
+func (ma *mdnsAdvertiser) close() {
+	close(ma.stop)
+
+	if _, err := ma.conn.WriteToUDP(ma.response(0, nil, ma.records(0), true), mdnsGroup); err != nil {
+		ma.logger.Debugw("Failed to send mDNS goodbye", "error", err)
+	}
+
+	ma.conn.Close()
+}
+
+func (ma *mdnsAdvertiser) announce() {
+	for idx := 0; idx < mdnsAnnounceCount; idx++ {
+		if _, err := ma.conn.WriteToUDP(ma.response(0, nil, ma.records(mdnsTTL), true), mdnsGroup); err != nil {
+			ma.logger.Warnw("Failed to send mDNS announcement", "error", err)
+		}
+
+		select {
+		case <-ma.stop:
+			return
+		case <-time.After(mdnsAnnounceInterval):
+		}
+	}
+}
+
+func (ma *mdnsAdvertiser) serve() {
+	buf := make([]byte, mdnsMaxMessageSize)
+
+	for {
+		n, from, err := ma.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-ma.stop:
+			default:
+				ma.logger.Warnw("Failed to read mDNS message", "error", err)
+			}
+
+			return
+		}
+
+		ma.handleQuery(buf[:n], from)
+	}
+}
+
+// handleQuery answers the questions about our service in an mDNS message, if there are any
+func (ma *mdnsAdvertiser) handleQuery(msg []byte, from *net.UDPAddr) {
+	id, questions, err := parseDNSQuery(msg)
+	if err != nil {
+		ma.logger.Debugw("Ignoring malformed mDNS message", "from", from, "error", err)
+		return
+	}
+
+	answers := []dnsRecord{}
+	unicast := false
+
+	for _, question := range questions {
+		for _, record := range ma.records(mdnsTTL) {
+			if strings.EqualFold(record.name, question.name) &&
+				(question.questionType == record.recordType || question.questionType == dnsTypeANY) {
+				answers = append(answers, record)
+				unicast = unicast || question.unicast
+			}
+		}
+	}
+
+	if len(answers) == 0 {
+		return
+	}
+
+	// queries that don't come from the mDNS port are one-shot queries from plain DNS resolvers (RFC 6762 6.7),
+	// which want their question and ID back, and the answer sent straight to them
+	if from.Port != mdnsPort {
+		ma.send(ma.response(id, questions, answers, false), from)
+		return
+	}
+
+	to := mdnsGroup
+	if unicast {
+		to = from
+	}
+
+	ma.send(ma.response(0, nil, answers, true), to)
+}
+
+func (ma *mdnsAdvertiser) send(msg []byte, to *net.UDPAddr) {
+	if _, err := ma.conn.WriteToUDP(msg, to); err != nil {
+		ma.logger.Debugw("Failed to send mDNS response", "to", to, "error", err)
+	}
+}
+
+// records returns every record deej answers for, with the given TTL
+func (ma *mdnsAdvertiser) records(ttl uint32) []dnsRecord {
+	srv := make([]byte, 6)
+	binary.BigEndian.PutUint16(srv[4:], uint16(ma.port))
+
+	records := []dnsRecord{
+		{name: mdnsServiceLister, recordType: dnsTypePTR, ttl: ttl, data: encodeDNSName(mdnsServiceType)},
+		{name: mdnsServiceType, recordType: dnsTypePTR, ttl: ttl, data: encodeDNSName(ma.instance)},
+		{name: ma.instance, recordType: dnsTypeSRV, flush: true, ttl: ttl, data: append(srv, encodeDNSName(ma.host)...)},
+		{name: ma.instance, recordType: dnsTypeTXT, flush: true, ttl: ttl, data: encodeDNSText("path=/", "remote=/remote", "status=/status")},
+	}
+
+	for _, ip := range ma.ips {
+		records = append(records, dnsRecord{name: ma.host, recordType: dnsTypeA, flush: true, ttl: ttl, data: ip.To4()})
+	}
+
+	return records
+}
+
+// response encodes a response message with the given answers. multicast responses have no ID or questions
+func (ma *mdnsAdvertiser) response(id uint16, questions []dnsQuestion, answers []dnsRecord, multicast bool) []byte {
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[2:], dnsFlagsResponse)
+	binary.BigEndian.PutUint16(msg[4:], uint16(len(questions)))
+	binary.BigEndian.PutUint16(msg[6:], uint16(len(answers)))
+
+	for _, question := range questions {
+		msg = append(msg, encodeDNSName(question.name)...)
+		msg = append(msg, byte(question.questionType>>8), byte(question.questionType), 0, dnsClassIN)
+	}
+
+	for _, answer := range answers {
+		class := uint16(dnsClassIN)
+
+		// legacy resolvers don't know about the cache flush bit
+		if answer.flush && multicast {
+			class |= dnsCacheFlush
+		}
+
+		msg = append(msg, encodeDNSName(answer.name)...)
+		msg = append(msg, byte(answer.recordType>>8), byte(answer.recordType), byte(class>>8), byte(class))
+		msg = append(msg, byte(answer.ttl>>24), byte(answer.ttl>>16), byte(answer.ttl>>8), byte(answer.ttl))
+		msg = append(msg, byte(len(answer.data)>>8), byte(len(answer.data)))
+		msg = append(msg, answer.data...)
+	}
+
+	return msg
+}
+
+// dnsQuestion is a single question from a query
+type dnsQuestion struct {
+	name         string
+	questionType uint16
+	unicast      bool
+}
+
+// parseDNSQuery returns a query's ID and questions. responses are ignored, and have no questions
+func parseDNSQuery(msg []byte) (uint16, []dnsQuestion, error) {
+	if len(msg) < 12 {
+		return 0, nil, errMalformedDNSMessage
+	}
+
+	id := binary.BigEndian.Uint16(msg[0:])
+	flags := binary.BigEndian.Uint16(msg[2:])
+	count := int(binary.BigEndian.Uint16(msg[4:]))
+
+	if flags&0x8000 != 0 {
+		return id, nil, nil
+	}
+
+	questions := []dnsQuestion{}
+	offset := 12
+
+	for idx := 0; idx < count; idx++ {
+		name, next, err := decodeDNSName(msg, offset)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if next+4 > len(msg) {
+			return 0, nil, errMalformedDNSMessage
+		}
+
+		class := binary.BigEndian.Uint16(msg[next+2:])
+
+		questions = append(questions, dnsQuestion{
+			name:         name,
+			questionType: binary.BigEndian.Uint16(msg[next:]),
+			unicast:      class&dnsUnicastFlag != 0,
+		})
+
+		offset = next + 4
+	}
+
+	return id, questions, nil
+}
+
+// decodeDNSName reads a possibly compressed name at the given offset, and returns it along with the offset
+// right after it
+func decodeDNSName(msg []byte, offset int) (string, int, error) {
+	labels := []string{}
+	next := -1
+
+	// every pointer has to go backwards, which also rules out loops
+	limit := offset
+
+	for {
+		if offset >= len(msg) {
+			return "", 0, errMalformedDNSMessage
+		}
+
+		length := int(msg[offset])
+
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+
+			return strings.Join(labels, ".") + ".", next, nil
+
+		case length&0xc0 == 0xc0:
+			if offset+1 >= len(msg) {
+				return "", 0, errMalformedDNSMessage
+			}
+
+			pointer := int(binary.BigEndian.Uint16(msg[offset:]) & 0x3fff)
+			if pointer >= limit {
+				return "", 0, errMalformedDNSMessage
+			}
+
+			if next < 0 {
+				next = offset + 2
+			}
+
+			offset, limit = pointer, pointer
+
+		case length&0xc0 != 0:
+			return "", 0, errMalformedDNSMessage
+
+		default:
+			if offset+1+length > len(msg) {
+				return "", 0, errMalformedDNSMessage
+			}
+
+			labels = append(labels, string(msg[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
+
+// encodeDNSName encodes a name like "desktop.local." without compression
+func encodeDNSName(name string) []byte {
+	encoded := []byte{}
+
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		encoded = append(encoded, byte(len(label)))
+		encoded = append(encoded, label...)
+	}
+
+	return append(encoded, 0)
+}
+
+// encodeDNSText encodes the strings of a TXT record
+func encodeDNSText(values ...string) []byte {
+	encoded := []byte{}
+
+	for _, value := range values {
+		encoded = append(encoded, byte(len(value)))
+		encoded = append(encoded, value...)
+	}
+
+	return encoded
+}
+
+// localIPv4Addresses returns the IPv4 addresses of every network interface that's up and can multicast
+func localIPv4Addresses() ([]net.IP, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	ips := []net.IP{}
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+				ips = append(ips, ipNet.IP.To4())
+			}
+		}
+	}
+
+	return ips, nil
+}
@@ -0,0 +1,42 @@
+package deej
+
+import (
+	"time"
+)
+
+// now-playing info comes from MPRIS media players on the session bus. asking every player for its state is slow,
+// so a watcher keeps a connection to the bus open, follows players coming, going and changing, and keeps what they
+// said around to be read instantly
+
+const (
+	mprisBusPrefix  = "org.mpris.MediaPlayer2."
+	mprisObjectPath = "/org/mpris/MediaPlayer2"
+
+	// how long to wait before trying the session bus again, after it went away or couldn't be reached
+	mprisReconnectInterval = 10 * time.Second
+)
+
+// mprisPlayer is a media player on the session bus, as of its last change
+type mprisPlayer struct {
+	busName string // e.g. "org.mpris.MediaPlayer2.spotify"
+
+	// the player's unique name on the bus, which its signals come from
+	owner string
+
+	// lowercase, or empty if it couldn't be told
+	processName string
+
+	info MprisInfo
+}
+
+// mprisWatcher represents a platform-specific source of media players
+type mprisWatcher interface {
+
+	// Players returns the media players currently around
+	Players() []mprisPlayer
+
+	// Changes returns a channel that's signaled whenever a player comes, goes or changes what it's playing
+	Changes() <-chan struct{}
+
+	Close() error
+}
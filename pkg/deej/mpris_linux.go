@@ -0,0 +1,298 @@
+package deej
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	mprisRootInterface   = "org.mpris.MediaPlayer2"
+	mprisPlayerInterface = "org.mpris.MediaPlayer2.Player"
+
+	// how long a player gets to answer before it's left for its next change
+	mprisCallTimeout = time.Second
+)
+
+var errSessionBusClosed = errors.New("session bus connection closed")
+
+// dbusMPRISWatcher follows media players over a long-lived session bus connection
+type dbusMPRISWatcher struct {
+	logger *zap.SugaredLogger
+
+	// bus name -> player
+	players map[string]*mprisPlayer
+	lock    sync.Mutex
+
+	changes     chan struct{}
+	stopChannel chan bool
+	conn        *dbus.Conn
+}
+
+func newMPRISWatcher(logger *zap.SugaredLogger) mprisWatcher {
+	logger = logger.Named("mpris")
+
+	w := &dbusMPRISWatcher{
+		logger:      logger,
+		players:     map[string]*mprisPlayer{},
+		changes:     make(chan struct{}, 1),
+		stopChannel: make(chan bool),
+	}
+
+	go w.run()
+
+	logger.Debug("Created MPRIS watcher instance")
+
+	return w
+}
+
+func (w *dbusMPRISWatcher) Players() []mprisPlayer {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	players := make([]mprisPlayer, 0, len(w.players))
+	for _, player := range w.players {
+		players = append(players, *player)
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].busName < players[j].busName
+	})
+
+	return players
+}
+
+func (w *dbusMPRISWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *dbusMPRISWatcher) Close() error {
+	close(w.stopChannel)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.conn != nil {
+		return w.conn.Close()
+	}
+
+	return nil
+}
+
+// run keeps a connection to the session bus for as long as the watcher lives, getting a new one whenever it's lost
+func (w *dbusMPRISWatcher) run() {
+	for {
+		if err := w.watch(); err != nil {
+			w.logger.Debugw("Not watching media players", "error", err)
+		}
+
+		// whatever we knew about players is gone with the connection
+		w.lock.Lock()
+		hadPlayers := len(w.players) > 0
+		w.players = map[string]*mprisPlayer{}
+		w.conn = nil
+		w.lock.Unlock()
+
+		if hadPlayers {
+			w.notify()
+		}
+
+		select {
+		case <-w.stopChannel:
+			return
+		case <-time.After(mprisReconnectInterval):
+		}
+	}
+}
+
+// watch connects to the session bus, finds the players already on it and follows their changes until the
+// connection goes away
+func (w *dbusMPRISWatcher) watch() error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("connect to session bus: %w", err)
+	}
+
+	w.lock.Lock()
+	select {
+	case <-w.stopChannel:
+		w.lock.Unlock()
+		conn.Close()
+		return nil
+	default:
+	}
+	w.conn = conn
+	w.lock.Unlock()
+
+	// subscribe before looking around, so nothing that changes in between is missed
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchInterface("org.freedesktop.DBus"),
+		dbus.WithMatchMember("NameOwnerChanged"),
+		dbus.WithMatchArg0Namespace(mprisRootInterface),
+	); err != nil {
+		conn.Close()
+		return fmt.Errorf("watch for players: %w", err)
+	}
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(mprisObjectPath),
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+	); err != nil {
+		conn.Close()
+		return fmt.Errorf("watch for player changes: %w", err)
+	}
+
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+
+	var names []string
+	if err := conn.BusObject().Call("org.freedesktop.DBus.ListNames", 0).Store(&names); err != nil {
+		conn.Close()
+		return fmt.Errorf("list bus names: %w", err)
+	}
+
+	for _, name := range names {
+		if strings.HasPrefix(name, mprisBusPrefix) {
+			w.load(conn, name)
+		}
+	}
+
+	w.logger.Debugw("Watching media players", "count", len(w.Players()))
+	w.notify()
+
+	for signal := range signals {
+		w.handleSignal(conn, signal)
+	}
+
+	return errSessionBusClosed
+}
+
+func (w *dbusMPRISWatcher) handleSignal(conn *dbus.Conn, signal *dbus.Signal) {
+	switch signal.Name {
+	case "org.freedesktop.DBus.NameOwnerChanged":
+		var name, oldOwner, newOwner string
+		if err := dbus.Store(signal.Body, &name, &oldOwner, &newOwner); err != nil ||
+			!strings.HasPrefix(name, mprisBusPrefix) {
+			return
+		}
+
+		if newOwner == "" {
+			w.lock.Lock()
+			delete(w.players, name)
+			w.lock.Unlock()
+
+			w.logger.Debugw("Media player went away", "name", name)
+			w.notify()
+			return
+		}
+
+		w.logger.Debugw("Media player appeared", "name", name)
+		w.load(conn, name)
+		w.notify()
+
+	case "org.freedesktop.DBus.Properties.PropertiesChanged":
+		var iface string
+		if len(signal.Body) == 0 || dbus.Store(signal.Body[:1], &iface) != nil {
+			return
+		}
+
+		if iface != mprisRootInterface && iface != mprisPlayerInterface {
+			return
+		}
+
+		// signals come from the player's unique name, rather than the well-known one it's listed under
+		for _, player := range w.Players() {
+			if player.owner == signal.Sender {
+				w.load(conn, player.busName)
+				w.notify()
+			}
+		}
+	}
+}
+
+// load asks a player for its current state
+func (w *dbusMPRISWatcher) load(conn *dbus.Conn, busName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), mprisCallTimeout)
+	defer cancel()
+
+	obj := conn.Object(busName, mprisObjectPath)
+
+	var root, player map[string]dbus.Variant
+	obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", 0, mprisRootInterface).Store(&root)
+	if err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", 0, mprisPlayerInterface).Store(&player); err != nil {
+		w.logger.Debugw("Failed to get media player state", "name", busName, "error", err)
+		return
+	}
+
+	loaded := &mprisPlayer{
+		busName: busName,
+		owner:   w.owner(ctx, conn, busName),
+		info: MprisInfo{
+			PlayerName: strings.TrimPrefix(busName, mprisBusPrefix),
+		},
+	}
+
+	if identity, ok := root["Identity"].Value().(string); ok && identity != "" {
+		loaded.info.PlayerName = identity
+	}
+
+	// prefer the desktop entry, and fall back to the name of the process behind the player
+	if desktopEntry, ok := root["DesktopEntry"].Value().(string); ok && desktopEntry != "" {
+		loaded.processName = strings.ToLower(desktopEntry)
+	} else {
+		loaded.processName = w.processName(ctx, conn, busName)
+	}
+
+	status, _ := player["PlaybackStatus"].Value().(string)
+	loaded.info.IsPlaying = status == "Playing"
+
+	metadata, _ := player["Metadata"].Value().(map[string]dbus.Variant)
+	loaded.info.Title, _ = metadata["xesam:title"].Value().(string)
+	loaded.info.Album, _ = metadata["xesam:album"].Value().(string)
+	if artists, ok := metadata["xesam:artist"].Value().([]string); ok && len(artists) > 0 {
+		loaded.info.Artist = artists[0]
+	}
+
+	w.lock.Lock()
+	w.players[busName] = loaded
+	w.lock.Unlock()
+}
+
+// owner returns the unique name behind a well-known one
+func (w *dbusMPRISWatcher) owner(ctx context.Context, conn *dbus.Conn, busName string) string {
+	var owner string
+	conn.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.GetNameOwner", 0, busName).Store(&owner)
+
+	return owner
+}
+
+// processName returns the lowercase name of the process behind a player, or "" if it can't be told. sandboxed
+// players are behind a proxy, whose name would be no help
+func (w *dbusMPRISWatcher) processName(ctx context.Context, conn *dbus.Conn, busName string) string {
+	var pid uint32
+	if err := conn.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.GetConnectionUnixProcessID", 0, busName).Store(&pid); err != nil || pid == 0 {
+		return ""
+	}
+
+	name := getProcessNameFromPID(pid)
+	if name == "xdg-dbus-proxy" || name == "bwrap" {
+		return ""
+	}
+
+	return strings.ToLower(name)
+}
+
+func (w *dbusMPRISWatcher) notify() {
+	select {
+	case w.changes <- struct{}{}:
+	default:
+	}
+}
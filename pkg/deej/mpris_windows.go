@@ -0,0 +1,24 @@
+package deej
+
+import (
+	"go.uber.org/zap"
+)
+
+// there's no MPRIS on windows, so there are never any players
+type noMPRISWatcher struct{}
+
+func newMPRISWatcher(logger *zap.SugaredLogger) mprisWatcher {
+	return noMPRISWatcher{}
+}
+
+func (noMPRISWatcher) Players() []mprisPlayer {
+	return nil
+}
+
+func (noMPRISWatcher) Changes() <-chan struct{} {
+	return nil
+}
+
+func (noMPRISWatcher) Close() error {
+	return nil
+}
@@ -1,8 +1,13 @@
 package deej
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gen2brain/beeep"
 	"go.uber.org/zap"
@@ -16,23 +21,101 @@ type Notifier interface {
 	Notify(title string, message string)
 }
 
-// ToastNotifier provides toast notifications for Windows
+// ToastNotifier provides toast notifications for Windows, and desktop notifications on linux. where those aren't
+// available (or stop working), notifications go to the tray instead, and without a tray only to the logs
 type ToastNotifier struct {
 	logger *zap.SugaredLogger
+
+	// false if desktop notifications can't be shown at all, and what's missing for them to be
+	desktop bool
+	missing []string
+
+	// shows a notification in the tray, nil until the tray is ready
+	trayFallback func(title string, message string)
+	lock         sync.Mutex
+
+	degradedOnce sync.Once
+}
+
+// trayNotifier is implemented by notifiers that can fall back to the tray
+type trayNotifier interface {
+	setTrayFallback(fallback func(title string, message string))
+	missingPieces() string
 }
 
+// desktop notifications that take longer than this to go out are given up on, some daemons hang instead of failing
+const notifyTimeout = 5 * time.Second
+
+var errNotifyTimeout = errors.New("timed out")
+
 // NewToastNotifier creates a new ToastNotifier
 func NewToastNotifier(logger *zap.SugaredLogger) (*ToastNotifier, error) {
 	logger = logger.Named("notifier")
 	tn := &ToastNotifier{logger: logger}
 
+	tn.desktop, tn.missing = detectDesktopNotifications()
+	if len(tn.missing) > 0 {
+		logger.Warnw("Desktop notifications are missing something they need", "missing", tn.missing, "available", tn.desktop)
+	}
+
 	logger.Debug("Created toast notifier instance")
 
 	return tn, nil
 }
 
-// Notify sends a toast notification (or falls back to other types of notification for older Windows versions)
+// Notify sends a toast notification (or falls back to other types of notification for older Windows versions).
+// if it can't, the notification goes to the tray, or at least the logs
 func (tn *ToastNotifier) Notify(title string, message string) {
+	if tn.desktop {
+		err := tn.send(title, message)
+		if err == nil {
+			return
+		}
+
+		tn.logger.Errorw("Failed to send toast notification", "error", err)
+	}
+
+	tn.degraded()
+
+	tn.lock.Lock()
+	trayFallback := tn.trayFallback
+	tn.lock.Unlock()
+
+	if trayFallback != nil {
+		trayFallback(title, message)
+		return
+	}
+
+	tn.logger.Infow("Notification", "title", title, "message", message)
+}
+
+// setTrayFallback gives the notifier somewhere to show notifications that can't be sent to the desktop
+func (tn *ToastNotifier) setTrayFallback(fallback func(title string, message string)) {
+	tn.lock.Lock()
+	defer tn.lock.Unlock()
+
+	tn.trayFallback = fallback
+}
+
+// missingPieces describes what desktop notifications are missing, for telling the user why they're in the tray
+func (tn *ToastNotifier) missingPieces() string {
+	if len(tn.missing) == 0 {
+		return tr("notify.degraded.unknown")
+	}
+
+	return strings.Join(tn.missing, ", ")
+}
+
+// degraded warns, the first time a notification can't be sent to the desktop, that they won't be for now
+func (tn *ToastNotifier) degraded() {
+	tn.degradedOnce.Do(func() {
+		tn.logger.Warnw("Desktop notifications unavailable, showing them in the tray or logs instead",
+			"missing", tn.missing)
+	})
+}
+
+// send is Notify, for callers that want to know whether the notification went out
+func (tn *ToastNotifier) send(title string, message string) error {
 
 	// Detect system theme to use appropriate icon
 	theme := DetectSystemTheme()
@@ -67,8 +150,20 @@ func (tn *ToastNotifier) Notify(title string, message string) {
 
 	tn.logger.Infow("Sending toast notification", "title", title, "message", message, "theme", theme)
 
-	// send the actual notification
-	if err := beeep.Notify(title, message, appIconPath); err != nil {
-		tn.logger.Errorw("Failed to send toast notification", "error", err)
+	// send the actual notification, without waiting forever on a daemon that's stuck
+	done := make(chan error, 1)
+	go func() {
+		done <- beeep.Notify(title, message, appIconPath)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("send toast notification: %w", err)
+		}
+	case <-time.After(notifyTimeout):
+		return fmt.Errorf("send toast notification: %w", errNotifyTimeout)
 	}
+
+	return nil
 }
@@ -0,0 +1,58 @@
+package deej
+
+import (
+	"os/exec"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const notificationsBusName = "org.freedesktop.Notifications"
+
+// detectDesktopNotifications checks what desktop notifications need on linux. beeep talks to the notification
+// daemon over the session bus, and falls back to kdialog's popups without one. minimal window managers often
+// come with neither
+func detectDesktopNotifications() (bool, []string) {
+	if notificationDaemonRunning() {
+		return true, nil
+	}
+
+	missing := []string{"a notification daemon (" + notificationsBusName + ")"}
+
+	if _, err := exec.LookPath("kdialog"); err == nil {
+		return true, missing
+	}
+
+	return false, append(missing, "kdialog")
+}
+
+// notificationDaemonRunning returns true if something on the session bus shows notifications
+func notificationDaemonRunning() bool {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	var owned bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, notificationsBusName).Store(&owned); err != nil {
+		return false
+	}
+
+	if owned {
+		return true
+	}
+
+	// daemons can also be started on demand, by the bus itself
+	var activatable []string
+	if err := conn.BusObject().Call("org.freedesktop.DBus.ListActivatableNames", 0).Store(&activatable); err != nil {
+		return false
+	}
+
+	for _, name := range activatable {
+		if name == notificationsBusName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,7 @@
+package deej
+
+// detectDesktopNotifications checks what desktop notifications need. windows always has toasts (or balloons on
+// versions from before them)
+func detectDesktopNotifications() (bool, []string) {
+	return true, nil
+}
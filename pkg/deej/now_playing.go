@@ -0,0 +1,232 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// what's playing on each slider comes from MPRIS (see mprisWatcher), so there's only something to report on linux.
+// it's worked out again whenever a player changes or the mapping does
+
+// longer titles are cut short before being sent to the board's display
+const nowPlayingDisplayLength = 40
+
+// SliderNowPlaying is what the players behind a slider's targets are playing
+type SliderNowPlaying struct {
+	SliderID   int        `json:"slider"`
+	NowPlaying *MprisInfo `json:"nowPlaying,omitempty"`
+}
+
+// nowPlayingTracker keeps track of what's playing on each slider, and tells the web UI and the board when it changes
+type nowPlayingTracker struct {
+	deej   *Deej
+	logger *zap.SugaredLogger
+
+	// slider -> what's playing on it, for sliders with something playing (or paused) on them
+	current map[int]*MprisInfo
+
+	consumers map[chan []SliderNowPlaying]bool
+
+	// slider -> the text the board's display was last sent for it
+	displayed map[int]string
+
+	lock sync.Mutex
+
+	// refreshes happen one at a time, so the display doesn't get sent the same thing twice
+	refreshLock sync.Mutex
+}
+
+func newNowPlayingTracker(deej *Deej, logger *zap.SugaredLogger) *nowPlayingTracker {
+	logger = logger.Named("now_playing")
+
+	npt := &nowPlayingTracker{
+		deej:      deej,
+		logger:    logger,
+		current:   map[int]*MprisInfo{},
+		consumers: map[chan []SliderNowPlaying]bool{},
+		displayed: map[int]string{},
+	}
+
+	logger.Debug("Created now playing tracker instance")
+
+	return npt
+}
+
+func (npt *nowPlayingTracker) initialize() {
+	playerChanges := npt.deej.mpris.Changes()
+	configReloadedChannel := npt.deej.config.SubscribeToChanges()
+
+	go func() {
+		for {
+			select {
+			case <-playerChanges:
+			case <-configReloadedChannel:
+			}
+
+			npt.refresh()
+		}
+	}()
+}
+
+// snapshot returns what's playing on each mapped slider
+func (npt *nowPlayingTracker) snapshot() []SliderNowPlaying {
+	npt.lock.Lock()
+	defer npt.lock.Unlock()
+
+	return npt.list()
+}
+
+// subscribe returns a channel that receives what's playing on each mapped slider whenever it changes. only the
+// latest is kept for slow readers
+func (npt *nowPlayingTracker) subscribe() chan []SliderNowPlaying {
+	ch := make(chan []SliderNowPlaying, 1)
+
+	npt.lock.Lock()
+	npt.consumers[ch] = true
+	npt.lock.Unlock()
+
+	return ch
+}
+
+func (npt *nowPlayingTracker) unsubscribe(ch chan []SliderNowPlaying) {
+	npt.lock.Lock()
+	delete(npt.consumers, ch)
+	npt.lock.Unlock()
+}
+
+// resendDisplay makes the next refresh send everything to the board's display again, e.g. because it just connected
+func (npt *nowPlayingTracker) resendDisplay() {
+	if npt == nil {
+		return
+	}
+
+	npt.lock.Lock()
+	npt.displayed = map[int]string{}
+	npt.lock.Unlock()
+
+	go npt.refresh()
+}
+
+// refresh works out what's playing on each slider, and lets everyone know if it changed
+func (npt *nowPlayingTracker) refresh() {
+	npt.refreshLock.Lock()
+	defer npt.refreshLock.Unlock()
+
+	players := getAllMprisPlayers(npt.deej.mpris.Players())
+
+	current := map[int]*MprisInfo{}
+	npt.deej.config.SliderMapping.iterate(func(sliderIdx int, targets []string) {
+		if info := nowPlaying(players, targets); info != nil {
+			current[sliderIdx] = info
+		}
+	})
+
+	npt.lock.Lock()
+	changed := !sameNowPlaying(npt.current, current)
+	npt.current = current
+
+	if changed {
+		list := npt.list()
+
+		for ch := range npt.consumers {
+			select {
+			case <-ch:
+			default:
+			}
+
+			ch <- list
+		}
+	}
+	npt.lock.Unlock()
+
+	npt.updateDisplay(current)
+}
+
+// updateDisplay sends the board's display whatever changed since it was last told. sliders with nothing playing
+// are sent an empty text, to clear what was there
+func (npt *nowPlayingTracker) updateDisplay(current map[int]*MprisInfo) {
+	if !npt.deej.serial.Capabilities().Supports(capabilityDisplay) {
+		return
+	}
+
+	texts := map[int]string{}
+
+	npt.lock.Lock()
+	npt.deej.config.SliderMapping.iterate(func(sliderIdx int, targets []string) {
+		text := nowPlayingDisplayText(current[sliderIdx])
+		if displayed, ok := npt.displayed[sliderIdx]; !ok || displayed != text {
+			texts[sliderIdx] = text
+		}
+	})
+	npt.lock.Unlock()
+
+	for sliderIdx, text := range texts {
+		err := npt.deej.serial.SendCommand(fmt.Sprintf("display:nowplaying:%d:%s", sliderIdx, text))
+		if err != nil {
+			if !errors.Is(err, errCapabilityUnsupported) && !errors.Is(err, errNotConnected) {
+				npt.logger.Warnw("Failed to send now playing to display", "sliderID", sliderIdx, "error", err)
+			}
+			continue
+		}
+
+		npt.lock.Lock()
+		npt.displayed[sliderIdx] = text
+		npt.lock.Unlock()
+	}
+}
+
+// list returns what's playing on each mapped slider, ordered by slider. callers must hold the lock
+func (npt *nowPlayingTracker) list() []SliderNowPlaying {
+	list := []SliderNowPlaying{}
+
+	npt.deej.config.SliderMapping.iterate(func(sliderIdx int, targets []string) {
+		list = append(list, SliderNowPlaying{SliderID: sliderIdx, NowPlaying: npt.current[sliderIdx]})
+	})
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].SliderID < list[j].SliderID
+	})
+
+	return list
+}
+
+func sameNowPlaying(a map[int]*MprisInfo, b map[int]*MprisInfo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for sliderIdx, info := range a {
+		other, ok := b[sliderIdx]
+		if !ok || *other != *info {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nowPlayingDisplayText is what the board's display shows for a slider: the title and artist while something plays
+func nowPlayingDisplayText(info *MprisInfo) string {
+	if info == nil || !info.IsPlaying || info.Title == "" {
+		return ""
+	}
+
+	text := info.Title
+	if info.Artist != "" {
+		text += " - " + info.Artist
+	}
+
+	// the command is a single line
+	text = strings.Join(strings.Fields(text), " ")
+
+	if runes := []rune(text); len(runes) > nowPlayingDisplayLength {
+		text = string(runes[:nowPlayingDisplayLength])
+	}
+
+	return text
+}
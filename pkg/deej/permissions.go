@@ -0,0 +1,120 @@
+package deej
+
+import (
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// SerialPermissionsHelper keeps track of serial ports deej wasn't allowed to open, and walks
+// the user through fixing that when asked to from the tray menu or the web UI.
+// Recording a problem never blocks, so it's safe to do while probing ports
+type SerialPermissionsHelper struct {
+	deej   *Deej
+	logger *zap.SugaredLogger
+
+	problems map[string]*SerialPermissionProblem
+	lock     sync.Mutex
+}
+
+// SerialPermissionProblem describes a serial port that deej wasn't allowed to open
+type SerialPermissionProblem struct {
+	PortName string   `json:"portName"`
+	Groups   []string `json:"groups,omitempty"`
+}
+
+func newSerialPermissionsHelper(deej *Deej, logger *zap.SugaredLogger) *SerialPermissionsHelper {
+	logger = logger.Named("permissions")
+
+	ph := &SerialPermissionsHelper{
+		deej:     deej,
+		logger:   logger,
+		problems: map[string]*SerialPermissionProblem{},
+	}
+
+	logger.Debug("Created serial permissions helper instance")
+
+	return ph
+}
+
+// Problems returns all currently known permission problems, sorted by port name
+func (ph *SerialPermissionsHelper) Problems() []SerialPermissionProblem {
+	ph.lock.Lock()
+	defer ph.lock.Unlock()
+
+	result := make([]SerialPermissionProblem, 0, len(ph.problems))
+	for _, problem := range ph.problems {
+		result = append(result, *problem)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].PortName < result[j].PortName })
+
+	return result
+}
+
+// Fix tries to resolve every known permission problem, asking the user for confirmation
+// through the configured dialog backend. It blocks for as long as the dialogs are open
+func (ph *SerialPermissionsHelper) Fix() {
+	dialog := newDialogBackend(ph.deej.config.PermissionDialog, ph.deej.notifier, ph.logger)
+	problems := ph.Problems()
+
+	ph.logger.Infow("Fixing serial port permissions", "problems", len(problems), "dialog", dialog.Name())
+
+	if len(problems) == 0 {
+		dialog.Inform(tr("permissions.title"), tr("permissions.no_problems"))
+		return
+	}
+
+	for _, problem := range problems {
+		resolved, err := fixPermissionProblem(ph.logger, dialog, problem)
+		if err != nil {
+			ph.logger.Warnw("Failed to fix serial port permissions", "port", problem.PortName, "error", err)
+			continue
+		}
+
+		if resolved {
+			ph.lock.Lock()
+			delete(ph.problems, problem.PortName)
+			ph.lock.Unlock()
+		}
+	}
+}
+
+// reportDenied records that opening the given port failed with a permission error, and lets
+// the user know (once per port) that the helper can sort it out for them
+func (ph *SerialPermissionsHelper) reportDenied(portName string) {
+	ph.lock.Lock()
+	defer ph.lock.Unlock()
+
+	if _, known := ph.problems[portName]; known {
+		return
+	}
+
+	groups, err := serialDeviceGroups(portName)
+	if err != nil {
+		ph.logger.Debugw("Couldn't determine serial device groups", "port", portName, "error", err)
+	}
+
+	ph.problems[portName] = &SerialPermissionProblem{
+		PortName: portName,
+		Groups:   groups,
+	}
+
+	ph.logger.Warnw("Permission denied opening serial port", "port", portName, "groups", groups)
+
+	// the tray menu and web UI still offer to fix it
+	if ph.deej.waitingPassively() {
+		return
+	}
+
+	go ph.deej.notifier.Notify(tr("notify.port_permission.title", portName), tr("notify.port_permission.message"))
+}
+
+func (ph *SerialPermissionsHelper) hasProblem(portName string) bool {
+	ph.lock.Lock()
+	defer ph.lock.Unlock()
+
+	_, ok := ph.problems[portName]
+	return ok
+}
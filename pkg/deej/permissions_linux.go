@@ -0,0 +1,104 @@
+package deej
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// serialDeviceGroups returns the names of the group(s) owning a device node
+func serialDeviceGroups(portName string) ([]string, error) {
+	info, err := os.Stat(portName)
+	if err != nil {
+		return nil, fmt.Errorf("stat device: %w", err)
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, fmt.Errorf("unexpected stat type for %s", portName)
+	}
+
+	groupFile, err := os.Open("/etc/group")
+	if err != nil {
+		return nil, fmt.Errorf("open group file: %w", err)
+	}
+	defer groupFile.Close()
+
+	gid := fmt.Sprint(stat.Gid)
+	groups := []string{}
+
+	scanner := bufio.NewScanner(groupFile)
+	for scanner.Scan() {
+		parts := strings.Split(scanner.Text(), ":")
+		if len(parts) >= 3 && parts[2] == gid {
+			groups = append(groups, parts[0])
+		}
+	}
+
+	return groups, nil
+}
+
+// fixPermissionProblem offers to add the current user to the group owning the device.
+// it returns true if the problem can be considered handled
+func fixPermissionProblem(logger *zap.SugaredLogger, dialog dialogBackend, problem SerialPermissionProblem) (bool, error) {
+	user := os.Getenv("USER")
+
+	if len(problem.Groups) == 0 {
+		dialog.Inform(tr("permissions.unknown_group.title"), tr("permissions.unknown_group.message", problem.PortName))
+
+		return false, nil
+	}
+
+	groupNames := strings.Join(problem.Groups, " or ")
+
+	// check whether the user's already in the group, which just means they haven't logged in again since joining it
+	output, err := exec.Command("id", "-nG", user).Output()
+	if err == nil {
+		memberships := strings.Fields(string(output))
+		for _, group := range problem.Groups {
+			for _, membership := range memberships {
+				if group == membership {
+					dialog.Inform(tr("permissions.already_member.title"), tr("permissions.already_member.message", groupNames))
+
+					return true, nil
+				}
+			}
+		}
+	}
+
+	manualCommand := fmt.Sprintf("sudo usermod -aG %s %s", problem.Groups[0], user)
+
+	if !dialog.Interactive() {
+		dialog.Inform(tr("permissions.title"), tr("permissions.manual.message", problem.PortName, manualCommand))
+
+		return false, nil
+	}
+
+	confirmed, err := dialog.Confirm(tr("permissions.title"), tr("permissions.confirm.message", problem.PortName, groupNames))
+
+	if err != nil {
+		return false, fmt.Errorf("confirm group change: %w", err)
+	}
+
+	if !confirmed {
+		dialog.Inform(tr("permissions.cancelled.title"), tr("permissions.cancelled.message"))
+		return false, nil
+	}
+
+	if err := exec.Command("pkexec", "usermod", "-aG", problem.Groups[0], user).Run(); err != nil {
+		logger.Warnw("Failed to add user to group", "group", problem.Groups[0], "user", user, "error", err)
+		dialog.Inform(tr("permissions.failed.title"), tr("permissions.failed.message", manualCommand))
+
+		return false, fmt.Errorf("add user to group: %w", err)
+	}
+
+	logger.Infow("Added user to serial device group", "group", problem.Groups[0], "user", user)
+	dialog.Inform(tr("permissions.added.title"), tr("permissions.added.message"))
+
+	return true, nil
+}
@@ -0,0 +1,22 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// serial ports aren't group-owned on windows
+func serialDeviceGroups(portName string) ([]string, error) {
+	return nil, errors.New("not supported on windows")
+}
+
+// on windows, "access denied" on a COM port means something else has it open. there's nothing for us to
+// fix here, but we can at least explain it
+func fixPermissionProblem(logger *zap.SugaredLogger, dialog dialogBackend, problem SerialPermissionProblem) (bool, error) {
+	dialog.Inform(fmt.Sprintf("Can't open %s", problem.PortName),
+		"Windows denied access to this port, which usually means another program (a serial monitor, or another deej instance) is using it.")
+
+	return false, nil
+}
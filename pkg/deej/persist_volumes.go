@@ -0,0 +1,23 @@
+package deej
+
+// apps usually come back at whatever volume the audio server remembers for them, rather than the one deej last set,
+// until their slider moves again. with persist_volumes on, sessions whose backend keeps such a memory (pulseaudio's
+// and pipewire's stream-restore database, see persist_volumes_linux.go) are told to remember deej's volume instead
+type volumePersister interface {
+
+	// persistVolume remembers the volume for the session's app. it's called for every volume a slider sets (see
+	// volumeWorker.apply) and for ducked sessions coming back, so it's up to the session to only actually write the
+	// last of a quick series. a session that still has one to write does so when it's released
+	persistVolume(volume float32)
+}
+
+// persistVolume has the audio server remember a volume deej set, if configured to and the session can
+func (m *sessionMap) persistVolume(session Session, volume float32) {
+	if !m.deej.config.PersistVolumes {
+		return
+	}
+
+	if persister, ok := session.(volumePersister); ok {
+		persister.persistVolume(volume)
+	}
+}
@@ -0,0 +1,107 @@
+package deej
+
+import (
+	"time"
+
+	"github.com/jfreymuth/pulse/proto"
+)
+
+// module-stream-restore's extension protocol, see pulseaudio's module-stream-restore.c. pipewire-pulse speaks it too
+const (
+	streamRestoreExtension       = "module-stream-restore"
+	streamRestoreSubcommandWrite = 2
+	streamRestoreUpdateMerge     = 1 // PA_UPDATE_MERGE, leave the other entries alone
+
+	// sliders send a move every few milliseconds while they're moving, only the volume they stop at is written
+	streamRestoreWriteDelay = time.Second
+)
+
+// streamRestoreWrite writes one entry to the stream-restore database. the client has no type for extension
+// commands, so this borrows proto.Extension's command and spells out the rest. the embedded struct itself isn't
+// written (the client skips struct fields it doesn't know), hence Index and Name
+type streamRestoreWrite struct {
+	proto.Extension
+
+	Index      uint32
+	Name       string
+	Subcommand uint32
+
+	Mode             uint32
+	ApplyImmediately bool
+
+	EntryName      string
+	ChannelMap     proto.ChannelMap
+	ChannelVolumes proto.ChannelVolumes
+	Device         string
+	Muted          bool
+}
+
+// streamRestoreEntryName returns the name pulse files a playback stream's volume under, the same way it picks one
+// (see pa_proplist_get_stream_group), or an empty string if it wouldn't remember it at all
+func streamRestoreEntryName(properties proto.PropList) string {
+	for _, group := range []struct{ property, by string }{
+		{"media.role", "media-role"},
+		{"application.id", "application-id"},
+		{"application.name", "application-name"},
+		{"media.name", "media-name"},
+	} {
+		if value := paStringProperty(properties, group.property); value != "" {
+			return "sink-input-by-" + group.by + ":" + value
+		}
+	}
+
+	return ""
+}
+
+func (s *paSession) persistVolume(volume float32) {
+	if s.restoreEntryName == "" {
+		return
+	}
+
+	s.persistLock.Lock()
+	defer s.persistLock.Unlock()
+
+	if s.persistTimer != nil {
+		s.persistTimer.Stop()
+	}
+
+	s.pendingVolume = volume
+	s.persistTimer = time.AfterFunc(streamRestoreWriteDelay, func() {
+		s.writeRestoreEntry(volume)
+	})
+}
+
+// flushPersistedVolume writes a volume that's still waiting for its slider to settle right away, rather than after
+// the session was released. it's called while the session's client is still around
+func (s *paSession) flushPersistedVolume() {
+	s.persistLock.Lock()
+	timer, volume := s.persistTimer, s.pendingVolume
+	s.persistTimer = nil
+	s.persistLock.Unlock()
+
+	if timer != nil && timer.Stop() {
+		s.writeRestoreEntry(volume)
+	}
+}
+
+// writeRestoreEntry replaces the volume pulse remembers for the session's app, keeping its mute state
+func (s *paSession) writeRestoreEntry(volume float32) {
+	request := streamRestoreWrite{
+		Index:            proto.Undefined,
+		Name:             streamRestoreExtension,
+		Subcommand:       streamRestoreSubcommandWrite,
+		Mode:             streamRestoreUpdateMerge,
+		ApplyImmediately: false,
+		EntryName:        s.restoreEntryName,
+		ChannelMap:       s.channelMap,
+		ChannelVolumes:   createChannelVolumes(byte(len(s.channelMap)), volume),
+		Muted:            s.GetMute(),
+	}
+
+	if err := s.client.Request(&request, nil); err != nil {
+		s.logger.Warnw("Failed to write stream-restore entry", "entry", s.restoreEntryName, "error", err)
+		return
+	}
+
+	s.logger.Debugw("Wrote stream-restore entry", "entry", s.restoreEntryName, "volume", volume)
+}
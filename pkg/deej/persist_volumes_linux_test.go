@@ -0,0 +1,26 @@
+package deej
+
+import (
+	"testing"
+
+	"github.com/jfreymuth/pulse/proto"
+)
+
+func TestStreamRestoreEntryName(t *testing.T) {
+	for _, tc := range []struct {
+		properties proto.PropList
+		expected   string
+	}{
+		{proto.PropList{"application.name": proto.PropListString("Firefox"), "media.name": proto.PropListString("AudioStream")},
+			"sink-input-by-application-name:Firefox"},
+		{proto.PropList{"application.name": proto.PropListString("Spotify"), "media.role": proto.PropListString("music")},
+			"sink-input-by-media-role:music"},
+		{proto.PropList{"application.id": proto.PropListString("org.gnome.Totem"), "application.name": proto.PropListString("Videos")},
+			"sink-input-by-application-id:org.gnome.Totem"},
+		{proto.PropList{}, ""},
+	} {
+		if name := streamRestoreEntryName(tc.properties); name != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, name)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package deej
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// pipelineFixture describes a config, the audio sessions around, and the lines a device sends, along with
+// what each line should do. fixtures live in testdata/pipeline, one per scenario
+type pipelineFixture struct {
+	Description string   `yaml:"description"`
+	Config      string   `yaml:"config"`
+	Sessions    []string `yaml:"sessions"`
+
+	Steps []struct {
+		Line string `yaml:"line"`
+
+		// how many slider moves the line should cause
+		Moves int `yaml:"moves"`
+
+		// session key -> its volume once the line's been handled
+		Volumes map[string]float32 `yaml:"volumes"`
+	} `yaml:"steps"`
+}
+
+func TestMappingPipelineFixtures(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "pipeline", "*.yaml"))
+	if err != nil {
+		t.Fatalf("list fixtures: %v", err)
+	}
+
+	if len(paths) == 0 {
+		t.Fatal("no pipeline fixtures found")
+	}
+
+	for _, path := range paths {
+		path := path
+
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".yaml"), func(t *testing.T) {
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read fixture: %v", err)
+			}
+
+			fixture := pipelineFixture{}
+			if err := yaml.UnmarshalStrict(contents, &fixture); err != nil {
+				t.Fatalf("parse fixture: %v", err)
+			}
+
+			td := newTestDeej(t, fixture.Config, fixture.Sessions...)
+
+			for stepIdx, step := range fixture.Steps {
+				moves := td.feed(step.Line)
+				if len(moves) != step.Moves {
+					t.Fatalf("step %d (%q): expected %d slider moves, got %d: %+v", stepIdx, step.Line, step.Moves, len(moves), moves)
+				}
+
+				td.expectVolumes(t, step.Volumes)
+				if t.Failed() {
+					t.Fatalf("step %d (%q) failed: %s", stepIdx, step.Line, fixture.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestMappingOverridesReachSessions(t *testing.T) {
+	td := newTestDeej(t, "slider_mapping:\n  0: spotify.exe\n", "spotify.exe", "discord.exe")
+
+	if err := td.config.SetMappingOverride(0, []string{"discord.exe"}); err != nil {
+		t.Fatalf("set override: %v", err)
+	}
+
+	td.feed("512")
+	td.expectVolumes(t, map[string]float32{"discord.exe": 0.5, "spotify.exe": fakeSessionInitialVolume})
+
+	td.config.ClearMappingOverride(0)
+
+	td.feed("256")
+	td.expectVolumes(t, map[string]float32{"discord.exe": 0.5, "spotify.exe": 0.25})
+}
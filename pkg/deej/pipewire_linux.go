@@ -0,0 +1,128 @@
+package deej
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// eq targets and the jack backend both go through pipewire's command line tools: pw-dump to read nodes, and pw-cli
+// to set their props
+const (
+	pwNodeType     = "PipeWire:Interface:Node"
+	pwMetadataType = "PipeWire:Interface:Metadata"
+)
+
+// pwObject is the part of pw-dump's output deej needs
+type pwObject struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+	Info struct {
+		Props  map[string]interface{} `json:"props"`
+		Params struct {
+			Props []pwProps `json:"Props"`
+		} `json:"params"`
+	} `json:"info"`
+
+	// only set for metadata objects
+	Metadata []struct {
+		Key   string          `json:"key"`
+		Value json.RawMessage `json:"value"`
+	} `json:"metadata"`
+}
+
+// pwProps is one of a node's props entries, as pw-dump lists them. which fields are set depends on the entry
+type pwProps struct {
+	ChannelVolumes []float64 `json:"channelVolumes"`
+	Mute           bool      `json:"mute"`
+
+	// filter-chain controls, alternating between a control's name and its value
+	Params []interface{} `json:"params"`
+}
+
+// pwPropsWriter sets a node's props with pw-cli, one call at a time from a goroutine of its own. pw-cli takes a
+// while to start, so props that are set again before it got to them are only set to the latest value
+type pwPropsWriter struct {
+	logger *zap.SugaredLogger
+
+	// sets the props, given in pw-cli's pod syntax
+	apply func(props string) error
+
+	// what's being set -> the props that set it
+	pending map[string]string
+	running bool
+	lock    sync.Mutex
+}
+
+// pwDump lists every object pipewire knows about, by id
+func pwDump() ([]pwObject, error) {
+	output, err := exec.Command("pw-dump").Output()
+	if err != nil {
+		return nil, fmt.Errorf("run pw-dump: %w", err)
+	}
+
+	objects := []pwObject{}
+	if err := json.Unmarshal(output, &objects); err != nil {
+		return nil, fmt.Errorf("parse pw-dump output: %w", err)
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].ID < objects[j].ID })
+
+	return objects, nil
+}
+
+func newPWPropsWriter(logger *zap.SugaredLogger, apply func(props string) error) *pwPropsWriter {
+	return &pwPropsWriter{
+		logger:  logger,
+		apply:   apply,
+		pending: map[string]string{},
+	}
+}
+
+// set queues props to be set, replacing any that set the same thing (key) and haven't been yet
+func (w *pwPropsWriter) set(key string, props string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.pending[key] = props
+
+	if !w.running {
+		w.running = true
+		go w.run()
+	}
+}
+
+func (w *pwPropsWriter) run() {
+	for {
+		w.lock.Lock()
+		pending := w.pending
+		w.pending = map[string]string{}
+
+		if len(pending) == 0 {
+			w.running = false
+			w.lock.Unlock()
+			return
+		}
+		w.lock.Unlock()
+
+		for _, props := range pending {
+			if err := w.apply(props); err != nil {
+				w.logger.Warnw("Failed to set node props", "props", props, "error", err)
+			}
+		}
+	}
+}
+
+// pwSetProps sets some of a node's props, given in pw-cli's pod syntax
+func pwSetProps(nodeID int, props string) error {
+	if err := exec.Command("pw-cli", "set-param", strconv.Itoa(nodeID), "Props", props).Run(); err != nil {
+		return fmt.Errorf("set node props: %w", err)
+	}
+
+	return nil
+}
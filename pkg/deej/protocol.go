@@ -0,0 +1,261 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// devices advertising the "crc" capability append a checksum to every line they send, and expect one on every
+// command they receive. it's a CRC-8 (polynomial 0x07) over everything before the separator, as two hex digits:
+// "deej:v2.0:sliders:512|1023|0*99"
+const (
+	checksumSeparator = '*'
+	checksumLength    = 2
+)
+
+// protocolVersion is the version a device reports in its framed messages ("deej:v2.0:...")
+type protocolVersion struct {
+	raw   string
+	major int
+	minor int
+}
+
+const (
+
+	// the protocol major version this build of deej speaks. devices reporting the same major
+	// version are compatible, regardless of minor version
+	supportedProtocolMajor = 2
+
+	// firmware that predates the "deej:" framing and only ever sends bare "n|n|n" lines
+	legacyProtocolMajor = 1
+)
+
+// outbound message types
+const (
+	messageTypeCommand  = "command"
+	messageTypeSetFader = "setfader"
+	messageTypeConfig   = "config"
+)
+
+var (
+	legacyProtocol = protocolVersion{raw: "legacy", major: legacyProtocolMajor}
+
+	errLegacyProtocol           = errors.New("device firmware predates the command protocol")
+	errIncompatibleProtocol     = errors.New("device firmware uses an incompatible protocol version")
+	errMalformedProtocolVersion = errors.New("malformed protocol version")
+	errChecksumMismatch         = errors.New("checksum mismatch")
+	errChecksumMissing          = errors.New("checksum missing")
+)
+
+// parseProtocolVersion parses versions like "v2.0", "v2" or "2.1"
+func parseProtocolVersion(raw string) (protocolVersion, error) {
+	version := protocolVersion{raw: raw}
+
+	parts := strings.SplitN(strings.TrimPrefix(strings.ToLower(raw), "v"), ".", 2)
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return version, fmt.Errorf("parse major version of %q: %w", raw, errMalformedProtocolVersion)
+	}
+
+	version.major = major
+
+	if len(parts) == 2 {
+		// tolerate suffixes like "2.1-beta"
+		minorDigits := strings.TrimRightFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' })
+		if minor, err := strconv.Atoi(minorDigits); err == nil {
+			version.minor = minor
+		}
+	}
+
+	return version, nil
+}
+
+func (v protocolVersion) String() string {
+	return v.raw
+}
+
+// compatible returns nil if we know how to talk to a device speaking this version
+func (v protocolVersion) compatible() error {
+	switch {
+	case v.major == legacyProtocolMajor:
+		return errLegacyProtocol
+	case v.major != supportedProtocolMajor:
+		return errIncompatibleProtocol
+	}
+
+	return nil
+}
+
+// formatCommand builds an outbound command in the format the device expects
+func (v protocolVersion) formatCommand(command string) (string, error) {
+	return v.formatMessage(messageTypeCommand, command)
+}
+
+// formatMessage builds any outbound message ("deej:v2.0:setfader:0:512\n"). messages are tagged
+// with the device's own version string, since that's what its parser was written against
+func (v protocolVersion) formatMessage(messageType string, payload string) (string, error) {
+	if err := v.compatible(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("deej:%s:%s:%s\n", v.raw, messageType, payload), nil
+}
+
+// crc8 computes a CRC-8 with polynomial 0x07, which is small and fast enough for AVR boards to compute per line
+func crc8(data string) byte {
+	var crc byte
+
+	for i := 0; i < len(data); i++ {
+		crc ^= data[i]
+
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
+
+// appendChecksum adds the separator and checksum to an outbound message (without its trailing newline)
+func appendChecksum(message string) string {
+	return fmt.Sprintf("%s%c%02x", message, checksumSeparator, crc8(message))
+}
+
+// verifyChecksum strips and checks a line's checksum, if it has one. lines without a checksum are
+// only accepted when the device didn't negotiate checksums
+func verifyChecksum(line string, required bool) (string, error) {
+	separatorIdx := len(line) - checksumLength - 1
+
+	if separatorIdx < 0 || line[separatorIdx] != checksumSeparator {
+		if required {
+			return line, errChecksumMissing
+		}
+
+		return line, nil
+	}
+
+	expected, err := strconv.ParseUint(line[separatorIdx+1:], 16, 8)
+	if err != nil {
+
+		// not actually a checksum, just a message that happens to end in a similar way
+		if required {
+			return line, errChecksumMissing
+		}
+
+		return line, nil
+	}
+
+	payload := line[:separatorIdx]
+	if crc8(payload) != byte(expected) {
+		return payload, errChecksumMismatch
+	}
+
+	return payload, nil
+}
+
+// the lines we parse arrive many times a second, so the parsers below stick to slicing the line they're given
+
+// frame is a framed message from the device, "deej:<version>:<type>:<payload>". the payload can be
+// made of more colon-separated fields, e.g. "deej:v2.0:button:0:down"
+type frame struct {
+	version     string
+	messageType string
+	payload     string
+	hasPayload  bool
+}
+
+const framePrefix = "deej:"
+
+// parseFrame splits a framed line into its parts. it returns false for lines that aren't framed,
+// or are missing the version or type
+func parseFrame(line string) (frame, bool) {
+	if !strings.HasPrefix(line, framePrefix) {
+		return frame{}, false
+	}
+
+	rest := line[len(framePrefix):]
+
+	versionEnd := strings.IndexByte(rest, ':')
+	if versionEnd < 0 {
+		return frame{}, false
+	}
+
+	f := frame{version: rest[:versionEnd]}
+	rest = rest[versionEnd+1:]
+
+	if typeEnd := strings.IndexByte(rest, ':'); typeEnd >= 0 {
+		f.messageType, f.payload, f.hasPayload = rest[:typeEnd], rest[typeEnd+1:], true
+	} else {
+		f.messageType = rest
+	}
+
+	return f, true
+}
+
+// field returns the payload's idx-th colon-separated field, or false if it doesn't have that many
+func (f frame) field(idx int) (string, bool) {
+	if !f.hasPayload {
+		return "", false
+	}
+
+	rest := f.payload
+	for ; idx > 0; idx-- {
+		separator := strings.IndexByte(rest, ':')
+		if separator < 0 {
+			return "", false
+		}
+
+		rest = rest[separator+1:]
+	}
+
+	if separator := strings.IndexByte(rest, ':'); separator >= 0 {
+		rest = rest[:separator]
+	}
+
+	return rest, true
+}
+
+// fieldsFrom returns the payload's fields from the idx-th one on. unlike field, it allocates, so it's
+// meant for messages that don't come often
+func (f frame) fieldsFrom(idx int) []string {
+	fields := []string{}
+
+	for ; ; idx++ {
+		value, ok := f.field(idx)
+		if !ok {
+			return fields
+		}
+
+		fields = append(fields, value)
+	}
+}
+
+// validSliderData returns true for slider data made of 1 to 4 digit values separated by pipes, e.g. "512|1023|0"
+func validSliderData(data string) bool {
+	digits := 0
+
+	for idx := 0; idx < len(data); idx++ {
+		switch char := data[idx]; {
+		case char >= '0' && char <= '9':
+			digits++
+			if digits > 4 {
+				return false
+			}
+
+		case char == '|' && digits > 0:
+			digits = 0
+
+		default:
+			return false
+		}
+	}
+
+	return digits > 0
+}
@@ -0,0 +1,72 @@
+package deej
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// what validSliderData replaced, kept around to check it against
+var sliderDataPattern = regexp.MustCompile(`^\d{1,4}(\|\d{1,4})*$`)
+
+var frameSeeds = []string{
+	"deej:v2.0:startup:5sliders,2buttons,display",
+	"deej:v2.0:sliders:512|256|1023|0|700",
+	"deej:v2.0:button:0:down",
+	"deej:v2.0:response:error:unknown_command:version",
+	"deej:v2.0:response:reboot_ack",
+	"deej:v2.0:sliders",
+	"deej:v2.0",
+	"deej:",
+	"deej::::",
+	"512|256",
+	"",
+}
+
+func FuzzParseFrame(f *testing.F) {
+	for _, seed := range frameSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		frame, ok := parseFrame(line)
+		if !ok {
+			if strings.Count(line, ":") >= 2 && strings.HasPrefix(line, framePrefix) {
+				t.Fatalf("%q: rejected a line with a version and type", line)
+			}
+
+			return
+		}
+
+		// the parts have to add back up to the line they came from
+		rebuilt := framePrefix + frame.version + ":" + frame.messageType
+		if frame.hasPayload {
+			rebuilt += ":" + frame.payload
+		}
+
+		if rebuilt != line {
+			t.Fatalf("%q: parsed into %+v, which rebuilds as %q", line, frame, rebuilt)
+		}
+
+		fields := []string{}
+		if frame.hasPayload {
+			fields = strings.Split(frame.payload, ":")
+		}
+
+		if actual := frame.fieldsFrom(0); strings.Join(actual, ":") != strings.Join(fields, ":") || len(actual) != len(fields) {
+			t.Fatalf("%q: expected fields %q, got %q", line, fields, actual)
+		}
+	})
+}
+
+func FuzzValidSliderData(f *testing.F) {
+	for _, seed := range append(frameSeeds, "0", "1023|", "|1023", "12345", "1||2", "1|2|3", "١٢٣") {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data string) {
+		if expected, actual := sliderDataPattern.MatchString(data), validSliderData(data); expected != actual {
+			t.Fatalf("%q: expected %v, got %v", data, expected, actual)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package deej
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfreymuth/pulse/proto"
+)
+
+// the pulseaudio protocol has no way to cancel a request. the client matches replies to requests by tag and decodes
+// them straight into the reply value a request was made with, so a request that's given up on still has its reply
+// decoded later, into a value its caller has moved on from, and a hung server keeps every later request waiting
+// behind it. so each request decodes into a reply value of its own, which is only handed to the caller once it's
+// complete, and a request timing out is taken to mean the connection can't be trusted anymore: it's dropped, the
+// requests still waiting on it fail right away, and the session finder reconnects (see watchConnection)
+
+// paClient is a PulseAudio client whose requests time out, and are timed (see audio_calls.go)
+type paClient struct {
+	*proto.Client
+	conn net.Conn
+
+	// closed once the connection is dropped, along with why it was
+	dropped      chan struct{}
+	dropReason   error
+	dropOnce     sync.Once
+	dropCloseErr error
+}
+
+// how long a request made without a deadline of its own may take
+const paRequestTimeout = 2 * time.Second
+
+var errPAConnectionDropped = errors.New("PulseAudio connection dropped")
+
+func newPAClient(client *proto.Client, conn net.Conn) *paClient {
+	return &paClient{
+		Client:  client,
+		conn:    conn,
+		dropped: make(chan struct{}),
+	}
+}
+
+// Request makes a request that times out after paRequestTimeout
+func (c *paClient) Request(req proto.RequestArgs, rpl proto.Reply) error {
+	ctx, cancel := context.WithTimeout(context.Background(), paRequestTimeout)
+	defer cancel()
+
+	return c.RequestContext(ctx, req, rpl)
+}
+
+// RequestContext makes a request, giving up on it (and the connection) once the context is done
+func (c *paClient) RequestContext(ctx context.Context, req proto.RequestArgs, rpl proto.Reply) error {
+	name := paRequestName(req)
+	defer audioCalls.time(name)()
+
+	select {
+	case <-c.dropped:
+		return c.dropReason
+	default:
+	}
+
+	var own proto.Reply
+	if rpl != nil {
+		own = reflect.New(reflect.TypeOf(rpl).Elem()).Interface().(proto.Reply)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Client.Request(req, own)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil && rpl != nil {
+			reflect.ValueOf(rpl).Elem().Set(reflect.ValueOf(own).Elem())
+		}
+
+		return err
+
+	case <-c.dropped:
+		return c.dropReason
+
+	case <-ctx.Done():
+		err := fmt.Errorf("%s: %w", name, ctx.Err())
+		c.drop(err)
+
+		return err
+	}
+}
+
+// Dropped returns a channel that's closed once the connection is dropped
+func (c *paClient) Dropped() <-chan struct{} {
+	return c.dropped
+}
+
+// drop closes the connection, failing every request still waiting on it. only the first call does anything, and
+// every call returns what closing the connection came to
+func (c *paClient) drop(reason error) error {
+	c.dropOnce.Do(func() {
+		c.dropReason = fmt.Errorf("%w: %v", errPAConnectionDropped, reason)
+		close(c.dropped)
+
+		c.dropCloseErr = c.conn.Close()
+	})
+
+	return c.dropCloseErr
+}
+
+// paRequestName returns a request's name for logs and metrics, e.g. "GetSinkInfo"
+func paRequestName(req proto.RequestArgs) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", req), "*proto.")
+}
@@ -0,0 +1,55 @@
+package deej
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jfreymuth/pulse/proto"
+)
+
+func TestPAClientTimeoutDropsConnection(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer serverConn.Close()
+
+	// a server that reads requests and never answers them
+	go io.Copy(ioutil.Discard, serverConn)
+
+	protoClient := &proto.Client{}
+	protoClient.Open(clientConn)
+	client := newPAClient(protoClient, clientConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	reply := proto.GetServerInfoReply{PackageName: "untouched"}
+
+	err := client.RequestContext(ctx, &proto.GetServerInfo{}, &reply)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the request to time out, got %v", err)
+	}
+
+	if reply.PackageName != "untouched" {
+		t.Errorf("expected the reply to be left alone, got %q", reply.PackageName)
+	}
+
+	select {
+	case <-client.Dropped():
+	default:
+		t.Fatal("expected the connection to be dropped")
+	}
+
+	// later requests fail right away, instead of queueing up behind the one that timed out
+	started := time.Now()
+	if err := client.Request(&proto.GetServerInfo{}, &reply); !errors.Is(err, errPAConnectionDropped) {
+		t.Errorf("expected the connection to be dropped, got %v", err)
+	}
+
+	if elapsed := time.Since(started); elapsed > paRequestTimeout/2 {
+		t.Errorf("expected a dropped connection to fail fast, took %v", elapsed)
+	}
+}
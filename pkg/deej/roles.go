@@ -0,0 +1,168 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// role targets ("role:music", "role:game", "role:communication") match sessions by the kind of audio they play,
+// as reported by the audio backend, for console-style game/chat/music mixing without listing every app
+const roleTargetPrefix = "role:"
+
+const (
+	sessionRoleMusic         = "music"
+	sessionRoleGame          = "game"
+	sessionRoleCommunication = "communication"
+	sessionRoleVideo         = "video"
+	sessionRoleNotification  = "notification"
+)
+
+// backends and apps don't agree on role names, so these get folded into the ones above
+var sessionRoleAliases = map[string]string{
+	"phone":        sessionRoleCommunication, // pulseaudio's name for it
+	"voice":        sessionRoleCommunication,
+	"chat":         sessionRoleCommunication,
+	"movie":        sessionRoleVideo, // pipewire's
+	"event":        sessionRoleNotification,
+	"notification": sessionRoleNotification,
+}
+
+// roleSession is implemented by sessions that can tell what kind of audio they play
+type roleSession interface {
+
+	// Role returns the session's role, or an empty string if the backend doesn't know
+	Role() string
+}
+
+func (s *baseSession) Role() string {
+	return s.role
+}
+
+// normalizeSessionRole lowercases a role reported by a backend, and folds aliases into deej's role names
+func normalizeSessionRole(role string) string {
+	role = strings.ToLower(strings.TrimSpace(role))
+
+	if alias, ok := sessionRoleAliases[role]; ok {
+		return alias
+	}
+
+	return role
+}
+
+// roleTarget returns the role a target refers to, if it's a role target
+func roleTarget(target string) (string, bool) {
+	if !strings.HasPrefix(target, roleTargetPrefix) {
+		return "", false
+	}
+
+	return normalizeSessionRole(strings.TrimPrefix(target, roleTargetPrefix)), true
+}
+
+// reportedSessionRole returns the role the audio backend reported for a session, if any
+func reportedSessionRole(session Session) string {
+	if rs, ok := session.(roleSession); ok {
+		return rs.Role()
+	}
+
+	return ""
+}
+
+// sessionRole returns a session's role. the user's classification rules come first,
+// so they can fix up apps that report the wrong role (or none at all, like on windows)
+func (m *sessionMap) sessionRole(session Session) string {
+	if role, ok := m.deej.config.SessionRoles.classify(session.Key()); ok {
+		return role
+	}
+
+	return reportedSessionRole(session)
+}
+
+var errInvalidRolePattern = errors.New("invalid role pattern")
+
+// sessionRoleRule puts sessions whose key matches its pattern in its role
+type sessionRoleRule struct {
+	role    string
+	pattern *regexp.Regexp
+}
+
+// sessionRoleRules are checked in order, and the first matching rule wins
+type sessionRoleRules []sessionRoleRule
+
+// parseSessionRoleRules builds rules from role name -> name patterns, as they appear in the config.
+// roles are ordered by name, so a session matching several of them always ends up in the same one
+func parseSessionRoleRules(patterns map[string][]string) (sessionRoleRules, []error) {
+	roles := make([]string, 0, len(patterns))
+	for role := range patterns {
+		roles = append(roles, role)
+	}
+
+	sort.Strings(roles)
+
+	rules := sessionRoleRules{}
+	errs := []error{}
+
+	for _, role := range roles {
+		for _, pattern := range patterns[role] {
+			compiled, err := compileRolePattern(pattern)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("role %q: %w", role, err))
+				continue
+			}
+
+			rules = append(rules, sessionRoleRule{role: normalizeSessionRole(role), pattern: compiled})
+		}
+	}
+
+	return rules, errs
+}
+
+// compileRolePattern turns a name pattern like "spotify*|vlc.exe" into a regular expression.
+// '*' matches anything, '|' separates alternatives, and matching ignores case
+func compileRolePattern(pattern string) (*regexp.Regexp, error) {
+	alternatives := strings.Split(pattern, "|")
+
+	for idx, alternative := range alternatives {
+		alternative = strings.TrimSpace(alternative)
+		if alternative == "" {
+			return nil, fmt.Errorf("%q has an empty alternative: %w", pattern, errInvalidRolePattern)
+		}
+
+		alternatives[idx] = strings.ReplaceAll(regexp.QuoteMeta(alternative), `\*`, ".*")
+	}
+
+	return regexp.MustCompile(`(?i)^(?:` + strings.Join(alternatives, "|") + `)$`), nil
+}
+
+// classify returns the role of the first rule matching the given session key
+func (rules sessionRoleRules) classify(key string) (string, bool) {
+	for _, rule := range rules {
+		if rule.pattern.MatchString(key) {
+			return rule.role, true
+		}
+	}
+
+	return "", false
+}
+
+// sessionKeysWithRole returns the keys of sessions playing the given role. sessions are controlled by key,
+// so other sessions that share a key with one of them are included too
+func (m *sessionMap) sessionKeysWithRole(role string) []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	keys := []string{}
+
+	for key, sessions := range m.m {
+		for _, session := range sessions {
+			if role != "" && m.sessionRole(session) == role {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+
+	return keys
+}
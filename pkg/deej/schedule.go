@@ -0,0 +1,303 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// a schedule performs actions at set times of day, like switching to a profile in the evening or capping master's
+// volume for quiet hours (see volume_caps.go). times are written like "22:00", and can be limited to some days of
+// the week: "sat 10:00", "mon-fri 07:30" or "sat,sun 09:00". each time has one or more actions
+const (
+
+	// how often the schedule is checked. checking rather than sleeping until the next time keeps the schedule
+	// right across system sleep and clock changes
+	scheduleCheckInterval = 15 * time.Second
+
+	// how far back deej looks for times it missed while it wasn't running (or the system was asleep)
+	scheduleCatchUpWindow = 7 * 24 * time.Hour
+
+	// times that came up longer ago than this by the time they're checked were missed, rather than just come up
+	scheduleMissedAfter = time.Minute
+)
+
+// indexed by time.Weekday
+var scheduleDayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+var errInvalidScheduleTime = errors.New("invalid schedule time")
+
+// scheduleEntry is a time in the schedule, along with what to do then
+type scheduleEntry struct {
+	spec    string
+	days    [7]bool // indexed by time.Weekday
+	minute  int     // since midnight
+	actions []string
+}
+
+// parseScheduleTime parses a schedule time like "22:00" or "mon-fri 07:30"
+func parseScheduleTime(spec string) (scheduleEntry, error) {
+	entry := scheduleEntry{spec: spec}
+
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) == 0 || len(fields) > 2 {
+		return scheduleEntry{}, fmt.Errorf("%q: expected [days] hh:mm: %w", spec, errInvalidScheduleTime)
+	}
+
+	clock, err := time.Parse("15:04", fields[len(fields)-1])
+	if err != nil {
+		return scheduleEntry{}, fmt.Errorf("%q: expected a time like 22:00: %w", spec, errInvalidScheduleTime)
+	}
+
+	entry.minute = clock.Hour()*60 + clock.Minute()
+
+	if len(fields) == 1 {
+		for day := range entry.days {
+			entry.days[day] = true
+		}
+
+		return entry, nil
+	}
+
+	for _, days := range strings.Split(fields[0], ",") {
+		bounds := strings.SplitN(days, "-", 2)
+
+		first, ok := scheduleDay(bounds[0])
+		last := first
+		if ok && len(bounds) == 2 {
+			last, ok = scheduleDay(bounds[1])
+		}
+
+		if !ok {
+			return scheduleEntry{}, fmt.Errorf("%q: unknown day %q: %w", spec, days, errInvalidScheduleTime)
+		}
+
+		// ranges can wrap around the end of the week, e.g. "fri-mon"
+		for day := first; ; day = (day + 1) % 7 {
+			entry.days[day] = true
+
+			if day == last {
+				break
+			}
+		}
+	}
+
+	return entry, nil
+}
+
+func scheduleDay(name string) (int, bool) {
+	for day, dayName := range scheduleDayNames {
+		if name == dayName {
+			return day, true
+		}
+	}
+
+	return 0, false
+}
+
+// on returns when the entry's time is on the given date, if it applies that day
+func (e scheduleEntry) on(date time.Time) (time.Time, bool) {
+	at := time.Date(date.Year(), date.Month(), date.Day(), e.minute/60, e.minute%60, 0, 0, date.Location())
+
+	return at, e.days[at.Weekday()]
+}
+
+// lastOccurrence returns the latest time the entry came up, at or before now
+func (e scheduleEntry) lastOccurrence(now time.Time) time.Time {
+	for daysAgo := 0; daysAgo <= 7; daysAgo++ {
+		if at, ok := e.on(now.AddDate(0, 0, -daysAgo)); ok && !at.After(now) {
+			return at
+		}
+	}
+
+	return time.Time{}
+}
+
+// nextOccurrence returns the first time the entry comes up after now
+func (e scheduleEntry) nextOccurrence(now time.Time) time.Time {
+	for daysAhead := 0; daysAhead <= 7; daysAhead++ {
+		if at, ok := e.on(now.AddDate(0, 0, daysAhead)); ok && at.After(now) {
+			return at
+		}
+	}
+
+	return time.Time{}
+}
+
+// describeScheduledTime puts a scheduled time the way schedule times are written, leaving out the day if it's today
+func describeScheduledTime(at time.Time, now time.Time) string {
+	if at.YearDay() == now.YearDay() && at.Year() == now.Year() {
+		return at.Format("15:04")
+	}
+
+	return scheduleDayNames[at.Weekday()] + " " + at.Format("15:04")
+}
+
+// scheduler performs the schedule's actions when their time comes
+type scheduler struct {
+	deej   *Deej
+	logger *zap.SugaredLogger
+
+	// times up to this one were taken care of
+	lastCheck time.Time
+
+	stopChannel chan bool
+}
+
+func newScheduler(deej *Deej, logger *zap.SugaredLogger) *scheduler {
+	logger = logger.Named("schedule")
+
+	s := &scheduler{
+		deej:        deej,
+		logger:      logger,
+		stopChannel: make(chan bool),
+	}
+
+	logger.Debug("Created scheduler instance")
+
+	return s
+}
+
+func (s *scheduler) initialize() {
+
+	// whatever the schedule says should be in effect now, e.g. quiet hours that started before deej did. see
+	// catchUpActions for what gets caught up
+	s.lastCheck = time.Now().Add(-scheduleCatchUpWindow)
+	s.check(time.Now())
+
+	configReloadedChannel := s.deej.config.SubscribeToChanges()
+	go func() {
+		for range configReloadedChannel {
+			s.deej.showNextScheduled()
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(scheduleCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.check(time.Now())
+			case <-s.stopChannel:
+				return
+			}
+		}
+	}()
+}
+
+func (s *scheduler) release() {
+	close(s.stopChannel)
+}
+
+// check performs the actions of every entry whose time came up since the last check, in the order they came up.
+// an entry that came up more than once only counts once. entries that were missed are caught up first
+func (s *scheduler) check(now time.Time) {
+	type dueEntry struct {
+		at    time.Time
+		entry scheduleEntry
+	}
+
+	due := []dueEntry{}
+	for _, entry := range s.deej.config.ScheduleEntries() {
+		if at := entry.lastOccurrence(now); at.After(s.lastCheck) {
+			due = append(due, dueEntry{at, entry})
+		}
+	}
+
+	s.lastCheck = now
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+
+	missed := []string{}
+	for len(due) > 0 && now.Sub(due[0].at) > scheduleMissedAfter {
+		missed = append(missed, due[0].entry.actions...)
+		due = due[1:]
+	}
+
+	if len(missed) > 0 {
+		actions := catchUpActions(missed)
+		s.logger.Infow("Catching up on missed scheduled actions", "missed", missed, "actions", actions)
+
+		s.perform(actions)
+	}
+
+	for _, dueEntry := range due {
+		s.logger.Infow("Performing scheduled actions", "time", dueEntry.entry.spec, "actions", dueEntry.entry.actions,
+			"due", dueEntry.at)
+
+		s.perform(dueEntry.entry.actions)
+	}
+
+	s.deej.showNextScheduled()
+}
+
+func (s *scheduler) perform(actions []string) {
+	for _, action := range actions {
+		if err := s.deej.performAction(action, noSliderID); err != nil {
+			s.logger.Warnw("Failed to perform scheduled action", "action", action, "error", err)
+		}
+	}
+}
+
+// catchUpActions picks, out of missed actions in the order they came up, the ones that put things where the
+// schedule has them now: the last profile switch, and the last cap on each target since the last uncap. toggles
+// (mute, next_profile, duck) are never caught up, since doing them late flips things rather than setting them
+func catchUpActions(missed []string) []string {
+	profile := ""
+	caps := []string{}
+
+	for _, action := range missed {
+		switch {
+		case strings.HasPrefix(action, actionProfilePrefix):
+			profile = action
+
+		case action == actionUncap:
+			caps = []string{actionUncap}
+
+		case strings.HasPrefix(action, actionCapPrefix):
+			key, _, _ := parseCapAction(action)
+
+			kept := caps[:0]
+			for _, capAction := range caps {
+				if capKey, _, _ := parseCapAction(capAction); capAction == actionUncap || capKey != key {
+					kept = append(kept, capAction)
+				}
+			}
+
+			caps = append(kept, action)
+		}
+	}
+
+	actions := []string{}
+	if profile != "" {
+		actions = append(actions, profile)
+	}
+
+	return append(actions, caps...)
+}
+
+// next returns when the schedule comes up next and the actions it performs then, or a zero time if it's empty
+func (s *scheduler) next(now time.Time) (time.Time, []string) {
+	var at time.Time
+	actions := []string{}
+
+	for _, entry := range s.deej.config.ScheduleEntries() {
+		entryAt := entry.nextOccurrence(now)
+
+		switch {
+		case entryAt.IsZero():
+		case at.IsZero() || entryAt.Before(at):
+			at, actions = entryAt, append([]string{}, entry.actions...)
+		case entryAt.Equal(at):
+			actions = append(actions, entry.actions...)
+		}
+	}
+
+	return at, actions
+}
@@ -0,0 +1,99 @@
+package deej
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseScheduleTime(t *testing.T) {
+	valid := map[string][7]bool{
+		"22:00":          {true, true, true, true, true, true, true},
+		"sat 10:00":      {false, false, false, false, false, false, true},
+		"Mon-Fri 07:30":  {false, true, true, true, true, true, false},
+		"fri-mon 7:30":   {true, true, false, false, false, true, true},
+		"sat,sun  09:00": {true, false, false, false, false, false, true},
+	}
+
+	for spec, days := range valid {
+		entry, err := parseScheduleTime(spec)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", spec, err)
+			continue
+		}
+
+		if entry.days != days {
+			t.Errorf("%q: expected days %v, got %v", spec, days, entry.days)
+		}
+	}
+
+	for _, spec := range []string{"", "25:00", "22", "someday 10:00", "mon-fri", "mon 10:00 extra"} {
+		if _, err := parseScheduleTime(spec); !errors.Is(err, errInvalidScheduleTime) {
+			t.Errorf("%q: expected an invalid schedule time, got %v", spec, err)
+		}
+	}
+}
+
+func TestScheduleOccurrences(t *testing.T) {
+	entry, _ := parseScheduleTime("mon-fri 07:30")
+
+	// a saturday
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.Local)
+
+	if last := entry.lastOccurrence(now); !last.Equal(time.Date(2026, time.October, 16, 7, 30, 0, 0, time.Local)) {
+		t.Errorf("expected friday morning, got %v", last)
+	}
+
+	if next := entry.nextOccurrence(now); !next.Equal(time.Date(2026, time.October, 19, 7, 30, 0, 0, time.Local)) {
+		t.Errorf("expected monday morning, got %v", next)
+	}
+}
+
+func TestSchedulerCatchesUpInOrder(t *testing.T) {
+	td := newTestDeej(t, `
+schedule:
+  "22:00": cap:master:30
+  "07:00": uncap
+`, "master")
+
+	s := newScheduler(td.Deej, td.logger)
+	td.scheduler = s
+
+	night := time.Date(2026, time.October, 15, 23, 0, 0, 0, time.Local)
+	s.lastCheck = night.Add(-scheduleCatchUpWindow)
+	s.check(night)
+
+	if volume := td.Deej.sessions.capVolume("master", 1); !volumesEqual(volume, 0.3) {
+		t.Errorf("expected master capped at night, got %.2f", volume)
+	}
+
+	at, actions := s.next(night)
+	if !at.Equal(time.Date(2026, time.October, 16, 7, 0, 0, 0, time.Local)) || len(actions) != 1 || actions[0] != actionUncap {
+		t.Errorf("expected uncap next morning, got %v at %v", actions, at)
+	}
+
+	s.check(at)
+
+	if volume := td.Deej.sessions.capVolume("master", 1); volume != 1 {
+		t.Errorf("expected the cap lifted in the morning, got %.2f", volume)
+	}
+}
+
+func TestCatchUpActions(t *testing.T) {
+	actions := catchUpActions([]string{
+		"cap:master:30", "mute", "profile:work", "uncap", "cap:discord.exe:80", "mute:1", "profile:",
+		"cap:master:50", "next_profile", "duck:spotify.exe", "cap:discord.exe:60",
+	})
+
+	expected := []string{"profile:", "uncap", "cap:master:50", "cap:discord.exe:60"}
+	if len(actions) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, actions)
+	}
+
+	for idx := range expected {
+		if actions[idx] != expected[idx] {
+			t.Errorf("expected %v, got %v", expected, actions)
+			break
+		}
+	}
+}
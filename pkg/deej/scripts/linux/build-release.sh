@@ -11,7 +11,10 @@ echo "- gitCommit $GIT_COMMIT"
 echo "- versionTag $VERSION_TAG"
 echo "- buildType $BUILD_TYPE"
 
-go build -o deej-release -ldflags "-s -w -X main.gitCommit=$GIT_COMMIT -X main.versionTag=$VERSION_TAG -X main.buildType=$BUILD_TYPE" ./pkg/deej/cmd
+# the public half of the key release assets are signed with, so deej can install updates (optional)
+UPDATE_PUBLIC_KEY=$DEEJ_UPDATE_PUBLIC_KEY
+
+go build -o deej-release -ldflags "-s -w -X main.gitCommit=$GIT_COMMIT -X main.versionTag=$VERSION_TAG -X main.buildType=$BUILD_TYPE -X main.updatePublicKey=$UPDATE_PUBLIC_KEY" ./pkg/deej/cmd
 if [ $? -eq 0 ]; then
     echo 'Done.'
 else
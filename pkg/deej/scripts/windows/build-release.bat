@@ -14,7 +14,10 @@ ECHO - gitCommit %GIT_COMMIT%
 ECHO - versionTag %VERSION_TAG%
 ECHO - buildType %BUILD_TYPE%
 
-go build -o "%DEEJ_ROOT%\deej-release.exe" -ldflags "-H=windowsgui -s -w -X main.gitCommit=%GIT_COMMIT% -X main.versionTag=%VERSION_TAG% -X main.buildType=%BUILD_TYPE%" "%DEEJ_ROOT%\pkg\deej\cmd"
+REM the public half of the key release assets are signed with, so deej can install updates (optional)
+set UPDATE_PUBLIC_KEY=%DEEJ_UPDATE_PUBLIC_KEY%
+
+go build -o "%DEEJ_ROOT%\deej-release.exe" -ldflags "-H=windowsgui -s -w -X main.gitCommit=%GIT_COMMIT% -X main.versionTag=%VERSION_TAG% -X main.buildType=%BUILD_TYPE% -X main.updatePublicKey=%UPDATE_PUBLIC_KEY%" "%DEEJ_ROOT%\pkg\deej\cmd"
 IF %ERRORLEVEL% NEQ 0 GOTO BUILDERROR
 ECHO Done.
 GOTO DONE
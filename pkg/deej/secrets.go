@@ -0,0 +1,211 @@
+package deej
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/omriharel/deej/pkg/deej/util"
+)
+
+// integrations that log in somewhere need a token or password in the config, and config files get pasted into bug
+// reports and synced between machines. instead of the plain value, such settings can hold one encrypted with a key
+// that only lives on this machine, in the per-user state directory ($XDG_STATE_HOME/deej or ~/.local/state/deej on
+// linux, %LOCALAPPDATA%\deej on windows), which unlike the config directory isn't synced or backed up with it. get
+// one with "deej --encrypt-secret", and read it with (cc *CanonicalConfig).secret
+const (
+	secretValuePrefix = "enc:"
+
+	secretKeyFilename = "secret.key"
+	secretKeySize     = 32
+)
+
+var (
+	errNoSecretKey      = errors.New("no secret key on this machine")
+	errMalformedSecret  = errors.New("malformed encrypted value")
+	errUndecryptableKey = errors.New("encrypted with a different key")
+)
+
+// secretKeyFilepath returns where the secret key is kept: the per-user state directory, or the logs directory
+// where there's none
+func secretKeyFilepath() string {
+	dir, err := userStateDir()
+	if err != nil {
+		return filepath.Join(internalConfigPath, secretKeyFilename)
+	}
+
+	return filepath.Join(dir, secretKeyFilename)
+}
+
+// userStateDir returns deej's per-user state directory
+func userStateDir() (string, error) {
+	if !util.Linux() {
+		localAppData := os.Getenv("LOCALAPPDATA")
+		if localAppData == "" {
+			return "", errors.New("%LOCALAPPDATA% is not set")
+		}
+
+		return filepath.Join(localAppData, userConfigDirName), nil
+	}
+
+	if stateHome := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(stateHome) {
+		return filepath.Join(stateHome, userConfigDirName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("find home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".local", "state", userConfigDirName), nil
+}
+
+// loadMachineSecretKey reads this machine's secret key (see loadSecretKey). keys made before it moved to the state
+// directory are moved there first, or the values encrypted with them couldn't be read anymore
+func loadMachineSecretKey(create bool) ([]byte, error) {
+	path := secretKeyFilepath()
+
+	if perUser, err := perUserConfigFilepath(); err == nil {
+		legacyPath := filepath.Join(filepath.Dir(perUser), secretKeyFilename)
+
+		if legacyPath != path && util.FileExists(legacyPath) && !util.FileExists(path) {
+			if err := util.EnsureDirExists(filepath.Dir(path)); err != nil {
+				return nil, fmt.Errorf("create secret key directory: %w", err)
+			}
+
+			if err := os.Rename(legacyPath, path); err != nil {
+				return nil, fmt.Errorf("move secret key out of the config directory: %w", err)
+			}
+		}
+	}
+
+	return loadSecretKey(path, create)
+}
+
+// loadSecretKey reads the secret key, creating one if asked to and there isn't one yet
+func loadSecretKey(path string, create bool) ([]byte, error) {
+	key, err := ioutil.ReadFile(path)
+	if err == nil {
+		if len(key) != secretKeySize {
+			return nil, fmt.Errorf("secret key %s is %d bytes, expected %d", path, len(key), secretKeySize)
+		}
+
+		return key, nil
+	}
+
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read secret key: %w", err)
+	}
+
+	if !create {
+		return nil, fmt.Errorf("%s: %w", path, errNoSecretKey)
+	}
+
+	key = make([]byte, secretKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("generate secret key: %w", err)
+	}
+
+	if err := util.EnsureDirExists(filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("create secret key directory: %w", err)
+	}
+
+	// only readable by the user, where the platform has a say in it
+	if err := ioutil.WriteFile(path, key, 0600); err != nil {
+		return nil, fmt.Errorf("write secret key: %w", err)
+	}
+
+	return key, nil
+}
+
+// EncryptSecret encrypts a value for the config with this machine's secret key, creating the key if needed
+func EncryptSecret(plaintext string) (string, error) {
+	key, err := loadMachineSecretKey(true)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptSecret(key, plaintext)
+}
+
+func encryptSecret(key []byte, plaintext string) (string, error) {
+	aead, err := newSecretCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return secretValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSecret(key []byte, value string) (string, error) {
+	aead, err := newSecretCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, secretValuePrefix))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errMalformedSecret
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errUndecryptableKey
+	}
+
+	return string(plaintext), nil
+}
+
+func newSecretCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// secret reads a config value that may be encrypted. plain values still work, but get a warning, since the point is
+// to not have them in config.yaml
+func (cc *CanonicalConfig) secret(configKey string) (string, error) {
+	value := cc.userConfig.GetString(configKey)
+	if value == "" {
+		return "", nil
+	}
+
+	if !strings.HasPrefix(value, secretValuePrefix) {
+		cc.logger.Warnw("Config value is stored as plain text, consider encrypting it with --encrypt-secret",
+			"key", configKey)
+
+		return value, nil
+	}
+
+	key, err := loadMachineSecretKey(false)
+	if err != nil {
+		cc.logger.Warnw("Can't decrypt config value", "key", configKey, "error", err)
+		return "", fmt.Errorf("decrypt %s: %w", configKey, err)
+	}
+
+	plaintext, err := decryptSecret(key, value)
+	if err != nil {
+		cc.logger.Warnw("Can't decrypt config value", "key", configKey, "error", err)
+		return "", fmt.Errorf("decrypt %s: %w", configKey, err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,56 @@
+package deej
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSecretRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deej-secrets")
+	if err != nil {
+		t.Fatalf("create key directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyPath := filepath.Join(dir, "deej", secretKeyFilename)
+
+	if _, err := loadSecretKey(keyPath, false); !errors.Is(err, errNoSecretKey) {
+		t.Errorf("expected no key without creating one, got %v", err)
+	}
+
+	key, err := loadSecretKey(keyPath, true)
+	if err != nil {
+		t.Fatalf("create key: %v", err)
+	}
+
+	encrypted, err := encryptSecret(key, "hunter2")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	if plaintext, err := decryptSecret(key, encrypted); err != nil || plaintext != "hunter2" {
+		t.Errorf("expected the value back, got %q (%v)", plaintext, err)
+	}
+
+	otherKey := make([]byte, secretKeySize)
+	if _, err := decryptSecret(otherKey, encrypted); !errors.Is(err, errUndecryptableKey) {
+		t.Errorf("expected another key to fail, got %v", err)
+	}
+
+	if _, err := decryptSecret(key, secretValuePrefix+"not base64!"); !errors.Is(err, errMalformedSecret) {
+		t.Errorf("expected a malformed value to fail, got %v", err)
+	}
+
+	// plain values pass through as they are
+	cc := &CanonicalConfig{logger: zap.NewNop().Sugar(), userConfig: newUserConfigViper()}
+	cc.userConfig.Set("token", "plain")
+
+	if value, err := cc.secret("token"); err != nil || value != "plain" {
+		t.Errorf("expected the plain value, got %q (%v)", value, err)
+	}
+}
@@ -2,22 +2,21 @@ package deej
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
-	"regexp"
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/jacobsa/go-serial/serial"
+	"github.com/thoas/go-funk"
 	"go.uber.org/zap"
 
-	"github.com/gen2brain/beeep"
 	"github.com/omriharel/deej/pkg/deej/util"
 )
 
@@ -26,28 +25,129 @@ type SerialIO struct {
 	deej   *Deej
 	logger *zap.SugaredLogger
 
-	stopChannel  chan bool
-	connected    bool
-	reconnecting bool
-	connOptions  serial.OpenOptions
-	conn         io.ReadWriteCloser
+	connected   bool
+	connOptions serial.OpenOptions
+	conn        io.ReadWriteCloser
+	connInfo    ConnectionInfo
+	connLock    sync.Mutex
+	startLock   sync.Mutex
+
+	// set (atomically) once any device has connected, see Deej.waitingPassively
+	deviceSeen int32
+
+	// set (atomically) while RequestSliderCount is asking the board for its sliders
+	sliderCountRequested int32
+
+	// cancels the current connection's reader. connDone is closed once the reader
+	// and every line handler it started have finished
+	cancelConn context.CancelFunc
+	connDone   chan struct{}
+
+	hotplug          hotplugWatcher
+	stopWatchChannel chan bool
+
+	// what the connected firmware told us about itself, nil until it does
+	capabilities *DeviceCapabilities
+	protocol     *protocolVersion
+	deviceLock   sync.Mutex
+
+	// the payload of the last config message the connected device received
+	pushedHardwareSettings string
+
+	// the report rate last asked of the connected device, see applyReportRate. zero until one is
+	requestedReportRate int
+
+	// slider -> the name the connected board keeps for it, and the name its display was last sent. see slider_names.go
+	boardSliderNames     map[int]string
+	displayedSliderNames map[int]string
+
+	// holds back slider frames from boards that report faster than the configured rate, see serial_throttle.go
+	throttle *sliderThrottle
+
+	// the last baud rate auto-detect offered to save to the config, so it doesn't ask again on every reconnect
+	suggestedBaudRate uint
+
+	checksumErrors uint64
+
+	// see connection_stability.go
+	stability *connectionStability
+
+	inspector *serialInspector
+
+	// commands waiting for the device's response, see serial_commands.go
+	commands *pendingCommands
+
+	// how far auto-detection got, see detect_progress.go
+	detection *detectionTracker
 
 	lastKnownNumSliders        int
 	currentSliderPercentValues []float32
 	sliderDataMutex            sync.Mutex
 
-	sliderMoveConsumers []chan SliderMoveEvent
+	// what the sliders last reported, and when any of them last moved. see processSliderData
+	lastSliderData      string
+	lastRawSliderValues []int
+	lastSliderMove      time.Time
+
+	// slider index -> the detent holding it, see detents.go
+	heldDetents map[int]int
+
+	// reused for every frame, to spare the allocations
+	moveEvents []SliderMoveEvent
+
+	// see serial_consumers.go
+	sliderMoveConsumers []*sliderMoveConsumer
+}
+
+// SerialStatus is a snapshot of the serial connection's state
+type SerialStatus struct {
+	Connected       bool                `json:"connected"`
+	Transport       string              `json:"transport"`
+	Port            string              `json:"port,omitempty"`
+	NumSliders      int                 `json:"numSliders"`
+	FirmwareVersion string              `json:"firmwareVersion,omitempty"`
+	Capabilities    *DeviceCapabilities `json:"capabilities,omitempty"`
+	ChecksumErrors  uint64              `json:"checksumErrors"`
+
+	// slider moves each subscriber missed, see SerialIO.DroppedSliderMoves
+	DroppedMoves map[string]uint64 `json:"droppedMoves"`
+
+	Stability ConnectionStability `json:"stability"`
+
+	// while the board isn't connected, how far auto-detection got looking for it. nil if it isn't looking
+	Detection *DetectionProgress `json:"detection,omitempty"`
 }
 
 // SliderMoveEvent represents a single slider move captured by deej
 type SliderMoveEvent struct {
 	SliderID     int
 	PercentValue float32
+
+	// true for the values a device reports right after connecting, rather than an actual slider move
+	Initial bool
+
+	// when the line the move came in was read, zero for moves that didn't come from the device
+	Received time.Time
 }
 
-var expectedLinePattern = regexp.MustCompile(`^\d{1,4}(\|\d{1,4})*\r\n$`)
+const (
+	firmwareVersion = "v2.0"
 
-const firmwareVersion = "v2.0"
+	// how long to let the OS finish setting up a freshly plugged-in device before opening it
+	hotplugSettleDelay = 500 * time.Millisecond
+
+	// only used when the platform's hotplug notifications aren't available
+	fallbackReconnectInterval = 5 * time.Second
+
+	// how long Stop waits for the reader and line handlers to finish before giving up on them
+	serialStopTimeout = 2 * time.Second
+
+	// how long the sliders have to stay put before repeated frames are skipped without being parsed
+	sliderIdleTimeout = 2 * time.Second
+
+	// button events waiting for the ones before them to be handled, per button
+	buttonEventBufferSize = 16
+)
 
 // NewSerialIO creates a SerialIO instance that uses the provided deej
 // instance's connection info to establish communications with the arduino chip
@@ -57,12 +157,22 @@ func NewSerialIO(deej *Deej, logger *zap.SugaredLogger) (*SerialIO, error) {
 	sio := &SerialIO{
 		deej:                deej,
 		logger:              logger,
-		stopChannel:         make(chan bool),
+		stopWatchChannel:    make(chan bool),
 		connected:           false,
 		conn:                nil,
-		sliderMoveConsumers: []chan SliderMoveEvent{},
+		sliderMoveConsumers: []*sliderMoveConsumer{},
+		inspector:           newSerialInspector(logger),
+		commands:            newPendingCommands(),
+		stability:           newConnectionStability(logger, deej.notifier),
+		heldDetents:         map[int]int{},
+
+		boardSliderNames:     map[int]string{},
+		displayedSliderNames: map[int]string{},
 	}
 
+	sio.throttle = newSliderThrottle(sio.processSliderData)
+	sio.detection = newDetectionTracker(deej.showDeviceInfo)
+
 	logger.Debug("Created serial i/o instance")
 
 	// respond to config changes
@@ -71,173 +181,374 @@ func NewSerialIO(deej *Deej, logger *zap.SugaredLogger) (*SerialIO, error) {
 	return sio, nil
 }
 
-// autoDetectArduinoPort scans for likely Arduino serial ports and returns the first one that sends a recognizable signature.
-func autoDetectArduinoPort(baudRate uint, logger *zap.SugaredLogger) (string, error) {
-	candidates := []string{}
-	files, err := os.ReadDir("/dev")
+// the rates deej firmware commonly runs at, tried in turn when a device doesn't answer at the configured one
+var commonBaudRates = []uint{9600, 57600, 115200}
+
+// autoDetectArduinoPort scans for likely Arduino serial ports and returns the first one that sends a recognizable signature,
+// along with the baud rate it answered at. every port is tried at the configured rate before falling back to the other
+// common ones. ports we aren't allowed to open are reported to the permissions helper and skipped, as are ports the
+// config says not to probe. each probe is reported to the progress tracker as it goes
+func autoDetectArduinoPort(
+	baudRate uint,
+	safety ProbeSafety,
+	logger *zap.SugaredLogger,
+	permissions *SerialPermissionsHelper,
+	progress *detectionTracker,
+) (string, uint, error) {
+	ports, err := listSerialPorts()
 	if err != nil {
-		return "", err
+		return "", 0, fmt.Errorf("list serial ports: %w", err)
 	}
-	for _, f := range files {
-		if strings.HasPrefix(f.Name(), "ttyUSB") || strings.HasPrefix(f.Name(), "ttyACM") {
-			candidates = append(candidates, "/dev/"+f.Name())
-		}
+
+	candidates, skipped := safety.probeablePorts(ports, logger)
+	logger.Debugw("Auto-detecting Arduino port", "candidates", candidates, "skipped", skipped)
+
+	progress.start()
+	for _, port := range skipped {
+		progress.result(port, 0, probeOutcomeSkipped)
 	}
-	logger.Debugw("Auto-detecting Arduino port", "candidates", candidates)
-	for _, port := range candidates {
-		opts := serial.OpenOptions{
-			PortName:        port,
-			BaudRate:        baudRate,
-			DataBits:        8,
-			StopBits:        1,
-			MinimumReadSize: 1,
+
+	baudRates := []uint{baudRate}
+	for _, rate := range commonBaudRates {
+		if rate != baudRate {
+			baudRates = append(baudRates, rate)
 		}
-		f, err := serial.Open(opts)
-		if err != nil {
-			if strings.Contains(err.Error(), "permission denied") {
-				// Try to get the group owner of the device
-				if fi, statErr := os.Stat(port); statErr == nil {
-					if stat, ok := fi.Sys().(*syscall.Stat_t); ok {
-						gid := stat.Gid
-						groupNames := []string{}
-						if groupFile, gerr := os.Open("/etc/group"); gerr == nil {
-							scanner := bufio.NewScanner(groupFile)
-							for scanner.Scan() {
-								line := scanner.Text()
-								parts := strings.Split(line, ":")
-								if len(parts) >= 3 && parts[2] == fmt.Sprint(gid) {
-									groupNames = append(groupNames, parts[0])
-								}
-							}
-							groupFile.Close()
-						}
-						groupNameStr := fmt.Sprintf("GID %d (unknown group)", gid)
-						if len(groupNames) > 0 {
-							groupNameStr = strings.Join(groupNames, " or ")
-						}
-						logger.Debugw("Detected group(s) for serial device", "port", port, "gid", gid, "groupNames", groupNameStr)
+	}
 
-						user := os.Getenv("USER")
-						if user == "" {
-							user = os.Getenv("USERNAME") // Windows fallback
-						}
-						// Check if user is already in the group
-						checkCmd := exec.Command("id", "-nG", user)
-						output, err := checkCmd.Output()
-						alreadyInGroup := false
-						for _, g := range groupNames {
-							if err == nil && strings.Contains(string(output), g) {
-								alreadyInGroup = true
-								break
-							}
-						}
-						if alreadyInGroup {
-							beeep.Alert("Already a Member", fmt.Sprintf("You are already a member of the '%s' group.\n\nPlease log out and log back in if you still have issues.", groupNameStr), "")
-							continue
-						}
-						// Ask for confirmation using zenity
-						confirm := exec.Command("zenity", "--question", "--text",
-							fmt.Sprintf("Permission denied opening %s.\n\nWould you like to add yourself to the '%s' group?\n\nYou will be prompted for your password.", port, groupNameStr))
-						err = confirm.Run()
-						if err == nil && len(groupNames) > 0 { // User clicked Yes
-							cmd := exec.Command("pkexec", "usermod", "-aG", groupNames[0], user)
-							if err := cmd.Run(); err == nil {
-								beeep.Alert("Action Required", "You have been added to the group.\n\nPlease log out and log back in, then rerun this program to continue.", "")
-							} else {
-								beeep.Alert("Error", "Failed to add you to the group.\n\nPlease run this command manually:\nsudo usermod -aG "+groupNames[0]+" "+user, "")
-							}
-						} else {
-							beeep.Alert("Action Cancelled", "No changes were made.", "")
-						}
-					}
-				}
-			}
-			logger.Debugw("Failed to open candidate port", "port", port, "error", err)
-			continue // skip if can't open (e.g., permission denied)
-		}
-		// Give Arduino time to reset and respond
-		time.Sleep(1 * time.Second)
-
-		// Try to read multiple times in case the Arduino is slow to respond
-		for attempt := 1; attempt <= 3; attempt++ {
-			logger.Debugw("Attempting to read from port", "port", port, "attempt", attempt)
-
-			// Send a command to request slider data to trigger a response
-			if attempt == 1 {
-				logger.Debugw("Sending slider request command to trigger response", "port", port)
-				sliderCommand := fmt.Sprintf("deej:%s:command:sliders\n", firmwareVersion)
-				_, writeErr := f.Write([]byte(sliderCommand))
-				if writeErr != nil {
-					logger.Debugw("Failed to send slider request command", "port", port, "error", writeErr)
+	for _, rate := range baudRates {
+		openable := []string{}
+
+		for _, port := range candidates {
+			progress.probing(port, rate)
+
+			found, err := probeArduinoPort(port, rate, logger)
+			if err != nil {
+
+				// leave resolving permission problems to the helper, we're only here to find the device
+				if errors.Is(err, os.ErrPermission) {
+					permissions.reportDenied(port)
+					progress.result(port, rate, probeOutcomeDenied)
 				} else {
-					logger.Debugw("Slider request command sent successfully", "port", port)
-					// Give Arduino time to respond
-					time.Sleep(200 * time.Millisecond)
+					progress.result(port, rate, probeOutcomeUnavailable)
 				}
+
+				continue // skip if can't open (e.g., permission denied)
 			}
 
-			buf := make([]byte, 256)
-			n, err := f.Read(buf)
-			if err != nil {
-				logger.Debugw("Read attempt failed", "port", port, "attempt", attempt, "error", err)
-				time.Sleep(500 * time.Millisecond)
-				continue
+			if found {
+				progress.result(port, rate, probeOutcomeFound)
+				progress.finish(true)
+
+				return port, rate, nil
 			}
 
-			logger.Debugw("Read data from port", "port", port, "attempt", attempt, "bytesRead", n)
-			if n > 0 {
-				response := string(buf[:n])
-				logger.Debugw("Read response from port", "port", port, "attempt", attempt, "response", response)
-
-				// Check for any deej message (robust detection)
-				lines := strings.Split(response, "\r\n")
-				for _, line := range lines {
-					line = strings.TrimSpace(line)
-					if line == "" {
-						continue
-					}
-					logger.Debugw("Checking line for deej message", "port", port, "line", line)
-					if strings.HasPrefix(line, "deej:") {
-						logger.Infow("Detected Arduino device", "port", port, "response_type", "deej_message", "sample_line", line)
-
-						// Send reboot command to ensure Arduino goes through full startup sequence
-						logger.Infow("Sending reboot command to Arduino to ensure proper startup sequence", "port", port)
-						rebootCommand := fmt.Sprintf("deej:%s:command:reboot\n", firmwareVersion)
-						_, writeErr := f.Write([]byte(rebootCommand))
-						if writeErr != nil {
-							logger.Warnw("Failed to send reboot command", "port", port, "error", writeErr)
-						} else {
-							logger.Infow("Reboot command sent successfully", "port", port)
-							// Give Arduino time to process reboot command
-							time.Sleep(200 * time.Millisecond)
+			logger.Debugw("No deej device found on port", "port", port, "baudRate", rate)
+			progress.result(port, rate, probeOutcomeNoAnswer)
+			openable = append(openable, port)
+		}
+
+		// no need to keep trying ports we can't open at all
+		candidates = openable
+	}
+
+	progress.finish(false)
+
+	return "", 0, fmt.Errorf("no Arduino device found")
+}
+
+// probeArduinoPort checks whether a deej device answers on the given port at the given baud rate.
+// it only returns an error when the port can't be opened
+func probeArduinoPort(port string, baudRate uint, logger *zap.SugaredLogger) (bool, error) {
+	opts := serial.OpenOptions{
+		PortName:        port,
+		BaudRate:        baudRate,
+		DataBits:        8,
+		StopBits:        1,
+		MinimumReadSize: 1,
+	}
+	f, err := serial.Open(opts)
+	if err != nil {
+		logger.Debugw("Failed to open candidate port", "port", port, "error", err)
+		return false, err
+	}
+	defer f.Close()
+
+	// Give Arduino time to reset and respond
+	time.Sleep(1 * time.Second)
+
+	// Try to read multiple times in case the Arduino is slow to respond
+	for attempt := 1; attempt <= 3; attempt++ {
+		logger.Debugw("Attempting to read from port", "port", port, "attempt", attempt)
+
+		// Send a command to request slider data to trigger a response
+		if attempt == 1 {
+			logger.Debugw("Sending slider request command to trigger response", "port", port)
+			sliderCommand := fmt.Sprintf("deej:%s:command:sliders\n", firmwareVersion)
+			_, writeErr := f.Write([]byte(sliderCommand))
+			if writeErr != nil {
+				logger.Debugw("Failed to send slider request command", "port", port, "error", writeErr)
+			} else {
+				logger.Debugw("Slider request command sent successfully", "port", port)
+				// Give Arduino time to respond
+				time.Sleep(200 * time.Millisecond)
+			}
+		}
+
+		buf := make([]byte, 256)
+		n, err := f.Read(buf)
+		if err != nil {
+			logger.Debugw("Read attempt failed", "port", port, "attempt", attempt, "error", err)
+			time.Sleep(500 * time.Millisecond)
+			continue
+		}
+
+		logger.Debugw("Read data from port", "port", port, "attempt", attempt, "bytesRead", n)
+		if n > 0 {
+			response := string(buf[:n])
+			logger.Debugw("Read response from port", "port", port, "attempt", attempt, "response", response)
+
+			// Check for any deej message (robust detection)
+			lines := strings.Split(response, "\r\n")
+			for _, line := range lines {
+				line = strings.TrimSpace(line)
+				if line == "" {
+					continue
+				}
+				logger.Debugw("Checking line for deej message", "port", port, "line", line)
+				if strings.HasPrefix(line, "deej:") {
+					logger.Infow("Detected Arduino device", "port", port, "response_type", "deej_message", "sample_line", line)
+
+					// Send reboot command to ensure Arduino goes through full startup sequence,
+					// in whichever format the version it just reported expects
+					logger.Infow("Sending reboot command to Arduino to ensure proper startup sequence", "port", port)
+
+					probeVersion, _ := parseProtocolVersion(firmwareVersion)
+					if fields := strings.Split(line, ":"); len(fields) >= 2 {
+						if reported, err := parseProtocolVersion(fields[1]); err == nil {
+							probeVersion = reported
 						}
+					}
 
-						f.Close()
-						return port, nil
+					rebootCommand, formatErr := probeVersion.formatCommand("reboot")
+					if formatErr != nil {
+						logger.Warnw("Detected device speaks an incompatible protocol", "port", port, "version", probeVersion)
+						return true, nil
 					}
+
+					_, writeErr := f.Write([]byte(rebootCommand))
+					if writeErr != nil {
+						logger.Warnw("Failed to send reboot command", "port", port, "error", writeErr)
+					} else {
+						logger.Infow("Reboot command sent successfully", "port", port)
+						// Give Arduino time to process reboot command
+						time.Sleep(200 * time.Millisecond)
+					}
+
+					return true, nil
 				}
-			} else {
-				logger.Debugw("No data read from port", "port", port, "attempt", attempt)
 			}
+		} else {
+			logger.Debugw("No data read from port", "port", port, "attempt", attempt)
+		}
 
-			// Wait before next attempt
-			time.Sleep(500 * time.Millisecond)
+		// Wait before next attempt
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return false, nil
+}
+
+// SerialPortInfo describes a serial port, and whether a deej answered on it
+type SerialPortInfo struct {
+	Name      string `json:"name"`
+	Deej      bool   `json:"deej"`
+	Connected bool   `json:"connected"`
+
+	// the config says not to probe it, so whether there's a deej behind it isn't known
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// ProbePorts lists the serial ports around and checks each for a deej. the port deej is connected to isn't probed,
+// since it's already known to be one. this takes a few seconds per port, during which deej doesn't connect
+func (sio *SerialIO) ProbePorts() ([]SerialPortInfo, error) {
+	// opening a port deej is connecting to (or auto-detecting on) would take it from under the connection
+	sio.startLock.Lock()
+	defer sio.startLock.Unlock()
+
+	sio.connLock.Lock()
+	connected := sio.connected
+	connectedPort := sio.connOptions.PortName
+	sio.connLock.Unlock()
+
+	ports, err := listSerialPorts()
+	if err != nil {
+		sio.logger.Warnw("Failed to list serial ports", "error", err)
+		return nil, fmt.Errorf("list serial ports: %w", err)
+	}
+
+	result := []SerialPortInfo{}
+	baudRate := uint(sio.deej.config.ConnectionInfo.BaudRate)
+	_, skipped := sio.deej.config.ProbeSafety.probeablePorts(ports, sio.logger)
+
+	for _, port := range ports {
+		if connected && samePort(port, connectedPort) {
+			result = append(result, SerialPortInfo{Name: port, Deej: true, Connected: true})
+			continue
+		}
+
+		// still listed, so it can be picked by hand
+		if funk.ContainsString(skipped, port) {
+			result = append(result, SerialPortInfo{Name: port, Skipped: true})
+			continue
 		}
 
-		logger.Debugw("No deej device found on port", "port", port)
-		f.Close()
+		found, err := probeArduinoPort(port, baudRate, sio.logger)
+		if err != nil {
+			sio.logger.Debugw("Couldn't probe serial port", "port", port, "error", err)
+		}
+
+		result = append(result, SerialPortInfo{Name: port, Deej: found})
 	}
-	return "", fmt.Errorf("no Arduino device found")
+
+	return result, nil
 }
 
 // Start attempts to connect to our arduino chip
 func (sio *SerialIO) Start() error {
+	// hotplug events and the initial connection attempt can race each other, so only let one through at a time
+	sio.startLock.Lock()
+	defer sio.startLock.Unlock()
+
 	// don't allow multiple concurrent connections
 	if sio.connected {
 		sio.logger.Warn("Already connected, can't start another without closing first")
 		return errors.New("serial: connection already active")
 	}
 
+	var err error
+	target := sio.deej.config.ConnectionInfo.COMPort
+	if sio.deej.config.ConnectionInfo.Type == connectionTypeHID {
+		target = connectionTypeHID
+		err = sio.openHID()
+	} else {
+		err = sio.openSerial()
+	}
+
+	if err != nil {
+		sio.deej.timeline.recordConnection(timelineKindConnectFailed, target, err.Error())
+		return err
+	}
+
+	// remember what we connected with, so config reloads can tell whether it changed
+	sio.connInfo = sio.deej.config.ConnectionInfo
+
+	namedLogger := sio.logger.Named(strings.ToLower(sio.connOptions.PortName))
+
+	namedLogger.Infow("Connected", "conn", sio.conn)
+	sio.connLock.Lock()
+	sio.connected = true
+	sio.connLock.Unlock()
+	atomic.StoreInt32(&sio.deviceSeen, 1)
+	sio.deej.timeline.recordConnection(timelineKindConnected, sio.connOptions.PortName, "")
+
+	// until the board agrees to report at the configured rate, hold it to that rate ourselves
+	sio.throttle.setRate(sio.deej.config.Hardware.ReportRate)
+	sio.deej.showDeviceInfo()
+
+	// Set tray icon immediately on connection
+	sio.deej.SetTrayIcon(TrayNormal, DetectSystemTheme())
+
+	// Give Arduino time to reboot and send startup sequence if a reboot was triggered
+	// This ensures we receive the initial slider data
+	time.Sleep(1 * time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	conn := sio.conn
+
+	sio.connLock.Lock()
+	sio.cancelConn = cancel
+	sio.connDone = done
+	sio.connLock.Unlock()
+
+	// read lines or await a stop
+	go func() {
+		defer close(done)
+
+		connReader := bufio.NewReader(conn)
+		lineChannel := sio.readLine(ctx, namedLogger, connReader)
+
+		// Process each line asynchronously to prevent blocking the serial reading,
+		// but keep track of them so that stopping can wait for them to finish
+		handlers := sync.WaitGroup{}
+
+		// a button's presses and releases are handled in order, by a goroutine of its own
+		buttons := map[string]chan string{}
+
+		for line := range lineChannel {
+			if buttonIdx, ok := buttonEventIndex(line); ok {
+				queue, ok := buttons[buttonIdx]
+				if !ok {
+					queue = make(chan string, buttonEventBufferSize)
+					buttons[buttonIdx] = queue
+
+					handlers.Add(1)
+					go func() {
+						defer handlers.Done()
+
+						for line := range queue {
+							sio.handleLine(namedLogger, line)
+						}
+					}()
+				}
+
+				queue <- line
+				continue
+			}
+
+			handlers.Add(1)
+
+			go func(line string) {
+				defer handlers.Done()
+				sio.handleLine(namedLogger, line)
+			}(line)
+		}
+
+		for _, queue := range buttons {
+			close(queue)
+		}
+
+		sio.closeConn(namedLogger, conn)
+		handlers.Wait()
+
+		// a button held down while the board went away will never be released
+		if sio.deej.sessions != nil && sio.deej.sessions.ducking() {
+			sio.deej.sessions.restoreDucked()
+		}
+
+		// whoever stopped us on purpose takes care of reconnecting, if they want to
+		if ctx.Err() != nil {
+			namedLogger.Debug("Serial reader stopped")
+			return
+		}
+
+		// Channel closed means Arduino disconnected
+		sio.logger.Warn("Arduino disconnected")
+		sio.stability.recordDisconnect(sio.connOptions.PortName)
+
+		// an unplugged device will be picked up again by the hotplug watcher once it returns. if the read failed
+		// for some other reason, the device may still be there and no arrival event will come - so try once more
+		go func(portName string) {
+			<-time.After(hotplugSettleDelay)
+			sio.connectIfIdle(portName)
+		}(sio.connOptions.PortName)
+	}()
+
+	return nil
+}
+
+// openSerial opens the configured (or auto-detected) serial port
+func (sio *SerialIO) openSerial() error {
 	// set minimum read size according to platform (0 for windows, 1 for linux)
 	minimumReadSize := 0
 	if util.Linux() {
@@ -246,14 +557,24 @@ func (sio *SerialIO) Start() error {
 
 	comPort := sio.deej.config.ConnectionInfo.COMPort
 	baudRate := uint(sio.deej.config.ConnectionInfo.BaudRate)
-	if comPort == "" || strings.ToLower(comPort) == "auto" {
-		port, err := autoDetectArduinoPort(baudRate, sio.logger)
+	if comPort == "" || strings.ToLower(comPort) == comPortAuto {
+		port, detectedBaudRate, err := autoDetectArduinoPort(baudRate, sio.deej.config.ProbeSafety, sio.logger, sio.deej.permissions, sio.detection)
 		if err != nil {
 			sio.logger.Warnw("Could not auto-detect Arduino port", "error", err)
 			sio.deej.SetTrayIcon(TrayError, DetectSystemTheme())
-			return fmt.Errorf("auto-detect Arduino port: %w", err)
+			return withErrorCode(errorCodeDeviceNotFound, "", fmt.Errorf("auto-detect Arduino port: %w", err))
 		}
 		comPort = port
+
+		// a mismatched baud rate only produces garbage, so talk at whatever rate the device actually answered at
+		if detectedBaudRate != baudRate {
+			sio.logger.Warnw("Device answered at a different baud rate than configured",
+				"configured", baudRate,
+				"detected", detectedBaudRate)
+
+			baudRate = detectedBaudRate
+			go sio.suggestBaudRate(baudRate)
+		}
 	}
 
 	sio.connOptions = serial.OpenOptions{
@@ -269,81 +590,185 @@ func (sio *SerialIO) Start() error {
 		"baudRate", sio.connOptions.BaudRate,
 		"minReadSize", minimumReadSize)
 
-	var err error
-	sio.conn, err = serial.Open(sio.connOptions)
+	conn, err := serial.Open(sio.connOptions)
 	if err != nil {
 		// might need a user notification here, TBD
 		sio.logger.Warnw("Failed to open serial connection", "error", err)
-		return fmt.Errorf("open serial connection: %w", err)
+
+		if errors.Is(err, os.ErrPermission) && util.Linux() {
+			sio.deej.permissions.reportDenied(comPort)
+		}
+
+		err = fmt.Errorf("open serial connection: %w", err)
+
+		// a port we can't open is usually one something else already has open
+		switch {
+		case errors.Is(err, os.ErrPermission):
+			return withErrorCode(errorCodePortBusy, comPort, err)
+		case errors.Is(err, os.ErrNotExist):
+			return withErrorCode(errorCodePortNotFound, comPort, err)
+		}
+
+		return err
 	}
 
-	namedLogger := sio.logger.Named(strings.ToLower(sio.connOptions.PortName))
+	sio.conn = conn
 
-	namedLogger.Infow("Connected", "conn", sio.conn)
-	sio.connected = true
-	sio.reconnecting = false // Reset reconnecting flag on successful connection
+	// whatever an earlier auto-detection ran into doesn't matter anymore
+	sio.detection.finish(true)
 
-	// Set tray icon immediately on connection
-	sio.deej.SetTrayIcon(TrayNormal, DetectSystemTheme())
+	return nil
+}
 
-	// Give Arduino time to reboot and send startup sequence if a reboot was triggered
-	// This ensures we receive the initial slider data
-	time.Sleep(1 * time.Second)
+// suggestBaudRate lets the user know their device runs at a different baud rate than configured, and offers to save
+// the right one to their config. it only asks once per rate, and only notifies if the dialog backend can't ask
+func (sio *SerialIO) suggestBaudRate(baudRate uint) {
+	sio.deviceLock.Lock()
+	alreadySuggested := sio.suggestedBaudRate == baudRate
+	sio.suggestedBaudRate = baudRate
+	sio.deviceLock.Unlock()
 
-	// read lines or await a stop
-	go func() {
-		connReader := bufio.NewReader(sio.conn)
-		lineChannel := sio.readLine(namedLogger, connReader)
+	if alreadySuggested {
+		return
+	}
 
-		for line := range lineChannel {
-			// Process each line asynchronously to prevent blocking the serial reading
-			go sio.handleLine(namedLogger, line)
-		}
+	configured := sio.deej.config.ConnectionInfo.BaudRate
+	title := tr("notify.baud_rate_mismatch.title")
+	dialog := newDialogBackend(sio.deej.config.PermissionDialog, sio.deej.notifier, sio.logger)
 
-		// Channel closed means Arduino disconnected
-		sio.logger.Warn("Arduino disconnected")
-		sio.close(namedLogger)
-
-		// Start reconnection attempts if not already reconnecting
-		if !sio.reconnecting {
-			sio.reconnecting = true
-			go func() {
-				sio.logger.Info("Starting reconnection attempts...")
-				for {
-					time.Sleep(5 * time.Second) // Wait before retry
-					if err := sio.Start(); err == nil {
-						sio.logger.Info("Successfully reconnected to Arduino")
-						sio.deej.SetTrayIcon(TrayNormal, DetectSystemTheme())
-						sio.reconnecting = false
-						break
-					} else {
-						sio.logger.Warnw("Reconnection attempt failed", "error", err)
-					}
-				}
-			}()
+	confirmed, err := dialog.Confirm(title,
+		tr("notify.baud_rate_mismatch.message", baudRate, configKeyBaudRate, configured, baudRate))
+
+	if errors.Is(err, errDialogNotInteractive) {
+		dialog.Inform(title, tr("notify.baud_rate_mismatch.inform", baudRate, configKeyBaudRate, baudRate))
+		return
+	}
+
+	if err != nil {
+		sio.logger.Warnw("Failed to ask about baud rate mismatch", "error", err)
+		return
+	}
+
+	if !confirmed {
+		sio.logger.Infow("User declined to save detected baud rate", "baudRate", baudRate)
+		return
+	}
+
+	// we're already connected at this rate, so saving it shouldn't renew the connection
+	sio.startLock.Lock()
+	sio.connInfo.BaudRate = int(baudRate)
+	sio.startLock.Unlock()
+
+	if err := sio.deej.config.SetBaudRate(int(baudRate)); err != nil {
+		sio.logger.Warnw("Failed to save detected baud rate", "baudRate", baudRate, "error", err)
+		return
+	}
+
+	sio.logger.Infow("Saved detected baud rate to config", "baudRate", baudRate)
+}
+
+// openHID opens the first HID device matching the configured vendor and product IDs
+func (sio *SerialIO) openHID() error {
+	vendorID := sio.deej.config.ConnectionInfo.HIDVendorID
+	productID := sio.deej.config.ConnectionInfo.HIDProductID
+
+	sio.logger.Debugw("Attempting HID connection",
+		"vendorID", fmt.Sprintf("%04x", vendorID),
+		"productID", fmt.Sprintf("%04x", productID))
+
+	conn, devicePath, err := openHIDDevice(vendorID, productID)
+	if err != nil {
+		sio.logger.Warnw("Failed to open HID connection", "error", err)
+
+		// hidraw nodes belong to root unless a udev rule says otherwise, so there's no group to join
+		if errors.Is(err, os.ErrPermission) && util.Linux() {
+			sio.logger.Warnw("Add a udev rule granting access to the board's hidraw device",
+				"device", devicePath,
+				"rule", fmt.Sprintf(`KERNEL=="hidraw*", ATTRS{idVendor}=="%04x", ATTRS{idProduct}=="%04x", MODE="0660", TAG+="uaccess"`, vendorID, productID))
 		}
-	}()
+
+		return fmt.Errorf("open HID connection: %w", err)
+	}
+
+	sio.conn = conn
+	sio.connOptions = serial.OpenOptions{PortName: devicePath}
 
 	return nil
 }
 
-// Stop signals us to shut down our serial connection, if one is active
+// Stop shuts down our serial connection, if one is active, and waits for its reader and line handlers to finish
 func (sio *SerialIO) Stop() {
-	if sio.connected {
-		sio.logger.Debug("Shutting down serial connection")
-		sio.stopChannel <- true
-	} else {
+	sio.connLock.Lock()
+	cancel := sio.cancelConn
+	done := sio.connDone
+	conn := sio.conn
+	sio.connLock.Unlock()
+
+	if cancel == nil || conn == nil {
 		sio.logger.Debug("Not currently connected, nothing to stop")
+		return
+	}
+
+	sio.logger.Debug("Shutting down serial connection")
+
+	// closing the port is what actually gets the reader out of its blocking read
+	cancel()
+	sio.closeConn(sio.logger, conn)
+
+	select {
+	case <-done:
+		sio.logger.Debug("Serial connection shut down")
+	case <-time.After(serialStopTimeout):
+		sio.logger.Warnw("Timed out waiting for serial reader to finish", "timeout", serialStopTimeout)
 	}
 }
 
-// SubscribeToSliderMoveEvents returns a buffered channel that receives
-// a sliderMoveEvent struct every time a slider moves
-func (sio *SerialIO) SubscribeToSliderMoveEvents() chan SliderMoveEvent {
-	ch := make(chan SliderMoveEvent, 100) // Buffer up to 100 events to prevent blocking
-	sio.sliderMoveConsumers = append(sio.sliderMoveConsumers, ch)
+// WatchForDevices connects to the arduino as soon as it's plugged in and tears the connection
+// down when it's unplugged. It blocks until StopWatchingForDevices is called
+func (sio *SerialIO) WatchForDevices() {
 
-	return ch
+	// windows only tells us about COM ports coming and going
+	if sio.deej.config.ConnectionInfo.Type == connectionTypeHID && !util.Linux() {
+		sio.logger.Debug("No hotplug notifications for HID boards on this platform, falling back to periodic reconnection attempts")
+		sio.pollForDevice()
+
+		return
+	}
+
+	watcher, err := newHotplugWatcher(sio.logger)
+	if err != nil {
+		sio.logger.Warnw("Hotplug detection unavailable, falling back to periodic reconnection attempts", "error", err)
+		sio.pollForDevice()
+
+		return
+	}
+
+	sio.hotplug = watcher
+	sio.logger.Debug("Watching for serial device hotplug events")
+
+	for {
+		select {
+		case <-sio.stopWatchChannel:
+			if err := watcher.Close(); err != nil {
+				sio.logger.Warnw("Failed to close hotplug watcher", "error", err)
+			}
+
+			return
+
+		case event, ok := <-watcher.Events():
+			if !ok {
+				sio.logger.Debug("Hotplug watcher closed")
+				return
+			}
+
+			sio.handleHotplugEvent(event)
+		}
+	}
+}
+
+// StopWatchingForDevices stops reacting to serial devices being plugged in or removed
+func (sio *SerialIO) StopWatchingForDevices() {
+	close(sio.stopWatchChannel)
 }
 
 func (sio *SerialIO) setupOnConfigReload() {
@@ -352,27 +777,31 @@ func (sio *SerialIO) setupOnConfigReload() {
 	const stopDelay = 50 * time.Millisecond
 
 	go func() {
-		for range configReloadedChannel {
-			// make any config reload unset our slider number to ensure process volumes are being re-set
-			// (the next read line will emit SliderMoveEvent instances for all sliders)\
-			// this needs to happen after a small delay, because the session map will also re-acquire sessions
-			// whenever the config file is reloaded, and we don't want it to receive these move events while the map
-			// is still cleared. this is kind of ugly, but shouldn't cause any issues
-			go func() {
-				<-time.After(stopDelay)
-				sio.lastKnownNumSliders = 0
-			}()
+		for volumesChanged := range configReloadedChannel {
+			// if the reload changed what the sliders control, re-send every slider so the volumes follow
+			// (the next read line will emit SliderMoveEvent instances for all sliders). this happens after a small
+			// delay, so the session map is done rebuilding its index first. a reload that only touched other
+			// settings leaves the volumes alone
+			if volumesChanged {
+				go func() {
+					<-time.After(stopDelay)
+					sio.resyncSliders()
+				}()
+			}
+
+			// the board's own settings might have changed too
+			if sio.connected {
+				sio.pushHardwareSettings()
+				sio.applyReportRate()
+				sio.displaySliderNames()
+			}
 
 			// if connection params have changed, attempt to stop and start the connection
-			if sio.deej.config.ConnectionInfo.COMPort != sio.connOptions.PortName ||
-				uint(sio.deej.config.ConnectionInfo.BaudRate) != sio.connOptions.BaudRate {
+			if sio.deej.config.ConnectionInfo != sio.connInfo {
 
 				sio.logger.Info("Detected change in connection parameters, attempting to renew connection")
 				sio.Stop()
 
-				// let the connection close
-				<-time.After(stopDelay)
-
 				if err := sio.Start(); err != nil {
 					sio.logger.Warnw("Failed to renew connection after parameter change", "error", err)
 				} else {
@@ -383,7 +812,109 @@ func (sio *SerialIO) setupOnConfigReload() {
 	}()
 }
 
-func (sio *SerialIO) close(logger *zap.SugaredLogger) {
+// reconnect closes and reopens the connection, for when it might have gone stale without an error to show for it
+func (sio *SerialIO) reconnect() {
+	if !sio.connected {
+		return
+	}
+
+	sio.logger.Info("Renewing connection")
+	sio.Stop()
+
+	// if the device isn't back yet, the hotplug watcher will connect once it is
+	if err := sio.Start(); err != nil {
+		sio.logger.Warnw("Failed to renew connection, waiting for the device to be plugged in again", "error", err)
+	} else {
+		sio.logger.Debug("Renewed connection successfully")
+	}
+}
+
+func (sio *SerialIO) handleHotplugEvent(event hotplugEvent) {
+	switch event.Action {
+	case hotplugAdded:
+		if sio.isConnected() || !sio.acceptsPort(event.PortName) {
+			return
+		}
+
+		sio.logger.Infow("Serial device plugged in, attempting connection", "port", event.PortName)
+
+		// give the driver (and udev rules, on linux) a moment to finish setting up the device node
+		<-time.After(hotplugSettleDelay)
+		sio.connectIfIdle(event.PortName)
+
+	case hotplugRemoved:
+		sio.connLock.Lock()
+		connected := sio.connected
+		connectedPort := sio.connOptions.PortName
+		sio.connLock.Unlock()
+
+		if !connected || !samePort(event.PortName, connectedPort) {
+			return
+		}
+
+		sio.logger.Infow("Connected serial device was unplugged", "port", event.PortName)
+		sio.Stop()
+	}
+}
+
+// acceptsPort returns true if a device on the given port could be the one we're configured to connect to
+func (sio *SerialIO) acceptsPort(portName string) bool {
+
+	// any HID device could be ours, opening it is what checks the vendor and product IDs
+	if sio.deej.config.ConnectionInfo.Type == connectionTypeHID {
+		return isHIDPort(portName)
+	} else if isHIDPort(portName) {
+		return false
+	}
+
+	comPort := sio.deej.config.ConnectionInfo.COMPort
+	if comPort == "" || strings.ToLower(comPort) == comPortAuto {
+		return true
+	}
+
+	return samePort(comPort, portName)
+}
+
+func (sio *SerialIO) connectIfIdle(portName string) {
+	if sio.isConnected() {
+		return
+	}
+
+	if err := sio.Start(); err != nil {
+		sio.logger.Debugw("Connection attempt failed, waiting for the device to be plugged in again",
+			"port", portName,
+			"error", err)
+	} else {
+		sio.logger.Infow("Connected to Arduino", "port", portName)
+	}
+}
+
+// pollForDevice periodically attempts to connect while disconnected. this is only
+// used on systems where we can't receive hotplug notifications
+func (sio *SerialIO) pollForDevice() {
+	ticker := time.NewTicker(fallbackReconnectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sio.stopWatchChannel:
+			return
+		case <-ticker.C:
+			sio.connectIfIdle(sio.deej.config.ConnectionInfo.COMPort)
+		}
+	}
+}
+
+// closeConn closes the given connection, unless it's already been closed or replaced by a newer one
+func (sio *SerialIO) closeConn(logger *zap.SugaredLogger, conn io.ReadWriteCloser) {
+	sio.connLock.Lock()
+	defer sio.connLock.Unlock()
+
+	// the reader goroutine and Stop can both get here for the same connection
+	if sio.conn == nil || sio.conn != conn {
+		return
+	}
+
 	if err := sio.conn.Close(); err != nil {
 		logger.Warnw("Failed to close serial connection", "error", err)
 	} else {
@@ -392,17 +923,39 @@ func (sio *SerialIO) close(logger *zap.SugaredLogger) {
 
 	sio.conn = nil
 	sio.connected = false
+	sio.deej.timeline.recordConnection(timelineKindDisconnected, sio.connOptions.PortName, "")
+
+	// whatever gets connected next will have to introduce itself again
+	sio.deviceLock.Lock()
+	sio.capabilities = nil
+	sio.protocol = nil
+	sio.pushedHardwareSettings = ""
+	sio.requestedReportRate = 0
+	sio.boardSliderNames = map[int]string{}
+	sio.displayedSliderNames = map[int]string{}
+	sio.deviceLock.Unlock()
+
+	sio.throttle.reset()
+	sio.commands.failAll(errCommandAborted)
+	sio.deej.showDeviceInfo()
 
 	// Set error icon when disconnected
 	sio.deej.SetTrayIcon(TrayError, DetectSystemTheme())
 }
 
-func (sio *SerialIO) readLine(logger *zap.SugaredLogger, reader *bufio.Reader) chan string {
+func (sio *SerialIO) readLine(ctx context.Context, logger *zap.SugaredLogger, reader *bufio.Reader) chan string {
 	ch := make(chan string)
 
 	go func() {
 		for {
 			line, err := reader.ReadString('\n')
+
+			// the port was closed on purpose, nothing to report
+			if ctx.Err() != nil {
+				close(ch)
+				return
+			}
+
 			if err != nil {
 
 				if sio.deej.Verbose() {
@@ -423,7 +976,12 @@ func (sio *SerialIO) readLine(logger *zap.SugaredLogger, reader *bufio.Reader) c
 			}
 
 			// deliver the line to the channel
-			ch <- line
+			select {
+			case ch <- line:
+			case <-ctx.Done():
+				close(ch)
+				return
+			}
 		}
 	}()
 
@@ -431,39 +989,106 @@ func (sio *SerialIO) readLine(logger *zap.SugaredLogger, reader *bufio.Reader) c
 }
 
 func (sio *SerialIO) handleLine(logger *zap.SugaredLogger, line string) {
+
+	// taken before anything else, so latency measurements include parsing the line
+	received := time.Now()
+
 	// Trim whitespace and newlines
 	line = strings.TrimSpace(line)
+	sio.inspector.record(trafficDirectionIn, trafficKindRaw, line, "")
+	sio.deej.trace.recordLine(line)
+
+	// corrupted frames are what cause wild volume jumps on noisy setups, so drop anything that fails its checksum.
+	// the startup message is exempt from requiring one, since that's how checksums get negotiated in the first place
+	checksumRequired := sio.Capabilities().Supports(capabilityCRC) && !strings.Contains(line, ":startup:")
+
+	line, err := verifyChecksum(line, checksumRequired)
+	if err != nil {
+		atomic.AddUint64(&sio.checksumErrors, 1)
+		sio.inspector.record(trafficDirectionIn, trafficKindRejected, line, err.Error())
+
+		if sio.deej.Verbose() {
+			logger.Debugw("Dropping line that failed checksum verification", "line", line, "error", err)
+		}
+
+		return
+	}
 
 	// Handle new deej protocol messages
-	if strings.HasPrefix(line, "deej:") {
-		parts := strings.Split(line, ":")
-		if len(parts) < 3 {
+	if strings.HasPrefix(line, framePrefix) {
+		frame, ok := parseFrame(line)
+		if !ok {
 			return // Invalid message format
 		}
 
-		messageType := parts[2]
+		messageType := frame.messageType
+
+		// every framed message carries the device's version, so we learn it even if we missed the startup message
+		compatible := sio.recordProtocolVersion(logger, frame.version)
 
 		switch messageType {
 		case "startup":
-			if len(parts) >= 4 {
-				capabilities := parts[3]
-				logger.Infow("Arduino connected", "version", parts[1], "capabilities", capabilities)
+			sio.inspector.record(trafficDirectionIn, trafficKindFrame, line, messageType)
+
+			if rawCapabilities, ok := frame.field(0); ok {
+				capabilities := parseCapabilities(rawCapabilities)
+				logger.Infow("Arduino connected", "version", frame.version, "capabilities", capabilities)
+
+				sio.deviceLock.Lock()
+				sio.capabilities = capabilities
+				sio.deviceLock.Unlock()
+
+				if sio.connected {
+					go sio.deej.config.rememberSliderCount(capabilities.Sliders)
+				}
+			}
+
+			if compatible {
+				sio.deej.SetTrayIcon(TrayNormal, DetectSystemTheme())
+				sio.pushHardwareSettings()
+				sio.applyReportRate()
+				sio.deej.nowPlaying.resendDisplay()
+				sio.deej.idle.connected()
+				go sio.loadSliderNames()
 			}
-			sio.deej.SetTrayIcon(TrayNormal, DetectSystemTheme())
 			return
 
 		case "sliders":
-			if len(parts) >= 4 {
-				// Extract slider data from the message
-				sliderData := parts[3]
-				sio.processSliderData(logger, sliderData)
+
+			// Extract slider data from the message
+			sliderData, ok := frame.field(0)
+			if !ok {
+				return
+			}
+
+			// garbage here would otherwise read as zeroes, and slam the volumes down
+			if !validSliderData(sliderData) {
+				sio.inspector.record(trafficDirectionIn, trafficKindRejected, line, "malformed slider data")
+				return
+			}
+
+			sio.inspector.record(trafficDirectionIn, trafficKindFrame, line, messageType)
+
+			if sio.throttle.admit(logger, sliderData, received) {
+				sio.processSliderData(logger, sliderData, received)
+			}
+			return
+
+		case "button":
+			sio.inspector.record(trafficDirectionIn, trafficKindFrame, line, messageType)
+
+			// e.g. "deej:v2.0:button:0:down"
+			buttonIdx, hasIdx := frame.field(0)
+			state, hasState := frame.field(1)
+			if hasIdx && hasState {
+				sio.handleButton(logger, buttonIdx, state)
 			}
 			return
 
 		case "response":
-			if len(parts) >= 4 {
-				responseType := parts[3]
-				sio.handleCommandResponse(logger, responseType, parts[4:])
+			if responseType, ok := frame.field(0); ok {
+				sio.inspector.recordResponse(line, responseType)
+				sio.handleCommandResponse(logger, responseType, frame.fieldsFrom(1))
 			}
 			return
 		}
@@ -488,36 +1113,166 @@ func (sio *SerialIO) handleLine(logger *zap.SugaredLogger, line string) {
 	}
 
 	// Handle old format slider data
-	if expectedLinePattern.MatchString(line) {
-		sio.processSliderData(logger, line)
+	if validSliderData(line) {
+		sio.inspector.record(trafficDirectionIn, trafficKindFrame, line, "legacy sliders")
+		sio.recordLegacyProtocol(logger)
+
+		if sio.throttle.admit(logger, line, received) {
+			sio.processSliderData(logger, line, received)
+		}
+		return
+	}
+
+	sio.inspector.record(trafficDirectionIn, trafficKindRejected, line, "unrecognized line")
+}
+
+// recordProtocolVersion remembers the version the device reports, and warns the user the first
+// time an incompatible one shows up. it returns false if the version isn't compatible
+func (sio *SerialIO) recordProtocolVersion(logger *zap.SugaredLogger, raw string) bool {
+	sio.deviceLock.Lock()
+
+	if sio.protocol != nil && sio.protocol.raw == raw {
+		compatible := sio.protocol.compatible() == nil
+		sio.deviceLock.Unlock()
+
+		return compatible
+	}
+
+	version, err := parseProtocolVersion(raw)
+	if err != nil {
+		sio.deviceLock.Unlock()
+		logger.Debugw("Ignoring malformed protocol version", "version", raw, "error", err)
+
+		return true
+	}
+
+	sio.protocol = &version
+	sio.deviceLock.Unlock()
+
+	sio.deej.showDeviceInfo()
+
+	if err := version.compatible(); err != nil {
+		logger.Warnw("Device firmware is incompatible with this version of deej",
+			"deviceVersion", version,
+			"supportedVersion", firmwareVersion,
+			"error", err)
+
+		sio.deej.SetTrayIcon(TrayError, DetectSystemTheme())
+		sio.stability.notify("incompatible_firmware", tr("notify.incompatible_firmware.title"),
+			tr("notify.incompatible_firmware.message", version, firmwareVersion))
+
+		return false
+	}
+
+	logger.Infow("Detected device protocol version", "version", version)
+
+	return true
+}
+
+// recordLegacyProtocol marks the device as one that only sends bare slider lines, unless it already identified itself
+func (sio *SerialIO) recordLegacyProtocol(logger *zap.SugaredLogger) {
+	sio.deviceLock.Lock()
+	defer sio.deviceLock.Unlock()
+
+	if sio.protocol != nil {
+		return
+	}
+
+	version := legacyProtocol
+	sio.protocol = &version
+
+	logger.Infow("Device uses the legacy protocol, commands won't be available")
+}
+
+// buttonEventIndex returns which button a line's event is for, if it's a button event
+func buttonEventIndex(line string) (string, bool) {
+	frame, ok := parseFrame(strings.TrimSpace(line))
+	if !ok || frame.messageType != "button" {
+		return "", false
+	}
+
+	return frame.field(0)
+}
+
+// handleButton performs the action bound to a button when it's pressed or released
+func (sio *SerialIO) handleButton(logger *zap.SugaredLogger, buttonIdxString string, state string) {
+	buttonIdx, err := strconv.Atoi(buttonIdxString)
+	if err != nil {
+		logger.Debugw("Ignoring button event with invalid index", "button", buttonIdxString)
+		return
+	}
+
+	var pressed bool
+
+	switch state {
+	case "down":
+		pressed = true
+	case "up":
+		pressed = false
+	default:
+		logger.Debugw("Ignoring button event with unknown state", "button", buttonIdx, "state", state)
+		return
+	}
+
+	sio.deej.idle.activity()
+
+	action, ok := sio.deej.config.Buttons[buttonIdx]
+	if !ok {
+		return
+	}
+
+	if err := sio.deej.performButtonAction(action, buttonIdx, pressed); err != nil {
+		logger.Warnw("Failed to perform button action", "button", buttonIdx, "action", action, "error", err)
 	}
 }
 
-func (sio *SerialIO) processSliderData(logger *zap.SugaredLogger, sliderData string) {
-	// split on pipe (|), this gives a slice of numerical strings between "0" and "1023"
-	splitLine := strings.Split(sliderData, "|")
-	numSliders := len(splitLine)
+// received is when the line the data came in was read, which move events carry along for latency measurements
+func (sio *SerialIO) processSliderData(logger *zap.SugaredLogger, sliderData string, received time.Time) {
+	// slider values are numerical strings between "0" and "1023", separated by pipes (|)
+	numSliders := strings.Count(sliderData, "|") + 1
 
 	// Use a mutex to protect shared state when processing slider data concurrently
 	sio.sliderDataMutex.Lock()
 	defer sio.sliderDataMutex.Unlock()
 
+	now := time.Now()
+
+	// once the sliders have been still for a while, the same frame tends to come in over and over. there's
+	// nothing in it we haven't seen, so don't bother parsing it
+	if sliderData == sio.lastSliderData && now.Sub(sio.lastSliderMove) >= sliderIdleTimeout {
+		return
+	}
+
 	// update our slider count, if needed - this will send slider move events for all
 	if numSliders != sio.lastKnownNumSliders {
 		logger.Infow("Detected sliders", "amount", numSliders)
 		sio.lastKnownNumSliders = numSliders
+
+		// for the config window, while the board isn't connected. only real connections count, not replayed lines
+		if sio.connected {
+			go sio.deej.config.rememberSliderCount(numSliders)
+		}
 		sio.currentSliderPercentValues = make([]float32, numSliders)
+		sio.lastRawSliderValues = make([]int, numSliders)
 
 		// reset everything to be an impossible value to force the slider move event later
 		for idx := range sio.currentSliderPercentValues {
 			sio.currentSliderPercentValues[idx] = -1.0
 		}
+
+		// it needs the slider count, which we're holding on to
+		go sio.deej.showDeviceInfo()
 	}
 
-	// for each slider:
-	moveEvents := []SliderMoveEvent{}
+	// for each slider (events are reused from line to line, since consumers get copies of them anyway):
+	moveEvents := sio.moveEvents[:0]
+	remaining := sliderData
 
-	for sliderIdx, stringValue := range splitLine {
+	for sliderIdx := 0; sliderIdx < numSliders; sliderIdx++ {
+		stringValue := remaining
+		if pipeIdx := strings.IndexByte(remaining, '|'); pipeIdx >= 0 {
+			stringValue, remaining = remaining[:pipeIdx], remaining[pipeIdx+1:]
+		}
 
 		// convert string values to integers ("1023" -> 1023)
 		number, _ := strconv.Atoi(stringValue)
@@ -526,9 +1281,18 @@ func (sio *SerialIO) processSliderData(logger *zap.SugaredLogger, sliderData str
 		// so let's check the first number for correctness just in case
 		if sliderIdx == 0 && number > 1023 {
 			sio.logger.Debugw("Got malformed line from serial, ignoring", "line", sliderData)
+			sio.inspector.record(trafficDirectionIn, trafficKindRejected, sliderData, "slider value out of range")
 			return
 		}
 
+		// the same raw value can't produce a different volume, so skip the math for sliders that haven't budged
+		initial := sio.currentSliderPercentValues[sliderIdx] == -1.0
+		if !initial && number == sio.lastRawSliderValues[sliderIdx] {
+			continue
+		}
+
+		sio.lastRawSliderValues[sliderIdx] = number
+
 		// map the value from raw to a "dirty" float between 0 and 1 (e.g. 0.15451...)
 		dirtyFloat := float32(number) / 1023.0
 
@@ -540,10 +1304,13 @@ func (sio *SerialIO) processSliderData(logger *zap.SugaredLogger, sliderData str
 			normalizedScalar = 1 - normalizedScalar
 		}
 
+		// snap it to the slider's detents, if it has any
+		normalizedScalar = sio.snapToDetent(sliderIdx, normalizedScalar)
+
 		// check if it changes the desired state (could just be a jumpy raw slider value)
 		// For initial values (when currentSliderPercentValues[sliderIdx] == -1.0), always process
 		// to ensure initial volume levels are set
-		if sio.currentSliderPercentValues[sliderIdx] == -1.0 ||
+		if initial ||
 			util.SignificantlyDifferent(sio.currentSliderPercentValues[sliderIdx], normalizedScalar, sio.deej.config.NoiseReductionLevel) {
 
 			// if it does, update the saved value and create a move event
@@ -552,6 +1319,8 @@ func (sio *SerialIO) processSliderData(logger *zap.SugaredLogger, sliderData str
 			moveEvents = append(moveEvents, SliderMoveEvent{
 				SliderID:     sliderIdx,
 				PercentValue: normalizedScalar,
+				Initial:      initial,
+				Received:     received,
 			})
 
 			if sio.deej.Verbose() {
@@ -560,28 +1329,21 @@ func (sio *SerialIO) processSliderData(logger *zap.SugaredLogger, sliderData str
 		}
 	}
 
+	sio.moveEvents = moveEvents
+	sio.lastSliderData = sliderData
+
 	// deliver move events if there are any, towards all potential consumers
 	if len(moveEvents) > 0 {
+		sio.lastSliderMove = now
+
 		if sio.deej.Verbose() {
 			logger.Debugw("Processing slider events", "count", len(moveEvents))
 		} else {
 			// Always log initial slider events for debugging
 			logger.Infow("Processing initial slider events", "count", len(moveEvents), "consumers", len(sio.sliderMoveConsumers))
 		}
-		for _, consumer := range sio.sliderMoveConsumers {
-			for _, moveEvent := range moveEvents {
-				// Use non-blocking send to prevent serial processing from being blocked
-				select {
-				case consumer <- moveEvent:
-					// Event sent successfully
-				default:
-					// Channel is full, skip this event to prevent blocking
-					if sio.deej.Verbose() {
-						logger.Debugw("Slider event channel full, skipping event", "sliderID", moveEvent.SliderID)
-					}
-				}
-			}
-		}
+
+		sio.deliverMoveEvents(moveEvents)
 	} else {
 		// Log when no events are generated (for debugging)
 		if sio.deej.Verbose() {
@@ -590,52 +1352,406 @@ func (sio *SerialIO) processSliderData(logger *zap.SugaredLogger, sliderData str
 	}
 }
 
+// deliverMoveEvents hands slider move events to every consumer, see serial_consumers.go. callers must hold
+// sliderDataMutex
+func (sio *SerialIO) deliverMoveEvents(moveEvents []SliderMoveEvent) {
+	for _, consumer := range sio.sliderMoveConsumers {
+		for _, moveEvent := range moveEvents {
+			consumer.deliver(moveEvent)
+		}
+	}
+}
+
+// MoveSlider moves a slider that isn't there, e.g. one on the web UI's remote page. the move goes through the
+// same pipeline as a physical slider's, and counts as where the slider is until the physical one moves
+func (sio *SerialIO) MoveSlider(sliderID int, percentValue float32) {
+	percentValue = util.NormalizeScalar(percentValue)
+
+	sio.sliderDataMutex.Lock()
+	defer sio.sliderDataMutex.Unlock()
+
+	percentValue = sio.snapToDetent(sliderID, percentValue)
+
+	// without a board there are no slider positions yet, or fewer of them than the remote has sliders
+	for len(sio.currentSliderPercentValues) <= sliderID {
+		sio.currentSliderPercentValues = append(sio.currentSliderPercentValues, -1.0)
+	}
+
+	if sio.currentSliderPercentValues[sliderID] == percentValue {
+		return
+	}
+
+	sio.currentSliderPercentValues[sliderID] = percentValue
+	sio.lastSliderMove = time.Now()
+
+	sio.deliverMoveEvents([]SliderMoveEvent{{SliderID: sliderID, PercentValue: percentValue}})
+}
+
+// snapToDetent returns where a slider at the given value counts as being, given its detents. callers must hold
+// sliderDataMutex
+func (sio *SerialIO) snapToDetent(sliderID int, percentValue float32) float32 {
+	detents, ok := sio.deej.config.SliderDetents[sliderID]
+	if !ok {
+		return percentValue
+	}
+
+	held, ok := sio.heldDetents[sliderID]
+	if !ok {
+		held = noDetent
+	}
+
+	percentValue, sio.heldDetents[sliderID] = detents.snap(percentValue, held)
+
+	return percentValue
+}
+
 // SendCommand sends a command to the Arduino
 func (sio *SerialIO) SendCommand(command string) error {
-	if !sio.connected || sio.conn == nil {
-		return fmt.Errorf("not connected to Arduino")
+
+	// don't send hardware-specific commands to devices that never said they have that hardware
+	commandName := strings.SplitN(command, ":", 2)[0]
+	if capability, ok := commandCapabilities[commandName]; ok && !sio.Capabilities().Supports(capability) {
+		sio.logger.Debugw("Not sending command unsupported by device", "command", command, "capability", capability)
+		return fmt.Errorf("send %s command: %w", commandName, errCapabilityUnsupported)
+	}
+
+	return sio.sendMessage(messageTypeCommand, command)
+}
+
+// SetFader asks a motorized fader to move to the given position, between 0.0 and 1.0
+func (sio *SerialIO) SetFader(sliderID int, percentValue float32) error {
+	position := percentValue
+	if sio.deej.config.InvertSliders {
+		position = 1 - position
+	}
+
+	rawValue := int(position*1023 + 0.5)
+
+	if err := sio.sendMessage(messageTypeSetFader, fmt.Sprintf("%d:%d", sliderID, rawValue)); err != nil {
+		return err
+	}
+
+	// the fader will report its new position back once it gets there. treat that as where the slider
+	// already is, so it doesn't come back to us as a slider move
+	sio.sliderDataMutex.Lock()
+	if sliderID < len(sio.currentSliderPercentValues) {
+		sio.currentSliderPercentValues[sliderID] = util.NormalizeScalar(percentValue)
+	}
+	sio.sliderDataMutex.Unlock()
+
+	return nil
+}
+
+// sendMessage formats a message for the connected device and writes it
+func (sio *SerialIO) sendMessage(messageType string, payload string) error {
+
+	// closeConn can take the connection away at any time, so write to the one that's here now
+	sio.connLock.Lock()
+	connected, conn := sio.connected, sio.conn
+	sio.connLock.Unlock()
+
+	if !connected || conn == nil {
+		return errNotConnected
+	}
+
+	if capability, ok := messageCapabilities[messageType]; ok && !sio.Capabilities().Supports(capability) {
+		sio.logger.Debugw("Not sending message unsupported by device", "type", messageType, "capability", capability)
+		return fmt.Errorf("send %s message: %w", messageType, errCapabilityUnsupported)
+	}
+
+	// format the message the way the device's firmware version expects it. if it hasn't
+	// identified itself yet, assume it speaks the same version we do
+	sio.deviceLock.Lock()
+	protocol := sio.protocol
+	sio.deviceLock.Unlock()
+
+	if protocol == nil {
+		native, _ := parseProtocolVersion(firmwareVersion)
+		protocol = &native
+	}
+
+	formattedMessage, err := protocol.formatMessage(messageType, payload)
+	if err != nil {
+		sio.logger.Debugw("Can't send message to this device", "type", messageType, "payload", payload, "version", protocol, "error", err)
+		return fmt.Errorf("format %s message: %w", messageType, err)
+	}
+
+	if sio.Capabilities().Supports(capabilityCRC) {
+		formattedMessage = appendChecksum(strings.TrimSuffix(formattedMessage, "\n")) + "\n"
 	}
 
-	// Format command with protocol prefix
-	formattedCommand := fmt.Sprintf("deej:%s:command:%s\n", firmwareVersion, command)
+	_, err = conn.Write([]byte(formattedMessage))
+
+	// the port was closed while we were writing to it
+	if err != nil && (errors.Is(err, os.ErrClosed) || !sio.isCurrentConn(conn)) {
+		sio.logger.Debugw("Connection closed before message was sent", "type", messageType, "payload", payload)
+		return errNotConnected
+	}
 
-	_, err := sio.conn.Write([]byte(formattedCommand))
 	if err != nil {
-		sio.logger.Warnw("Failed to send command to Arduino", "command", command, "error", err)
-		return fmt.Errorf("send command: %w", err)
+		sio.logger.Warnw("Failed to send message to Arduino", "type", messageType, "payload", payload, "error", err)
+		return fmt.Errorf("send %s message: %w", messageType, err)
+	}
+
+	sio.inspector.record(trafficDirectionOut, trafficKindSent, strings.TrimSpace(formattedMessage), messageType)
+	if messageType == messageTypeCommand {
+		sio.inspector.recordCommandSent(strings.SplitN(payload, ":", 2)[0])
 	}
 
-	sio.logger.Debugw("Sent command to Arduino", "command", command)
+	sio.logger.Debugw("Sent message to Arduino", "type", messageType, "payload", payload)
 	return nil
 }
 
-// RebootArduino sends a reboot command to the Arduino
+// pushHardwareSettings sends the configured hardware settings to boards that take them,
+// unless they already have exactly these settings
+func (sio *SerialIO) pushHardwareSettings() {
+	if !sio.Capabilities().Supports(capabilityConfig) {
+		return
+	}
+
+	payload := sio.deej.config.Hardware.payload()
+
+	sio.deviceLock.Lock()
+	unchanged := payload == sio.pushedHardwareSettings
+	cleared := payload == "" && !unchanged
+	if cleared {
+		sio.pushedHardwareSettings = ""
+	}
+	sio.deviceLock.Unlock()
+
+	// there's no config message that takes settings back, and an empty one means nothing to the firmware. the board
+	// keeps what it was last sent until it restarts, and gets the settings again once any are set
+	if cleared {
+		sio.logger.Info("Hardware settings cleared, the device keeps its current ones until it restarts")
+		return
+	}
+
+	if unchanged {
+		return
+	}
+
+	if err := sio.sendMessage(messageTypeConfig, payload); err != nil {
+		sio.logger.Warnw("Failed to push hardware settings to device", "settings", payload, "error", err)
+		return
+	}
+
+	sio.deviceLock.Lock()
+	sio.pushedHardwareSettings = payload
+	sio.deviceLock.Unlock()
+
+	sio.logger.Infow("Pushed hardware settings to device", "settings", payload)
+}
+
+// applyReportRate has the connected board report slider positions at the configured rate, or has deej throttle
+// them when the board can't (see serial_throttle.go). it does nothing if the rate hasn't changed since last time
+func (sio *SerialIO) applyReportRate() {
+	rate := sio.deej.config.Hardware.ReportRate
+
+	sio.deviceLock.Lock()
+	unchanged := rate == sio.requestedReportRate
+	sio.requestedReportRate = rate
+	protocol := sio.protocol
+	sio.deviceLock.Unlock()
+
+	if unchanged {
+		return
+	}
+
+	sio.throttle.setRate(rate)
+
+	// legacy boards don't take commands, so they're throttled no matter what
+	if rate == hardwareSettingUnset || protocol == nil || protocol.compatible() != nil {
+		return
+	}
+
+	// boards that take hardware settings got the rate along with the rest of them
+	if sio.Capabilities().Supports(capabilityConfig) {
+		sio.throttle.setRate(0)
+		return
+	}
+
+	// the response arrives through a line handler, so don't wait for it from one
+	go func() {
+		if _, err := sio.SendCommandAndWait(fmt.Sprintf("rate:%d", rate), commandResponseTimeout); err != nil {
+			sio.logger.Debugw("Board can't lower its report rate, throttling slider frames instead", "rate", rate, "error", err)
+			return
+		}
+
+		sio.throttle.setRate(0)
+		sio.logger.Infow("Board agreed to report rate", "rate", rate)
+	}()
+}
+
+// resyncSliders makes the next line from the board emit slider move events for all sliders, to put
+// volumes back where the sliders are
+func (sio *SerialIO) resyncSliders() {
+	sio.sliderDataMutex.Lock()
+	sio.lastKnownNumSliders = 0
+	sio.lastSliderData = ""
+	sio.sliderDataMutex.Unlock()
+}
+
+// dimLEDs turns the board's LEDs off, or back to their configured brightness
+func (sio *SerialIO) dimLEDs(dim bool) {
+	if !sio.Capabilities().Supports(capabilityConfig) {
+		return
+	}
+
+	brightness := sio.deej.config.Hardware.LEDBrightness
+	if dim {
+		brightness = 0
+	} else if brightness == hardwareSettingUnset {
+		brightness = maxLEDBrightness
+	}
+
+	payload := fmt.Sprintf("%s=%d", hardwareKeyLEDBrightness, brightness)
+	if err := sio.sendMessage(messageTypeConfig, payload); err != nil {
+		sio.logger.Warnw("Failed to set LED brightness", "brightness", brightness, "error", err)
+	}
+}
+
+// RebootArduino sends a reboot command to the Arduino, and waits for it to acknowledge it
 func (sio *SerialIO) RebootArduino() error {
 	// Notify user that reboot command is being sent
-	sio.deej.notifier.Notify("Arduino Reboot", "Sending reboot command to Arduino...")
+	sio.deej.notifier.Notify(tr("notify.arduino_reboot.title"), tr("notify.arduino_reboot.message"))
+
+	_, err := sio.SendCommandAndWait("reboot", commandResponseTimeout)
+	return err
+}
+
+// RequestVersion asks the Arduino for its firmware version
+func (sio *SerialIO) RequestVersion() (string, error) {
+	args, err := sio.SendCommandAndWait("version", commandResponseTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	if len(args) == 0 || args[0] == "" {
+		return "", fmt.Errorf("version command: empty response")
+	}
 
-	return sio.SendCommand("reboot")
+	return args[0], nil
 }
 
-// RequestVersion sends a version request command to the Arduino
-func (sio *SerialIO) RequestVersion() error {
-	return sio.SendCommand("version")
+// Capabilities returns what the connected device advertised in its startup message, or nil if it hasn't yet
+func (sio *SerialIO) Capabilities() *DeviceCapabilities {
+	sio.deviceLock.Lock()
+	defer sio.deviceLock.Unlock()
+
+	return sio.capabilities
 }
 
-// GetNumSliders returns the number of sliders detected from the Arduino
-func (sio *SerialIO) GetNumSliders() int {
+// Status returns a snapshot of the current connection state
+func (sio *SerialIO) Status() SerialStatus {
+	status := SerialStatus{
+		Connected:      sio.connected,
+		Transport:      sio.deej.config.ConnectionInfo.Type,
+		NumSliders:     sio.GetNumSliders(),
+		Capabilities:   sio.Capabilities(),
+		ChecksumErrors: atomic.LoadUint64(&sio.checksumErrors),
+		DroppedMoves:   sio.DroppedSliderMoves(),
+		Stability:      sio.stability.snapshot(),
+	}
+
+	sio.deviceLock.Lock()
+	if sio.protocol != nil {
+		status.FirmwareVersion = sio.protocol.String()
+	}
+	sio.deviceLock.Unlock()
+
+	if status.Connected {
+		status.Port = sio.connOptions.PortName
+	} else {
+		status.Detection = sio.detection.snapshot()
+	}
+
+	return status
+}
+
+// SliderValue returns a slider's last known position, or false if it hasn't reported one yet
+func (sio *SerialIO) SliderValue(sliderID int) (float32, bool) {
 	sio.sliderDataMutex.Lock()
 	defer sio.sliderDataMutex.Unlock()
-	return sio.lastKnownNumSliders
+
+	if sliderID < 0 || sliderID >= len(sio.currentSliderPercentValues) || sio.currentSliderPercentValues[sliderID] == -1.0 {
+		return 0, false
+	}
+
+	return sio.currentSliderPercentValues[sliderID], true
+}
+
+// GetNumSliders returns the number of sliders detected from the Arduino: as many as its last slider data had, or
+// until it sends some, as many as its startup message said it has. 0 if neither is known
+func (sio *SerialIO) GetNumSliders() int {
+	sio.sliderDataMutex.Lock()
+	numSliders := sio.lastKnownNumSliders
+	sio.sliderDataMutex.Unlock()
+
+	if numSliders == 0 {
+		if capabilities := sio.Capabilities(); capabilities != nil {
+			numSliders = capabilities.Sliders
+		}
+	}
+
+	return numSliders
+}
+
+// RequestSliderCount returns the number of sliders known so far, and asks a connected board to send its sliders if
+// it isn't known yet (i.e. deej missed its startup message and the sliders haven't moved since). firmware answers
+// the "sliders" command with a slider data frame rather than a response, so the count is only known once that
+// arrives - this doesn't wait for it
+func (sio *SerialIO) RequestSliderCount() int {
+	if numSliders := sio.GetNumSliders(); numSliders > 0 || !sio.isConnected() {
+		return numSliders
+	}
+
+	// one request at a time is plenty
+	if !atomic.CompareAndSwapInt32(&sio.sliderCountRequested, 0, 1) {
+		return 0
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&sio.sliderCountRequested, 0)
+
+		if err := sio.SendCommand("sliders"); err != nil {
+			sio.logger.Debugw("Failed to ask board for its sliders", "error", err)
+		}
+	}()
+
+	return 0
+}
+
+// isCurrentConn returns true if conn is still the connection in use, rather than one that was closed since
+func (sio *SerialIO) isCurrentConn(conn io.ReadWriteCloser) bool {
+	sio.connLock.Lock()
+	defer sio.connLock.Unlock()
+
+	return sio.conn == conn
+}
+
+// isConnected returns true while a device is connected, safe to use from any goroutine
+func (sio *SerialIO) isConnected() bool {
+	sio.connLock.Lock()
+	defer sio.connLock.Unlock()
+
+	return sio.connected
 }
 
 func (sio *SerialIO) handleCommandResponse(logger *zap.SugaredLogger, responseType string, responseArgs []string) {
+	if !sio.commands.resolve(responseType, responseArgs) && sio.deej.Verbose() {
+		logger.Debugw("Response doesn't match any pending command", "type", responseType)
+	}
+
 	// Handle command response based on the response type
 	switch responseType {
 	case "reboot_ack":
 		logger.Info("Arduino acknowledged reboot command, device will restart")
 		return
 
+	case "rate_ack":
+		// applyReportRate is waiting for this one
+		return
+
 	case "version":
 		if len(responseArgs) >= 1 {
 			version := responseArgs[0]
@@ -0,0 +1,198 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the firmware doesn't number its commands, but it answers them one at a time and in order, with a response named
+// after the command (optionally with an "_ack" suffix, "version" or "reboot_ack") or an error naming it. so a
+// response goes to the oldest command still waiting for one by that name. a command that timed out keeps its place
+// for a while, so its late response is dropped instead of going to the next command by that name
+const (
+	commandResponseTimeout = 2 * time.Second
+
+	// how long a timed out command's response can still turn up
+	commandLateResponseGrace = 10 * time.Second
+)
+
+var (
+	errCommandTimeout  = errors.New("device didn't respond in time")
+	errCommandRejected = errors.New("device rejected the command")
+	errCommandAborted  = errors.New("connection closed before the device responded")
+	errNotConnected    = errors.New("not connected to Arduino")
+)
+
+// commandResponse is what a pending command ends with: the response's arguments, or why there are none
+type commandResponse struct {
+	args []string
+	err  error
+}
+
+type pendingCommand struct {
+	name string
+	done chan commandResponse
+
+	// when the command timed out, zero while it's still waited for
+	abandoned time.Time
+}
+
+// pendingCommands tracks commands sent to the device that are waiting for their response
+type pendingCommands struct {
+	queue []*pendingCommand
+	lock  sync.Mutex
+}
+
+func newPendingCommands() *pendingCommands {
+	return &pendingCommands{}
+}
+
+func (pc *pendingCommands) add(name string) *pendingCommand {
+	cmd := &pendingCommand{
+		name: name,
+		done: make(chan commandResponse, 1),
+	}
+
+	pc.lock.Lock()
+	pc.queue = append(pc.queue, cmd)
+	pc.lock.Unlock()
+
+	return cmd
+}
+
+// remove stops waiting for a command, e.g. after it timed out
+func (pc *pendingCommands) remove(cmd *pendingCommand) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	for idx, pending := range pc.queue {
+		if pending == cmd {
+			pc.queue = append(pc.queue[:idx], pc.queue[idx+1:]...)
+			return
+		}
+	}
+}
+
+// abandon stops waiting for a command that timed out. it stays in line to take its response, should the device
+// still send one
+func (pc *pendingCommands) abandon(cmd *pendingCommand) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	cmd.abandoned = time.Now()
+}
+
+// resolve hands a response to the command waiting for it. it returns false if no command was waiting for it
+func (pc *pendingCommands) resolve(responseType string, args []string) bool {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	pc.forgetAbandoned()
+
+	if len(pc.queue) == 0 {
+		return false
+	}
+
+	idx := -1
+	result := commandResponse{args: args}
+
+	if responseType == "error" {
+
+		// e.g. "error:unknown_command:version". errors that don't name their command go to the oldest one
+		idx = 0
+		if len(args) >= 2 {
+			if named := pc.find(args[1]); named >= 0 {
+				idx = named
+			}
+		}
+
+		result = commandResponse{err: fmt.Errorf("%w: %s", errCommandRejected, strings.Join(args, ": "))}
+	} else if idx = pc.find(responseType); idx < 0 {
+		idx = pc.find(strings.TrimSuffix(responseType, "_ack"))
+	}
+
+	if idx < 0 {
+		return false
+	}
+
+	cmd := pc.queue[idx]
+	pc.queue = append(pc.queue[:idx], pc.queue[idx+1:]...)
+
+	// nobody's waiting for it anymore
+	if !cmd.abandoned.IsZero() {
+		return true
+	}
+
+	cmd.done <- result
+
+	return true
+}
+
+// forgetAbandoned drops commands that timed out long enough ago that their response isn't coming anymore. callers
+// must hold the lock
+func (pc *pendingCommands) forgetAbandoned() {
+	waiting := pc.queue[:0]
+	for _, cmd := range pc.queue {
+		if cmd.abandoned.IsZero() || time.Since(cmd.abandoned) < commandLateResponseGrace {
+			waiting = append(waiting, cmd)
+		}
+	}
+
+	pc.queue = waiting
+}
+
+// failAll gives up on every pending command, e.g. when the connection closes
+func (pc *pendingCommands) failAll(err error) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	for _, cmd := range pc.queue {
+		cmd.done <- commandResponse{err: err}
+	}
+
+	pc.queue = nil
+}
+
+// find returns the index of the oldest command with the given name, or -1. callers must hold the lock
+func (pc *pendingCommands) find(name string) int {
+	for idx, cmd := range pc.queue {
+		if cmd.name == name {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// SendCommandAndWait sends a command to the Arduino and waits for its response, returning the response's arguments
+// (e.g. the version for "version"). it fails if the device rejects the command or doesn't answer within timeout
+func (sio *SerialIO) SendCommandAndWait(command string, timeout time.Duration) ([]string, error) {
+	commandName := strings.SplitN(command, ":", 2)[0]
+
+	// registered before sending, or a quick device could answer before we're listening
+	cmd := sio.commands.add(commandName)
+
+	if err := sio.SendCommand(command); err != nil {
+		sio.commands.remove(cmd)
+		return nil, err
+	}
+
+	select {
+	case response := <-cmd.done:
+		if response.err != nil {
+			sio.logger.Debugw("Command failed", "command", command, "error", response.err)
+			return nil, fmt.Errorf("%s command: %w", commandName, response.err)
+		}
+
+		return response.args, nil
+
+	case <-time.After(timeout):
+		sio.commands.abandon(cmd)
+		sio.logger.Debugw("Timed out waiting for command response", "command", command, "timeout", timeout)
+
+		return nil, fmt.Errorf("%s command: %w", commandName, errCommandTimeout)
+	}
+}
@@ -0,0 +1,187 @@
+package deej
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sliderMoveConsumer hands slider moves to one subscriber. moves go straight into its channel while there's room.
+// once it's full, only the latest move of each slider is kept until the subscriber catches up, since that's the
+// only one whose volume matters by then - the ones it replaces are counted as dropped
+type sliderMoveConsumer struct {
+	logger *zap.SugaredLogger
+	name   string
+	events chan SliderMoveEvent
+
+	// moves that didn't fit, by slider, and the order their sliders first overflowed in. forwarding is set while
+	// they're being handed over, and until then new moves queue up behind them so none overtakes an older one
+	pending      map[int]SliderMoveEvent
+	pendingOrder []int
+	forwarding   bool
+
+	dropped uint64
+
+	// drops in the current window, and how many windows in a row had too many of them
+	windowStart      time.Time
+	windowDrops      uint64
+	sustainedWindows int
+
+	lock sync.Mutex
+}
+
+const (
+	// how many moves a subscriber's channel holds before moves start queueing per slider
+	sliderMoveBufferSize = 100
+
+	// drops are judged over windows this long. this many of them in a row with at least this many drops each is
+	// when deej warns about it, since a few drops while a slider is yanked around are nothing to worry about
+	sliderDropWindow           = 10 * time.Second
+	sliderDropWindowThreshold  = 20
+	sliderDropSustainedWindows = 3
+)
+
+func newSliderMoveConsumer(logger *zap.SugaredLogger, name string) *sliderMoveConsumer {
+	return &sliderMoveConsumer{
+		logger:      logger,
+		name:        name,
+		events:      make(chan SliderMoveEvent, sliderMoveBufferSize),
+		pending:     map[int]SliderMoveEvent{},
+		windowStart: time.Now(),
+	}
+}
+
+// deliver hands a move to the subscriber without ever blocking
+func (c *sliderMoveConsumer) deliver(event SliderMoveEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.forwarding {
+		select {
+		case c.events <- event:
+			return
+		default:
+		}
+	}
+
+	if previous, ok := c.pending[event.SliderID]; ok {
+
+		// whoever gets the newer move still needs to know it's the first since connecting
+		event.Initial = event.Initial || previous.Initial
+		c.drop()
+	} else {
+		c.pendingOrder = append(c.pendingOrder, event.SliderID)
+	}
+
+	c.pending[event.SliderID] = event
+
+	if !c.forwarding {
+		c.forwarding = true
+		go c.forward()
+	}
+}
+
+// forward hands queued moves over as the subscriber makes room for them, until there are none left
+func (c *sliderMoveConsumer) forward() {
+	for {
+		c.lock.Lock()
+
+		if len(c.pendingOrder) == 0 {
+			c.forwarding = false
+			c.lock.Unlock()
+			return
+		}
+
+		sliderID := c.pendingOrder[0]
+		event := c.pending[sliderID]
+
+		c.pendingOrder = c.pendingOrder[1:]
+		delete(c.pending, sliderID)
+
+		c.lock.Unlock()
+
+		c.events <- event
+	}
+}
+
+// drop counts a move that was replaced before the subscriber got to it, and warns once drops keep happening.
+// callers must hold the lock
+func (c *sliderMoveConsumer) drop() {
+	c.dropped++
+	c.windowDrops++
+
+	now := time.Now()
+	if now.Sub(c.windowStart) < sliderDropWindow {
+		return
+	}
+
+	if c.windowDrops >= sliderDropWindowThreshold {
+		c.sustainedWindows++
+	} else {
+		c.sustainedWindows = 0
+	}
+
+	c.windowStart, c.windowDrops = now, 0
+
+	if c.sustainedWindows == sliderDropSustainedWindows {
+		c.logger.Warnw("Slider moves keep arriving faster than they can be handled, only the latest position of each slider is kept",
+			"consumer", c.name,
+			"dropped", c.dropped,
+			"advice", "lower the board's report rate (report_rate under hardware in the config) or raise noise_reduction, "+
+				"and run deej with --measure-latency to see whether the audio backend is what's slow")
+	}
+}
+
+// stats returns how many moves were dropped so far, and how many are queued right now
+func (c *sliderMoveConsumer) stats() (uint64, int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.dropped, len(c.pendingOrder)
+}
+
+// SubscribeToSliderMoveEvents returns a channel that receives every slider move. name identifies the subscriber
+// in logs and stats
+func (sio *SerialIO) SubscribeToSliderMoveEvents(name string) chan SliderMoveEvent {
+	consumer := newSliderMoveConsumer(sio.logger, name)
+	sio.sliderMoveConsumers = append(sio.sliderMoveConsumers, consumer)
+
+	return consumer.events
+}
+
+// DroppedSliderMoves returns how many slider moves each subscriber missed because newer ones replaced them
+func (sio *SerialIO) DroppedSliderMoves() map[string]uint64 {
+	dropped := map[string]uint64{}
+	for _, consumer := range sio.sliderMoveConsumers {
+		dropped[consumer.name], _ = consumer.stats()
+	}
+
+	return dropped
+}
+
+// writeConsumerMetrics writes every subscriber's drops and queue length in prometheus' text format
+func (sio *SerialIO) writeConsumerMetrics(w io.Writer) {
+	dropped := make([]uint64, len(sio.sliderMoveConsumers))
+	queued := make([]int, len(sio.sliderMoveConsumers))
+
+	for idx, consumer := range sio.sliderMoveConsumers {
+		dropped[idx], queued[idx] = consumer.stats()
+	}
+
+	fmt.Fprintln(w, "# HELP deej_slider_moves_dropped_total Slider moves replaced by a newer one before a subscriber got to them.")
+	fmt.Fprintln(w, "# TYPE deej_slider_moves_dropped_total counter")
+
+	for idx, consumer := range sio.sliderMoveConsumers {
+		fmt.Fprintf(w, "deej_slider_moves_dropped_total{consumer=%q} %d\n", consumer.name, dropped[idx])
+	}
+
+	fmt.Fprintln(w, "# HELP deej_slider_moves_queued Slider moves waiting for a subscriber with a full channel.")
+	fmt.Fprintln(w, "# TYPE deej_slider_moves_queued gauge")
+
+	for idx, consumer := range sio.sliderMoveConsumers {
+		fmt.Fprintf(w, "deej_slider_moves_queued{consumer=%q} %d\n", consumer.name, queued[idx])
+	}
+}
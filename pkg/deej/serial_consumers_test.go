@@ -0,0 +1,63 @@
+package deej
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestSliderMoveConsumerKeepsLatestPerSlider(t *testing.T) {
+	c := newSliderMoveConsumer(zap.NewNop().Sugar(), "test")
+
+	// fill the channel, then keep moving two sliders while nobody reads
+	for idx := 0; idx < sliderMoveBufferSize; idx++ {
+		c.deliver(SliderMoveEvent{SliderID: 0, PercentValue: 0})
+	}
+
+	c.deliver(SliderMoveEvent{SliderID: 1, PercentValue: 0.1, Initial: true})
+	for idx := 1; idx <= 10; idx++ {
+		c.deliver(SliderMoveEvent{SliderID: 0, PercentValue: float32(idx) / 10})
+		c.deliver(SliderMoveEvent{SliderID: 1, PercentValue: float32(idx) / 20})
+	}
+
+	dropped, queued := c.stats()
+	if dropped != 19 || queued != 2 {
+		t.Errorf("expected 19 dropped and 2 queued moves, got %d and %d", dropped, queued)
+	}
+
+	for idx := 0; idx < sliderMoveBufferSize; idx++ {
+		<-c.events
+	}
+
+	expected := []SliderMoveEvent{{SliderID: 1, PercentValue: 0.5, Initial: true}, {SliderID: 0, PercentValue: 1}}
+	for _, move := range expected {
+		select {
+		case got := <-c.events:
+			if got != move {
+				t.Errorf("expected %+v, got %+v", move, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %+v", move)
+		}
+	}
+
+	// with the queue drained, moves go straight through again
+	deadline := time.Now().Add(time.Second)
+	for {
+		c.lock.Lock()
+		forwarding := c.forwarding
+		c.lock.Unlock()
+
+		if !forwarding || time.Now().After(deadline) {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	c.deliver(SliderMoveEvent{SliderID: 2, PercentValue: 0.3})
+	if got := <-c.events; got.SliderID != 2 {
+		t.Errorf("expected slider 2's move, got %+v", got)
+	}
+}
@@ -0,0 +1,134 @@
+package deej
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// serialInspector keeps a rolling record of everything that crosses the serial connection, for the web UI's
+// inspector page and the --dump-serial flag. it only records while one of those is actually watching
+type serialInspector struct {
+	logger *zap.SugaredLogger
+
+	// print every entry to the log as it's recorded
+	dump bool
+
+	entries  []SerialTrafficEntry
+	nextSeq  uint64
+	lastView time.Time
+
+	// when each command was last sent, to time its response
+	commandsSent map[string]time.Time
+
+	lock sync.Mutex
+}
+
+// SerialTrafficEntry is a single line sent or received, along with what deej made of it
+type SerialTrafficEntry struct {
+	Seq       uint64    `json:"seq"`
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction"`
+	Kind      string    `json:"kind"`
+	Text      string    `json:"text"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+const (
+	trafficDirectionIn  = "in"
+	trafficDirectionOut = "out"
+
+	trafficKindRaw      = "raw"      // a line exactly as it arrived
+	trafficKindFrame    = "frame"    // a line that parsed as a deej message
+	trafficKindRejected = "rejected" // a line that was dropped, and why
+	trafficKindSent     = "sent"     // a message deej sent to the device
+	trafficKindResponse = "response" // the device's response to a command, and how long it took
+
+	// how many entries to keep around for the inspector page
+	serialInspectorCapacity = 500
+
+	// the inspector page polls, so stop recording once it hasn't for a while
+	serialInspectorViewTimeout = 10 * time.Second
+)
+
+func newSerialInspector(logger *zap.SugaredLogger) *serialInspector {
+	return &serialInspector{
+		logger:       logger.Named("traffic"),
+		commandsSent: map[string]time.Time{},
+	}
+}
+
+func (si *serialInspector) record(direction string, kind string, text string, detail string) {
+	si.lock.Lock()
+	defer si.lock.Unlock()
+
+	if !si.dump && time.Since(si.lastView) >= serialInspectorViewTimeout {
+		return
+	}
+
+	si.nextSeq++
+
+	entry := SerialTrafficEntry{
+		Seq:       si.nextSeq,
+		Time:      time.Now(),
+		Direction: direction,
+		Kind:      kind,
+		Text:      text,
+		Detail:    detail,
+	}
+
+	si.entries = append(si.entries, entry)
+	if len(si.entries) > serialInspectorCapacity {
+		si.entries = si.entries[len(si.entries)-serialInspectorCapacity:]
+	}
+
+	if si.dump {
+		si.logger.Infof("%-3s %-8s %q %s", direction, kind, text, detail)
+	}
+}
+
+// recordCommandSent remembers when a command went out, so recordResponse can tell how long the device took
+func (si *serialInspector) recordCommandSent(command string) {
+	si.lock.Lock()
+	si.commandsSent[command] = time.Now()
+	si.lock.Unlock()
+}
+
+// recordResponse records a command response along with its round-trip time, if deej sent the command.
+// responses are named after their command, optionally with an "_ack" suffix ("version", "reboot_ack")
+func (si *serialInspector) recordResponse(line string, responseType string) {
+	si.lock.Lock()
+
+	detail := ""
+	for _, command := range []string{responseType, strings.TrimSuffix(responseType, "_ack")} {
+		if sentAt, ok := si.commandsSent[command]; ok {
+			detail = fmt.Sprintf("%s round trip: %s", command, time.Since(sentAt).Round(time.Millisecond))
+			delete(si.commandsSent, command)
+			break
+		}
+	}
+
+	si.lock.Unlock()
+
+	si.record(trafficDirectionIn, trafficKindResponse, line, detail)
+}
+
+// since returns recorded entries newer than the given sequence number, and marks the traffic as being watched
+func (si *serialInspector) since(seq uint64) []SerialTrafficEntry {
+	si.lock.Lock()
+	defer si.lock.Unlock()
+
+	si.lastView = time.Now()
+
+	result := []SerialTrafficEntry{}
+	for _, entry := range si.entries {
+		if entry.Seq > seq {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,24 @@
+package deej
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// listSerialPorts returns the serial ports an arduino could be behind
+func listSerialPorts() ([]string, error) {
+	ports := []string{}
+
+	for _, pattern := range []string{"/dev/ttyUSB*", "/dev/ttyACM*"} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		ports = append(ports, matches...)
+	}
+
+	sort.Strings(ports)
+
+	return ports, nil
+}
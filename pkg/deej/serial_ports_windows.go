@@ -0,0 +1,40 @@
+package deej
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// windows keeps a value for every present COM port here, named after its driver and holding the port name
+const serialPortsRegistryKey = `HARDWARE\DEVICEMAP\SERIALCOMM`
+
+// listSerialPorts returns the serial ports an arduino could be behind
+func listSerialPorts() ([]string, error) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, serialPortsRegistryKey, registry.QUERY_VALUE)
+
+	// the key only exists while at least one COM port does
+	if err == registry.ErrNotExist {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("open serial ports registry key: %w", err)
+	}
+	defer key.Close()
+
+	valueNames, err := key.ReadValueNames(-1)
+	if err != nil {
+		return nil, fmt.Errorf("read serial ports registry key: %w", err)
+	}
+
+	ports := []string{}
+	for _, valueName := range valueNames {
+		if port, _, err := key.GetStringValue(valueName); err == nil {
+			ports = append(ports, port)
+		}
+	}
+
+	sort.Strings(ports)
+
+	return ports, nil
+}
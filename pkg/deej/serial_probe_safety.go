@@ -0,0 +1,72 @@
+package deej
+
+import (
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// probing a port means opening it (which resets most arduinos) and writing to it. that's harmless for a deej, but
+// 3D printers, CNC machines and the like can react badly to either. ports listed in skip_ports are never probed,
+// and with safe_probing on, only ports whose USB metadata says they belong to a microcontroller board are
+
+// the USB vendor IDs of the boards deej usually runs on. generic USB to serial chips (CH340, FTDI, CP210x) aren't
+// here on purpose: most printers and CNC controllers are behind one of those too
+var knownBoardVendorIDs = map[uint16]string{
+	0x2341: "Arduino",
+	0x2a03: "Arduino",
+	0x1b4f: "SparkFun",
+	0x239a: "Adafruit",
+	0x2e8a: "Raspberry Pi",
+	0x16c0: "Teensy",
+	0x303a: "Espressif",
+}
+
+// skipsPort returns true if the given port is listed in skip_ports, by name or by a glob pattern ("/dev/ttyACM*")
+func (ps ProbeSafety) skipsPort(port string) bool {
+	for _, skipped := range ps.SkipPorts {
+		if samePort(port, skipped) {
+			return true
+		}
+
+		if matched, err := filepath.Match(strings.ToLower(skipped), strings.ToLower(port)); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// probeablePorts splits the given ports into the ones it's safe to probe, and the ones that have to be left alone
+func (ps ProbeSafety) probeablePorts(ports []string, logger *zap.SugaredLogger) ([]string, []string) {
+	var vendorIDs map[string]uint16
+	if ps.SafeProbing {
+		vendorIDs = serialPortVendorIDs(ports)
+	}
+
+	probeable, skipped := []string{}, []string{}
+
+	for _, port := range ports {
+		if ps.skipsPort(port) {
+			logger.Debugw("Not probing port listed in skip_ports", "port", port)
+			skipped = append(skipped, port)
+
+			continue
+		}
+
+		if ps.SafeProbing {
+			// ports that aren't behind a USB device at all have no vendor ID, and aren't known either
+			if _, known := knownBoardVendorIDs[vendorIDs[port]]; !known {
+				logger.Debugw("Not probing port of unknown device in safe probing mode", "port", port, "vendorID", vendorIDs[port])
+				skipped = append(skipped, port)
+
+				continue
+			}
+		}
+
+		probeable = append(probeable, port)
+	}
+
+	return probeable, skipped
+}
@@ -0,0 +1,42 @@
+package deej
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// how far up from a tty's device the USB device holding its vendor ID can be: the interface for ttyACM ports,
+// the interface's parent for ttyUSB ones
+const maxUSBDeviceDepth = 4
+
+// serialPortVendorIDs returns the USB vendor ID of each port that's behind a USB device, from sysfs
+func serialPortVendorIDs(ports []string) map[string]uint16 {
+	vendorIDs := map[string]uint16{}
+
+	for _, port := range ports {
+		device, err := filepath.EvalSymlinks(filepath.Join("/sys/class/tty", filepath.Base(port), "device"))
+		if err != nil {
+			continue
+		}
+
+		for depth := 0; depth < maxUSBDeviceDepth; depth, device = depth+1, filepath.Dir(device) {
+			raw, err := ioutil.ReadFile(filepath.Join(device, "idVendor"))
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			if err == nil {
+				if vendorID, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 16, 16); err == nil {
+					vendorIDs[port] = uint16(vendorID)
+				}
+			}
+
+			break
+		}
+	}
+
+	return vendorIDs
+}
@@ -0,0 +1,21 @@
+package deej
+
+import (
+	"testing"
+)
+
+func TestSkipsPort(t *testing.T) {
+	safety := ProbeSafety{SkipPorts: []string{"COM3", "/dev/ttyACM*"}}
+
+	for port, expected := range map[string]bool{
+		"COM3":         true,
+		"com3":         true,
+		"COM4":         false,
+		"/dev/ttyACM0": true,
+		"/dev/ttyUSB0": false,
+	} {
+		if actual := safety.skipsPort(port); actual != expected {
+			t.Errorf("%s: expected %v, got %v", port, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package deej
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// windows keeps a key for every USB device it has seen here, named after its vendor and product IDs
+// ("VID_2341&PID_0043"). the instances under it name their COM port in "Device Parameters"
+const usbDevicesRegistryKey = `SYSTEM\CurrentControlSet\Enum\USB`
+
+// serialPortVendorIDs returns the USB vendor ID of each port that's behind a USB device, from the registry
+func serialPortVendorIDs(ports []string) map[string]uint16 {
+	vendorIDs := map[string]uint16{}
+
+	devices, err := registry.OpenKey(registry.LOCAL_MACHINE, usbDevicesRegistryKey, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return vendorIDs
+	}
+	defer devices.Close()
+
+	deviceNames, err := devices.ReadSubKeyNames(-1)
+	if err != nil {
+		return vendorIDs
+	}
+
+	for _, deviceName := range deviceNames {
+		vendorID, ok := usbVendorID(deviceName)
+		if !ok {
+			continue
+		}
+
+		for _, port := range usbDevicePorts(usbDevicesRegistryKey + `\` + deviceName) {
+			for _, wanted := range ports {
+				if samePort(port, wanted) {
+					vendorIDs[wanted] = vendorID
+				}
+			}
+		}
+	}
+
+	return vendorIDs
+}
+
+// usbVendorID parses the vendor ID out of a USB device's key name
+func usbVendorID(deviceName string) (uint16, bool) {
+	for _, part := range strings.Split(strings.ToUpper(deviceName), "&") {
+		if !strings.HasPrefix(part, "VID_") {
+			continue
+		}
+
+		vendorID, err := strconv.ParseUint(strings.TrimPrefix(part, "VID_"), 16, 16)
+		return uint16(vendorID), err == nil
+	}
+
+	return 0, false
+}
+
+// usbDevicePorts returns the COM ports of a USB device's instances
+func usbDevicePorts(deviceKey string) []string {
+	device, err := registry.OpenKey(registry.LOCAL_MACHINE, deviceKey, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return nil
+	}
+	defer device.Close()
+
+	instances, err := device.ReadSubKeyNames(-1)
+	if err != nil {
+		return nil
+	}
+
+	ports := []string{}
+
+	for _, instance := range instances {
+		parameters, err := registry.OpenKey(registry.LOCAL_MACHINE, deviceKey+`\`+instance+`\Device Parameters`, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+
+		if port, _, err := parameters.GetStringValue("PortName"); err == nil {
+			ports = append(ports, port)
+		}
+
+		parameters.Close()
+	}
+
+	return ports
+}
@@ -0,0 +1,145 @@
+package deej
+
+import (
+	"testing"
+	"time"
+)
+
+const benchmarkConfig = `
+slider_mapping:
+  0: master
+  1: chrome.exe
+  2: discord.exe
+  3: spotify.exe
+  4: mic
+`
+
+var benchmarkSessions = []string{"master", "chrome.exe", "discord.exe", "spotify.exe", "mic"}
+
+// newBenchmarkDeej returns a test deej that has already seen the first frame, so the sliders are known
+func newBenchmarkDeej(b *testing.B) *testDeej {
+	td := newTestDeej(b, benchmarkConfig, benchmarkSessions...)
+	td.feed("deej:v2.0:sliders:512|256|1023|0|700")
+
+	return td
+}
+
+// the sliders have been still for a while, so repeated frames are skipped outright
+func BenchmarkHandleLineIdle(b *testing.B) {
+	td := newBenchmarkDeej(b)
+	td.serial.lastSliderMove = time.Now().Add(-sliderIdleTimeout)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		td.serial.handleLine(td.serial.logger, "deej:v2.0:sliders:512|256|1023|0|700")
+	}
+}
+
+// the sliders just moved, so frames get parsed, but the unchanged ones aren't normalized
+func BenchmarkHandleLineStill(b *testing.B) {
+	td := newBenchmarkDeej(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		td.serial.lastSliderMove = time.Now()
+		td.serial.handleLine(td.serial.logger, "deej:v2.0:sliders:512|256|1023|0|700")
+	}
+}
+
+// one slider jitters below the noise threshold, which is what a cheap potentiometer at rest looks like
+func BenchmarkHandleLineJitter(b *testing.B) {
+	td := newBenchmarkDeej(b)
+	lines := []string{"deej:v2.0:sliders:512|256|1023|0|700", "deej:v2.0:sliders:513|256|1023|0|700"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		td.serial.handleLine(td.serial.logger, lines[i%len(lines)])
+	}
+}
+
+func BenchmarkHandleLineLegacy(b *testing.B) {
+	td := newBenchmarkDeej(b)
+	td.feed("512|256|1023|0|700")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		td.serial.handleLine(td.serial.logger, "512|256|1023|0|700")
+	}
+}
+
+// whatever comes in over the wire, handling it mustn't bring deej down
+func FuzzHandleLine(f *testing.F) {
+	for _, seed := range append(frameSeeds, "status:ok", "4558|925", "deej:v2.0:sliders:512|256*00", "deej:v9:sliders:1") {
+		f.Add(seed)
+	}
+
+	td := newTestDeej(f, benchmarkConfig, benchmarkSessions...)
+
+	f.Fuzz(func(t *testing.T, line string) {
+		td.serial.handleLine(td.serial.logger, line)
+	})
+}
+
+// the startup message says how many sliders there are, before any of them reports
+func TestSliderCountFromStartup(t *testing.T) {
+	td := newTestDeej(t, benchmarkConfig, benchmarkSessions...)
+
+	if numSliders := td.serial.GetNumSliders(); numSliders != 0 {
+		t.Fatalf("expected no sliders before the board said anything, got %d", numSliders)
+	}
+
+	td.feed("deej:v2.0:startup:4sliders,2buttons")
+	if numSliders := td.serial.GetNumSliders(); numSliders != 4 {
+		t.Errorf("expected the 4 sliders the startup message advertised, got %d", numSliders)
+	}
+
+	// what the board actually sends wins
+	td.feed("deej:v2.0:sliders:512|256|1023|0|700")
+	if numSliders := td.serial.GetNumSliders(); numSliders != 5 {
+		t.Errorf("expected the 5 sliders in the data, got %d", numSliders)
+	}
+}
+
+func TestButtonEventIndex(t *testing.T) {
+	for line, expected := range map[string]string{
+		"deej:v2.0:button:3:down\r\n": "3",
+		"deej:v2.0:button:0:up*4F":    "0",
+		"deej:v2.0:sliders:0|1023":    "",
+		"0|512|1023":                  "",
+	} {
+		idx, ok := buttonEventIndex(line)
+		if idx != expected || ok != (expected != "") {
+			t.Errorf("%q: expected button %q, got %q (%v)", line, expected, idx, ok)
+		}
+	}
+}
+
+func TestLateCommandResponseDropped(t *testing.T) {
+	pc := newPendingCommands()
+
+	timedOut := pc.add("version")
+	pc.abandon(timedOut)
+
+	next := pc.add("version")
+
+	// the first command's response, arriving late, isn't the second's
+	pc.resolve("version", []string{"v1.0"})
+	select {
+	case response := <-next.done:
+		t.Fatalf("expected the late response to be dropped, got %v", response.args)
+	default:
+	}
+
+	pc.resolve("version", []string{"v2.0"})
+	if response := <-next.done; len(response.args) != 1 || response.args[0] != "v2.0" {
+		t.Errorf("expected the second response, got %v", response.args)
+	}
+}
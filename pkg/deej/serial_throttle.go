@@ -0,0 +1,98 @@
+package deej
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// the report rate is a hardware setting, so boards that take settings get it along with the rest of them. boards that
+// don't are asked with a "rate:<per second>" command instead, and if they don't know that one either, deej throttles
+// their slider frames on its own end. that doesn't spare the USB traffic, but it does spare the volume updates
+
+// sliderThrottle limits how often slider frames get processed. frames arriving too soon are held back, and the
+// newest of them is processed once the interval is up - boards only report changes, so dropping it would lose
+// the position a slider stopped at
+type sliderThrottle struct {
+	process func(logger *zap.SugaredLogger, sliderData string, received time.Time)
+
+	// zero while not throttling
+	interval time.Duration
+
+	lastProcessed time.Time
+
+	// the newest held back frame, when it was read and who to log it with
+	held         string
+	heldReceived time.Time
+	heldLogger   *zap.SugaredLogger
+	heldTimer    *time.Timer
+
+	lock sync.Mutex
+}
+
+func newSliderThrottle(process func(logger *zap.SugaredLogger, sliderData string, received time.Time)) *sliderThrottle {
+	return &sliderThrottle{process: process}
+}
+
+// setRate throttles to the given number of frames per second, or stops throttling if it isn't positive
+func (st *sliderThrottle) setRate(rate int) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	if rate <= 0 {
+		st.interval = 0
+		return
+	}
+
+	st.interval = time.Second / time.Duration(rate)
+}
+
+// admit returns true if the frame can be processed right away. otherwise it's held back until the interval is up
+func (st *sliderThrottle) admit(logger *zap.SugaredLogger, sliderData string, received time.Time) bool {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	if st.interval == 0 {
+		return true
+	}
+
+	now := time.Now()
+	if st.heldTimer == nil && now.Sub(st.lastProcessed) >= st.interval {
+		st.lastProcessed = now
+		return true
+	}
+
+	st.held, st.heldReceived, st.heldLogger = sliderData, received, logger
+
+	if st.heldTimer == nil {
+		st.heldTimer = time.AfterFunc(st.interval-now.Sub(st.lastProcessed), st.flush)
+	}
+
+	return false
+}
+
+func (st *sliderThrottle) flush() {
+	st.lock.Lock()
+	sliderData, received, logger := st.held, st.heldReceived, st.heldLogger
+	st.held, st.heldReceived, st.heldLogger, st.heldTimer = "", time.Time{}, nil, nil
+	st.lastProcessed = time.Now()
+	st.lock.Unlock()
+
+	if logger != nil {
+		st.process(logger, sliderData, received)
+	}
+}
+
+// reset drops any held back frame, for when the connection closes
+func (st *sliderThrottle) reset() {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	if st.heldTimer != nil {
+		st.heldTimer.Stop()
+	}
+
+	st.held, st.heldReceived, st.heldLogger, st.heldTimer = "", time.Time{}, nil, nil
+	st.lastProcessed = time.Time{}
+}
@@ -0,0 +1,34 @@
+package deej
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// deej can run as a windows service, which starts it along with the machine. services live in a session of their
+// own with no desktop and no audio sessions of their own, so the service doesn't do much by itself: it starts
+// (and keeps restarting) a regular deej process in whichever user session is active, and that one does the rest
+const (
+	serviceName        = "deej"
+	serviceDisplayName = "deej"
+	serviceDescription = "Starts deej for whoever is logged in, without needing an autostart shortcut."
+
+	// what the service manager starts deej with, see main.go
+	serviceArgument = "--service"
+)
+
+// ChdirToExecutable makes the executable's directory the working directory, since that's where deej keeps its
+// config and logs. services start out in system32
+func ChdirToExecutable() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("get executable path: %w", err)
+	}
+
+	if err := os.Chdir(filepath.Dir(executable)); err != nil {
+		return fmt.Errorf("change working directory: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package deej
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// systemd user units (or the desktop's autostart) already do this job on linux
+var errServiceUnsupported = errors.New("running as a service is only supported on windows, use a systemd user unit instead")
+
+// InstallService installs and starts the deej service
+func InstallService() error {
+	return errServiceUnsupported
+}
+
+// UninstallService stops and removes the deej service
+func UninstallService() error {
+	return errServiceUnsupported
+}
+
+// RunService runs deej as the service, until the service manager stops it
+func RunService(logger *zap.SugaredLogger) error {
+	return errServiceUnsupported
+}
@@ -0,0 +1,298 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"unsafe"
+
+	"go.uber.org/zap"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+const (
+
+	// how long to wait before restarting deej after it crashed
+	serviceRelaunchDelay = 5 * time.Second
+
+	// how long deej gets to quit on its own when the service stops, before it's terminated
+	serviceStopTimeout = 5 * time.Second
+
+	// how long the service manager waits before restarting the service itself, and when it forgets about failures
+	serviceRecoveryDelay       = 10 * time.Second
+	serviceRecoveryResetPeriod = 24 * 60 * 60
+
+	// what WTSGetActiveConsoleSessionId returns while there's no session attached to the console
+	noActiveConsoleSession = 0xFFFFFFFF
+
+	// the interactive desktop, which the tray icon and notifications need
+	interactiveDesktop = `winsta0\default`
+)
+
+// InstallService installs and starts the deej service. this needs to run as an administrator
+func InstallService() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("get executable path: %w", err)
+	}
+
+	manager, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connect to service manager (are you running as an administrator?): %w", err)
+	}
+	defer manager.Disconnect()
+
+	if service, err := manager.OpenService(serviceName); err == nil {
+		service.Close()
+		return fmt.Errorf("service %s is already installed", serviceName)
+	}
+
+	service, err := manager.CreateService(serviceName, executable, mgr.Config{
+		StartType:   mgr.StartAutomatic,
+		DisplayName: serviceDisplayName,
+		Description: serviceDescription,
+	}, serviceArgument)
+	if err != nil {
+		return fmt.Errorf("create service: %w", err)
+	}
+	defer service.Close()
+
+	// not being able to come back after a crash isn't worth failing the install over
+	service.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: serviceRecoveryDelay},
+	}, serviceRecoveryResetPeriod)
+
+	if err := service.Start(); err != nil {
+		return fmt.Errorf("start service: %w", err)
+	}
+
+	return nil
+}
+
+// UninstallService stops and removes the deej service. this needs to run as an administrator
+func UninstallService() error {
+	manager, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connect to service manager (are you running as an administrator?): %w", err)
+	}
+	defer manager.Disconnect()
+
+	service, err := manager.OpenService(serviceName)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed", serviceName)
+	}
+	defer service.Close()
+
+	// it might not be running, which is fine
+	service.Control(svc.Stop)
+
+	if err := service.Delete(); err != nil {
+		return fmt.Errorf("delete service: %w", err)
+	}
+
+	return nil
+}
+
+// RunService runs deej as the service, until the service manager stops it
+func RunService(logger *zap.SugaredLogger) error {
+	logger = logger.Named("service")
+
+	isService, err := svc.IsWindowsService()
+	if err != nil {
+		return fmt.Errorf("check whether running as a service: %w", err)
+	}
+
+	if !isService {
+		return errors.New("not started by the service manager, use --install-service instead")
+	}
+
+	logger.Info("Running as a service")
+
+	if err := svc.Run(serviceName, &serviceHandler{
+		logger: logger,
+		exited: make(chan uint32, 1),
+	}); err != nil {
+		logger.Warnw("Service failed", "error", err)
+		return fmt.Errorf("run service: %w", err)
+	}
+
+	logger.Info("Service stopped")
+
+	return nil
+}
+
+// serviceHandler keeps a deej process running in the active user's session
+type serviceHandler struct {
+	logger *zap.SugaredLogger
+
+	// the deej process we started, 0 while there isn't one
+	process windows.Handle
+
+	// receives the process's exit code once it exits
+	exited chan uint32
+}
+
+func (h *serviceHandler) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+
+	// whoever's already logged in gets deej right away, everyone else once they log in
+	h.launch()
+
+	status <- svc.Status{
+		State:   svc.Running,
+		Accepts: svc.AcceptStop | svc.AcceptShutdown | svc.AcceptSessionChange,
+	}
+
+	var relaunch <-chan time.Time
+
+	for {
+		select {
+		case request := <-requests:
+			switch request.Cmd {
+			case svc.Interrogate:
+				status <- request.CurrentStatus
+
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				h.stopProcess()
+
+				return false, 0
+
+			case svc.SessionChange:
+				if request.EventType == windows.WTS_SESSION_LOGON {
+					h.logger.Debugw("User logged on", "sessionID", sessionIDFromEventData(request.EventData))
+					h.launch()
+				}
+			}
+
+		case exitCode := <-h.exited:
+			h.closeProcess()
+
+			// quitting from the tray exits cleanly, and should stick until the next logon
+			if exitCode == 0 {
+				h.logger.Info("deej quit, not restarting it until the next logon")
+				continue
+			}
+
+			h.logger.Warnw("deej exited unexpectedly, restarting it", "exitCode", exitCode)
+			relaunch = time.After(serviceRelaunchDelay)
+
+		case <-relaunch:
+			relaunch = nil
+			h.launch()
+		}
+	}
+}
+
+// launch starts deej in the active console session, as the user logged in to it
+func (h *serviceHandler) launch() {
+	if h.process != 0 {
+		return
+	}
+
+	sessionID := windows.WTSGetActiveConsoleSessionId()
+	if sessionID == noActiveConsoleSession {
+		h.logger.Debug("No active console session, waiting for someone to log in")
+		return
+	}
+
+	var token windows.Token
+	if err := windows.WTSQueryUserToken(sessionID, &token); err != nil {
+		h.logger.Debugw("Nobody is logged in to the active session, waiting for someone to", "sessionID", sessionID, "error", err)
+		return
+	}
+	defer token.Close()
+
+	// the user's own environment, not the service's
+	var environment *uint16
+	if err := windows.CreateEnvironmentBlock(&environment, token, false); err != nil {
+		h.logger.Warnw("Failed to create user environment", "error", err)
+		return
+	}
+	defer windows.DestroyEnvironmentBlock(environment)
+
+	executable, err := os.Executable()
+	if err != nil {
+		h.logger.Warnw("Failed to get executable path", "error", err)
+		return
+	}
+
+	commandLine, _ := windows.UTF16PtrFromString(windows.EscapeArg(executable))
+	workingDirectory, _ := windows.UTF16PtrFromString(filepath.Dir(executable))
+	desktop, _ := windows.UTF16PtrFromString(interactiveDesktop)
+
+	startupInfo := windows.StartupInfo{Desktop: desktop}
+	startupInfo.Cb = uint32(unsafe.Sizeof(startupInfo))
+
+	var processInfo windows.ProcessInformation
+
+	if err := windows.CreateProcessAsUser(token, nil, commandLine, nil, nil, false,
+		windows.CREATE_UNICODE_ENVIRONMENT, environment, workingDirectory, &startupInfo, &processInfo); err != nil {
+
+		h.logger.Warnw("Failed to start deej in the user's session", "sessionID", sessionID, "error", err)
+		return
+	}
+
+	windows.CloseHandle(processInfo.Thread)
+	h.process = processInfo.Process
+
+	h.logger.Infow("Started deej in the user's session", "sessionID", sessionID, "pid", processInfo.ProcessId)
+
+	go h.waitForExit(processInfo.Process)
+}
+
+func (h *serviceHandler) waitForExit(process windows.Handle) {
+	var exitCode uint32
+
+	if _, err := windows.WaitForSingleObject(process, windows.INFINITE); err != nil {
+		h.logger.Warnw("Failed to wait for deej to exit", "error", err)
+	} else if err := windows.GetExitCodeProcess(process, &exitCode); err != nil {
+		h.logger.Warnw("Failed to get deej's exit code", "error", err)
+	}
+
+	h.exited <- exitCode
+}
+
+// stopProcess asks deej to quit the same way a second instance talks to it, and terminates it if it doesn't
+func (h *serviceHandler) stopProcess() {
+	if h.process == 0 {
+		return
+	}
+
+	if _, err := sendInstanceCommand(instanceCommandQuit); err != nil {
+		h.logger.Debugw("Failed to ask deej to quit", "error", err)
+	}
+
+	select {
+	case <-h.exited:
+		h.logger.Debug("deej quit")
+
+	case <-time.After(serviceStopTimeout):
+		h.logger.Warn("deej didn't quit in time, terminating it")
+
+		if err := windows.TerminateProcess(h.process, 1); err != nil {
+			h.logger.Warnw("Failed to terminate deej", "error", err)
+		}
+	}
+
+	h.closeProcess()
+}
+
+func (h *serviceHandler) closeProcess() {
+	windows.CloseHandle(h.process)
+	h.process = 0
+}
+
+// sessionIDFromEventData reads the session ID out of a session change's WTSSESSION_NOTIFICATION
+func sessionIDFromEventData(eventData uintptr) uint32 {
+	if eventData == 0 {
+		return noActiveConsoleSession
+	}
+
+	notification := (*windows.WTSSESSION_NOTIFICATION)(unsafe.Pointer(eventData))
+	return notification.SessionID
+}
@@ -11,9 +11,11 @@ type Session interface {
 	GetVolume() float32
 	SetVolume(v float32) error
 
-	// TODO: future mute support
-	// GetMute() bool
-	// SetMute(m bool) error
+	GetMute() bool
+	SetMute(m bool) error
+
+	// IsAlive returns false once the backend no longer knows this session (e.g. its process exited)
+	IsAlive() bool
 
 	Key() string
 	Release()
@@ -39,6 +41,15 @@ type baseSession struct {
 
 	// used by String(), needs to be set by child
 	humanReadableDesc string
+
+	// used by Role(), set by children whose backend reports one
+	role string
+
+	// used by DisplayName(), set by children whose backend (or app) has a friendlier name than the key
+	displayName string
+
+	// used by OutputName(), set by children that know which output they play on
+	outputName string
 }
 
 func (s *baseSession) Key() string {
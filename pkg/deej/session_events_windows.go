@@ -0,0 +1,174 @@
+package deej
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	ole "github.com/go-ole/go-ole"
+	wca "github.com/moutend/go-wca"
+)
+
+// volumeNotifier is our implementation of both IAudioSessionEvents (for process sessions) and
+// IAudioEndpointVolumeCallback (for master sessions). windows passes the callbacks a pointer to the
+// object it was registered with, so the vtable pointer must stay the struct's first field
+type volumeNotifier struct {
+	vtable unsafe.Pointer
+
+	// called with the event context of whoever changed the volume
+	onChange func(eventContext *ole.GUID)
+
+	// called when a process session expires or disconnects, never for master sessions
+	onExpired func()
+}
+
+type endpointVolumeCallbackVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+	OnNotify       uintptr
+}
+
+// AUDIO_VOLUME_NOTIFICATION_DATA, minus the trailing per-channel volumes we don't need
+type audioVolumeNotificationData struct {
+	EventContext ole.GUID
+	Muted        int32
+	MasterVolume float32
+	Channels     uint32
+}
+
+// windows only allows a limited number of callbacks per process, so every notifier shares the same two vtables
+var (
+	sessionEventsVtbl      *wca.IAudioSessionEventsVtbl
+	endpointVolumeCbVtbl   *endpointVolumeCallbackVtbl
+	createNotifierVtblOnce sync.Once
+)
+
+func createNotifierVtables() {
+	noop := syscall.NewCallback(func(this *volumeNotifier) (hResult uintptr) { return })
+
+	sessionEventsVtbl = &wca.IAudioSessionEventsVtbl{
+		QueryInterface:         queryInterfaceCallback(wca.IID_IAudioSessionEvents),
+		AddRef:                 noop,
+		Release:                noop,
+		OnDisplayNameChanged:   noop,
+		OnIconPathChanged:      noop,
+		OnChannelVolumeChanged: noop,
+		OnGroupingParamChanged: noop,
+
+		OnStateChanged: syscall.NewCallback(func(this *volumeNotifier, newState uintptr) (hResult uintptr) {
+			if newState == wca.AudioSessionStateExpired && this.onExpired != nil {
+				this.onExpired()
+			}
+			return
+		}),
+
+		OnSessionDisconnected: syscall.NewCallback(func(this *volumeNotifier, reason uintptr) (hResult uintptr) {
+			if this.onExpired != nil {
+				this.onExpired()
+			}
+			return
+		}),
+
+		// the new volume is passed as a float, which callbacks can't receive - consumers re-read it instead
+		OnSimpleVolumeChanged: syscall.NewCallback(func(
+			this *volumeNotifier,
+			newVolume uintptr,
+			newMute uintptr,
+			eventContext *ole.GUID,
+		) (hResult uintptr) {
+			this.onChange(eventContext)
+			return
+		}),
+	}
+
+	endpointVolumeCbVtbl = &endpointVolumeCallbackVtbl{
+		QueryInterface: queryInterfaceCallback(wca.IID_IAudioEndpointVolumeCallback),
+		AddRef:         noop,
+		Release:        noop,
+		OnNotify: syscall.NewCallback(func(this *volumeNotifier, data *audioVolumeNotificationData) (hResult uintptr) {
+			this.onChange(&data.EventContext)
+			return
+		}),
+	}
+}
+
+// queryInterfaceCallback answers QueryInterface for IUnknown and the given interface, which is all a notifier is
+func queryInterfaceCallback(iid *ole.GUID) uintptr {
+	return syscall.NewCallback(func(this *volumeNotifier, riid *ole.GUID, ppv *unsafe.Pointer) (hResult uintptr) {
+		if ole.IsEqualGUID(riid, ole.IID_IUnknown) || ole.IsEqualGUID(riid, iid) {
+			*ppv = unsafe.Pointer(this)
+			return ole.S_OK
+		}
+
+		*ppv = nil
+		return ole.E_NOINTERFACE
+	})
+}
+
+func newSessionEventsNotifier(onChange func(eventContext *ole.GUID), onExpired func()) *volumeNotifier {
+	createNotifierVtblOnce.Do(createNotifierVtables)
+
+	return &volumeNotifier{
+		vtable:    unsafe.Pointer(sessionEventsVtbl),
+		onChange:  onChange,
+		onExpired: onExpired,
+	}
+}
+
+func newEndpointVolumeNotifier(onChange func(eventContext *ole.GUID)) *volumeNotifier {
+	createNotifierVtblOnce.Do(createNotifierVtables)
+
+	return &volumeNotifier{
+		vtable:   unsafe.Pointer(endpointVolumeCbVtbl),
+		onChange: onChange,
+	}
+}
+
+func (n *volumeNotifier) registerWithSession(control *wca.IAudioSessionControl2) error {
+	if err := control.RegisterAudioSessionNotification((*wca.IAudioSessionEvents)(unsafe.Pointer(n))); err != nil {
+		return fmt.Errorf("register audio session notification: %w", err)
+	}
+
+	return nil
+}
+
+func (n *volumeNotifier) unregisterFromSession(control *wca.IAudioSessionControl2) error {
+	if err := control.UnregisterAudioSessionNotification((*wca.IAudioSessionEvents)(unsafe.Pointer(n))); err != nil {
+		return fmt.Errorf("unregister audio session notification: %w", err)
+	}
+
+	return nil
+}
+
+// go-wca doesn't implement (Un)RegisterControlChangeNotify, so call through the vtable ourselves
+func (n *volumeNotifier) registerWithEndpoint(volume *wca.IAudioEndpointVolume) error {
+	hr, _, _ := syscall.Syscall(
+		volume.VTable().RegisterControlChangeNotify,
+		2,
+		uintptr(unsafe.Pointer(volume)),
+		uintptr(unsafe.Pointer(n)),
+		0)
+
+	if hr != 0 {
+		return fmt.Errorf("register control change notify: %w", ole.NewError(hr))
+	}
+
+	return nil
+}
+
+func (n *volumeNotifier) unregisterFromEndpoint(volume *wca.IAudioEndpointVolume) error {
+	hr, _, _ := syscall.Syscall(
+		volume.VTable().UnregisterControlChangeNotify,
+		2,
+		uintptr(unsafe.Pointer(volume)),
+		uintptr(unsafe.Pointer(n)),
+		0)
+
+	if hr != 0 {
+		return fmt.Errorf("unregister control change notify: %w", ole.NewError(hr))
+	}
+
+	return nil
+}
@@ -1,8 +1,126 @@
 package deej
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
 // SessionFinder represents an entity that can find all current audio sessions
 type SessionFinder interface {
 	GetAllSessions() ([]Session, error)
 
 	Release() error
 }
+
+// sessionVolumeWatcher is implemented by session finders that can tell when one of the sessions
+// they found changes volume. changes deej made itself may or may not be reported, depending on the backend
+type sessionVolumeWatcher interface {
+	SubscribeToVolumeChanges() <-chan Session
+}
+
+// sessionRemovalWatcher is implemented by session finders that can tell when one of the sessions
+// they found goes away (e.g. its process exited), so it can be dropped without re-acquiring every session
+type sessionRemovalWatcher interface {
+	SubscribeToSessionRemovals() <-chan Session
+}
+
+// sessionDisconnectWatcher is implemented by session finders whose connection to the audio server can drop.
+// the returned channel is closed when it does, or when the finder is released
+type sessionDisconnectWatcher interface {
+	Disconnected() <-chan struct{}
+}
+
+// audio backends register a session finder constructor under their name from the files that implement them, so build
+// tags decide which are around. the audio_backend config option picks one, or leaves it to the platform's default
+type sessionFinderBackend func(logger *zap.SugaredLogger) (SessionFinder, error)
+
+const audioBackendAuto = "auto"
+
+var (
+	sessionFinderBackends       = map[string]sessionFinderBackend{}
+	defaultSessionFinderBackend string
+
+	// tried when the default backend can't be used at startup, see newSessionFinderWithFallback
+	fallbackSessionFinderBackend string
+
+	errUnknownAudioBackend = errors.New("unknown audio backend")
+)
+
+// registerSessionFinderBackend makes a backend available by name. it's meant to be called from init
+func registerSessionFinderBackend(name string, backend sessionFinderBackend, isDefault bool) {
+	sessionFinderBackends[name] = backend
+
+	if isDefault {
+		defaultSessionFinderBackend = name
+	}
+}
+
+// registerSessionFinderFallback makes a registered backend the one to fall back to when the default one fails
+func registerSessionFinderFallback(name string) {
+	fallbackSessionFinderBackend = name
+}
+
+// sessionFinderBackendNames returns the names of every registered backend, sorted
+func sessionFinderBackendNames() []string {
+	names := make([]string, 0, len(sessionFinderBackends))
+	for name := range sessionFinderBackends {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// newSessionFinder creates a session finder with the named backend, or the platform's default one for audioBackendAuto
+func newSessionFinder(logger *zap.SugaredLogger, backendName string) (SessionFinder, error) {
+	backendName = strings.ToLower(backendName)
+	if backendName == "" || backendName == audioBackendAuto {
+		backendName = defaultSessionFinderBackend
+	}
+
+	backend, ok := sessionFinderBackends[backendName]
+	if !ok {
+		return nil, fmt.Errorf("%q: %w", backendName, errUnknownAudioBackend)
+	}
+
+	logger.Debugw("Creating session finder", "backend", backendName)
+
+	return backend(logger)
+}
+
+// newSessionFinderWithFallback is newSessionFinder, except that the platform's fallback backend gets a go if the
+// default one fails and no backend was asked for in particular. it's only meant for startup: a finder that's
+// renewed later should stay with the default backend, whose server is likely just restarting
+func newSessionFinderWithFallback(logger *zap.SugaredLogger, backendName string) (SessionFinder, error) {
+	sessionFinder, err := newSessionFinder(logger, backendName)
+	if err == nil || fallbackSessionFinderBackend == "" {
+		return sessionFinder, err
+	}
+
+	if backendName = strings.ToLower(backendName); backendName != "" && backendName != audioBackendAuto {
+		return nil, err
+	}
+
+	logger.Warnw("Failed to create session finder with the default backend, falling back",
+		"backend", defaultSessionFinderBackend,
+		"fallback", fallbackSessionFinderBackend,
+		"error", err)
+
+	sessionFinder, fallbackErr := newSessionFinder(logger, fallbackSessionFinderBackend)
+	if fallbackErr != nil {
+		logger.Warnw("Failed to create session finder with the fallback backend", "error", fallbackErr)
+
+		// the default backend's problem is the one worth telling the user about
+		return nil, err
+	}
+
+	return sessionFinder, nil
+}
+
+// how many volume changes a session finder buffers before dropping them, in case its consumer falls behind
+const sessionVolumeChangeBufferSize = 64
@@ -0,0 +1,288 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// the alsa backend is for boxes without pulseaudio or pipewire (headless machines, minimal distros). plain alsa has
+// no idea which app plays what, so all it offers is the default card's playback and capture levels, as master and
+// mic. it goes through amixer rather than alsa-lib, which keeps deej free of cgo
+const (
+	amixerCommand = "amixer"
+
+	// amixer's own error when a control doesn't exist
+	amixerNoControl = "Unable to find simple control"
+)
+
+// simple controls that stand for the card's output and input level, most preferred first. cards name them
+// differently, and plenty only have some of them. "Mic" isn't one: on most cards it's the mic's loopback into the
+// speakers, not its recording level
+var (
+	alsaPlaybackControls = []string{"Master", "PCM", "Speaker", "Headphone", "Digital"}
+	alsaCaptureControls  = []string{"Capture", "Digital Capture"}
+)
+
+// "Simple mixer control 'Master',0" from amixer scontrols
+var alsaControlPattern = regexp.MustCompile(`^Simple mixer control '(.+)',(\d+)$`)
+
+// a channel's level ("[69%]") and switch ("[on]") in amixer get's output
+var (
+	alsaPercentPattern = regexp.MustCompile(`\[(\d+)%\]`)
+	alsaSwitchPattern  = regexp.MustCompile(`\[(on|off)\]`)
+)
+
+var errNoALSAControls = errors.New("no playback or capture controls found")
+
+type alsaSessionFinder struct {
+	logger        *zap.SugaredLogger
+	sessionLogger *zap.SugaredLogger
+}
+
+// alsaSession controls a simple mixer control on the default card
+type alsaSession struct {
+	baseSession
+
+	control string
+	capture bool
+}
+
+func init() {
+	registerSessionFinderBackend("alsa", newALSASessionFinder, false)
+	registerSessionFinderFallback("alsa")
+}
+
+func newALSASessionFinder(logger *zap.SugaredLogger) (SessionFinder, error) {
+	if _, err := exec.LookPath(amixerCommand); err != nil {
+		logger.Warnw("Failed to find amixer, is alsa-utils installed?", "error", err)
+		return nil, fmt.Errorf("find amixer: %w", err)
+	}
+
+	sf := &alsaSessionFinder{
+		logger:        logger.Named("session_finder"),
+		sessionLogger: logger.Named("sessions"),
+	}
+
+	// make sure there's something to control, rather than finding out on the first slider move
+	if _, err := sf.controls(); err != nil {
+		sf.logger.Warnw("Failed to list ALSA mixer controls", "error", err)
+		return nil, err
+	}
+
+	sf.logger.Debug("Created ALSA session finder instance")
+
+	return sf, nil
+}
+
+func (sf *alsaSessionFinder) GetAllSessions() ([]Session, error) {
+	controls, err := sf.controls()
+	if err != nil {
+		sf.logger.Warnw("Failed to list ALSA mixer controls", "error", err)
+		return nil, err
+	}
+
+	sessions := []Session{}
+
+	if control := firstALSAControl(controls, alsaPlaybackControls); control != "" {
+		sessions = append(sessions, newALSASession(sf.sessionLogger, masterSessionName, control, false))
+	}
+
+	if control := firstALSAControl(controls, alsaCaptureControls); control != "" {
+		sessions = append(sessions, newALSASession(sf.sessionLogger, inputSessionName, control, true))
+	}
+
+	if len(sessions) == 0 {
+		sf.logger.Warnw("Default card has no known playback or capture controls", "controls", controls)
+		return nil, errNoALSAControls
+	}
+
+	return sessions, nil
+}
+
+func (sf *alsaSessionFinder) Release() error {
+	sf.logger.Debug("Released ALSA session finder instance")
+
+	return nil
+}
+
+// controls returns the names of the default card's simple mixer controls
+func (sf *alsaSessionFinder) controls() (map[string]bool, error) {
+	output, err := exec.Command(amixerCommand, "scontrols").Output()
+	if err != nil {
+		return nil, fmt.Errorf("list mixer controls: %w", err)
+	}
+
+	controls := map[string]bool{}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		match := alsaControlPattern.FindStringSubmatch(strings.TrimSpace(line))
+
+		// only the first control of each name, which is what amixer picks when given just the name
+		if match != nil && match[2] == "0" {
+			controls[match[1]] = true
+		}
+	}
+
+	if len(controls) == 0 {
+		return nil, errNoALSAControls
+	}
+
+	return controls, nil
+}
+
+func firstALSAControl(controls map[string]bool, preferred []string) string {
+	for _, control := range preferred {
+		if controls[control] {
+			return control
+		}
+	}
+
+	return ""
+}
+
+func newALSASession(logger *zap.SugaredLogger, key string, control string, capture bool) *alsaSession {
+	s := &alsaSession{
+		control: control,
+		capture: capture,
+	}
+
+	s.logger = logger.Named(key)
+	s.master = true
+	s.name = key
+	s.humanReadableDesc = fmt.Sprintf("%s (alsa control %s)", key, control)
+
+	s.logger.Debugw(sessionCreationLogMessage, "session", s)
+
+	return s
+}
+
+// get returns the control's level (averaged over its channels) and whether it's switched on, i.e. not muted
+func (s *alsaSession) get() (float32, bool, error) {
+
+	// -M maps levels the way alsamixer shows them, which is closer to how loud they sound than the raw values
+	output, err := exec.Command(amixerCommand, "-M", "get", s.control).CombinedOutput()
+	if err != nil {
+		return 0, false, fmt.Errorf("get %s: %w: %s", s.control, err, strings.TrimSpace(string(output)))
+	}
+
+	total, channels := 0, 0
+	on := false
+
+	for _, line := range strings.Split(string(output), "\n") {
+
+		// capture controls list their playback side too, and vice versa
+		if s.capture != strings.Contains(line, "Capture") {
+			continue
+		}
+
+		if match := alsaPercentPattern.FindStringSubmatch(line); match != nil {
+			percent, _ := strconv.Atoi(match[1])
+			total += percent
+			channels++
+		}
+
+		if match := alsaSwitchPattern.FindStringSubmatch(line); match != nil && match[1] == "on" {
+			on = true
+		}
+	}
+
+	if channels == 0 {
+		return 0, false, fmt.Errorf("no levels in amixer output for %s", s.control)
+	}
+
+	return float32(total) / float32(channels) / 100, on, nil
+}
+
+// set runs amixer set on the session's control with the given arguments
+func (s *alsaSession) set(args ...string) error {
+	args = append([]string{"-q", "-M", "set", s.control}, args...)
+
+	if output, err := exec.Command(amixerCommand, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("set %s: %w: %s", s.control, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+func (s *alsaSession) GetVolume() float32 {
+	volume, _, err := s.get()
+	if err != nil {
+		s.logger.Warnw("Failed to get session volume", "error", err)
+	}
+
+	return volume
+}
+
+func (s *alsaSession) SetVolume(v float32) error {
+	level := fmt.Sprintf("%d%%", int(v*100+0.5))
+
+	// playback and capture controls can have both, so say which one's meant
+	direction := "playback"
+	if s.capture {
+		direction = "capture"
+	}
+
+	if err := s.set(direction, level); err != nil {
+		s.logger.Warnw("Failed to set session volume", "error", err)
+		return fmt.Errorf("adjust session volume: %w", err)
+	}
+
+	s.logger.Debugw("Adjusting session volume", "to", fmt.Sprintf("%.2f", v))
+
+	return nil
+}
+
+func (s *alsaSession) GetMute() bool {
+	_, on, err := s.get()
+	if err != nil {
+		s.logger.Warnw("Failed to get session mute state", "error", err)
+		return false
+	}
+
+	return !on
+}
+
+func (s *alsaSession) SetMute(m bool) error {
+	state := "unmute"
+	if m {
+		state = "mute"
+	}
+
+	// capture controls "mute" by not capturing
+	if s.capture {
+		state = "cap"
+		if m {
+			state = "nocap"
+		}
+	}
+
+	if err := s.set(state); err != nil {
+		s.logger.Warnw("Failed to set session mute state", "error", err)
+		return fmt.Errorf("adjust session mute state: %w", err)
+	}
+
+	s.logger.Debugw("Setting session mute state", "to", m)
+
+	return nil
+}
+
+// the default card's controls stay around for as long as it does
+func (s *alsaSession) IsAlive() bool {
+	_, _, err := s.get()
+
+	return err == nil || !strings.Contains(err.Error(), amixerNoControl)
+}
+
+func (s *alsaSession) Release() {
+	s.logger.Debug("Releasing audio session")
+}
+
+func (s *alsaSession) String() string {
+	return fmt.Sprintf(sessionStringFormat, s.humanReadableDesc, s.GetVolume())
+}
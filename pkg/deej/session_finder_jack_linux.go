@@ -0,0 +1,241 @@
+package deej
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// the jack backend makes jack clients (ardour, carla, a soft synth) targets by their client name, with master as the
+// default output. jack itself has no notion of a client's volume, so this only works with pipewire's jack
+// implementation, where every jack client is a node whose volume pipewire applies. like eq targets, nodes are read
+// with pw-dump and set with pw-cli, see pipewire_linux.go
+const pwDefaultSinkKey = "default.audio.sink"
+
+var errJACKNeedsPipeWire = errors.New("jack clients can only be controlled through pipewire's jack implementation")
+
+type jackSessionFinder struct {
+	logger        *zap.SugaredLogger
+	sessionLogger *zap.SugaredLogger
+}
+
+// jackSession controls the volume of a pipewire node, either a jack client's or the default sink's
+type jackSession struct {
+	baseSession
+
+	nodeID   int
+	channels int
+
+	// pw-dump is too slow to run on every GetVolume, so these are what deej last read or set
+	volume float32
+	muted  bool
+	lock   sync.Mutex
+
+	writer *pwPropsWriter
+}
+
+func init() {
+	registerSessionFinderBackend("jack", newJACKSessionFinder, false)
+}
+
+func newJACKSessionFinder(logger *zap.SugaredLogger) (SessionFinder, error) {
+	for _, tool := range []string{"pw-dump", "pw-cli"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			logger.Warnw("Failed to find pipewire's tools", "tool", tool, "error", err)
+			return nil, fmt.Errorf("find %s: %w", tool, errJACKNeedsPipeWire)
+		}
+	}
+
+	sf := &jackSessionFinder{
+		logger:        logger.Named("session_finder"),
+		sessionLogger: logger.Named("sessions"),
+	}
+
+	// pw-dump fails without a pipewire server to talk to, i.e. when jackd itself is running
+	if _, err := pwDump(); err != nil {
+		sf.logger.Warnw("Failed to list pipewire nodes", "error", err)
+		return nil, fmt.Errorf("%v: %w", err, errJACKNeedsPipeWire)
+	}
+
+	sf.logger.Debug("Created JACK session finder instance")
+
+	return sf, nil
+}
+
+func (sf *jackSessionFinder) GetAllSessions() ([]Session, error) {
+	objects, err := pwDump()
+	if err != nil {
+		sf.logger.Warnw("Failed to list pipewire nodes", "error", err)
+		return nil, err
+	}
+
+	defaultSink := pwDefaultSinkName(objects)
+	sessions := []Session{}
+
+	for _, object := range objects {
+		if object.Type != pwNodeType || pwNodeVolume(object) == nil {
+			continue
+		}
+
+		nodeName, _ := object.Info.Props["node.name"].(string)
+		clientAPI, _ := object.Info.Props["client.api"].(string)
+
+		switch {
+		case nodeName != "" && nodeName == defaultSink:
+			sessions = append(sessions, newJACKSession(sf.sessionLogger, object, masterSessionName, true))
+		case clientAPI == "jack" && nodeName != "":
+			sessions = append(sessions, newJACKSession(sf.sessionLogger, object, nodeName, false))
+		}
+	}
+
+	return sessions, nil
+}
+
+func (sf *jackSessionFinder) Release() error {
+	sf.logger.Debug("Released JACK session finder instance")
+
+	return nil
+}
+
+// pwDefaultSinkName returns the node name of the default output, as the session manager keeps it in its metadata
+func pwDefaultSinkName(objects []pwObject) string {
+	for _, object := range objects {
+		if object.Type != pwMetadataType || object.Info.Props["metadata.name"] != "default" {
+			continue
+		}
+
+		for _, entry := range object.Metadata {
+			if entry.Key != pwDefaultSinkKey {
+				continue
+			}
+
+			value := struct {
+				Name string `json:"name"`
+			}{}
+
+			if json.Unmarshal(entry.Value, &value) == nil {
+				return value.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+// pwNodeVolume returns the node's volume props, or nil for nodes without a volume of their own
+func pwNodeVolume(object pwObject) *pwProps {
+	for idx, props := range object.Info.Params.Props {
+		if len(props.ChannelVolumes) > 0 {
+			return &object.Info.Params.Props[idx]
+		}
+	}
+
+	return nil
+}
+
+func newJACKSession(logger *zap.SugaredLogger, object pwObject, name string, master bool) *jackSession {
+	props := pwNodeVolume(object)
+
+	s := &jackSession{
+		nodeID:   object.ID,
+		channels: len(props.ChannelVolumes),
+		volume:   pwVolumeToSession(props.ChannelVolumes),
+		muted:    props.Mute,
+	}
+
+	s.master = master
+	s.name = name
+	s.humanReadableDesc = fmt.Sprintf("%s (node %d)", name, object.ID)
+
+	if description, ok := object.Info.Props["node.description"].(string); ok && !master {
+		s.displayName = description
+	}
+
+	s.logger = logger.Named(s.Key())
+	s.writer = newPWPropsWriter(s.logger, func(props string) error { return pwSetProps(object.ID, props) })
+
+	s.logger.Debugw(sessionCreationLogMessage, "session", s)
+
+	return s
+}
+
+// pwVolumeToSession averages a node's channel volumes, which pipewire keeps linear, into the cubic scale pulseaudio
+// (and so the rest of deej) uses
+func pwVolumeToSession(channelVolumes []float64) float32 {
+	if len(channelVolumes) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, volume := range channelVolumes {
+		total += volume
+	}
+
+	return float32(math.Cbrt(total / float64(len(channelVolumes))))
+}
+
+func (s *jackSession) GetVolume() float32 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.volume
+}
+
+// volumes and mute states are set in the background, see pwPropsWriter. failing to set them is only logged
+func (s *jackSession) SetVolume(v float32) error {
+	linear := strconv.FormatFloat(math.Pow(float64(v), 3), 'f', 4, 64)
+
+	volumes := make([]string, s.channels)
+	for idx := range volumes {
+		volumes[idx] = linear
+	}
+
+	s.lock.Lock()
+	s.volume = v
+	s.lock.Unlock()
+
+	s.writer.set("channelVolumes", fmt.Sprintf("{ channelVolumes = [ %s ] }", strings.Join(volumes, " ")))
+
+	s.logger.Debugw("Adjusting session volume", "to", fmt.Sprintf("%.2f", v))
+
+	return nil
+}
+
+func (s *jackSession) GetMute() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.muted
+}
+
+func (s *jackSession) SetMute(m bool) error {
+	s.lock.Lock()
+	s.muted = m
+	s.lock.Unlock()
+
+	s.writer.set("mute", fmt.Sprintf("{ mute = %t }", m))
+
+	s.logger.Debugw("Setting session mute state", "to", m)
+
+	return nil
+}
+
+// clients that go away are dropped on the next refresh
+func (s *jackSession) IsAlive() bool {
+	return true
+}
+
+func (s *jackSession) Release() {
+	s.logger.Debug("Releasing audio session")
+}
+
+func (s *jackSession) String() string {
+	return fmt.Sprintf(sessionStringFormat, s.humanReadableDesc, s.GetVolume())
+}
@@ -0,0 +1,45 @@
+package deej
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// trimmed down from pw-dump, with a jack client, the default sink and another sink
+const testPWDump = `[
+	{"id": 30, "type": "PipeWire:Interface:Metadata", "info": {"props": {"metadata.name": "default"}},
+	 "metadata": [{"subject": 0, "key": "default.audio.sink", "type": "Spa:String:JSON", "value": {"name": "alsa_output.usb"}}]},
+	{"id": 41, "type": "PipeWire:Interface:Node", "info": {"props": {"node.name": "alsa_output.usb", "node.description": "USB Audio"},
+	 "params": {"Props": [{"volume": 1.0}, {"channelVolumes": [0.125, 0.125], "mute": false}]}}},
+	{"id": 42, "type": "PipeWire:Interface:Node", "info": {"props": {"node.name": "alsa_output.hdmi"},
+	 "params": {"Props": [{"channelVolumes": [1.0, 1.0]}]}}},
+	{"id": 57, "type": "PipeWire:Interface:Node", "info": {"props": {"node.name": "Carla", "node.description": "Carla Rack", "client.api": "jack"},
+	 "params": {"Props": [{"channelVolumes": [1.0, 0.0], "mute": true}]}}}
+]`
+
+func TestJACKSessionsFromPWDump(t *testing.T) {
+	objects := []pwObject{}
+	if err := json.Unmarshal([]byte(testPWDump), &objects); err != nil {
+		t.Fatalf("parse test pw-dump: %v", err)
+	}
+
+	if sink := pwDefaultSinkName(objects); sink != "alsa_output.usb" {
+		t.Fatalf("expected the usb sink as default, got %q", sink)
+	}
+
+	master := newJACKSession(zap.NewNop().Sugar(), objects[1], masterSessionName, true)
+	if master.Key() != masterSessionName || !volumesEqual(master.GetVolume(), 0.5) || master.GetMute() {
+		t.Errorf("expected master at 0.50 and unmuted, got %v", master)
+	}
+
+	carla := newJACKSession(zap.NewNop().Sugar(), objects[3], "Carla", false)
+	if carla.Key() != "carla" || carla.DisplayName() != "Carla Rack" || !carla.GetMute() {
+		t.Errorf("expected carla, muted and called Carla Rack, got %v (%q)", carla, carla.DisplayName())
+	}
+
+	if pwNodeVolume(objects[0]) != nil {
+		t.Errorf("expected metadata to have no volume")
+	}
+}
@@ -1,11 +1,13 @@
 package deej
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
-	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jfreymuth/pulse/proto"
@@ -26,12 +28,71 @@ type paSessionFinder struct {
 	logger        *zap.SugaredLogger
 	sessionLogger *zap.SugaredLogger
 
-	client *proto.Client
-	conn   net.Conn
+	client *paClient
+
+	// our own client, whose recording streams (see loudness_linux.go) aren't anyone's capture
+	clientIndex uint32
+
+	// sessions from the last GetAllSessions call, by their PulseAudio facility and index
+	watchedSessions map[paSessionIndex][]Session
+	watchedLock     sync.Mutex
+
+	subscribeEvents chan *proto.SubscribeEvent
+	peakStreams     *paPeakStreams
+	volumeChanges   chan Session
+	sessionRemovals chan Session
+
+	// disconnected is closed when the server stops answering or we're released, whichever comes first
+	disconnected     chan struct{}
+	disconnectedOnce sync.Once
+	released         chan struct{}
+
+	// volumeChanges and sessionRemovals are closed once, by the event handler on its way out
+	subscriptionsOnce sync.Once
 }
 
-func newSessionFinder(logger *zap.SugaredLogger) (SessionFinder, error) {
-	client, conn, err := proto.Connect("")
+// paSessionIndex identifies a sink, source, sink input or source output the way PulseAudio's subscription events do
+type paSessionIndex struct {
+	facility uint32
+	index    uint32
+}
+
+// pulse doesn't export these, see pulseaudio's def.h
+const (
+	paSubscriptionMaskSink         = 0x0001
+	paSubscriptionMaskSource       = 0x0002
+	paSubscriptionMaskSinkInput    = 0x0004
+	paSubscriptionMaskSourceOutput = 0x0008
+
+	paEventFacilityMask         = 0x000F
+	paEventFacilitySink         = 0x0000
+	paEventFacilitySource       = 0x0001
+	paEventFacilitySinkInput    = 0x0002
+	paEventFacilitySourceOutput = 0x0003
+
+	paEventTypeMask   = 0x0030
+	paEventTypeChange = 0x0010
+	paEventTypeRemove = 0x0020
+)
+
+// the client doesn't notice when the server goes away until a request hangs, so ask it something every so often
+const (
+	paHealthCheckInterval = 5 * time.Second
+	paHealthCheckTimeout  = 2 * time.Second
+)
+
+// how long listing every session may take altogether
+const paGetAllSessionsTimeout = 5 * time.Second
+
+func init() {
+	registerSessionFinderBackend("pulse", newPASessionFinder, true)
+
+	// pipewire serves the pulseaudio protocol through pipewire-pulse, which is all deej needs from it
+	registerSessionFinderBackend("pipewire", newPASessionFinder, false)
+}
+
+func newPASessionFinder(logger *zap.SugaredLogger) (SessionFinder, error) {
+	protoClient, conn, err := proto.Connect("")
 	if err != nil {
 		logger.Warnw("Failed to establish PulseAudio connection", "error", err)
 		return nil, fmt.Errorf("establish PulseAudio connection: %w", err)
@@ -44,17 +105,32 @@ func newSessionFinder(logger *zap.SugaredLogger) (SessionFinder, error) {
 	}
 	reply := proto.SetClientNameReply{}
 
+	client := newPAClient(protoClient, conn)
 	if err := client.Request(&request, &reply); err != nil {
 		return nil, err
 	}
 
 	sf := &paSessionFinder{
-		logger:        logger.Named("session_finder"),
-		sessionLogger: logger.Named("sessions"),
-		client:        client,
-		conn:          conn,
+		logger:          logger.Named("session_finder"),
+		sessionLogger:   logger.Named("sessions"),
+		client:          client,
+		clientIndex:     reply.ClientIndex,
+		watchedSessions: map[paSessionIndex][]Session{},
+		subscribeEvents: make(chan *proto.SubscribeEvent, sessionVolumeChangeBufferSize),
+		peakStreams:     newPAPeakStreams(),
+		volumeChanges:   make(chan Session, sessionVolumeChangeBufferSize),
+		sessionRemovals: make(chan Session, sessionVolumeChangeBufferSize),
+		disconnected:    make(chan struct{}),
+		released:        make(chan struct{}),
+	}
+
+	// not being able to watch for volume changes isn't fatal, it only affects features that follow them
+	if err := sf.subscribe(); err != nil {
+		sf.logger.Warnw("Failed to subscribe to PulseAudio events", "error", err)
 	}
 
+	go sf.watchConnection()
+
 	sf.logger.Debug("Created PA session finder instance")
 
 	return sf, nil
@@ -64,19 +140,36 @@ func (sf *paSessionFinder) GetAllSessions() ([]Session, error) {
 	sf.logger.Debug("Starting GetAllSessions")
 	sessions := []Session{}
 
+	ctx, cancel := context.WithTimeout(context.Background(), paGetAllSessionsTimeout)
+	defer cancel()
+
 	// get the master sink session
 	sf.logger.Debug("Getting master sink session")
-	masterSink, err := sf.getMasterSinkSession()
+	masterSink, err := sf.getMasterSinkSession(ctx)
 	if err == nil {
 		sessions = append(sessions, masterSink)
+		sessions = append(sessions, masterSink.channelSessions(sf.sessionLogger)...)
 		sf.logger.Debug("Added master sink session")
 	} else {
 		sf.logger.Warnw("Failed to get master audio sink session", "error", err)
 	}
 
+	// sinks' monitors, for listening through to them (i.e. a loopback to hear another pc)
+	defaultSinkIndex := uint32(proto.Undefined)
+	if masterSink != nil {
+		defaultSinkIndex = masterSink.streamIndex
+	}
+
+	monitorSessions, err := sf.getMonitorSessions(ctx, defaultSinkIndex)
+	if err == nil {
+		sessions = append(sessions, monitorSessions...)
+	} else {
+		sf.logger.Warnw("Failed to get sink monitor sessions", "error", err)
+	}
+
 	// get the master source session
 	sf.logger.Debug("Getting master source session")
-	masterSource, err := sf.getMasterSourceSession()
+	masterSource, err := sf.getMasterSourceSession(ctx)
 	if err == nil {
 		sessions = append(sessions, masterSource)
 		sf.logger.Debug("Added master source session")
@@ -86,17 +179,51 @@ func (sf *paSessionFinder) GetAllSessions() ([]Session, error) {
 
 	// enumerate sink inputs and add sessions along the way
 	sf.logger.Debug("Enumerating sink inputs")
-	if err := sf.enumerateAndAddSessions(&sessions); err != nil {
+	if err := sf.enumerateAndAddSessions(ctx, &sessions); err != nil {
 		sf.logger.Warnw("Failed to enumerate audio sessions", "error", err)
 		return nil, fmt.Errorf("enumerate audio sessions: %w", err)
 	}
 
+	// apps' recording streams, for capture targets. losing them isn't worth losing every other session over
+	sf.logger.Debug("Enumerating source outputs")
+	if err := sf.enumerateAndAddCaptureSessions(ctx, &sessions); err != nil {
+		sf.logger.Warnw("Failed to enumerate capture sessions", "error", err)
+	}
+
+	// equalizer bands aren't pulse streams, but sliders control them all the same
+	eqSessions, err := findEQSessions(sf.sessionLogger)
+	if err != nil {
+		sf.logger.Warnw("Failed to find equalizer bands", "error", err)
+	}
+
+	sessions = append(sessions, eqSessions...)
+
+	sf.watch(sessions)
+
 	sf.logger.Debugw("GetAllSessions complete", "sessionCount", len(sessions))
 	return sessions, nil
 }
 
+// SubscribeToVolumeChanges returns a channel that receives sessions whenever their volume changes
+func (sf *paSessionFinder) SubscribeToVolumeChanges() <-chan Session {
+	return sf.volumeChanges
+}
+
+// SubscribeToSessionRemovals returns a channel that receives sessions whose stream or device went away
+func (sf *paSessionFinder) SubscribeToSessionRemovals() <-chan Session {
+	return sf.sessionRemovals
+}
+
+// Disconnected returns a channel that's closed once the PulseAudio server stops answering
+func (sf *paSessionFinder) Disconnected() <-chan struct{} {
+	return sf.disconnected
+}
+
 func (sf *paSessionFinder) Release() error {
-	if err := sf.conn.Close(); err != nil {
+	close(sf.released)
+	sf.disconnectedOnce.Do(func() { close(sf.disconnected) })
+
+	if err := sf.client.drop(errors.New("released")); err != nil {
 		sf.logger.Warnw("Failed to close PulseAudio connection", "error", err)
 		return fmt.Errorf("close PulseAudio connection: %w", err)
 	}
@@ -106,7 +233,57 @@ func (sf *paSessionFinder) Release() error {
 	return nil
 }
 
-func (sf *paSessionFinder) getMasterSinkSession() (Session, error) {
+func (sf *paSessionFinder) watchConnection() {
+	ticker := time.NewTicker(paHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sf.released:
+			return
+
+		case <-ticker.C:
+			if err := sf.ping(); err != nil {
+				sf.reportDisconnected(err)
+				return
+			}
+
+		// any request timing out drops the connection, there's no need to wait for the next ping to notice
+		case <-sf.client.Dropped():
+			sf.reportDisconnected(sf.client.dropReason)
+			return
+		}
+	}
+}
+
+func (sf *paSessionFinder) reportDisconnected(err error) {
+
+	// releasing closes the connection, which isn't worth reporting
+	select {
+	case <-sf.released:
+		return
+	default:
+	}
+
+	sf.logger.Warnw("PulseAudio server stopped answering", "error", err)
+	sf.disconnectedOnce.Do(func() { close(sf.disconnected) })
+}
+
+func (sf *paSessionFinder) ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), paHealthCheckTimeout)
+	defer cancel()
+
+	request := proto.GetServerInfo{}
+	reply := proto.GetServerInfoReply{}
+
+	if err := sf.client.RequestContext(ctx, &request, &reply); err != nil {
+		return fmt.Errorf("get server info: %w", err)
+	}
+
+	return nil
+}
+
+func (sf *paSessionFinder) getMasterSinkSession(ctx context.Context) (*masterSession, error) {
 	sf.logger.Debug("Requesting master sink info")
 
 	request := proto.GetSinkInfo{
@@ -114,32 +291,20 @@ func (sf *paSessionFinder) getMasterSinkSession() (Session, error) {
 	}
 	reply := proto.GetSinkInfoReply{}
 
-	// Use a channel to implement timeout
-	done := make(chan error, 1)
-	go func() {
-		done <- sf.client.Request(&request, &reply)
-	}()
-
-	// Wait for either completion or timeout
-	select {
-	case err := <-done:
-		if err != nil {
-			sf.logger.Warnw("Failed to get master sink info", "error", err)
-			return nil, fmt.Errorf("get master sink info: %w", err)
-		}
-	case <-time.After(2 * time.Second):
-		sf.logger.Warnw("Timeout getting master sink info")
-		return nil, fmt.Errorf("timeout getting master sink info")
+	if err := sf.client.RequestContext(ctx, &request, &reply); err != nil {
+		sf.logger.Warnw("Failed to get master sink info", "error", err)
+		return nil, fmt.Errorf("get master sink info: %w", err)
 	}
 
 	sf.logger.Debug("Got master sink info, creating session")
 	// create the master sink session
 	sink := newMasterSession(sf.sessionLogger, sf.client, reply.SinkIndex, reply.Channels, true)
+	sink.channelMap = reply.ChannelMap
 
 	return sink, nil
 }
 
-func (sf *paSessionFinder) getMasterSourceSession() (Session, error) {
+func (sf *paSessionFinder) getMasterSourceSession(ctx context.Context) (Session, error) {
 	sf.logger.Debug("Requesting master source info")
 
 	request := proto.GetSourceInfo{
@@ -147,22 +312,9 @@ func (sf *paSessionFinder) getMasterSourceSession() (Session, error) {
 	}
 	reply := proto.GetSourceInfoReply{}
 
-	// Use a channel to implement timeout
-	done := make(chan error, 1)
-	go func() {
-		done <- sf.client.Request(&request, &reply)
-	}()
-
-	// Wait for either completion or timeout
-	select {
-	case err := <-done:
-		if err != nil {
-			sf.logger.Warnw("Failed to get master source info", "error", err)
-			return nil, fmt.Errorf("get master source info: %w", err)
-		}
-	case <-time.After(2 * time.Second):
-		sf.logger.Warnw("Timeout getting master source info")
-		return nil, fmt.Errorf("timeout getting master source info")
+	if err := sf.client.RequestContext(ctx, &request, &reply); err != nil {
+		sf.logger.Warnw("Failed to get master source info", "error", err)
+		return nil, fmt.Errorf("get master source info: %w", err)
 	}
 
 	sf.logger.Debug("Got master source info, creating session")
@@ -172,7 +324,52 @@ func (sf *paSessionFinder) getMasterSourceSession() (Session, error) {
 	return source, nil
 }
 
-func (sf *paSessionFinder) enumerateAndAddSessions(sessions *[]Session) error {
+// getSinkNames returns the name of every sink, by its index
+func (sf *paSessionFinder) getSinkNames(ctx context.Context) (map[uint32]string, error) {
+	request := proto.GetSinkInfoList{}
+	reply := proto.GetSinkInfoListReply{}
+
+	if err := sf.client.RequestContext(ctx, &request, &reply); err != nil {
+		return nil, fmt.Errorf("get sink list: %w", err)
+	}
+
+	sinkNames := map[uint32]string{}
+	for _, sink := range reply {
+		sinkNames[sink.SinkIndex] = sink.SinkName
+	}
+
+	return sinkNames, nil
+}
+
+// getMonitorSessions returns a session for every sink's monitor source, plus one more for the default sink's
+func (sf *paSessionFinder) getMonitorSessions(ctx context.Context, defaultSinkIndex uint32) ([]Session, error) {
+	request := proto.GetSinkInfoList{}
+	reply := proto.GetSinkInfoListReply{}
+
+	if err := sf.client.RequestContext(ctx, &request, &reply); err != nil {
+		return nil, fmt.Errorf("get sink list: %w", err)
+	}
+
+	sessions := []Session{}
+
+	for _, sink := range reply {
+		if sink.MonitorSourceIndex == proto.Undefined {
+			continue
+		}
+
+		sessions = append(sessions, newMonitorSession(sf.sessionLogger, sf.client, sink.MonitorSourceIndex,
+			sink.Channels, monitorSessionPrefix+sink.SinkName))
+
+		if sink.SinkIndex == defaultSinkIndex {
+			sessions = append(sessions, newMonitorSession(sf.sessionLogger, sf.client, sink.MonitorSourceIndex,
+				sink.Channels, monitorSessionName))
+		}
+	}
+
+	return sessions, nil
+}
+
+func (sf *paSessionFinder) enumerateAndAddSessions(ctx context.Context, sessions *[]Session) error {
 	sf.logger.Debug("Starting enumerateAndAddSessions")
 
 	request := proto.GetSinkInputInfoList{}
@@ -180,26 +377,20 @@ func (sf *paSessionFinder) enumerateAndAddSessions(sessions *[]Session) error {
 
 	sf.logger.Debug("Requesting sink input list from PulseAudio")
 
-	// Use a channel to implement timeout
-	done := make(chan error, 1)
-	go func() {
-		done <- sf.client.Request(&request, &reply)
-	}()
-
-	// Wait for either completion or timeout
-	select {
-	case err := <-done:
-		if err != nil {
-			sf.logger.Warnw("Failed to get sink input list", "error", err)
-			return fmt.Errorf("get sink input list: %w", err)
-		}
-	case <-time.After(2 * time.Second):
-		sf.logger.Warnw("Timeout getting sink input list")
-		return fmt.Errorf("timeout getting sink input list")
+	if err := sf.client.RequestContext(ctx, &request, &reply); err != nil {
+		sf.logger.Warnw("Failed to get sink input list", "error", err)
+		return fmt.Errorf("get sink input list: %w", err)
 	}
 
 	sf.logger.Debugw("Got sink input list", "count", len(reply))
 
+	// for telling apart the sessions of an app that plays on more than one sink. they're just left without one
+	// if the sinks can't be listed
+	sinkNames, err := sf.getSinkNames(ctx)
+	if err != nil {
+		sf.logger.Debugw("Failed to get sink names for sink inputs", "error", err)
+	}
+
 	for i, info := range reply {
 		sf.logger.Debugw("Processing sink input", "index", i, "sinkInputIndex", info.SinkInputIndex)
 
@@ -220,7 +411,18 @@ func (sf *paSessionFinder) enumerateAndAddSessions(sessions *[]Session) error {
 		var pid uint32 = 0
 
 		// create the deej session object
-		newSession := newPASession(sf.sessionLogger, sf.client, info.SinkInputIndex, info.Channels, name.String(), pid)
+		newSession := newPASession(sf.sessionLogger, sf.client, sf.peakStreams, info.SinkInputIndex, info.Channels,
+			name.String(), pid)
+
+		// apps that bother to say what they're playing, for role targets
+		if role, ok := info.Properties["media.role"]; ok {
+			newSession.role = normalizeSessionRole(role.String())
+		}
+
+		newSession.displayName = paDisplayName(info.Properties)
+		newSession.outputName = sinkNames[info.SinkIndex]
+		newSession.restoreEntryName = streamRestoreEntryName(info.Properties)
+		newSession.channelMap = info.ChannelMap
 
 		// add it to our slice
 		*sessions = append(*sessions, newSession)
@@ -230,3 +432,185 @@ func (sf *paSessionFinder) enumerateAndAddSessions(sessions *[]Session) error {
 	sf.logger.Debug("Finished enumerateAndAddSessions")
 	return nil
 }
+
+// enumerateAndAddCaptureSessions adds a session for every app's recording stream, keyed e.g. "capture:obs"
+func (sf *paSessionFinder) enumerateAndAddCaptureSessions(ctx context.Context, sessions *[]Session) error {
+	request := proto.GetSourceOutputInfoList{}
+	reply := proto.GetSourceOutputInfoListReply{}
+
+	if err := sf.client.RequestContext(ctx, &request, &reply); err != nil {
+		return fmt.Errorf("get source output list: %w", err)
+	}
+
+	sf.logger.Debugw("Got source output list", "count", len(reply))
+
+	for _, info := range reply {
+		if info.ClientIndex == sf.clientIndex {
+			continue
+		}
+
+		// some streams (i.e. peak meters) can't have their volume changed at all
+		if !info.VolumeWritable {
+			continue
+		}
+
+		name := paStringProperty(info.Properties, "application.process.binary")
+		if name == "" {
+			name = paStringProperty(info.Properties, "application.name")
+		}
+
+		if name == "" {
+			sf.logger.Debugw("Skipping source output without a process or application name",
+				"sourceOutputIndex", info.SourceOutpuIndex)
+			continue
+		}
+
+		newSession := newPACaptureSession(sf.sessionLogger, sf.client, info.SourceOutpuIndex, info.Channels, name)
+		newSession.displayName = paDisplayName(info.Properties)
+
+		*sessions = append(*sessions, newSession)
+		sf.logger.Debugw("Added capture session", "name", name)
+	}
+
+	return nil
+}
+
+func (sf *paSessionFinder) subscribe() error {
+
+	// the callback runs on the client's read loop, which must never block on (or make) a request.
+	// hand events off to a goroutine that's allowed to do both
+	sf.client.Callback = func(message interface{}) {
+		switch message := message.(type) {
+		case *proto.SubscribeEvent:
+			select {
+			case sf.subscribeEvents <- message:
+			default:
+			}
+
+		case *proto.DataPacket:
+			sf.peakStreams.deliver(message)
+		}
+	}
+
+	go sf.handleSubscribeEvents()
+
+	request := proto.Subscribe{
+		Mask: paSubscriptionMaskSink | paSubscriptionMaskSource | paSubscriptionMaskSinkInput |
+			paSubscriptionMaskSourceOutput,
+	}
+
+	if err := sf.client.Request(&request, nil); err != nil {
+		return fmt.Errorf("subscribe to events: %w", err)
+	}
+
+	return nil
+}
+
+func (sf *paSessionFinder) handleSubscribeEvents() {
+
+	// whoever's watching is done once we're released
+	defer sf.subscriptionsOnce.Do(func() {
+		close(sf.volumeChanges)
+		close(sf.sessionRemovals)
+	})
+
+	// subscribeEvents is never closed, the client's read loop may still be delivering to it while we're released
+	for {
+		var event *proto.SubscribeEvent
+
+		select {
+		case event = <-sf.subscribeEvents:
+		case <-sf.released:
+			return
+		}
+
+		eventType := event.Event & paEventTypeMask
+		if eventType != paEventTypeChange && eventType != paEventTypeRemove {
+			continue
+		}
+
+		index := paSessionIndex{
+			facility: event.Event & paEventFacilityMask,
+			index:    event.Index,
+		}
+
+		sf.watchedLock.Lock()
+		sessions := sf.watchedSessions[index]
+		if eventType == paEventTypeRemove {
+			delete(sf.watchedSessions, index)
+		}
+		sf.watchedLock.Unlock()
+
+		target := sf.volumeChanges
+		if eventType == paEventTypeRemove {
+			target = sf.sessionRemovals
+		}
+
+		for _, session := range sessions {
+			select {
+			case target <- session:
+			default:
+				sf.logger.Debugw("Session event channel full, dropping event", "session", session.Key())
+			}
+		}
+	}
+}
+
+// watch replaces the set of sessions we report volume changes for
+func (sf *paSessionFinder) watch(sessions []Session) {
+	watched := map[paSessionIndex][]Session{}
+
+	for _, session := range sessions {
+		var index paSessionIndex
+
+		switch s := session.(type) {
+		case *paSession:
+			index = paSessionIndex{paEventFacilitySinkInput, s.sinkInputIndex}
+		case *paCaptureSession:
+			index = paSessionIndex{paEventFacilitySourceOutput, s.sourceOutputIndex}
+		case *masterSession:
+			if s.isOutput {
+				index = paSessionIndex{paEventFacilitySink, s.streamIndex}
+			} else {
+				index = paSessionIndex{paEventFacilitySource, s.streamIndex}
+			}
+		default:
+			continue
+		}
+
+		watched[index] = append(watched[index], session)
+	}
+
+	sf.watchedLock.Lock()
+	sf.watchedSessions = watched
+	sf.watchedLock.Unlock()
+}
+
+// paDisplayName returns the friendly name an app gave its stream: its application name, or failing that its icon
+// name made presentable ("google-chrome" becomes "Google Chrome"). it's empty if the app gave neither
+func paDisplayName(properties proto.PropList) string {
+	if name := paStringProperty(properties, "application.name"); name != "" {
+		return name
+	}
+
+	if iconName := paStringProperty(properties, "application.icon_name"); iconName != "" {
+		return prettyProcessName(strings.NewReplacer("-", " ", "_", " ").Replace(iconName))
+	}
+
+	return ""
+}
+
+// paStringProperty returns a string property, or an empty string if it's missing or holds something else
+func paStringProperty(properties proto.PropList, key string) string {
+	entry, ok := properties[key]
+	if !ok {
+		return ""
+	}
+
+	value := entry.String()
+	if value == "<not a string>" {
+		return ""
+	}
+
+	return strings.TrimSpace(value)
+}
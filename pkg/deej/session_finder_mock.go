@@ -0,0 +1,134 @@
+package deej
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/omriharel/deej/pkg/deej/util"
+)
+
+// the mock backend has a fixed set of in-memory sessions that only log what's done to them. it lets deej run where
+// there's no audio server, e.g. to replay a slider trace (see --replay-trace) or to try out a config
+
+// mockSessionApps are the apps the mock backend pretends are playing, by process name and display name
+var mockSessionApps = [][2]string{
+	{"spotify", "Spotify"},
+	{"chrome", "Google Chrome"},
+	{"discord", "Discord"},
+}
+
+type mockSessionFinder struct {
+	logger *zap.SugaredLogger
+
+	// the same sessions are found every time, so their volumes stick around
+	sessions []Session
+}
+
+type mockSession struct {
+	baseSession
+
+	volume float32
+	muted  bool
+	lock   sync.Mutex
+}
+
+func init() {
+	registerSessionFinderBackend("mock", newMockSessionFinder, false)
+}
+
+func newMockSessionFinder(logger *zap.SugaredLogger) (SessionFinder, error) {
+	sf := &mockSessionFinder{
+		logger: logger.Named("session_finder"),
+	}
+
+	sessionLogger := logger.Named("sessions")
+
+	sf.sessions = append(sf.sessions,
+		newMockSession(sessionLogger, masterSessionName, "", true, false),
+		newMockSession(sessionLogger, inputSessionName, "", true, false),
+		newMockSession(sessionLogger, systemSessionName, "", false, true))
+
+	for _, app := range mockSessionApps {
+		processName := app[0]
+		if !util.Linux() {
+			processName += ".exe"
+		}
+
+		sf.sessions = append(sf.sessions, newMockSession(sessionLogger, processName, app[1], false, false))
+	}
+
+	sf.logger.Debug("Created mock session finder instance")
+
+	return sf, nil
+}
+
+func (sf *mockSessionFinder) GetAllSessions() ([]Session, error) {
+	return sf.sessions, nil
+}
+
+func (sf *mockSessionFinder) Release() error {
+	sf.logger.Debug("Released mock session finder instance")
+
+	return nil
+}
+
+func newMockSession(logger *zap.SugaredLogger, name string, displayName string, master bool, system bool) *mockSession {
+	s := &mockSession{volume: 1}
+
+	s.name = name
+	s.humanReadableDesc = name
+	s.displayName = displayName
+	s.master = master
+	s.system = system
+
+	s.logger = logger.Named(s.Key())
+	s.logger.Debugw(sessionCreationLogMessage, "session", s)
+
+	return s
+}
+
+func (s *mockSession) GetVolume() float32 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.volume
+}
+
+func (s *mockSession) SetVolume(v float32) error {
+	s.lock.Lock()
+	s.volume = v
+	s.lock.Unlock()
+
+	s.logger.Infow("Adjusting session volume", "to", fmt.Sprintf("%.2f", v))
+
+	return nil
+}
+
+func (s *mockSession) GetMute() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.muted
+}
+
+func (s *mockSession) SetMute(m bool) error {
+	s.lock.Lock()
+	s.muted = m
+	s.lock.Unlock()
+
+	s.logger.Infow("Setting session mute state", "to", m)
+
+	return nil
+}
+
+func (s *mockSession) IsAlive() bool {
+	return true
+}
+
+func (s *mockSession) Release() {}
+
+func (s *mockSession) String() string {
+	return fmt.Sprintf(sessionStringFormat, s.humanReadableDesc, s.GetVolume())
+}
@@ -0,0 +1,52 @@
+package deej
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNewSessionFinder(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	if _, err := newSessionFinder(logger, "oss"); !errors.Is(err, errUnknownAudioBackend) {
+		t.Errorf("expected an unknown backend to fail, got %v", err)
+	}
+
+	if defaultSessionFinderBackend == "" {
+		t.Errorf("expected the platform to register a default backend, got %v", sessionFinderBackendNames())
+	}
+
+	finder, err := newSessionFinder(logger, "Mock")
+	if err != nil {
+		t.Fatalf("create mock session finder: %v", err)
+	}
+	defer finder.Release()
+
+	sessions, err := finder.GetAllSessions()
+	if err != nil {
+		t.Fatalf("get mock sessions: %v", err)
+	}
+
+	keys := map[string]Session{}
+	for _, session := range sessions {
+		keys[session.Key()] = session
+	}
+
+	for _, key := range []string{masterSessionName, inputSessionName, systemSessionName} {
+		if _, ok := keys[key]; !ok {
+			t.Errorf("expected a %q session, got %v", key, sessions)
+		}
+	}
+
+	// volumes stick around between lookups, like a real backend's
+	keys[masterSessionName].SetVolume(0.3)
+
+	sessions, _ = finder.GetAllSessions()
+	for _, session := range sessions {
+		if session.Key() == masterSessionName && !volumesEqual(session.GetVolume(), 0.3) {
+			t.Errorf("expected master to keep its volume, got %.2f", session.GetVolume())
+		}
+	}
+}
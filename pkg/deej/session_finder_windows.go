@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unsafe"
@@ -27,6 +28,15 @@ type wcaSessionFinder struct {
 	// our master input and output sessions
 	masterOut *masterSession
 	masterIn  *masterSession
+
+	volumeChanges   chan Session
+	sessionRemovals chan Session
+
+	// closed on release, along with the channels above. windows' notification threads report under reportLock's
+	// read side, so they never send on a closed channel
+	released    chan struct{}
+	releaseOnce sync.Once
+	reportLock  sync.RWMutex
 }
 
 const (
@@ -40,13 +50,26 @@ const (
 
 	// prefix for device sessions in logger
 	deviceSessionFormat = "device.%s"
+
+	// undocumented, see enumerateAndAddProcessSessions
+	audclntSNoCurrentProcess = 0x889000D
+
+	// AUDCLNT_E_DEVICE_INVALIDATED, the device the session was on was removed or disabled
+	audclntEDeviceInvalidated = 0x88890004
 )
 
-func newSessionFinder(logger *zap.SugaredLogger) (SessionFinder, error) {
+func init() {
+	registerSessionFinderBackend("wasapi", newWCASessionFinder, true)
+}
+
+func newWCASessionFinder(logger *zap.SugaredLogger) (SessionFinder, error) {
 	sf := &wcaSessionFinder{
-		logger:        logger.Named("session_finder"),
-		sessionLogger: logger.Named("sessions"),
-		eventCtx:      ole.NewGUID(myteriousGUID),
+		logger:          logger.Named("session_finder"),
+		sessionLogger:   logger.Named("sessions"),
+		eventCtx:        ole.NewGUID(myteriousGUID),
+		volumeChanges:   make(chan Session, sessionVolumeChangeBufferSize),
+		sessionRemovals: make(chan Session, sessionVolumeChangeBufferSize),
+		released:        make(chan struct{}),
 	}
 
 	sf.logger.Debug("Created WCA session finder instance")
@@ -55,6 +78,8 @@ func newSessionFinder(logger *zap.SugaredLogger) (SessionFinder, error) {
 }
 
 func (sf *wcaSessionFinder) GetAllSessions() ([]Session, error) {
+	defer audioCalls.time("GetAllSessions")()
+
 	sessions := []Session{}
 
 	// we must call this every time we're about to list devices, i think. could be wrong
@@ -149,6 +174,16 @@ func (sf *wcaSessionFinder) GetAllSessions() ([]Session, error) {
 
 func (sf *wcaSessionFinder) Release() error {
 
+	// whoever's watching is done once we're released
+	sf.releaseOnce.Do(func() {
+		sf.reportLock.Lock()
+		defer sf.reportLock.Unlock()
+
+		close(sf.released)
+		close(sf.volumeChanges)
+		close(sf.sessionRemovals)
+	})
+
 	// skip unregistering the mmnotificationclient, as it's not implemented in go-wca
 	if sf.mmDeviceEnumerator != nil {
 		sf.mmDeviceEnumerator.Release()
@@ -179,6 +214,7 @@ func (sf *wcaSessionFinder) getDeviceEnumerator() error {
 }
 
 func (sf *wcaSessionFinder) getDefaultAudioEndpoints() (*wca.IMMDevice, *wca.IMMDevice, error) {
+	defer audioCalls.time("GetDefaultAudioEndpoint")()
 
 	// get the default audio endpoints as IMMDevice instances
 	var mmOutDevice *wca.IMMDevice
@@ -237,6 +273,10 @@ func (sf *wcaSessionFinder) getMasterSession(mmDevice *wca.IMMDevice, key string
 		return nil, fmt.Errorf("create master session: %w", err)
 	}
 
+	if err := master.watchVolume(func() { sf.reportVolumeChange(master) }); err != nil {
+		sf.logger.Debugw("Failed to watch master session volume", "key", key, "error", err)
+	}
+
 	return master, nil
 }
 
@@ -398,7 +438,11 @@ func (sf *wcaSessionFinder) enumerateAndAddProcessSessions(
 	// get its IAudioSessionEnumerator
 	var sessionEnumerator *wca.IAudioSessionEnumerator
 
-	if err := audioSessionManager2.GetSessionEnumerator(&sessionEnumerator); err != nil {
+	stopTiming := audioCalls.time("GetSessionEnumerator")
+	err := audioSessionManager2.GetSessionEnumerator(&sessionEnumerator)
+	stopTiming()
+
+	if err != nil {
 		return err
 	}
 	defer sessionEnumerator.Release()
@@ -450,10 +494,9 @@ func (sf *wcaSessionFinder) enumerateAndAddProcessSessions(
 			// if this is the system sounds session, GetProcessId will error with an undocumented
 			// AUDCLNT_S_NO_CURRENT_PROCESS (0x889000D) - this is fine, we actually want to treat it a bit differently
 			// The first part of this condition will be true if the call to IsSystemSoundsSession fails
-			// The second part will be true if the original error mesage from GetProcessId doesn't contain this magical
-			// error code (in decimal format).
+			// The second part will be true if the original error from GetProcessId isn't this magical error code
 			isSystemSoundsErr := audioSessionControl2.IsSystemSoundsSession()
-			if isSystemSoundsErr != nil && !strings.Contains(err.Error(), "143196173") {
+			if isSystemSoundsErr != nil && !isOleError(err, audclntSNoCurrentProcess) {
 
 				// of course, if it's not the system sounds session, we got a problem
 				sf.logger.Warnw("Failed to query session's pid",
@@ -503,6 +546,16 @@ func (sf *wcaSessionFinder) enumerateAndAddProcessSessions(
 			continue
 		}
 
+		if err := newSession.watch(
+			func() { sf.reportVolumeChange(newSession) },
+			func() { sf.reportSessionRemoval(newSession) },
+		); err != nil {
+			sf.logger.Debugw("Failed to watch session volume", "pid", pid, "error", err)
+		}
+
+		// for telling apart the sessions of an app that plays on more than one device
+		newSession.outputName = endpointFriendlyName
+
 		// add it to our slice
 		*sessions = append(*sessions, newSession)
 	}
@@ -510,6 +563,42 @@ func (sf *wcaSessionFinder) enumerateAndAddProcessSessions(
 	return nil
 }
 
+// SubscribeToVolumeChanges returns a channel that receives sessions whenever something else changes their volume
+func (sf *wcaSessionFinder) SubscribeToVolumeChanges() <-chan Session {
+	return sf.volumeChanges
+}
+
+// SubscribeToSessionRemovals returns a channel that receives process sessions once they expire
+func (sf *wcaSessionFinder) SubscribeToSessionRemovals() <-chan Session {
+	return sf.sessionRemovals
+}
+
+// this is called from windows' own notification threads, so it must never block
+func (sf *wcaSessionFinder) reportVolumeChange(session Session) {
+	sf.report(sf.volumeChanges, session)
+}
+
+// same as reportVolumeChange
+func (sf *wcaSessionFinder) reportSessionRemoval(session Session) {
+	sf.report(sf.sessionRemovals, session)
+}
+
+func (sf *wcaSessionFinder) report(target chan Session, session Session) {
+	sf.reportLock.RLock()
+	defer sf.reportLock.RUnlock()
+
+	select {
+	case <-sf.released:
+		return
+	default:
+	}
+
+	select {
+	case target <- session:
+	default:
+	}
+}
+
 func (sf *wcaSessionFinder) defaultDeviceChangedCallback(
 	this *wca.IMMNotificationClient,
 	EDataFlow, eRole uint32,
@@ -539,3 +628,9 @@ func (sf *wcaSessionFinder) defaultDeviceChangedCallback(
 func (sf *wcaSessionFinder) noopCallback() (hResult uintptr) {
 	return
 }
+
+// isOleError returns true if err is a COM error with the given HRESULT
+func isOleError(err error, code uintptr) bool {
+	oleError := &ole.OleError{}
+	return errors.As(err, &oleError) && oleError.Code() == code
+}
@@ -0,0 +1,117 @@
+package deej
+
+import (
+	"fmt"
+	"strings"
+)
+
+// an app playing on two outputs at once (or running twice) has several sessions under one key, and a slider mapped
+// to that key moves all of them together. each one can also be addressed on its own: "chrome.exe#2" is the second
+// chrome.exe session found, and "chrome.exe@<output>" is the one playing on that output (a sink's name on linux, a
+// device's friendly name on windows). device sessions (master, mic and such) are one of a kind, and have no instances
+
+const (
+	sessionInstanceNumberSeparator = "#"
+	sessionInstanceOutputSeparator = "@"
+)
+
+// outputSession is implemented by sessions that know which output they play on
+type outputSession interface {
+
+	// OutputName returns the name of the output the session plays on, or an empty string if it isn't known
+	OutputName() string
+}
+
+func (s *baseSession) OutputName() string {
+	return s.outputName
+}
+
+// sessionInstances maps every instance key of the given sessions to the session it addresses. sessions are numbered
+// from 1, in the order they were found
+func (m *sessionMap) sessionInstances(found map[string][]Session) map[string][]Session {
+	instances := map[string][]Session{}
+
+	for key, sessions := range found {
+		for sessionIdx, session := range sessions {
+			if m.isDeviceSession(session) {
+				continue
+			}
+
+			numbered := fmt.Sprintf("%s%s%d", key, sessionInstanceNumberSeparator, sessionIdx+1)
+			instances[numbered] = append(instances[numbered], session)
+
+			if output := sessionOutputName(session); output != "" {
+				byOutput := key + sessionInstanceOutputSeparator + output
+				instances[byOutput] = append(instances[byOutput], session)
+			}
+		}
+	}
+
+	return instances
+}
+
+// sessionOutputName returns the lowercased name of the output a session plays on, if it's known
+func sessionOutputName(session Session) string {
+	if os, ok := session.(outputSession); ok {
+		return strings.ToLower(os.OutputName())
+	}
+
+	return ""
+}
+
+// instanceKeys returns the instance keys that address the given session, most specific (by output) first
+func (m *sessionMap) instanceKeys(session Session) []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	byOutput, numbered := []string{}, []string{}
+
+	for key, sessions := range m.instances {
+		if instanceBaseKey(key) != session.Key() {
+			continue
+		}
+
+		for _, instance := range sessions {
+			if instance != session {
+				continue
+			}
+
+			if strings.Contains(strings.TrimPrefix(key, session.Key()), sessionInstanceOutputSeparator) {
+				byOutput = append(byOutput, key)
+			} else {
+				numbered = append(numbered, key)
+			}
+		}
+	}
+
+	return append(byOutput, numbered...)
+}
+
+// targetKey returns the key a slider reaches the given session through: one of its instance keys if a slider is
+// mapped to it, or otherwise the session's own key
+func (m *sessionMap) targetKey(session Session) string {
+	for _, key := range m.instanceKeys(session) {
+		if len(m.slidersForSession(key)) > 0 {
+			return key
+		}
+	}
+
+	return session.Key()
+}
+
+// instanceBaseKey returns the key an instance key belongs to, e.g. "chrome.exe" for "chrome.exe#2". any other key
+// is returned as it is
+func instanceBaseKey(key string) string {
+	if idx := strings.LastIndex(key, sessionInstanceOutputSeparator); idx > 0 {
+		return key[:idx]
+	}
+
+	if idx := strings.LastIndex(key, sessionInstanceNumberSeparator); idx > 0 {
+		suffix := key[idx+1:]
+		if suffix != "" && strings.Trim(suffix, "0123456789") == "" {
+			return key[:idx]
+		}
+	}
+
+	return key
+}
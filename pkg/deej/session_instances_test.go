@@ -0,0 +1,51 @@
+package deej
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInstanceBaseKey(t *testing.T) {
+	for key, expected := range map[string]string{
+		"chrome.exe#2":                          "chrome.exe",
+		"chrome.exe@alsa_output.usb-headset":    "chrome.exe",
+		"chrome.exe@speakers (realtek audio)":   "chrome.exe",
+		"chrome.exe":                            "chrome.exe",
+		"#2":                                    "#2",
+		"channel#general":                       "channel#general",
+		"speakers (realtek high definition #2)": "speakers (realtek high definition #2)",
+	} {
+		if actual := instanceBaseKey(key); actual != expected {
+			t.Errorf("%q: expected %q, got %q", key, expected, actual)
+		}
+	}
+}
+
+func TestInstanceTargetsReachOneSession(t *testing.T) {
+	td := newTestDeej(t, "slider_mapping:\n  0: chrome.exe#2\n", "chrome.exe", "chrome.exe")
+
+	first, second := td.sessions["chrome.exe"][0], td.sessions["chrome.exe"][1]
+
+	td.feed("512")
+
+	deadline := time.Now().Add(testSettleTimeout)
+	for !volumesEqual(second.GetVolume(), 0.5) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if volume := second.GetVolume(); !volumesEqual(volume, 0.5) {
+		t.Errorf("chrome.exe#2: expected volume 0.500, got %.3f", volume)
+	}
+
+	if volume := first.GetVolume(); !volumesEqual(volume, fakeSessionInitialVolume) {
+		t.Errorf("chrome.exe#1: expected volume %.3f, got %.3f", fakeSessionInitialVolume, volume)
+	}
+
+	if !td.Deej.sessions.sessionMapped(second) {
+		t.Error("chrome.exe#2: expected it to count as mapped")
+	}
+
+	if td.Deej.sessions.sessionMapped(first) {
+		t.Error("chrome.exe#1: expected it to count as unmapped")
+	}
+}
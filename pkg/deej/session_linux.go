@@ -1,7 +1,11 @@
 package deej
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -11,31 +15,99 @@ import (
 // normal PulseAudio volume (100%)
 const maxVolume = 0x10000
 
+// pulseaudio (and pipewire) happily amplify streams past 100%, so sliders may opt into doing that
+const sessionVolumeBoostSupported = true
+
+// pulseaudio volumes are cubic, a stream at 50% plays at an eighth of its full level
+const sessionVolumeExponent = 3
+
 type paSession struct {
 	baseSession
 
 	processName string
 	pid         uint32
 
-	client *proto.Client
+	client      *paClient
+	peakStreams *paPeakStreams
 
 	sinkInputIndex    uint32
 	sinkInputChannels byte
+
+	// for persist_volumes: where pulse's stream-restore database keeps the app's volume, and in which channel
+	// layout. see persist_volumes_linux.go
+	restoreEntryName string
+	channelMap       proto.ChannelMap
+	persistTimer     *time.Timer
+	pendingVolume    float32
+	persistLock      sync.Mutex
+}
+
+// paCaptureSession is an app's recording stream (a source output), i.e. what obs or a voice chat hears. its volume
+// is how loud the app hears its source, not how loud anyone else does
+type paCaptureSession struct {
+	baseSession
+
+	client *paClient
+
+	sourceOutputIndex    uint32
+	sourceOutputChannels byte
 }
 
 type masterSession struct {
 	baseSession
 
-	client *proto.Client
+	client *paClient
 
 	streamIndex    uint32
 	streamChannels byte
 	isOutput       bool
+
+	// which speaker each of the stream's channels goes to, only set for the master sink
+	channelMap proto.ChannelMap
+
+	// the channel volumes from the last time they weren't all silent. scaling up from 0 can't tell how the
+	// channels were balanced, so it scales these instead
+	balance     []uint32
+	balanceLock sync.Mutex
+}
+
+// masterChannelSession controls some of the master sink's channels (i.e. the rear speakers) on their own,
+// for trimming a surround setup from the sliders
+type masterChannelSession struct {
+	baseSession
+
+	client *paClient
+
+	sinkIndex uint32
+
+	// indexes into the sink's channel volumes
+	channels []int
+}
+
+// speaker group name -> the channel positions in it, see masterChannelSessionPrefix
+var speakerChannelGroups = map[string][]byte{
+	"front":  {proto.ChannelFrontLeft, proto.ChannelFrontRight},
+	"center": {proto.ChannelFrontCenter},
+	"sub":    {proto.ChannelLFE},
+	"rear":   {proto.ChannelRearLeft, proto.ChannelRearRight, proto.ChannelRearCenter},
+	"side":   {proto.ChannelLeftSide, proto.ChannelRightSide},
+}
+
+var errChannelMuteUnsupported = errors.New("channels can't be muted on their own")
+
+var errChannelGone = errors.New("channel no longer exists")
+
+// isPulseEntityGone returns true if err says the server no longer knows what was asked about, as opposed to the
+// request not getting through (a timeout, a restarting server). only the former means a session is gone
+func isPulseEntityGone(err error) bool {
+	var pulseErr proto.Error
+	return errors.As(err, &pulseErr) && (pulseErr == proto.ErrNoSuchEntity || pulseErr == proto.ErrEntityKilled)
 }
 
 func newPASession(
 	logger *zap.SugaredLogger,
-	client *proto.Client,
+	client *paClient,
+	peakStreams *paPeakStreams,
 	sinkInputIndex uint32,
 	sinkInputChannels byte,
 	processName string,
@@ -44,6 +116,7 @@ func newPASession(
 
 	s := &paSession{
 		client:            client,
+		peakStreams:       peakStreams,
 		sinkInputIndex:    sinkInputIndex,
 		sinkInputChannels: sinkInputChannels,
 		pid:               pid,
@@ -60,9 +133,35 @@ func newPASession(
 	return s
 }
 
+// newPACaptureSession creates a session for an app's recording stream, keyed e.g. "capture:obs"
+func newPACaptureSession(
+	logger *zap.SugaredLogger,
+	client *paClient,
+	sourceOutputIndex uint32,
+	sourceOutputChannels byte,
+	processName string,
+) *paCaptureSession {
+
+	s := &paCaptureSession{
+		client:               client,
+		sourceOutputIndex:    sourceOutputIndex,
+		sourceOutputChannels: sourceOutputChannels,
+	}
+
+	key := captureSessionPrefix + processName
+
+	s.logger = logger.Named(key)
+	s.name = key
+	s.humanReadableDesc = key
+
+	s.logger.Debugw(sessionCreationLogMessage, "session", s)
+
+	return s
+}
+
 func newMasterSession(
 	logger *zap.SugaredLogger,
-	client *proto.Client,
+	client *paClient,
 	streamIndex uint32,
 	streamChannels byte,
 	isOutput bool,
@@ -93,6 +192,58 @@ func newMasterSession(
 	return s
 }
 
+// newMonitorSession creates a session for a sink's monitor source, keyed "monitor" for the default sink's or
+// e.g. "monitor:alsa_output.usb-headset" for any sink's. it's a source like the mic, only one that hears the sink
+func newMonitorSession(
+	logger *zap.SugaredLogger,
+	client *paClient,
+	sourceIndex uint32,
+	sourceChannels byte,
+	key string,
+) *masterSession {
+
+	s := &masterSession{
+		client:         client,
+		streamIndex:    sourceIndex,
+		streamChannels: sourceChannels,
+		isOutput:       false,
+	}
+
+	s.logger = logger.Named(key)
+	s.name = key
+	s.humanReadableDesc = key
+
+	s.logger.Debugw(sessionCreationLogMessage, "session", s)
+
+	return s
+}
+
+// newMasterChannelSession creates a session for the given speaker group, keyed e.g. "master:rear"
+func newMasterChannelSession(
+	logger *zap.SugaredLogger,
+	client *paClient,
+	sinkIndex uint32,
+	group string,
+	channels []int,
+) *masterChannelSession {
+
+	s := &masterChannelSession{
+		client:    client,
+		sinkIndex: sinkIndex,
+		channels:  channels,
+	}
+
+	key := masterChannelSessionPrefix + group
+
+	s.logger = logger.Named(key)
+	s.name = key
+	s.humanReadableDesc = key
+
+	s.logger.Debugw(sessionCreationLogMessage, "session", s)
+
+	return s
+}
+
 func (s *paSession) GetVolume() float32 {
 	request := proto.GetSinkInputInfo{
 		SinkInputIndex: s.sinkInputIndex,
@@ -125,8 +276,53 @@ func (s *paSession) SetVolume(v float32) error {
 	return nil
 }
 
+func (s *paSession) GetMute() bool {
+	request := proto.GetSinkInputInfo{
+		SinkInputIndex: s.sinkInputIndex,
+	}
+	reply := proto.GetSinkInputInfoReply{}
+
+	if err := s.client.Request(&request, &reply); err != nil {
+		s.logger.Warnw("Failed to get session mute state", "error", err)
+	}
+
+	return reply.Muted
+}
+
+func (s *paSession) SetMute(m bool) error {
+	request := proto.SetSinkInputMute{
+		SinkInputIndex: s.sinkInputIndex,
+		Mute:           m,
+	}
+
+	if err := s.client.Request(&request, nil); err != nil {
+		s.logger.Warnw("Failed to set session mute state", "error", err)
+		return fmt.Errorf("adjust session mute state: %w", err)
+	}
+
+	s.logger.Debugw("Adjusting session mute state", "to", m)
+
+	return nil
+}
+
+func (s *paSession) IsAlive() bool {
+	request := proto.GetSinkInputInfo{
+		SinkInputIndex: s.sinkInputIndex,
+	}
+	reply := proto.GetSinkInputInfoReply{}
+
+	// the sink input is gone once its stream closes
+	err := s.client.Request(&request, &reply)
+	if err != nil && !isPulseEntityGone(err) {
+		s.logger.Debugw("Failed to get sink input info, assuming it's still there", "error", err)
+	}
+
+	return !isPulseEntityGone(err)
+}
+
 func (s *paSession) Release() {
 	s.logger.Debug("Releasing audio session")
+	s.flushPersistedVolume()
 }
 
 func (s *paSession) String() string {
@@ -137,42 +333,105 @@ func (s *paSession) GetPID() uint32 {
 	return s.pid
 }
 
-func (s *masterSession) GetVolume() float32 {
-	var level float32
+func (s *paCaptureSession) GetVolume() float32 {
+	request := proto.GetSourceOutputInfo{
+		SourceOutpuIndex: s.sourceOutputIndex,
+	}
+	reply := proto.GetSourceOutputInfoReply{}
 
-	if s.isOutput {
-		request := proto.GetSinkInfo{
-			SinkIndex: s.streamIndex,
-		}
-		reply := proto.GetSinkInfoReply{}
+	if err := s.client.Request(&request, &reply); err != nil {
+		s.logger.Warnw("Failed to get session volume", "error", err)
+		return 0
+	}
 
-		if err := s.client.Request(&request, &reply); err != nil {
-			s.logger.Warnw("Failed to get session volume", "error", err)
-			return 0
-		}
+	return parseChannelVolumes(reply.ChannelVolumes)
+}
 
-		level = parseChannelVolumes(reply.ChannelVolumes)
-	} else {
-		request := proto.GetSourceInfo{
-			SourceIndex: s.streamIndex,
-		}
-		reply := proto.GetSourceInfoReply{}
+func (s *paCaptureSession) SetVolume(v float32) error {
+	request := proto.SetSourceOutputVolume{
+		SourceOutputIndex: s.sourceOutputIndex,
+		ChannelVolumes:    createChannelVolumes(s.sourceOutputChannels, v),
+	}
 
-		if err := s.client.Request(&request, &reply); err != nil {
-			s.logger.Warnw("Failed to get session volume", "error", err)
-			return 0
-		}
+	if err := s.client.Request(&request, nil); err != nil {
+		s.logger.Warnw("Failed to set session volume", "error", err)
+		return fmt.Errorf("adjust session volume: %w", err)
+	}
+
+	s.logger.Debugw("Adjusting session volume", "to", fmt.Sprintf("%.2f", v))
 
-		level = parseChannelVolumes(reply.ChannelVolumes)
+	return nil
+}
+
+func (s *paCaptureSession) GetMute() bool {
+	request := proto.GetSourceOutputInfo{
+		SourceOutpuIndex: s.sourceOutputIndex,
 	}
+	reply := proto.GetSourceOutputInfoReply{}
 
-	return level
+	if err := s.client.Request(&request, &reply); err != nil {
+		s.logger.Warnw("Failed to get session mute state", "error", err)
+	}
+
+	return reply.Muted
+}
+
+func (s *paCaptureSession) SetMute(m bool) error {
+	request := proto.SetSourceOutputMute{
+		SourceOutputIndex: s.sourceOutputIndex,
+		Mute:              m,
+	}
+
+	if err := s.client.Request(&request, nil); err != nil {
+		s.logger.Warnw("Failed to set session mute state", "error", err)
+		return fmt.Errorf("adjust session mute state: %w", err)
+	}
+
+	s.logger.Debugw("Adjusting session mute state", "to", m)
+
+	return nil
+}
+
+func (s *paCaptureSession) IsAlive() bool {
+	request := proto.GetSourceOutputInfo{
+		SourceOutpuIndex: s.sourceOutputIndex,
+	}
+
+	// the source output is gone once the app stops recording
+	err := s.client.Request(&request, &proto.GetSourceOutputInfoReply{})
+	if err != nil && !isPulseEntityGone(err) {
+		s.logger.Debugw("Failed to get source output info, assuming it's still there", "error", err)
+	}
+
+	return !isPulseEntityGone(err)
+}
+
+func (s *paCaptureSession) Release() {
+	s.logger.Debug("Releasing audio session")
+}
+
+func (s *paCaptureSession) String() string {
+	return fmt.Sprintf(sessionStringFormat, s.humanReadableDesc, s.GetVolume())
+}
+
+// the master volume is its loudest channel's, like pavucontrol shows it. setting it scales all channels
+// together, so balance and any per-channel trims (see masterChannelSession) survive slider moves
+func (s *masterSession) GetVolume() float32 {
+	volumes, err := s.channelVolumes()
+	if err != nil {
+		s.logger.Warnw("Failed to get session volume", "error", err)
+		return 0
+	}
+
+	return loudestChannelVolume(volumes)
 }
 
 func (s *masterSession) SetVolume(v float32) error {
 	var request proto.RequestArgs
 
-	volumes := createChannelVolumes(s.streamChannels, v)
+	// without the current volumes, all channels just get set alike
+	current, _ := s.channelVolumes()
+	volumes := scaleChannelVolumes(s.balanced(current), s.streamChannels, v)
 
 	if s.isOutput {
 		request = &proto.SetSinkVolume{
@@ -199,6 +458,78 @@ func (s *masterSession) SetVolume(v float32) error {
 	return nil
 }
 
+func (s *masterSession) GetMute() bool {
+	if s.isOutput {
+		request := proto.GetSinkInfo{
+			SinkIndex: s.streamIndex,
+		}
+		reply := proto.GetSinkInfoReply{}
+
+		if err := s.client.Request(&request, &reply); err != nil {
+			s.logger.Warnw("Failed to get session mute state", "error", err)
+			return false
+		}
+
+		return reply.Mute
+	}
+
+	request := proto.GetSourceInfo{
+		SourceIndex: s.streamIndex,
+	}
+	reply := proto.GetSourceInfoReply{}
+
+	if err := s.client.Request(&request, &reply); err != nil {
+		s.logger.Warnw("Failed to get session mute state", "error", err)
+		return false
+	}
+
+	return reply.Mute
+}
+
+func (s *masterSession) SetMute(m bool) error {
+	var request proto.RequestArgs
+
+	if s.isOutput {
+		request = &proto.SetSinkMute{
+			SinkIndex: s.streamIndex,
+			Mute:      m,
+		}
+	} else {
+		request = &proto.SetSourceMute{
+			SourceIndex: s.streamIndex,
+			Mute:        m,
+		}
+	}
+
+	if err := s.client.Request(request, nil); err != nil {
+		s.logger.Warnw("Failed to set session mute state",
+			"error", err,
+			"mute", m)
+
+		return fmt.Errorf("adjust session mute state: %w", err)
+	}
+
+	s.logger.Debugw("Adjusting session mute state", "to", m)
+
+	return nil
+}
+
+func (s *masterSession) IsAlive() bool {
+	var err error
+
+	if s.isOutput {
+		err = s.client.Request(&proto.GetSinkInfo{SinkIndex: s.streamIndex}, &proto.GetSinkInfoReply{})
+	} else {
+		err = s.client.Request(&proto.GetSourceInfo{SourceIndex: s.streamIndex}, &proto.GetSourceInfoReply{})
+	}
+
+	if err != nil && !isPulseEntityGone(err) {
+		s.logger.Debugw("Failed to get device info, assuming it's still there", "error", err)
+	}
+
+	return !isPulseEntityGone(err)
+}
+
 func (s *masterSession) Release() {
 	s.logger.Debug("Releasing audio session")
 }
@@ -207,16 +538,205 @@ func (s *masterSession) String() string {
 	return fmt.Sprintf(sessionStringFormat, s.humanReadableDesc, s.GetVolume())
 }
 
+func (s *masterSession) channelVolumes() ([]uint32, error) {
+	if s.isOutput {
+		request := proto.GetSinkInfo{
+			SinkIndex: s.streamIndex,
+		}
+		reply := proto.GetSinkInfoReply{}
+
+		if err := s.client.Request(&request, &reply); err != nil {
+			return nil, fmt.Errorf("get sink info: %w", err)
+		}
+
+		return reply.ChannelVolumes, nil
+	}
+
+	request := proto.GetSourceInfo{
+		SourceIndex: s.streamIndex,
+	}
+	reply := proto.GetSourceInfoReply{}
+
+	if err := s.client.Request(&request, &reply); err != nil {
+		return nil, fmt.Errorf("get source info: %w", err)
+	}
+
+	return reply.ChannelVolumes, nil
+}
+
+// channelSessions returns a session for every speaker group the master sink has channels for
+func (s *masterSession) channelSessions(logger *zap.SugaredLogger) []Session {
+	groups := make([]string, 0, len(speakerChannelGroups))
+	for group := range speakerChannelGroups {
+		groups = append(groups, group)
+	}
+
+	sort.Strings(groups)
+
+	sessions := []Session{}
+
+	for _, group := range groups {
+		channels := []int{}
+
+		for idx, position := range s.channelMap {
+			for _, groupPosition := range speakerChannelGroups[group] {
+				if position == groupPosition {
+					channels = append(channels, idx)
+				}
+			}
+		}
+
+		if len(channels) > 0 {
+			sessions = append(sessions, newMasterChannelSession(logger, s.client, s.streamIndex, group, channels))
+		}
+	}
+
+	return sessions
+}
+
+func (s *masterChannelSession) GetVolume() float32 {
+	volumes, err := s.sinkVolumes()
+	if err != nil {
+		s.logger.Warnw("Failed to get session volume", "error", err)
+		return 0
+	}
+
+	own := []uint32{}
+	for _, idx := range s.channels {
+		own = append(own, volumes[idx])
+	}
+
+	return parseChannelVolumes(own)
+}
+
+func (s *masterChannelSession) SetVolume(v float32) error {
+	volumes, err := s.sinkVolumes()
+	if err != nil {
+		s.logger.Warnw("Failed to set session volume", "error", err)
+		return fmt.Errorf("adjust session volume: %w", err)
+	}
+
+	// leave every other channel where it is
+	volumes = append([]uint32{}, volumes...)
+	for _, idx := range s.channels {
+		volumes[idx] = uint32(v*maxVolume + 0.5)
+	}
+
+	request := proto.SetSinkVolume{
+		SinkIndex:      s.sinkIndex,
+		ChannelVolumes: volumes,
+	}
+
+	if err := s.client.Request(&request, nil); err != nil {
+		s.logger.Warnw("Failed to set session volume", "error", err, "volume", v)
+		return fmt.Errorf("adjust session volume: %w", err)
+	}
+
+	s.logger.Debugw("Adjusting session volume", "to", fmt.Sprintf("%.2f", v))
+
+	return nil
+}
+
+// pulse mutes whole sinks, not channels
+func (s *masterChannelSession) GetMute() bool {
+	return false
+}
+
+func (s *masterChannelSession) SetMute(m bool) error {
+	return errChannelMuteUnsupported
+}
+
+func (s *masterChannelSession) IsAlive() bool {
+	_, err := s.sinkVolumes()
+	if err != nil && !isPulseEntityGone(err) && !errors.Is(err, errChannelGone) {
+		s.logger.Debugw("Failed to get sink info, assuming it's still there", "error", err)
+		return true
+	}
+
+	return err == nil
+}
+
+func (s *masterChannelSession) Release() {
+	s.logger.Debug("Releasing audio session")
+}
+
+func (s *masterChannelSession) String() string {
+	return fmt.Sprintf(sessionStringFormat, s.humanReadableDesc, s.GetVolume())
+}
+
+// sinkVolumes returns all of the sink's channel volumes, making sure this session's channels are still among them
+func (s *masterChannelSession) sinkVolumes() ([]uint32, error) {
+	request := proto.GetSinkInfo{
+		SinkIndex: s.sinkIndex,
+	}
+	reply := proto.GetSinkInfoReply{}
+
+	if err := s.client.Request(&request, &reply); err != nil {
+		return nil, fmt.Errorf("get sink info: %w", err)
+	}
+
+	for _, idx := range s.channels {
+		if idx >= len(reply.ChannelVolumes) {
+			return nil, fmt.Errorf("sink channel %d: %w", idx, errChannelGone)
+		}
+	}
+
+	return reply.ChannelVolumes, nil
+}
+
 func createChannelVolumes(channels byte, volume float32) []uint32 {
 	volumes := make([]uint32, channels)
 
+	// volumes above 100% are fine here, they're amplified in software
 	for i := range volumes {
-		volumes[i] = uint32(volume * maxVolume)
+		volumes[i] = uint32(volume*maxVolume + 0.5)
 	}
 
 	return volumes
 }
 
+// balanced returns the channel volumes to scale for a new volume: the current ones, unless they're all silent
+func (s *masterSession) balanced(current []uint32) []uint32 {
+	s.balanceLock.Lock()
+	defer s.balanceLock.Unlock()
+
+	if loudestChannelVolume(current) > 0 {
+		s.balance = current
+	} else if len(s.balance) == len(current) {
+		return s.balance
+	}
+
+	return current
+}
+
+// scaleChannelVolumes scales the current channel volumes so the loudest one ends up at the given volume.
+// if they're all silent (or unknown), all channels are simply set to it
+func scaleChannelVolumes(current []uint32, channels byte, volume float32) []uint32 {
+	loudest := loudestChannelVolume(current)
+	if loudest == 0 {
+		return createChannelVolumes(channels, volume)
+	}
+
+	volumes := make([]uint32, len(current))
+	for i, channelVolume := range current {
+		volumes[i] = uint32(float32(channelVolume)*volume/loudest + 0.5)
+	}
+
+	return volumes
+}
+
+func loudestChannelVolume(volumes []uint32) float32 {
+	var loudest uint32
+
+	for _, volume := range volumes {
+		if volume > loudest {
+			loudest = volume
+		}
+	}
+
+	return float32(loudest) / float32(maxVolume)
+}
+
 func parseChannelVolumes(volumes []uint32) float32 {
 	var level uint32
 
@@ -0,0 +1,25 @@
+package deej
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMasterBalanceSurvivesSilence(t *testing.T) {
+	s := &masterSession{streamChannels: 2}
+
+	// the right channel is trimmed, and the volume goes down to 0 and back up
+	silent := scaleChannelVolumes(s.balanced([]uint32{maxVolume / 2, maxVolume / 4}), 2, 0)
+	volumes := scaleChannelVolumes(s.balanced(silent), 2, 1)
+
+	if !reflect.DeepEqual(volumes, []uint32{maxVolume, maxVolume / 2}) {
+		t.Errorf("expected the trim back after going through 0, got %v", volumes)
+	}
+
+	fresh := &masterSession{streamChannels: 2}
+	volumes = scaleChannelVolumes(fresh.balanced([]uint32{0, 0}), 2, 1)
+
+	if !reflect.DeepEqual(volumes, []uint32{maxVolume, maxVolume}) {
+		t.Errorf("expected channels without a known balance set alike, got %v", volumes)
+	}
+}
@@ -2,6 +2,8 @@ package deej
 
 import (
 	"fmt"
+	"math"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
@@ -21,8 +23,74 @@ type sessionMap struct {
 
 	sessionFinder SessionFinder
 
+	// protected by refreshLock, the audio_backend the session finder was created for. empty if it was handed in
+	backend string
+
+	// protected by refreshLock, an audio_backend the config switched to that couldn't be, so that later reloads
+	// don't try (and notify about) it again. empty once a switch works
+	failedBackend string
+
+	// held for the whole of a refresh, so that concurrent refreshes don't acquire every session twice
+	refreshLock sync.Mutex
+
 	lastSessionRefresh time.Time
-	unmappedSessions   []Session
+
+	// protected by refreshLock, set once the session finder is released for good
+	released bool
+
+	// protected by lock. always replaced as a whole, never modified in place
+	unmappedSessions []Session
+
+	// protected by lock, the sessions behind each instance key (e.g. "chrome.exe#2"), see session_instances.go
+	instances map[string][]Session
+
+	// protected by lock, every session key found since deej started. see targetDiagnostics
+	seenKeys map[string]bool
+
+	// protected by lock, the session keys around as of the last refresh, to tell which came and went
+	presentKeys map[string]bool
+
+	// the volume deej last set for each session key, to tell its own changes apart from everyone else's
+	lastSetVolumes map[string]float32
+	volumeLock     sync.Mutex
+
+	// sliders whose targets' volume was changed externally, and which keep that volume until they move
+	adoptedVolumes map[int]adoptedVolume
+
+	// session key -> the most its volume can be set to, see volume_caps.go
+	volumeCaps map[string]float32
+
+	// each session's volume worker, for sessions a slider set a volume on. see volume_ramp.go
+	volumeWorkers map[Session]*volumeWorker
+
+	// session key -> its volume from before it was ducked, nil while nothing is. see ducking.go
+	duckedVolumes map[string]duckedVolume
+	duckLock      sync.Mutex
+
+	// 1 while loudness is being calibrated, see loudness.go
+	calibrating int32
+
+	// puts composite sliders together from their inputs' moves, see composite_sliders.go
+	sliders *sliderAggregator
+
+	// where the session snapshot is kept between runs, empty for none. see session_snapshot.go
+	snapshotPath string
+
+	externalVolumeChangeConsumers []chan ExternalVolumeChangeEvent
+}
+
+type adoptedVolume struct {
+	volume      float32
+	sliderValue float32 // where the slider was when the volume was adopted
+}
+
+// ExternalVolumeChangeEvent is sent when a session mapped to a slider changes volume without deej being the one to change it
+type ExternalVolumeChangeEvent struct {
+	SliderID   int
+	SessionKey string
+
+	// where the slider would have to be to produce the session's new volume, taking its trim into account
+	PercentValue float32
 }
 
 const (
@@ -30,6 +98,20 @@ const (
 	systemSessionName = "system" // system sounds volume
 	inputSessionName  = "mic"    // microphone input level
 
+	// linux only - speaker groups of the master device (front, center, sub, rear, side), e.g. "master:rear"
+	masterChannelSessionPrefix = masterSessionName + ":"
+
+	// linux only - equalizer band parameters, e.g. "eq:band2.gain"
+	eqTargetPrefix = "eq:"
+
+	// linux only - apps' recording streams, e.g. "capture:obs" for how loud obs hears what it records
+	captureSessionPrefix = "capture:"
+
+	// linux only - sinks' monitor sources, i.e. what a loopback of them hears. "monitor" is the default sink's,
+	// "monitor:" and a sink's name is any sink's
+	monitorSessionName   = "monitor"
+	monitorSessionPrefix = monitorSessionName + ":"
+
 	// some targets need to be transformed before their correct audio sessions can be accessed.
 	// this prefix identifies those targets to ensure they don't contradict with another similarly-named process
 	specialTargetTransformPrefix = "deej."
@@ -40,10 +122,28 @@ const (
 	// targets all currently unmapped sessions (experimental)
 	specialTargetAllUnmapped = "unmapped"
 
-	// this threshold constant assumes that re-acquiring all sessions is a kind of expensive operation,
-	// and needs to be limited in some manner. this value was previously user-configurable through a config
-	// key "process_refresh_frequency", but exposing this type of implementation detail seems wrong now
-	minTimeBetweenSessionRefreshes = time.Second * 5
+	// re-acquiring all sessions is a kind of expensive operation, so refreshes deej decides on by itself are limited
+	// to one every process_refresh_frequency seconds. on slow machines, session_refresh can leave them to the user
+	sessionRefreshAuto   = "auto"
+	sessionRefreshManual = "manual"
+
+	// how long to wait between attempts to reconnect to the audio server, doubling up to the max
+	audioReconnectMinDelay = time.Second
+	audioReconnectMaxDelay = time.Second * 30
+
+	// backends don't always report back exactly the volume we set, so allow for some rounding
+	externalVolumeChangeTolerance = 0.01
+
+	// what to do when something other than deej changes a mapped session's volume
+	externalVolumeChangeIgnore   = "ignore"   // leave it, the slider takes over again whenever it moves
+	externalVolumeChangeReassert = "reassert" // immediately put the volume back where the slider is
+	externalVolumeChangeAdopt    = "adopt"    // keep the new volume, even across reconnects, until the slider moves
+
+	// what happens to a muted session's mute state when deej sets its volume. neither pulseaudio nor windows
+	// unmute when a volume is set through the APIs we use, but other mixers (and some pipewire setups) do
+	volumeChangeMuteLeave    = "leave"    // don't touch it, whatever the backend does goes
+	volumeChangeMutePreserve = "preserve" // make sure muted sessions stay muted
+	volumeChangeMuteUnmute   = "unmute"   // unmute sessions whenever their slider moves
 )
 
 // this matches friendly device names (on Windows), e.g. "Headphones (Realtek Audio)"
@@ -55,11 +155,19 @@ func newSessionMap(deej *Deej, logger *zap.SugaredLogger, sessionFinder SessionF
 	logger.Debug("Creating session map instance")
 
 	m := &sessionMap{
-		deej:          deej,
-		logger:        logger,
-		m:             make(map[string][]Session),
-		lock:          &sync.Mutex{},
-		sessionFinder: sessionFinder,
+		deej:           deej,
+		logger:         logger,
+		m:              make(map[string][]Session),
+		instances:      map[string][]Session{},
+		lock:           &sync.Mutex{},
+		sessionFinder:  sessionFinder,
+		lastSetVolumes: map[string]float32{},
+		adoptedVolumes: map[int]adoptedVolume{},
+		volumeCaps:     map[string]float32{},
+		volumeWorkers:  map[Session]*volumeWorker{},
+		seenKeys:       map[string]bool{},
+		presentKeys:    map[string]bool{},
+		sliders:        newSliderAggregator(deej),
 	}
 
 	logger.Debug("Created session map instance")
@@ -70,19 +178,70 @@ func newSessionMap(deej *Deej, logger *zap.SugaredLogger, sessionFinder SessionF
 func (m *sessionMap) initialize() error {
 	m.logger.Info("Initializing session map")
 
-	if err := m.getAndAddSessions(); err != nil {
-		m.logger.Warnw("Failed to get all sessions during session map initialization", "error", err)
-		return fmt.Errorf("get all sessions during init: %w", err)
+	// unless one was handed in (e.g. by tests), use the configured backend's
+	if m.sessionFinder == nil {
+		sessionFinder, err := newSessionFinderWithFallback(m.deej.logger, m.deej.config.AudioBackend)
+		if err != nil {
+			m.logger.Warnw("Failed to create session finder", "backend", m.deej.config.AudioBackend, "error", err)
+			return withErrorCode(errorCodeAudioUnavailable, "", fmt.Errorf("create session finder: %w", err))
+		}
+
+		m.sessionFinder = sessionFinder
+		m.backend = m.deej.config.AudioBackend
+		m.snapshotPath = filepath.Join(internalConfigPath, sessionSnapshotFilename)
+	}
+
+	restored := false
+
+	if m.snapshotPath != "" {
+		if snapshot, err := loadSessionSnapshot(m.snapshotPath); err != nil {
+			m.logger.Debugw("No session snapshot to start from", "reason", err)
+		} else {
+			m.rehydrate(snapshot)
+			restored = true
+		}
+	}
+
+	if !restored {
+		if err := m.getAndAddSessions(); err != nil {
+			m.logger.Warnw("Failed to get all sessions during session map initialization", "error", err)
+			return fmt.Errorf("get all sessions during init: %w", err)
+		}
 	}
 
 	m.setupOnConfigReload()
 	m.setupOnSliderMove()
+	m.setupOnVolumeChange()
+	m.setupOnSessionRemoval()
+	m.setupOnDisconnect()
+
+	if restored {
+		go m.discoverInBackground()
+	}
 
 	m.logger.Info("Session map initialization complete")
 	return nil
 }
 
 func (m *sessionMap) release() error {
+	m.refreshLock.Lock()
+	defer m.refreshLock.Unlock()
+
+	// so that nothing tries to reconnect once it's gone
+	m.released = true
+
+	// initialize never got as far as creating one
+	if m.sessionFinder == nil {
+		return nil
+	}
+
+	// the sessions go with the finder, so this is the last chance to write them down
+	if m.snapshotPath != "" {
+		if err := m.saveSnapshot(); err != nil {
+			m.logger.Warnw("Failed to save session snapshot, next startup will wait for discovery", "error", err)
+		}
+	}
+
 	if err := m.sessionFinder.Release(); err != nil {
 		m.logger.Warnw("Failed to release session finder during session map release", "error", err)
 		return fmt.Errorf("release session finder during release: %w", err)
@@ -91,20 +250,188 @@ func (m *sessionMap) release() error {
 	return nil
 }
 
+// renewSessionFinder replaces the session finder with a fresh one and re-acquires all sessions through it.
+// the audio server can restart (or its connection can quietly go stale) while the system sleeps, and the old
+// finder would keep handing out sessions that no longer go anywhere. if a new one can't be created, the old
+// one stays
+func (m *sessionMap) renewSessionFinder() error {
+	backend := m.deej.config.AudioBackend
+
+	sessionFinder, err := newSessionFinder(m.deej.logger, backend)
+	if err != nil {
+		m.logger.Warnw("Failed to create new session finder", "error", err)
+		return fmt.Errorf("create session finder: %w", err)
+	}
+
+	m.refreshLock.Lock()
+	defer m.refreshLock.Unlock()
+
+	// sessions belong to the finder that found them, so they go first
+	m.clear()
+
+	if err := m.sessionFinder.Release(); err != nil {
+		m.logger.Warnw("Failed to release old session finder", "error", err)
+	}
+
+	m.sessionFinder = sessionFinder
+	m.backend = backend
+	m.failedBackend = ""
+
+	m.setupOnVolumeChange()
+	m.setupOnSessionRemoval()
+	m.setupOnDisconnect()
+
+	if err := m.getAndAddSessions(); err != nil {
+		m.logger.Warnw("Failed to re-acquire all audio sessions with renewed session finder", "error", err)
+	} else {
+		m.logger.Debug("Renewed session finder and re-acquired sessions successfully")
+	}
+
+	return nil
+}
+
+// reconnectAudioBackend renews the session finder on request, without restarting deej: when pulseaudio was swapped
+// for pipewire-pulse under it, say, or the config now names another audio_backend. every session is then put back
+// where its slider is
+func (m *sessionMap) reconnectAudioBackend() error {
+	if m.isReleased() {
+		return nil
+	}
+
+	m.logger.Infow("Reconnecting audio backend", "backend", m.deej.config.AudioBackend)
+
+	if err := m.renewSessionFinder(); err != nil {
+		return withErrorCode(errorCodeAudioUnavailable, "", fmt.Errorf("renew session finder: %w", err))
+	}
+
+	m.deej.setAudioServerAvailable(true)
+	m.deej.serial.resyncSliders()
+
+	return nil
+}
+
+// backendChanged returns true if the config names another audio backend than the session finder's, unless
+// switching to it already failed
+func (m *sessionMap) backendChanged() bool {
+	m.refreshLock.Lock()
+	defer m.refreshLock.Unlock()
+
+	backend := m.deej.config.AudioBackend
+
+	return m.backend != "" && m.backend != backend && m.failedBackend != backend
+}
+
+// backendSwitchFailed remembers that the config's audio backend couldn't be switched to
+func (m *sessionMap) backendSwitchFailed(backend string) {
+	m.refreshLock.Lock()
+	defer m.refreshLock.Unlock()
+
+	m.failedBackend = backend
+}
+
+// reconnect keeps trying to renew the session finder after its connection to the audio server dropped,
+// backing off between attempts. master and mic sessions come back along with everything else
+func (m *sessionMap) reconnect() {
+	m.deej.setAudioServerAvailable(false)
+	m.deej.timeline.recordConnection(timelineKindAudioLost, "", "")
+	m.deej.notifier.Notify(tr("notify.audio_lost.title"), tr("notify.audio_lost.message"))
+
+	delay := audioReconnectMinDelay
+
+	for attempt := 1; ; attempt++ {
+		<-time.After(delay)
+
+		// deej is shutting down
+		if m.isReleased() {
+			return
+		}
+
+		m.logger.Infow("Attempting to reconnect to the audio server", "attempt", attempt)
+
+		if err := m.renewSessionFinder(); err == nil {
+			break
+		}
+
+		if delay *= 2; delay > audioReconnectMaxDelay {
+			delay = audioReconnectMaxDelay
+		}
+	}
+
+	m.logger.Info("Reconnected to the audio server")
+
+	m.deej.setAudioServerAvailable(true)
+	m.deej.timeline.recordConnection(timelineKindAudioBack, "", "")
+	m.deej.notifier.Notify(tr("notify.audio_back.title"), tr("notify.audio_back.message"))
+
+	// whatever the server restarted with, put it back where the sliders are
+	m.deej.serial.resyncSliders()
+}
+
+// getAndAddSessions gets all sessions from the session finder and puts them in the map in one go, in place of
+// whatever it held. the sessions it replaces stay in use until then, so sliders keep working throughout a refresh
 func (m *sessionMap) getAndAddSessions() error {
 	m.lastSessionRefresh = time.Now()
-	m.unmappedSessions = nil
 
 	sessions, err := m.sessionFinder.GetAllSessions()
 	if err != nil {
 		m.logger.Warnw("Failed to get sessions from session finder", "error", err)
-		return fmt.Errorf("get sessions from SessionFinder: %w", err)
+		return withErrorCode(errorCodeAudioUnavailable, "", fmt.Errorf("get sessions from SessionFinder: %w", err))
 	}
 
+	found := map[string][]Session{}
+	presentKeys := map[string]bool{}
+
 	for _, session := range sessions {
-		m.add(session)
-		if !m.sessionMapped(session) {
-			m.unmappedSessions = append(m.unmappedSessions, session)
+		found[session.Key()] = append(found[session.Key()], session)
+		presentKeys[session.Key()] = true
+	}
+
+	instances := m.sessionInstances(found)
+
+	// unmapped sessions are worked out against the new instances, since a slider might map one of them
+	m.lock.Lock()
+	m.instances = instances
+	m.lock.Unlock()
+
+	unmappedSessions := m.findUnmappedSessions(sessions)
+
+	m.lock.Lock()
+	replaced := m.m
+	m.m = found
+	m.unmappedSessions = unmappedSessions
+	previousKeys := m.presentKeys
+	m.presentKeys = presentKeys
+
+	for key := range presentKeys {
+		m.seenKeys[key] = true
+	}
+
+	// instance keys only count as seen, they come and go with their sessions
+	for key := range instances {
+		m.seenKeys[key] = true
+	}
+	m.lock.Unlock()
+
+	for _, keySessions := range replaced {
+		for _, session := range keySessions {
+			session.Release()
+		}
+	}
+
+	for key := range presentKeys {
+		if !previousKeys[key] {
+			m.deej.timeline.recordSession(timelineKindSessionAdded, key)
+
+			// apps tend to start out at full volume
+			for _, session := range found[key] {
+				m.enforceVolumeCap(session, session.GetVolume())
+			}
+		}
+	}
+
+	for key := range previousKeys {
+		if !presentKeys[key] {
+			m.deej.timeline.recordSession(timelineKindSessionRemoved, key)
 		}
 	}
 
@@ -116,37 +443,365 @@ func (m *sessionMap) setupOnConfigReload() {
 	configReloadedChannel := m.deej.config.SubscribeToChanges()
 	go func() {
 		for range configReloadedChannel {
-			m.logger.Info("Config reloaded, refreshing audio sessions")
+
+			// a new backend finds its sessions from scratch, which covers the mapping too. one that can't be had
+			// is only tried again once the config names yet another, or a reconnect is asked for
+			if m.backendChanged() {
+				backend := m.deej.config.AudioBackend
+				m.logger.Infow("Audio backend changed in config, switching", "backend", backend)
+
+				err := m.reconnectAudioBackend()
+				if err == nil {
+					continue
+				}
+
+				m.logger.Warnw("Failed to switch audio backend, keeping the current one", "error", err)
+				m.backendSwitchFailed(backend)
+				m.deej.notifyError(err)
+			}
+
+			// which sessions there are doesn't depend on the config, only which slider they belong to does. so
+			// rebuilding that is enough, unless the mapping now names something that hasn't been found yet
+			m.updateUnmappedSessions()
+
+			if !m.mappingHasMissingTargets() {
+				m.logger.Info("Config reloaded, updated slider mapping")
+				continue
+			}
+
+			m.logger.Info("Config reloaded, looking for audio sessions of newly mapped targets")
 			m.refreshSessions(false)
 		}
 	}()
 }
 
+// mappingHasMissingTargets returns true if a slider is mapped to a session name that isn't in the map. special
+// transforms and roles resolve to whatever is around, so they never count as missing
+func (m *sessionMap) mappingHasMissingTargets() bool {
+	missing := false
+
+	m.deej.config.SliderMapping.iterate(func(sliderIdx int, targets []string) {
+		for _, target := range targets {
+			name, _, _ := splitTargetTrim(strings.ToLower(target))
+			if _, ok := roleTarget(name); ok || m.targetHasSpecialTransform(name) {
+				continue
+			}
+
+			if _, ok := m.get(m.resolveTarget(target)[0]); !ok {
+				missing = true
+				return
+			}
+		}
+	})
+
+	return missing
+}
+
+// updateUnmappedSessions recomputes which of the current sessions aren't mapped to any slider
+func (m *sessionMap) updateUnmappedSessions() {
+	m.refreshLock.Lock()
+	defer m.refreshLock.Unlock()
+
+	m.lock.Lock()
+	sessions := []Session{}
+	for _, keySessions := range m.m {
+		sessions = append(sessions, keySessions...)
+	}
+	m.lock.Unlock()
+
+	unmappedSessions := m.findUnmappedSessions(sessions)
+
+	m.lock.Lock()
+	m.unmappedSessions = unmappedSessions
+	m.lock.Unlock()
+}
+
+func (m *sessionMap) findUnmappedSessions(sessions []Session) []Session {
+	unmappedSessions := []Session{}
+
+	for _, session := range sessions {
+		if !m.sessionMapped(session) {
+			unmappedSessions = append(unmappedSessions, session)
+		}
+	}
+
+	return unmappedSessions
+}
+
 func (m *sessionMap) setupOnSliderMove() {
 	m.logger.Debug("Setting up slider move event subscription")
-	sliderEventsChannel := m.deej.serial.SubscribeToSliderMoveEvents()
+	sliderEventsChannel := m.deej.serial.SubscribeToSliderMoveEvents("sessions")
 	m.logger.Debug("Subscribed to slider move events")
 	go func() {
 		m.logger.Debug("Starting slider event processing loop")
 		for event := range sliderEventsChannel {
 			m.logger.Debugw("Received slider move event", "sliderID", event.SliderID, "percentValue", event.PercentValue)
-			m.handleSliderMoveEvent(event)
+
+			if event, ok := m.sliders.aggregate(event); ok {
+				m.handleSliderMoveEvent(event)
+			}
 		}
 		m.logger.Debug("Slider event processing loop ended")
 	}()
 }
 
-// performance: explain why force == true at every such use to avoid unintended forced refresh spams
-func (m *sessionMap) refreshSessions(force bool) {
+// SubscribeToExternalVolumeChanges returns a channel that receives an event whenever something other
+// than deej changes the volume of a session mapped to a slider
+func (m *sessionMap) SubscribeToExternalVolumeChanges() chan ExternalVolumeChangeEvent {
+	ch := make(chan ExternalVolumeChangeEvent, 10)
+	m.externalVolumeChangeConsumers = append(m.externalVolumeChangeConsumers, ch)
 
-	// make sure enough time passed since the last refresh, unless force is true in which case always clear
-	if !force && m.lastSessionRefresh.Add(minTimeBetweenSessionRefreshes).After(time.Now()) {
+	return ch
+}
+
+func (m *sessionMap) setupOnVolumeChange() {
+	watcher, ok := m.sessionFinder.(sessionVolumeWatcher)
+	if !ok {
+		m.logger.Debug("Session finder can't watch for volume changes")
 		return
 	}
 
-	// clear and release sessions first
-	m.clear()
+	volumeChanges := watcher.SubscribeToVolumeChanges()
+	go func() {
+		for session := range volumeChanges {
+			m.handleVolumeChange(session)
+		}
+	}()
+}
+
+func (m *sessionMap) isReleased() bool {
+	m.refreshLock.Lock()
+	defer m.refreshLock.Unlock()
+
+	return m.released
+}
+
+func (m *sessionMap) setupOnDisconnect() {
+	watcher, ok := m.sessionFinder.(sessionDisconnectWatcher)
+	if !ok {
+		return
+	}
+
+	sessionFinder := m.sessionFinder
+	disconnected := watcher.Disconnected()
+
+	go func() {
+		<-disconnected
+
+		// a finder that was already replaced or released doesn't matter anymore
+		m.refreshLock.Lock()
+		current := m.sessionFinder == sessionFinder && !m.released
+		m.refreshLock.Unlock()
+
+		if current {
+			m.logger.Warn("Lost connection to the audio server")
+			m.reconnect()
+		}
+	}()
+}
 
+func (m *sessionMap) setupOnSessionRemoval() {
+	watcher, ok := m.sessionFinder.(sessionRemovalWatcher)
+	if !ok {
+		m.logger.Debug("Session finder can't watch for session removals")
+		return
+	}
+
+	sessionRemovals := watcher.SubscribeToSessionRemovals()
+	go func() {
+		for session := range sessionRemovals {
+			m.handleDeadSession(session)
+		}
+	}()
+}
+
+// handleDeadSession gets rid of a session the backend no longer knows about. process sessions are simply dropped,
+// but a dead device session means our view of the devices is out of date, so that takes a full refresh
+func (m *sessionMap) handleDeadSession(session Session) {
+	if !m.isDeviceSession(session) {
+		m.remove(session)
+		return
+	}
+
+	m.logger.Debugw("Device session went away, refreshing sessions", "session", session.Key())
+
+	// performance: the device this session pointed at is gone, and there's no other way to find its replacement
+	m.refreshSessions(true)
+}
+
+func (m *sessionMap) handleVolumeChange(session Session) {
+	key := m.targetKey(session)
+	volume := session.GetVolume()
+
+	// the steps of a fade are deej's own doing too
+	if m.isVolumeRampEcho(session, volume) {
+		return
+	}
+
+	// ignore the change if it's just the echo of a volume we set ourselves
+	m.volumeLock.Lock()
+	lastSet, ok := m.lastSetVolumes[key]
+	m.lastSetVolumes[key] = volume
+	m.volumeLock.Unlock()
+
+	if ok && math.Abs(float64(lastSet-volume)) < externalVolumeChangeTolerance {
+		return
+	}
+
+	// a fade would only fight whoever changed it. reasserting steers the fade instead
+	if m.deej.config.ExternalVolumeChange != externalVolumeChangeReassert {
+		m.cancelVolumeRamp(session)
+	}
+
+	// caps hold no matter who changes the volume, or whether a slider controls the session
+	if m.enforceVolumeCap(session, volume) {
+		return
+	}
+
+	sliderIDs := m.slidersForSession(key)
+	if len(sliderIDs) == 0 {
+		return
+	}
+
+	m.deej.timeline.recordVolume(timelineKindExternalVolume, sliderIDs[0], key, volume)
+
+	policy := m.deej.config.ExternalVolumeChange
+	m.logger.Debugw("Session volume changed externally",
+		"session", key,
+		"volume", volume,
+		"sliders", sliderIDs,
+		"policy", policy)
+
+	switch policy {
+	case externalVolumeChangeReassert:
+		m.reassertSliderVolume(session, key, sliderIDs)
+
+		// the volume's going right back, so don't tell anyone it changed
+		return
+
+	case externalVolumeChangeAdopt:
+		m.adoptVolume(sliderIDs, volume)
+	}
+
+	for _, sliderID := range sliderIDs {
+		percentValue := m.sliderTrim(sliderID, key).invert(volume, m.sliderCeiling(sliderID))
+
+		event := ExternalVolumeChangeEvent{
+			SliderID:     sliderID,
+			SessionKey:   key,
+			PercentValue: m.crossfadeValue(sliderID, m.sliderTarget(sliderID, key), percentValue),
+		}
+
+		for _, consumer := range m.externalVolumeChangeConsumers {
+			select {
+			case consumer <- event:
+			default:
+			}
+		}
+	}
+}
+
+// reassertSliderVolume puts a session's volume back to where its slider is. key is the one the slider reaches the
+// session through, see targetKey
+func (m *sessionMap) reassertSliderVolume(session Session, key string, sliderIDs []int) {
+	for _, sliderID := range sliderIDs {
+		sliderValue, ok := m.sliders.value(sliderID)
+		if !ok {
+			continue
+		}
+
+		sliderValue = m.crossfadeValue(sliderID, m.sliderTarget(sliderID, key), sliderValue)
+
+		volume := m.capVolume(key, m.sliderTrim(sliderID, key).apply(sliderValue, m.sliderCeiling(sliderID)))
+		m.recordVolumeSet(key, volume)
+
+		m.queueSessionVolume(session, volume, false, func(volume float32, err error) {
+			if err != nil {
+				m.logger.Warnw("Failed to reassert session volume", "session", session.Key(), "error", err)
+			}
+		})
+
+		return
+	}
+}
+
+// adoptVolume makes an externally set volume stick until the given sliders physically move
+func (m *sessionMap) adoptVolume(sliderIDs []int, volume float32) {
+	m.volumeLock.Lock()
+	defer m.volumeLock.Unlock()
+
+	for _, sliderID := range sliderIDs {
+		sliderValue, ok := m.sliders.value(sliderID)
+		if !ok {
+			continue
+		}
+
+		m.adoptedVolumes[sliderID] = adoptedVolume{volume: volume, sliderValue: sliderValue}
+	}
+}
+
+// keepAdoptedVolume returns true if a slider move event should be dropped because its slider's targets
+// adopted an externally set volume, and the slider hasn't actually moved since (e.g. it just reconnected)
+func (m *sessionMap) keepAdoptedVolume(event SliderMoveEvent) bool {
+	m.volumeLock.Lock()
+	defer m.volumeLock.Unlock()
+
+	adopted, ok := m.adoptedVolumes[event.SliderID]
+	if !ok {
+		return false
+	}
+
+	if event.Initial && !util.SignificantlyDifferent(adopted.sliderValue, event.PercentValue, m.deej.config.NoiseReductionLevel) {
+		return true
+	}
+
+	// the slider moved, so it's back in charge
+	delete(m.adoptedVolumes, event.SliderID)
+
+	return false
+}
+
+// slidersForSession returns the IDs of all sliders whose targets currently include the given session key
+func (m *sessionMap) slidersForSession(key string) []int {
+	sliderIDs := []int{}
+
+	m.deej.config.SliderMapping.iterate(func(sliderIdx int, targets []string) {
+		for _, target := range targets {
+			if funk.ContainsString(m.resolveTarget(target), key) {
+				sliderIDs = append(sliderIDs, sliderIdx)
+				return
+			}
+		}
+	})
+
+	return sliderIDs
+}
+
+func (m *sessionMap) recordVolumeSet(key string, volume float32) {
+	m.volumeLock.Lock()
+	defer m.volumeLock.Unlock()
+
+	m.lastSetVolumes[key] = volume
+}
+
+// performance: explain why force == true at every such use to avoid unintended forced refresh spams
+func (m *sessionMap) refreshSessions(force bool) {
+	m.refreshLock.Lock()
+	defer m.refreshLock.Unlock()
+
+	// make sure enough time passed since the last refresh (and that deej is allowed to refresh by itself at all),
+	// unless force is true in which case always clear
+	if !force {
+		if m.deej.config.SessionRefresh == sessionRefreshManual {
+			return
+		}
+
+		if m.lastSessionRefresh.Add(m.deej.config.SessionRefreshInterval).After(time.Now()) {
+			return
+		}
+	}
+
+	// the new sessions replace the old ones as they come in, no need to clear the map first
 	if err := m.getAndAddSessions(); err != nil {
 		m.logger.Warnw("Failed to re-acquire all audio sessions", "error", err)
 	} else {
@@ -154,22 +809,41 @@ func (m *sessionMap) refreshSessions(force bool) {
 	}
 }
 
+// isDeviceSession returns true for the special sessions (master, system, mic) and device-specific sessions,
+// as opposed to sessions that belong to a process
+func (m *sessionMap) isDeviceSession(session Session) bool {
+	if funk.ContainsString([]string{masterSessionName, systemSessionName, inputSessionName}, session.Key()) {
+		return true
+	}
+
+	if strings.HasPrefix(session.Key(), masterChannelSessionPrefix) || strings.HasPrefix(session.Key(), eqTargetPrefix) {
+		return true
+	}
+
+	if session.Key() == monitorSessionName || strings.HasPrefix(session.Key(), monitorSessionPrefix) {
+		return true
+	}
+
+	return deviceSessionKeyPattern.MatchString(session.Key())
+}
+
 // returns true if a session is not currently mapped to any slider, false otherwise
-// special sessions (master, system, mic) and device-specific sessions always count as mapped,
+// special sessions (master, system, mic), device-specific and capture sessions always count as mapped,
 // even when absent from the config. this makes sense for every current feature that uses "unmapped sessions"
 func (m *sessionMap) sessionMapped(session Session) bool {
 
-	// count master/system/mic as mapped
-	if funk.ContainsString([]string{masterSessionName, systemSessionName, inputSessionName}, session.Key()) {
+	// count master/system/mic and device sessions as mapped
+	if m.isDeviceSession(session) {
 		return true
 	}
 
-	// count device sessions as mapped
-	if deviceSessionKeyPattern.MatchString(session.Key()) {
+	// an app's recording stream isn't something it plays, so it's not one of the unmapped apps either
+	if strings.HasPrefix(session.Key(), captureSessionPrefix) {
 		return true
 	}
 
 	matchFound := false
+	keys := append([]string{session.Key()}, m.instanceKeys(session)...)
 
 	// look through the actual mappings
 	m.deej.config.SliderMapping.iterate(func(sliderIdx int, targets []string) {
@@ -180,10 +854,21 @@ func (m *sessionMap) sessionMapped(session Session) bool {
 				continue
 			}
 
+			// sessions a slider picks up by their role count as mapped too
+			name, _, _ := splitTargetTrim(strings.ToLower(target))
+			if role, ok := roleTarget(name); ok {
+				if role != "" && m.sessionRole(session) == role {
+					matchFound = true
+					return
+				}
+
+				continue
+			}
+
 			// safe to assume this has a single element because we made sure there's no special transform
 			target = m.resolveTarget(target)[0]
 
-			if target == session.Key() {
+			if funk.ContainsString(keys, target) {
 				matchFound = true
 				return
 			}
@@ -194,7 +879,20 @@ func (m *sessionMap) sessionMapped(session Session) bool {
 }
 
 func (m *sessionMap) handleSliderMoveEvent(event SliderMoveEvent) {
+	m.deej.latency.record(latencyStageDispatch, event.Received)
+
 	m.logger.Debugw("Handling slider move event", "sliderID", event.SliderID, "percentValue", event.PercentValue)
+
+	if m.deej.lockPolicies.frozen() {
+		m.logger.Debugw("Ignoring slider move while input is frozen", "sliderID", event.SliderID)
+		return
+	}
+
+	if m.keepAdoptedVolume(event) {
+		m.logger.Debugw("Keeping adopted volume for slider", "sliderID", event.SliderID)
+		return
+	}
+
 	targets, ok := m.deej.config.SliderMapping.get(event.SliderID)
 	if !ok {
 		m.logger.Debugw("No targets mapped for slider", "sliderID", event.SliderID)
@@ -212,37 +910,227 @@ func (m *sessionMap) handleSliderMoveEvent(event SliderMoveEvent) {
 				continue
 			}
 			m.logger.Debugw("Found sessions for target", "target", resolvedTarget, "sessionCount", len(sessions))
+
+			sliderValue := m.crossfadeValue(event.SliderID, target, event.PercentValue)
+			volume := m.targetTrim(target, resolvedTarget).apply(sliderValue, m.sliderCeiling(event.SliderID))
+			volume = m.capVolume(resolvedTarget, volume)
+			m.recordVolumeSet(resolvedTarget, volume)
+
 			for _, session := range sessions {
-				go func(s Session, volume float32, target string) {
-					if err := s.SetVolume(volume); err != nil {
+				s, target := session, resolvedTarget
+
+				m.queueSessionVolume(s, volume, !event.Initial, func(volume float32, err error) {
+					if err != nil {
 						m.logger.Warnw("Failed to set session volume", "target", target, "error", err)
+
+						// sessions of exited processes can be dropped on their own, anything else warrants a refresh
+						if !s.IsAlive() && !m.isDeviceSession(s) {
+							m.remove(s)
+							return
+						}
+
 						go func() {
 							time.Sleep(100 * time.Millisecond)
 							m.refreshSessions(true)
 						}()
-					} else {
-						m.logger.Debugw("Successfully set session volume", "target", target, "volume", volume)
+
+						return
 					}
-				}(session, event.PercentValue, resolvedTarget)
+
+					m.deej.latency.record(latencyStageApply, event.Received)
+					m.logger.Debugw("Successfully set session volume", "target", target, "volume", volume)
+					m.deej.trace.recordVolume(event.SliderID, target, volume)
+					m.deej.timeline.recordVolume(timelineKindVolume, event.SliderID, target, volume)
+				})
 			}
 		}
 	}
 }
 
+// setSessionVolume sets a session's volume, and takes care of its mute state as configured.
+// moved is false when deej is just restoring a volume, rather than following the slider
+func (m *sessionMap) setSessionVolume(session Session, volume float32, moved bool) error {
+	switch m.deej.config.VolumeChangeMute {
+	case volumeChangeMutePreserve:
+		muted := session.GetMute()
+
+		if err := session.SetVolume(volume); err != nil {
+			return err
+		}
+
+		if muted && !session.GetMute() {
+			m.logger.Debugw("Backend unmuted session while setting its volume, muting it again", "session", session.Key())
+			return session.SetMute(true)
+		}
+
+	case volumeChangeMuteUnmute:
+		if err := session.SetVolume(volume); err != nil {
+			return err
+		}
+
+		if moved && session.GetMute() {
+			return session.SetMute(false)
+		}
+
+	default:
+		return session.SetVolume(volume)
+	}
+
+	return nil
+}
+
+// toggleSliderMute flips the mute state of every session the slider controls, and returns the new state.
+// sessions are all set to the same state, based on the first one's
+func (m *sessionMap) toggleSliderMute(sliderID int) (bool, error) {
+	sessions := m.sliderSessions(sliderID)
+	if len(sessions) == 0 {
+		return false, fmt.Errorf("no sessions mapped to slider %d", sliderID)
+	}
+
+	muted := !sessions[0].GetMute()
+
+	for _, session := range sessions {
+		if err := session.SetMute(muted); err != nil {
+			m.logger.Warnw("Failed to set session mute state", "session", session.Key(), "error", err)
+		}
+	}
+
+	return muted, nil
+}
+
+// muteAll mutes every session that isn't muted yet, and returns their keys
+func (m *sessionMap) muteAll() []string {
+	m.lock.Lock()
+	sessions := []Session{}
+	for _, keySessions := range m.m {
+		sessions = append(sessions, keySessions...)
+	}
+	m.lock.Unlock()
+
+	muted := []string{}
+
+	for _, session := range sessions {
+		if session.GetMute() {
+			continue
+		}
+
+		// some sessions (i.e. single channels) can't be muted, which is fine
+		if err := session.SetMute(true); err != nil {
+			m.logger.Debugw("Failed to mute session", "session", session.Key(), "error", err)
+			continue
+		}
+
+		muted = append(muted, session.Key())
+	}
+
+	return funk.UniqString(muted)
+}
+
+// unmuteAll unmutes every session with one of the given keys
+func (m *sessionMap) unmuteAll(keys []string) {
+	for _, key := range keys {
+		sessions, _ := m.get(key)
+
+		for _, session := range sessions {
+			if err := session.SetMute(false); err != nil {
+				m.logger.Warnw("Failed to unmute session", "session", key, "error", err)
+			}
+		}
+	}
+}
+
+// sliderSessions returns all sessions currently controlled by a slider
+func (m *sessionMap) sliderSessions(sliderID int) []Session {
+	result := []Session{}
+
+	targets, ok := m.deej.config.SliderMapping.get(sliderID)
+	if !ok {
+		return result
+	}
+
+	for _, target := range targets {
+		for _, resolvedTarget := range m.resolveTarget(target) {
+			if sessions, ok := m.get(resolvedTarget); ok {
+				result = append(result, sessions...)
+			}
+		}
+	}
+
+	return result
+}
+
+// targetTrim returns the trim for a session reached through the given mapping target:
+// the target's inline trim if it has one, otherwise whatever target_trim says about the session (or for an instance
+// key, about the session key it belongs to).
+// the session's loudness offset (see loudness.go) applies on top of either
+func (m *sessionMap) targetTrim(target string, resolvedTarget string) volumeTrim {
+	trim := noTrim
+
+	if _, inlineTrim, ok := splitTargetTrim(target); ok {
+		trim = inlineTrim
+	} else if configuredTrim, ok := m.deej.config.TargetTrim[resolvedTarget]; ok {
+		trim = configuredTrim
+	} else if configuredTrim, ok := m.deej.config.TargetTrim[instanceBaseKey(resolvedTarget)]; ok {
+		trim = configuredTrim
+	}
+
+	if offset, ok := m.deej.config.LoudnessOffsets[instanceBaseKey(resolvedTarget)]; ok {
+		trim.gain *= loudnessOffsetTrim(offset).gain
+	}
+
+	return trim
+}
+
+// sliderCeiling returns the volume the top of a slider stands for
+func (m *sessionMap) sliderCeiling(sliderID int) float32 {
+	if ceiling, ok := m.deej.config.SliderMaxVolume[sliderID]; ok {
+		return ceiling
+	}
+
+	return 1
+}
+
+// sliderTrim returns the trim a slider applies to the given session key
+func (m *sessionMap) sliderTrim(sliderID int, key string) volumeTrim {
+	target := m.sliderTarget(sliderID, key)
+	if target == "" {
+		return noTrim
+	}
+
+	return m.targetTrim(target, key)
+}
+
+// sliderTarget returns the slider's mapping target that reaches the given session key, or "" if none does
+func (m *sessionMap) sliderTarget(sliderID int, key string) string {
+	targets, _ := m.deej.config.SliderMapping.get(sliderID)
+
+	for _, target := range targets {
+		if funk.ContainsString(m.resolveTarget(target), key) {
+			return target
+		}
+	}
+
+	return ""
+}
+
 func (m *sessionMap) targetHasSpecialTransform(target string) bool {
 	return strings.HasPrefix(target, specialTargetTransformPrefix)
 }
 
 func (m *sessionMap) resolveTarget(target string) []string {
 
-	// start by ignoring the case
-	target = strings.ToLower(target)
+	// start by ignoring the case, and any trim
+	target, _, _ = splitTargetTrim(strings.ToLower(target))
 
 	// look for any special targets first, by examining the prefix
 	if m.targetHasSpecialTransform(target) {
 		return m.applyTargetTransform(strings.TrimPrefix(target, specialTargetTransformPrefix))
 	}
 
+	if role, ok := roleTarget(target); ok {
+		return m.sessionKeysWithRole(role)
+	}
+
 	return []string{target}
 }
 
@@ -253,7 +1141,7 @@ func (m *sessionMap) applyTargetTransform(specialTargetName string) []string {
 
 	// get current active window
 	case specialTargetCurrentWindow:
-		currentWindowProcessNames, err := util.GetCurrentWindowProcessNames()
+		currentWindowProcessNames, err := util.GetCurrentWindowProcessNames(m.deej.config.FocusMode)
 
 		// silently ignore errors here, as this is on deej's "hot path" (and it could just mean the user's running linux)
 		if err != nil {
@@ -270,8 +1158,12 @@ func (m *sessionMap) applyTargetTransform(specialTargetName string) []string {
 
 	// get currently unmapped sessions
 	case specialTargetAllUnmapped:
-		targetKeys := make([]string, len(m.unmappedSessions))
-		for sessionIdx, session := range m.unmappedSessions {
+		m.lock.Lock()
+		unmappedSessions := m.unmappedSessions
+		m.lock.Unlock()
+
+		targetKeys := make([]string, len(unmappedSessions))
+		for sessionIdx, session := range unmappedSessions {
 			targetKeys[sessionIdx] = session.Key()
 		}
 
@@ -281,36 +1173,98 @@ func (m *sessionMap) applyTargetTransform(specialTargetName string) []string {
 	return nil
 }
 
-func (m *sessionMap) add(value Session) {
-	m.logger.Debugw("About to add session to map", "session", value)
-
-	m.logger.Debug("About to acquire lock")
+// remove drops and releases a single session, leaving every other session alone
+func (m *sessionMap) remove(session Session) {
 	m.lock.Lock()
-	m.logger.Debug("Lock acquired")
-	defer m.lock.Unlock()
 
-	key := value.Key()
-	m.logger.Debugw("Session key", "key", key)
+	key := session.Key()
+	found, gone := false, false
 
-	existing, ok := m.m[key]
-	if !ok {
-		m.m[key] = []Session{value}
-		m.logger.Debugw("Created new session list", "key", key)
+	remaining := []Session{}
+	for _, existing := range m.m[key] {
+		if existing == session {
+			found = true
+			continue
+		}
+
+		remaining = append(remaining, existing)
+	}
+
+	if len(remaining) == 0 {
+		delete(m.m, key)
+
+		// the last of its sessions, so the key's gone until the next refresh finds it again
+		if found && m.presentKeys[key] {
+			gone = true
+			delete(m.presentKeys, key)
+		}
 	} else {
-		m.m[key] = append(existing, value)
-		m.logger.Debugw("Added to existing session list", "key", key, "count", len(m.m[key]))
+		m.m[key] = remaining
+	}
+
+	remainingUnmapped := []Session{}
+	for _, unmapped := range m.unmappedSessions {
+		if unmapped != session {
+			remainingUnmapped = append(remainingUnmapped, unmapped)
+		}
+	}
+
+	m.unmappedSessions = remainingUnmapped
+
+	for instanceKey, instances := range m.instances {
+		for instanceIdx, instance := range instances {
+			if instance == session {
+				instances = append(instances[:instanceIdx:instanceIdx], instances[instanceIdx+1:]...)
+				break
+			}
+		}
+
+		if len(instances) == 0 {
+			delete(m.instances, instanceKey)
+		} else {
+			m.instances[instanceKey] = instances
+		}
+	}
+
+	m.lock.Unlock()
+
+	// it might have been released by a refresh already
+	if !found {
+		return
+	}
+
+	m.forgetVolumeWorker(session)
+	session.Release()
+	m.logger.Debugw("Removed dead session", "session", key)
+
+	if gone {
+		m.deej.timeline.recordSession(timelineKindSessionRemoved, key)
 	}
 }
 
+// everMatched returns true if a session with the given key was found at any point since deej started
+func (m *sessionMap) everMatched(key string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.seenKeys[key]
+}
+
 func (m *sessionMap) get(key string) ([]Session, bool) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	value, ok := m.m[key]
+	if value, ok := m.m[key]; ok {
+		return value, ok
+	}
+
+	value, ok := m.instances[key]
 	return value, ok
 }
 
 func (m *sessionMap) clear() {
+	m.forgetVolumeWorkers()
+
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -324,6 +1278,9 @@ func (m *sessionMap) clear() {
 		delete(m.m, key)
 	}
 
+	m.unmappedSessions = nil
+	m.instances = map[string][]Session{}
+
 	m.logger.Debug("Session map cleared")
 }
 
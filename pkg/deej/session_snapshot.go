@@ -0,0 +1,160 @@
+package deej
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/omriharel/deej/pkg/deej/util"
+)
+
+// finding every audio session can take a while (on windows, every app's process gets looked up), and deej used to
+// wait for it before connecting to the board. so when deej exits, it writes down which sessions each slider reached
+// and the volumes it left them at. the next time it starts with such a snapshot, the board connects right away and
+// sessions are discovered in the background. the snapshot can't stand in for the sessions themselves (they're
+// handles into the audio server), so slider moves don't reach any until discovery is done - then every slider is
+// applied where it is by then, moves made in the meantime included. until then, the snapshot only keeps its
+// sessions from being reported as missing, and their volumes from being taken for external changes (see rehydrate).
+// without a snapshot (i.e. the first time), startup waits for discovery like it always did
+const sessionSnapshotFilename = "session_snapshot.json"
+
+// snapshots older than this probably don't describe what's running anymore, and aren't trusted
+const maxSessionSnapshotAge = 7 * 24 * time.Hour
+
+type sessionSnapshot struct {
+	Saved time.Time `json:"saved"`
+
+	// slider index -> the session keys it reached
+	Sliders map[string][]string `json:"sliders"`
+
+	// session key -> the volume deej last set it to
+	Volumes map[string]float32 `json:"volumes"`
+}
+
+// takeSnapshot describes the sessions each slider reaches right now, and the volumes deej set
+func (m *sessionMap) takeSnapshot() sessionSnapshot {
+	snapshot := sessionSnapshot{
+		Saved:   time.Now(),
+		Sliders: map[string][]string{},
+		Volumes: map[string]float32{},
+	}
+
+	m.deej.config.SliderMapping.iterate(func(sliderIdx int, targets []string) {
+		keys := []string{}
+
+		for _, target := range targets {
+			for _, resolvedTarget := range m.resolveTarget(target) {
+				if _, ok := m.get(resolvedTarget); ok {
+					keys = append(keys, resolvedTarget)
+				}
+			}
+		}
+
+		if len(keys) > 0 {
+			sort.Strings(keys)
+			snapshot.Sliders[strconv.Itoa(sliderIdx)] = keys
+		}
+	})
+
+	m.volumeLock.Lock()
+	for key, volume := range m.lastSetVolumes {
+		snapshot.Volumes[key] = volume
+	}
+	m.volumeLock.Unlock()
+
+	return snapshot
+}
+
+// saveSnapshot writes a snapshot of the session map for the next startup
+func (m *sessionMap) saveSnapshot() error {
+	contents, err := json.MarshalIndent(m.takeSnapshot(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode session snapshot: %w", err)
+	}
+
+	if err := util.EnsureDirExists(filepath.Dir(m.snapshotPath)); err != nil {
+		return fmt.Errorf("ensure session snapshot directory exists: %w", err)
+	}
+
+	if err := ioutil.WriteFile(m.snapshotPath, contents, 0644); err != nil {
+		m.logger.Warnw("Failed to write session snapshot", "path", m.snapshotPath, "error", err)
+		return fmt.Errorf("write session snapshot: %w", err)
+	}
+
+	m.logger.Debugw("Saved session snapshot", "path", m.snapshotPath)
+
+	return nil
+}
+
+// loadSessionSnapshot reads the snapshot the last run left behind, if it's recent enough to trust
+func loadSessionSnapshot(path string) (sessionSnapshot, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return sessionSnapshot{}, fmt.Errorf("read session snapshot: %w", err)
+	}
+
+	var snapshot sessionSnapshot
+	if err := json.Unmarshal(contents, &snapshot); err != nil {
+		return sessionSnapshot{}, fmt.Errorf("decode session snapshot: %w", err)
+	}
+
+	if age := time.Since(snapshot.Saved); age > maxSessionSnapshotAge || age < 0 {
+		return sessionSnapshot{}, fmt.Errorf("session snapshot from %v is too old", snapshot.Saved)
+	}
+
+	return snapshot, nil
+}
+
+// rehydrate takes a snapshot's word for the sessions until discovery is done: its sessions count as seen (so they
+// aren't reported as missing in the meantime), and its volumes as what deej last set them to (so finding them at
+// those volumes isn't mistaken for someone else changing them)
+func (m *sessionMap) rehydrate(snapshot sessionSnapshot) {
+	m.lock.Lock()
+	for _, keys := range snapshot.Sliders {
+		for _, key := range keys {
+			m.seenKeys[key] = true
+		}
+	}
+	m.lock.Unlock()
+
+	m.volumeLock.Lock()
+	for key, volume := range snapshot.Volumes {
+		m.lastSetVolumes[key] = volume
+	}
+	m.volumeLock.Unlock()
+
+	m.logger.Infow("Restored session snapshot, discovering sessions in the background",
+		"saved", snapshot.Saved,
+		"sliders", len(snapshot.Sliders),
+		"volumes", len(snapshot.Volumes))
+}
+
+// discoverInBackground finds every session after starting from a snapshot, then puts them where the sliders are.
+// the sliders may well have moved while it was looking
+func (m *sessionMap) discoverInBackground() {
+	m.refreshLock.Lock()
+
+	// deej was closed before discovery got anywhere
+	if m.released {
+		m.refreshLock.Unlock()
+		return
+	}
+
+	err := m.getAndAddSessions()
+	m.refreshLock.Unlock()
+
+	if err != nil {
+		m.logger.Warnw("Failed to discover audio sessions in the background", "error", err)
+
+		// same as losing the audio server, keep trying until it's there
+		m.reconnect()
+		return
+	}
+
+	m.logger.Info("Finished discovering audio sessions")
+	m.deej.serial.resyncSliders()
+}
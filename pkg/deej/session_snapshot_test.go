@@ -0,0 +1,70 @@
+package deej
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSessionSnapshotRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deej-snapshot")
+	if err != nil {
+		t.Fatalf("create snapshot directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	td := newTestDeej(t, `
+slider_mapping:
+  0: master
+  1:
+    - chrome.exe
+    - spotify.exe
+  2: discord.exe
+`, "master", "chrome.exe", "spotify.exe")
+
+	td.feed("1023|512|0")
+	td.expectVolumes(t, map[string]float32{"chrome.exe": 0.5})
+
+	td.Deej.sessions.snapshotPath = filepath.Join(dir, "logs", sessionSnapshotFilename)
+	if err := td.Deej.sessions.saveSnapshot(); err != nil {
+		t.Fatalf("save snapshot: %v", err)
+	}
+
+	snapshot, err := loadSessionSnapshot(td.Deej.sessions.snapshotPath)
+	if err != nil {
+		t.Fatalf("load snapshot: %v", err)
+	}
+
+	// discord.exe isn't running, so slider 2 didn't reach anything
+	wantSliders := map[string][]string{"0": {"master"}, "1": {"chrome.exe", "spotify.exe"}}
+	if !reflect.DeepEqual(snapshot.Sliders, wantSliders) {
+		t.Errorf("expected sliders %v, got %v", wantSliders, snapshot.Sliders)
+	}
+
+	if volume, ok := snapshot.Volumes["spotify.exe"]; !ok || !volumesEqual(volume, 0.5) {
+		t.Errorf("expected spotify.exe at 0.5, got %.2f (%v)", volume, ok)
+	}
+
+	// a fresh session map takes its word for it until discovery is done
+	other := newTestDeej(t, "slider_mapping:\n  0: master\n")
+	other.Deej.sessions.rehydrate(snapshot)
+
+	if !other.Deej.sessions.everMatched("chrome.exe") {
+		t.Error("expected chrome.exe to count as seen")
+	}
+
+	// an old snapshot is no good
+	snapshot.Saved = time.Now().Add(-2 * maxSessionSnapshotAge)
+	contents, _ := json.Marshal(snapshot)
+	if err := ioutil.WriteFile(td.Deej.sessions.snapshotPath, contents, 0644); err != nil {
+		t.Fatalf("write old snapshot: %v", err)
+	}
+
+	if _, err := loadSessionSnapshot(td.Deej.sessions.snapshotPath); err == nil {
+		t.Error("expected an old snapshot to be refused")
+	}
+}
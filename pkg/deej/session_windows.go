@@ -4,13 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"syscall"
+	"unsafe"
 
 	ole "github.com/go-ole/go-ole"
 	ps "github.com/mitchellh/go-ps"
 	wca "github.com/moutend/go-wca"
 	"go.uber.org/zap"
+	"golang.org/x/sys/windows"
 )
 
+// windows won't set volumes past 100%
+const sessionVolumeBoostSupported = false
+
+// session volumes scale the stream's level directly
+const sessionVolumeExponent = 1
+
 var errNoSuchProcess = errors.New("No such process")
 var errRefreshSessions = errors.New("Trigger session refresh")
 
@@ -24,6 +33,7 @@ type wcaSession struct {
 	volume  *wca.ISimpleAudioVolume
 
 	eventCtx *ole.GUID
+	notifier *volumeNotifier
 }
 
 type masterSession struct {
@@ -32,6 +42,7 @@ type masterSession struct {
 	volume *wca.IAudioEndpointVolume
 
 	eventCtx *ole.GUID
+	notifier *volumeNotifier
 
 	stale bool // when set to true, we should refresh sessions on the next call to SetVolume
 }
@@ -56,6 +67,10 @@ func newWCASession(
 		s.system = true
 		s.name = systemSessionName
 		s.humanReadableDesc = "system sounds"
+
+		// windows doesn't expose the audio category apps open their streams with,
+		// but the system sounds session is all notifications
+		s.role = sessionRoleNotification
 	} else {
 
 		// find our session's process name
@@ -77,6 +92,7 @@ func newWCASession(
 		s.processName = process.Executable()
 		s.name = s.processName
 		s.humanReadableDesc = fmt.Sprintf("%s (pid %d)", s.processName, s.pid)
+		s.displayName = wcaDisplayName(control, pid)
 	}
 
 	// use a self-identifying session name e.g. deej.sessions.chrome
@@ -86,6 +102,76 @@ func newWCASession(
 	return s, nil
 }
 
+// wcaDisplayName returns the name a session was given by its app, or failing that, the description in its
+// executable's version info ("Google Chrome" for chrome.exe). plenty of apps have neither
+func wcaDisplayName(control *wca.IAudioSessionControl2, pid uint32) string {
+
+	// go-wca reads the returned string through a 32-bit pointer, so call through the vtable ourselves
+	var name *uint16
+	hr, _, _ := syscall.Syscall(
+		control.VTable().GetDisplayName,
+		2,
+		uintptr(unsafe.Pointer(control)),
+		uintptr(unsafe.Pointer(&name)),
+		0)
+
+	if hr == 0 && name != nil {
+		displayName := windows.UTF16PtrToString(name)
+		windows.CoTaskMemFree(unsafe.Pointer(name))
+
+		// names like "@%SystemRoot%\System32\AudioSrv.Dll,-202" point at a resource, rather than being one
+		if displayName != "" && !strings.HasPrefix(displayName, "@") {
+			return displayName
+		}
+	}
+
+	return executableDescription(pid)
+}
+
+// executableDescription returns the file description from a process' executable, or an empty string if it has none
+func executableDescription(pid uint32) string {
+	process, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(process)
+
+	path := make([]uint16, windows.MAX_LONG_PATH)
+	pathLength := uint32(len(path))
+	if err := windows.QueryFullProcessImageName(process, 0, &path[0], &pathLength); err != nil {
+		return ""
+	}
+
+	executable := windows.UTF16ToString(path[:pathLength])
+
+	size, err := windows.GetFileVersionInfoSize(executable, nil)
+	if err != nil || size == 0 {
+		return ""
+	}
+
+	info := make([]byte, size)
+	if err := windows.GetFileVersionInfo(executable, 0, size, unsafe.Pointer(&info[0])); err != nil {
+		return ""
+	}
+
+	// version info strings are kept per language and code page, so look the description up in the first one
+	var translation *[2]uint16
+	var translationLength uint32
+	err = windows.VerQueryValue(unsafe.Pointer(&info[0]), `\VarFileInfo\Translation`, unsafe.Pointer(&translation), &translationLength)
+	if err != nil || translationLength < 4 {
+		return ""
+	}
+
+	var description *uint16
+	var descriptionLength uint32
+	subBlock := fmt.Sprintf(`\StringFileInfo\%04x%04x\FileDescription`, translation[0], translation[1])
+	if err := windows.VerQueryValue(unsafe.Pointer(&info[0]), subBlock, unsafe.Pointer(&description), &descriptionLength); err != nil || descriptionLength == 0 {
+		return ""
+	}
+
+	return strings.TrimSpace(windows.UTF16PtrToString(description))
+}
+
 func newMasterSession(
 	logger *zap.SugaredLogger,
 	volume *wca.IAudioEndpointVolume,
@@ -110,6 +196,8 @@ func newMasterSession(
 }
 
 func (s *wcaSession) GetVolume() float32 {
+	defer audioCalls.time("GetMasterVolume")()
+
 	var level float32
 
 	if err := s.volume.GetMasterVolume(&level); err != nil {
@@ -120,6 +208,8 @@ func (s *wcaSession) GetVolume() float32 {
 }
 
 func (s *wcaSession) SetVolume(v float32) error {
+	defer audioCalls.time("SetMasterVolume")()
+
 	if err := s.volume.SetMasterVolume(v, s.eventCtx); err != nil {
 		s.logger.Warnw("Failed to set session volume", "error", err)
 		return fmt.Errorf("adjust session volume: %w", err)
@@ -143,9 +233,73 @@ func (s *wcaSession) SetVolume(v float32) error {
 	return nil
 }
 
+func (s *wcaSession) GetMute() bool {
+	defer audioCalls.time("GetMute")()
+
+	// windows writes a 4-byte BOOL here, which doesn't fit in a go bool
+	var muted int32
+
+	if err := s.volume.GetMute((*bool)(unsafe.Pointer(&muted))); err != nil {
+		s.logger.Warnw("Failed to get session mute state", "error", err)
+	}
+
+	return muted != 0
+}
+
+func (s *wcaSession) SetMute(m bool) error {
+	defer audioCalls.time("SetMute")()
+
+	if err := s.volume.SetMute(m, s.eventCtx); err != nil {
+		s.logger.Warnw("Failed to set session mute state", "error", err)
+		return fmt.Errorf("adjust session mute state: %w", err)
+	}
+
+	s.logger.Debugw("Adjusting session mute state", "to", m)
+
+	return nil
+}
+
+func (s *wcaSession) IsAlive() bool {
+	var state uint32
+
+	// only a session whose device went away is known to be gone, anything else might pass
+	if err := s.control.GetState(&state); err != nil {
+		s.logger.Debugw("Failed to get session state", "error", err)
+		return !isOleError(err, audclntEDeviceInvalidated)
+	}
+
+	return state != wca.AudioSessionStateExpired
+}
+
+// watch calls onChange whenever something other than deej changes this session's volume,
+// and onExpired once the session goes away
+func (s *wcaSession) watch(onChange func(), onExpired func()) error {
+	notifier := newSessionEventsNotifier(func(eventContext *ole.GUID) {
+		if eventContext != nil && ole.IsEqualGUID(eventContext, s.eventCtx) {
+			return
+		}
+
+		onChange()
+	}, onExpired)
+
+	if err := notifier.registerWithSession(s.control); err != nil {
+		return fmt.Errorf("watch session volume: %w", err)
+	}
+
+	s.notifier = notifier
+
+	return nil
+}
+
 func (s *wcaSession) Release() {
 	s.logger.Debug("Releasing audio session")
 
+	if s.notifier != nil {
+		if err := s.notifier.unregisterFromSession(s.control); err != nil {
+			s.logger.Debugw("Failed to stop watching session volume", "error", err)
+		}
+	}
+
 	s.volume.Release()
 	s.control.Release()
 }
@@ -155,6 +309,8 @@ func (s *wcaSession) String() string {
 }
 
 func (s *masterSession) GetVolume() float32 {
+	defer audioCalls.time("GetMasterVolumeLevelScalar")()
+
 	var level float32
 
 	if err := s.volume.GetMasterVolumeLevelScalar(&level); err != nil {
@@ -170,6 +326,8 @@ func (s *masterSession) SetVolume(v float32) error {
 		return errRefreshSessions
 	}
 
+	defer audioCalls.time("SetMasterVolumeLevelScalar")()
+
 	if err := s.volume.SetMasterVolumeLevelScalar(v, s.eventCtx); err != nil {
 		s.logger.Warnw("Failed to set session volume",
 			"error", err,
@@ -183,9 +341,70 @@ func (s *masterSession) SetVolume(v float32) error {
 	return nil
 }
 
+func (s *masterSession) GetMute() bool {
+	defer audioCalls.time("GetMute")()
+
+	// windows writes a 4-byte BOOL here, which doesn't fit in a go bool
+	var muted int32
+
+	if err := s.volume.GetMute((*bool)(unsafe.Pointer(&muted))); err != nil {
+		s.logger.Warnw("Failed to get session mute state", "error", err)
+	}
+
+	return muted != 0
+}
+
+func (s *masterSession) SetMute(m bool) error {
+	if s.stale {
+		s.logger.Warnw("Session expired because default device has changed, triggering session refresh")
+		return errRefreshSessions
+	}
+
+	defer audioCalls.time("SetMute")()
+
+	if err := s.volume.SetMute(m, s.eventCtx); err != nil {
+		s.logger.Warnw("Failed to set session mute state", "error", err)
+		return fmt.Errorf("adjust session mute state: %w", err)
+	}
+
+	s.logger.Debugw("Adjusting session mute state", "to", m)
+
+	return nil
+}
+
+// IsAlive returns false after the default device changed, since this session still points at the old one
+func (s *masterSession) IsAlive() bool {
+	return !s.stale
+}
+
+// watchVolume calls onChange whenever something other than deej changes this device's volume
+func (s *masterSession) watchVolume(onChange func()) error {
+	notifier := newEndpointVolumeNotifier(func(eventContext *ole.GUID) {
+		if eventContext != nil && ole.IsEqualGUID(eventContext, s.eventCtx) {
+			return
+		}
+
+		onChange()
+	})
+
+	if err := notifier.registerWithEndpoint(s.volume); err != nil {
+		return fmt.Errorf("watch device volume: %w", err)
+	}
+
+	s.notifier = notifier
+
+	return nil
+}
+
 func (s *masterSession) Release() {
 	s.logger.Debug("Releasing audio session")
 
+	if s.notifier != nil {
+		if err := s.notifier.unregisterFromEndpoint(s.volume); err != nil {
+			s.logger.Debugw("Failed to stop watching device volume", "error", err)
+		}
+	}
+
 	s.volume.Release()
 }
 
@@ -0,0 +1,56 @@
+package deej
+
+// while the board isn't connected, the config window still shows a row for each of its sliders to map. it shows as
+// many as the board had the last time it was connected (remembered in the internal config), or until deej has seen
+// it at all, default_slider_count
+const (
+	defaultSliderCount = 5
+	maxSliderCount     = 64
+
+	internalKeyLastSliderCount = "last_slider_count"
+)
+
+// populateDefaultSliderCount reads default_slider_count, which has to be a sensible number of sliders
+func (cc *CanonicalConfig) populateDefaultSliderCount() {
+	cc.DefaultSliderCount = cc.userConfig.GetInt(configKeyDefaultSliderCount)
+
+	if cc.DefaultSliderCount < 1 || cc.DefaultSliderCount > maxSliderCount {
+		cc.logger.Warnw("Invalid default slider count, using default value",
+			"key", configKeyDefaultSliderCount,
+			"invalidValue", cc.DefaultSliderCount,
+			"defaultValue", defaultSliderCount)
+
+		cc.DefaultSliderCount = defaultSliderCount
+	}
+}
+
+// rememberSliderCount saves how many sliders the connected board has, for when it isn't connected
+func (cc *CanonicalConfig) rememberSliderCount(numSliders int) {
+	if numSliders < 1 || numSliders > maxSliderCount {
+		return
+	}
+
+	cc.internalLock.Lock()
+	defer cc.internalLock.Unlock()
+
+	if cc.internalConfig.GetInt(internalKeyLastSliderCount) == numSliders {
+		return
+	}
+
+	cc.internalConfig.Set(internalKeyLastSliderCount, numSliders)
+	cc.saveInternalConfig()
+
+	cc.logger.Debugw("Remembered slider count", "sliders", numSliders)
+}
+
+// offlineSliderCount returns how many sliders to show while the board isn't connected
+func (cc *CanonicalConfig) offlineSliderCount() int {
+	cc.internalLock.Lock()
+	defer cc.internalLock.Unlock()
+
+	if numSliders := cc.internalConfig.GetInt(internalKeyLastSliderCount); numSliders >= 1 && numSliders <= maxSliderCount {
+		return numSliders
+	}
+
+	return cc.DefaultSliderCount
+}
@@ -81,6 +81,43 @@ func sliderMapFromConfigs(userMapping map[string][]string, internalMapping map[s
 	return resultMap
 }
 
+// withOverrides returns a copy of the map where each overridden slider's targets are replaced entirely.
+// an override with no targets leaves its slider unmapped
+func (m *sliderMap) withOverrides(overrides map[int][]string) *sliderMap {
+	resultMap := newSliderMap()
+
+	m.iterate(func(sliderIdx int, targets []string) {
+		resultMap.set(sliderIdx, targets)
+	})
+
+	for sliderIdx, targets := range overrides {
+		if len(targets) == 0 {
+			delete(resultMap.m, sliderIdx)
+			continue
+		}
+
+		resultMap.set(sliderIdx, targets)
+	}
+
+	return resultMap
+}
+
+// replace swaps the map's contents for the other map's. the config refills its maps this way rather than swapping
+// them out, since every other goroutine reads them through the same pointer
+func (m *sliderMap) replace(other *sliderMap) {
+	other.lock.Lock()
+	contents := make(map[int][]string, len(other.m))
+	for sliderIdx, targets := range other.m {
+		contents[sliderIdx] = targets
+	}
+	other.lock.Unlock()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.m = contents
+}
+
 func (m *sliderMap) iterate(f func(int, []string)) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
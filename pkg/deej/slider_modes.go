@@ -0,0 +1,69 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// slider modes change what a slider does with its targets, beyond setting them all to its value.
+// the only one so far is crossfade, written "crossfade(spotify.exe, chrome.exe)": the first target is at full
+// volume with the slider at the bottom, the second one with it at the top, and both are at half in the middle
+var crossfadeModePattern = regexp.MustCompile(`(?i)^crossfade\(\s*([^,()]+?)\s*,\s*([^,()]+?)\s*\)$`)
+
+var errInvalidSliderMode = errors.New("invalid slider mode")
+
+// crossfade is a slider fading between two targets
+type crossfade struct {
+	from string
+	to   string
+}
+
+// parseSliderMode parses a slider mode as written in the config
+func parseSliderMode(spec string) (crossfade, error) {
+	match := crossfadeModePattern.FindStringSubmatch(strings.TrimSpace(spec))
+	if match == nil {
+		return crossfade{}, fmt.Errorf("%q: %w", spec, errInvalidSliderMode)
+	}
+
+	from, to := strings.ToLower(match[1]), strings.ToLower(match[2])
+	if from == to {
+		return crossfade{}, fmt.Errorf("%q: can't crossfade a target with itself: %w", spec, errInvalidSliderMode)
+	}
+
+	return crossfade{from: from, to: to}, nil
+}
+
+// populateSliderModes reads the slider modes from the config. sliders with a mode get their targets
+// from it, so this has to happen before populateSliderMapping
+func (cc *CanonicalConfig) populateSliderModes() {
+	cc.Crossfades = map[int]crossfade{}
+
+	for sliderIdxString, spec := range cc.userConfig.GetStringMapString(configKeySliderModes) {
+		sliderIdx, err := strconv.Atoi(sliderIdxString)
+		if err != nil || sliderIdx < 0 {
+			cc.logger.Warnw("Invalid slider index in slider modes, ignoring", "key", configKeySliderModes, "slider", sliderIdxString)
+			continue
+		}
+
+		mode, err := parseSliderMode(spec)
+		if err != nil {
+			cc.logger.Warnw("Invalid slider mode in config, ignoring", "slider", sliderIdx, "mode", spec, "error", err)
+			continue
+		}
+
+		cc.Crossfades[sliderIdx] = mode
+	}
+}
+
+// crossfadeValue returns the value a target of the given slider follows. that's just the slider's value,
+// except for the first target of a crossfading slider, which goes the other way. it works in both directions
+func (m *sessionMap) crossfadeValue(sliderID int, target string, value float32) float32 {
+	if mode, ok := m.deej.config.Crossfades[sliderID]; ok && strings.ToLower(target) == mode.from {
+		return 1 - value
+	}
+
+	return value
+}
@@ -0,0 +1,117 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sliders can be given names ("Music", "Game", "Chat") that deej shows instead of their numbers: in the config
+// window, on the remote and status pages, in the tray's readout and on the board's display, if it has one. names
+// come from slider_names in the config, or for sliders it doesn't name, from the board's EEPROM (see BoardSettings)
+
+// populateSliderNames reads slider_names. names have to fit on the board's display, longer ones are cut short
+func (cc *CanonicalConfig) populateSliderNames() {
+	cc.SliderNames = map[int]string{}
+
+	for sliderIdxString, name := range cc.userConfig.GetStringMapString(configKeySliderNames) {
+		sliderIdx, err := strconv.Atoi(sliderIdxString)
+		if err != nil || sliderIdx < 0 {
+			cc.logger.Warnw("Invalid slider index in slider names, ignoring", "key", configKeySliderNames, "slider", sliderIdxString)
+			continue
+		}
+
+		name = cleanSliderName(name)
+		if name == "" {
+			continue
+		}
+
+		if runes := []rune(name); len(runes) > maxBoardNameLength {
+			cc.logger.Warnw("Slider name too long, cutting it short", "slider", sliderIdx, "name", name, "maxLength", maxBoardNameLength)
+			name = string(runes[:maxBoardNameLength])
+		}
+
+		cc.SliderNames[sliderIdx] = name
+	}
+}
+
+// cleanSliderName puts a slider's name on a single line, since it's sent to the board as part of one
+func cleanSliderName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// sliderName returns what a slider is called, or an empty string if neither the config nor the board names it
+func (d *Deej) sliderName(sliderIdx int) string {
+	if name, ok := d.config.SliderNames[sliderIdx]; ok {
+		return name
+	}
+
+	return d.serial.boardSliderName(sliderIdx)
+}
+
+// sliderLabel returns a slider's name, or if it has none, its number as users count them (from 1)
+func (d *Deej) sliderLabel(sliderIdx int) string {
+	if name := d.sliderName(sliderIdx); name != "" {
+		return name
+	}
+
+	return tr("tray.slider", strconv.Itoa(sliderIdx+1))
+}
+
+// boardSliderName returns the name the connected board keeps for a slider in its EEPROM, if it keeps one
+func (sio *SerialIO) boardSliderName(sliderIdx int) string {
+	sio.deviceLock.Lock()
+	defer sio.deviceLock.Unlock()
+
+	return sio.boardSliderNames[sliderIdx]
+}
+
+// loadSliderNames reads the slider names kept in the board's EEPROM, if it keeps any, and then sends each slider's
+// name to the board's display. it waits on the board's answer, so it can't run on the goroutine reading lines
+func (sio *SerialIO) loadSliderNames() {
+	if sio.Capabilities().Supports(capabilityEEPROM) {
+		settings, err := sio.ReadBoardSettings()
+		if err != nil {
+			sio.logger.Warnw("Failed to read slider names from the board", "error", err)
+		} else {
+			sio.deviceLock.Lock()
+			sio.boardSliderNames = settings.SliderNames
+			sio.deviceLock.Unlock()
+		}
+	}
+
+	sio.displaySliderNames()
+	sio.deej.showDeviceInfo()
+}
+
+// displaySliderNames sends the board's display the name of each slider whose name changed since it was last told,
+// an empty one for sliders without a name
+func (sio *SerialIO) displaySliderNames() {
+	if !sio.Capabilities().Supports(capabilityDisplay) {
+		return
+	}
+
+	for sliderIdx := 0; sliderIdx < sio.GetNumSliders(); sliderIdx++ {
+		name := sio.deej.sliderName(sliderIdx)
+
+		sio.deviceLock.Lock()
+		displayed, ok := sio.displayedSliderNames[sliderIdx]
+		sio.deviceLock.Unlock()
+
+		if ok && displayed == name {
+			continue
+		}
+
+		if err := sio.SendCommand(fmt.Sprintf("display:name:%d:%s", sliderIdx, name)); err != nil {
+			if !errors.Is(err, errCapabilityUnsupported) && !errors.Is(err, errNotConnected) {
+				sio.logger.Warnw("Failed to send slider name to display", "sliderID", sliderIdx, "error", err)
+			}
+			return
+		}
+
+		sio.deviceLock.Lock()
+		sio.displayedSliderNames[sliderIdx] = name
+		sio.deviceLock.Unlock()
+	}
+}
@@ -0,0 +1,183 @@
+package deej
+
+import (
+	"sync"
+
+	"github.com/thoas/go-funk"
+	"go.uber.org/zap"
+)
+
+// systemEvent is something the OS tells us about the session deej runs in
+type systemEvent int
+
+const (
+	systemLocked     systemEvent = iota // the screen locked, or the screensaver started
+	systemUnlocked                      // the screen unlocked, or the screensaver stopped
+	systemSuspending                    // the system is about to sleep
+	systemResumed                       // the system woke up
+)
+
+// what deej does while the screen is locked or the system sleeps, see configKeyOnLock
+const (
+	lockPolicyMute   = "mute"   // mute every session, and unmute them afterwards
+	lockPolicyFreeze = "freeze" // ignore slider input
+	lockPolicyDim    = "dim"    // turn the board's LEDs off
+)
+
+var supportedLockPolicies = []string{lockPolicyMute, lockPolicyFreeze, lockPolicyDim}
+
+// events are delivered from the platform's listener, which must never block
+const systemEventBufferSize = 16
+
+// systemEventWatcher represents a platform-specific source of lock and sleep notifications
+type systemEventWatcher interface {
+	Events() <-chan systemEvent
+	Close() error
+}
+
+func (e systemEvent) String() string {
+	switch e {
+	case systemLocked:
+		return "locked"
+	case systemUnlocked:
+		return "unlocked"
+	case systemSuspending:
+		return "suspending"
+	default:
+		return "resumed"
+	}
+}
+
+// lockPolicies applies the configured lock policies while the screen is locked or the system sleeps,
+// and undoes them afterwards
+type lockPolicies struct {
+	deej   *Deej
+	logger *zap.SugaredLogger
+
+	watcher systemEventWatcher
+
+	// the policies in effect, nil while unlocked. the system can lock and then sleep, so they're only
+	// applied once and undone once
+	applied []string
+
+	// session keys muted by lockPolicyMute, to unmute only those afterwards
+	mutedKeys []string
+
+	lock sync.Mutex
+}
+
+func newLockPolicies(deej *Deej, logger *zap.SugaredLogger) *lockPolicies {
+	logger = logger.Named("lock")
+
+	lp := &lockPolicies{
+		deej:   deej,
+		logger: logger,
+	}
+
+	logger.Debug("Created lock policies instance")
+
+	return lp
+}
+
+func (lp *lockPolicies) initialize() {
+	watcher, err := newSystemEventWatcher(lp.logger)
+	if err != nil {
+		lp.logger.Warnw("Lock and sleep notifications unavailable", "error", err)
+		return
+	}
+
+	lp.watcher = watcher
+
+	go func() {
+		for event := range watcher.Events() {
+			lp.handleSystemEvent(event)
+		}
+	}()
+}
+
+func (lp *lockPolicies) release() {
+	if lp.watcher == nil {
+		return
+	}
+
+	if err := lp.watcher.Close(); err != nil {
+		lp.logger.Warnw("Failed to close system event watcher", "error", err)
+	}
+}
+
+// frozen returns true while slider input should be ignored
+func (lp *lockPolicies) frozen() bool {
+	if lp == nil {
+		return false
+	}
+
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	return funk.ContainsString(lp.applied, lockPolicyFreeze)
+}
+
+func (lp *lockPolicies) handleSystemEvent(event systemEvent) {
+	lp.logger.Infow("System event", "event", event)
+
+	switch event {
+	case systemLocked, systemSuspending:
+		lp.apply()
+
+	case systemUnlocked:
+		lp.undo()
+
+	case systemResumed:
+		lp.undo()
+		lp.deej.recoverFromSleep()
+	}
+}
+
+func (lp *lockPolicies) apply() {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+
+	if lp.applied != nil {
+		return
+	}
+
+	lp.applied = append([]string{}, lp.deej.config.LockPolicies...)
+
+	if funk.ContainsString(lp.applied, lockPolicyMute) {
+		lp.mutedKeys = lp.deej.sessions.muteAll()
+	}
+
+	if funk.ContainsString(lp.applied, lockPolicyDim) {
+		lp.deej.serial.dimLEDs(true)
+	}
+
+	lp.logger.Debugw("Applied lock policies", "policies", lp.applied)
+}
+
+func (lp *lockPolicies) undo() {
+	lp.lock.Lock()
+
+	if lp.applied == nil {
+		lp.lock.Unlock()
+		return
+	}
+
+	applied, mutedKeys := lp.applied, lp.mutedKeys
+	lp.applied, lp.mutedKeys = nil, nil
+	lp.lock.Unlock()
+
+	if funk.ContainsString(applied, lockPolicyMute) {
+		lp.deej.sessions.unmuteAll(mutedKeys)
+	}
+
+	if funk.ContainsString(applied, lockPolicyDim) {
+		lp.deej.serial.dimLEDs(false)
+	}
+
+	// whatever the sliders did while frozen applies now
+	if funk.ContainsString(applied, lockPolicyFreeze) {
+		lp.deej.serial.resyncSliders()
+	}
+
+	lp.logger.Debugw("Undid lock policies", "policies", applied)
+}
@@ -0,0 +1,189 @@
+package deej
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/godbus/dbus/v5"
+	"go.uber.org/zap"
+)
+
+// logind tells us about sleep (PrepareForSleep) and whether our session is locked (LockedHint) over the system bus,
+// and desktops tell us about their screensaver over the session bus
+const (
+	logindBusName          = "org.freedesktop.login1"
+	logindObjectPath       = "/org/freedesktop/login1"
+	logindManagerInterface = "org.freedesktop.login1.Manager"
+	logindSessionInterface = "org.freedesktop.login1.Session"
+
+	dbusPropertiesInterface = "org.freedesktop.DBus.Properties"
+	screenSaverInterface    = "org.freedesktop.ScreenSaver"
+)
+
+type logindWatcher struct {
+	logger *zap.SugaredLogger
+
+	systemConn  *dbus.Conn
+	sessionConn *dbus.Conn
+
+	// our logind session, if we have one
+	sessionPath dbus.ObjectPath
+
+	events chan systemEvent
+}
+
+func newSystemEventWatcher(logger *zap.SugaredLogger) (systemEventWatcher, error) {
+	systemConn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("connect to system bus: %w", err)
+	}
+
+	w := &logindWatcher{
+		logger:     logger,
+		systemConn: systemConn,
+		events:     make(chan systemEvent, systemEventBufferSize),
+	}
+
+	// deej started outside a login session (i.e. as a system service) still hears about sleep
+	manager := systemConn.Object(logindBusName, logindObjectPath)
+	if err := manager.Call(logindManagerInterface+".GetSessionByPID", 0, uint32(os.Getpid())).Store(&w.sessionPath); err != nil {
+		logger.Debugw("Not in a logind session, won't know when the screen locks", "error", err)
+	}
+
+	systemSignals := make(chan *dbus.Signal, systemEventBufferSize)
+	systemConn.Signal(systemSignals)
+
+	matches := [][]dbus.MatchOption{
+		{dbus.WithMatchInterface(logindManagerInterface), dbus.WithMatchMember("PrepareForSleep")},
+	}
+
+	if w.sessionPath != "" {
+		matches = append(matches, []dbus.MatchOption{
+			dbus.WithMatchObjectPath(w.sessionPath),
+			dbus.WithMatchInterface(dbusPropertiesInterface),
+			dbus.WithMatchMember("PropertiesChanged"),
+		})
+	}
+
+	for _, match := range matches {
+		if err := systemConn.AddMatchSignal(match...); err != nil {
+			systemConn.Close()
+			return nil, fmt.Errorf("subscribe to logind signals: %w", err)
+		}
+	}
+
+	sessionSignals := make(chan *dbus.Signal, systemEventBufferSize)
+
+	// the screensaver is nice to have, so carry on without it
+	if sessionConn, err := dbus.ConnectSessionBus(); err != nil {
+		logger.Debugw("Failed to connect to session bus, won't know when the screensaver starts", "error", err)
+	} else if err := sessionConn.AddMatchSignal(dbus.WithMatchInterface(screenSaverInterface), dbus.WithMatchMember("ActiveChanged")); err != nil {
+		logger.Debugw("Failed to subscribe to screensaver signals", "error", err)
+		sessionConn.Close()
+	} else {
+		w.sessionConn = sessionConn
+		sessionConn.Signal(sessionSignals)
+	}
+
+	go w.forward(systemSignals, sessionSignals)
+
+	logger.Debug("Watching logind for lock and sleep events")
+
+	return w, nil
+}
+
+func (w *logindWatcher) Events() <-chan systemEvent {
+	return w.events
+}
+
+func (w *logindWatcher) Close() error {
+	if w.sessionConn != nil {
+		w.sessionConn.Close()
+	}
+
+	// closing the connection closes its signal channel, which ends forward
+	if err := w.systemConn.Close(); err != nil {
+		return fmt.Errorf("close system bus connection: %w", err)
+	}
+
+	return nil
+}
+
+func (w *logindWatcher) forward(systemSignals chan *dbus.Signal, sessionSignals chan *dbus.Signal) {
+	defer close(w.events)
+
+	for {
+		var signal *dbus.Signal
+		var ok bool
+
+		select {
+		case signal, ok = <-systemSignals:
+			if !ok {
+				w.logger.Debug("Stopped watching logind")
+				return
+			}
+
+		case signal, ok = <-sessionSignals:
+
+			// without the screensaver, keep going with logind alone
+			if !ok {
+				sessionSignals = nil
+				continue
+			}
+		}
+
+		if event, ok := w.eventFromSignal(signal); ok {
+			select {
+			case w.events <- event:
+			default:
+				w.logger.Warnw("System event channel full, dropping event", "event", event)
+			}
+		}
+	}
+}
+
+func (w *logindWatcher) eventFromSignal(signal *dbus.Signal) (systemEvent, bool) {
+	if signal == nil || len(signal.Body) == 0 {
+		return 0, false
+	}
+
+	switch signal.Name {
+	case logindManagerInterface + ".PrepareForSleep":
+		if sleeping, _ := signal.Body[0].(bool); sleeping {
+			return systemSuspending, true
+		}
+
+		return systemResumed, true
+
+	case screenSaverInterface + ".ActiveChanged":
+		if active, _ := signal.Body[0].(bool); active {
+			return systemLocked, true
+		}
+
+		return systemUnlocked, true
+
+	case dbusPropertiesInterface + ".PropertiesChanged":
+		if signal.Path != w.sessionPath || len(signal.Body) < 2 {
+			return 0, false
+		}
+
+		if iface, _ := signal.Body[0].(string); iface != logindSessionInterface {
+			return 0, false
+		}
+
+		changed, _ := signal.Body[1].(map[string]dbus.Variant)
+
+		lockedHint, ok := changed["LockedHint"]
+		if !ok {
+			return 0, false
+		}
+
+		if locked, _ := lockedHint.Value().(bool); locked {
+			return systemLocked, true
+		}
+
+		return systemUnlocked, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,166 @@
+package deej
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+	"go.uber.org/zap"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	systemEventWindowClassName = "deejSystemEventWindow"
+
+	// from winuser.h and wtsapi32.h
+	wmPowerBroadcast       = 0x0218
+	wmWTSSessionChange     = 0x02B1
+	pbtAPMSuspend          = 0x0004
+	pbtAPMResumeSuspend    = 0x0007
+	pbtAPMResumeAutomatic  = 0x0012
+	wtsSessionLock         = 0x7
+	wtsSessionUnlock       = 0x8
+	notifyForThisSessionID = 0
+)
+
+var (
+	wtsapi32DLL                          = windows.NewLazySystemDLL("wtsapi32.dll")
+	procWTSRegisterSessionNotification   = wtsapi32DLL.NewProc("WTSRegisterSessionNotification")
+	procWTSUnRegisterSessionNotification = wtsapi32DLL.NewProc("WTSUnRegisterSessionNotification")
+)
+
+// powerWatcher listens for power broadcasts (sleep and resume) and session changes (lock and unlock),
+// both of which windows delivers to a window
+type powerWatcher struct {
+	logger *zap.SugaredLogger
+
+	hwnd   win.HWND
+	events chan systemEvent
+
+	// windows sends both of these on resume, but we only need one
+	resumed bool
+}
+
+func newSystemEventWatcher(logger *zap.SugaredLogger) (systemEventWatcher, error) {
+	w := &powerWatcher{
+		logger: logger,
+		events: make(chan systemEvent, systemEventBufferSize),
+	}
+
+	ready := make(chan error)
+	go w.runMessageLoop(ready)
+
+	if err := <-ready; err != nil {
+		return nil, fmt.Errorf("create system event window: %w", err)
+	}
+
+	logger.Debug("Watching for lock and sleep events")
+
+	return w, nil
+}
+
+func (w *powerWatcher) Events() <-chan systemEvent {
+	return w.events
+}
+
+func (w *powerWatcher) Close() error {
+	win.PostMessage(w.hwnd, win.WM_CLOSE, 0, 0)
+	return nil
+}
+
+func (w *powerWatcher) runMessageLoop(ready chan error) {
+
+	// windows delivers messages to the thread that created the window, so we can't move around
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(w.events)
+
+	className, _ := syscall.UTF16PtrFromString(systemEventWindowClassName)
+	instance := win.GetModuleHandle(nil)
+
+	windowClass := win.WNDCLASSEX{
+		LpfnWndProc:   syscall.NewCallback(w.windowProc),
+		HInstance:     instance,
+		LpszClassName: className,
+	}
+	windowClass.CbSize = uint32(unsafe.Sizeof(windowClass))
+
+	if win.RegisterClassEx(&windowClass) == 0 {
+		ready <- fmt.Errorf("register window class: %w", syscall.GetLastError())
+		return
+	}
+
+	// power broadcasts only reach top-level windows, so this can't be a message-only window. it's never shown, though
+	w.hwnd = win.CreateWindowEx(0, className, className, 0, 0, 0, 0, 0, 0, 0, instance, nil)
+	if w.hwnd == 0 {
+		ready <- fmt.Errorf("create window: %w", syscall.GetLastError())
+		return
+	}
+
+	if ret, _, err := procWTSRegisterSessionNotification.Call(uintptr(w.hwnd), notifyForThisSessionID); ret == 0 {
+		w.logger.Warnw("Failed to register for session notifications, won't know when the screen locks", "error", err)
+	} else {
+		defer procWTSUnRegisterSessionNotification.Call(uintptr(w.hwnd))
+	}
+
+	ready <- nil
+
+	var msg win.MSG
+	for win.GetMessage(&msg, 0, 0, 0) > 0 {
+		win.TranslateMessage(&msg)
+		win.DispatchMessage(&msg)
+	}
+
+	w.logger.Debug("System event message loop ended")
+}
+
+func (w *powerWatcher) windowProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case wmPowerBroadcast:
+		switch wParam {
+		case pbtAPMSuspend:
+			w.resumed = false
+			w.send(systemSuspending)
+
+		case pbtAPMResumeAutomatic, pbtAPMResumeSuspend:
+			if !w.resumed {
+				w.resumed = true
+				w.send(systemResumed)
+			}
+		}
+
+		return 1
+
+	case wmWTSSessionChange:
+		switch wParam {
+		case wtsSessionLock:
+			w.send(systemLocked)
+		case wtsSessionUnlock:
+			w.send(systemUnlocked)
+		}
+
+		return 0
+
+	case win.WM_CLOSE:
+		win.DestroyWindow(hwnd)
+		return 0
+
+	case win.WM_DESTROY:
+		win.PostQuitMessage(0)
+		return 0
+	}
+
+	return win.DefWindowProc(hwnd, msg, wParam, lParam)
+}
+
+func (w *powerWatcher) send(event systemEvent) {
+
+	// don't block the message loop on a slow consumer
+	select {
+	case w.events <- event:
+	default:
+		w.logger.Warnw("System event channel full, dropping event", "event", event)
+	}
+}
@@ -0,0 +1,223 @@
+package deej
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/omriharel/deej/pkg/deej/util"
+)
+
+// a mistyped process name doesn't fail anywhere, its slider just does nothing. so targets that haven't matched a
+// single session for a while get pointed out, unless they're installed apps that simply haven't played anything yet
+const (
+	// how long a target gets to match a session before it's pointed out
+	unmatchedTargetGracePeriod = 2 * time.Minute
+
+	unmatchedTargetCheckInterval = 30 * time.Second
+)
+
+// targetDiagnostics keeps track of mapped targets that never matched any session
+type targetDiagnostics struct {
+	deej   *Deej
+	logger *zap.SugaredLogger
+
+	// target -> when it was first seen in the mapping
+	mappedSince map[string]time.Time
+
+	// the targets found unmatched by the last check, and those the user was already told about
+	unmatched map[string]bool
+	notified  map[string]bool
+
+	// lowercase names of installed apps, nil until needed. looking them up is slow, so it's done once per config
+	installedApps map[string]bool
+
+	lock sync.Mutex
+}
+
+func newTargetDiagnostics(deej *Deej, logger *zap.SugaredLogger) *targetDiagnostics {
+	logger = logger.Named("diagnostics")
+
+	td := &targetDiagnostics{
+		deej:        deej,
+		logger:      logger,
+		mappedSince: map[string]time.Time{},
+		unmatched:   map[string]bool{},
+		notified:    map[string]bool{},
+	}
+
+	logger.Debug("Created target diagnostics instance")
+
+	return td
+}
+
+func (td *targetDiagnostics) initialize() {
+	td.trackMappedTargets()
+
+	configReloadedChannel := td.deej.config.SubscribeToChanges()
+	go func() {
+		for range configReloadedChannel {
+			td.lock.Lock()
+			td.installedApps = nil
+			td.lock.Unlock()
+
+			td.trackMappedTargets()
+		}
+	}()
+
+	go func() {
+		for {
+			<-time.After(unmatchedTargetCheckInterval)
+			td.check()
+		}
+	}()
+}
+
+// isUnmatched returns true if the given mapping target hasn't matched any session since it was mapped
+func (td *targetDiagnostics) isUnmatched(target string) bool {
+	if td == nil {
+		return false
+	}
+
+	name, _, _ := splitTargetTrim(strings.ToLower(target))
+
+	td.lock.Lock()
+	defer td.lock.Unlock()
+
+	return td.unmatched[name]
+}
+
+// trackMappedTargets notes when each target in the mapping first showed up, and forgets the ones that are gone
+func (td *targetDiagnostics) trackMappedTargets() {
+	mapped := td.mappedTargets()
+
+	td.lock.Lock()
+	defer td.lock.Unlock()
+
+	now := time.Now()
+
+	for target := range mapped {
+		if _, ok := td.mappedSince[target]; !ok {
+			td.mappedSince[target] = now
+		}
+	}
+
+	for target := range td.mappedSince {
+		if !mapped[target] {
+			delete(td.mappedSince, target)
+			delete(td.unmatched, target)
+		}
+	}
+}
+
+// mappedTargets returns the mapping's targets that name a session, lowercase and without their trim. special
+// transforms and roles stand for whatever sessions there are, so they can't be mistyped in the same way
+func (td *targetDiagnostics) mappedTargets() map[string]bool {
+	mapped := map[string]bool{}
+
+	td.deej.config.SliderMapping.iterate(func(sliderIdx int, targets []string) {
+		for _, target := range targets {
+			name, _, _ := splitTargetTrim(strings.ToLower(target))
+
+			if td.deej.sessions.targetHasSpecialTransform(name) {
+				continue
+			}
+
+			if _, ok := roleTarget(name); ok {
+				continue
+			}
+
+			mapped[name] = true
+		}
+	})
+
+	return mapped
+}
+
+// check looks for targets that are past their grace period without having matched anything, and lets the user
+// know about the ones they weren't told about yet
+func (td *targetDiagnostics) check() {
+
+	// without the audio server there's nothing to match against, which says nothing about the targets
+	if atomic.LoadInt32(&td.deej.audioServerUnavailable) == 1 {
+		return
+	}
+
+	// sessions are only looked for when something asks, so make sure apps started since are known
+	td.deej.sessions.refreshSessions(false)
+
+	td.lock.Lock()
+	candidates := []string{}
+	for target, since := range td.mappedSince {
+		if time.Since(since) >= unmatchedTargetGracePeriod && !td.deej.sessions.everMatched(target) {
+			candidates = append(candidates, target)
+		}
+	}
+	td.lock.Unlock()
+
+	// outside the lock, looking up installed apps can take a while
+	installed := td.installed(len(candidates) > 0)
+
+	unmatched := map[string]bool{}
+	newlyUnmatched := []string{}
+
+	td.lock.Lock()
+	for _, target := range candidates {
+		if installed[target] {
+			continue
+		}
+
+		unmatched[target] = true
+
+		if !td.notified[target] {
+			td.notified[target] = true
+			newlyUnmatched = append(newlyUnmatched, target)
+		}
+	}
+
+	td.unmatched = unmatched
+	td.lock.Unlock()
+
+	if len(newlyUnmatched) == 0 {
+		return
+	}
+
+	td.logger.Warnw("Mapped targets haven't matched any audio session", "targets", newlyUnmatched)
+	td.deej.notifier.Notify(tr("notify.unmatched_targets.title"),
+		tr("notify.unmatched_targets.message", strings.Join(newlyUnmatched, ", ")))
+}
+
+// installed returns the lowercase names of installed apps, looking them up if needed and asked to
+func (td *targetDiagnostics) installed(lookUp bool) map[string]bool {
+	td.lock.Lock()
+	installedApps := td.installedApps
+	td.lock.Unlock()
+
+	if installedApps != nil || !lookUp {
+		return installedApps
+	}
+
+	getInstalledApps := getWindowsInstalledApps
+	if util.Linux() {
+		getInstalledApps = getLinuxInstalledApps
+	}
+
+	apps, err := getInstalledApps()
+	if err != nil {
+		td.logger.Warnw("Failed to get installed apps", "error", err)
+	}
+
+	installedApps = map[string]bool{}
+	for _, app := range apps {
+		installedApps[strings.ToLower(app.Name)] = true
+	}
+
+	td.lock.Lock()
+	td.installedApps = installedApps
+	td.lock.Unlock()
+
+	return installedApps
+}
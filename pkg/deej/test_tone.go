@@ -0,0 +1,111 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// a test tone is a short beep played on every output a slider's targets play on, so users can hear which speakers
+// or headphones a slider ends up controlling. it's played one output after the other, at the output's own volume
+
+// testToneSession is implemented by sessions that play on an output a test tone can be played on
+type testToneSession interface {
+
+	// testToneOutput returns the output the session plays on
+	testToneOutput() (testToneOutput, error)
+}
+
+// testToneOutput is an output a test tone can be played on
+type testToneOutput struct {
+
+	// identifies the output to the audio backend
+	ID string
+
+	// what to call it
+	Description string
+}
+
+const (
+	testToneFrequency  = 440
+	testToneDuration   = 0.6
+	testToneFadeLength = 0.02
+
+	// a gentle level, the output's volume might be all the way up
+	testToneAmplitude = 0.25
+)
+
+var errNoTestToneOutput = errors.New("none of the slider's targets plays on an output a test tone can be played on")
+
+// testToneSamples generates the test tone as mono samples at the given rate. it fades in and out, so it doesn't click
+func testToneSamples(sampleRate int) []float32 {
+	samples := make([]float32, int(testToneDuration*float64(sampleRate)))
+	fadeSamples := int(testToneFadeLength * float64(sampleRate))
+
+	for i := range samples {
+		level := testToneAmplitude
+		if edge := math.Min(float64(i), float64(len(samples)-1-i)); edge < float64(fadeSamples) {
+			level *= edge / float64(fadeSamples)
+		}
+
+		samples[i] = float32(level * math.Sin(2*math.Pi*testToneFrequency*float64(i)/float64(sampleRate)))
+	}
+
+	return samples
+}
+
+// playTestTone plays a test tone on each output the slider's targets play on, and returns the outputs it played on
+func (m *sessionMap) playTestTone(sliderID int) ([]string, error) {
+	targets, ok := m.deej.config.SliderMapping.get(sliderID)
+	if !ok {
+		return nil, fmt.Errorf("slider %d: %w", sliderID, errNoTestToneOutput)
+	}
+
+	outputs := map[string]testToneOutput{}
+
+	for _, target := range targets {
+		for _, resolvedTarget := range m.resolveTarget(target) {
+			sessions, _ := m.get(resolvedTarget)
+
+			for _, session := range sessions {
+				tts, ok := session.(testToneSession)
+				if !ok {
+					continue
+				}
+
+				output, err := tts.testToneOutput()
+				if err != nil {
+					m.logger.Warnw("Failed to find a session's output for a test tone", "session", session, "error", err)
+					continue
+				}
+
+				outputs[output.ID] = output
+			}
+		}
+	}
+
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("slider %d: %w", sliderID, errNoTestToneOutput)
+	}
+
+	ids := []string{}
+	for id := range outputs {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	played := []string{}
+	for _, id := range ids {
+		if err := playTestToneOn(outputs[id]); err != nil {
+			m.logger.Warnw("Failed to play test tone", "output", id, "error", err)
+			return played, fmt.Errorf("play test tone on %s: %w", outputs[id].Description, err)
+		}
+
+		m.logger.Debugw("Played test tone", "sliderID", sliderID, "output", id)
+		played = append(played, outputs[id].Description)
+	}
+
+	return played, nil
+}
@@ -0,0 +1,89 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jfreymuth/pulse"
+	"github.com/jfreymuth/pulse/proto"
+)
+
+const testToneSampleRate = 48000
+
+var errNotAnOutput = errors.New("session isn't an output")
+
+func (s *masterSession) testToneOutput() (testToneOutput, error) {
+	if !s.isOutput {
+		return testToneOutput{}, errNotAnOutput
+	}
+
+	return sinkTestToneOutput(s.client, s.streamIndex)
+}
+
+func (s *masterChannelSession) testToneOutput() (testToneOutput, error) {
+	return sinkTestToneOutput(s.client, s.sinkIndex)
+}
+
+// an app's stream plays on whichever sink it's on right now
+func (s *paSession) testToneOutput() (testToneOutput, error) {
+	var reply proto.GetSinkInputInfoReply
+
+	if err := s.client.Request(&proto.GetSinkInputInfo{SinkInputIndex: s.sinkInputIndex}, &reply); err != nil {
+		return testToneOutput{}, fmt.Errorf("get sink input info: %w", err)
+	}
+
+	return sinkTestToneOutput(s.client, reply.SinkIndex)
+}
+
+func sinkTestToneOutput(client *paClient, sinkIndex uint32) (testToneOutput, error) {
+	var reply proto.GetSinkInfoReply
+
+	if err := client.Request(&proto.GetSinkInfo{SinkIndex: sinkIndex}, &reply); err != nil {
+		return testToneOutput{}, fmt.Errorf("get sink info: %w", err)
+	}
+
+	return testToneOutput{ID: reply.SinkName, Description: reply.Device}, nil
+}
+
+// playTestToneOn plays the test tone on a sink, from a connection of its own so the session finder's isn't held up.
+// it returns once the tone has played
+func playTestToneOn(output testToneOutput) error {
+	client, err := pulse.NewClient(pulse.ClientApplicationName("deej"))
+	if err != nil {
+		return fmt.Errorf("connect to pulse: %w", err)
+	}
+
+	defer client.Close()
+
+	sink, err := client.SinkByID(output.ID)
+	if err != nil {
+		return fmt.Errorf("find sink: %w", err)
+	}
+
+	samples := testToneSamples(testToneSampleRate)
+	reader := pulse.Float32Reader(func(buf []float32) (int, error) {
+		n := copy(buf, samples)
+		samples = samples[n:]
+
+		if len(samples) == 0 {
+			return n, pulse.EndOfData
+		}
+
+		return n, nil
+	})
+
+	stream, err := client.NewPlayback(reader,
+		pulse.PlaybackSink(sink),
+		pulse.PlaybackSampleRate(testToneSampleRate),
+		pulse.PlaybackMediaName("deej test tone"))
+	if err != nil {
+		return fmt.Errorf("create playback stream: %w", err)
+	}
+
+	defer stream.Close()
+
+	stream.Start()
+	stream.Drain()
+
+	return stream.Error()
+}
@@ -0,0 +1,28 @@
+package deej
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTestToneSamples(t *testing.T) {
+	samples := testToneSamples(48000)
+
+	if expected := int(testToneDuration * 48000); len(samples) != expected {
+		t.Fatalf("expected %d samples, got %d", expected, len(samples))
+	}
+
+	// faded in and out, so it doesn't click
+	if samples[0] != 0 || samples[len(samples)-1] != 0 {
+		t.Errorf("expected the tone to start and end silent, got %f and %f", samples[0], samples[len(samples)-1])
+	}
+
+	peak := 0.0
+	for _, sample := range samples {
+		peak = math.Max(peak, math.Abs(float64(sample)))
+	}
+
+	if peak > testToneAmplitude || peak < testToneAmplitude*0.9 {
+		t.Errorf("expected the tone to peak at %f, got %f", testToneAmplitude, peak)
+	}
+}
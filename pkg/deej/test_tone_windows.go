@@ -0,0 +1,10 @@
+package deej
+
+import "errors"
+
+// no windows session plays on an output deej can play a test tone on yet, see testToneSession
+var errTestToneUnsupported = errors.New("test tones aren't supported on windows")
+
+func playTestToneOn(output testToneOutput) error {
+	return errTestToneUnsupported
+}
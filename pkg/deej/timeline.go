@@ -0,0 +1,134 @@
+package deej
+
+import (
+	"sync"
+	"time"
+)
+
+// the timeline is a rolling record of what happened to sessions, volumes and the device connection, kept in memory
+// for as long as deej runs. unlike the serial inspector it always records, since the reports it helps with are of
+// the "it stopped working after an hour" kind, and by then it's too late to start
+
+// TimelineEvent is a single thing that happened, or a run of the same thing happening over and over
+type TimelineEvent struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	Subject string    `json:"subject"` // the session key or port it happened to
+	Detail  string    `json:"detail,omitempty"`
+
+	// for volume events
+	Slider *int     `json:"slider,omitempty"`
+	Volume *float32 `json:"volume,omitempty"`
+
+	// how many times in a row it happened, with Time being the last
+	Count int `json:"count"`
+}
+
+const (
+	timelineKindSessionAdded   = "session_added"
+	timelineKindSessionRemoved = "session_removed"
+	timelineKindVolume         = "volume"          // deej set a session's volume because of a slider
+	timelineKindExternalVolume = "external_volume" // something else changed a mapped session's volume
+	timelineKindConnected      = "connected"
+	timelineKindDisconnected   = "disconnected"
+	timelineKindConnectFailed  = "connect_failed"
+	timelineKindAudioLost      = "audio_lost"
+	timelineKindAudioBack      = "audio_back"
+
+	// how many events to keep around
+	eventTimelineCapacity = 1000
+
+	// a slider moving produces a volume event per step, so events for the same session this close together
+	// are folded into one
+	eventTimelineFoldWindow = time.Second
+
+	// how far back to look for an event to fold into, since a slider can have several targets
+	eventTimelineFoldLookback = 8
+)
+
+// eventTimeline keeps the most recent events around for the web UI
+type eventTimeline struct {
+	events []TimelineEvent
+	lock   sync.Mutex
+}
+
+func newEventTimeline() *eventTimeline {
+	return &eventTimeline{}
+}
+
+// record adds an event to the timeline, or folds it into a recent one of the same kind and subject. volume events
+// fold as long as they're close together, anything else only when it's an exact repeat of the last event
+func (et *eventTimeline) record(event TimelineEvent) {
+	if et == nil {
+		return
+	}
+
+	et.lock.Lock()
+	defer et.lock.Unlock()
+
+	event.Time = time.Now()
+	event.Count = 1
+
+	if folded := et.fold(event); folded != nil {
+		folded.Count++
+		folded.Time = event.Time
+		folded.Volume = event.Volume
+		return
+	}
+
+	et.events = append(et.events, event)
+	if len(et.events) > eventTimelineCapacity {
+		et.events = et.events[len(et.events)-eventTimelineCapacity:]
+	}
+}
+
+// fold returns the event the given one can be folded into, if there is one. callers must hold the lock
+func (et *eventTimeline) fold(event TimelineEvent) *TimelineEvent {
+	if len(et.events) == 0 {
+		return nil
+	}
+
+	if event.Kind != timelineKindVolume {
+		last := &et.events[len(et.events)-1]
+		if last.Kind == event.Kind && last.Subject == event.Subject && last.Detail == event.Detail {
+			return last
+		}
+
+		return nil
+	}
+
+	for idx := len(et.events) - 1; idx >= 0 && idx >= len(et.events)-eventTimelineFoldLookback; idx-- {
+		candidate := &et.events[idx]
+		if candidate.Kind != event.Kind || candidate.Subject != event.Subject {
+			continue
+		}
+
+		if *candidate.Slider == *event.Slider && event.Time.Sub(candidate.Time) < eventTimelineFoldWindow {
+			return candidate
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+func (et *eventTimeline) recordSession(kind string, key string) {
+	et.record(TimelineEvent{Kind: kind, Subject: key})
+}
+
+func (et *eventTimeline) recordVolume(kind string, sliderID int, key string, volume float32) {
+	et.record(TimelineEvent{Kind: kind, Subject: key, Slider: &sliderID, Volume: &volume})
+}
+
+func (et *eventTimeline) recordConnection(kind string, port string, detail string) {
+	et.record(TimelineEvent{Kind: kind, Subject: port, Detail: detail})
+}
+
+// snapshot returns every event in the timeline, oldest first
+func (et *eventTimeline) snapshot() []TimelineEvent {
+	et.lock.Lock()
+	defer et.lock.Unlock()
+
+	return append([]TimelineEvent{}, et.events...)
+}
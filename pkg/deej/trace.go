@@ -0,0 +1,182 @@
+package deej
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sliderTrace records every line the device sends, and the volumes deej applies in response, to a file that can
+// later be replayed with --replay-trace. traces are JSON lines, with times relative to when recording started:
+//
+//	{"t":1503,"kind":"line","line":"deej:v2.0:sliders:512|1023"}
+//	{"t":1504,"kind":"volume","slider":0,"target":"master","volume":0.5}
+type sliderTrace struct {
+	logger *zap.SugaredLogger
+
+	file    *os.File
+	encoder *json.Encoder
+	start   time.Time
+
+	lock sync.Mutex
+}
+
+type traceEntry struct {
+	Time   int64   `json:"t"`
+	Kind   string  `json:"kind"`
+	Line   string  `json:"line,omitempty"`
+	Slider int     `json:"slider"`
+	Target string  `json:"target,omitempty"`
+	Volume float32 `json:"volume"`
+}
+
+const (
+	traceKindLine   = "line"
+	traceKindVolume = "volume"
+)
+
+var errInvalidTrace = errors.New("invalid trace")
+
+func newSliderTrace(logger *zap.SugaredLogger, path string) (*sliderTrace, error) {
+	logger = logger.Named("trace")
+
+	file, err := os.Create(path)
+	if err != nil {
+		logger.Warnw("Failed to create trace file", "path", path, "error", err)
+		return nil, fmt.Errorf("create trace file: %w", err)
+	}
+
+	logger.Infow("Recording slider trace", "path", path)
+
+	return &sliderTrace{
+		logger:  logger,
+		file:    file,
+		encoder: json.NewEncoder(file),
+		start:   time.Now(),
+	}, nil
+}
+
+// recordLine records a line received from the device. like the other record methods, it does nothing on a nil trace
+func (t *sliderTrace) recordLine(line string) {
+	if t == nil {
+		return
+	}
+
+	t.write(traceEntry{Kind: traceKindLine, Line: line})
+}
+
+// recordVolume records a volume deej applied to a target because of a slider
+func (t *sliderTrace) recordVolume(sliderID int, target string, volume float32) {
+	if t == nil {
+		return
+	}
+
+	t.write(traceEntry{Kind: traceKindVolume, Slider: sliderID, Target: target, Volume: volume})
+}
+
+func (t *sliderTrace) write(entry traceEntry) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.file == nil {
+		return
+	}
+
+	entry.Time = time.Since(t.start).Milliseconds()
+
+	if err := t.encoder.Encode(entry); err != nil {
+		t.logger.Warnw("Failed to write trace entry, no longer recording", "error", err)
+		t.file.Close()
+		t.file = nil
+	}
+}
+
+func (t *sliderTrace) close() {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.file == nil {
+		return
+	}
+
+	if err := t.file.Close(); err != nil {
+		t.logger.Warnw("Failed to close trace file", "error", err)
+	}
+
+	t.file = nil
+}
+
+// readTrace loads a recorded trace, in the order it was recorded
+func readTrace(path string) ([]traceEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open trace file: %w", err)
+	}
+	defer file.Close()
+
+	entries := []traceEntry{}
+	scanner := bufio.NewScanner(file)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		entry := traceEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %v: %w", lineNumber, err, errInvalidTrace)
+		}
+
+		if entry.Kind != traceKindLine && entry.Kind != traceKindVolume {
+			return nil, fmt.Errorf("line %d: unknown kind %q: %w", lineNumber, entry.Kind, errInvalidTrace)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read trace file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// replayTrace feeds a recorded trace's lines through the serial handling, at the pace they were recorded, in place
+// of a connected device. lines are handled one at a time and in order, so the same trace always plays out the same.
+// the recorded volumes are logged alongside, to compare against what deej applies this time around
+func (sio *SerialIO) replayTrace(path string) error {
+	logger := sio.logger.Named("replay")
+
+	entries, err := readTrace(path)
+	if err != nil {
+		logger.Warnw("Failed to read trace", "path", path, "error", err)
+		return fmt.Errorf("read trace: %w", err)
+	}
+
+	logger.Infow("Replaying slider trace", "path", path, "entries", len(entries))
+
+	start := time.Now()
+
+	for _, entry := range entries {
+		if wait := time.Duration(entry.Time)*time.Millisecond - time.Since(start); wait > 0 {
+			time.Sleep(wait)
+		}
+
+		switch entry.Kind {
+		case traceKindLine:
+			sio.handleLine(logger, entry.Line)
+		case traceKindVolume:
+			logger.Infow("Recorded volume", "sliderID", entry.Slider, "target", entry.Target, "volume", entry.Volume)
+		}
+	}
+
+	logger.Info("Finished replaying slider trace")
+
+	return nil
+}
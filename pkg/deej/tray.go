@@ -2,8 +2,11 @@ package deej
 
 import (
 	//"github.com/getlantern/systray"
-	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"fyne.io/systray"
 	"github.com/omriharel/deej/pkg/deej/icon"
@@ -18,6 +21,13 @@ const (
 	ThemeLight
 )
 
+// how often the slider readout in the tray's tooltip is redrawn at most while sliders move, and how long the
+// tooltip can be
+const (
+	trayReadoutInterval = 500 * time.Millisecond
+	maxTooltipLength    = 127
+)
+
 // TrayState represents the tray icon state
 type TrayState int
 
@@ -65,6 +75,17 @@ func containsIgnoreCase(s, substr string) bool {
 
 // SetTrayIcon sets the tray icon based on state and theme
 func (d *Deej) SetTrayIcon(state TrayState, theme ThemeType) {
+
+	// a board that's only plugged in now and then isn't missing until it's been around once
+	if state == TrayError && d.waitingPassively() {
+		state = TrayNormal
+	}
+
+	// nothing works without the audio server, however happy the arduino is
+	if atomic.LoadInt32(&d.audioServerUnavailable) == 1 {
+		state = TrayError
+	}
+
 	switch state {
 	case TrayNormal:
 		switch theme {
@@ -83,6 +104,154 @@ func (d *Deej) SetTrayIcon(state TrayState, theme ThemeType) {
 	}
 }
 
+// setAudioServerAvailable keeps the tray icon in its error state while the audio server can't be reached
+func (d *Deej) setAudioServerAvailable(available bool) {
+	if available {
+		atomic.StoreInt32(&d.audioServerUnavailable, 0)
+
+		// the serial connection decides from here on
+		if d.serial.isConnected() {
+			d.SetTrayIcon(TrayNormal, DetectSystemTheme())
+		}
+
+		return
+	}
+
+	atomic.StoreInt32(&d.audioServerUnavailable, 1)
+	d.SetTrayIcon(TrayError, DetectSystemTheme())
+}
+
+// showDeviceInfo puts the connected board's port, firmware version and slider count in the tray's tooltip and menu,
+// so users can tell which board is attached without digging through logs
+func (d *Deej) showDeviceInfo() {
+	if d.deviceMenuItem == nil {
+		return
+	}
+
+	info := describeDevice(d.serial.Status())
+
+	tooltip := "deej - " + info
+	if readout := d.sliderReadout(); readout != "" {
+		tooltip += "\n" + readout
+	}
+
+	// windows cuts tooltips off at 128 characters, without ending them
+	if runes := []rune(tooltip); len(runes) > maxTooltipLength {
+		tooltip = string(runes[:maxTooltipLength])
+	}
+
+	systray.SetTooltip(tooltip)
+	d.deviceMenuItem.SetTitle(info)
+}
+
+// sliderReadout lists where each of the board's sliders is at, by name where they have one: "Music 40%, Slider 2 75%"
+func (d *Deej) sliderReadout() string {
+	parts := []string{}
+
+	for sliderIdx := 0; sliderIdx < d.serial.GetNumSliders(); sliderIdx++ {
+		if value, ok := d.sliderValue(sliderIdx); ok {
+			parts = append(parts, tr("tray.slider_readout", d.sliderLabel(sliderIdx), strconv.Itoa(int(value*100+0.5))))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// watchSliderReadout keeps the tray's slider readout current as sliders move and names change. moves are gathered
+// up for trayReadoutInterval, so dragging a slider doesn't redraw the tooltip on every step
+func (d *Deej) watchSliderReadout() {
+	sliderEventsChannel := d.serial.SubscribeToSliderMoveEvents("tray")
+	configReloadedChannel := d.config.SubscribeToChanges()
+
+	var redraw <-chan time.Time
+
+	for {
+		select {
+		case <-sliderEventsChannel:
+			if redraw == nil {
+				redraw = time.After(trayReadoutInterval)
+			}
+			continue
+
+		case <-configReloadedChannel:
+		case <-redraw:
+		}
+
+		redraw = nil
+		d.showDeviceInfo()
+	}
+}
+
+// showNextScheduled puts the schedule's next actions in the tray menu, or hides them if there's no schedule
+func (d *Deej) showNextScheduled() {
+	if d.scheduleMenuItem == nil {
+		return
+	}
+
+	now := time.Now()
+
+	at, actions := d.scheduler.next(now)
+	if at.IsZero() {
+		d.scheduleMenuItem.Hide()
+		return
+	}
+
+	d.scheduleMenuItem.SetTitle(tr("tray.schedule_next", strings.Join(actions, ", "), describeScheduledTime(at, now)))
+	d.scheduleMenuItem.Show()
+}
+
+// showTrayNotification puts a notification in the tray menu, cut short if it's long
+func showTrayNotification(item *systray.MenuItem, tooltip string, title string, message string) {
+	text := tr("tray.notification", title, message)
+	if runes := []rune(text); len(runes) > maxTooltipLength {
+		text = string(runes[:maxTooltipLength])
+	}
+
+	item.SetTitle(text)
+	item.SetTooltip(tooltip)
+	item.Show()
+}
+
+// describeDevice sums up a serial status, leaving out whatever the board hasn't told us yet
+func describeDevice(status SerialStatus) string {
+	if !status.Connected {
+		switch {
+		case status.Detection == nil:
+			return tr("tray.device_disconnected")
+		case status.Detection.Active:
+			return tr("tray.device_detecting", status.Detection.Port, strconv.FormatUint(uint64(status.Detection.BaudRate), 10))
+		default:
+			return tr("tray.device_not_found", strconv.Itoa(status.Detection.probedPorts()))
+		}
+	}
+
+	parts := []string{tr("tray.device_port", status.Port)}
+
+	switch status.FirmwareVersion {
+	case "":
+	case legacyProtocol.raw:
+		parts = append(parts, tr("tray.device_legacy_firmware"))
+	default:
+		parts = append(parts, tr("tray.device_firmware", status.FirmwareVersion))
+	}
+
+	if status.NumSliders > 0 {
+		parts = append(parts, tr("tray.device_sliders", strconv.Itoa(status.NumSliders)))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// showUpdateMenuItem offers the given release in the tray menu
+func (d *Deej) showUpdateMenuItem(tag string) {
+	if d.updateMenuItem == nil {
+		return
+	}
+
+	d.updateMenuItem.SetTitle(tr("tray.update_to", tag))
+	d.updateMenuItem.Show()
+}
+
 func (d *Deej) initializeTray(onDone func()) {
 	logger := d.logger.Named("tray")
 
@@ -102,29 +271,94 @@ func (d *Deej) initializeTray(onDone func()) {
 		systray.SetTitle("deej")
 		systray.SetTooltip("deej")
 
-		editConfig := systray.AddMenuItem("Edit configuration", "Open config file with notepad")
+		editConfig := systray.AddMenuItem(tr("tray.edit_config"), tr("tray.edit_config.tooltip"))
 		editConfig.SetIcon(icon.EditConfig)
 
-		configWindow := systray.AddMenuItem("Configuration Window", "Open web-based configuration interface")
+		configWindow := systray.AddMenuItem(tr("tray.config_window"), tr("tray.config_window.tooltip"))
 		configWindow.SetIcon(icon.EditConfig)
 
-		refreshSessions := systray.AddMenuItem("Re-scan audio sessions", "Manually refresh audio sessions if something's stuck")
+		// in manual mode this is the only way deej finds new apps, so say so
+		refreshSessionsTooltip := tr("tray.refresh_sessions.tooltip")
+		if d.config.SessionRefresh == sessionRefreshManual {
+			refreshSessionsTooltip = tr("tray.refresh_sessions.tooltip_manual")
+		}
+
+		refreshSessions := systray.AddMenuItem(tr("tray.refresh_sessions"), refreshSessionsTooltip)
 		refreshSessions.SetIcon(icon.RefreshSessions)
 
+		reconnectAudio := systray.AddMenuItem(tr("tray.reconnect_audio"), tr("tray.reconnect_audio.tooltip"))
+
+		// only linux has group-based serial permissions for us to help with
+		fixPermissions := systray.AddMenuItem(tr("tray.fix_permissions"), tr("tray.fix_permissions.tooltip"))
+		if !util.Linux() {
+			fixPermissions.Hide()
+		}
+
+		// windows' counterpart, for picking the board's port
+		setupBoard := systray.AddMenuItem(tr("tray.setup_board"), tr("tray.setup_board.tooltip"))
+		if util.Linux() {
+			setupBoard.Hide()
+		}
+
+		startsAtLogin, err := autostartEnabled()
+		if err != nil {
+			logger.Warnw("Failed to check whether deej starts at login", "error", err)
+		}
+
+		autostart := systray.AddMenuItemCheckbox(tr("tray.autostart"), tr("tray.autostart.tooltip"), startsAtLogin)
+		d.autostartLock.Lock()
+		d.autostartMenuItem = autostart
+		d.autostartLock.Unlock()
+
+		verbose := systray.AddMenuItemCheckbox(tr("tray.verbose"), tr("tray.verbose.tooltip"), d.Verbose())
+		d.verboseMenuItem = verbose
+
+		// shown once there's something to update to
+		update := systray.AddMenuItem(tr("tray.update"), tr("tray.update.tooltip"))
+		update.Hide()
+		d.updateMenuItem = update
+
 		// Arduino commands submenu
-		arduinoMenu := systray.AddMenuItem("Arduino Commands", "Send commands to the Arduino")
+		arduinoMenu := systray.AddMenuItem(tr("tray.arduino"), tr("tray.arduino.tooltip"))
+
+		rebootArduino := arduinoMenu.AddSubMenuItem(tr("tray.reboot_arduino"), tr("tray.reboot_arduino.tooltip"))
+		requestVersion := arduinoMenu.AddSubMenuItem(tr("tray.request_version"), tr("tray.request_version.tooltip"))
+
+		systray.AddSeparator()
 
-		rebootArduino := arduinoMenu.AddSubMenuItem("Reboot Arduino", "Soft reboot the Arduino device")
-		requestVersion := arduinoMenu.AddSubMenuItem("Request Version", "Get Arduino firmware version")
+		// what's connected, kept up to date by showDeviceInfo
+		deviceInfo := systray.AddMenuItem("", "")
+		deviceInfo.Disable()
+		d.deviceMenuItem = deviceInfo
+		d.showDeviceInfo()
+		go d.watchSliderReadout()
+
+		// what the schedule does next, kept up to date by showNextScheduled
+		scheduleInfo := systray.AddMenuItem("", "")
+		scheduleInfo.Disable()
+		d.scheduleMenuItem = scheduleInfo
+		d.showNextScheduled()
+
+		// the latest notification, when they can't be shown on the desktop
+		notificationInfo := systray.AddMenuItem("", "")
+		notificationInfo.Disable()
+		notificationInfo.Hide()
+
+		if tn, ok := d.notifier.(trayNotifier); ok {
+			tooltip := tr("tray.notification.tooltip", tn.missingPieces())
+
+			tn.setTrayFallback(func(title string, message string) {
+				showTrayNotification(notificationInfo, tooltip, title, message)
+			})
+		}
 
 		if d.version != "" {
-			systray.AddSeparator()
 			versionInfo := systray.AddMenuItem(d.version, "")
 			versionInfo.Disable()
 		}
 
 		systray.AddSeparator()
-		quit := systray.AddMenuItem("Quit", "Stop deej and quit")
+		quit := systray.AddMenuItem(tr("tray.quit"), tr("tray.quit.tooltip"))
 
 		// wait on things to happen
 		go func() {
@@ -154,21 +388,7 @@ func (d *Deej) initializeTray(onDone func()) {
 				case <-configWindow.ClickedCh:
 					logger.Info("Configuration window menu item clicked, opening web config interface")
 
-					webConfig := NewWebConfigServer(d, logger)
-					go func() {
-						if err := webConfig.Start(); err != nil && err != http.ErrServerClosed {
-							logger.Errorw("Web config server error", "error", err)
-						}
-					}()
-
-					// Open the web browser
-					browserCmd := "xdg-open"
-					if !util.Linux() {
-						browserCmd = "start"
-					}
-					if err := util.OpenExternal(logger, browserCmd, "http://localhost:8080"); err != nil {
-						logger.Warnw("Failed to open web browser", "error", err)
-					}
+					d.openWebConfig(webConfigIndexPage)
 
 				// refresh sessions
 				case <-refreshSessions.ClickedCh:
@@ -178,18 +398,81 @@ func (d *Deej) initializeTray(onDone func()) {
 					// right-click -> select-this-option sequence at a rate that's meaningful to performance
 					d.sessions.refreshSessions(true)
 
+				// reconnect audio backend
+				case <-reconnectAudio.ClickedCh:
+					logger.Info("Reconnect audio backend menu item clicked, renewing session finder")
+
+					// connecting to the audio server can take a moment, the tray should respond in the meantime
+					go func() {
+						if err := d.sessions.reconnectAudioBackend(); err != nil {
+							logger.Warnw("Failed to reconnect audio backend", "error", err)
+							d.notifyError(err)
+						}
+					}()
+
+				// fix serial permissions
+				case <-fixPermissions.ClickedCh:
+					logger.Info("Fix permissions menu item clicked, starting serial permissions helper")
+
+					// the helper's dialogs block, and we still want the tray to respond in the meantime
+					go d.permissions.Fix()
+
+				// board setup
+				case <-setupBoard.ClickedCh:
+					logger.Info("Set up board menu item clicked, opening setup page")
+
+					d.openWebConfig(webConfigSetupPage)
+
+				// autostart
+				case <-autostart.ClickedCh:
+					logger.Infow("Autostart menu item clicked, toggling autostart", "enabled", !autostart.Checked())
+
+					if err := d.setAutostart(!autostart.Checked()); err != nil {
+						d.notifier.Notify(tr("notify.autostart_failed.title"), err.Error())
+					}
+
+				// verbose mode
+				case <-verbose.ClickedCh:
+					logger.Infow("Verbose menu item clicked, toggling verbose mode", "enabled", !verbose.Checked())
+					d.SetVerbose(!verbose.Checked())
+
+				// update
+				case <-update.ClickedCh:
+					logger.Info("Update menu item clicked, installing update")
+
+					// downloading takes a while, and we still want the tray to respond in the meantime
+					go func() {
+						if err := d.updates.install(); err != nil {
+							d.notifier.Notify(tr("notify.update_failed.title"), err.Error())
+						}
+					}()
+
 				// Arduino commands
+				// both wait for the Arduino to answer, and we still want the tray to respond in the meantime
 				case <-rebootArduino.ClickedCh:
 					logger.Info("Reboot Arduino menu item clicked, sending reboot command")
-					if err := d.serial.RebootArduino(); err != nil {
-						logger.Warnw("Failed to send reboot command to Arduino", "error", err)
-					}
+
+					go func() {
+						if err := d.serial.RebootArduino(); err != nil {
+							logger.Warnw("Failed to reboot Arduino", "error", err)
+							d.notifier.Notify(tr("notify.arduino_reboot_failed.title"), errorMessage(err))
+						}
+					}()
 
 				case <-requestVersion.ClickedCh:
 					logger.Info("Request version menu item clicked, sending version request")
-					if err := d.serial.RequestVersion(); err != nil {
-						logger.Warnw("Failed to send version request to Arduino", "error", err)
-					}
+
+					go func() {
+						version, err := d.serial.RequestVersion()
+						if err != nil {
+							logger.Warnw("Failed to get Arduino firmware version", "error", err)
+							d.notifier.Notify(tr("notify.firmware_version_failed.title"), errorMessage(err))
+							return
+						}
+
+						logger.Infow("Arduino firmware version", "version", version)
+						d.notifier.Notify(tr("notify.firmware_version.title"), tr("notify.firmware_version.message", version))
+					}()
 				}
 			}
 		}()
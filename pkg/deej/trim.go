@@ -0,0 +1,100 @@
+package deej
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// a trim adjusts a single target's volume relative to its slider, to even out apps that are much louder or quieter
+// than the others sharing it. trims are written after the target's name in the slider mapping ("spotify.exe(+10%)",
+// "discord.exe(x0.8)") or in the target_trim config section. offsets add to the slider's value, gains multiply it
+type volumeTrim struct {
+	offset float32
+	gain   float32
+}
+
+var noTrim = volumeTrim{gain: 1}
+
+// the last parenthesized part of a target, e.g. "spotify.exe (+10%)"
+var targetTrimPattern = regexp.MustCompile(`^(.+?)\s*\(([^()]*)\)$`)
+
+var errInvalidTrim = errors.New("invalid volume trim")
+
+// parseVolumeTrim parses trims in the form "+10%", "-5%", "x1.5" or "*0.8"
+func parseVolumeTrim(spec string) (volumeTrim, error) {
+	spec = strings.ToLower(strings.Join(strings.Fields(spec), ""))
+
+	switch {
+	case strings.HasPrefix(spec, "x"), strings.HasPrefix(spec, "*"):
+		gain, err := strconv.ParseFloat(spec[1:], 32)
+		if err != nil || gain < 0 {
+			return noTrim, fmt.Errorf("%q: %w", spec, errInvalidTrim)
+		}
+
+		return volumeTrim{gain: float32(gain)}, nil
+
+	case strings.HasPrefix(spec, "+"), strings.HasPrefix(spec, "-"):
+		if !strings.HasSuffix(spec, "%") {
+			return noTrim, fmt.Errorf("%q: offsets need a %% sign: %w", spec, errInvalidTrim)
+		}
+
+		offset, err := strconv.ParseFloat(strings.TrimSuffix(spec, "%"), 32)
+		if err != nil {
+			return noTrim, fmt.Errorf("%q: %w", spec, errInvalidTrim)
+		}
+
+		return volumeTrim{offset: float32(offset / 100), gain: 1}, nil
+	}
+
+	return noTrim, fmt.Errorf("%q: %w", spec, errInvalidTrim)
+}
+
+// splitTargetTrim separates a mapping target from its inline trim, if it has one. parentheses that don't hold
+// a valid trim are left alone, since they're also part of device names like "Speakers (Realtek Audio)"
+func splitTargetTrim(target string) (string, volumeTrim, bool) {
+	match := targetTrimPattern.FindStringSubmatch(target)
+	if match == nil {
+		return target, noTrim, false
+	}
+
+	trim, err := parseVolumeTrim(match[2])
+	if err != nil {
+		return target, noTrim, false
+	}
+
+	return match[1], trim, true
+}
+
+// apply returns the volume for a target whose slider is at the given value, where the top of the slider
+// stands for ceiling (1, unless the slider's allowed to boost). the bottom of the slider stays silent
+func (t volumeTrim) apply(sliderValue float32, ceiling float32) float32 {
+	if sliderValue <= 0 {
+		return 0
+	}
+
+	return clampVolume(sliderValue*ceiling*t.gain+t.offset, ceiling)
+}
+
+// invert returns where a slider has to be for its trimmed target to end up at the given volume
+func (t volumeTrim) invert(volume float32, ceiling float32) float32 {
+	if volume <= 0 || t.gain == 0 || ceiling <= 0 {
+		return 0
+	}
+
+	return clampVolume((volume-t.offset)/t.gain/ceiling, 1)
+}
+
+func clampVolume(volume float32, ceiling float32) float32 {
+	if volume < 0 {
+		return 0
+	}
+
+	if volume > ceiling {
+		return ceiling
+	}
+
+	return volume
+}
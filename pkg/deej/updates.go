@@ -0,0 +1,422 @@
+package deej
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/omriharel/deej/pkg/deej/util"
+)
+
+const (
+	updateReleasesURL = "https://api.github.com/repos/NiyuniCidron/deej/releases/latest"
+
+	// how often to look for a new release, the first time being shortly after startup
+	updateCheckInterval = 24 * time.Hour
+	updateFirstCheck    = 30 * time.Second
+
+	updateRequestTimeout  = 15 * time.Second
+	updateDownloadTimeout = 5 * time.Minute
+
+	// no deej build comes anywhere near this, so anything bigger isn't one
+	updateMaxDownloadSize = 64 << 20
+
+	// each release asset is signed, the signature (base64) being another asset named after it. what's signed is the
+	// asset along with the release it belongs to, see updateSignedMessage
+	updateSignatureSuffix = ".sig"
+
+	// where the replaced executable goes until the next start, since windows won't delete a running one
+	updatePreviousSuffix = ".old"
+)
+
+var (
+	errNoUpdateAvailable = errors.New("no update available")
+	errUpdateUnsigned    = errors.New("this build of deej has no key to verify updates with, download it from the releases page instead")
+)
+
+// releaseInfo describes a release newer than the running one
+type releaseInfo struct {
+	Tag string `json:"tag"`
+	URL string `json:"url"`
+
+	assetURL     string
+	signatureURL string
+}
+
+// githubRelease is the part of the releases API's response we care about
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+	Assets  []struct {
+		Name        string `json:"name"`
+		DownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// updateChecker looks for new releases, and installs them when asked to
+type updateChecker struct {
+	deej   *Deej
+	logger *zap.SugaredLogger
+
+	// the running release's tag, and the key release assets are signed with. either can be empty for dev builds
+	currentTag string
+	publicKey  ed25519.PublicKey
+
+	// the newest release found so far, nil until one is
+	available *releaseInfo
+	lock      sync.Mutex
+
+	// only one install at a time
+	installLock sync.Mutex
+}
+
+func newUpdateChecker(deej *Deej, logger *zap.SugaredLogger) *updateChecker {
+	logger = logger.Named("updates")
+
+	uc := &updateChecker{
+		deej:   deej,
+		logger: logger,
+	}
+
+	logger.Debug("Created update checker instance")
+
+	return uc
+}
+
+// setRelease tells the update checker which release is running and how to verify the next one
+func (uc *updateChecker) setRelease(tag string, publicKey string) {
+	uc.currentTag = tag
+
+	if publicKey == "" {
+		return
+	}
+
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		uc.logger.Warnw("Ignoring invalid update signing key", "error", err)
+		return
+	}
+
+	uc.publicKey = ed25519.PublicKey(key)
+}
+
+func (uc *updateChecker) initialize() {
+	uc.removePreviousExecutable()
+
+	if _, ok := parseReleaseVersion(uc.currentTag); !ok {
+		uc.logger.Debugw("Not a release build, won't check for updates", "tag", uc.currentTag)
+		return
+	}
+
+	go func() {
+		<-time.After(updateFirstCheck)
+
+		for {
+			if uc.deej.config.CheckForUpdates {
+				uc.checkAndNotify()
+			}
+
+			<-time.After(updateCheckInterval)
+		}
+	}()
+}
+
+// latest returns the newest release found so far, or nil if the running one is the newest
+func (uc *updateChecker) latest() *releaseInfo {
+	uc.lock.Lock()
+	defer uc.lock.Unlock()
+
+	return uc.available
+}
+
+// canInstall returns true if updates can be installed from within deej, rather than downloaded by hand
+func (uc *updateChecker) canInstall() bool {
+	return uc.publicKey != nil
+}
+
+func (uc *updateChecker) checkAndNotify() {
+	release, err := uc.check()
+	if err != nil {
+		uc.logger.Debugw("Failed to check for updates", "error", err)
+		return
+	}
+
+	if release == nil {
+		uc.logger.Debug("deej is up to date")
+		return
+	}
+
+	uc.lock.Lock()
+	alreadyKnown := uc.available != nil && uc.available.Tag == release.Tag
+	uc.available = release
+	uc.lock.Unlock()
+
+	if alreadyKnown {
+		return
+	}
+
+	uc.logger.Infow("Found a newer release", "current", uc.currentTag, "latest", release.Tag)
+
+	uc.deej.showUpdateMenuItem(release.Tag)
+
+	message := tr("notify.update_available.notes", release.URL)
+	if uc.canInstall() {
+		message = tr("notify.update_available.install")
+	}
+
+	uc.deej.notifier.Notify(tr("notify.update_available.title", release.Tag), message)
+}
+
+// check asks github for the latest release, and returns it if it's newer than the running one
+func (uc *updateChecker) check() (*releaseInfo, error) {
+	current, ok := parseReleaseVersion(uc.currentTag)
+	if !ok {
+		return nil, fmt.Errorf("not a release build (%s)", uc.currentTag)
+	}
+
+	body, err := download(updateReleasesURL, updateRequestTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("get latest release: %w", err)
+	}
+
+	var release githubRelease
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("parse latest release: %w", err)
+	}
+
+	latest, ok := parseReleaseVersion(release.TagName)
+	if !ok || !newerReleaseVersion(latest, current) {
+		return nil, nil
+	}
+
+	info := &releaseInfo{
+		Tag: release.TagName,
+		URL: release.HTMLURL,
+	}
+
+	for _, asset := range release.Assets {
+		switch asset.Name {
+		case updateAssetName:
+			info.assetURL = asset.DownloadURL
+		case updateAssetName + updateSignatureSuffix:
+			info.signatureURL = asset.DownloadURL
+		}
+	}
+
+	return info, nil
+}
+
+// install downloads the newest release, verifies its signature and puts it in place of the running executable.
+// it takes effect the next time deej starts
+func (uc *updateChecker) install() error {
+	uc.installLock.Lock()
+	defer uc.installLock.Unlock()
+
+	release := uc.latest()
+	if release == nil {
+		return errNoUpdateAvailable
+	}
+
+	if !uc.canInstall() {
+		return errUpdateUnsigned
+	}
+
+	if release.assetURL == "" || release.signatureURL == "" {
+		return fmt.Errorf("release %s has no signed %s, download it from %s instead", release.Tag, updateAssetName, release.URL)
+	}
+
+	uc.logger.Infow("Installing update", "tag", release.Tag)
+
+	binary, err := download(release.assetURL, updateDownloadTimeout)
+	if err != nil {
+		uc.logger.Warnw("Failed to download update", "error", err)
+		return fmt.Errorf("download update: %w", err)
+	}
+
+	encodedSignature, err := download(release.signatureURL, updateRequestTimeout)
+	if err != nil {
+		uc.logger.Warnw("Failed to download update signature", "error", err)
+		return fmt.Errorf("download update signature: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encodedSignature)))
+	if err != nil || !ed25519.Verify(uc.publicKey, updateSignedMessage(release.Tag, binary), signature) {
+		uc.logger.Warnw("Update signature doesn't match, not installing it", "tag", release.Tag, "error", err)
+		return fmt.Errorf("update %s isn't signed by the deej release key", release.Tag)
+	}
+
+	if err := replaceExecutable(binary); err != nil {
+		uc.logger.Warnw("Failed to replace executable", "error", err)
+		return fmt.Errorf("replace executable: %w", err)
+	}
+
+	uc.logger.Infow("Installed update", "tag", release.Tag)
+
+	uc.deej.notifier.Notify(tr("notify.updated.title", release.Tag), tr("notify.updated.message"))
+
+	return nil
+}
+
+// updateSignedMessage is what a release asset's signature is made over: its name and release tag, then the asset
+// itself. signing the asset alone would let an older (signed) build pass for a newer release
+//
+//	deej-linux v0.9.11\n<asset>
+func updateSignedMessage(tag string, asset []byte) []byte {
+	return append([]byte(fmt.Sprintf("%s %s\n", updateAssetName, tag)), asset...)
+}
+
+// removePreviousExecutable cleans up after the last update, now that it's no longer running
+func (uc *updateChecker) removePreviousExecutable() {
+	executable, err := currentExecutable()
+	if err != nil {
+		return
+	}
+
+	previous := executable + updatePreviousSuffix
+	if !util.FileExists(previous) {
+		return
+	}
+
+	if err := os.Remove(previous); err != nil {
+		uc.logger.Debugw("Failed to remove executable left over from the last update", "path", previous, "error", err)
+	}
+}
+
+// replaceExecutable moves the running executable aside and puts the new one in its place
+func replaceExecutable(binary []byte) error {
+	executable, err := currentExecutable()
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(executable)
+	if err != nil {
+		return fmt.Errorf("stat executable: %w", err)
+	}
+
+	next := executable + ".new"
+	previous := executable + updatePreviousSuffix
+
+	if err := ioutil.WriteFile(next, binary, info.Mode()); err != nil {
+		return fmt.Errorf("write new executable: %w", err)
+	}
+
+	os.Remove(previous)
+
+	// windows won't overwrite a running executable, but it will rename one
+	if err := os.Rename(executable, previous); err != nil {
+		os.Remove(next)
+		return fmt.Errorf("move running executable aside: %w", err)
+	}
+
+	if err := os.Rename(next, executable); err != nil {
+		os.Rename(previous, executable)
+		return fmt.Errorf("move new executable in place: %w", err)
+	}
+
+	return nil
+}
+
+func currentExecutable() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("get executable path: %w", err)
+	}
+
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		return "", fmt.Errorf("resolve executable path: %w", err)
+	}
+
+	return executable, nil
+}
+
+func download(url string, timeout time.Duration) ([]byte, error) {
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	// github's API refuses requests without one
+	request.Header.Set("User-Agent", "deej")
+
+	client := &http.Client{Timeout: timeout}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, updateMaxDownloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if len(body) > updateMaxDownloadSize {
+		return nil, fmt.Errorf("response is larger than %d bytes", updateMaxDownloadSize)
+	}
+
+	return body, nil
+}
+
+// parseReleaseVersion turns a release tag like "v0.9.10" into its numbers. anything else, like the
+// "v0.9.10-3-g1234567" git describe gives builds made between releases, isn't a release
+func parseReleaseVersion(tag string) ([]int, bool) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "v")
+	if tag == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(tag, ".")
+	version := make([]int, len(parts))
+
+	for idx, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return nil, false
+		}
+
+		version[idx] = number
+	}
+
+	return version, true
+}
+
+// newerReleaseVersion returns true if a is newer than b. missing numbers count as zero, so 1.2 and 1.2.0 are equal
+func newerReleaseVersion(a []int, b []int) bool {
+	for idx := 0; idx < len(a) || idx < len(b); idx++ {
+		var left, right int
+
+		if idx < len(a) {
+			left = a[idx]
+		}
+
+		if idx < len(b) {
+			right = b[idx]
+		}
+
+		if left != right {
+			return left > right
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,4 @@
+package deej
+
+// the release asset holding the executable for this platform
+const updateAssetName = "deej.exe"
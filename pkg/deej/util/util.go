@@ -39,17 +39,32 @@ func Linux() bool {
 // SetupCloseHandler creates a 'listener' on a new goroutine which will notify the
 // program if it receives an interrupt from the OS
 func SetupCloseHandler() chan os.Signal {
-	c := make(chan os.Signal)
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
 	return c
 }
 
+// SetupVerboseToggleHandler creates a 'listener' which will notify the program whenever
+// the user asks to toggle verbose mode from outside (SIGUSR1, on Linux)
+func SetupVerboseToggleHandler() chan os.Signal {
+	c := make(chan os.Signal, 1)
+	notifyVerboseToggle(c)
+
+	return c
+}
+
+// focus modes decide which window counts as the current one, which matters mostly with several monitors
+const (
+	FocusModeForeground = "foreground" // the window that last received keyboard/mouse input
+	FocusModeCursor     = "cursor"     // the window under the mouse cursor
+)
+
 // GetCurrentWindowProcessNames returns the process names (including extension, if applicable)
-// of the current foreground window. This includes child processes belonging to the window.
+// of the current window, as chosen by focusMode. This includes child processes belonging to the window.
 // This is currently only implemented for Windows
-func GetCurrentWindowProcessNames() ([]string, error) {
-	return getCurrentWindowProcessNames()
+func GetCurrentWindowProcessNames(focusMode string) ([]string, error) {
+	return getCurrentWindowProcessNames(focusMode)
 }
 
 // OpenExternal spawns a detached window with the provided command and argument
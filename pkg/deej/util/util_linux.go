@@ -2,8 +2,15 @@ package util
 
 import (
 	"errors"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
-func getCurrentWindowProcessNames() ([]string, error) {
+func getCurrentWindowProcessNames(focusMode string) ([]string, error) {
 	return nil, errors.New("Not implemented")
 }
+
+func notifyVerboseToggle(c chan os.Signal) {
+	signal.Notify(c, syscall.SIGUSR1)
+}
@@ -2,6 +2,8 @@ package util
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"syscall"
 	"time"
 	"unsafe"
@@ -12,23 +14,47 @@ import (
 
 const (
 	getCurrentWindowInternalCooldown = time.Millisecond * 350
+
+	// how many generations up from the foreground window's process to look for related processes
+	maxProcessAncestorDepth = 3
 )
 
+// shells and system processes start pretty much everything, so they say nothing about which app is in the
+// foreground. relatedProcessNames doesn't walk through them in either direction
+var shellProcessNames = []string{
+	"explorer.exe",
+	"sihost.exe",
+	"shellexperiencehost.exe",
+	"startmenuexperiencehost.exe",
+	"searchhost.exe",
+	"svchost.exe",
+	"services.exe",
+	"wininit.exe",
+	"winlogon.exe",
+	"userinit.exe",
+	"cmd.exe",
+	"powershell.exe",
+	"pwsh.exe",
+	"conhost.exe",
+}
+
 var (
 	lastGetCurrentWindowResult []string
+	lastGetCurrentWindowMode   string
 	lastGetCurrentWindowCall   = time.Now()
 )
 
-func getCurrentWindowProcessNames() ([]string, error) {
+func getCurrentWindowProcessNames(focusMode string) ([]string, error) {
 
 	// apply an internal cooldown on this function to avoid calling windows API functions too frequently.
 	// return a cached value during that cooldown
 	now := time.Now()
-	if lastGetCurrentWindowCall.Add(getCurrentWindowInternalCooldown).After(now) {
+	if focusMode == lastGetCurrentWindowMode && lastGetCurrentWindowCall.Add(getCurrentWindowInternalCooldown).After(now) {
 		return lastGetCurrentWindowResult, nil
 	}
 
 	lastGetCurrentWindowCall = now
+	lastGetCurrentWindowMode = focusMode
 
 	// the logic of this implementation is a bit convoluted because of the way UWP apps
 	// (also known as "modern win 10 apps" or "microsoft store apps") work.
@@ -41,6 +67,7 @@ func getCurrentWindowProcessNames() ([]string, error) {
 	// (like steam, and the league client, and any UWP app)
 
 	result := []string{}
+	windowPIDs := []uint32{}
 
 	// a callback that will be called for each child window of the foreground window, if it has any
 	enumChildWindowsCallback := func(childHWND *uintptr, lParam *uintptr) uintptr {
@@ -59,6 +86,7 @@ func getCurrentWindowProcessNames() ([]string, error) {
 			actualProcess, err := ps.FindProcess(int(childPID))
 			if err == nil {
 				result = append(result, actualProcess.Executable())
+				windowPIDs = append(windowPIDs, childPID)
 			}
 		}
 
@@ -66,8 +94,8 @@ func getCurrentWindowProcessNames() ([]string, error) {
 		return 1
 	}
 
-	// get the current foreground window
-	hwnd := win.GetForegroundWindow()
+	// get the current window
+	hwnd := currentWindow(focusMode)
 	var ownerPID uint32
 
 	// get its PID and put it in our window info struct
@@ -86,11 +114,111 @@ func getCurrentWindowProcessNames() ([]string, error) {
 
 	// add it to our result slice
 	result = append(result, process.Executable())
+	windowPIDs = append(windowPIDs, ownerPID)
 
 	// iterate its child windows, adding their names too
 	win.EnumChildWindows(hwnd, syscall.NewCallback(enumChildWindowsCallback), (uintptr)(unsafe.Pointer(&ownerPID)))
 
+	// audio often comes from somewhere else in the window's process tree - games started by a launcher,
+	// browsers that play audio in a separate process, and so on. include those processes as well
+	related, err := relatedProcessNames(windowPIDs)
+	if err == nil {
+		result = append(result, related...)
+	}
+
 	// cache & return whichever executable names we ended up with
 	lastGetCurrentWindowResult = result
 	return result, nil
 }
+
+// currentWindow returns the top-level window that counts as current in the given focus mode
+func currentWindow(focusMode string) win.HWND {
+	if focusMode == FocusModeCursor {
+		var cursor win.POINT
+
+		if win.GetCursorPos(&cursor) {
+
+			// the cursor is usually over one of the window's controls rather than the window itself
+			if hwnd := win.WindowFromPoint(cursor); hwnd != 0 {
+				return win.GetAncestor(hwnd, win.GA_ROOT)
+			}
+		}
+	}
+
+	return win.GetForegroundWindow()
+}
+
+// relatedProcessNames returns the executable names of all descendants of the given processes,
+// as well as their ancestors up to maxProcessAncestorDepth (stopping at shells and system processes, see
+// shellProcessNames)
+func relatedProcessNames(pids []uint32) ([]string, error) {
+	processes, err := ps.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("list processes: %w", err)
+	}
+
+	byPID := map[int]ps.Process{}
+	children := map[int][]ps.Process{}
+
+	for _, process := range processes {
+		byPID[process.Pid()] = process
+		children[process.PPid()] = append(children[process.PPid()], process)
+	}
+
+	result := []string{}
+	visited := map[int]bool{}
+
+	for _, pid := range pids {
+		process, ok := byPID[int(pid)]
+
+		// the focused window can be the shell's own (i.e. the desktop), whose descendants are every app
+		if ok && isShellProcess(process.Executable()) {
+			continue
+		}
+
+		// walk down through every descendant
+		queue := []int{int(pid)}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			for _, child := range children[current] {
+				if visited[child.Pid()] || isShellProcess(child.Executable()) {
+					continue
+				}
+
+				visited[child.Pid()] = true
+				result = append(result, child.Executable())
+				queue = append(queue, child.Pid())
+			}
+		}
+
+		// and up through a few ancestors
+		for depth := 0; ok && depth < maxProcessAncestorDepth; depth++ {
+
+			// pid 0 is its own parent
+			parent, found := byPID[process.PPid()]
+			if !found || parent.Pid() == process.Pid() || isShellProcess(parent.Executable()) {
+				break
+			}
+
+			result = append(result, parent.Executable())
+			process = parent
+		}
+	}
+
+	return result, nil
+}
+
+func isShellProcess(executable string) bool {
+	for _, name := range shellProcessNames {
+		if strings.EqualFold(executable, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// windows has no signal for this, the tray menu and configuration window will have to do
+func notifyVerboseToggle(c chan os.Signal) {}
@@ -0,0 +1,72 @@
+package deej
+
+import (
+	"strings"
+)
+
+// a cap limits how loud a session key can get, wherever its slider is, e.g. "quiet hours" keeping master at 30%
+// at night. caps are set by the cap action (usually on a schedule, see schedule.go) and only last until deej exits,
+// while volume_limits in the config always hold. either way, sessions that get louder some other way (or start out
+// louder) are turned back down
+
+// setVolumeCap caps the given session key's volume, or lifts its cap if the limit is 1 or more
+func (m *sessionMap) setVolumeCap(key string, limit float32) {
+	m.volumeLock.Lock()
+	defer m.volumeLock.Unlock()
+
+	key = strings.ToLower(key)
+
+	if limit >= 1 {
+		delete(m.volumeCaps, key)
+		return
+	}
+
+	m.volumeCaps[key] = limit
+}
+
+// clearVolumeCaps lifts every cap
+func (m *sessionMap) clearVolumeCaps() {
+	m.volumeLock.Lock()
+	defer m.volumeLock.Unlock()
+
+	m.volumeCaps = map[string]float32{}
+}
+
+// capVolume returns the given volume, lowered to the session key's cap or configured limit if it has one. an
+// instance key (see session_instances.go) without a limit of its own goes by its session key's
+func (m *sessionMap) capVolume(key string, volume float32) float32 {
+	limit, ok := m.deej.config.VolumeLimits[key]
+	if !ok {
+		limit, ok = m.deej.config.VolumeLimits[instanceBaseKey(key)]
+	}
+
+	if ok && volume > limit {
+		volume = limit
+	}
+
+	m.volumeLock.Lock()
+	defer m.volumeLock.Unlock()
+
+	if limit, ok := m.volumeCaps[key]; ok && volume > limit {
+		return limit
+	}
+
+	return volume
+}
+
+// enforceVolumeCap lowers a session that's louder than its cap, returning true if it had to
+func (m *sessionMap) enforceVolumeCap(session Session, volume float32) bool {
+	capped := m.capVolume(session.Key(), volume)
+	if capped >= volume {
+		return false
+	}
+
+	m.logger.Infow("Session is louder than its cap, lowering it", "session", session.Key(), "volume", volume, "cap", capped)
+	m.recordVolumeSet(session.Key(), capped)
+
+	if err := session.SetVolume(capped); err != nil {
+		m.logger.Warnw("Failed to lower session to its cap", "session", session.Key(), "error", err)
+	}
+
+	return true
+}
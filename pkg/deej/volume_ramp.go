@@ -0,0 +1,265 @@
+package deej
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// every volume a slider sets on a session goes through the session's volume worker, which applies them one at a
+// time in the order they came in, and skips straight to the latest when they come in faster than the audio backend
+// takes them.
+//
+// with volume_ramp set, the worker fades big jumps in over that long instead of making them at once. sliders only
+// jump when what they control wasn't where they are: the board connecting (deej setting everything to the sliders'
+// positions), a profile switch handing a slider to an app at some other volume, or a slider taking back an adopted
+// volume. ordinary slider moves come in small steps, and are set right away. volumes that come in during a fade
+// steer it rather than stepping past it, and something else changing the volume meanwhile cuts it short
+
+// volumeRequest is a volume for a session's worker to apply
+type volumeRequest struct {
+	volume float32
+	moved  bool
+
+	// called with the volume that was set, once it was. a request that a later one overtook before it was applied
+	// is never called back, the later one reports for both
+	applied func(volume float32, err error)
+}
+
+// volumeWorker applies one session's volumes. its goroutine only runs while there's something to apply
+type volumeWorker struct {
+	m       *sessionMap
+	session Session
+
+	pending *volumeRequest
+	running bool
+
+	// whether a fade is underway, and whether it was cut short
+	ramping   bool
+	cancelled bool
+
+	// the volumes fades set on their way and when, so their echoes aren't taken for external changes
+	steps []volumeRampStep
+
+	lock sync.Mutex
+}
+
+type volumeRampStep struct {
+	volume float32
+	set    time.Time
+}
+
+const (
+	// jumps smaller than this are set right away
+	volumeRampMinJump = 0.1
+
+	// how often a fade sets the volume on its way
+	volumeRampStepInterval = 20 * time.Millisecond
+
+	// how long after a fade's step its echo may still come back
+	volumeRampEchoGrace = 250 * time.Millisecond
+)
+
+// queueSessionVolume hands a volume to the session's worker, to be applied after whatever it's already doing.
+// applied may be nil
+func (m *sessionMap) queueSessionVolume(session Session, volume float32, moved bool, applied func(float32, error)) {
+	m.volumeLock.Lock()
+	worker, ok := m.volumeWorkers[session]
+	if !ok {
+		worker = &volumeWorker{m: m, session: session}
+		m.volumeWorkers[session] = worker
+	}
+	m.volumeLock.Unlock()
+
+	worker.queue(&volumeRequest{volume: volume, moved: moved, applied: applied})
+}
+
+// forgetVolumeWorker drops a session's worker once the session is gone. a request it's still applying finishes
+// (or fails) on its own
+func (m *sessionMap) forgetVolumeWorker(session Session) {
+	m.volumeLock.Lock()
+	defer m.volumeLock.Unlock()
+
+	delete(m.volumeWorkers, session)
+}
+
+func (m *sessionMap) forgetVolumeWorkers() {
+	m.volumeLock.Lock()
+	defer m.volumeLock.Unlock()
+
+	m.volumeWorkers = map[Session]*volumeWorker{}
+}
+
+// volumeWorkerFor returns the session's worker, or nil if nothing was queued for it
+func (m *sessionMap) volumeWorkerFor(session Session) *volumeWorker {
+	m.volumeLock.Lock()
+	defer m.volumeLock.Unlock()
+
+	return m.volumeWorkers[session]
+}
+
+// isVolumeRampEcho returns true if the session's volume is one a fade just set on its way, rather than an external
+// change
+func (m *sessionMap) isVolumeRampEcho(session Session, volume float32) bool {
+	worker := m.volumeWorkerFor(session)
+	if worker == nil {
+		return false
+	}
+
+	worker.lock.Lock()
+	defer worker.lock.Unlock()
+
+	for _, step := range worker.steps {
+		if time.Since(step.set) < volumeRampEchoGrace && math.Abs(float64(step.volume-volume)) < externalVolumeChangeTolerance {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cancelVolumeRamp cuts the session's fade short, if one is underway, leaving the volume where it is
+func (m *sessionMap) cancelVolumeRamp(session Session) {
+	worker := m.volumeWorkerFor(session)
+	if worker == nil {
+		return
+	}
+
+	worker.lock.Lock()
+	defer worker.lock.Unlock()
+
+	if worker.ramping {
+		worker.cancelled = true
+	}
+}
+
+func (w *volumeWorker) queue(request *volumeRequest) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.pending = request
+
+	if !w.running {
+		w.running = true
+		go w.run()
+	}
+}
+
+func (w *volumeWorker) run() {
+	for request := w.next(); request != nil; request = w.next() {
+		w.apply(request)
+	}
+}
+
+// next takes the latest request, or stops the worker if there's none
+func (w *volumeWorker) next() *volumeRequest {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	request := w.pending
+	w.pending = nil
+
+	if request == nil {
+		w.running = false
+	}
+
+	return request
+}
+
+func (w *volumeWorker) apply(request *volumeRequest) {
+	if rampTime := w.m.deej.config.VolumeRamp; rampTime > 0 {
+		from := w.session.GetVolume()
+
+		if math.Abs(float64(request.volume-from)) >= volumeRampMinJump {
+			if request = w.ramp(request, from, rampTime); request == nil {
+				return
+			}
+		}
+	}
+
+	err := w.m.setSessionVolume(w.session, request.volume, request.moved)
+
+	// every volume a slider stands for ends up here, whether it moved or deej put the session back where it is
+	if err == nil {
+		w.m.persistVolume(w.session, request.volume)
+	}
+
+	if request.applied != nil {
+		request.applied(request.volume, err)
+	}
+}
+
+// ramp fades the volume from where it was towards the request's, following whatever requests come in meanwhile.
+// it returns the request to finish on, or nil if the fade was cut short or failed (which its request hears about)
+func (w *volumeWorker) ramp(request *volumeRequest, from float32, rampTime time.Duration) *volumeRequest {
+	w.m.logger.Debugw("Ramping session volume", "session", w.session.Key(), "from", from, "to", request.volume)
+
+	w.lock.Lock()
+	w.ramping, w.cancelled = true, false
+	w.lock.Unlock()
+
+	defer func() {
+		w.lock.Lock()
+		w.ramping = false
+		w.lock.Unlock()
+	}()
+
+	started := time.Now()
+	step := from
+
+	ticker := time.NewTicker(volumeRampStepInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		w.lock.Lock()
+		steered := w.pending
+		w.pending = nil
+		cancelled := w.cancelled
+		w.lock.Unlock()
+
+		if cancelled {
+			w.m.logger.Debugw("Volume changed externally, cutting ramp short", "session", w.session.Key())
+			return nil
+		}
+
+		// a steered fade carries on from where it got to, and still ends on time
+		if steered != nil {
+			rampTime -= time.Since(started)
+			started = time.Now()
+			from, request = step, steered
+		}
+
+		progress := float32(time.Since(started)) / float32(rampTime)
+		if rampTime <= 0 || progress >= 1 {
+			return request
+		}
+
+		step = from + (request.volume-from)*progress
+		w.recordStep(step)
+
+		// the mute state only follows the slider once the volume gets there
+		if err := w.m.setSessionVolume(w.session, step, false); err != nil {
+			if request.applied != nil {
+				request.applied(step, err)
+			}
+
+			return nil
+		}
+	}
+
+	return request
+}
+
+func (w *volumeWorker) recordStep(volume float32) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	recent := w.steps[:0]
+	for _, step := range w.steps {
+		if time.Since(step.set) < volumeRampEchoGrace {
+			recent = append(recent, step)
+		}
+	}
+
+	w.steps = append(recent, volumeRampStep{volume: volume, set: time.Now()})
+}
@@ -0,0 +1,48 @@
+package deej
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVolumeRampFadesBigJumps(t *testing.T) {
+	td := newTestDeej(t, "slider_mapping:\n  0: spotify.exe\nvolume_ramp: 0.2\n", "spotify.exe")
+	session := td.sessions["spotify.exe"][0]
+
+	started := time.Now()
+	td.feed("1023")
+
+	time.Sleep(60 * time.Millisecond)
+
+	if volume := session.GetVolume(); volume <= fakeSessionInitialVolume || volume >= 1 {
+		t.Errorf("expected the volume to be on its way to 1.000, got %.3f", volume)
+	}
+
+	td.expectVolumes(t, map[string]float32{"spotify.exe": 1})
+
+	if elapsed := time.Since(started); elapsed < 200*time.Millisecond {
+		t.Errorf("expected the fade to take the ramp's time, took %v", elapsed)
+	}
+
+	// the slider now matches the volume, so small moves aren't slowed down
+	td.feed("972")
+	td.expectVolumes(t, map[string]float32{"spotify.exe": 0.95})
+}
+
+func TestVolumeRampFollowsLaterMoves(t *testing.T) {
+	td := newTestDeej(t, "slider_mapping:\n  0: spotify.exe\nvolume_ramp: 0.2\n", "spotify.exe")
+	session := td.sessions["spotify.exe"][0]
+
+	td.feed("1023")
+	time.Sleep(60 * time.Millisecond)
+
+	// moving the slider mid-fade points the fade somewhere else, rather than jumping there
+	td.feed("0")
+	time.Sleep(30 * time.Millisecond)
+
+	if volume := session.GetVolume(); volume <= 0 {
+		t.Errorf("expected the fade to still be on its way, got %.3f", volume)
+	}
+
+	td.expectVolumes(t, map[string]float32{"spotify.exe": 0})
+}
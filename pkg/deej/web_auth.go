@@ -0,0 +1,82 @@
+package deej
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// the web UI can change the config and every volume, so when it's served to the network (see web_host), other
+// devices have to present the web_token from the config. they can pass it once in the address they open
+// (http://<this machine>:<port>/remote?token=...), which leaves it in a cookie for the page's own requests, or send
+// it as a bearer token. this machine itself never needs it
+const (
+	webTokenParam  = "token"
+	webTokenCookie = "deej_token"
+)
+
+// requireWebToken wraps the web UI's handler, turning away requests from other devices without the web token
+func (wcs *WebConfigServer) requireWebToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isLoopbackRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := wcs.config.webToken()
+
+		// the config only lets the web UI out to the network with a token, but it may have been removed since
+		if token == "" || !webTokenMatches(requestWebToken(r), token) {
+			wcs.logger.Debugw("Turning away web request without the web token",
+				"remote", r.RemoteAddr, "path", r.URL.Path)
+			http.Error(w, "This needs the web_token from deej's config.yaml, open it with ?token=<web_token>",
+				http.StatusUnauthorized)
+			return
+		}
+
+		// so the page's own requests carry it along
+		if r.URL.Query().Get(webTokenParam) != "" {
+			http.SetCookie(w, &http.Cookie{
+				Name:     webTokenCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestWebToken returns the token a request came with, from wherever it put it
+func requestWebToken(r *http.Request) string {
+	if token := r.URL.Query().Get(webTokenParam); token != "" {
+		return token
+	}
+
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+
+	if cookie, err := r.Cookie(webTokenCookie); err == nil {
+		return cookie.Value
+	}
+
+	return ""
+}
+
+func webTokenMatches(given string, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+func isLoopbackRequest(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
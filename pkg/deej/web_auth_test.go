@@ -0,0 +1,53 @@
+package deej
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebTokenRequiredFromTheNetwork(t *testing.T) {
+	td := newTestDeej(t, `
+web_host: 0.0.0.0
+web_token: hunter2
+`, "master")
+
+	wcs := &WebConfigServer{logger: td.logger, deej: td.Deej, config: td.Deej.config}
+	handler := wcs.requireWebToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, tc := range []struct {
+		remote   string
+		target   string
+		cookie   string
+		expected int
+	}{
+		{"127.0.0.1:50000", "/api/config", "", http.StatusOK},
+		{"192.168.1.20:50000", "/api/config", "", http.StatusUnauthorized},
+		{"192.168.1.20:50000", "/remote?token=wrong", "", http.StatusUnauthorized},
+		{"192.168.1.20:50000", "/remote?token=hunter2", "", http.StatusOK},
+		{"192.168.1.20:50000", "/api/config", "hunter2", http.StatusOK},
+	} {
+		request := httptest.NewRequest("GET", tc.target, nil)
+		request.RemoteAddr = tc.remote
+		if tc.cookie != "" {
+			request.AddCookie(&http.Cookie{Name: webTokenCookie, Value: tc.cookie})
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		if recorder.Code != tc.expected {
+			t.Errorf("%s from %s: expected %d, got %d", tc.target, tc.remote, tc.expected, recorder.Code)
+		}
+	}
+}
+
+func TestWebHostStaysLocalWithoutToken(t *testing.T) {
+	td := newTestDeej(t, `
+web_host: 0.0.0.0
+`, "master")
+
+	if td.Deej.config.WebHost != defaultWebHost || td.Deej.webConfigExposed() {
+		t.Errorf("expected the web UI kept to this machine without a token, got host %q", td.Deej.config.WebHost)
+	}
+}
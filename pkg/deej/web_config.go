@@ -2,11 +2,17 @@ package deej
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 
 	"go.uber.org/zap"
+
+	"github.com/omriharel/deej/pkg/deej/util"
 )
 
 // WebConfigServer provides a web-based configuration interface
@@ -15,6 +21,15 @@ type WebConfigServer struct {
 	deej   *Deej
 	config *CanonicalConfig
 	server *http.Server
+
+	// set by listen. the advertiser stays nil unless the web UI is reachable from the network
+	listener   net.Listener
+	url        string
+	advertiser *mdnsAdvertiser
+
+	// closed on stop. closing the server doesn't touch websockets, since they're no longer its connections
+	stopped  chan struct{}
+	stopOnce sync.Once
 }
 
 // ConfigData represents the configuration data for the web interface
@@ -25,6 +40,20 @@ type ConfigData struct {
 	BaudRate       int               `json:"baudRate"`
 	NoiseReduction string            `json:"noiseReduction"`
 	NumSliders     int               `json:"numSliders"`
+
+	// false while the board isn't connected, NumSliders is then how many it had last time (or the default)
+	SlidersDetected bool `json:"slidersDetected"`
+
+	// how deej looks for new audio sessions, see CanonicalConfig.SessionRefresh
+	SessionRefresh   string  `json:"sessionRefresh"`
+	RefreshFrequency float64 `json:"refreshFrequency"`
+
+	// slider -> its targets that haven't matched any audio session
+	UnmatchedTargets map[string][]string `json:"unmatchedTargets"`
+
+	// slider -> its name in the config, and the one the board keeps for it. see slider_names.go
+	SliderNames      map[string]string `json:"sliderNames"`
+	BoardSliderNames map[string]string `json:"boardSliderNames"`
 }
 
 // NewWebConfigServer creates a new web configuration server
@@ -32,9 +61,10 @@ func NewWebConfigServer(deej *Deej, logger *zap.SugaredLogger) *WebConfigServer
 	logger = logger.Named("web_config")
 
 	wcs := &WebConfigServer{
-		logger: logger,
-		deej:   deej,
-		config: deej.config,
+		logger:  logger,
+		deej:    deej,
+		config:  deej.config,
+		stopped: make(chan struct{}),
 	}
 
 	// Set up HTTP server
@@ -43,27 +73,266 @@ func NewWebConfigServer(deej *Deej, logger *zap.SugaredLogger) *WebConfigServer
 	mux.HandleFunc("/api/config", wcs.handleGetConfig)
 	mux.HandleFunc("/api/save", wcs.handleSaveConfig)
 	mux.HandleFunc("/api/targets", wcs.handleGetTargets)
+	mux.HandleFunc("/api/status", wcs.handleGetStatus)
+	mux.HandleFunc("/api/version", wcs.handleGetVersion)
+	mux.HandleFunc("/api/permissions", wcs.handleGetPermissions)
+	mux.HandleFunc("/api/permissions/fix", wcs.handleFixPermissions)
+	mux.HandleFunc("/api/overrides", wcs.handleMappingOverrides)
+	mux.HandleFunc("/api/explain", wcs.handleExplain)
+	mux.HandleFunc("/api/testtone", wcs.handleTestTone)
+	mux.HandleFunc("/api/loudness", wcs.handleLoudness)
+	mux.HandleFunc("/api/timeline", wcs.handleGetTimeline)
+	mux.HandleFunc("/api/hardware", wcs.handleHardwareSettings)
+	mux.HandleFunc("/api/board", wcs.handleBoardSettings)
+	mux.HandleFunc("/api/autostart", wcs.handleAutostart)
+	mux.HandleFunc("/api/verbose", wcs.handleVerbose)
+	mux.HandleFunc("/api/update", wcs.handleUpdate)
+	mux.HandleFunc("/api/strings", wcs.handleGetStrings)
+	mux.HandleFunc("/api/theme", wcs.handleTheme)
+	mux.HandleFunc("/inspector", wcs.handleInspector)
+	mux.HandleFunc("/api/serial/traffic", wcs.handleGetSerialTraffic)
+	mux.HandleFunc(webConfigSetupPage, wcs.handleSetup)
+	mux.HandleFunc("/api/ports", wcs.handleGetPorts)
+	mux.HandleFunc("/api/setup", wcs.handleSaveSetup)
+	mux.HandleFunc("/remote", wcs.handleRemote)
+	mux.HandleFunc("/api/remote", wcs.handleRemoteSliders)
+	mux.HandleFunc("/status", wcs.handleStatusPage)
+	mux.HandleFunc("/api/status/sliders", wcs.handleGetSliderStatus)
+	mux.HandleFunc("/api/nowplaying", wcs.handleGetNowPlaying)
+	mux.HandleFunc("/api/nowplaying/ws", wcs.handleNowPlayingSocket)
+	mux.HandleFunc("/metrics", wcs.handleMetrics)
 
 	wcs.server = &http.Server{
-		Addr:    "localhost:8080",
-		Handler: mux,
+		Handler: wcs.requireWebToken(mux),
 	}
 
 	return wcs
 }
 
-// Start starts the web configuration server
+// listen binds the configured port, or any free one if it can't be had. that's usually because something else
+// already has it, but windows also reserves whole port ranges (e.g. for hyper-v), which fails differently
+func (wcs *WebConfigServer) listen() error {
+	host := wcs.config.WebHost
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(wcs.config.WebPort)))
+	if err != nil {
+		wcs.logger.Warnw("Can't use web port, letting the system pick another", "port", wcs.config.WebPort, "error", err)
+		listener, err = net.Listen("tcp", net.JoinHostPort(host, "0"))
+	}
+
+	if err != nil {
+		wcs.logger.Warnw("Failed to listen for web config connections", "error", err)
+		return fmt.Errorf("listen on web port: %w", err)
+	}
+
+	wcs.listener = listener
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	// the browser can't go to "every address", but this machine is one of them
+	browseHost := host
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		browseHost = defaultWebHost
+	}
+
+	wcs.url = "http://" + net.JoinHostPort(browseHost, strconv.Itoa(port))
+
+	// others on the network can reach it, so let them find it too
+	if wcs.deej.webConfigExposed() {
+		advertiser, err := newMDNSAdvertiser(wcs.logger, net.ParseIP(host), port)
+		if err != nil {
+			wcs.logger.Warnw("Failed to advertise web UI over mDNS", "error", err)
+		} else {
+			wcs.advertiser = advertiser
+		}
+	}
+
+	return nil
+}
+
+// URL returns where the web configuration server can be reached, once it's listening
+func (wcs *WebConfigServer) URL() string {
+	return wcs.url
+}
+
+// Start serves the web configuration server on the port it's listening on
 func (wcs *WebConfigServer) Start() error {
-	wcs.logger.Info("Starting web configuration server on http://localhost:8080")
-	return wcs.server.ListenAndServe()
+	wcs.logger.Infow("Starting web configuration server", "address", wcs.url)
+	return wcs.server.Serve(wcs.listener)
 }
 
 // Stop stops the web configuration server
 func (wcs *WebConfigServer) Stop() error {
 	wcs.logger.Info("Stopping web configuration server")
+
+	if wcs.advertiser != nil {
+		wcs.advertiser.close()
+	}
+
+	wcs.stopOnce.Do(func() { close(wcs.stopped) })
+
 	return wcs.server.Close()
 }
 
+const (
+	defaultWebHost = "localhost"
+	defaultWebPort = 8080
+
+	webConfigIndexPage = "/"
+	webConfigSetupPage = "/setup"
+)
+
+// how the web UI looks, see configKeyWebTheme
+const (
+	webThemeAuto  = "auto" // follow the system's light or dark preference
+	webThemeLight = "light"
+	webThemeDark  = "dark"
+)
+
+// webThemeCSS defines the colors every page styles itself with: light by default, dark when the system prefers it,
+// and whatever the page's data-theme attribute says over both
+const webThemeCSS = `
+        :root {
+            color-scheme: light;
+            --page-bg: #f5f5f5;
+            --surface: white;
+            --text: #333;
+            --heading: #555;
+            --muted: #666;
+            --border: #e0e0e0;
+            --input-border: #767676;
+            --subtle: #f8f9fa;
+            --subtle-hover: #e9ecef;
+            --link: #0060a8;
+            --focus: #005a9e;
+            --success-bg: #d4edda;
+            --success-text: #155724;
+            --error-bg: #f8d7da;
+            --error-text: #721c24;
+        }
+        @media (prefers-color-scheme: dark) {
+            :root:not([data-theme="light"]) {
+                color-scheme: dark;
+                --page-bg: #121212;
+                --surface: #1e1e1e;
+                --text: #e4e4e4;
+                --heading: #c8c8c8;
+                --muted: #a8a8a8;
+                --border: #3a3a3a;
+                --input-border: #8a8a8a;
+                --subtle: #2a2a2a;
+                --subtle-hover: #333;
+                --link: #6cb6ff;
+                --focus: #6cb6ff;
+                --success-bg: #1e3a26;
+                --success-text: #a3d9b1;
+                --error-bg: #4a1f24;
+                --error-text: #f5b7bd;
+            }
+        }
+        :root[data-theme="dark"] {
+            color-scheme: dark;
+            --page-bg: #121212;
+            --surface: #1e1e1e;
+            --text: #e4e4e4;
+            --heading: #c8c8c8;
+            --muted: #a8a8a8;
+            --border: #3a3a3a;
+            --input-border: #8a8a8a;
+            --subtle: #2a2a2a;
+            --subtle-hover: #333;
+            --link: #6cb6ff;
+            --focus: #6cb6ff;
+            --success-bg: #1e3a26;
+            --success-text: #a3d9b1;
+            --error-bg: #4a1f24;
+            --error-text: #f5b7bd;
+        }`
+
+// openWebConfig starts the web configuration server unless it's already running, and opens the given page in the browser
+func (d *Deej) openWebConfig(page string) {
+	logger := d.logger.Named("web_config")
+
+	url, err := d.startWebConfig()
+	if err != nil {
+		d.notifier.Notify(tr("notify.web_config_failed.title"), tr("notify.web_config_failed.message", err.Error()))
+		return
+	}
+
+	// Open the web browser
+	browserCmd := "xdg-open"
+	if !util.Linux() {
+		browserCmd = "start"
+	}
+	if err := util.OpenExternal(logger, browserCmd, url+page); err != nil {
+		logger.Warnw("Failed to open web browser", "error", err)
+	}
+}
+
+// startWebConfig starts the web configuration server unless it's already running, and returns its URL
+func (d *Deej) startWebConfig() (string, error) {
+	logger := d.logger.Named("web_config")
+
+	d.webConfigLock.Lock()
+	defer d.webConfigLock.Unlock()
+
+	if d.webConfig == nil {
+		webConfig := NewWebConfigServer(d, d.logger)
+
+		if err := webConfig.listen(); err != nil {
+			logger.Errorw("Failed to start web config server", "error", err)
+			return "", fmt.Errorf("start web config server: %w", err)
+		}
+
+		d.webConfig = webConfig
+
+		go func() {
+			if err := webConfig.Start(); err != nil && err != http.ErrServerClosed {
+				logger.Errorw("Web config server error", "error", err)
+
+				// stop advertising it, too
+				webConfig.Stop()
+			}
+
+			// let the next attempt start it again
+			d.webConfigLock.Lock()
+			d.webConfig = nil
+			d.webConfigLock.Unlock()
+		}()
+	}
+
+	return d.webConfig.URL(), nil
+}
+
+// webConfigExposed returns true if the web UI is served to the network, rather than to this machine alone
+func (d *Deej) webConfigExposed() bool {
+	ip := net.ParseIP(d.config.WebHost)
+	return ip != nil && !ip.IsLoopback()
+}
+
+// stopWebConfig stops the web configuration server, if it's running
+func (d *Deej) stopWebConfig() {
+	d.webConfigLock.Lock()
+	webConfig := d.webConfig
+	d.webConfigLock.Unlock()
+
+	if webConfig == nil {
+		return
+	}
+
+	if err := webConfig.Stop(); err != nil {
+		d.logger.Warnw("Failed to stop web config server", "error", err)
+	}
+}
+
+// openSetup points the user at the setup page, where they can pick their board's port from the ones deej finds.
+// it's the windows counterpart to the linux permissions helper, for when deej can't get to the board on its own
+func (d *Deej) openSetup(title string, message string) {
+	d.logger.Infow("Opening setup page", "reason", title)
+
+	d.notifier.Notify(title, message)
+	d.openWebConfig(webConfigSetupPage)
+}
+
 // handleIndex serves the main configuration page
 func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/" {
@@ -78,34 +347,39 @@ func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request)
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>deej Configuration</title>
+    <title data-i18n="web.title">deej Configuration</title>
     <style>
+` + webThemeCSS + `
         body {
             font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
             max-width: 800px;
             margin: 0 auto;
             padding: 20px;
-            background-color: #f5f5f5;
+            background-color: var(--page-bg);
+            color: var(--text);
+        }
+        a {
+            color: var(--link);
         }
         .container {
-            background: white;
+            background: var(--surface);
             padding: 30px;
             border-radius: 8px;
             box-shadow: 0 2px 10px rgba(0,0,0,0.1);
         }
         h1 {
-            color: #333;
+            color: var(--text);
             margin-bottom: 30px;
             text-align: center;
         }
         .section {
             margin-bottom: 30px;
             padding: 20px;
-            border: 1px solid #e0e0e0;
+            border: 1px solid var(--border);
             border-radius: 5px;
         }
         .section h2 {
-            color: #555;
+            color: var(--heading);
             margin-top: 0;
             border-bottom: 2px solid #007acc;
             padding-bottom: 10px;
@@ -117,18 +391,40 @@ func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request)
             display: block;
             margin-bottom: 5px;
             font-weight: 500;
-            color: #333;
+            color: var(--text);
         }
         input[type="text"], input[type="number"], select {
             width: 100%;
+            min-height: 44px;
             padding: 8px 12px;
-            border: 1px solid #ddd;
+            border: 1px solid var(--input-border);
             border-radius: 4px;
-            font-size: 14px;
+            background-color: var(--surface);
+            color: var(--text);
+            font-size: 16px;
             box-sizing: border-box;
         }
         input[type="checkbox"] {
+            width: 20px;
+            height: 20px;
             margin-right: 8px;
+            vertical-align: middle;
+        }
+        button, summary {
+            min-height: 44px;
+        }
+        :focus-visible {
+            outline: 3px solid var(--focus);
+            outline-offset: 2px;
+        }
+        /* read by screen readers, but not shown */
+        .sr-only {
+            position: absolute;
+            width: 1px;
+            height: 1px;
+            overflow: hidden;
+            clip: rect(0, 0, 0, 0);
+            white-space: nowrap;
         }
         .slider-row {
             display: flex;
@@ -142,6 +438,24 @@ func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request)
         }
         .slider-row input {
             flex: 1;
+            min-width: 0;
+        }
+        .slider-row input.slider-name {
+            flex: 0 0 140px;
+            margin-right: 10px;
+        }
+        .target-warning {
+            color: var(--error-text);
+            font-size: 13px;
+            margin: -5px 0 10px 90px;
+        }
+        .now-playing {
+            color: var(--muted);
+            font-size: 13px;
+            margin: -5px 0 10px 90px;
+        }
+        .now-playing:empty {
+            display: none;
         }
         .special-btn {
             background: #007acc;
@@ -183,10 +497,16 @@ func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request)
             background: #545b62;
         }
         .help-text {
-            color: #666;
+            color: var(--muted);
             font-size: 14px;
             margin-bottom: 15px;
         }
+        .footer {
+            color: var(--muted);
+            font-size: 13px;
+            text-align: center;
+            margin-top: 20px;
+        }
         .modal {
             display: none;
             position: fixed;
@@ -198,7 +518,7 @@ func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request)
             background-color: rgba(0,0,0,0.5);
         }
         .modal-content {
-            background-color: white;
+            background-color: var(--surface);
             margin: 5% auto;
             padding: 20px;
             border-radius: 8px;
@@ -208,22 +528,27 @@ func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request)
             overflow-y: auto;
         }
         .accordion {
-            border: 1px solid #ddd;
+            border: 1px solid var(--border);
             border-radius: 4px;
             margin-bottom: 10px;
         }
         .accordion-header {
-            background-color: #f8f9fa;
+            width: 100%;
+            background-color: var(--subtle);
+            color: inherit;
+            font: inherit;
+            text-align: left;
             padding: 10px 15px;
             cursor: pointer;
-            border-bottom: 1px solid #ddd;
+            border: none;
+            border-bottom: 1px solid var(--border);
             font-weight: 500;
             display: flex;
             justify-content: space-between;
             align-items: center;
         }
         .accordion-header:hover {
-            background-color: #e9ecef;
+            background-color: var(--subtle-hover);
         }
         .accordion-header:last-child {
             border-bottom: none;
@@ -231,7 +556,7 @@ func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request)
         .accordion-content {
             display: none;
             padding: 15px;
-            background-color: white;
+            background-color: var(--surface);
         }
         .accordion-content.expanded {
             display: block;
@@ -254,92 +579,293 @@ func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request)
             cursor: pointer;
         }
         .success-message {
-            background: #d4edda;
-            color: #155724;
+            background: var(--success-bg);
+            color: var(--success-text);
             padding: 10px;
             border-radius: 4px;
             margin-bottom: 20px;
             display: none;
         }
         .error-message {
-            background: #f8d7da;
-            color: #721c24;
+            background: var(--error-bg);
+            color: var(--error-text);
             padding: 10px;
             border-radius: 4px;
             margin-bottom: 20px;
             display: none;
         }
+        @media (prefers-reduced-motion: reduce) {
+            .accordion-icon {
+                transition: none;
+            }
+        }
+        /* phones and narrow windows: stack everything, and give fingers room */
+        @media (max-width: 600px) {
+            body {
+                padding: 0;
+            }
+            .container {
+                padding: 15px;
+                border-radius: 0;
+                box-shadow: none;
+            }
+            .section {
+                padding: 12px;
+            }
+            .slider-row {
+                flex-wrap: wrap;
+            }
+            .slider-row label {
+                width: 100%;
+                margin-bottom: 5px;
+            }
+            .special-btn {
+                padding: 10px 14px;
+                font-size: 14px;
+            }
+            .buttons {
+                display: flex;
+                flex-direction: column-reverse;
+            }
+            .btn {
+                margin: 5px 0;
+            }
+            .modal-content {
+                margin: 0;
+                width: 100%;
+                height: 100%;
+                max-height: none;
+                border-radius: 0;
+                box-sizing: border-box;
+            }
+        }
     </style>
 </head>
 <body>
-    <div class="container">
-        <h1>deej Configuration</h1>
+    <main class="container">
+        <h1 data-i18n="web.title">deej Configuration</h1>
+        
+        <div id="detectionBanner" class="help-text" role="status" aria-live="polite" hidden>
+            <span id="detectionText"></span>
+            <ul id="detectionResults"></ul>
+        </div>
         
-        <div id="successMessage" class="success-message"></div>
-        <div id="errorMessage" class="error-message"></div>
+        <div id="successMessage" class="success-message" role="status" aria-live="polite"></div>
+        <div id="errorMessage" class="error-message" role="alert"></div>
         
         <form id="configForm">
-            <div class="section">
-                <h2>Slider Mappings</h2>
+            <section class="section" aria-labelledby="sliderMappingsTitle">
+                <h2 id="sliderMappingsTitle" data-i18n="web.slider_mappings">Slider Mappings</h2>
                 <div style="text-align: right; margin-bottom: 10px;">
-                    <button type="button" class="btn btn-secondary" onclick="refreshSliderCount()" style="padding: 6px 12px; font-size: 12px;">Refresh Slider Count</button>
+                    <button type="button" class="btn btn-secondary" onclick="refreshSliderCount()" style="padding: 6px 12px; font-size: 12px;" data-i18n="web.refresh_slider_count">Refresh Slider Count</button>
                 </div>
                 <div id="sliderMappings">
                     <!-- Slider mappings will be populated by JavaScript -->
                 </div>
-            </div>
+            </section>
             
             <details style="margin-bottom: 30px;">
-                <summary style="font-size: 1.1em; font-weight: bold;">Advanced</summary>
+                <summary style="font-size: 1.1em; font-weight: bold;" data-i18n="web.advanced">Advanced</summary>
                 <div class="section" style="margin-top: 15px;">
-                    <h2>Connection Settings</h2>
+                    <h2 data-i18n="web.connection_settings">Connection Settings</h2>
+                    <div id="permissionsNotice" class="error-message" role="alert">
+                        <span id="permissionsText"></span>
+                        <button type="button" class="special-btn" onclick="fixPermissions()" data-i18n="web.fix_permissions">Fix permissions</button>
+                    </div>
                     <div class="form-group">
-                        <label for="comPort">COM Port:</label>
-                        <input type="text" id="comPort" name="comPort" placeholder="e.g., COM4 or auto">
+                        <label for="comPort" data-i18n="web.com_port">COM Port:</label>
+                        <input type="text" id="comPort" name="comPort" placeholder="e.g., COM4 or auto" data-i18n-placeholder="web.com_port.placeholder">
                     </div>
                     <div class="form-group">
-                        <label for="baudRate">Baud Rate:</label>
+                        <label for="baudRate" data-i18n="web.baud_rate">Baud Rate:</label>
                         <input type="number" id="baudRate" name="baudRate" value="9600">
                     </div>
+                    <div class="help-text" data-i18n-html="web.inspector_hint">
+                        Having trouble with your board? The <a href="/inspector" target="_blank">serial traffic inspector</a> shows everything it sends, live.
+                    </div>
+                    <button type="button" class="special-btn" style="margin-left: 0;" onclick="requestVersion(this)" data-i18n="web.request_version">Ask the board for its firmware version</button>
+                </div>
+            </details>
+            
+            <details style="margin-bottom: 30px;">
+                <summary style="font-size: 1.1em; font-weight: bold;" data-i18n="web.hardware">Hardware</summary>
+                <div class="section" style="margin-top: 15px;">
+                    <h2 data-i18n="web.board_settings">Board Settings</h2>
+                    <div id="hardwareNotice" class="help-text" data-i18n="web.hardware_notice">
+                        These settings are sent to the board whenever it connects. Leave a field empty to keep the firmware's default.
+                    </div>
+                    <div class="form-group">
+                        <label for="reportRate" data-i18n="web.report_rate">Report rate (times per second):</label>
+                        <input type="number" id="reportRate" min="1" max="1000">
+                    </div>
+                    <div class="form-group">
+                        <label for="ledBrightness" data-i18n="web.led_brightness">LED brightness (0-255):</label>
+                        <input type="number" id="ledBrightness" min="0" max="255">
+                    </div>
+                    <div class="form-group">
+                        <label for="dimAfter" data-i18n="web.dim_after">Dim the display and LEDs after (seconds untouched):</label>
+                        <input type="number" id="dimAfter" min="0">
+                    </div>
+                    <div class="form-group">
+                        <label for="offAfter" data-i18n="web.off_after">Turn them off after (seconds untouched):</label>
+                        <input type="number" id="offAfter" min="0">
+                        <div class="help-text" data-i18n="web.idle_hint">Leave empty to keep them on. Moving a slider or pressing a button wakes them up.</div>
+                    </div>
+                    <div id="sliderSmoothing">
+                        <!-- per-slider smoothing will be populated by JavaScript -->
+                    </div>
+                    <div class="help-text" data-i18n="web.led_colors_hint">
+                        For boards with RGB LEDs: give each slider a color, so it matches its name on screen.
+                    </div>
+                    <div id="sliderColors">
+                        <!-- per-slider colors will be populated by JavaScript -->
+                    </div>
+                    <div style="text-align: right;">
+                        <button type="button" class="special-btn" onclick="saveHardware()" data-i18n="web.save_hardware">Save and Send to Board</button>
+                    </div>
+                </div>
+                <div class="section">
+                    <h2 data-i18n="web.board_storage">Stored on the Board</h2>
+                    <div id="boardStorageNotice" class="help-text" data-i18n="web.board_storage_hint">
+                        Boards that keep settings in their EEPROM take them along to other computers.
+                    </div>
+                    <div class="form-group">
+                        <label for="boardName" data-i18n="web.board_name">Board name:</label>
+                        <input type="text" id="boardName" maxlength="32">
+                    </div>
+                    <div class="form-group">
+                        <label for="boardSliders" data-i18n="web.board_sliders">Number of sliders:</label>
+                        <input type="number" id="boardSliders" min="1" max="64">
+                    </div>
+                    <div class="form-group">
+                        <label for="boardLEDBrightness" data-i18n="web.led_brightness">LED brightness (0-255):</label>
+                        <input type="number" id="boardLEDBrightness" min="0" max="255">
+                    </div>
+                    <div id="boardSliderNames">
+                        <!-- slider names will be populated by JavaScript -->
+                    </div>
+                    <div style="text-align: right;">
+                        <button type="button" class="special-btn" onclick="readBoardSettings(this)" data-i18n="web.board_read">Read from Board</button>
+                        <button type="button" class="special-btn" onclick="writeBoardSettings(this)" data-i18n="web.board_write">Write to Board</button>
+                    </div>
+                </div>
+            </details>
+            
+            <details style="margin-bottom: 30px;">
+                <summary style="font-size: 1.1em; font-weight: bold;" data-i18n="web.explain">Explain a slider</summary>
+                <div class="section" style="margin-top: 15px;">
+                    <div class="help-text" data-i18n="web.explain_hint">
+                        See what a slider controls right now: its targets, the audio sessions they matched and their volumes.
+                    </div>
+                    <div class="form-group">
+                        <label for="explainSlider" data-i18n="web.explain_slider">Slider:</label>
+                        <input type="number" id="explainSlider" min="1" value="1">
+                    </div>
+                    <button type="button" class="special-btn" style="margin-left: 0;" onclick="explainSlider(false)" data-i18n="web.explain_button">Explain</button>
+                    <button type="button" class="special-btn" onclick="explainSlider(true)" data-i18n="web.explain_preview_button">Preview unsaved mapping</button>
+                    <button type="button" class="special-btn" onclick="playTestTone(this)" data-i18n="web.test_tone">Play a test tone</button>
+                    <div id="explanation" aria-live="polite"></div>
+                </div>
+            </details>
+            
+            <details style="margin-bottom: 30px;" ontoggle="if (this.open) loadLoudness()">
+                <summary style="font-size: 1.1em; font-weight: bold;" data-i18n="web.loudness">Even out loudness</summary>
+                <div class="section" style="margin-top: 15px;">
+                    <div class="help-text" data-i18n="web.loudness_hint">
+                        Some apps play much louder than others. Play something typical in each app you want to even out (or pink noise, if it can play it), then calibrate: deej listens to each app at full volume for a few seconds and turns the louder ones down, so the same slider position sounds about as loud everywhere.
+                    </div>
+                    <button type="button" class="special-btn" style="margin-left: 0;" onclick="calibrateLoudness(this)" data-i18n="web.loudness_calibrate">Calibrate</button>
+                    <button type="button" class="special-btn" onclick="clearLoudness()" data-i18n="web.loudness_clear">Clear offsets</button>
+                    <ul id="loudnessOffsets" aria-live="polite"></ul>
+                </div>
+            </details>
+            
+            <details style="margin-bottom: 30px;" ontoggle="if (this.open) loadTimeline()">
+                <summary style="font-size: 1.1em; font-weight: bold;" data-i18n="web.timeline">Event timeline</summary>
+                <div class="section" style="margin-top: 15px;">
+                    <div class="help-text" data-i18n="web.timeline_hint">
+                        What happened to your audio sessions, their volumes and the board's connection since deej started, newest first.
+                    </div>
+                    <button type="button" class="special-btn" style="margin-left: 0;" onclick="loadTimeline()" data-i18n="web.timeline_refresh">Refresh</button>
+                    <ul id="timeline"></ul>
                 </div>
             </details>
             
-            <div class="section">
-                <h2>Other Settings</h2>
+            <section class="section" aria-labelledby="otherSettingsTitle">
+                <h2 id="otherSettingsTitle" data-i18n="web.other_settings">Other Settings</h2>
+                <div class="help-text" data-i18n-html="web.remote_hint">
+                    The <a href="/remote" target="_blank">remote</a> has on-screen sliders that work just like your board's, even when it isn't plugged in. To use it from your phone, set web_host and web_token in config.yaml.
+                </div>
+                <div class="form-group">
+                    <label>
+                        <input type="checkbox" id="autostart" onchange="saveAutostart(this.checked)">
+                        <span data-i18n="web.autostart">Start deej at login</span>
+                    </label>
+                </div>
+                <div class="form-group">
+                    <label>
+                        <input type="checkbox" id="verbose" onchange="saveVerbose(this.checked)">
+                        <span data-i18n="web.verbose">Verbose logging (to capture a problem in deej's logs)</span>
+                    </label>
+                </div>
                 <div class="form-group">
                     <label>
                         <input type="checkbox" id="invertSliders" name="invertSliders">
-                        Invert sliders
+                        <span data-i18n="web.invert_sliders">Invert sliders</span>
                     </label>
                 </div>
                 <div class="form-group">
-                    <label for="noiseReduction">Noise Reduction:</label>
+                    <label for="theme" data-i18n="web.theme">Theme:</label>
+                    <select id="theme" onchange="saveTheme(this.value)">
+                        <option value="auto" data-i18n="web.theme_auto">Match system</option>
+                        <option value="light" data-i18n="web.theme_light">Light</option>
+                        <option value="dark" data-i18n="web.theme_dark">Dark</option>
+                    </select>
+                </div>
+                <div class="form-group">
+                    <label for="noiseReduction" data-i18n="web.noise_reduction">Noise Reduction:</label>
                     <select id="noiseReduction" name="noiseReduction">
-                        <option value="low">Low (excellent hardware)</option>
-                        <option value="default" selected>Default (regular hardware)</option>
-                        <option value="high">High (bad, noisy hardware)</option>
+                        <option value="low" data-i18n="web.noise_low">Low (excellent hardware)</option>
+                        <option value="default" selected data-i18n="web.noise_default">Default (regular hardware)</option>
+                        <option value="high" data-i18n="web.noise_high">High (bad, noisy hardware)</option>
                     </select>
                 </div>
-            </div>
+                <div class="form-group">
+                    <label for="sessionRefresh" data-i18n="web.session_refresh">Finding new apps:</label>
+                    <select id="sessionRefresh" name="sessionRefresh" onchange="updateRefreshFrequency()">
+                        <option value="auto" selected data-i18n="web.session_refresh_auto">Automatically</option>
+                        <option value="manual" data-i18n="web.session_refresh_manual">Only when asked to</option>
+                    </select>
+                </div>
+                <div class="form-group" id="refreshFrequencyGroup">
+                    <label for="refreshFrequency" data-i18n="web.refresh_frequency">At most every (seconds):</label>
+                    <input type="number" id="refreshFrequency" name="refreshFrequency" min="0" step="1" value="5">
+                </div>
+                <div class="help-text" data-i18n="web.session_refresh_hint">
+                    Scanning for apps takes a moment, which slow machines can notice. Set this to only when asked to, and deej finds apps started after it only when you choose "Re-scan audio sessions" from the tray or rescan running applications here.
+                </div>
+            </section>
             
             <div class="buttons">
-                <button type="button" class="btn btn-secondary" onclick="window.close()">Cancel</button>
-                <button type="submit" class="btn btn-primary">Save Configuration</button>
+                <button type="button" class="btn btn-secondary" onclick="window.close()" data-i18n="web.cancel">Cancel</button>
+                <button type="submit" class="btn btn-primary" data-i18n="web.save">Save Configuration</button>
             </div>
         </form>
-    </div>
+        
+        <footer class="footer" id="updateFooter" aria-live="polite"></footer>
+    </main>
     
     <!-- Audio targets modal -->
     <div id="specialModal" class="modal">
-        <div class="modal-content">
-            <h3>Select Audio Target</h3>
+        <div class="modal-content" role="dialog" aria-modal="true" aria-labelledby="specialModalTitle">
+            <h3 id="specialModalTitle" data-i18n="web.select_target">Select Audio Target</h3>
             <div id="specialTargetsSearchContainer"></div>
             <div style="text-align:right; margin-bottom:8px;">
-                <button id="rescanRunningBtn" class="btn btn-secondary" style="padding:6px 12px; font-size:12px;">Rescan Running Applications</button>
+                <button type="button" id="rescanRunningBtn" class="btn btn-secondary" style="padding:6px 12px; font-size:12px;" data-i18n="web.rescan_running">Rescan Running Applications</button>
             </div>
-            <div id="specialTargetsList"></div>
+            <div id="specialTargetsList" aria-live="polite"></div>
             <div class="modal-buttons">
-                <button class="modal-btn btn-secondary" onclick="closeSpecialModal()">Cancel</button>
+                <button type="button" class="modal-btn btn-secondary" onclick="closeSpecialModal()" data-i18n="web.cancel">Cancel</button>
             </div>
         </div>
     </div>
@@ -347,254 +873,1063 @@ func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request)
     <script>
         let currentSliderIndex = 0;
         
-        // Load configuration on page load
+        // strings in deej's language, keyed like its catalogs. the page's own english text is the fallback
+        let strings = {};
+        
+        // Load configuration on page load, once we know what to call things
         window.onload = function() {
-            loadConfig();
+            loadTheme();
+            loadStrings().finally(() => {
+                loadConfig();
+                loadPermissions();
+                loadHardware();
+                loadAutostart();
+                loadVerbose();
+                loadUpdate();
+                watchNowPlaying();
+                watchDetection();
+            });
         };
         
-        function loadConfig() {
-            fetch('/api/config')
+        function loadStrings() {
+            return fetch('/api/strings')
                 .then(response => response.json())
                 .then(data => {
-                    populateSliderMappings(data.sliderMappings, data.numSliders);
-                    document.getElementById('comPort').value = data.comPort;
-                    document.getElementById('baudRate').value = data.baudRate;
-                    document.getElementById('invertSliders').checked = data.invertSliders;
-                    document.getElementById('noiseReduction').value = data.noiseReduction;
+                    strings = data.strings;
+                    document.documentElement.lang = data.locale;
+                    document.querySelectorAll('[data-i18n]').forEach(el => {
+                        el.textContent = t(el.dataset.i18n);
+                    });
+                    document.querySelectorAll('[data-i18n-html]').forEach(el => {
+                        el.innerHTML = t(el.dataset.i18nHtml);
+                    });
+                    document.querySelectorAll('[data-i18n-placeholder]').forEach(el => {
+                        el.placeholder = t(el.dataset.i18nPlaceholder);
+                    });
                 })
                 .catch(error => {
-                    showError('Failed to load configuration: ' + error.message);
+                    console.warn('Failed to load strings, staying in english', error);
                 });
         }
         
-        function refreshSliderCount() {
-            fetch('/api/config')
+        // t looks up a string and fills its %s placeholders in order
+        function t(key, ...args) {
+            let text = strings[key];
+            if (text === undefined) {
+                return key;
+            }
+            args.forEach(arg => {
+                text = text.replace('%s', arg);
+            });
+            return text;
+        }
+        
+        function loadUpdate() {
+            fetch('/api/update')
+                .then(response => response.json())
+                .then(data => {
+                    const footer = document.getElementById('updateFooter');
+                    footer.textContent = data.current ? 'deej ' + data.current : '';
+                    if (!data.available) {
+                        return;
+                    }
+                    
+                    const notice = document.createElement('span');
+                    notice.textContent = (data.current ? ' · ' : '') + t('web.update_available', data.available.tag) + ' ';
+                    footer.appendChild(notice);
+                    
+                    const notes = document.createElement('a');
+                    notes.href = data.available.url;
+                    notes.target = '_blank';
+                    notes.textContent = t('web.whats_new');
+                    footer.appendChild(notes);
+                    
+                    if (data.canInstall) {
+                        const install = document.createElement('button');
+                        install.type = 'button';
+                        install.className = 'special-btn';
+                        install.style.marginLeft = '10px';
+                        install.textContent = t('web.update');
+                        install.onclick = () => installUpdate(install);
+                        footer.appendChild(install);
+                    }
+                });
+        }
+        
+        function installUpdate(button) {
+            button.disabled = true;
+            button.textContent = t('web.updating');
+            
+            fetch('/api/update', { method: 'POST' })
                 .then(response => response.json())
                 .then(data => {
-                    populateSliderMappings(data.sliderMappings, data.numSliders);
-                    showSuccess('Slider count refreshed: ' + data.numSliders + ' slider(s) detected');
+                    if (data.success) {
+                        button.textContent = t('web.restart_to_finish');
+                        showSuccess(t('web.updated'));
+                    } else {
+                        button.disabled = false;
+                        button.textContent = t('web.update');
+                        showError(t('web.update_failed', data.error));
+                    }
                 })
                 .catch(error => {
-                    showError('Failed to refresh slider count: ' + error.message);
+                    button.disabled = false;
+                    button.textContent = t('web.update');
+                    showError(t('web.update_failed', error.message));
                 });
         }
         
-        function populateSliderMappings(mappings, numSliders) {
-            const container = document.getElementById('sliderMappings');
-            container.innerHTML = '';
-            
-            // Add slider count info
-            const infoDiv = document.createElement('div');
-            infoDiv.className = 'help-text';
-            if (numSliders > 0) {
-                infoDiv.innerHTML = '<strong>Detected ' + numSliders + ' slider(s) from Arduino</strong><br>Enter process names (e.g., chrome.exe) or special targets (master, mic, deej.unmapped, etc.)<br>Multiple targets can be separated by commas';
+        // the theme takes effect right away, and is saved on its own rather than with the rest of the form
+        function applyTheme(theme) {
+            if (theme === 'light' || theme === 'dark') {
+                document.documentElement.dataset.theme = theme;
             } else {
-                infoDiv.innerHTML = '<strong style="color: #dc3545;">Arduino not connected - using default 5 sliders</strong><br>Connect your Arduino and click "Refresh Slider Count" to detect the actual number of sliders<br>Enter process names (e.g., chrome.exe) or special targets (master, mic, deej.unmapped, etc.)<br>Multiple targets can be separated by commas';
+                delete document.documentElement.dataset.theme;
             }
-            container.appendChild(infoDiv);
+            document.getElementById('theme').value = theme;
+        }
+        
+        function loadTheme() {
+            fetch('/api/theme')
+                .then(response => response.json())
+                .then(data => applyTheme(data.theme));
+        }
+        
+        function saveTheme(theme) {
+            const previous = document.documentElement.dataset.theme || 'auto';
+            applyTheme(theme);
             
-            for (let i = 0; i < numSliders; i++) {
-                const sliderDiv = document.createElement('div');
-                sliderDiv.className = 'slider-row';
-                
-                const label = document.createElement('label');
-                label.textContent = 'Slider ' + (i + 1) + ':';
-                
-                const input = document.createElement('input');
-                input.type = 'text';
-                input.name = 'slider' + i;
-                input.placeholder = 'e.g., chrome.exe, firefox.exe';
-                input.value = mappings[i] || '';
-                
-                const specialBtn = document.createElement('button');
-                specialBtn.type = 'button';
-                specialBtn.className = 'special-btn';
-                specialBtn.textContent = 'Pick Target';
-                specialBtn.onclick = function() { showSpecialModal(i); };
-                
-                sliderDiv.appendChild(label);
-                sliderDiv.appendChild(input);
-                sliderDiv.appendChild(specialBtn);
-                container.appendChild(sliderDiv);
-            }
+            fetch('/api/theme', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ theme: theme })
+            })
+            .then(response => {
+                if (!response.ok) {
+                    return response.text().then(text => { throw new Error(text); });
+                }
+            })
+            .catch(error => {
+                applyTheme(previous);
+                showError(t('web.theme_failed', error.message));
+            });
         }
         
-        function showSpecialModal(sliderIndex) {
-            currentSliderIndex = sliderIndex;
-            const modal = document.getElementById('specialModal');
-            const list = document.getElementById('specialTargetsList');
-            const searchContainer = document.getElementById('specialTargetsSearchContainer');
-            list.innerHTML = '<div style="text-align: center; margin-bottom: 10px;"><strong>Loading available targets...</strong></div>';
-            searchContainer.innerHTML = '<input type="text" id="specialTargetsSearch" placeholder="Search installed applications..." style="width: 100%; padding: 8px; margin-bottom: 10px; border-radius: 4px; border: 1px solid #ccc; font-size: 14px; display: block;">';
-            modal.style.display = 'block';
-            // Fetch available targets from the server
-            fetch('/api/targets')
+        // autostart takes effect right away, it isn't part of the config file
+        function loadAutostart() {
+            fetch('/api/autostart')
                 .then(response => response.json())
-                .then(targets => {
-                    window._allAudioTargets = targets;
-                    renderSpecialTargets(targets, '');
-                    document.getElementById('specialTargetsSearch').oninput = function(e) {
-                        renderSpecialTargets(window._allAudioTargets, e.target.value);
-                    };
-                    // Add rescan button handler
-                    document.getElementById('rescanRunningBtn').onclick = function() {
-                        list.innerHTML = '<div style="text-align: center; margin-bottom: 10px;"><strong>Rescanning running applications...</strong></div>';
-                        fetch('/api/targets?refresh=1')
-                            .then(response => response.json())
-                            .then(targets => {
-                                window._allAudioTargets = targets;
-                                renderSpecialTargets(targets, document.getElementById('specialTargetsSearch').value);
-                            });
-                    };
+                .then(data => {
+                    document.getElementById('autostart').checked = data.enabled;
+                });
+        }
+        
+        function saveAutostart(enabled) {
+            fetch('/api/autostart', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ enabled: enabled })
+            })
+            .then(response => {
+                if (!response.ok) {
+                    return response.text().then(text => { throw new Error(text); });
+                }
+                return response.json();
+            })
+            .then(data => {
+                document.getElementById('autostart').checked = data.enabled;
+                showSuccess(data.enabled ? t('web.autostart_on') : t('web.autostart_off'));
+            })
+            .catch(error => {
+                document.getElementById('autostart').checked = !enabled;
+                showError(t('web.autostart_failed', error.message));
+            });
+        }
+        
+        // verbose mode lasts until deej exits, it isn't part of the config file either
+        function loadVerbose() {
+            fetch('/api/verbose')
+                .then(response => response.json())
+                .then(data => {
+                    document.getElementById('verbose').checked = data.enabled;
+                });
+        }
+        
+        function saveVerbose(enabled) {
+            fetch('/api/verbose', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ enabled: enabled })
+            })
+            .then(response => {
+                if (!response.ok) {
+                    return response.text().then(text => { throw new Error(text); });
+                }
+                return response.json();
+            })
+            .then(data => {
+                document.getElementById('verbose').checked = data.enabled;
+                showSuccess(data.enabled ? t('web.verbose_on') : t('web.verbose_off'));
+            })
+            .catch(error => {
+                document.getElementById('verbose').checked = !enabled;
+                showError(t('web.verbose_failed', error.message));
+            });
+        }
+        
+        function loadHardware() {
+            fetch('/api/hardware')
+                .then(response => response.json())
+                .then(data => {
+                    const settings = data.settings;
+                    document.getElementById('reportRate').value = settings.reportRate >= 0 ? settings.reportRate : '';
+                    document.getElementById('ledBrightness').value = settings.ledBrightness >= 0 ? settings.ledBrightness : '';
+                    document.getElementById('dimAfter').value = data.dimAfter > 0 ? data.dimAfter : '';
+                    document.getElementById('offAfter').value = data.offAfter > 0 ? data.offAfter : '';
+                    
+                    const container = document.getElementById('sliderSmoothing');
+                    container.innerHTML = '';
+                    for (let i = 0; i < data.numSliders; i++) {
+                        const row = document.createElement('div');
+                        row.className = 'slider-row';
+                        const label = document.createElement('label');
+                        label.textContent = t('web.smoothing', i + 1);
+                        label.htmlFor = 'smoothing' + i;
+                        const input = document.createElement('input');
+                        input.id = 'smoothing' + i;
+                        input.type = 'number';
+                        input.min = 0;
+                        input.max = 32;
+                        input.name = 'smoothing' + i;
+                        input.placeholder = t('web.smoothing.placeholder');
+                        input.value = settings.smoothing && settings.smoothing[i] !== undefined ? settings.smoothing[i] : '';
+                        row.appendChild(label);
+                        row.appendChild(input);
+                        container.appendChild(row);
+                    }
+                    
+                    // a color picker always has a color, so sliders without one are marked unset until picked
+                    const colors = document.getElementById('sliderColors');
+                    colors.innerHTML = '';
+                    for (let i = 0; i < data.numSliders; i++) {
+                        const name = (data.names || {})[i] || String(i + 1);
+                        const row = document.createElement('div');
+                        row.className = 'slider-row';
+                        const label = document.createElement('label');
+                        label.textContent = t('web.led_color', name);
+                        label.htmlFor = 'color' + i;
+                        const input = document.createElement('input');
+                        input.id = 'color' + i;
+                        input.type = 'color';
+                        input.name = 'color' + i;
+                        const color = settings.colors && settings.colors[i];
+                        input.value = color || '#000000';
+                        input.dataset.set = color ? '1' : '';
+                        input.style.opacity = color ? 1 : 0.4;
+                        input.oninput = () => { input.dataset.set = '1'; input.style.opacity = 1; };
+                        const clear = document.createElement('button');
+                        clear.type = 'button';
+                        clear.className = 'special-btn';
+                        clear.textContent = t('web.led_color_clear');
+                        clear.setAttribute('aria-label', t('web.led_color_clear_for', name));
+                        clear.onclick = () => { input.dataset.set = ''; input.style.opacity = 0.4; };
+                        row.appendChild(label);
+                        row.appendChild(input);
+                        row.appendChild(clear);
+                        colors.appendChild(row);
+                    }
+                    
+                    if (!data.supported) {
+                        document.getElementById('hardwareNotice').textContent = t('web.hardware_unsupported');
+                    }
+                });
+        }
+        
+        function saveHardware() {
+            const numberOrUnset = value => value.trim() === '' ? -1 : parseInt(value);
+            const settings = {
+                reportRate: numberOrUnset(document.getElementById('reportRate').value),
+                ledBrightness: numberOrUnset(document.getElementById('ledBrightness').value),
+                dimAfter: Math.max(0, parseFloat(document.getElementById('dimAfter').value) || 0),
+                offAfter: Math.max(0, parseFloat(document.getElementById('offAfter').value) || 0),
+                smoothing: {},
+                colors: {}
+            };
+            
+            document.querySelectorAll('#sliderSmoothing input').forEach(input => {
+                if (input.value.trim() !== '') {
+                    settings.smoothing[input.name.replace('smoothing', '')] = parseInt(input.value);
+                }
+            });
+            
+            document.querySelectorAll('#sliderColors input').forEach(input => {
+                if (input.dataset.set) {
+                    settings.colors[input.name.replace('color', '')] = input.value;
+                }
+            });
+            
+            fetch('/api/hardware', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify(settings)
+            })
+            .then(response => response.json())
+            .then(data => {
+                if (data.success) {
+                    showSuccess(t('web.hardware_saved'));
+                } else {
+                    showError(t('web.hardware_failed', data.error));
+                }
+            })
+            .catch(error => {
+                showError(t('web.hardware_failed', error.message));
+            });
+        }
+        
+        function readBoardSettings(button) {
+            button.disabled = true;
+            fetch('/api/board')
+                .then(response => {
+                    if (!response.ok) {
+                        return response.text().then(text => { throw new Error(text); });
+                    }
+                    return response.json();
+                })
+                .then(settings => {
+                    document.getElementById('boardName').value = settings.name;
+                    document.getElementById('boardSliders').value = settings.sliders > 0 ? settings.sliders : '';
+                    document.getElementById('boardLEDBrightness').value = settings.ledBrightness >= 0 ? settings.ledBrightness : '';
+                    
+                    const container = document.getElementById('boardSliderNames');
+                    container.innerHTML = '';
+                    const numSliders = settings.sliders > 0 ? settings.sliders : document.querySelectorAll('#sliderSmoothing input').length;
+                    for (let i = 0; i < numSliders; i++) {
+                        const row = document.createElement('div');
+                        row.className = 'slider-row';
+                        const label = document.createElement('label');
+                        label.textContent = t('web.board_slider_name', i + 1);
+                        label.htmlFor = 'boardSliderName' + i;
+                        const input = document.createElement('input');
+                        input.id = 'boardSliderName' + i;
+                        input.type = 'text';
+                        input.maxLength = 32;
+                        input.dataset.slider = i;
+                        input.value = settings.sliderNames && settings.sliderNames[i] !== undefined ? settings.sliderNames[i] : '';
+                        row.appendChild(label);
+                        row.appendChild(input);
+                        container.appendChild(row);
+                    }
+                    
+                    showSuccess(t('web.board_read_done'));
                 })
                 .catch(error => {
-                    list.innerHTML = '<div style="text-align: center; color: #dc3545;">Failed to load targets: ' + error.message + '</div>';
+                    showError(t('web.board_read_failed', error.message));
+                })
+                .finally(() => {
+                    button.disabled = false;
                 });
         }
         
-        function renderSpecialTargets(targets, search) {
-            const list = document.getElementById('specialTargetsList');
-            search = (search || '').toLowerCase();
-            list.innerHTML = '';
-            // Group targets by type and category
-            const specialTargets = targets.filter(t => t.type === 'special');
-            const processTargets = targets.filter(t => t.type === 'process');
-            const deviceTargets = targets.filter(t => t.type === 'device');
-            let installedTargets = targets.filter(t => t.type === 'installed');
-            // Filter installed targets by search
-            if (search) {
-                installedTargets = installedTargets.filter(t =>
-                    t.displayName.toLowerCase().includes(search) ||
-                    (t.category && t.category.toLowerCase().includes(search))
-                );
+        function writeBoardSettings(button) {
+            const numberOrUnset = (value, unset) => value.trim() === '' ? unset : parseInt(value);
+            const settings = {
+                name: document.getElementById('boardName').value.trim(),
+                sliders: numberOrUnset(document.getElementById('boardSliders').value, 0),
+                ledBrightness: numberOrUnset(document.getElementById('boardLEDBrightness').value, -1),
+                sliderNames: {}
+            };
+            
+            document.querySelectorAll('#boardSliderNames input').forEach(input => {
+                if (input.value.trim() !== '') {
+                    settings.sliderNames[input.dataset.slider] = input.value.trim();
+                }
+            });
+            
+            button.disabled = true;
+            fetch('/api/board', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify(settings)
+            })
+            .then(response => {
+                if (!response.ok) {
+                    return response.text().then(text => { throw new Error(text); });
+                }
+                showSuccess(t('web.board_written'));
+            })
+            .catch(error => {
+                showError(t('web.board_write_failed', error.message));
+            })
+            .finally(() => {
+                button.disabled = false;
+            });
+        }
+        
+        // with preview, explains what the slider's targets as typed above would control, without saving them
+        function explainSlider(preview) {
+            const slider = parseInt(document.getElementById('explainSlider').value, 10) - 1;
+            let url = '/api/explain?slider=' + slider;
+            if (preview) {
+                const input = document.querySelector('input[name="slider' + slider + '"]');
+                url += '&targets=' + encodeURIComponent(input ? input.value : '');
             }
-            // Add special targets section
-            if (specialTargets.length > 0) {
-                const specialSection = document.createElement('div');
-                specialSection.innerHTML = '<h4 style="margin: 10px 0 5px 0; color: #007acc;">System Controls</h4>';
-                list.appendChild(specialSection);
-                specialTargets.forEach(target => {
-                    const btn = document.createElement('button');
-                    btn.className = 'modal-btn btn-primary';
-                    btn.textContent = target.displayName;
-                    btn.title = target.description;
-                    btn.onclick = function() { selectTarget(target.name); };
-                    list.appendChild(btn);
+            
+            fetch(url)
+                .then(response => {
+                    if (!response.ok) {
+                        return response.text().then(text => { throw new Error(text); });
+                    }
+                    return response.json();
+                })
+                .then(renderExplanation)
+                .catch(error => {
+                    showError(t('web.explain_failed', error.message));
+                });
+        }
+        
+        // beeps on each output the slider's saved targets play on, to hear which one it controls
+        function playTestTone(button) {
+            const slider = parseInt(document.getElementById('explainSlider').value, 10) - 1;
+            button.disabled = true;
+            
+            fetch('/api/testtone?slider=' + slider, { method: 'POST' })
+                .then(response => {
+                    if (!response.ok) {
+                        return response.text().then(text => { throw new Error(text); });
+                    }
+                    return response.json();
+                })
+                .then(data => {
+                    showSuccess(t('web.test_tone_played', data.played.join(', ')));
+                })
+                .catch(error => {
+                    showError(t('web.test_tone_failed', error.message));
+                })
+                .finally(() => {
+                    button.disabled = false;
                 });
+        }
+        
+        function percent(value) {
+            return Math.round(value * 100) + '%';
+        }
+        
+        // lays out the resolution chain: targets, what they resolved to, and the sessions behind those
+        function renderExplanation(data) {
+            const container = document.getElementById('explanation');
+            container.innerHTML = '';
+            
+            const notes = document.createElement('div');
+            notes.className = 'help-text';
+            const lines = [data.sliderValue !== undefined ? t('web.explain_position', percent(data.sliderValue)) : t('web.explain_position_unknown')];
+            if (data.preview) {
+                lines.push(t('web.explain_preview'));
             }
-            // Add process targets section
-            if (processTargets.length > 0) {
-                const processSection = document.createElement('div');
-                processSection.innerHTML = '<h4 style="margin: 15px 0 5px 0; color: #007acc;">Running Applications</h4>';
-                list.appendChild(processSection);
-                processTargets.forEach(target => {
-                    const btn = document.createElement('button');
-                    btn.className = 'modal-btn btn-secondary';
+            if (data.overridden) {
+                lines.push(t('web.explain_overridden'));
+            }
+            if (data.frozen) {
+                lines.push(t('web.explain_frozen'));
+            }
+            if (data.adopted) {
+                lines.push(t('web.explain_adopted'));
+            }
+            if (data.targets.length === 0) {
+                lines.push(t('web.explain_unmapped'));
+            }
+            lines.forEach((line, idx) => {
+                if (idx > 0) {
+                    notes.appendChild(document.createElement('br'));
+                }
+                notes.appendChild(document.createTextNode(line));
+            });
+            container.appendChild(notes);
+            
+            const list = document.createElement('ul');
+            data.targets.forEach(target => {
+                const item = document.createElement('li');
+                const name = document.createElement('strong');
+                name.textContent = target.target;
+                item.appendChild(name);
+                item.appendChild(document.createTextNode(' (' + t('web.explain_kind_' + target.kind) + ')'));
+                
+                if (target.neverMatched) {
+                    const warning = document.createElement('div');
+                    warning.className = 'target-warning';
+                    warning.style.margin = '0';
+                    warning.textContent = t('web.explain_never_matched');
+                    item.appendChild(warning);
+                }
+                
+                const resolvedList = document.createElement('ul');
+                if (target.resolved.length === 0) {
+                    const resolvedItem = document.createElement('li');
+                    resolvedItem.textContent = t('web.explain_resolved_nothing');
+                    resolvedList.appendChild(resolvedItem);
+                }
+                target.resolved.forEach(resolved => {
+                    const resolvedItem = document.createElement('li');
+                    let text = resolved.key;
+                    if (resolved.displayName) {
+                        text += ' (' + resolved.displayName + ')';
+                    }
+                    if (resolved.volume !== undefined) {
+                        text += ': ' + t('web.explain_sets', percent(resolved.volume));
+                    }
+                    if (resolved.sessions.length === 0) {
+                        text += ' - ' + t('web.explain_no_sessions');
+                    } else {
+                        const volumes = resolved.sessions.map(session => percent(session.volume) + (session.muted ? ' ' + t('web.explain_muted') : ''));
+                        text += ' - ' + t('web.explain_sessions', resolved.sessions.length, volumes.join(', '));
+                    }
+                    resolvedItem.textContent = text;
                     
-                    // Create display text with MPRIS info if available
-                    let displayText = target.displayName;
-                    if (target.mprisInfo && target.mprisInfo.isPlaying) {
-                        let mprisText = '';
-                        if (target.mprisInfo.title) {
-                            mprisText = target.mprisInfo.title;
-                            if (target.mprisInfo.artist) {
-                                mprisText += ' by ' + target.mprisInfo.artist;
-                            }
-                        } else if (target.mprisInfo.artist) {
-                            mprisText = target.mprisInfo.artist;
-                        }
-                        
-                        if (mprisText) {
-                            displayText += ' - Playing: ' + mprisText;
-                        }
+                    // several sessions behind one key can each be mapped on their own, by any of their instance keys
+                    const instanced = resolved.sessions.filter(session => session.instances && session.instances.length > 0);
+                    if (instanced.length > 1) {
+                        const instanceList = document.createElement('ul');
+                        instanced.forEach(session => {
+                            const instanceItem = document.createElement('li');
+                            instanceItem.textContent = t('web.explain_instance', session.instances.join(', '), percent(session.volume));
+                            instanceList.appendChild(instanceItem);
+                        });
+                        resolvedItem.appendChild(instanceList);
                     }
                     
-                    btn.textContent = displayText;
-                    btn.title = target.description;
-                    btn.onclick = function() { selectTarget(target.name); };
-                    list.appendChild(btn);
+                    resolvedList.appendChild(resolvedItem);
                 });
-            }
-
-            // Add unmatched MPRIS players section
-            const unmatchedMprisTargets = targets.filter(t => t.type === 'mpris-unmatched');
-            if (unmatchedMprisTargets.length > 0) {
-                const mprisSection = document.createElement('div');
-                mprisSection.innerHTML = '<h4 style="margin: 15px 0 5px 0; color: #007acc;">Unmatched MPRIS Players</h4>';
-                list.appendChild(mprisSection);
-                unmatchedMprisTargets.forEach(target => {
-                    const btn = document.createElement('button');
-                    btn.className = 'modal-btn btn-secondary';
-                    let displayText = target.displayName || target.name;
-                    if (target.mprisInfo && target.mprisInfo.title) {
-                        displayText += ' - ' + target.mprisInfo.title;
-                        if (target.mprisInfo.artist) {
-                            displayText += ' by ' + target.mprisInfo.artist;
-                        }
+                item.appendChild(resolvedList);
+                
+                list.appendChild(item);
+            });
+            container.appendChild(list);
+        }
+        
+        function loadTimeline() {
+            fetch('/api/timeline')
+                .then(response => response.json())
+                .then(events => {
+                    const list = document.getElementById('timeline');
+                    list.innerHTML = '';
+                    
+                    if (events.length === 0) {
+                        const item = document.createElement('li');
+                        item.textContent = t('web.timeline_empty');
+                        list.appendChild(item);
+                        return;
                     }
-                    btn.textContent = displayText;
-                    btn.title = target.description;
-                    btn.onclick = function() { selectTarget(target.name); };
-                    mprisSection.appendChild(btn);
+                    
+                    events.reverse().forEach(event => {
+                        const item = document.createElement('li');
+                        let text = new Date(event.time).toLocaleTimeString() + ' - ';
+                        if (event.volume !== undefined) {
+                            text += t('web.timeline_' + event.kind, event.subject, percent(event.volume), event.slider + 1);
+                        } else {
+                            text += t('web.timeline_' + event.kind, event.subject, event.detail || '');
+                        }
+                        if (event.count > 1) {
+                            text += ' ' + t('web.timeline_repeated', event.count);
+                        }
+                        item.textContent = text;
+                        list.appendChild(item);
+                    });
+                })
+                .catch(error => {
+                    showError(t('web.timeline_failed', error.message));
                 });
+        }
+        
+        // while the board isn't connected, show what auto-detection is up to instead of a plain "not connected"
+        function watchDetection() {
+            fetch('/api/status')
+                .then(response => response.json())
+                .then(status => {
+                    renderDetection(status);
+                    setTimeout(watchDetection, status.connected ? 5000 : 1000);
+                })
+                .catch(() => setTimeout(watchDetection, 5000));
+        }
+        
+        function renderDetection(status) {
+            const banner = document.getElementById('detectionBanner');
+            const detection = status.detection;
+            if (status.connected || !detection) {
+                banner.hidden = true;
+                return;
             }
-
-            // Add device targets section
-            if (deviceTargets.length > 0) {
-                const deviceSection = document.createElement('div');
-                deviceSection.innerHTML = '<h4 style="margin: 15px 0 5px 0; color: #007acc;">Audio Devices</h4>';
-                list.appendChild(deviceSection);
-                deviceTargets.forEach(target => {
-                    const btn = document.createElement('button');
-                    btn.className = 'modal-btn btn-secondary';
-                    btn.textContent = target.displayName;
-                    btn.title = target.description;
-                    btn.onclick = function() { selectTarget(target.name); };
-                    list.appendChild(btn);
+            
+            document.getElementById('detectionText').textContent = detection.active
+                ? t('web.detect.probing', detection.port, detection.baudRate)
+                : t('web.detect.failed');
+            
+            const results = document.getElementById('detectionResults');
+            results.innerHTML = '';
+            detection.results.forEach(result => {
+                const item = document.createElement('li');
+                item.textContent = t('web.detect.' + result.outcome, result.port, result.baudRate);
+                results.appendChild(item);
+            });
+            banner.hidden = false;
+        }
+        
+        function loadPermissions() {
+            fetch('/api/permissions')
+                .then(response => response.json())
+                .then(problems => {
+                    const notice = document.getElementById('permissionsNotice');
+                    if (problems.length === 0) {
+                        notice.style.display = 'none';
+                        return;
+                    }
+                    document.getElementById('permissionsText').textContent = t('web.permissions_notice', problems.map(p => p.portName).join(', '));
+                    notice.style.display = 'block';
                 });
-            }
-            // Add installed applications section (grouped by category) - accordion style
-            if (installedTargets.length > 0) {
-                // Group installed apps by category
-                const categories = {};
-                installedTargets.forEach(target => {
-                    const category = target.category || 'Other';
-                    if (!categories[category]) {
-                        categories[category] = [];
+        }
+        
+        function requestVersion(button) {
+            button.disabled = true;
+            fetch('/api/version')
+                .then(response => {
+                    if (!response.ok) {
+                        return response.text().then(text => { throw new Error(text); });
                     }
-                    categories[category].push(target);
+                    return response.json();
+                })
+                .then(data => {
+                    showSuccess(t('web.firmware_version', data.version));
+                })
+                .catch(error => {
+                    showError(t('web.firmware_version_failed', error.message));
+                })
+                .finally(() => {
+                    button.disabled = false;
                 });
-                
-                // Sort categories alphabetically
-                const sortedCategories = Object.keys(categories).sort();
-                
-                // Create accordion container
-                const accordionContainer = document.createElement('div');
-                accordionContainer.className = 'accordion';
-                
-                // Create accordion header
-                const accordionHeader = document.createElement('div');
-                accordionHeader.className = 'accordion-header';
-                accordionHeader.innerHTML = '<span>Installed Applications (' + installedTargets.length + ')</span><span class="accordion-icon">▼</span>';
-                accordionHeader.onclick = function() {
+        }
+        
+        function loadLoudness() {
+            fetch('/api/loudness')
+                .then(response => response.json())
+                .then(renderLoudness);
+        }
+        
+        function calibrateLoudness(button) {
+            button.disabled = true;
+            showSuccess(t('web.loudness_calibrating'));
+            fetch('/api/loudness', { method: 'POST' })
+                .then(response => {
+                    if (!response.ok) {
+                        return response.text().then(text => { throw new Error(text); });
+                    }
+                    return response.json();
+                })
+                .then(data => {
+                    if (Object.keys(data.offsets).length === 0) {
+                        showError(t('web.loudness_nothing_playing'));
+                        return;
+                    }
+                    renderLoudness(data);
+                    showSuccess(t('web.loudness_calibrated'));
+                })
+                .catch(error => {
+                    showError(t('web.loudness_failed', error.message));
+                })
+                .finally(() => {
+                    button.disabled = false;
+                });
+        }
+        
+        function clearLoudness() {
+            fetch('/api/loudness', { method: 'DELETE' })
+                .then(response => {
+                    if (!response.ok) {
+                        return response.text().then(text => { throw new Error(text); });
+                    }
+                    return response.json();
+                })
+                .then(renderLoudness)
+                .catch(error => {
+                    showError(t('web.loudness_failed', error.message));
+                });
+        }
+        
+        // lists each target's offset, quietest (the reference) first, then the apps that were silent
+        function renderLoudness(data) {
+            const list = document.getElementById('loudnessOffsets');
+            list.innerHTML = '';
+            
+            const targets = Object.keys(data.offsets).sort((a, b) => data.offsets[b] - data.offsets[a]);
+            if (targets.length === 0) {
+                const item = document.createElement('li');
+                item.textContent = t('web.loudness_none');
+                list.appendChild(item);
+            }
+            targets.forEach(target => {
+                const item = document.createElement('li');
+                item.textContent = t('web.loudness_offset', target, data.offsets[target]);
+                list.appendChild(item);
+            });
+            (data.silent || []).forEach(target => {
+                const item = document.createElement('li');
+                item.textContent = t('web.loudness_silent', target);
+                list.appendChild(item);
+            });
+        }
+        
+        function fixPermissions() {
+            fetch('/api/permissions/fix', { method: 'POST' })
+                .then(response => response.json())
+                .then(data => {
+                    showSuccess(t('web.permissions_started'));
+                })
+                .catch(error => {
+                    showError(t('web.permissions_failed', error.message));
+                });
+        }
+        
+        // the refresh frequency only matters while deej refreshes by itself
+        function updateRefreshFrequency() {
+            const manual = document.getElementById('sessionRefresh').value === 'manual';
+            document.getElementById('refreshFrequencyGroup').style.display = manual ? 'none' : '';
+        }
+        
+        function loadConfig() {
+            fetch('/api/config')
+                .then(response => response.json())
+                .then(data => {
+                    populateSliderMappings(data.sliderMappings, data.numSliders, data.unmatchedTargets, data.slidersDetected,
+                        data.sliderNames, data.boardSliderNames);
+                    document.getElementById('comPort').value = data.comPort;
+                    document.getElementById('baudRate').value = data.baudRate;
+                    document.getElementById('invertSliders').checked = data.invertSliders;
+                    document.getElementById('noiseReduction').value = data.noiseReduction;
+                    document.getElementById('sessionRefresh').value = data.sessionRefresh;
+                    document.getElementById('refreshFrequency').value = data.refreshFrequency;
+                    updateRefreshFrequency();
+                })
+                .catch(error => {
+                    showError(t('web.load_failed', error.message));
+                });
+        }
+        
+        function refreshSliderCount() {
+            fetch('/api/config')
+                .then(response => response.json())
+                .then(data => {
+                    populateSliderMappings(data.sliderMappings, data.numSliders, data.unmatchedTargets, data.slidersDetected,
+                        data.sliderNames, data.boardSliderNames);
+                    showSuccess(t('web.slider_count_refreshed', data.numSliders));
+                })
+                .catch(error => {
+                    showError(t('web.slider_count_failed', error.message));
+                });
+        }
+        
+        function populateSliderMappings(mappings, numSliders, unmatchedTargets, slidersDetected, names, boardNames) {
+            const container = document.getElementById('sliderMappings');
+            container.innerHTML = '';
+            
+            // Add slider count info
+            const infoDiv = document.createElement('div');
+            infoDiv.className = 'help-text';
+            const hints = [t('web.targets_hint'), t('web.targets_separator_hint')];
+            const status = document.createElement('strong');
+            if (slidersDetected) {
+                status.textContent = t('web.sliders_detected', numSliders);
+            } else {
+                status.style.color = 'var(--error-text)';
+                status.textContent = t('web.sliders_default', numSliders);
+                hints.unshift(t('web.sliders_default_hint'));
+            }
+            infoDiv.appendChild(status);
+            hints.forEach(hint => {
+                infoDiv.appendChild(document.createElement('br'));
+                infoDiv.appendChild(document.createTextNode(hint));
+            });
+            container.appendChild(infoDiv);
+            
+            for (let i = 0; i < numSliders; i++) {
+                const sliderDiv = document.createElement('div');
+                sliderDiv.className = 'slider-row';
+                
+                const label = document.createElement('label');
+                label.textContent = t('web.slider', i + 1);
+                label.htmlFor = 'slider' + i;
+                
+                // names the board keeps show through until the config names the slider itself
+                const nameInput = document.createElement('input');
+                nameInput.id = 'sliderName' + i;
+                nameInput.type = 'text';
+                nameInput.name = 'sliderName' + i;
+                nameInput.className = 'slider-name';
+                nameInput.maxLength = 32;
+                nameInput.placeholder = (boardNames || {})[i] || t('web.slider_name.placeholder');
+                nameInput.value = (names || {})[i] || '';
+                nameInput.setAttribute('aria-label', t('web.slider_name_for', i + 1));
+                
+                const input = document.createElement('input');
+                input.id = 'slider' + i;
+                input.type = 'text';
+                input.name = 'slider' + i;
+                input.placeholder = t('web.slider.placeholder');
+                input.value = mappings[i] || '';
+                
+                const specialBtn = document.createElement('button');
+                specialBtn.type = 'button';
+                specialBtn.className = 'special-btn';
+                specialBtn.textContent = t('web.pick_target');
+                specialBtn.setAttribute('aria-label', t('web.pick_target_for', i + 1));
+                specialBtn.setAttribute('aria-haspopup', 'dialog');
+                specialBtn.onclick = function() { showSpecialModal(i, specialBtn); };
+                
+                sliderDiv.appendChild(label);
+                sliderDiv.appendChild(nameInput);
+                sliderDiv.appendChild(input);
+                sliderDiv.appendChild(specialBtn);
+                container.appendChild(sliderDiv);
+                
+                const playingDiv = document.createElement('div');
+                playingDiv.id = 'slider' + i + 'Playing';
+                playingDiv.className = 'now-playing';
+                container.appendChild(playingDiv);
+                
+                // targets that never matched an audio session are usually typos
+                const unmatched = (unmatchedTargets || {})[i];
+                if (unmatched && unmatched.length > 0) {
+                    const warning = document.createElement('div');
+                    warning.id = 'slider' + i + 'Warning';
+                    warning.className = 'target-warning';
+                    warning.textContent = t('web.unmatched_targets', unmatched.join(', '));
+                    input.setAttribute('aria-describedby', warning.id);
+                    container.appendChild(warning);
+                }
+            }
+            
+            showNowPlaying();
+        }
+        
+        // slider -> what's playing on it, pushed over a websocket as it changes
+        let nowPlaying = {};
+        
+        function watchNowPlaying() {
+            const socket = new WebSocket((location.protocol === 'https:' ? 'wss://' : 'ws://') + location.host + '/api/nowplaying/ws');
+            socket.onmessage = event => {
+                nowPlaying = {};
+                JSON.parse(event.data).forEach(slider => {
+                    nowPlaying[slider.slider] = slider.nowPlaying;
+                });
+                showNowPlaying();
+            };
+            socket.onclose = () => setTimeout(watchNowPlaying, 5000);
+        }
+        
+        function showNowPlaying() {
+            document.querySelectorAll('.now-playing').forEach(el => {
+                const info = nowPlaying[el.id.replace('slider', '').replace('Playing', '')];
+                let text = '';
+                if (info && info.isPlaying && info.title) {
+                    text = t('web.now_playing', info.artist ? t('web.by_artist', info.title, info.artist) : info.title);
+                }
+                el.textContent = text;
+            });
+        }
+        
+        // where focus goes back to once the modal closes
+        let modalOpener = null;
+        
+        function showSpecialModal(sliderIndex, opener) {
+            currentSliderIndex = sliderIndex;
+            modalOpener = opener || document.activeElement;
+            const modal = document.getElementById('specialModal');
+            const list = document.getElementById('specialTargetsList');
+            const searchContainer = document.getElementById('specialTargetsSearchContainer');
+            list.innerHTML = '<div style="text-align: center; margin-bottom: 10px;"><strong>' + t('web.loading_targets') + '</strong></div>';
+            searchContainer.innerHTML = '<input type="text" id="specialTargetsSearch" style="margin-bottom: 10px; display: block;">';
+            const search = document.getElementById('specialTargetsSearch');
+            search.placeholder = t('web.search_installed');
+            search.setAttribute('aria-label', t('web.search_installed'));
+            modal.style.display = 'block';
+            search.focus();
+            // Fetch available targets from the server
+            fetch('/api/targets')
+                .then(response => response.json())
+                .then(targets => {
+                    window._allAudioTargets = targets;
+                    renderSpecialTargets(targets, '');
+                    document.getElementById('specialTargetsSearch').oninput = function(e) {
+                        renderSpecialTargets(window._allAudioTargets, e.target.value);
+                    };
+                    // Add rescan button handler
+                    document.getElementById('rescanRunningBtn').onclick = function() {
+                        list.innerHTML = '<div style="text-align: center; margin-bottom: 10px;"><strong>' + t('web.rescanning') + '</strong></div>';
+                        fetch('/api/targets?refresh=1')
+                            .then(response => response.json())
+                            .then(targets => {
+                                window._allAudioTargets = targets;
+                                renderSpecialTargets(targets, document.getElementById('specialTargetsSearch').value);
+                            });
+                    };
+                })
+                .catch(error => {
+                    list.innerHTML = '<div style="text-align: center; color: var(--error-text);">' + t('web.targets_failed', error.message) + '</div>';
+                });
+        }
+        
+        function renderSpecialTargets(targets, search) {
+            const list = document.getElementById('specialTargetsList');
+            search = (search || '').toLowerCase();
+            list.innerHTML = '';
+            // Group targets by type and category
+            const specialTargets = targets.filter(t => t.type === 'special');
+            const processTargets = targets.filter(t => t.type === 'process');
+            const deviceTargets = targets.filter(t => t.type === 'device');
+            let installedTargets = targets.filter(t => t.type === 'installed');
+            // Filter installed targets by search
+            if (search) {
+                installedTargets = installedTargets.filter(t =>
+                    t.displayName.toLowerCase().includes(search) ||
+                    (t.category && t.category.toLowerCase().includes(search))
+                );
+            }
+            // Add special targets section
+            if (specialTargets.length > 0) {
+                const specialSection = document.createElement('div');
+                specialSection.innerHTML = '<h4 style="margin: 10px 0 5px 0; color: #007acc;">' + t('web.system_controls') + '</h4>';
+                list.appendChild(specialSection);
+                specialTargets.forEach(target => {
+                    const btn = document.createElement('button');
+                    btn.className = 'modal-btn btn-primary';
+                    btn.textContent = target.displayName;
+                    btn.title = target.description;
+                    btn.onclick = function() { selectTarget(target.name); };
+                    list.appendChild(btn);
+                });
+            }
+            // Add process targets section
+            if (processTargets.length > 0) {
+                const processSection = document.createElement('div');
+                processSection.innerHTML = '<h4 style="margin: 15px 0 5px 0; color: #007acc;">' + t('web.running_apps') + '</h4>';
+                list.appendChild(processSection);
+                processTargets.forEach(target => {
+                    const btn = document.createElement('button');
+                    btn.className = 'modal-btn btn-secondary';
+                    
+                    // Create display text with MPRIS info if available
+                    let displayText = target.displayName;
+                    if (target.mprisInfo && target.mprisInfo.isPlaying) {
+                        let mprisText = '';
+                        if (target.mprisInfo.title) {
+                            mprisText = target.mprisInfo.title;
+                            if (target.mprisInfo.artist) {
+                                mprisText = t('web.by_artist', mprisText, target.mprisInfo.artist);
+                            }
+                        } else if (target.mprisInfo.artist) {
+                            mprisText = target.mprisInfo.artist;
+                        }
+                        
+                        if (mprisText) {
+                            displayText = t('web.playing', displayText, mprisText);
+                        }
+                    }
+                    
+                    btn.textContent = displayText;
+                    btn.title = target.description;
+                    btn.onclick = function() { selectTarget(target.name); };
+                    list.appendChild(btn);
+                });
+            }
+
+            // Add unmatched MPRIS players section
+            const unmatchedMprisTargets = targets.filter(t => t.type === 'mpris-unmatched');
+            if (unmatchedMprisTargets.length > 0) {
+                const mprisSection = document.createElement('div');
+                mprisSection.innerHTML = '<h4 style="margin: 15px 0 5px 0; color: #007acc;">' + t('web.mpris_players') + '</h4>';
+                list.appendChild(mprisSection);
+                unmatchedMprisTargets.forEach(target => {
+                    const btn = document.createElement('button');
+                    btn.className = 'modal-btn btn-secondary';
+                    let displayText = target.displayName || target.name;
+                    if (target.mprisInfo && target.mprisInfo.title) {
+                        let mprisText = target.mprisInfo.title;
+                        if (target.mprisInfo.artist) {
+                            mprisText = t('web.by_artist', mprisText, target.mprisInfo.artist);
+                        }
+                        displayText += ' - ' + mprisText;
+                    }
+                    btn.textContent = displayText;
+                    btn.title = target.description;
+                    btn.onclick = function() { selectTarget(target.name); };
+                    mprisSection.appendChild(btn);
+                });
+            }
+
+            // Add device targets section
+            if (deviceTargets.length > 0) {
+                const deviceSection = document.createElement('div');
+                deviceSection.innerHTML = '<h4 style="margin: 15px 0 5px 0; color: #007acc;">' + t('web.audio_devices') + '</h4>';
+                list.appendChild(deviceSection);
+                deviceTargets.forEach(target => {
+                    const btn = document.createElement('button');
+                    btn.className = 'modal-btn btn-secondary';
+                    btn.textContent = target.displayName;
+                    btn.title = target.description;
+                    btn.onclick = function() { selectTarget(target.name); };
+                    list.appendChild(btn);
+                });
+            }
+            // Add installed applications section (grouped by category) - accordion style
+            if (installedTargets.length > 0) {
+                // Group installed apps by category
+                const categories = {};
+                installedTargets.forEach(target => {
+                    const category = target.category || t('web.other_category');
+                    if (!categories[category]) {
+                        categories[category] = [];
+                    }
+                    categories[category].push(target);
+                });
+                
+                // Sort categories alphabetically
+                const sortedCategories = Object.keys(categories).sort();
+                
+                // Create accordion container
+                const accordionContainer = document.createElement('div');
+                accordionContainer.className = 'accordion';
+                
+                // Create accordion header
+                const accordionHeader = document.createElement('button');
+                accordionHeader.type = 'button';
+                accordionHeader.className = 'accordion-header';
+                accordionHeader.setAttribute('aria-expanded', 'false');
+                accordionHeader.setAttribute('aria-controls', 'installedAppsList');
+                accordionHeader.innerHTML = '<span>' + t('web.installed_apps', installedTargets.length) + '</span><span class="accordion-icon" aria-hidden="true">▼</span>';
+                accordionHeader.onclick = function() {
                     const content = accordionContainer.querySelector('.accordion-content');
                     const icon = accordionHeader.querySelector('.accordion-icon');
-                    content.classList.toggle('expanded');
+                    const expanded = content.classList.toggle('expanded');
                     icon.classList.toggle('expanded');
+                    accordionHeader.setAttribute('aria-expanded', expanded);
                 };
                 accordionContainer.appendChild(accordionHeader);
                 
                 // Create accordion content
                 const accordionContent = document.createElement('div');
                 accordionContent.className = 'accordion-content';
+                accordionContent.id = 'installedAppsList';
+                
+                // searching is for finding an app, so show what was found
+                if (search) {
+                    accordionContent.classList.add('expanded');
+                    accordionHeader.querySelector('.accordion-icon').classList.add('expanded');
+                    accordionHeader.setAttribute('aria-expanded', 'true');
+                }
                 
                 sortedCategories.forEach(category => {
                     const categorySection = document.createElement('div');
                     categorySection.style.marginBottom = '15px';
-                    const categoryHeader = document.createElement('h5');
+                    const categoryHeader = document.createElement('h4');
                     categoryHeader.textContent = category;
                     categoryHeader.style.margin = '10px 0 5px 0';
-                    categoryHeader.style.color = '#666';
+                    categoryHeader.style.color = 'var(--heading)';
                     categoryHeader.style.fontSize = '14px';
                     categorySection.appendChild(categoryHeader);
                     // Sort apps within category alphabetically
@@ -610,227 +1945,1840 @@ func (wcs *WebConfigServer) handleIndex(w http.ResponseWriter, r *http.Request)
                         btn.onclick = function() { selectTarget(target.name); };
                         categorySection.appendChild(btn);
                     });
-                    accordionContent.appendChild(categorySection);
+                    accordionContent.appendChild(categorySection);
+                });
+                
+                accordionContainer.appendChild(accordionContent);
+                list.appendChild(accordionContainer);
+            }
+            if (specialTargets.length === 0 && processTargets.length === 0 && deviceTargets.length === 0 && installedTargets.length === 0) {
+                list.innerHTML = '<div style="text-align: center; color: var(--muted);">' + t('web.no_targets') + '</div>';
+            }
+        }
+        
+        function closeSpecialModal() {
+            document.getElementById('specialModal').style.display = 'none';
+            if (modalOpener) {
+                modalOpener.focus();
+                modalOpener = null;
+            }
+        }
+        
+        function selectTarget(target) {
+            const input = document.querySelector('input[name="slider' + currentSliderIndex + '"]');
+            const currentValue = input.value;
+            if (currentValue) {
+                input.value = currentValue + ', ' + target;
+            } else {
+                input.value = target;
+            }
+            closeSpecialModal();
+        }
+        
+        // Handle form submission
+        document.getElementById('configForm').onsubmit = function(e) {
+            e.preventDefault();
+            
+            const formData = {
+                sliderMappings: {},
+                sliderNames: {},
+                comPort: document.getElementById('comPort').value,
+                baudRate: parseInt(document.getElementById('baudRate').value),
+                invertSliders: document.getElementById('invertSliders').checked,
+                noiseReduction: document.getElementById('noiseReduction').value,
+                sessionRefresh: document.getElementById('sessionRefresh').value,
+                refreshFrequency: parseFloat(document.getElementById('refreshFrequency').value)
+            };
+            
+            // Collect slider mappings
+            const numSliders = document.querySelectorAll('#sliderMappings .slider-row').length;
+            for (let i = 0; i < numSliders; i++) {
+                const input = document.querySelector('input[name="slider' + i + '"]');
+                if (input && input.value.trim()) {
+                    formData.sliderMappings[i] = input.value.trim();
+                }
+                
+                const nameInput = document.getElementById('sliderName' + i);
+                if (nameInput && nameInput.value.trim()) {
+                    formData.sliderNames[i] = nameInput.value.trim();
+                }
+            }
+            
+            // Send to server
+            fetch('/api/save', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify(formData)
+            })
+            .then(response => response.json())
+            .then(data => {
+                if (data.success) {
+                    showSuccess(t('web.saved'));
+                } else {
+                    showError(t('web.save_failed', data.error));
+                }
+            })
+            .catch(error => {
+                showError(t('web.save_failed', error.message));
+            });
+        };
+        
+        function showSuccess(message) {
+            const successDiv = document.getElementById('successMessage');
+            successDiv.textContent = message;
+            successDiv.style.display = 'block';
+            setTimeout(() => {
+                successDiv.style.display = 'none';
+            }, 5000);
+        }
+        
+        function showError(message) {
+            const errorDiv = document.getElementById('errorMessage');
+            errorDiv.textContent = message;
+            errorDiv.style.display = 'block';
+            setTimeout(() => {
+                errorDiv.style.display = 'none';
+            }, 5000);
+        }
+        
+        // Close modal when clicking outside
+        window.onclick = function(event) {
+            const modal = document.getElementById('specialModal');
+            if (event.target === modal) {
+                closeSpecialModal();
+            }
+        }
+        
+        // keep the keyboard inside the modal while it's open, and let escape close it
+        document.addEventListener('keydown', function(event) {
+            const modal = document.getElementById('specialModal');
+            if (modal.style.display !== 'block') {
+                return;
+            }
+            
+            if (event.key === 'Escape') {
+                closeSpecialModal();
+                return;
+            }
+            
+            if (event.key !== 'Tab') {
+                return;
+            }
+            
+            const focusable = Array.from(modal.querySelectorAll('button, input')).filter(el => el.offsetParent !== null);
+            if (focusable.length === 0) {
+                return;
+            }
+            
+            const first = focusable[0];
+            const last = focusable[focusable.length - 1];
+            if (event.shiftKey && document.activeElement === first) {
+                event.preventDefault();
+                last.focus();
+            } else if (!event.shiftKey && document.activeElement === last) {
+                event.preventDefault();
+                first.focus();
+            }
+        });
+    </script>
+</body>
+</html>`
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(htmlTemplate))
+}
+
+// handleGetConfig returns the current configuration as JSON
+func (wcs *WebConfigServer) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get the number of sliders from the Arduino connection, asking it if it's connected but hasn't said. until it
+	// answers, the page shows the last known count
+	numSliders := wcs.deej.serial.RequestSliderCount()
+	slidersDetected := numSliders > 0
+	if !slidersDetected {
+		numSliders = wcs.config.offlineSliderCount()
+	}
+
+	// Convert slider mappings to string format for the web interface
+	sliderMappings := make(map[string]string)
+	unmatchedTargets := make(map[string][]string)
+	sliderNames := make(map[string]string)
+	boardSliderNames := make(map[string]string)
+	// runtime overrides aren't part of the config, so don't show them where they could end up being saved
+	sliderMap := wcs.config.configuredSliderMapping
+	for i := 0; i < numSliders; i++ {
+		if targets, exists := sliderMap.get(i); exists {
+			sliderMappings[strconv.Itoa(i)] = strings.Join(targets, ", ")
+
+			for _, target := range targets {
+				if wcs.deej.diagnostics.isUnmatched(target) {
+					unmatchedTargets[strconv.Itoa(i)] = append(unmatchedTargets[strconv.Itoa(i)], target)
+				}
+			}
+		}
+
+		if name, ok := wcs.config.SliderNames[i]; ok {
+			sliderNames[strconv.Itoa(i)] = name
+		}
+
+		if name := wcs.deej.serial.boardSliderName(i); name != "" {
+			boardSliderNames[strconv.Itoa(i)] = name
+		}
+	}
+
+	configData := ConfigData{
+		SliderMappings: sliderMappings,
+		InvertSliders:  wcs.config.InvertSliders,
+		COMPort:        wcs.config.ConnectionInfo.COMPort,
+		BaudRate:       wcs.config.ConnectionInfo.BaudRate,
+		NoiseReduction: wcs.config.NoiseReductionLevel,
+		NumSliders:     numSliders,
+
+		SlidersDetected: slidersDetected,
+
+		SessionRefresh:   wcs.config.SessionRefresh,
+		RefreshFrequency: wcs.config.SessionRefreshInterval.Seconds(),
+
+		UnmatchedTargets: unmatchedTargets,
+
+		SliderNames:      sliderNames,
+		BoardSliderNames: boardSliderNames,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(configData)
+}
+
+// handleSaveConfig saves the configuration from the web interface
+func (wcs *WebConfigServer) handleSaveConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var requestData struct {
+		SliderMappings map[string]string `json:"sliderMappings"`
+		SliderNames    map[string]string `json:"sliderNames"`
+		COMPort        string            `json:"comPort"`
+		BaudRate       int               `json:"baudRate"`
+		InvertSliders  bool              `json:"invertSliders"`
+		NoiseReduction string            `json:"noiseReduction"`
+
+		SessionRefresh   string   `json:"sessionRefresh"`
+		RefreshFrequency *float64 `json:"refreshFrequency"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	// Convert slider mappings to the format expected by viper
+	sliderMapping := make(map[string][]string)
+	for sliderStr, targetsStr := range requestData.SliderMappings {
+		if cleanTargets := splitMappingTargets(targetsStr); len(cleanTargets) > 0 {
+			sliderMapping[sliderStr] = cleanTargets
+		}
+	}
+
+	sliderNames := make(map[string]string)
+	for sliderStr, name := range requestData.SliderNames {
+		if name = cleanSliderName(name); name != "" {
+			sliderNames[sliderStr] = name
+		}
+	}
+
+	values := map[string]interface{}{
+		configKeySliderMapping:       sliderMapping,
+		configKeySliderNames:         sliderNames,
+		configKeyInvertSliders:       requestData.InvertSliders,
+		configKeyCOMPort:             strings.TrimSpace(requestData.COMPort),
+		configKeyBaudRate:            requestData.BaudRate,
+		configKeyNoiseReductionLevel: requestData.NoiseReduction,
+	}
+
+	if requestData.SessionRefresh != "" {
+		values[configKeySessionRefresh] = requestData.SessionRefresh
+	}
+
+	// an empty field comes through as NaN, which JSON has no way of saying
+	if requestData.RefreshFrequency != nil && *requestData.RefreshFrequency >= 0 {
+		values[configKeyRefreshFrequency] = *requestData.RefreshFrequency
+	}
+
+	if err := wcs.config.writeUserConfigValues(values); err != nil {
+		wcs.logger.Errorw("Failed to save configuration", "error", err)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
+// handleGetTargets returns available audio targets as JSON
+func (wcs *WebConfigServer) handleGetTargets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	refresh := r.URL.Query().Get("refresh")
+	if refresh == "1" {
+		// Force session map refresh for running processes
+		if wcs.deej.sessions != nil {
+			wcs.deej.sessions.refreshSessions(true)
+		}
+	}
+
+	targets, err := wcs.deej.GetAvailableAudioTargets()
+	if err != nil {
+		wcs.logger.Errorw("Failed to get available audio targets", "error", err)
+		http.Error(w, "Failed to get audio targets", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(targets)
+}
+
+// handleGetStatus returns the state of the connection to the device, including its advertised capabilities
+func (wcs *WebConfigServer) handleGetStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wcs.deej.serial.Status())
+}
+
+// handleGetVersion asks the connected board for its firmware version
+func (wcs *WebConfigServer) handleGetVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	version, err := wcs.deej.serial.RequestVersion()
+	if err != nil {
+		http.Error(w, errorMessage(err), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"version": version,
+	})
+}
+
+// handleMetrics serves what deej measures about itself in prometheus' text format
+func (wcs *WebConfigServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	wcs.deej.serial.writeConsumerMetrics(w)
+	audioCalls.writeMetrics(w)
+
+	if wcs.deej.latency == nil {
+		fmt.Fprintln(w, "# slider latency isn't being measured, start deej with --measure-latency to include it")
+		return
+	}
+
+	wcs.deej.latency.writeMetrics(w)
+}
+
+// handleGetStrings returns the web UI's strings in deej's current language
+func (wcs *WebConfigServer) handleGetStrings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"locale":  currentLocale(),
+		"strings": localeStrings("web."),
+	})
+}
+
+// handleTheme returns (GET) or changes (POST) the web UI's theme. changes take effect right away,
+// and are saved to the config
+func (wcs *WebConfigServer) handleTheme(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+
+	case "POST":
+		var requestData struct {
+			Theme string `json:"theme"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if err := wcs.config.SetWebTheme(requestData.Theme); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"theme": wcs.config.webTheme(),
+	})
+}
+
+// handleGetPermissions returns serial ports deej wasn't allowed to open
+func (wcs *WebConfigServer) handleGetPermissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wcs.deej.permissions.Problems())
+}
+
+// handleFixPermissions starts the serial permissions helper, whose dialogs appear outside the browser
+func (wcs *WebConfigServer) handleFixPermissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	go wcs.deej.permissions.Fix()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"started": true,
+	})
+}
+
+// handleAutostart returns (GET) or changes (POST) whether deej starts at login. this isn't part of the config,
+// it's whether the platform's autostart entry exists
+func (wcs *WebConfigServer) handleAutostart(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+
+	case "POST":
+		var requestData struct {
+			Enabled bool `json:"enabled"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if err := wcs.deej.setAutostart(requestData.Enabled); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	enabled, err := autostartEnabled()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled": enabled,
+	})
+}
+
+// handleVerbose returns (GET) or changes (POST) whether deej runs in verbose mode
+func (wcs *WebConfigServer) handleVerbose(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+
+	case "POST":
+		var requestData struct {
+			Enabled bool `json:"enabled"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		wcs.deej.SetVerbose(requestData.Enabled)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled": wcs.deej.Verbose(),
+	})
+}
+
+// handleUpdate returns (GET) the newest release deej knows of, or installs it (POST)
+func (wcs *WebConfigServer) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"current":    wcs.deej.updates.currentTag,
+			"available":  wcs.deej.updates.latest(),
+			"canInstall": wcs.deej.updates.canInstall(),
+		})
+
+	case "POST":
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := wcs.deej.updates.install(); err != nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleMappingOverrides lists (GET), sets (POST) or clears (DELETE) transient slider mapping overrides.
+// these take effect immediately and last until deej exits, without touching the config file
+func (wcs *WebConfigServer) handleMappingOverrides(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+
+	case "POST":
+		var requestData struct {
+			Slider  int      `json:"slider"`
+			Targets []string `json:"targets"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if err := wcs.config.SetMappingOverride(requestData.Slider, requestData.Targets); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+	case "DELETE":
+		sliderParam := r.URL.Query().Get("slider")
+		if sliderParam == "" {
+			wcs.config.ClearMappingOverrides()
+			break
+		}
+
+		sliderIdx, err := strconv.Atoi(sliderParam)
+		if err != nil {
+			http.Error(w, "Invalid slider index", http.StatusBadRequest)
+			return
+		}
+
+		wcs.config.ClearMappingOverride(sliderIdx)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wcs.config.MappingOverrides())
+}
+
+// handleExplain describes how a slider's mapping resolves right now, from its targets down to the sessions' volumes
+func (wcs *WebConfigServer) handleExplain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sliderIdx, err := strconv.Atoi(r.URL.Query().Get("slider"))
+	if err != nil || sliderIdx < 0 {
+		http.Error(w, "Invalid slider index", http.StatusBadRequest)
+		return
+	}
+
+	// targets that aren't saved yet, to preview what they'd control
+	var preview []string
+	if targets, ok := r.URL.Query()["targets"]; ok {
+		preview = append([]string{}, splitMappingTargets(targets[0])...)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wcs.deej.sessions.explainSlider(sliderIdx, preview))
+}
+
+// handleTestTone plays a test tone on each output the given slider's targets play on. it answers once they've played
+func (wcs *WebConfigServer) handleTestTone(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sliderIdx, err := strconv.Atoi(r.URL.Query().Get("slider"))
+	if err != nil || sliderIdx < 0 {
+		http.Error(w, "Invalid slider index", http.StatusBadRequest)
+		return
+	}
+
+	played, err := wcs.deej.sessions.playTestTone(sliderIdx)
+	if err != nil {
+		http.Error(w, errorMessage(err), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"played": played,
+	})
+}
+
+// handleLoudness returns the loudness offsets (GET), calibrates new ones from whatever's playing (POST) or clears
+// them (DELETE). calibrating takes a few seconds, and its offsets apply once the config file is reloaded
+func (wcs *WebConfigServer) handleLoudness(w http.ResponseWriter, r *http.Request) {
+	offsets := wcs.config.LoudnessOffsets
+	silent := []string{}
+
+	switch r.Method {
+	case "GET":
+
+	case "POST":
+		var err error
+
+		offsets, silent, err = wcs.deej.sessions.calibrateLoudness(loudnessCalibrationDuration)
+		switch {
+		case errors.Is(err, errNothingPlaying):
+			offsets = map[string]float32{}
+		case errors.Is(err, errCalibrationRunning):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		default:
+			if err := wcs.config.SetLoudnessOffsets(offsets); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+	case "DELETE":
+		offsets = map[string]float32{}
+
+		if err := wcs.config.SetLoudnessOffsets(offsets); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"offsets": offsets,
+		"silent":  silent,
+	})
+}
+
+// splitMappingTargets splits a slider's targets as typed in the web UI, comma-separated
+func splitMappingTargets(targetsStr string) []string {
+	var targets []string
+
+	for _, target := range strings.Split(targetsStr, ",") {
+		if target = strings.TrimSpace(target); target != "" {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// handleGetTimeline returns the timeline of session, volume and connection events, oldest first
+func (wcs *WebConfigServer) handleGetTimeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wcs.deej.timeline.snapshot())
+}
+
+// handleBoardSettings reads (GET) or writes (POST) the settings the board keeps in its EEPROM
+func (wcs *WebConfigServer) handleBoardSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		settings, err := wcs.deej.serial.ReadBoardSettings()
+		if err != nil {
+			http.Error(w, errorMessage(err), http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(settings)
+
+	case "POST":
+		settings := newBoardSettings()
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if err := settings.validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := wcs.deej.serial.WriteBoardSettings(settings); err != nil {
+			http.Error(w, errorMessage(err), http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleHardwareSettings returns (GET) or saves (POST) the settings pushed to the board. saved settings
+// reach the board through the config reload that follows
+func (wcs *WebConfigServer) handleHardwareSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		numSliders := wcs.deej.serial.GetNumSliders()
+		if numSliders == 0 {
+			numSliders = wcs.config.offlineSliderCount()
+		}
+
+		// so each color can be picked next to the name it's meant to match
+		names := map[int]string{}
+		for sliderIdx := 0; sliderIdx < numSliders; sliderIdx++ {
+			if name := wcs.deej.sliderName(sliderIdx); name != "" {
+				names[sliderIdx] = name
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"settings":   wcs.config.Hardware,
+			"supported":  wcs.deej.serial.Capabilities().Supports(capabilityConfig),
+			"numSliders": numSliders,
+			"names":      names,
+			"dimAfter":   wcs.config.IdleDimAfter.Seconds(),
+			"offAfter":   wcs.config.IdleOffAfter.Seconds(),
+		})
+
+	case "POST":
+		// the idle timeouts live in the same config section, but deej keeps them to itself, see idle.go
+		var request struct {
+			HardwareSettings
+			DimAfter float64 `json:"dimAfter"`
+			OffAfter float64 `json:"offAfter"`
+		}
+
+		request.HardwareSettings = newHardwareSettings()
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		settings := request.HardwareSettings
+
+		if err := settings.validate(); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+
+		// only write the settings that are actually set, so the rest stay at the firmware's defaults
+		hardware := map[string]interface{}{}
+		if settings.ReportRate != hardwareSettingUnset {
+			hardware[hardwareKeyReportRate] = settings.ReportRate
+		}
+
+		if settings.LEDBrightness != hardwareSettingUnset {
+			hardware[hardwareKeyLEDBrightness] = settings.LEDBrightness
+		}
+
+		if len(settings.Smoothing) > 0 {
+			smoothing := map[string]int{}
+			for sliderIdx, value := range settings.Smoothing {
+				smoothing[strconv.Itoa(sliderIdx)] = value
+			}
+
+			hardware[hardwareKeySmoothing] = smoothing
+		}
+
+		if len(settings.Colors) > 0 {
+			colors := map[string]string{}
+			for sliderIdx, value := range settings.Colors {
+				colors[strconv.Itoa(sliderIdx)], _ = normalizeLEDColor(value)
+			}
+
+			hardware[hardwareKeyColors] = colors
+		}
+
+		if request.DimAfter > 0 {
+			hardware[hardwareKeyDimAfter] = request.DimAfter
+		}
+
+		if request.OffAfter > 0 {
+			hardware[hardwareKeyOffAfter] = request.OffAfter
+		}
+
+		if err := wcs.config.writeUserConfigValues(map[string]interface{}{configKeyHardware: hardware}); err != nil {
+			wcs.logger.Errorw("Failed to save hardware settings", "error", err)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSetup serves the setup page, which helps pick the board's port without editing the config by hand
+func (wcs *WebConfigServer) handleSetup(w http.ResponseWriter, r *http.Request) {
+	setupTemplate := `
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title data-i18n="web.setup.title">deej Setup</title>
+    <style>
+` + webThemeCSS + `
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            max-width: 600px;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: var(--page-bg);
+            color: var(--text);
+        }
+        .container {
+            background: var(--surface);
+            padding: 30px;
+            border-radius: 8px;
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+        }
+        .port {
+            display: block;
+            padding: 10px;
+            margin-bottom: 8px;
+            border: 1px solid var(--border);
+            border-radius: 5px;
+            cursor: pointer;
+        }
+        .port input {
+            width: 20px;
+            height: 20px;
+            vertical-align: middle;
+        }
+        .found {
+            color: var(--success-text);
+            font-weight: 500;
+        }
+        button {
+            background-color: #007acc;
+            color: white;
+            padding: 10px 20px;
+            border: none;
+            border-radius: 4px;
+            font-size: 14px;
+            min-height: 44px;
+            cursor: pointer;
+        }
+        :focus-visible {
+            outline: 3px solid var(--focus);
+            outline-offset: 2px;
+        }
+        a {
+            color: var(--link);
+        }
+        #status {
+            margin-top: 15px;
+        }
+        @media (max-width: 600px) {
+            body {
+                padding: 0;
+            }
+            .container {
+                padding: 15px;
+                border-radius: 0;
+                box-shadow: none;
+            }
+            button {
+                width: 100%;
+                margin-bottom: 8px;
+            }
+        }
+    </style>
+</head>
+<body>
+    <main class="container">
+        <h1 id="setupTitle" data-i18n="web.setup.title">Set up deej</h1>
+        <p data-i18n="web.setup.intro">Plug in your deej, then pick the port it's on. deej marks the ports it found a board on.</p>
+        <div id="ports" role="radiogroup" aria-labelledby="setupTitle" aria-live="polite">Looking for your deej, this can take a few seconds...</div>
+        <button type="button" onclick="loadPorts()" data-i18n="web.setup.look_again">Look again</button>
+        <button type="button" onclick="save()" data-i18n="web.setup.save">Save</button>
+        <div id="status" role="status" aria-live="polite"></div>
+        <p><a href="/" data-i18n="web.setup.full_config">Go to the full configuration</a></p>
+    </main>
+    <script>
+        let strings = {};
+
+        function loadStrings() {
+            return fetch('/api/strings')
+                .then(response => response.json())
+                .then(data => {
+                    strings = data.strings;
+                    document.documentElement.lang = data.locale;
+                    document.querySelectorAll('[data-i18n]').forEach(el => {
+                        el.textContent = t(el.dataset.i18n);
+                    });
+                })
+                .catch(error => {
+                    console.warn('Failed to load strings, staying in english', error);
+                });
+        }
+
+        // t looks up a string and fills its %s placeholders in order
+        function t(key, ...args) {
+            let text = strings[key];
+            if (text === undefined) {
+                return key;
+            }
+            args.forEach(arg => {
+                text = text.replace('%s', arg);
+            });
+            return text;
+        }
+
+        function loadPorts() {
+            const container = document.getElementById('ports');
+            container.textContent = t('web.setup.looking');
+
+            fetch('/api/ports')
+                .then(response => response.json())
+                .then(ports => {
+                    container.innerHTML = '';
+
+                    const choices = [{name: 'auto', label: t('web.setup.auto')}].concat(ports.map(port => ({
+                        name: port.name,
+                        label: port.connected ? t('web.setup.connected', port.name)
+                            : port.deej ? t('web.setup.deej_found', port.name)
+                            : port.skipped ? t('web.setup.skipped', port.name)
+                            : port.name,
+                        found: port.deej,
+                    })));
+
+                    const firstFound = ports.find(port => port.deej);
+
+                    choices.forEach(choice => {
+                        const label = document.createElement('label');
+                        label.className = 'port' + (choice.found ? ' found' : '');
+
+                        const radio = document.createElement('input');
+                        radio.type = 'radio';
+                        radio.name = 'port';
+                        radio.value = choice.name;
+                        radio.checked = firstFound ? choice.name === firstFound.name : choice.name === 'auto';
+
+                        label.appendChild(radio);
+                        label.appendChild(document.createTextNode(' ' + choice.label));
+                        container.appendChild(label);
+                    });
+                })
+                .catch(error => {
+                    container.textContent = t('web.setup.ports_failed', error);
+                });
+        }
+
+        function save() {
+            const selected = document.querySelector('input[name="port"]:checked');
+            if (!selected) {
+                return;
+            }
+
+            fetch('/api/setup', {
+                method: 'POST',
+                headers: {'Content-Type': 'application/json'},
+                body: JSON.stringify({comPort: selected.value}),
+            })
+                .then(response => response.json())
+                .then(result => {
+                    document.getElementById('status').textContent = result.success
+                        ? t('web.setup.saved')
+                        : t('web.setup.save_failed', result.error);
+
+                    if (result.success) {
+                        waitForConnection();
+                    }
+                });
+        }
+
+        function waitForConnection() {
+            fetch('/api/status')
+                .then(response => response.json())
+                .then(status => {
+                    if (status.connected) {
+                        document.getElementById('status').textContent = t('web.setup.all_set', status.port);
+                        return;
+                    }
+
+                    setTimeout(waitForConnection, 1000);
+                });
+        }
+
+        fetch('/api/theme')
+            .then(response => response.json())
+            .then(data => {
+                if (data.theme === 'light' || data.theme === 'dark') {
+                    document.documentElement.dataset.theme = data.theme;
+                }
+            });
+
+        loadStrings().finally(loadPorts);
+    </script>
+</body>
+</html>`
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(setupTemplate))
+}
+
+// handleRemote serves the remote page, a mixer of on-screen sliders for phones and other devices on the network
+func (wcs *WebConfigServer) handleRemote(w http.ResponseWriter, r *http.Request) {
+	remoteTemplate := `
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0, user-scalable=no">
+    <meta name="mobile-web-app-capable" content="yes">
+    <meta name="apple-mobile-web-app-capable" content="yes">
+    <title data-i18n="web.remote.title">deej Remote</title>
+    <style>
+` + webThemeCSS + `
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            max-width: 600px;
+            margin: 0 auto;
+            padding: 12px;
+            background-color: var(--page-bg);
+            color: var(--text);
+        }
+        h1 {
+            font-size: 1.3em;
+            margin: 4px 0 12px;
+        }
+        .slider {
+            background: var(--surface);
+            border: 1px solid var(--border);
+            border-radius: 8px;
+            padding: 12px 14px;
+            margin-bottom: 10px;
+        }
+        .slider label {
+            display: flex;
+            justify-content: space-between;
+            gap: 10px;
+            font-weight: 500;
+            margin-bottom: 6px;
+        }
+        .slider .targets {
+            overflow: hidden;
+            text-overflow: ellipsis;
+            white-space: nowrap;
+        }
+        .slider .value {
+            color: var(--muted);
+            font-variant-numeric: tabular-nums;
+        }
+        .slider input {
+            width: 100%;
+            height: 44px;
+            margin: 0;
+            touch-action: pan-y;
+        }
+        :focus-visible {
+            outline: 3px solid var(--focus);
+            outline-offset: 2px;
+        }
+        #status {
+            color: var(--muted);
+            font-size: 14px;
+            margin-bottom: 10px;
+        }
+    </style>
+</head>
+<body>
+    <main>
+        <h1 data-i18n="web.remote.title">deej Remote</h1>
+        <div id="status" role="status" aria-live="polite"></div>
+        <div id="sliders"></div>
+    </main>
+    <script>
+        let strings = {};
+
+        // slider -> the value waiting to be sent, and whether a request for it is already out
+        const pending = {};
+        const sending = {};
+
+        // sliders being dragged keep their own value, rather than whatever the last poll said
+        const dragging = {};
+
+        function loadStrings() {
+            return fetch('/api/strings')
+                .then(response => response.json())
+                .then(data => {
+                    strings = data.strings;
+                    document.documentElement.lang = data.locale;
+                    document.querySelectorAll('[data-i18n]').forEach(el => {
+                        el.textContent = t(el.dataset.i18n);
+                    });
+                })
+                .catch(error => {
+                    console.warn('Failed to load strings, staying in english', error);
+                });
+        }
+
+        // t looks up a string and fills its %s placeholders in order
+        function t(key, ...args) {
+            let text = strings[key];
+            if (text === undefined) {
+                return key;
+            }
+            args.forEach(arg => {
+                text = text.replace('%s', arg);
+            });
+            return text;
+        }
+
+        function percent(value) {
+            return Math.round(value * 100) + '%';
+        }
+
+        function render(state) {
+            const container = document.getElementById('sliders');
+            document.getElementById('status').textContent = state.connected ? t('web.remote.board_connected') : t('web.remote.board_missing');
+
+            state.sliders.forEach(slider => {
+                let row = document.getElementById('remote' + slider.id);
+                if (!row) {
+                    row = document.createElement('div');
+                    row.className = 'slider';
+                    row.id = 'remote' + slider.id;
+
+                    const label = document.createElement('label');
+                    label.htmlFor = 'remoteInput' + slider.id;
+                    const targets = document.createElement('span');
+                    targets.className = 'targets';
+                    const value = document.createElement('span');
+                    value.className = 'value';
+                    label.appendChild(targets);
+                    label.appendChild(value);
+
+                    const input = document.createElement('input');
+                    input.type = 'range';
+                    input.id = 'remoteInput' + slider.id;
+                    input.min = 0;
+                    input.max = 100;
+                    input.oninput = () => move(slider.id, input.value / 100);
+                    input.onpointerdown = () => { dragging[slider.id] = true; };
+                    input.onpointerup = input.onpointercancel = () => { dragging[slider.id] = false; };
+
+                    row.appendChild(label);
+                    row.appendChild(input);
+                    container.appendChild(row);
+                }
+
+                row.querySelector('.targets').textContent = slider.targets.length > 0
+                    ? t('web.remote.slider', slider.name || slider.id + 1, slider.targets.join(', '))
+                    : t('web.remote.slider_unmapped', slider.name || slider.id + 1);
+
+                if (!dragging[slider.id] && pending[slider.id] === undefined && slider.value !== undefined) {
+                    row.querySelector('input').value = Math.round(slider.value * 100);
+                    row.querySelector('.value').textContent = percent(slider.value);
+                }
+            });
+        }
+
+        // moves are sent one at a time per slider, with only the newest value waiting, so a fast drag
+        // doesn't queue up requests faster than they can go through
+        function move(sliderId, value) {
+            document.querySelector('#remote' + sliderId + ' .value').textContent = percent(value);
+            pending[sliderId] = value;
+
+            if (!sending[sliderId]) {
+                send(sliderId);
+            }
+        }
+
+        function send(sliderId) {
+            const value = pending[sliderId];
+            delete pending[sliderId];
+            sending[sliderId] = true;
+
+            fetch('/api/remote', {
+                method: 'POST',
+                headers: {'Content-Type': 'application/json'},
+                body: JSON.stringify({slider: sliderId, value: value}),
+            })
+                .catch(error => {
+                    document.getElementById('status').textContent = t('web.remote.failed', error.message);
+                })
+                .finally(() => {
+                    sending[sliderId] = false;
+                    if (pending[sliderId] !== undefined) {
+                        send(sliderId);
+                    }
+                });
+        }
+
+        // follows the physical sliders (and other remotes), and picks up mapping changes
+        function poll() {
+            fetch('/api/remote')
+                .then(response => response.json())
+                .then(render)
+                .catch(error => {
+                    document.getElementById('status').textContent = t('web.remote.failed', error.message);
+                })
+                .finally(() => setTimeout(poll, 1000));
+        }
+
+        fetch('/api/theme')
+            .then(response => response.json())
+            .then(data => {
+                if (data.theme === 'light' || data.theme === 'dark') {
+                    document.documentElement.dataset.theme = data.theme;
+                }
+            });
+
+        loadStrings().finally(poll);
+    </script>
+</body>
+</html>`
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(remoteTemplate))
+}
+
+// remoteSlider is a slider on the remote page
+type remoteSlider struct {
+	ID      int      `json:"id"`
+	Name    string   `json:"name,omitempty"`
+	Targets []string `json:"targets"`
+
+	// nil until the slider's position is known
+	Value *float32 `json:"value,omitempty"`
+}
+
+// handleRemoteSliders returns (GET) the sliders for the remote page, or moves (POST) one of them
+func (wcs *WebConfigServer) handleRemoteSliders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+
+	case "POST":
+		var requestData struct {
+			Slider int     `json:"slider"`
+			Value  float32 `json:"value"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if requestData.Slider < 0 || requestData.Slider >= wcs.remoteSliderCount() {
+			http.Error(w, "Invalid slider index", http.StatusBadRequest)
+			return
+		}
+
+		if requestData.Value < 0 || requestData.Value > 1 {
+			http.Error(w, "Invalid slider value", http.StatusBadRequest)
+			return
+		}
+
+		wcs.deej.serial.MoveSlider(requestData.Slider, requestData.Value)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sliders := []remoteSlider{}
+	for sliderIdx := 0; sliderIdx < wcs.remoteSliderCount(); sliderIdx++ {
+		slider := remoteSlider{ID: sliderIdx, Name: wcs.deej.sliderName(sliderIdx), Targets: []string{}}
+
+		if targets, ok := wcs.config.SliderMapping.get(sliderIdx); ok {
+			slider.Targets = targets
+		}
+
+		if value, ok := wcs.deej.sliderValue(sliderIdx); ok {
+			slider.Value = &value
+		}
+
+		sliders = append(sliders, slider)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"connected": wcs.deej.serial.Status().Connected,
+		"sliders":   sliders,
+	})
+}
+
+// remoteSliderCount returns how many sliders the remote page shows: as many as the board has (or had, while it's
+// not connected), or as the mapping needs if that's more
+func (wcs *WebConfigServer) remoteSliderCount() int {
+	count := wcs.deej.serial.GetNumSliders()
+	if count == 0 {
+		count = wcs.config.offlineSliderCount()
+	}
+
+	wcs.config.SliderMapping.iterate(func(sliderIdx int, targets []string) {
+		if sliderIdx >= count {
+			count = sliderIdx + 1
+		}
+	})
+
+	return count
+}
+
+// handleStatusPage serves the status page, a read-only view of the mixer meant for a small display left open next
+// to it. it only ever reads, so unlike the remote it can't change anything
+func (wcs *WebConfigServer) handleStatusPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	statusTemplate := `
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <meta name="mobile-web-app-capable" content="yes">
+    <meta name="apple-mobile-web-app-capable" content="yes">
+    <title data-i18n="web.status.title">deej Status</title>
+    <style>
+` + webThemeCSS + `
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            margin: 0;
+            padding: 16px;
+            background-color: var(--page-bg);
+            color: var(--text);
+            cursor: none;
+        }
+        #connection {
+            font-size: 1.1em;
+            color: var(--muted);
+            margin-bottom: 14px;
+        }
+        #connection.connected {
+            color: var(--success-text);
+        }
+        #stability {
+            color: var(--muted);
+            margin: -8px 0 14px;
+        }
+        #stability.unstable {
+            color: var(--error-text);
+        }
+        #sliders {
+            display: grid;
+            grid-template-columns: repeat(auto-fit, minmax(220px, 1fr));
+            gap: 12px;
+        }
+        .slider {
+            background: var(--surface);
+            border: 1px solid var(--border);
+            border-radius: 8px;
+            padding: 14px 16px;
+        }
+        .slider .header {
+            display: flex;
+            justify-content: space-between;
+            gap: 10px;
+            font-size: 1.2em;
+            font-weight: 500;
+        }
+        .slider .targets, .slider .playing {
+            overflow: hidden;
+            text-overflow: ellipsis;
+            white-space: nowrap;
+        }
+        .slider .value {
+            font-variant-numeric: tabular-nums;
+        }
+        .slider .bar {
+            height: 10px;
+            margin: 10px 0 8px;
+            background: var(--subtle);
+            border-radius: 5px;
+            overflow: hidden;
+        }
+        .slider .fill {
+            height: 100%;
+            width: 0;
+            background: var(--link);
+            transition: width 0.2s;
+        }
+        .slider .playing {
+            color: var(--muted);
+            min-height: 1.2em;
+        }
+    </style>
+</head>
+<body>
+    <main>
+        <div id="connection" role="status" aria-live="polite"></div>
+        <div id="stability" hidden></div>
+        <div id="sliders"></div>
+    </main>
+    <script>
+        let strings = {};
+
+        function loadStrings() {
+            return fetch('/api/strings')
+                .then(response => response.json())
+                .then(data => {
+                    strings = data.strings;
+                    document.documentElement.lang = data.locale;
+                    document.querySelectorAll('[data-i18n]').forEach(el => {
+                        el.textContent = t(el.dataset.i18n);
+                    });
+                })
+                .catch(error => {
+                    console.warn('Failed to load strings, staying in english', error);
                 });
-                
-                accordionContainer.appendChild(accordionContent);
-                list.appendChild(accordionContainer);
-            }
-            if (specialTargets.length === 0 && processTargets.length === 0 && deviceTargets.length === 0 && installedTargets.length === 0) {
-                list.innerHTML = '<div style="text-align: center; color: #666;">No audio targets found</div>';
+        }
+
+        // t looks up a string and fills its %s placeholders in order
+        function t(key, ...args) {
+            let text = strings[key];
+            if (text === undefined) {
+                return key;
             }
+            args.forEach(arg => {
+                text = text.replace('%s', arg);
+            });
+            return text;
         }
-        
-        function closeSpecialModal() {
-            document.getElementById('specialModal').style.display = 'none';
+
+        function percent(value) {
+            return Math.round(value * 100) + '%';
         }
-        
-        function selectTarget(target) {
-            const input = document.querySelector('input[name="slider' + currentSliderIndex + '"]');
-            const currentValue = input.value;
-            if (currentValue) {
-                input.value = currentValue + ', ' + target;
-            } else {
-                input.value = target;
+
+        function playing(info) {
+            if (!info || !info.isPlaying || !info.title) {
+                return '';
             }
-            closeSpecialModal();
+            return info.artist ? t('web.by_artist', info.title, info.artist) : info.title;
         }
-        
-        // Handle form submission
-        document.getElementById('configForm').onsubmit = function(e) {
-            e.preventDefault();
-            
-            const formData = {
-                sliderMappings: {},
-                comPort: document.getElementById('comPort').value,
-                baudRate: parseInt(document.getElementById('baudRate').value),
-                invertSliders: document.getElementById('invertSliders').checked,
-                noiseReduction: document.getElementById('noiseReduction').value
-            };
-            
-            // Collect slider mappings
-            const numSliders = document.querySelectorAll('.slider-row').length;
-            for (let i = 0; i < numSliders; i++) {
-                const input = document.querySelector('input[name="slider' + i + '"]');
-                if (input && input.value.trim()) {
-                    formData.sliderMappings[i] = input.value.trim();
-                }
+
+        function render(state) {
+            const connection = document.getElementById('connection');
+            connection.textContent = state.connected ? t('web.status.connected', state.port || state.transport) : t('web.status.disconnected');
+            if (!state.connected && state.detection) {
+                connection.textContent = state.detection.active
+                    ? t('web.detect.probing', state.detection.port, state.detection.baudRate)
+                    : t('web.detect.failed');
             }
-            
-            // Send to server
-            fetch('/api/save', {
-                method: 'POST',
-                headers: {
-                    'Content-Type': 'application/json',
-                },
-                body: JSON.stringify(formData)
-            })
-            .then(response => response.json())
-            .then(data => {
-                if (data.success) {
-                    showSuccess('Configuration saved successfully!');
-                } else {
-                    showError('Failed to save configuration: ' + data.error);
+            connection.className = state.connected ? 'connected' : '';
+            renderStability(state.stability);
+
+            const container = document.getElementById('sliders');
+            state.sliders.forEach(slider => {
+                let card = document.getElementById('status' + slider.id);
+                if (!card) {
+                    card = document.createElement('div');
+                    card.className = 'slider';
+                    card.id = 'status' + slider.id;
+                    card.innerHTML = '<div class="header"><span class="targets"></span><span class="value"></span></div>' +
+                        '<div class="bar"><div class="fill"></div></div><div class="playing"></div>';
+                    container.appendChild(card);
                 }
-            })
-            .catch(error => {
-                showError('Failed to save configuration: ' + error.message);
+
+                card.querySelector('.targets').textContent = slider.targets.length > 0
+                    ? (slider.name ? t('web.status.named', slider.name, slider.targets.join(', ')) : slider.targets.join(', '))
+                    : slider.name || t('web.status.unmapped', slider.id + 1);
+                card.querySelector('.value').textContent = slider.value !== undefined ? percent(slider.value) : '';
+                card.querySelector('.fill').style.width = slider.value !== undefined ? percent(slider.value) : '0';
+                card.querySelector('.playing').textContent = playing(nowPlaying[slider.id]);
             });
-        };
-        
-        function showSuccess(message) {
-            const successDiv = document.getElementById('successMessage');
-            successDiv.textContent = message;
-            successDiv.style.display = 'block';
-            setTimeout(() => {
-                successDiv.style.display = 'none';
-            }, 5000);
-        }
-        
-        function showError(message) {
-            const errorDiv = document.getElementById('errorMessage');
-            errorDiv.textContent = message;
-            errorDiv.style.display = 'block';
-            setTimeout(() => {
-                errorDiv.style.display = 'none';
-            }, 5000);
+
+            // sliders dropped from the mapping or the board
+            Array.from(container.children).slice(state.sliders.length).forEach(card => card.remove());
         }
-        
-        // Close modal when clicking outside
-        window.onclick = function(event) {
-            const modal = document.getElementById('specialModal');
-            if (event.target === modal) {
-                closeSpecialModal();
+
+        // a connection that keeps dropping gets a single notification, the details are shown here
+        function renderStability(stability) {
+            const element = document.getElementById('stability');
+            element.hidden = !stability || stability.recentDisconnects === 0;
+            if (element.hidden) {
+                return;
+            }
+
+            const last = new Date(stability.lastDisconnect).toLocaleTimeString();
+            let text = t('web.status.disconnects', stability.recentDisconnects, stability.windowMinutes, last);
+            if (stability.unstable) {
+                text = t('web.status.unstable') + ' ' + text;
+                if (stability.suppressedNotifications > 0) {
+                    text += ' ' + t('web.status.suppressed', stability.suppressedNotifications);
+                }
             }
+
+            element.textContent = text;
+            element.className = stability.unstable ? 'unstable' : '';
+        }
+
+        // slider -> what's playing on it, pushed over a websocket as it changes
+        let nowPlaying = {};
+
+        function watchNowPlaying() {
+            const socket = new WebSocket((location.protocol === 'https:' ? 'wss://' : 'ws://') + location.host + '/api/nowplaying/ws');
+            socket.onmessage = event => {
+                nowPlaying = {};
+                JSON.parse(event.data).forEach(slider => {
+                    nowPlaying[slider.slider] = slider.nowPlaying;
+                });
+                document.querySelectorAll('#sliders .slider').forEach(card => {
+                    card.querySelector('.playing').textContent = playing(nowPlaying[card.id.replace('status', '')]);
+                });
+            };
+            socket.onclose = () => setTimeout(watchNowPlaying, 5000);
         }
+
+        function poll() {
+            fetch('/api/status/sliders')
+                .then(response => response.json())
+                .then(render)
+                .catch(error => {
+                    document.getElementById('connection').textContent = t('web.status.failed', error.message);
+                })
+                .finally(() => setTimeout(poll, 2000));
+        }
+
+        fetch('/api/theme')
+            .then(response => response.json())
+            .then(data => {
+                if (data.theme === 'light' || data.theme === 'dark') {
+                    document.documentElement.dataset.theme = data.theme;
+                }
+            });
+
+        loadStrings().finally(() => {
+            poll();
+            watchNowPlaying();
+        });
     </script>
 </body>
 </html>`
 
 	w.Header().Set("Content-Type", "text/html")
-	w.Write([]byte(htmlTemplate))
+	w.Write([]byte(statusTemplate))
 }
 
-// handleGetConfig returns the current configuration as JSON
-func (wcs *WebConfigServer) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+// handleGetSliderStatus returns the connection state and every slider's position
+func (wcs *WebConfigServer) handleGetSliderStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Get the number of sliders from the Arduino connection
-	numSliders := wcs.deej.serial.GetNumSliders()
-	if numSliders == 0 {
-		// If not connected, default to 5 sliders (most common)
-		numSliders = 5
-	}
+	sliders := []remoteSlider{}
+	for sliderIdx := 0; sliderIdx < wcs.remoteSliderCount(); sliderIdx++ {
+		slider := remoteSlider{ID: sliderIdx, Name: wcs.deej.sliderName(sliderIdx), Targets: []string{}}
 
-	// Convert slider mappings to string format for the web interface
-	sliderMappings := make(map[string]string)
-	sliderMap := wcs.config.SliderMapping
-	for i := 0; i < numSliders; i++ {
-		if targets, exists := sliderMap.get(i); exists {
-			sliderMappings[strconv.Itoa(i)] = strings.Join(targets, ", ")
+		if targets, ok := wcs.config.SliderMapping.get(sliderIdx); ok {
+			slider.Targets = targets
 		}
-	}
 
-	configData := ConfigData{
-		SliderMappings: sliderMappings,
-		InvertSliders:  wcs.config.InvertSliders,
-		COMPort:        wcs.config.ConnectionInfo.COMPort,
-		BaudRate:       wcs.config.ConnectionInfo.BaudRate,
-		NoiseReduction: wcs.config.NoiseReductionLevel,
-		NumSliders:     numSliders,
+		if value, ok := wcs.deej.sliderValue(sliderIdx); ok {
+			slider.Value = &value
+		}
+
+		sliders = append(sliders, slider)
 	}
 
+	status := wcs.deej.serial.Status()
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(configData)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"connected": status.Connected,
+		"transport": status.Transport,
+		"port":      status.Port,
+		"stability": status.Stability,
+		"detection": status.Detection,
+		"sliders":   sliders,
+	})
 }
 
-// handleSaveConfig saves the configuration from the web interface
-func (wcs *WebConfigServer) handleSaveConfig(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
+// handleGetNowPlaying returns what's playing on each mapped slider
+func (wcs *WebConfigServer) handleGetNowPlaying(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	var requestData struct {
-		SliderMappings map[string]string `json:"sliderMappings"`
-		COMPort        string            `json:"comPort"`
-		BaudRate       int               `json:"baudRate"`
-		InvertSliders  bool              `json:"invertSliders"`
-		NoiseReduction string            `json:"noiseReduction"`
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wcs.deej.nowPlaying.snapshot())
+}
+
+// handleNowPlayingSocket sends what's playing on each mapped slider over a websocket, right away and then whenever
+// it changes
+func (wcs *WebConfigServer) handleNowPlayingSocket(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgradeWebSocket(w, r)
+	if err != nil {
+		wcs.logger.Debugw("Failed to open now playing websocket", "error", err)
+		return
 	}
+	defer ws.close()
 
-	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	updates := wcs.deej.nowPlaying.subscribe()
+	defer wcs.deej.nowPlaying.unsubscribe(updates)
+
+	if err := ws.writeJSON(wcs.deej.nowPlaying.snapshot()); err != nil {
 		return
 	}
 
-	// Convert slider mappings to the format expected by viper
-	sliderMapping := make(map[string][]string)
-	for sliderStr, targetsStr := range requestData.SliderMappings {
-		if targetsStr != "" {
-			targets := strings.Split(targetsStr, ",")
-			var cleanTargets []string
-			for _, target := range targets {
-				target = strings.TrimSpace(target)
-				if target != "" {
-					cleanTargets = append(cleanTargets, target)
-				}
-			}
-			if len(cleanTargets) > 0 {
-				sliderMapping[sliderStr] = cleanTargets
+	for {
+		select {
+		case list := <-updates:
+			if err := ws.writeJSON(list); err != nil {
+				return
 			}
+		case <-ws.closed:
+			return
+		case <-wcs.stopped:
+			return
 		}
 	}
+}
 
-	// Update the viper config
-	wcs.config.userConfig.Set("slider_mapping", sliderMapping)
-	wcs.config.userConfig.Set("invert_sliders", requestData.InvertSliders)
-	wcs.config.userConfig.Set("com_port", strings.TrimSpace(requestData.COMPort))
-	wcs.config.userConfig.Set("baud_rate", requestData.BaudRate)
-	wcs.config.userConfig.Set("noise_reduction", requestData.NoiseReduction)
+// handleGetPorts returns the serial ports around, and which of them a deej answers on
+func (wcs *WebConfigServer) handleGetPorts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-	// Write to file
-	if err := wcs.config.userConfig.WriteConfig(); err != nil {
-		wcs.logger.Errorw("Failed to save configuration", "error", err)
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   err.Error(),
-		})
+	ports, err := wcs.deej.serial.ProbePorts()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-	})
+	json.NewEncoder(w).Encode(ports)
 }
 
-// handleGetTargets returns available audio targets as JSON
-func (wcs *WebConfigServer) handleGetTargets(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
+// handleSaveSetup saves the port picked on the setup page. the config reload that follows connects to it
+func (wcs *WebConfigServer) handleSaveSetup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	refresh := r.URL.Query().Get("refresh")
-	if refresh == "1" {
-		// Force session map refresh for running processes
-		if wcs.deej.sessions != nil {
-			wcs.deej.sessions.refreshSessions(true)
-		}
+	var requestData struct {
+		COMPort string `json:"comPort"`
 	}
 
-	targets, err := wcs.deej.GetAvailableAudioTargets()
-	if err != nil {
-		wcs.logger.Errorw("Failed to get available audio targets", "error", err)
-		http.Error(w, "Failed to get audio targets", http.StatusInternalServerError)
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
+	result := map[string]interface{}{"success": true}
+
+	if err := wcs.config.SetCOMPort(strings.TrimSpace(requestData.COMPort)); err != nil {
+		result = map[string]interface{}{"success": false, "error": err.Error()}
+	} else {
+		wcs.logger.Infow("Saved port from setup page", "comPort", requestData.COMPort)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(targets)
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleInspector serves the serial traffic inspector page
+func (wcs *WebConfigServer) handleInspector(w http.ResponseWriter, r *http.Request) {
+	inspectorTemplate := `
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>deej Serial Inspector</title>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            margin: 0 auto;
+            padding: 20px;
+            background-color: #f5f5f5;
+        }
+        .toolbar {
+            margin-bottom: 10px;
+        }
+        .toolbar label {
+            margin-right: 15px;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            background: white;
+            font-family: monospace;
+            font-size: 13px;
+        }
+        td, th {
+            padding: 4px 8px;
+            border-bottom: 1px solid #eee;
+            text-align: left;
+            vertical-align: top;
+        }
+        .kind-rejected {
+            background: #f8d7da;
+        }
+        .kind-sent {
+            background: #e2efff;
+        }
+        .kind-response {
+            background: #d4edda;
+        }
+    </style>
+</head>
+<body>
+    <h1>Serial Inspector</h1>
+    <div class="toolbar">
+        <label><input type="checkbox" class="kind" value="raw"> Raw lines</label>
+        <label><input type="checkbox" class="kind" value="frame" checked> Parsed frames</label>
+        <label><input type="checkbox" class="kind" value="rejected" checked> Rejected lines</label>
+        <label><input type="checkbox" class="kind" value="sent" checked> Sent</label>
+        <label><input type="checkbox" class="kind" value="response" checked> Responses</label>
+        <label><input type="checkbox" id="paused"> Pause</label>
+        <button type="button" onclick="document.getElementById('traffic').innerHTML = ''">Clear</button>
+    </div>
+    <table>
+        <thead>
+            <tr><th>Time</th><th>Dir</th><th>Kind</th><th>Line</th><th>Detail</th></tr>
+        </thead>
+        <tbody id="traffic"></tbody>
+    </table>
+    <script>
+        let lastSeq = 0;
+        const maxRows = 500;
+
+        function shownKinds() {
+            return Array.from(document.querySelectorAll('.kind:checked')).map(box => box.value);
+        }
+
+        function poll() {
+            fetch('/api/serial/traffic?since=' + lastSeq)
+                .then(response => response.json())
+                .then(entries => {
+                    const kinds = shownKinds();
+                    const body = document.getElementById('traffic');
+                    const paused = document.getElementById('paused').checked;
+
+                    entries.forEach(entry => {
+                        lastSeq = entry.seq;
+                        if (paused || !kinds.includes(entry.kind)) {
+                            return;
+                        }
+
+                        const row = document.createElement('tr');
+                        row.className = 'kind-' + entry.kind;
+                        [new Date(entry.time).toLocaleTimeString(), entry.direction, entry.kind, entry.text, entry.detail || ''].forEach(value => {
+                            const cell = document.createElement('td');
+                            cell.textContent = value;
+                            row.appendChild(cell);
+                        });
+                        body.insertBefore(row, body.firstChild);
+                    });
+
+                    while (body.children.length > maxRows) {
+                        body.removeChild(body.lastChild);
+                    }
+                })
+                .finally(() => setTimeout(poll, 500));
+        }
+
+        poll();
+    </script>
+</body>
+</html>`
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(inspectorTemplate))
+}
+
+// handleGetSerialTraffic returns serial traffic recorded after the given sequence number. traffic is
+// only recorded while something polls this, so the first call after a while returns nothing
+func (wcs *WebConfigServer) handleGetSerialTraffic(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	since, _ := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wcs.deej.serial.inspector.since(since))
 }
@@ -0,0 +1,235 @@
+package deej
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the web UI gets pushed updates over websockets. like mDNS, there's no library for it among deej's dependencies, and
+// the part deej needs is small: it only ever sends text messages, and only reads to answer pings and to notice the
+// page going away (see RFC 6455)
+
+const (
+	webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	webSocketOpText  = 0x1
+	webSocketOpClose = 0x8
+	webSocketOpPing  = 0x9
+	webSocketOpPong  = 0xA
+
+	// control frames can't carry more than this
+	webSocketMaxControlPayload = 125
+
+	webSocketWriteTimeout = 5 * time.Second
+)
+
+var (
+	errWebSocketHandshake = errors.New("not a websocket handshake")
+	errWebSocketFrame     = errors.New("malformed websocket frame")
+)
+
+// webSocketConn is the server side of a websocket connection
+type webSocketConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	// closed once the connection is gone, for whatever reason
+	closed chan struct{}
+
+	writeLock sync.Mutex
+}
+
+// upgradeWebSocket answers a websocket handshake and takes over the request's connection. if it fails, it already
+// responded to the request
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*webSocketConn, error) {
+	if r.Method != "GET" || !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		!strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade") {
+		http.Error(w, "Expected a websocket handshake", http.StatusBadRequest)
+		return nil, errWebSocketHandshake
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, errWebSocketHandshake
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "Missing websocket key", http.StatusBadRequest)
+		return nil, errWebSocketHandshake
+	}
+
+	// browsers let any page open a websocket anywhere, so only take them from our own pages
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if originURL, err := url.Parse(origin); err != nil || originURL.Host != r.Host {
+			http.Error(w, "Cross-origin websocket", http.StatusForbidden)
+			return nil, errWebSocketHandshake
+		}
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Websockets not supported", http.StatusInternalServerError)
+		return nil, errWebSocketHandshake
+	}
+
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijack connection: %w", err)
+	}
+
+	hash := sha1.Sum([]byte(key + webSocketGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(hash[:]) + "\r\n\r\n"
+
+	conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("write websocket handshake: %w", err)
+	}
+
+	ws := &webSocketConn{
+		conn:   conn,
+		reader: buffered.Reader,
+		closed: make(chan struct{}),
+	}
+
+	go ws.readLoop()
+
+	return ws, nil
+}
+
+// writeJSON sends v as a text message
+func (ws *webSocketConn) writeJSON(v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal websocket message: %w", err)
+	}
+
+	return ws.writeFrame(webSocketOpText, payload)
+}
+
+// close says goodbye to the client and closes the connection
+func (ws *webSocketConn) close() {
+	ws.writeFrame(webSocketOpClose, nil)
+	ws.conn.Close()
+}
+
+func (ws *webSocketConn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode, 0}
+
+	// servers don't mask their frames, so the length is all there is to the rest of the header
+	switch {
+	case len(payload) <= webSocketMaxControlPayload:
+		header[1] = byte(len(payload))
+	case len(payload) <= 0xFFFF:
+		header[1] = 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(len(payload)))
+	default:
+		header[1] = 127
+		header = append(header, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(header[2:], uint64(len(payload)))
+	}
+
+	ws.writeLock.Lock()
+	defer ws.writeLock.Unlock()
+
+	ws.conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+	if _, err := ws.conn.Write(append(header, payload...)); err != nil {
+		return fmt.Errorf("write websocket frame: %w", err)
+	}
+
+	return nil
+}
+
+// readLoop reads what the client sends until the connection goes away, answering pings and closes
+func (ws *webSocketConn) readLoop() {
+	defer close(ws.closed)
+	defer ws.conn.Close()
+
+	for {
+		opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case webSocketOpPing:
+			ws.writeFrame(webSocketOpPong, payload)
+		case webSocketOpClose:
+			ws.writeFrame(webSocketOpClose, nil)
+			return
+		}
+	}
+}
+
+// readFrame reads a single frame. only control frames' payloads are kept, since that's all deej has a use for
+func (ws *webSocketConn) readFrame() (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(ws.reader, header); err != nil {
+		return 0, nil, err
+	}
+
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(ws.reader, extended); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(ws.reader, extended); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	mask := make([]byte, 4)
+	if masked {
+		if _, err := io.ReadFull(ws.reader, mask); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if opcode < webSocketOpClose {
+		_, err := io.CopyN(ioutil.Discard, ws.reader, int64(length))
+		return opcode, nil, err
+	}
+
+	if length > webSocketMaxControlPayload {
+		return 0, nil, errWebSocketFrame
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return 0, nil, err
+	}
+
+	for idx := range payload {
+		payload[idx] ^= mask[idx%4]
+	}
+
+	return opcode, payload, nil
+}